@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+)
+
+// robotDoctorOutput is the --robot-doctor / `bv doctor --json` payload.
+type robotDoctorOutput struct {
+	RobotEnvelope
+	*datasource.DoctorReport
+	Fixed []datasource.DoctorFixResult `json:"fixed,omitempty"`
+}
+
+// runDoctor diagnoses beadsDir and, when fix is set, applies the fix plan
+// and diagnoses again so the returned report describes the repaired data.
+func runDoctor(beadsDir string, fix bool) (robotDoctorOutput, error) {
+	report, err := datasource.DiagnoseDir(beadsDir)
+	if err != nil {
+		return robotDoctorOutput{}, err
+	}
+	var fixed []datasource.DoctorFixResult
+	if fix && len(report.FixPlan) > 0 {
+		fixed, err = datasource.ApplyDoctorFixes(report.FixPlan)
+		if err != nil {
+			return robotDoctorOutput{}, fmt.Errorf("applying fixes: %w", err)
+		}
+		if report, err = datasource.DiagnoseDir(beadsDir); err != nil {
+			return robotDoctorOutput{}, err
+		}
+	}
+
+	dataHash := ""
+	if issues, err := datasource.LoadIssuesFromDir(beadsDir); err == nil {
+		dataHash = analysis.ComputeDataHash(issues)
+	}
+	return robotDoctorOutput{
+		RobotEnvelope: NewRobotEnvelope(dataHash),
+		DoctorReport:  report,
+		Fixed:         fixed,
+	}, nil
+}
+
+// runDoctorCommand implements `bv doctor`. It exits 1 when errors remain.
+func runDoctorCommand(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "Path to beads database file or .beads directory")
+	fix := fs.Bool("fix", false, "Apply the fix plan to the JSONL files (originals kept as *.backup)")
+	jsonOut := fs.Bool("json", false, "Print the report as JSON (same as --robot-doctor)")
+	format := fs.String("format", "", "Structured output format with --json: json|toon (env: BV_OUTPUT_FORMAT)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv doctor [options]")
+		fmt.Fprintln(os.Stderr, "\nCheck beads data for integrity problems and optionally repair them.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if *dbPath != "" {
+		absDB, err := filepath.Abs(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving --db path: %v\n", err)
+			return 1
+		}
+		os.Setenv(loader.BeadsDBEnvVar, absDB)
+	}
+	robotOutputFormat = resolveRobotOutputFormat(*format)
+	robotToonEncodeOptions = resolveToonEncodeOptionsFromEnv()
+	if robotOutputFormat != "json" && robotOutputFormat != "toon" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (expected json|toon)\n", robotOutputFormat)
+		return 2
+	}
+
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+	output, err := runDoctor(beadsDir, *fix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *jsonOut {
+		if err := newRobotEncoder(os.Stdout).Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding doctor report: %v\n", err)
+			return 1
+		}
+	} else {
+		printDoctorReport(os.Stdout, output, *fix)
+	}
+	if output.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+// printDoctorReport writes the human-readable form of a doctor report.
+func printDoctorReport(w io.Writer, out robotDoctorOutput, fixed bool) {
+	cwd, _ := os.Getwd()
+	rel := func(path string) string {
+		if r, err := filepath.Rel(cwd, path); err == nil && len(r) < len(path) {
+			return r
+		}
+		return path
+	}
+
+	for _, res := range out.Fixed {
+		fmt.Fprintf(w, "Fixed %d problem(s) in %s (backup: %s)\n", res.Applied, rel(res.Path), rel(res.Backup))
+	}
+	if len(out.Fixed) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	summary := out.Summary
+	fmt.Fprintf(w, "Checked %d issue(s) in %d source(s)\n", summary.Issues, summary.Sources)
+	if len(out.Findings) == 0 {
+		fmt.Fprintln(w, "No problems found.")
+		return
+	}
+	fmt.Fprintln(w)
+	for _, f := range out.Findings {
+		where := rel(f.Source)
+		if f.Line > 0 {
+			where = fmt.Sprintf("%s:%d", where, f.Line)
+		}
+		mark := ""
+		if f.Fix != nil {
+			mark = " [fixable]"
+		}
+		fmt.Fprintf(w, "  %-7s %s  %s: %s%s\n", f.Severity, where, f.Check, f.Message, mark)
+	}
+	fmt.Fprintf(w, "\n%d error(s), %d warning(s), %d fixable\n", summary.Errors, summary.Warnings, summary.Fixable)
+	if summary.Fixable > 0 && !fixed {
+		fmt.Fprintln(w, "Run 'bv doctor --fix' to apply the fix plan.")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+)
+
+func TestDoctor_EndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"A","depends_on_id":"A","type":"blocks"}]}
+{"id":"B","title":"Second","status":"Done","priority":1,"issue_type":"task"}
+`
+	jsonlPath := filepath.Join(beadsDir, "beads.jsonl")
+	if err := os.WriteFile(jsonlPath, []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	run := func(args ...string) ([]byte, int) {
+		cmd := exec.Command(exe, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return out, exitErr.ExitCode()
+		}
+		if err != nil {
+			t.Fatalf("run %v: %v", args, err)
+		}
+		return out, 0
+	}
+
+	out, code := run("doctor")
+	if code != 1 || !strings.Contains(string(out), "beads.jsonl:1") || !strings.Contains(string(out), "unknown_status") {
+		t.Errorf("bv doctor: exit %d\n%s", code, out)
+	}
+
+	out, code = run("--robot-doctor")
+	if code != 1 {
+		t.Errorf("--robot-doctor should exit 1 while errors remain, got %d", code)
+	}
+	var report robotDoctorOutput
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out)
+	}
+	if report.DoctorReport == nil || report.Summary.Errors != 2 || len(report.FixPlan) != 2 {
+		t.Fatalf("unexpected report: %s", out)
+	}
+	if report.FixPlan[0].Action != datasource.FixRemoveDependency || report.FixPlan[1].Field != "status" {
+		t.Errorf("unexpected fix plan: %+v", report.FixPlan)
+	}
+
+	out, code = run("--robot-doctor", "--doctor-fix")
+	if code != 0 {
+		t.Fatalf("--doctor-fix: exit %d\n%s", code, out)
+	}
+	report = robotDoctorOutput{}
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("decode fixed report: %v", err)
+	}
+	if len(report.Fixed) != 1 || report.Fixed[0].Applied != 2 || len(report.Findings) != 0 || report.DataHash == "" {
+		t.Errorf("unexpected fixed report: %s", out)
+	}
+	if _, err := os.Stat(jsonlPath + ".backup"); err != nil {
+		t.Errorf("backup missing: %v", err)
+	}
+
+	out, code = run("doctor")
+	if code != 0 || !strings.Contains(string(out), "No problems found.") {
+		t.Errorf("bv doctor after fix: exit %d\n%s", code, out)
+	}
+}
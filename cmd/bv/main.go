@@ -50,6 +50,23 @@ import (
 )
 
 func main() {
+	// Subcommands own their flag sets and bypass the robot/TUI flag parsing.
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		os.Exit(runServeCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "mcp" {
+		os.Exit(runMCPCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(runDoctorCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		os.Exit(runMergeCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "search-eval" {
+		os.Exit(runSearchEvalCommand(os.Args[2:]))
+	}
+
 	cpuProfile := flag.String("cpu-profile", "", "Write CPU profile to file")
 	dbPath := flag.String("db", "", "Path to beads database file or .beads directory (overrides BEADS_DB and BEADS_DIR env vars)")
 	help := flag.Bool("help", false, "Show help")
@@ -96,6 +113,7 @@ func main() {
 	exportGraph := flag.String("export-graph", "", "Export graph: .html for interactive, .png/.svg for static (auto-names if empty)")
 	graphPreset := flag.String("graph-preset", "compact", "Graph layout preset: compact (default) or roomy")
 	graphTitle := flag.String("graph-title", "", "Title for graph export (default: project name)")
+	exportTimeline := flag.String("export-timeline", "", "Export a Gantt timeline of open issues (ETAs, slack, critical path, due dates) as .png or .svg")
 	// Robot output filters (bv-84)
 	robotMinConf := flag.Float64("robot-min-confidence", 0.0, "Filter robot outputs by minimum confidence (0.0-1.0)")
 	robotMaxResults := flag.Int("robot-max-results", 0, "Limit robot output count (0 = use defaults)")
@@ -107,7 +125,7 @@ func main() {
 	alertType := flag.String("alert-type", "", "Filter robot alerts by alert type (e.g., stale_issue)")
 	alertLabel := flag.String("alert-label", "", "Filter robot alerts by label match")
 	recipeName := flag.StringP("recipe", "r", "", "Apply named recipe (e.g., triage, actionable, high-impact)")
-	semanticQuery := flag.String("search", "", "Semantic search query (vector-based; builds/updates index on first run)")
+	semanticQuery := flag.String("search", "", "Search query: free text plus filters like status:open label:x priority:<2 updated:>7d (builds/updates index on first run)")
 	robotSearch := flag.Bool("robot-search", false, "Output semantic search results as JSON for AI agents (use with --search)")
 	searchLimit := flag.Int("search-limit", 10, "Max results for --search/--robot-search")
 	searchMode := flag.String("search-mode", "", "Search ranking mode: text or hybrid (default: BV_SEARCH_MODE or text)")
@@ -121,6 +139,12 @@ func main() {
 	profileJSON := flag.Bool("profile-json", false, "Output profile in JSON format (use with --profile-startup)")
 	noHooks := flag.Bool("no-hooks", false, "Skip running hooks during export")
 	workspaceConfig := flag.String("workspace", "", "Load issues from workspace config file (.bv/workspace.yaml)")
+	sessionName := flag.String("session", "", "Open the TUI in a named saved session, creating it if new (default: the last one used)")
+	noSession := flag.Bool("no-session", false, "Start the TUI without restoring or saving session state")
+	themeName := flag.String("theme", "", "TUI color theme: a preset (solarized, gruvbox, high-contrast, colorblind-safe), a theme from ~/.config/bv/themes, or a theme .yaml file")
+	scoringProfileName := flag.String("profile", "", "Scoring profile for triage, priority and the TUI: a built-in (default, unblock, bug-burndown) or one from .bv/scoring.yaml")
+	importPath := flag.String("import", "", "Load issues from another tracker's export (GitHub issues JSON, Jira CSV, Linear JSON) instead of .beads")
+	importFormat := flag.String("import-format", "auto", "Format of --import file: auto, github, jira or linear")
 	repoFilter := flag.String("repo", "", "Filter issues by repository prefix (e.g., 'api-' or 'api')")
 	saveBaseline := flag.String("save-baseline", "", "Save current metrics as baseline with optional description")
 	baselineInfo := flag.Bool("baseline-info", false, "Show information about the current baseline")
@@ -128,6 +152,10 @@ func main() {
 	robotDriftCheck := flag.Bool("robot-drift", false, "Output drift check as JSON (use with --check-drift)")
 	robotHistory := flag.Bool("robot-history", false, "Output bead-to-commit correlations as JSON")
 	beadHistory := flag.String("bead-history", "", "Show history for specific bead ID")
+	robotTimeline := flag.String("robot-timeline", "", "Output field-level change timeline for issue ID as JSON")
+	robotFlowMetrics := flag.Bool("robot-flow-metrics", false, "Output lead time, cycle time, time-in-status and throughput percentiles as JSON")
+	flowWindow := flag.Int("flow-window", 90, "Days of closures counted by --robot-flow-metrics (0 = all)")
+	flowWeeks := flag.Int("flow-weeks", 8, "Weeks of throughput history for --robot-flow-metrics")
 	historySince := flag.String("history-since", "", "Limit history to commits after this date/ref (e.g., '30 days ago', '2024-01-01')")
 	historyLimit := flag.Int("history-limit", 500, "Max commits to analyze (0 = unlimited)")
 	minConfidence := flag.Float64("min-confidence", 0.0, "Filter correlations by minimum confidence (0.0-1.0)")
@@ -159,6 +187,24 @@ func main() {
 	relatedIncludeClosed := flag.Bool("related-include-closed", false, "Include closed beads in related work results")
 	// Blocker chain analysis flag (bv-nlo0)
 	robotBlockerChain := flag.String("robot-blocker-chain", "", "Output full blocker chain analysis for issue ID as JSON")
+	// Write-back mutation flags
+	robotClaim := flag.String("robot-claim", "", "Claim issue ID (status in_progress, assignee set) and output the change as JSON")
+	robotClose := flag.String("robot-close", "", "Close issue ID and output the change as JSON")
+	robotLink := flag.String("robot-link", "", "Add or remove dependencies of issue ID (use with --depends-on/--unlink)")
+	robotUpdate := flag.String("robot-update", "", "Update status/priority/assignee/labels of issue ID (use with --set-*, --add-label, --remove-label)")
+	mutAssignee := flag.String("assignee", "", "Assignee for --robot-claim/--robot-update (default: $BV_ACTOR or $USER; '-' unassigns)")
+	mutForce := flag.Bool("force", false, "Allow --robot-claim to take over an issue claimed by someone else")
+	mutReason := flag.String("reason", "", "Close reason for --robot-close")
+	mutDependsOn := flag.StringSlice("depends-on", nil, "Issue IDs the target depends on (comma-separated; use with --robot-link)")
+	mutUnlink := flag.StringSlice("unlink", nil, "Dependency targets to remove (comma-separated; use with --robot-link)")
+	mutDepType := flag.String("dep-type", "blocks", "Dependency type for --depends-on: blocks, related, parent-child, discovered-from")
+	mutSetStatus := flag.String("set-status", "", "New status for --robot-update")
+	mutSetPriority := flag.Int("set-priority", -1, "New priority 0-4 for mutation commands")
+	mutAddLabels := flag.StringSlice("add-label", nil, "Labels to add (comma-separated) for mutation commands")
+	mutRemoveLabels := flag.StringSlice("remove-label", nil, "Labels to remove (comma-separated) for mutation commands")
+	// Data integrity check
+	robotDoctor := flag.Bool("robot-doctor", false, "Check beads data for integrity problems and output the report and fix plan as JSON")
+	doctorFix := flag.Bool("doctor-fix", false, "Apply the --robot-doctor fix plan to the JSONL files (originals kept as *.backup)")
 	// Impact network graph flag (bv-48kr)
 	robotImpactNetwork := flag.String("robot-impact-network", "", "Output bead impact network as JSON (empty for full, or bead ID for subnetwork)")
 	networkDepth := flag.Int("network-depth", 2, "Depth of subnetwork when querying specific bead (1-3)")
@@ -171,11 +217,23 @@ func main() {
 	robotForecast := flag.String("robot-forecast", "", "Output ETA forecast for bead ID, or 'all' for all open issues")
 	forecastLabel := flag.String("forecast-label", "", "Filter forecast by label")
 	forecastSprint := flag.String("forecast-sprint", "", "Filter forecast by sprint ID")
+	forecastEpic := flag.String("forecast-epic", "", "Filter forecast to an epic's parent-child descendants")
+	forecastTrials := flag.Int("forecast-trials", 1000, "Monte Carlo trials for the completion-date distribution (0 = off)")
+	forecastSeed := flag.Int64("forecast-seed", 1, "Monte Carlo RNG seed (same seed, same forecast)")
 	forecastAgents := flag.Int("forecast-agents", 1, "Number of parallel agents for capacity calculation")
+	robotWhatIf := flag.String("robot-whatif", "", "Compare hypothetical edits against the current plan: a scenario YAML file, or inline edits like close:ID,add-dep:A>B")
+	// Learning-to-rank flags
+	robotTrainRanking := flag.Bool("robot-train-ranking", false, "Learn scoring weights from which issues git history shows were claimed next, and report them as JSON")
+	trainRevisions := flag.Int("train-revisions", 200, "Beads-file revisions to replay for --robot-train-ranking (0 = all)")
+	trainName := flag.String("train-name", "learned", "Name of the scoring profile --robot-train-ranking learns")
+	trainExport := flag.String("train-export", "", "Write the learned profile into this scoring profile file (e.g. .bv/scoring.yaml)")
 	// Capacity simulation flags (bv-160)
 	robotCapacity := flag.Bool("robot-capacity", false, "Output capacity simulation and completion projection as JSON")
 	capacityAgents := flag.Int("agents", 1, "Number of parallel agents for capacity simulation")
 	capacityLabel := flag.String("capacity-label", "", "Filter capacity simulation by label")
+	// Roster-based scheduling flags
+	robotSchedule := flag.Bool("robot-schedule", false, "Output a per-person schedule over the team roster's calendars as JSON")
+	teamFile := flag.String("team", "", "Team roster YAML for --robot-schedule (default: .bv/team.yaml)")
 	// Burndown flags (bv-159)
 	robotBurndown := flag.String("robot-burndown", "", "Output burndown data for sprint ID, or 'current' for active sprint")
 	// Action script emission flags (bv-89)
@@ -300,21 +358,31 @@ func main() {
 		*robotSearch ||
 		*robotDriftCheck ||
 		*robotHistory ||
+		*robotTimeline != "" ||
+		*robotFlowMetrics ||
 		*robotFileBeads != "" ||
 		*fileHotspots ||
 		*robotImpact != "" ||
 		*robotFileRelations != "" ||
 		*robotRelatedWork != "" ||
 		*robotBlockerChain != "" ||
+		*robotClaim != "" ||
+		*robotClose != "" ||
+		*robotLink != "" ||
+		*robotUpdate != "" ||
+		*robotDoctor ||
 		*robotImpactNetwork != "" ||
 		*robotCausality != "" ||
 		*robotSprintList ||
 		*robotSprintShow != "" ||
 		*robotForecast != "" ||
+		*robotWhatIf != "" ||
+		*robotTrainRanking ||
 		*robotBurndown != "" ||
 		*robotByLabel != "" ||
 		*robotByAssignee != "" ||
 		*robotCapacity ||
+		*robotSchedule ||
 		*robotDocs != "" ||
 		// When stdout is non-TTY, --diff-since auto-enables JSON output. Mark this
 		// as robot mode early so parsers keep stdout JSON clean.
@@ -401,8 +469,10 @@ func main() {
 		fmt.Println("      Use when you just need to know \"what should I work on next?\"")
 		fmt.Println("")
 		fmt.Println("  --search \"query\" [--robot-search]")
-		fmt.Println("      Semantic vector search over issue titles/descriptions.")
+		fmt.Println("      BM25 + semantic vector search over issue titles, descriptions and comments.")
 		fmt.Println("      Builds/updates a local on-disk vector index on first run.")
+		fmt.Println("      Filters: status:open label:x priority:<2 assignee:me updated:>7d blocks:ID -label:y \"phrase\"")
+		fmt.Println("      Each result carries match{field, snippet, highlights} pointing at the passage that matched.")
 		fmt.Println("      Use --robot-search to emit JSON for automation.")
 		fmt.Println("      Optional hybrid re-ranking:")
 		fmt.Println("      - --search-mode=text|hybrid (default: BV_SEARCH_MODE or text)")
@@ -432,6 +502,30 @@ func main() {
 		fmt.Println("      Example: bv --robot-history --history-since '30 days ago'")
 		fmt.Println("      Example: bv --robot-history --min-confidence 0.7")
 		fmt.Println("")
+		fmt.Println("  --robot-timeline <id>")
+		fmt.Println("      Outputs every field-level change to an issue, reconstructed from git.")
+		fmt.Println("      Each event carries the commit SHA, author, timestamp and the changed")
+		fmt.Println("      status, priority, assignee, labels, dependencies or text fields.")
+		fmt.Println("      Key sections:")
+		fmt.Println("      - events: created/updated/deleted events in commit order")
+		fmt.Println("      - time_in_status_hours: Hours spent in each status")
+		fmt.Println("      Results are cached per commit in .bv/timeline.json.")
+		fmt.Println("      Example: bv --robot-timeline bv-123 | jq '.events[].changes'")
+		fmt.Println("")
+		fmt.Println("  --robot-flow-metrics")
+		fmt.Println("      Outputs flow metrics with p50/p85/p95 percentiles (hours) as JSON:")
+		fmt.Println("      lead time (created -> closed), cycle time (first in_progress -> closed),")
+		fmt.Println("      time in each status, and weekly throughput.")
+		fmt.Println("      Status transitions come from git history; outside a repo only lead time")
+		fmt.Println("      and throughput are available (status_source: snapshot).")
+		fmt.Println("      Key sections:")
+		fmt.Println("      - overall: Metrics across all issues")
+		fmt.Println("      - by_label, by_type, by_assignee, by_priority: Same metrics per slice")
+		fmt.Println("      Flags:")
+		fmt.Println("      - --flow-window <days>: Closures counted (default: 90, 0 = all)")
+		fmt.Println("      - --flow-weeks <n>: Weeks of throughput (default: 8)")
+		fmt.Println("      Example: bv --robot-flow-metrics | jq '.by_label | map_values(.cycle_time.p85_hours)'")
+		fmt.Println("")
 		fmt.Println("  --robot-file-beads <path>")
 		fmt.Println("      Outputs beads that have touched a file path as JSON.")
 		fmt.Println("      Answers: 'What beads have touched this file, and why?'")
@@ -530,13 +624,51 @@ func main() {
 		fmt.Println("  --robot-forecast <id|all>")
 		fmt.Println("      Outputs ETA forecast for a specific bead or all open issues.")
 		fmt.Println("      Returns estimated completion date, confidence, and factors.")
+		fmt.Println("      monte_carlo: p50/p85/p95 completion dates and a histogram from simulated")
+		fmt.Println("      futures that resample historical cycle times and throughput and respect")
+		fmt.Println("      blocking order.")
 		fmt.Println("      Options:")
 		fmt.Println("        --forecast-label=X    Filter by label")
 		fmt.Println("        --forecast-sprint=Y   Filter by sprint")
+		fmt.Println("        --forecast-epic=Z     Filter to an epic's descendants")
 		fmt.Println("        --forecast-agents=N   Parallel agents (default: 1)")
+		fmt.Println("        --forecast-trials=N   Monte Carlo trials (default: 1000, 0 = off)")
+		fmt.Println("        --forecast-seed=S     Monte Carlo seed (default: 1)")
 		fmt.Println("      Example: bv --robot-forecast bv-123")
 		fmt.Println("      Example: bv --robot-forecast all --forecast-label=backend")
-		fmt.Println("      Example: bv --robot-forecast all --forecast-agents=2")
+		fmt.Println("      Example: bv --robot-forecast all --forecast-epic=bv-42 --forecast-agents=2")
+		fmt.Println("")
+		fmt.Println("  --robot-whatif <scenarios.yaml|edits>")
+		fmt.Println("      Applies hypothetical edits to a copy of the issues and compares graph")
+		fmt.Println("      stats, triage, the execution plan and the Monte Carlo forecast with the")
+		fmt.Println("      unmodified baseline. Nothing is written.")
+		fmt.Println("      Inline edits (comma-separated): close:ID, defer:ID (with descendants),")
+		fmt.Println("      add-dep:A>B (A waits on B), remove-dep:A>B, add-agents:N")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - baseline: open/actionable/blocked counts, top picks and forecast")
+		fmt.Println("        - scenarios[].unblocked / newly_blocked: Actionable set changes")
+		fmt.Println("        - scenarios[].rank_changes: Moves in the top 10 triage picks")
+		fmt.Println("        - scenarios[].forecast_delta: p50/p85/p95 days vs baseline")
+		fmt.Println("        - scenarios[].diff: Snapshot diff, as in --robot-diff")
+		fmt.Println("      Uses --forecast-agents, --forecast-trials and --forecast-seed.")
+		fmt.Println("      Example: bv --robot-whatif close:bv-12,add-agents:1")
+		fmt.Println("      Example: bv --robot-whatif .bv/scenarios.yaml")
+		fmt.Println("")
+		fmt.Println("  --robot-train-ranking [--train-revisions=N] [--train-export=FILE]")
+		fmt.Println("      Replays beads-file revisions from git, finds the open issues claimed")
+		fmt.Println("      (moved to in_progress or closed) after each one, and fits impact score")
+		fmt.Println("      weights that rank those claims above the alternatives (pairwise")
+		fmt.Println("      logistic regression). The latest 20% of claims are held out to compare")
+		fmt.Println("      the learned weights with the defaults.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - baseline / trained: top1, top3, mrr, pairwise_accuracy")
+		fmt.Println("        - evaluated_on: holdout, or training when history is too short")
+		fmt.Println("        - weights / profile: The learned scoring profile")
+		fmt.Println("        - events[].claims: Each claim's baseline and trained rank")
+		fmt.Println("      --train-export writes the profile (named by --train-name, default")
+		fmt.Println("      learned) into a scoring profile file, keeping its other profiles.")
+		fmt.Println("      Example: bv --robot-train-ranking --train-export .bv/scoring.yaml")
+		fmt.Println("      Then:    bv --profile learned")
 		fmt.Println("")
 		fmt.Println("  --robot-capacity [--agents=N] [--capacity-label=X]")
 		fmt.Println("      Outputs capacity simulation and completion projection as JSON.")
@@ -551,6 +683,21 @@ func main() {
 		fmt.Println("        --agents=N           Number of parallel agents (default: 1)")
 		fmt.Println("        --capacity-label=X   Filter analysis to label's subgraph")
 		fmt.Println("      Example: bv --robot-capacity --agents=3")
+		fmt.Println("")
+		fmt.Println("  --robot-schedule [--team=path]")
+		fmt.Println("      Assigns open issues to the people on the team roster by list scheduling")
+		fmt.Println("      over the dependency graph, within each person's capacity, skills,")
+		fmt.Println("      working days and holidays. Durations use estimated_minutes, or the")
+		fmt.Println("      median estimate when an issue has none.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - schedule.members: Per-person issues, assigned hours and utilization")
+		fmt.Println("        - schedule.assignments: Start/finish, assignee and slip per issue")
+		fmt.Println("        - schedule.slipped: Issues finishing after their due date")
+		fmt.Println("        - schedule.unscheduled: Issues no one has the skills for")
+		fmt.Println("      Options:")
+		fmt.Println("        --team=path          Roster YAML (default: .bv/team.yaml)")
+		fmt.Println("        --agents=N           Generalists to assume without a roster (default: 1)")
+		fmt.Println("      Example: bv --robot-schedule | jq '.schedule.slipped'")
 		fmt.Println("      Example: bv --robot-capacity --capacity-label=backend")
 		fmt.Println("")
 		fmt.Println("  --emit-script [--script-limit=N] [--script-format=bash|fish|zsh]")
@@ -600,6 +747,50 @@ func main() {
 		fmt.Println("      Robot outputs include 'as_of' and 'as_of_commit' metadata fields.")
 		fmt.Println("      Examples: --as-of HEAD~30, --as-of v1.0.0, --as-of '2024-01-01'")
 		fmt.Println("")
+		fmt.Println("  bv serve [--addr=127.0.0.1:9595] [--format=json|toon] [--no-watch]")
+		fmt.Println("      Long-running HTTP API with warm analysis state; reloads when beads data changes.")
+		fmt.Println("      Endpoints mirror robot commands: /triage /next /plan /insights /graph /search")
+		fmt.Println("      /history /forecast (append ?format=toon for TOON). GET / lists query params.")
+		fmt.Println("      Example: curl -s localhost:9595/next")
+		fmt.Println("")
+		fmt.Println("  bv mcp [--format=json|toon] [--no-watch]")
+		fmt.Println("      Model Context Protocol server on stdio. Tools: robot-triage, robot-next, robot-plan,")
+		fmt.Println("      robot-blocker-chain, robot-impact, robot-search, robot-related.")
+		fmt.Println("      Tool input schemas match --robot-schema 'inputs'; analysis stays warm between calls.")
+		fmt.Println("      Example client config: {\"command\": \"bv\", \"args\": [\"mcp\"]}")
+		fmt.Println("")
+		fmt.Println("  bv merge <rev> [--dry-run] [--json]")
+		fmt.Println("      Three-way merge of the beads issues at <rev> into the working beads.jsonl, using the")
+		fmt.Println("      merge-base with HEAD. Fields, labels, dependencies and comments merge independently;")
+		fmt.Println("      conflicts keep our value, are listed in the output and can be resolved in the TUI (X).")
+		fmt.Println("      As a git merge driver: git config merge.beads.driver 'bv merge --driver %O %A %B %P'")
+		fmt.Println("      plus '.beads/beads.jsonl merge=beads' in .gitattributes. Exits 1 while conflicts remain.")
+		fmt.Println("")
+		fmt.Println("  bv search-eval <queries.yaml|queries.jsonl> [--k=10] [--grid-step=0.1|--no-grid] [--json]")
+		fmt.Println("      Scores search relevance on judged queries ({query, relevant: [ids], grades?}) with MRR,")
+		fmt.Println("      nDCG@k and recall@k for text mode, hybrid mode under every preset and grid-searched")
+		fmt.Println("      weights, then suggests the best BV_SEARCH_PRESET or BV_SEARCH_WEIGHTS for this repo.")
+		fmt.Println("")
+		fmt.Println("  --robot-claim <id> [--assignee=NAME] [--force]")
+		fmt.Println("  --robot-close <id> [--reason=TEXT]")
+		fmt.Println("  --robot-link <id> --depends-on=ID[,ID] [--dep-type=blocks] | --unlink=ID[,ID]")
+		fmt.Println("  --robot-update <id> [--set-status=S] [--set-priority=N] [--assignee=NAME|-]")
+		fmt.Println("      Write changes back to the beads data (JSONL, plus beads.db when that is the source).")
+		fmt.Println("      Only the target issue's line is rewritten; writers are serialized via .beads/.bv.write.lock.")
+		fmt.Println("      All four accept --add-label/--remove-label and --set-priority.")
+		fmt.Println("      Claim fails if another assignee holds the issue in_progress unless --force.")
+		fmt.Println("      Key fields: action, issue_id, changed, before, after, source, written")
+		fmt.Println("      Example: bv --robot-next | jq -r .id | xargs bv --robot-claim")
+		fmt.Println("      Example: bv --robot-link bv-12 --depends-on bv-7")
+		fmt.Println("")
+		fmt.Println("  --robot-doctor [--doctor-fix]")
+		fmt.Println("      Integrity report for every beads source: parse errors, duplicate IDs, self and")
+		fmt.Println("      dangling dependencies, parent-child cycles, bad timestamps, unknown statuses.")
+		fmt.Println("      Each finding has source, line and severity; fix_plan lists machine-applicable repairs.")
+		fmt.Println("      --doctor-fix applies the plan to JSONL files (originals kept as *.backup).")
+		fmt.Println("      Exits 1 while errors remain. Human-readable form: bv doctor [--fix]")
+		fmt.Println("      Key fields: findings[].check, findings[].severity, findings[].line, fix_plan, summary")
+		fmt.Println("")
 		fmt.Println("  --robot-diff")
 		fmt.Println("      Output diff as JSON (use with --diff-since).")
 		fmt.Println("      Fields: generated_at, resolved_revision, from_data_hash, to_data_hash, diff{...}")
@@ -681,6 +872,13 @@ func main() {
 		fmt.Println("      Example: bv --export-graph deps.svg --label=api --graph-title='API Dependencies'")
 		fmt.Println("      Example: bv --export-graph full.png --graph-style=force --graph-preset=roomy")
 		fmt.Println("")
+		fmt.Println("  --export-timeline <path.png|path.svg>")
+		fmt.Println("      Export a Gantt chart of open issues laid out from their ETA estimates in dependency order.")
+		fmt.Println("      Bars show the estimate, light bars the slack, red outlines the critical path and")
+		fmt.Println("      ticks the due dates (red when the forecast misses them).")
+		fmt.Println("      Options: --label LABEL, --graph-title TITLE, --forecast-agents N")
+		fmt.Println("      Example: bv --export-timeline roadmap.svg --forecast-agents=2")
+		fmt.Println("")
 		fmt.Println("  --robot-insights")
 		fmt.Println("      Graph metrics JSON for agents.")
 		fmt.Println("      Top lists: Bottlenecks (betweenness), Keystones (critical path), Influencers (eigenvector),")
@@ -728,6 +926,13 @@ func main() {
 		fmt.Println("  --robot-triage / --robot-next")
 		fmt.Println("      Unified triage (mega command) or single top pick. QuickRef includes top picks, quick_wins, blockers_to_clear.")
 		fmt.Println("")
+		fmt.Println("  --profile NAME")
+		fmt.Println("      Scoring profile for --robot-triage/next/priority/whatif, briefs, --emit-script and the TUI.")
+		fmt.Println("      Built-ins: default, unblock, bug-burndown. .bv/scoring.yaml can add or replace profiles with")
+		fmt.Println("      per-component weights, label/type multipliers, urgency rules, exclusions and triage blend")
+		fmt.Println("      weights, and pick the default one. The profile used is echoed in triage meta.profile.")
+		fmt.Println("      Example: bv --robot-triage --profile bug-burndown")
+		fmt.Println("")
 		fmt.Println("  --recipe NAME, -r NAME")
 		fmt.Println("      Apply a named recipe to filter and sort issues.")
 		fmt.Println("      Example: bv --recipe actionable")
@@ -746,6 +951,13 @@ func main() {
 		fmt.Println("      Aggregates issues from multiple repositories with namespaced IDs.")
 		fmt.Println("      Example: bv --workspace .bv/workspace.yaml")
 		fmt.Println("")
+		fmt.Println("  --import FILE [--import-format auto|github|jira|linear]")
+		fmt.Println("      Load issues from another tracker's offline export instead of .beads.")
+		fmt.Println("      Supports `gh issue list --json ...`, Jira CSV and Linear JSON exports.")
+		fmt.Println("      Read-only: write-back commands are disabled and the TUI does not live-reload.")
+		fmt.Println("      With --export-pages --watch-export the file is re-imported whenever it changes.")
+		fmt.Println("      Example: bv --import issues.json --robot-triage")
+		fmt.Println("")
 		fmt.Println("  --repo PREFIX")
 		fmt.Println("      Filter issues by repository prefix.")
 		fmt.Println("      Use with --workspace to focus on one repo in a multi-repo view.")
@@ -1243,6 +1455,9 @@ func main() {
 					"command":        *schemaCommand,
 					"schema":         schema,
 				}
+				if input, ok := schemas.Inputs[*schemaCommand]; ok {
+					singleOutput["input_schema"] = input
+				}
 				encoder := newRobotEncoder(os.Stdout)
 				if err := encoder.Encode(singleOutput); err != nil {
 					fmt.Fprintf(os.Stderr, "Error encoding schema: %v\n", err)
@@ -1280,6 +1495,29 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --robot-doctor before loading: it must work on data the loader rejects
+	if *robotDoctor {
+		beadsDir, err := loader.GetBeadsDir("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := runDoctor(beadsDir, *doctorFix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding doctor report: %v\n", err)
+			os.Exit(1)
+		}
+		if output.HasErrors() {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Get project directory for baseline operations (moved up to allow info check without loading issues)
 	projectDir, _ := os.Getwd()
 	baselinePath := baseline.DefaultPath(projectDir)
@@ -1350,6 +1588,22 @@ func main() {
 				fmt.Fprintf(os.Stderr, "Loaded %d issues from %s\n", len(issues), *asOf)
 			}
 		}
+	} else if *importPath != "" {
+		// Load from a foreign tracker export (read-only; only --watch-export re-imports)
+		if *workspaceConfig != "" {
+			fmt.Fprintf(os.Stderr, "Warning: --workspace is ignored when --import is specified\n")
+		}
+		var src datasource.Source
+		var err error
+		issues, src, err = datasource.ImportFile(*importPath, *importFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing %s: %v\n", *importPath, err)
+			os.Exit(1)
+		}
+		beadsPath = ""
+		if !envRobot {
+			fmt.Fprintf(os.Stderr, "Imported %d issues from %s export %s\n", len(issues), src.Name(), *importPath)
+		}
 	} else if *workspaceConfig != "" {
 		// Load from workspace configuration
 		loadedIssues, results, err := workspace.LoadAllFromConfig(context.Background(), *workspaceConfig)
@@ -1408,6 +1662,15 @@ func main() {
 	// Stable data hash for robot outputs (after repo filter but before recipes/TUI)
 	dataHash := analysis.ComputeDataHash(issues)
 
+	// Scoring profile for every triage/priority computation below: --profile,
+	// else the default from .bv/scoring.yaml, else the compiled-in weights
+	profileDir, _ := os.Getwd()
+	scoringProfile, err := resolveScoringProfile(profileDir, *scoringProfileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Label subgraph scoping (bv-122)
 	// When --label is specified, extract the label's subgraph and use it for all robot analysis.
 	// This includes label health context in the output.
@@ -1465,158 +1728,34 @@ func main() {
 			os.Exit(1)
 		}
 
-		embedder, err := search.NewEmbedderFromConfig(embedCfg)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
 		projectDir, err := os.Getwd()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		indexPath := search.DefaultIndexPath(projectDir, embedCfg)
-		idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
+		searcher, err := newSemanticSearcher(projectDir, embedCfg)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-
-		docs := search.DocumentsFromIssues(issuesForSearch)
-		if !*robotSearch && !loaded {
-			fmt.Fprintf(os.Stderr, "Building semantic index (%d issues)...\n", len(docs))
+		if !*robotSearch {
+			searcher.Progress = os.Stderr
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		syncStats, err := search.SyncVectorIndex(ctx, idx, embedder, docs, 64)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error building semantic index: %v\n", err)
-			os.Exit(1)
-		}
-		if !loaded || syncStats.Changed() {
-			if err := idx.Save(indexPath); err != nil {
-				fmt.Fprintf(os.Stderr, "Error saving semantic index: %v\n", err)
-				os.Exit(1)
-			}
-		}
-
-		qvecs, err := embedder.Embed(ctx, []string{*semanticQuery})
-		if err != nil || len(qvecs) != 1 {
-			if err == nil {
-				err = fmt.Errorf("embedder returned %d vectors for query", len(qvecs))
-			}
-			fmt.Fprintf(os.Stderr, "Error embedding query: %v\n", err)
-			os.Exit(1)
-		}
-
-		limit := *searchLimit
-		if limit <= 0 {
-			limit = 10
-		}
-		fetchLimit := limit
-		if searchCfg.Mode == search.SearchModeHybrid {
-			fetchLimit = search.HybridCandidateLimit(limit, len(issuesForSearch), *semanticQuery)
-		}
-		results, err := idx.SearchTopK(qvecs[0], fetchLimit)
+		out, err := searcher.Search(ctx, issuesForSearch, dataHash, semanticSearchRequest{
+			Query:  *semanticQuery,
+			Limit:  *searchLimit,
+			Config: searchCfg,
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error searching index: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		results = search.ApplyShortQueryLexicalBoost(results, *semanticQuery, docs)
-		if isLikelyIssueID(*semanticQuery) {
-			results = promoteExactSearchResult(*semanticQuery, results)
-		}
-
-		titleByID := make(map[string]string, len(issuesForSearch))
-		for _, iss := range issuesForSearch {
-			titleByID[iss.ID] = iss.Title
-		}
-
-		var hybridResults []search.HybridScore
-		var resolvedPreset search.PresetName
-		var resolvedWeights *search.Weights
-		if searchCfg.Mode == search.SearchModeHybrid {
-			weights, presetName, err := resolveSearchWeights(searchCfg)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			weights = weights.Normalize()
-			weights = search.AdjustWeightsForQuery(weights, *semanticQuery)
-			resolvedPreset = presetName
-			resolvedWeights = &weights
-
-			cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issuesForSearch))
-			if err := cache.Refresh(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error computing hybrid metrics: %v\n", err)
-				os.Exit(1)
-			}
-
-			scorer := search.NewHybridScorer(weights, cache)
-			hybridResults, err = buildHybridScores(results, scorer)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error scoring hybrid results: %v\n", err)
-				os.Exit(1)
-			}
-			if isLikelyIssueID(*semanticQuery) {
-				hybridResults = promoteExactHybridResult(*semanticQuery, hybridResults)
-			}
-			if len(hybridResults) > limit {
-				hybridResults = hybridResults[:limit]
-			}
-		}
 
 		if *robotSearch {
-			out := robotSearchOutput{
-				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-				DataHash:    dataHash,
-				Query:       *semanticQuery,
-				Provider:    embedCfg.Provider,
-				Model:       embedCfg.Model,
-				Dim:         embedder.Dim(),
-				IndexPath:   indexPath,
-				Index:       syncStats,
-				Loaded:      loaded,
-				Limit:       limit,
-				Mode:        searchCfg.Mode,
-			}
-			if searchCfg.Mode == search.SearchModeHybrid {
-				out.Preset = resolvedPreset
-				out.Weights = resolvedWeights
-			}
-			out.Results = make([]robotSearchResult, 0, max(len(results), len(hybridResults)))
-			if searchCfg.Mode == search.SearchModeHybrid {
-				for _, r := range hybridResults {
-					out.Results = append(out.Results, robotSearchResult{
-						IssueID:         r.IssueID,
-						Score:           r.FinalScore,
-						TextScore:       r.TextScore,
-						Title:           titleByID[r.IssueID],
-						ComponentScores: r.ComponentScores,
-					})
-				}
-				out.UsageHints = []string{
-					"jq '.results[] | {id: .issue_id, score: .score, text: .text_score}' - Extract scores",
-					"jq '.results[] | {id: .issue_id, components: .component_scores}' - Hybrid breakdown",
-					"jq '.index' - Index update stats (added/updated/removed/embedded)",
-				}
-			} else {
-				for _, r := range results {
-					out.Results = append(out.Results, robotSearchResult{
-						IssueID: r.IssueID,
-						Score:   r.Score,
-						Title:   titleByID[r.IssueID],
-					})
-				}
-				out.UsageHints = []string{
-					"jq '.results[] | {id: .issue_id, score: .score, title: .title}' - Extract results",
-					"jq '.index' - Index update stats (added/updated/removed/embedded)",
-				}
-			}
-
 			if err := writeRobotSearchOutput(os.Stdout, out); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding robot-search: %v\n", err)
 				os.Exit(1)
@@ -1625,16 +1764,13 @@ func main() {
 		}
 
 		// Human-readable output
-		if !loaded || syncStats.Changed() {
-			fmt.Fprintf(os.Stderr, "Index: +%d ~%d -%d (%d total) → %s\n", syncStats.Added, syncStats.Updated, syncStats.Removed, idx.Size(), indexPath)
+		if !out.Loaded || out.Index.Changed() {
+			fmt.Fprintf(os.Stderr, "Index: +%d ~%d -%d (%d total) → %s\n", out.Index.Added, out.Index.Updated, out.Index.Removed, searcher.Size(), out.IndexPath)
 		}
-		if searchCfg.Mode == search.SearchModeHybrid {
-			for _, r := range hybridResults {
-				fmt.Printf("%.4f\t%s\t%s\n", r.FinalScore, r.IssueID, titleByID[r.IssueID])
-			}
-		} else {
-			for _, r := range results {
-				fmt.Printf("%.4f\t%s\t%s\n", r.Score, r.IssueID, titleByID[r.IssueID])
+		for _, r := range out.Results {
+			fmt.Printf("%.4f\t%s\t%s\n", r.Score, r.IssueID, r.Title)
+			if r.Match != nil && r.Match.Field != search.ChunkFieldTitle {
+				fmt.Printf("\t\t%s\n", formatSearchMatch(r.Match, stdoutIsTTY))
 			}
 		}
 		os.Exit(0)
@@ -1642,7 +1778,7 @@ func main() {
 
 	// Handle --pages wizard (bv-10g)
 	if *pagesWizard {
-		if err := runPagesWizard(beadsPath); err != nil {
+		if err := runPagesWizard(beadsPath, scoringProfile); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -1718,7 +1854,7 @@ func main() {
 
 			// Compute triage
 			fmt.Println("  → Generating triage data...")
-			triage := analysis.ComputeTriage(exportIssues)
+			triage := analysis.ComputeTriageWithOptions(exportIssues, analysis.TriageOptions{Profile: scoringProfile})
 
 			// Extract dependencies
 			var deps []*model.Dependency
@@ -1813,7 +1949,10 @@ func main() {
 			var watchFiles []string
 			var watchers []*watcher.Watcher
 
-			if *workspaceConfig != "" {
+			if *importPath != "" {
+				// Import mode: re-import whenever the export file is replaced
+				watchFiles = append(watchFiles, *importPath)
+			} else if *workspaceConfig != "" {
 				// Workspace mode: watch all repos' issues.jsonl files (bv-79)
 				wsConfig, err := workspace.LoadConfig(*workspaceConfig)
 				if err != nil {
@@ -1912,7 +2051,9 @@ func main() {
 					// Reload issues from disk using appropriate method
 					var freshIssues []model.Issue
 					var err error
-					if *workspaceConfig != "" {
+					if *importPath != "" {
+						freshIssues, _, err = datasource.ImportFile(*importPath, *importFormat)
+					} else if *workspaceConfig != "" {
 						freshIssues, _, err = workspace.LoadAllFromConfig(context.Background(), *workspaceConfig)
 					} else {
 						freshIssues, err = datasource.LoadIssues("")
@@ -2161,7 +2302,7 @@ func main() {
 			}
 
 			// Compute triage for the graph export
-			triageOpts := analysis.TriageOptions{WaitForPhase2: true}
+			triageOpts := analysis.TriageOptions{WaitForPhase2: true, Profile: scoringProfile}
 			triage := analysis.ComputeTriageWithOptions(exportIssues, triageOpts)
 
 			opts := export.InteractiveGraphOptions{
@@ -2206,6 +2347,46 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --export-timeline - Gantt chart of ETAs in PNG/SVG
+	if *exportTimeline != "" {
+		exportIssues := issues
+		if *labelScope != "" {
+			var filtered []model.Issue
+			for _, iss := range issues {
+				for _, lbl := range iss.Labels {
+					if strings.EqualFold(lbl, *labelScope) {
+						filtered = append(filtered, iss)
+						break
+					}
+				}
+			}
+			exportIssues = filtered
+		}
+
+		analyzer := analysis.NewAnalyzer(exportIssues)
+		stats := analyzer.Analyze()
+		timeline := analysis.BuildTimeline(exportIssues, &stats, analyzer.GetExecutionPlan(), analysis.TimelineOptions{Agents: *forecastAgents})
+
+		title := *graphTitle
+		if title == "" {
+			cwd, _ := os.Getwd()
+			title = filepath.Base(cwd) + " timeline"
+		}
+		err := export.SaveTimelineSnapshot(export.TimelineSnapshotOptions{
+			Path:     *exportTimeline,
+			Title:    title,
+			Timeline: timeline,
+			DataHash: dataHash,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting timeline: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Timeline exported to %s (%d issues, %d on the critical path, %d missed due dates)\n",
+			*exportTimeline, len(timeline.Items), len(timeline.CriticalPath), timeline.MissedDue)
+		os.Exit(0)
+	}
+
 	// Handle --robot-alerts (drift + proactive)
 	if *robotAlerts {
 		driftConfig, err := drift.LoadConfig(projectDir)
@@ -2554,151 +2735,13 @@ func main() {
 			analyzer.SetConfig(&cfg)
 		}
 		stats := analyzer.Analyze()
-		// Generate top 50 lists for summary, but full stats are included in the struct
-		insights := stats.GenerateInsights(50)
-
-		// Add project-level velocity snapshot (using dedicated helper for efficiency)
-		if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
-			snap := &analysis.VelocitySnapshot{
-				Closed7:   v.ClosedLast7Days,
-				Closed30:  v.ClosedLast30Days,
-				AvgDays:   v.AvgDaysToClose,
-				Estimated: v.Estimated,
-			}
-			if len(v.Weekly) > 0 {
-				snap.Weekly = make([]int, len(v.Weekly))
-				for i := range v.Weekly {
-					snap.Weekly[i] = v.Weekly[i].Closed
-				}
-			}
-			insights.Velocity = snap
-		}
-
-		// Optional cap for metric maps to avoid overload
-		limitMaps := func(m map[string]float64, limit int) map[string]float64 {
-			if limit <= 0 || limit >= len(m) {
-				return m
-			}
-			type kv struct {
-				k string
-				v float64
-			}
-			var items []kv
-			for k, v := range m {
-				items = append(items, kv{k, v})
-			}
-			sort.Slice(items, func(i, j int) bool {
-				if items[i].v == items[j].v {
-					return items[i].k < items[j].k
-				}
-				return items[i].v > items[j].v
-			})
-			trim := make(map[string]float64, limit)
-			for i := 0; i < limit; i++ {
-				trim[items[i].k] = items[i].v
-			}
-			return trim
-		}
-
-		limitMapInt := func(m map[string]int, limit int) map[string]int {
-			if limit <= 0 || len(m) <= limit {
-				return m
-			}
-			trim := make(map[string]int, limit)
-			count := 0
-			for k, v := range m {
-				trim[k] = v
-				count++
-				if count >= limit {
-					break
-				}
-			}
-			return trim
-		}
-
-		limitSlice := func(s []string, limit int) []string {
-			if limit <= 0 || len(s) <= limit {
-				return s
-			}
-			return s[:limit]
-		}
-
-		// Default cap to keep payload small; allow override via env
-		mapLimit := 200
-		if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
-			if n, err := strconv.Atoi(v); err == nil && n > 0 {
-				mapLimit = n
-			}
-		}
-
-		fullStats := struct {
-			PageRank          map[string]float64 `json:"pagerank"`
-			Betweenness       map[string]float64 `json:"betweenness"`
-			Eigenvector       map[string]float64 `json:"eigenvector"`
-			Hubs              map[string]float64 `json:"hubs"`
-			Authorities       map[string]float64 `json:"authorities"`
-			CriticalPathScore map[string]float64 `json:"critical_path_score"`
-			CoreNumber        map[string]int     `json:"core_number"`
-			Slack             map[string]float64 `json:"slack"`
-			Articulation      []string           `json:"articulation_points"`
-		}{
-			PageRank:          limitMaps(stats.PageRank(), mapLimit),
-			Betweenness:       limitMaps(stats.Betweenness(), mapLimit),
-			Eigenvector:       limitMaps(stats.Eigenvector(), mapLimit),
-			Hubs:              limitMaps(stats.Hubs(), mapLimit),
-			Authorities:       limitMaps(stats.Authorities(), mapLimit),
-			CriticalPathScore: limitMaps(stats.CriticalPathScore(), mapLimit),
-			CoreNumber:        limitMapInt(stats.CoreNumber(), mapLimit),
-			Slack:             limitMaps(stats.Slack(), mapLimit),
-			Articulation:      limitSlice(stats.ArticulationPoints(), mapLimit),
-		}
-
-		// Get top what-if deltas for issues with highest downstream impact (bv-83)
-		topWhatIfs := analyzer.TopWhatIfDeltas(10)
-
-		// Generate advanced insights with canonical structure (bv-181)
-		advancedInsights := analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig())
-
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			analysis.Insights
-			FullStats        interface{}                `json:"full_stats"`
-			TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
-			AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
-			UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
-			DataHash:         dataHash,
-			AsOf:             *asOf,
-			AsOfCommit:       asOfResolved,
-			AnalysisConfig:   stats.Config,
-			Status:           stats.Status(),
-			LabelScope:       *labelScope,
-			LabelContext:     labelScopeContext,
-			Insights:         insights,
-			FullStats:        fullStats,
-			TopWhatIfs:       topWhatIfs,
-			AdvancedInsights: advancedInsights,
-			UsageHints: []string{
-				"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
-				"jq '.CriticalPath[:3]' - Top 3 critical path items",
-				"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
-				"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
-				"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
-				"jq '.full_stats.articulation_points' - Structural cut points",
-				"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
-				"jq '.Cycles | length' - Count of detected cycles",
-				"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
-				"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
-			},
-		}
+		output := buildRobotInsightsOutput(robotScope{
+			DataHash:     dataHash,
+			AsOf:         *asOf,
+			AsOfCommit:   asOfResolved,
+			LabelScope:   *labelScope,
+			LabelContext: labelScopeContext,
+		}, issues, analyzer, &stats)
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
@@ -2710,64 +2753,20 @@ func main() {
 
 	if *robotPlan {
 		analyzer := analysis.NewAnalyzer(issues)
-		// For --robot-plan we primarily need Phase 1 metrics (degree/topo/density).
-		// However, we still emit a stable status contract for agents. If the user
-		// explicitly asks for full analysis, honor it; otherwise, skip expensive
-		// centrality metrics and record the skip reasons deterministically.
-		cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
-		if *forceFullAnalysis {
-			cfg = analysis.FullAnalysisConfig()
-		} else {
-			const skipReason = "not computed for --robot-plan"
-			cfg.ComputePageRank = false
-			cfg.PageRankSkipReason = skipReason
-			cfg.ComputeBetweenness = false
-			cfg.BetweennessMode = analysis.BetweennessSkip
-			cfg.BetweennessSkipReason = skipReason
-			cfg.ComputeHITS = false
-			cfg.HITSSkipReason = skipReason
-			cfg.ComputeEigenvector = false
-			cfg.ComputeCriticalPath = false
-			cfg.ComputeCycles = false
-			cfg.CyclesSkipReason = skipReason
-		}
+		cfg := robotPlanAnalysisConfig(issues, *forceFullAnalysis)
 
 		plan := analyzer.GetExecutionPlan()
 
 		stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
 		stats.WaitForPhase2()
-		status := stats.Status()
 
-		// Wrap with metadata
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			Plan           analysis.ExecutionPlan  `json:"plan"`
-			UsageHints     []string                `json:"usage_hints"` // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
-			DataHash:       dataHash,
-			AsOf:           *asOf,
-			AsOfCommit:     asOfResolved,
-			AnalysisConfig: cfg,
-			Status:         status,
-			LabelScope:     *labelScope,
-			LabelContext:   labelScopeContext,
-			Plan:           plan,
-			UsageHints: []string{
-				"jq '.plan.tracks | length' - Number of parallel execution tracks",
-				"jq '.plan.tracks[0].items | map(.id)' - First track item IDs",
-				"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
-				"jq '.plan.summary' - High-level execution summary",
-				"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
-			},
-		}
+		output := buildRobotPlanOutput(robotScope{
+			DataHash:     dataHash,
+			AsOf:         *asOf,
+			AsOfCommit:   asOfResolved,
+			LabelScope:   *labelScope,
+			LabelContext: labelScopeContext,
+		}, cfg, stats.Status(), plan)
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
@@ -2779,6 +2778,7 @@ func main() {
 
 	if *robotPriority {
 		analyzer := analysis.NewAnalyzer(issues)
+		analyzer.SetScoringProfile(scoringProfile)
 		cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
 		if *forceFullAnalysis {
 			cfg = analysis.FullAnalysisConfig()
@@ -2860,6 +2860,7 @@ func main() {
 			Status            analysis.MetricStatus                     `json:"status"`
 			LabelScope        string                                    `json:"label_scope,omitempty"`   // bv-122: Label filter applied
 			LabelContext      *analysis.LabelHealth                     `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+			Profile           string                                    `json:"profile"`                 // Scoring profile used for impact scores
 			Recommendations   []analysis.EnhancedPriorityRecommendation `json:"recommendations"`
 			FieldDescriptions map[string]string                         `json:"field_descriptions"`
 			Filters           struct {
@@ -2883,6 +2884,7 @@ func main() {
 			Status:            status,
 			LabelScope:        *labelScope,
 			LabelContext:      labelScopeContext,
+			Profile:           scoringProfile.Name,
 			Recommendations:   recommendations,
 			FieldDescriptions: analysis.DefaultFieldDescriptions(),
 			Usage: []string{
@@ -2915,52 +2917,8 @@ func main() {
 		// Attempt to load history for staleness analysis
 		// We use a best-effort approach here - if history isn't available or fails,
 		// we just proceed without staleness data.
-		var historyReport *correlation.HistoryReport
-
-		// bv-perf: Skip history loading if no open issues exist
-		// ComputeStaleness only processes open issues, so loading git history
-		// is wasted work when all issues are closed.
-		hasOpenIssues := false
-		for _, issue := range issues {
-			if issue.Status != model.StatusClosed && issue.Status != model.StatusTombstone {
-				hasOpenIssues = true
-				break
-			}
-		}
-
-		if hasOpenIssues {
-			if cwd, err := os.Getwd(); err == nil {
-				if beadsDir, err := loader.GetBeadsDir(""); err == nil {
-					if beadsPath, err := loader.FindJSONLPath(beadsDir); err == nil {
-						// Use a smaller limit for triage to keep it fast, unless overridden
-						limit := *historyLimit
-						if limit == 500 { // If default
-							limit = 200 // Use smaller default for triage
-						}
-
-						// Validate repo first
-						if correlation.ValidateRepository(cwd) == nil {
-							beadInfos := make([]correlation.BeadInfo, len(issues))
-							for i, issue := range issues {
-								beadInfos[i] = correlation.BeadInfo{
-									ID:     issue.ID,
-									Title:  issue.Title,
-									Status: string(issue.Status),
-								}
-							}
-
-							correlator := correlation.NewCorrelator(cwd, beadsPath)
-							opts := correlation.CorrelatorOptions{Limit: limit}
-
-							// Swallow errors for triage flow - staleness is optional
-							if report, err := correlator.GenerateReport(beadInfos, opts); err == nil {
-								historyReport = report
-							}
-						}
-					}
-				}
-			}
-		}
+		cwd, _ := os.Getwd()
+		historyReport := loadTriageHistory(cwd, issues, *historyLimit)
 
 		// bv-87: Support track/label-aware grouping for multi-agent coordination
 		opts := analysis.TriageOptions{
@@ -2969,105 +2927,23 @@ func main() {
 			WaitForPhase2: true, // Triage needs full graph metrics
 			UseFastConfig: true, // Use minimal Phase 2 config for robot mode (bv-t1js)
 			History:       historyReport,
+			Profile:       scoringProfile,
 		}
 		triage := analysis.ComputeTriageWithOptions(issues, opts)
-
-		// bv-90: Load feedback data for output
-		var feedbackInfo *analysis.FeedbackJSON
-		if robotTriageBeadsDir, err := loader.GetBeadsDir(""); err == nil {
-			if feedbackData, err := analysis.LoadFeedback(robotTriageBeadsDir); err == nil && len(feedbackData.Events) > 0 {
-				info := feedbackData.ToJSON()
-				feedbackInfo = &info
-			}
-		}
+		scope := robotScope{DataHash: dataHash, AsOf: *asOf, AsOfCommit: asOfResolved}
 
 		if *robotNext {
 			// Minimal output: just the top pick
-			envelope := NewRobotEnvelope(dataHash)
-			if len(triage.QuickRef.TopPicks) == 0 {
-				output := struct {
-					RobotEnvelope
-					AsOf       string `json:"as_of,omitempty"`
-					AsOfCommit string `json:"as_of_commit,omitempty"`
-					Message    string `json:"message"`
-				}{
-					RobotEnvelope: envelope,
-					AsOf:          *asOf,
-					AsOfCommit:    asOfResolved,
-					Message:       "No actionable items available",
-				}
-				encoder := newRobotEncoder(os.Stdout)
-				if err := encoder.Encode(output); err != nil {
-					fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
-					os.Exit(1)
-				}
-				os.Exit(0)
-			}
-
-			top := triage.QuickRef.TopPicks[0]
-			output := struct {
-				RobotEnvelope
-				AsOf       string   `json:"as_of,omitempty"`
-				AsOfCommit string   `json:"as_of_commit,omitempty"`
-				ID         string   `json:"id"`
-				Title      string   `json:"title"`
-				Score      float64  `json:"score"`
-				Reasons    []string `json:"reasons"`
-				Unblocks   int      `json:"unblocks"`
-				ClaimCmd   string   `json:"claim_command"`
-				ShowCmd    string   `json:"show_command"`
-			}{
-				RobotEnvelope: envelope,
-				AsOf:          *asOf,
-				AsOfCommit:    asOfResolved,
-				ID:            top.ID,
-				Title:         top.Title,
-				Score:         top.Score,
-				Reasons:       top.Reasons,
-				Unblocks:      top.Unblocks,
-				ClaimCmd:      fmt.Sprintf("br update %s --status=in_progress", top.ID),
-				ShowCmd:       fmt.Sprintf("br show %s", top.ID),
-			}
-
 			encoder := newRobotEncoder(os.Stdout)
-			if err := encoder.Encode(output); err != nil {
+			if err := encoder.Encode(buildRobotNextOutput(scope, triage)); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
 				os.Exit(1)
 			}
 			os.Exit(0)
 		}
 
-		// Full triage output with usage hints
-		output := struct {
-			GeneratedAt string                 `json:"generated_at"`
-			DataHash    string                 `json:"data_hash"`
-			AsOf        string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
-			AsOfCommit  string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			Triage      analysis.TriageResult  `json:"triage"`
-			Feedback    *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
-			UsageHints  []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-			DataHash:    dataHash,
-			AsOf:        *asOf,
-			AsOfCommit:  asOfResolved,
-			Triage:      triage,
-			Feedback:    feedbackInfo,
-			UsageHints: []string{
-				"jq '.triage.quick_ref.top_picks[:3]' - Top 3 picks for immediate work",
-				"jq '.triage.recommendations[3:10] | map({id,title,score})' - Next candidates after top picks",
-				"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
-				"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
-				"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
-				"jq '.triage.quick_wins' - Low-effort, high-impact items",
-				"--robot-next - Get only the single top recommendation",
-				"--robot-triage-by-track - Group by execution track for multi-agent coordination",
-				"--robot-triage-by-label - Group by label for area-focused agents",
-				"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
-				"jq '.triage.recommendations_by_label[].claim_command' - Claim commands per label",
-				"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
-			},
-		}
+		// Full triage output with usage hints (bv-90: includes feedback state)
+		output := buildRobotTriageOutput(scope, triage, loadTriageFeedback())
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding robot-triage: %v\n", err)
@@ -3079,7 +2955,7 @@ func main() {
 	// Handle --priority-brief flag (bv-96)
 	if *priorityBrief != "" {
 		fmt.Printf("Generating priority brief to %s...\n", *priorityBrief)
-		triage := analysis.ComputeTriage(issues)
+		triage := analysis.ComputeTriageWithOptions(issues, analysis.TriageOptions{Profile: scoringProfile})
 
 		// Marshal triage to JSON for the export function
 		triageJSON, err := json.Marshal(triage)
@@ -3118,7 +2994,7 @@ func main() {
 		}
 
 		// Generate triage data
-		triage := analysis.ComputeTriage(issues)
+		triage := analysis.ComputeTriageWithOptions(issues, analysis.TriageOptions{Profile: scoringProfile})
 		triageJSON, err := json.MarshalIndent(triage, "", "  ")
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error marshaling triage: %v\n", err)
@@ -3194,7 +3070,7 @@ func main() {
 
 	// Handle --emit-script flag (bv-89)
 	if *emitScript {
-		triage := analysis.ComputeTriage(issues)
+		triage := analysis.ComputeTriageWithOptions(issues, analysis.TriageOptions{Profile: scoringProfile})
 
 		// Determine script limit
 		limit := *scriptLimit
@@ -3292,13 +3168,12 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Build correlator options
-		opts := correlation.CorrelatorOptions{
-			BeadID: *beadHistory,
-			Limit:  *historyLimit,
-		}
-
 		// Parse --history-since if provided
+		req := robotHistoryRequest{
+			BeadID:        *beadHistory,
+			Limit:         *historyLimit,
+			MinConfidence: *minConfidence,
+		}
 		if *historySince != "" {
 			since, err := recipe.ParseRelativeTime(*historySince, time.Now())
 			if err != nil {
@@ -3306,54 +3181,80 @@ func main() {
 				os.Exit(1)
 			}
 			if !since.IsZero() {
-				opts.Since = &since
+				req.Since = &since
 			}
 		}
 
-		// Convert issues to BeadInfo for correlator
-		beadInfos := make([]correlation.BeadInfo, len(issues))
-		for i, issue := range issues {
-			beadInfos[i] = correlation.BeadInfo{
-				ID:     issue.ID,
-				Title:  issue.Title,
-				Status: string(issue.Status),
-			}
+		// Generate report with explicit beads path
+		report, err := buildRobotHistoryReport(cwd, beadsPath, issues, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating history report: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Output JSON
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding history report: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --robot-timeline flag
+	if *robotTimeline != "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		if err := correlation.ValidateRepository(cwd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		beadsDir, err := loader.GetBeadsDir("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+			os.Exit(1)
+		}
+		beadsPath, err := loader.FindJSONLPath(beadsDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding beads file: %v\n", err)
+			os.Exit(1)
 		}
 
-		// Generate report with explicit beads path
-		correlator := correlation.NewCorrelator(cwd, beadsPath)
-		report, err := correlator.GenerateReport(beadInfos, opts)
+		output, ok, err := buildRobotTimelineOutput(cwd, beadsPath, issues, *robotTimeline)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating history report: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error building timeline: %v\n", err)
 			os.Exit(1)
 		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Issue not found: %s\n", *robotTimeline)
+			os.Exit(1)
+		}
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding timeline: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-		// Apply confidence filter if specified
-		if *minConfidence > 0 {
-			scorer := correlation.NewScorer()
-			report.Histories = scorer.FilterHistoriesByConfidence(report.Histories, *minConfidence)
-
-			// Rebuild commit index after filtering
-			report.CommitIndex = make(correlation.CommitIndex)
-			for beadID, history := range report.Histories {
-				for _, commit := range history.Commits {
-					report.CommitIndex[commit.SHA] = append(report.CommitIndex[commit.SHA], beadID)
-				}
-			}
-
-			// Update stats
-			report.Stats.BeadsWithCommits = 0
-			for _, history := range report.Histories {
-				if len(history.Commits) > 0 {
-					report.Stats.BeadsWithCommits++
+	// Handle --robot-flow-metrics flag
+	if *robotFlowMetrics {
+		var history map[string][]analysis.StatusChange
+		if cwd, err := os.Getwd(); err == nil {
+			if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+				if beadsPath, err := loader.FindJSONLPath(beadsDir); err == nil {
+					history = loadStatusHistory(cwd, beadsPath)
 				}
 			}
 		}
-
-		// Output JSON
+		cfg := analysis.FlowConfig{WindowDays: *flowWindow, Weeks: *flowWeeks}
+		output := buildRobotFlowMetricsOutput(issues, history, cfg, time.Now())
 		encoder := newRobotEncoder(os.Stdout)
-		if err := encoder.Encode(report); err != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding history report: %v\n", err)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding flow metrics: %v\n", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
@@ -3833,51 +3734,13 @@ func main() {
 			os.Exit(1)
 		}
 
-		beadInfos := make([]correlation.BeadInfo, len(issues))
-		for i, issue := range issues {
-			beadInfos[i] = correlation.BeadInfo{
-				ID:     issue.ID,
-				Title:  issue.Title,
-				Status: string(issue.Status),
-			}
-		}
-
-		correlator := correlation.NewCorrelator(cwd, beadsPath)
-		report, err := correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{
-			Limit: *historyLimit,
-		})
+		report, err := generateCorrelationReport(cwd, beadsPath, issues, *historyLimit)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating history report: %v\n", err)
 			os.Exit(1)
 		}
 
-		fileLookup := correlation.NewFileLookup(report)
-		files := strings.Split(*robotImpact, ",")
-		for i := range files {
-			files[i] = strings.TrimSpace(files[i])
-		}
-
-		impactResult := fileLookup.ImpactAnalysis(files)
-
-		type ImpactOutput struct {
-			RobotEnvelope
-			Files         []string                   `json:"files"`
-			RiskLevel     string                     `json:"risk_level"`
-			RiskScore     float64                    `json:"risk_score"`
-			Summary       string                     `json:"summary"`
-			Warnings      []string                   `json:"warnings"`
-			AffectedBeads []correlation.AffectedBead `json:"affected_beads"`
-		}
-
-		output := ImpactOutput{
-			RobotEnvelope: NewRobotEnvelope(report.DataHash),
-			Files:         impactResult.Files,
-			RiskLevel:     impactResult.RiskLevel,
-			RiskScore:     impactResult.RiskScore,
-			Summary:       impactResult.Summary,
-			Warnings:      impactResult.Warnings,
-			AffectedBeads: impactResult.AffectedBeads,
-		}
+		output := buildRobotImpactOutput(report, strings.Split(*robotImpact, ","))
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
@@ -3992,65 +3855,80 @@ func main() {
 			os.Exit(1)
 		}
 
-		beadInfos := make([]correlation.BeadInfo, len(issues))
-		for i, issue := range issues {
-			beadInfos[i] = correlation.BeadInfo{
-				ID:     issue.ID,
-				Title:  issue.Title,
-				Status: string(issue.Status),
-			}
-		}
-
-		correlatorObj := correlation.NewCorrelator(cwd, beadsPath)
-		report, err := correlatorObj.GenerateReport(beadInfos, correlation.CorrelatorOptions{
-			Limit: *historyLimit,
-		})
+		report, err := generateCorrelationReport(cwd, beadsPath, issues, *historyLimit)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating history report: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Build dependency graph from issues
-		depGraph := make(map[string][]string)
-		for _, issue := range issues {
-			for _, dep := range issue.Dependencies {
-				depGraph[issue.ID] = append(depGraph[issue.ID], dep.DependsOnID)
-			}
-		}
-
-		// Configure options
-		opts := correlation.RelatedWorkOptions{
-			MinRelevance:      *relatedMinRelevance,
-			MaxResults:        *relatedMaxResults,
-			ConcurrencyWindow: 7 * 24 * time.Hour,
-			IncludeClosed:     *relatedIncludeClosed,
-			DependencyGraph:   depGraph,
+		output, ok := buildRobotRelatedOutput(report, issues, robotRelatedRequest{
+			BeadID:        *robotRelatedWork,
+			MinRelevance:  *relatedMinRelevance,
+			MaxResults:    *relatedMaxResults,
+			IncludeClosed: *relatedIncludeClosed,
+		})
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Bead not found in history: %s\n", *robotRelatedWork)
+			os.Exit(1)
 		}
 
-		result := report.FindRelatedWork(*robotRelatedWork, opts)
-		if result == nil {
-			fmt.Fprintf(os.Stderr, "Bead not found in history: %s\n", *robotRelatedWork)
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding related work: %v\n", err)
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		// Add envelope fields to output
-		type RelatedWorkOutput struct {
-			*correlation.RelatedWorkResult
-			DataHash     string `json:"data_hash"`
-			OutputFormat string `json:"output_format,omitempty"`
-			Version      string `json:"version,omitempty"`
+	// Handle write-back mutation flags
+	if *robotClaim != "" || *robotClose != "" || *robotLink != "" || *robotUpdate != "" {
+		var req robotMutationRequest
+		selected := 0
+		for _, c := range []struct{ action, id string }{
+			{"claim", *robotClaim}, {"close", *robotClose}, {"link", *robotLink}, {"update", *robotUpdate},
+		} {
+			if c.id != "" {
+				req.Action, req.IssueID = c.action, c.id
+				selected++
+			}
+		}
+		if selected > 1 {
+			fmt.Fprintln(os.Stderr, "Error: use only one of --robot-claim, --robot-close, --robot-link, --robot-update")
+			os.Exit(2)
+		}
+		if *importPath != "" {
+			fmt.Fprintln(os.Stderr, "Error: write-back is not supported for --import data")
+			os.Exit(2)
+		}
+		req.Assignee = *mutAssignee
+		req.Force = *mutForce
+		req.Reason = *mutReason
+		req.Status = *mutSetStatus
+		req.Priority = *mutSetPriority
+		req.AddLabels = splitFlagList(*mutAddLabels)
+		req.RemoveLabels = splitFlagList(*mutRemoveLabels)
+		req.DependsOn = splitFlagList(*mutDependsOn)
+		req.Unlink = splitFlagList(*mutUnlink)
+		req.DepType = *mutDepType
+		if _, err := req.mutation(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
 		}
 
-		output := RelatedWorkOutput{
-			RelatedWorkResult: result,
-			DataHash:          report.DataHash,
-			OutputFormat:      robotOutputFormat,
-			Version:           version.Version,
+		beadsDir, err := loader.GetBeadsDir("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := runRobotMutation(beadsDir, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding related work: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error encoding mutation result: %v\n", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
@@ -4071,26 +3949,12 @@ func main() {
 		}
 
 		an := analysis.NewAnalyzer(issues)
-		result := an.GetBlockerChain(*robotBlockerChain)
-
-		if result == nil {
+		output, ok := buildRobotBlockerChainOutput(an, analysis.ComputeDataHash(issues), *robotBlockerChain)
+		if !ok {
 			fmt.Fprintf(os.Stderr, "Issue not found: %s\n", *robotBlockerChain)
 			os.Exit(1)
 		}
 
-		type BlockerChainOutput struct {
-			RobotEnvelope
-			Result *analysis.BlockerChainResult `json:"result"`
-		}
-
-		// Compute data hash for consistency
-		dataHash := analysis.ComputeDataHash(issues)
-
-		output := BlockerChainOutput{
-			RobotEnvelope: NewRobotEnvelope(dataHash),
-			Result:        result,
-		}
-
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding blocker chain: %v\n", err)
@@ -4410,145 +4274,129 @@ func main() {
 		analyzer := analysis.NewAnalyzer(issues)
 		graphStats := analyzer.Analyze()
 
-		// Filter issues by label and sprint if specified
-		targetIssues := make([]model.Issue, 0, len(issues))
-		var sprintBeadIDs map[string]bool
-		if *forecastSprint != "" {
-			sprints, err := loader.LoadSprints(cwd)
-			if err == nil {
-				for _, s := range sprints {
-					if s.ID == *forecastSprint {
-						sprintBeadIDs = make(map[string]bool)
-						for _, bid := range s.BeadIDs {
-							sprintBeadIDs[bid] = true
-						}
-						break
-					}
+		req := robotForecastRequest{
+			Target: *robotForecast,
+			Label:  *forecastLabel,
+			Sprint: *forecastSprint,
+			Epic:   *forecastEpic,
+			Agents: *forecastAgents,
+			Trials: *forecastTrials,
+			Seed:   *forecastSeed,
+		}
+		if req.Trials > 0 {
+			if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+				if beadsPath, err := loader.FindJSONLPath(beadsDir); err == nil {
+					req.History = loadStatusHistory(cwd, beadsPath)
 				}
 			}
-			if sprintBeadIDs == nil {
+		}
+		if *forecastSprint != "" {
+			if sprints, err := loader.LoadSprints(cwd); err == nil {
+				req.SprintBeadIDs = sprintBeadIDSet(sprints, *forecastSprint)
+			}
+			if req.SprintBeadIDs == nil {
 				fmt.Fprintf(os.Stderr, "Sprint not found: %s\n", *forecastSprint)
 				os.Exit(1)
 			}
 		}
 
-		for _, iss := range issues {
-			// Filter by label
-			if *forecastLabel != "" {
-				hasLabel := false
-				for _, l := range iss.Labels {
-					if l == *forecastLabel {
-						hasLabel = true
-						break
-					}
-				}
-				if !hasLabel {
-					continue
-				}
-			}
-			// Filter by sprint
-			if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
-				continue
-			}
-			targetIssues = append(targetIssues, iss)
+		output, err := buildRobotForecastOutput(issues, &graphStats, req, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 
-		now := time.Now()
-		agents := *forecastAgents
-		if agents <= 0 {
-			agents = 1
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		type ForecastSummary struct {
-			TotalMinutes  int       `json:"total_minutes"`
-			TotalDays     float64   `json:"total_days"`
-			AvgConfidence float64   `json:"avg_confidence"`
-			EarliestETA   time.Time `json:"earliest_eta"`
-			LatestETA     time.Time `json:"latest_eta"`
+	// Handle --robot-whatif flag
+	if *robotWhatIf != "" {
+		scenarios, agents, source, err := resolveWhatIfScenarios(*robotWhatIf)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		type ForecastOutput struct {
-			RobotEnvelope
-			Agents        int                    `json:"agents"`
-			Filters       map[string]string      `json:"filters,omitempty"`
-			ForecastCount int                    `json:"forecast_count"`
-			Forecasts     []analysis.ETAEstimate `json:"forecasts"`
-			Summary       *ForecastSummary       `json:"summary,omitempty"`
+		req := robotWhatIfRequest{
+			Scenarios: scenarios,
+			Source:    source,
+			Agents:    *forecastAgents,
+			Trials:    *forecastTrials,
+			Seed:      *forecastSeed,
+			Profile:   scoringProfile,
 		}
-
-		var forecasts []analysis.ETAEstimate
-		var outputErr error
-
-		if *robotForecast == "all" {
-			// Forecast all open issues
-			for _, iss := range targetIssues {
-				if iss.Status == model.StatusClosed {
-					continue
-				}
-				eta, err := analysis.EstimateETAForIssue(issues, &graphStats, iss.ID, agents, now)
-				if err != nil {
-					continue
-				}
-				forecasts = append(forecasts, eta)
-			}
-		} else {
-			// Single issue forecast
-			eta, err := analysis.EstimateETAForIssue(issues, &graphStats, *robotForecast, agents, now)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			forecasts = append(forecasts, eta)
-		}
-
-		// Build summary if multiple forecasts
-		var summary *ForecastSummary
-		if len(forecasts) > 1 {
-			totalMin := 0
-			totalConf := 0.0
-			earliest := forecasts[0].ETADate
-			latest := forecasts[0].ETADate
-			for _, f := range forecasts {
-				totalMin += f.EstimatedMinutes
-				totalConf += f.Confidence
-				if f.ETADate.Before(earliest) {
-					earliest = f.ETADate
-				}
-				if f.ETADate.After(latest) {
-					latest = f.ETADate
+		if agents > 0 {
+			req.Agents = agents
+		}
+		if req.Trials > 0 {
+			if cwd, err := os.Getwd(); err == nil {
+				if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+					if beadsPath, err := loader.FindJSONLPath(beadsDir); err == nil {
+						req.History = loadStatusHistory(cwd, beadsPath)
+					}
 				}
 			}
-			summary = &ForecastSummary{
-				TotalMinutes:  totalMin,
-				TotalDays:     float64(totalMin) / (60.0 * 8.0), // 8hr workday
-				AvgConfidence: totalConf / float64(len(forecasts)),
-				EarliestETA:   earliest,
-				LatestETA:     latest,
-			}
 		}
 
-		// Build output
-		filters := make(map[string]string)
-		if *forecastLabel != "" {
-			filters["label"] = *forecastLabel
+		output, err := buildRobotWhatIfOutput(issues, req, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		if *forecastSprint != "" {
-			filters["sprint"] = *forecastSprint
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding what-if report: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		output := ForecastOutput{
-			RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
-			Agents:        agents,
-			ForecastCount: len(forecasts),
-			Forecasts:     forecasts,
-			Summary:       summary,
+	// Handle --robot-schedule flag
+	if *robotSchedule {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		roster, source, err := resolveTeamRoster(cwd, *teamFile, *capacityAgents)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		if len(filters) > 0 {
-			output.Filters = filters
+
+		output := buildRobotScheduleOutput(issues, roster, source, time.Now())
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding schedule: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
+	// Handle --robot-train-ranking flag
+	if *robotTrainRanking {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		req := robotTrainRankingRequest{
+			Revisions: *trainRevisions,
+			Name:      *trainName,
+			Export:    *trainExport,
+		}
+		output, err := buildRobotTrainRankingOutput(cwd, issues, req, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		encoder := newRobotEncoder(os.Stdout)
-		if outputErr = encoder.Encode(output); outputErr != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", outputErr)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding ranking report: %v\n", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
@@ -4951,6 +4799,20 @@ func main() {
 		})
 	}
 
+	if *themeName != "" {
+		if err := m.SelectTheme(*themeName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --theme: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *scoringProfileName != "" {
+		if err := m.SelectScoringProfile(*scoringProfileName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Debug render mode - output a view to file and exit
 	if *debugRender != "" {
 		output := m.RenderDebugView(*debugRender, *debugWidth, *debugHeight)
@@ -4958,6 +4820,13 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Restore the last (or --session) saved view state
+	if *noSession {
+		m.DisableSessions()
+	} else if err := m.RestoreSession(*sessionName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (starting fresh)\n", err)
+	}
+
 	// Run Program
 	if err := runTUIProgram(m); err != nil {
 		fmt.Printf("Error running beads viewer: %v\n", err)
@@ -5025,7 +4894,12 @@ func runTUIProgram(m ui.Model) error {
 		}
 	}
 
-	_, err := p.Run()
+	final, err := p.Run()
+	if fm, ok := final.(ui.Model); ok {
+		if serr := fm.SaveSession(); serr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save session: %v\n", serr)
+		}
+	}
 	if err != nil && errors.Is(err, tea.ErrProgramKilled) {
 		if err == tea.ErrProgramKilled || errors.Is(err, tea.ErrInterrupted) {
 			return nil
@@ -5405,6 +5279,11 @@ func applyRecipeFilters(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 			}
 		}
 
+		// Custom field filter
+		if !f.MatchCustomFields(issue.CustomFields) {
+			continue
+		}
+
 		result = append(result, issue)
 	}
 
@@ -5446,6 +5325,13 @@ func applyRecipeSort(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 		case "status":
 			less = issues[i].Status < issues[j].Status
 		default:
+			if name, ok := recipe.CustomSortField(s.Field); ok {
+				cmp := recipe.CompareCustomField(&issues[i], &issues[j], name, !ascending)
+				if ascending {
+					return cmp < 0
+				}
+				return cmp > 0
+			}
 			// Unknown sort field, maintain order
 			return false
 		}
@@ -6274,8 +6160,9 @@ func runPreviewServer(dir string, liveReload bool) error {
 	return export.StartPreviewWithConfig(cfg)
 }
 
-// runPagesWizard runs the interactive deployment wizard (bv-10g).
-func runPagesWizard(beadsPath string) error {
+// runPagesWizard runs the interactive deployment wizard (bv-10g). Triage
+// in the exported site is scored with profile.
+func runPagesWizard(beadsPath string, profile *analysis.ScoringProfile) error {
 	wizard := export.NewWizard(beadsPath)
 
 	// Run interactive wizard to collect configuration
@@ -6340,7 +6227,7 @@ func runPagesWizard(beadsPath string) error {
 
 	// Compute triage
 	fmt.Println("  -> Generating triage data...")
-	triage := analysis.ComputeTriage(exportIssues)
+	triage := analysis.ComputeTriageWithOptions(exportIssues, analysis.TriageOptions{Profile: profile})
 
 	// Extract dependencies
 	var deps []*model.Dependency
@@ -7525,7 +7412,13 @@ func newJSONRobotEncoder(w io.Writer) *json.Encoder {
 // Default output is JSON. Use `--format toon` (or BV_OUTPUT_FORMAT/TOON_DEFAULT_FORMAT)
 // to emit TOON for agent-friendly token savings.
 func newRobotEncoder(w io.Writer) robotEncoder {
-	if robotOutputFormat == "toon" {
+	return newRobotEncoderFor(w, robotOutputFormat)
+}
+
+// newRobotEncoderFor creates a robot encoder for an explicit format, for
+// callers (such as bv serve) that negotiate the format per request.
+func newRobotEncoderFor(w io.Writer, format string) robotEncoder {
+	if format == "toon" {
 		return &toonRobotEncoder{w: w}
 	}
 	return newJSONRobotEncoder(w)
@@ -7616,11 +7509,13 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		"robot-triage": {
 			Flag: "--robot-triage", Description: "Unified triage: top picks, recommendations, quick wins, blockers, project health, velocity.",
 			KeyFields:   []string{"triage.quick_ref.top_picks", "triage.recommendations", "triage.quick_wins", "triage.blockers_to_clear", "triage.project_health"},
+			Params:      []string{"--profile <name>"},
 			NeedsIssues: true,
 		},
 		"robot-next": {
 			Flag: "--robot-next", Description: "Single top recommendation with claim/show commands.",
 			KeyFields:   []string{"id", "title", "score", "reasons", "unblocks", "claim_command", "show_command"},
+			Params:      []string{"--profile <name>"},
 			NeedsIssues: true,
 		},
 		"robot-plan": {
@@ -7636,16 +7531,19 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		"robot-priority": {
 			Flag: "--robot-priority", Description: "Priority misalignment detection: items whose graph importance differs from assigned priority.",
 			KeyFields:   []string{"misalignments", "suggestions"},
+			Params:      []string{"--profile <name>"},
 			NeedsIssues: true,
 		},
 		"robot-triage-by-track": {
 			Flag: "--robot-triage-by-track", Description: "Triage grouped by independent parallel execution tracks.",
 			KeyFields:   []string{"tracks[].track_id", "tracks[].top_pick", "tracks[].items"},
+			Params:      []string{"--profile <name>"},
 			NeedsIssues: true,
 		},
 		"robot-triage-by-label": {
 			Flag: "--robot-triage-by-label", Description: "Triage grouped by label for area-focused agents.",
 			KeyFields:   []string{"labels[].label", "labels[].top_pick", "labels[].items"},
+			Params:      []string{"--profile <name>"},
 			NeedsIssues: true,
 		},
 		"robot-alerts": {
@@ -7676,13 +7574,24 @@ func generateRobotDocs(topic string) map[string]interface{} {
 			Params:      []string{"--bead-history <id>", "--history-since <date>", "--history-limit <n>", "--min-confidence 0.0-1.0"},
 			NeedsIssues: true,
 		},
+		"robot-flow-metrics": {
+			Flag: "--robot-flow-metrics", Description: "Lead time, cycle time, time-in-status and throughput percentiles by label, type, assignee and priority.",
+			KeyFields:   []string{"overall", "by_label", "by_type", "by_assignee", "by_priority", "status_source"},
+			Params:      []string{"--flow-window <days>", "--flow-weeks <n>"},
+			NeedsIssues: true,
+		},
+		"robot-timeline": {
+			Flag: "--robot-timeline <id>", Description: "Field-level change timeline for one issue, reconstructed from git history.",
+			KeyFields:   []string{"events", "kind", "changes", "commit_sha", "time_in_status_hours"},
+			NeedsIssues: true,
+		},
 		"robot-diff": {
 			Flag: "--robot-diff", Description: "Changes since a historical point (commit, branch, tag, or date).",
 			Params:      []string{"--diff-since <ref>"},
 			NeedsIssues: true,
 		},
 		"robot-search": {
-			Flag: "--robot-search", Description: "Semantic vector search over issue titles and descriptions.",
+			Flag: "--robot-search", Description: "BM25 and semantic search over issue text and comments, with field filters and match snippets.",
 			Params:      []string{"--search <query>", "--search-limit <n>", "--search-mode text|hybrid"},
 			NeedsIssues: true,
 		},
@@ -7737,6 +7646,36 @@ func generateRobotDocs(topic string) map[string]interface{} {
 			Flag: "--robot-blocker-chain <id>", Description: "Full blocker chain analysis for an issue.",
 			NeedsIssues: true,
 		},
+		"robot-claim": {
+			Flag: "--robot-claim <id>", Description: "Claim an issue: set in_progress and assignee, written back to beads data.",
+			KeyFields:   []string{"changed", "before", "after", "written"},
+			Params:      []string{"--assignee <name>", "--force", "--add-label <l>", "--set-priority 0-4"},
+			NeedsIssues: true,
+		},
+		"robot-close": {
+			Flag: "--robot-close <id>", Description: "Close an issue and record closed_at.",
+			KeyFields:   []string{"changed", "before", "after", "written"},
+			Params:      []string{"--reason <text>"},
+			NeedsIssues: true,
+		},
+		"robot-link": {
+			Flag: "--robot-link <id>", Description: "Add or remove dependencies; blocking cycles are rejected.",
+			KeyFields:   []string{"changed", "before", "after", "written"},
+			Params:      []string{"--depends-on <id,...>", "--unlink <id,...>", "--dep-type blocks|related|parent-child|discovered-from"},
+			NeedsIssues: true,
+		},
+		"robot-update": {
+			Flag: "--robot-update <id>", Description: "Update status, priority, assignee or labels of an issue.",
+			KeyFields:   []string{"changed", "before", "after", "written"},
+			Params:      []string{"--set-status <status>", "--set-priority 0-4", "--assignee <name|->", "--add-label <l>", "--remove-label <l>"},
+			NeedsIssues: true,
+		},
+		"robot-doctor": {
+			Flag: "--robot-doctor", Description: "Integrity report with line numbers, severities and a fix plan; --doctor-fix applies it.",
+			KeyFields:   []string{"findings", "fix_plan", "summary"},
+			Params:      []string{"--doctor-fix"},
+			NeedsIssues: false,
+		},
 		"robot-impact-network": {
 			Flag: "--robot-impact-network [<id>|all]", Description: "Impact network graph (full or subnetwork for a bead).",
 			Params:      []string{"--network-depth 1-3"},
@@ -7756,7 +7695,18 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		},
 		"robot-forecast": {
 			Flag: "--robot-forecast <id|all>", Description: "ETA predictions for bead completion.",
-			Params:      []string{"--forecast-label <label>", "--forecast-sprint <id>", "--forecast-agents <n>"},
+			Params:      []string{"--forecast-label <label>", "--forecast-sprint <id>", "--forecast-epic <id>", "--forecast-agents <n>", "--forecast-trials <n>", "--forecast-seed <n>"},
+			NeedsIssues: true,
+		},
+		"robot-whatif": {
+			Flag: "--robot-whatif <scenarios.yaml|edits>", Description: "Before/after comparison of hypothetical edits (close, defer, add/remove dependency, add agents).",
+			Params:      []string{"--forecast-agents <n>", "--forecast-trials <n>", "--forecast-seed <n>", "--profile <name>"},
+			NeedsIssues: true,
+		},
+		"robot-train-ranking": {
+			Flag: "--robot-train-ranking", Description: "Learn scoring weights from which issues git history shows were claimed next; export them as a scoring profile.",
+			KeyFields:   []string{"baseline", "trained", "evaluated_on", "weights", "profile", "events[].claims"},
+			Params:      []string{"--train-revisions <n>", "--train-name <name>", "--train-export <file>"},
 			NeedsIssues: true,
 		},
 		"robot-capacity": {
@@ -7764,6 +7714,11 @@ func generateRobotDocs(topic string) map[string]interface{} {
 			Params:      []string{"--agents <n>", "--capacity-label <label>"},
 			NeedsIssues: true,
 		},
+		"robot-schedule": {
+			Flag: "--robot-schedule", Description: "Per-person assignment plan over the team roster's capacity and calendars.",
+			Params:      []string{"--team <path>", "--agents <n>"},
+			NeedsIssues: true,
+		},
 		"robot-burndown": {
 			Flag: "--robot-burndown <sprint|current>", Description: "Sprint burndown data.",
 			NeedsIssues: true,
@@ -7798,12 +7753,13 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		"BV_PRETTY_JSON":      "Set to 1 for indented JSON output",
 		"BV_ROBOT":            "Set to 1 to force robot mode (clean stdout)",
 		"BV_SEARCH_MODE":      "Search mode: text or hybrid",
+		"BV_ACTOR":            "Default assignee/actor for --robot-claim and dependency created_by (falls back to $USER)",
 		"BV_SEARCH_PRESET":    "Hybrid search preset name",
 	}
 
 	exitCodes := map[string]string{
 		"0": "Success",
-		"1": "Error (general failure, drift critical)",
+		"1": "Error (general failure, drift critical, doctor errors remaining)",
 		"2": "Invalid arguments or drift warning",
 	}
 
@@ -7838,6 +7794,7 @@ type RobotSchemas struct {
 	GeneratedAt   string                            `json:"generated_at"`
 	Envelope      map[string]interface{}            `json:"envelope"`
 	Commands      map[string]map[string]interface{} `json:"commands"`
+	Inputs        map[string]map[string]interface{} `json:"inputs,omitempty"` // Argument schemas (used by bv mcp tools)
 }
 
 // generateRobotSchemas creates JSON Schema definitions for robot command outputs
@@ -7889,6 +7846,7 @@ func generateRobotSchemas() RobotSchemas {
 								"generated_at": map[string]interface{}{"type": "string"},
 								"phase2_ready": map[string]interface{}{"type": "boolean"},
 								"issue_count":  map[string]interface{}{"type": "integer"},
+								"profile":      map[string]interface{}{"type": "string", "description": "Scoring profile used for the scores"},
 							},
 						},
 						"quick_ref": map[string]interface{}{
@@ -8014,6 +7972,7 @@ func generateRobotSchemas() RobotSchemas {
 				"generated_at":    map[string]interface{}{"type": "string", "format": "date-time"},
 				"data_hash":       map[string]interface{}{"type": "string"},
 				"recommendations": map[string]interface{}{"type": "array"},
+				"profile":         map[string]interface{}{"type": "string", "description": "Scoring profile used for impact scores"},
 				"status":          map[string]interface{}{"type": "object"},
 				"usage_hints":     map[string]interface{}{"type": "array"},
 			},
@@ -8095,9 +8054,53 @@ func generateRobotSchemas() RobotSchemas {
 				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
 				"data_hash":    map[string]interface{}{"type": "string"},
 				"forecasts":    map[string]interface{}{"type": "array"},
+				"monte_carlo":  map[string]interface{}{"type": "object"},
 				"methodology":  map[string]interface{}{"type": "object"},
 			},
 		},
+		"robot-whatif": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot What-If Output",
+			"description": "Baseline plan and forecast compared with each hypothetical scenario",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"source":       map[string]interface{}{"type": "string"},
+				"baseline":     map[string]interface{}{"type": "object"},
+				"scenarios":    map[string]interface{}{"type": "array"},
+			},
+		},
+		"robot-train-ranking": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Train Ranking Output",
+			"description": "Scoring weights learned from historical claims, evaluated against the defaults",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"revisions":    map[string]interface{}{"type": "integer"},
+				"evaluated_on": map[string]interface{}{"type": "string", "enum": []string{"holdout", "training"}},
+				"baseline":     map[string]interface{}{"type": "object", "description": "top1, top3, mrr and pairwise_accuracy of the default weights"},
+				"trained":      map[string]interface{}{"type": "object", "description": "The same metrics for the learned weights"},
+				"weights":      map[string]interface{}{"type": "object"},
+				"profile":      map[string]interface{}{"type": "object"},
+				"exported_to":  map[string]interface{}{"type": "string"},
+				"events":       map[string]interface{}{"type": "array"},
+			},
+		},
+		"robot-schedule": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Schedule Output",
+			"description": "Roster-constrained assignment plan with utilization and due-date slips",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"roster":       map[string]interface{}{"type": "string"},
+				"schedule":     map[string]interface{}{"type": "object"},
+			},
+		},
 	}
 
 	return RobotSchemas{
@@ -8105,5 +8108,90 @@ func generateRobotSchemas() RobotSchemas {
 		GeneratedAt:   now,
 		Envelope:      envelope,
 		Commands:      commands,
+		Inputs:        generateRobotInputSchemas(),
+	}
+}
+
+// generateRobotInputSchemas describes the arguments accepted by robot commands
+// as JSON Schema objects. Property names mirror the CLI flags without their
+// command prefix (e.g. --related-min-relevance becomes min_relevance).
+func generateRobotInputSchemas() map[string]map[string]interface{} {
+	object := func(props map[string]interface{}, required ...string) map[string]interface{} {
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	issueID := map[string]interface{}{"type": "string", "description": "Issue ID (e.g. bv-123)"}
+	profile := map[string]interface{}{"type": "string", "description": "Scoring profile: a built-in (default, unblock, bug-burndown) or one from .bv/scoring.yaml (--profile)"}
+
+	return map[string]map[string]interface{}{
+		"robot-triage": object(map[string]interface{}{
+			"group_by": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"track", "label"},
+				"description": "Group recommendations by execution track or label (--robot-triage-by-track/--robot-triage-by-label)",
+			},
+			"profile": profile,
+		}),
+		"robot-next":     object(map[string]interface{}{"profile": profile}),
+		"robot-plan":     object(map[string]interface{}{}),
+		"robot-insights": object(map[string]interface{}{}),
+		"robot-blocker-chain": object(map[string]interface{}{
+			"id": issueID,
+		}, "id"),
+		"robot-impact": object(map[string]interface{}{
+			"files": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"minItems":    1,
+				"description": "Repository-relative file paths to analyze",
+			},
+			"history_limit": map[string]interface{}{"type": "integer", "minimum": 0, "default": 500, "description": "Max commits to analyze (0 = unlimited)"},
+		}, "files"),
+		"robot-search": object(map[string]interface{}{
+			"query":   map[string]interface{}{"type": "string", "description": "Search query"},
+			"limit":   map[string]interface{}{"type": "integer", "minimum": 1, "default": 10},
+			"mode":    map[string]interface{}{"type": "string", "enum": []string{"text", "hybrid"}},
+			"preset":  map[string]interface{}{"type": "string", "enum": []string{"default", "bug-hunting", "sprint-planning", "impact-first", "text-only"}},
+			"weights": map[string]interface{}{"type": "string", "description": "Hybrid weights as JSON (overrides preset)"},
+		}, "query"),
+		"robot-related": object(map[string]interface{}{
+			"id":             issueID,
+			"min_relevance":  map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 100, "default": 20},
+			"max_results":    map[string]interface{}{"type": "integer", "minimum": 1, "default": 10},
+			"include_closed": map[string]interface{}{"type": "boolean", "default": false},
+			"history_limit":  map[string]interface{}{"type": "integer", "minimum": 0, "default": 500, "description": "Max commits to analyze (0 = unlimited)"},
+		}, "id"),
+		"robot-forecast": object(map[string]interface{}{
+			"target": map[string]interface{}{"type": "string", "default": "all", "description": "Issue ID or \"all\""},
+			"label":  map[string]interface{}{"type": "string"},
+			"sprint": map[string]interface{}{"type": "string"},
+			"epic":   map[string]interface{}{"type": "string", "description": "Restrict to the epic's parent-child descendants"},
+			"agents": map[string]interface{}{"type": "integer", "minimum": 1, "default": 1},
+			"trials": map[string]interface{}{"type": "integer", "minimum": 0, "default": 1000, "description": "Monte Carlo trials (0 = off)"},
+			"seed":   map[string]interface{}{"type": "integer", "default": 1},
+		}),
+		"robot-whatif": object(map[string]interface{}{
+			"scenarios": map[string]interface{}{"type": "string", "description": "Scenario YAML path, or inline edits: close:ID, defer:ID, add-dep:A>B, remove-dep:A>B, add-agents:N"},
+			"agents":    map[string]interface{}{"type": "integer", "minimum": 1, "default": 1},
+			"trials":    map[string]interface{}{"type": "integer", "minimum": 0, "default": 1000, "description": "Monte Carlo trials (0 = off)"},
+			"seed":      map[string]interface{}{"type": "integer", "default": 1},
+			"profile":   profile,
+		}, "scenarios"),
+		"robot-schedule": object(map[string]interface{}{
+			"team":   map[string]interface{}{"type": "string", "description": "Roster YAML path (default .bv/team.yaml)"},
+			"agents": map[string]interface{}{"type": "integer", "minimum": 1, "default": 1, "description": "Generalists to assume without a roster"},
+		}),
+		"robot-train-ranking": object(map[string]interface{}{
+			"revisions": map[string]interface{}{"type": "integer", "minimum": 0, "default": 200, "description": "Beads-file revisions to replay (0 = all)"},
+			"name":      map[string]interface{}{"type": "string", "default": "learned"},
+			"export":    map[string]interface{}{"type": "string", "description": "Scoring profile file to write the learned profile into"},
+		}),
 	}
 }
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mcp"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/serve"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
+)
+
+// mcpToolNames lists the robot commands exposed as MCP tools, in the order
+// they are documented.
+var mcpToolNames = []string{
+	"robot-triage",
+	"robot-next",
+	"robot-plan",
+	"robot-blocker-chain",
+	"robot-impact",
+	"robot-search",
+	"robot-related",
+}
+
+// mcpToolDescriptions covers tools whose robot command has no output schema
+// (and therefore no description) in --robot-schema.
+var mcpToolDescriptions = map[string]string{
+	"robot-blocker-chain": "Full blocker chain for an issue: root blockers, chain depth and cycle detection",
+	"robot-impact":        "Beads touched by commits to the given files, with risk level",
+	"robot-search":        "Semantic/hybrid search over issues",
+	"robot-related":       "Beads related to an issue via shared files, commits and dependencies",
+}
+
+// runMCPCommand implements `bv mcp`: a Model Context Protocol server on
+// stdin/stdout. stdout carries protocol messages only; logs go to stderr.
+func runMCPCommand(args []string) int {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "Path to beads database file or .beads directory")
+	format := fs.String("format", "", "Tool result format: json|toon (env: BV_OUTPUT_FORMAT)")
+	noWatch := fs.Bool("no-watch", false, "Disable automatic reload when beads data changes")
+	profileName := fs.String("profile", "", "Scoring profile for triage (default: .bv/scoring.yaml's default)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv mcp [options]")
+		fmt.Fprintln(os.Stderr, "\nServe robot commands as MCP tools over stdio.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if *dbPath != "" {
+		absDB, err := filepath.Abs(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving --db path: %v\n", err)
+			return 1
+		}
+		os.Setenv(loader.BeadsDBEnvVar, absDB)
+	}
+
+	robotOutputFormat = resolveRobotOutputFormat(*format)
+	robotToonEncodeOptions = resolveToonEncodeOptionsFromEnv()
+	if robotOutputFormat != "json" && robotOutputFormat != "toon" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (expected json|toon)\n", robotOutputFormat)
+		return 2
+	}
+
+	// stdout is the protocol channel; keep parsers and loaders quiet on it.
+	_ = os.Setenv("BV_ROBOT", "1")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+		return 1
+	}
+	profile, err := resolveScoringProfile(cwd, *profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	store, err := serve.NewStore(func() ([]model.Issue, error) {
+		return datasource.LoadIssues("")
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	store.OnReload(func(snap *serve.Snapshot, err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bv mcp: reload failed: %v\n", err)
+		}
+	})
+	if !*noWatch {
+		if sources, err := serveWatchSources(); err == nil {
+			if err := store.Watch(sources, datasource.DefaultWatcherOptions()); err != nil {
+				fmt.Fprintf(os.Stderr, "bv mcp: auto-reload disabled: %v\n", err)
+			}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	svc := newRobotService(cwd)
+	svc.profile = profile
+	srv := newMCPServer(store, svc)
+	if err := srv.Serve(ctx, os.Stdin, os.Stdout); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newMCPServer registers the robot tools against store. Input schemas are
+// the same ones published by --robot-schema, so agents see one contract.
+func newMCPServer(store *serve.Store, svc *robotService) *mcp.Server {
+	srv := mcp.NewServer(mcp.ServerOptions{
+		Name:    "bv",
+		Version: version.Version,
+		Instructions: "Graph-aware triage for beads issues. Start with robot-triage or robot-next; " +
+			"results reflect the on-disk beads data and refresh automatically when it changes.",
+		Encode: func(v any) (string, error) {
+			var buf bytes.Buffer
+			if err := newRobotEncoderFor(&buf, robotOutputFormat).Encode(v); err != nil {
+				return "", err
+			}
+			return strings.TrimRight(buf.String(), "\n"), nil
+		},
+	})
+
+	schemas := generateRobotSchemas()
+	h := &mcpHandlers{store: store, svc: svc}
+	handlers := map[string]mcp.ToolHandler{
+		"robot-triage":        h.triage,
+		"robot-next":          h.next,
+		"robot-plan":          h.plan,
+		"robot-blocker-chain": h.blockerChain,
+		"robot-impact":        h.impact,
+		"robot-search":        h.search,
+		"robot-related":       h.related,
+	}
+	for _, name := range mcpToolNames {
+		desc := mcpToolDescriptions[name]
+		if d, ok := schemas.Commands[name]["description"].(string); ok && d != "" {
+			desc = d
+		}
+		srv.AddTool(mcp.Tool{
+			Name:        name,
+			Description: desc,
+			InputSchema: schemas.Inputs[name],
+		}, handlers[name])
+	}
+	return srv
+}
+
+// mcpHandlers adapts robotService to MCP tool calls. Each call runs against
+// the store's current snapshot.
+type mcpHandlers struct {
+	store *serve.Store
+	svc   *robotService
+}
+
+func decodeToolArgs(args json.RawMessage, v any) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return json.Unmarshal(args, v)
+}
+
+func (h *mcpHandlers) triage(_ context.Context, args json.RawMessage) (any, error) {
+	var in struct {
+		GroupBy string `json:"group_by"`
+		Profile string `json:"profile"`
+	}
+	if err := decodeToolArgs(args, &in); err != nil {
+		return nil, err
+	}
+	profile, err := h.svc.scoringProfile(in.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return h.svc.triageOutput(h.store.Current(), in.GroupBy, profile)
+}
+
+func (h *mcpHandlers) next(_ context.Context, args json.RawMessage) (any, error) {
+	var in struct {
+		Profile string `json:"profile"`
+	}
+	if err := decodeToolArgs(args, &in); err != nil {
+		return nil, err
+	}
+	profile, err := h.svc.scoringProfile(in.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return h.svc.nextOutput(h.store.Current(), profile)
+}
+
+func (h *mcpHandlers) plan(context.Context, json.RawMessage) (any, error) {
+	return h.svc.planOutput(h.store.Current())
+}
+
+func (h *mcpHandlers) blockerChain(_ context.Context, args json.RawMessage) (any, error) {
+	var in struct {
+		ID string `json:"id"`
+	}
+	if err := decodeToolArgs(args, &in); err != nil {
+		return nil, err
+	}
+	out, ok := h.svc.blockerChainOutput(h.store.Current(), in.ID)
+	if !ok {
+		return nil, fmt.Errorf("issue not found: %s", in.ID)
+	}
+	return out, nil
+}
+
+func (h *mcpHandlers) impact(_ context.Context, args json.RawMessage) (any, error) {
+	in := struct {
+		Files        []string `json:"files"`
+		HistoryLimit int      `json:"history_limit"`
+	}{HistoryLimit: 500}
+	if err := decodeToolArgs(args, &in); err != nil {
+		return nil, err
+	}
+	report, err := h.svc.correlationReport(h.store.Current(), in.HistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	return buildRobotImpactOutput(report, in.Files), nil
+}
+
+func (h *mcpHandlers) search(ctx context.Context, args json.RawMessage) (any, error) {
+	in := struct {
+		Query   string `json:"query"`
+		Limit   int    `json:"limit"`
+		Mode    string `json:"mode"`
+		Preset  string `json:"preset"`
+		Weights string `json:"weights"`
+	}{Limit: 10}
+	if err := decodeToolArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Query) == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+	cfg, err := searchConfig(in.Mode, in.Preset, in.Weights)
+	if err != nil {
+		return nil, err
+	}
+	return h.svc.searchOutput(ctx, h.store.Current(), semanticSearchRequest{
+		Query:  strings.TrimSpace(in.Query),
+		Limit:  in.Limit,
+		Config: cfg,
+	})
+}
+
+func (h *mcpHandlers) related(_ context.Context, args json.RawMessage) (any, error) {
+	in := struct {
+		ID            string `json:"id"`
+		MinRelevance  int    `json:"min_relevance"`
+		MaxResults    int    `json:"max_results"`
+		IncludeClosed bool   `json:"include_closed"`
+		HistoryLimit  int    `json:"history_limit"`
+	}{MinRelevance: 20, MaxResults: 10, HistoryLimit: 500}
+	if err := decodeToolArgs(args, &in); err != nil {
+		return nil, err
+	}
+	snap := h.store.Current()
+	report, err := h.svc.correlationReport(snap, in.HistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := buildRobotRelatedOutput(report, snap.Issues, robotRelatedRequest{
+		BeadID:        in.ID,
+		MinRelevance:  in.MinRelevance,
+		MaxResults:    in.MaxResults,
+		IncludeClosed: in.IncludeClosed,
+	})
+	if !ok {
+		return nil, fmt.Errorf("issue not found: %s", in.ID)
+	}
+	return out, nil
+}
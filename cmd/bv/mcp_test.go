@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mcp"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/serve"
+)
+
+func newTestMCPClient(t *testing.T, issues []model.Issue) *mcp.Client {
+	t.Helper()
+	repoDir := t.TempDir()
+	t.Setenv("BEADS_DIR", repoDir)
+
+	store, err := serve.NewStore(func() ([]model.Issue, error) { return issues, nil })
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := newMCPServer(store, newRobotService(repoDir))
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = srv.Serve(ctx, inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("mcp server did not stop")
+		}
+	})
+
+	client := mcp.NewClient(outR, inW)
+	if _, err := client.Initialize(context.Background(), mcp.Implementation{Name: "bv-test", Version: "0"}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	return client
+}
+
+func callMCPTool(t *testing.T, client *mcp.Client, name string, args any) map[string]any {
+	t.Helper()
+	res, err := client.CallTool(context.Background(), name, args)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if res.IsError {
+		t.Fatalf("%s returned tool error: %+v", name, res.Content)
+	}
+	var out map[string]any
+	if err := json.Unmarshal([]byte(res.Content[0].Text), &out); err != nil {
+		t.Fatalf("%s: invalid JSON result: %v\n%s", name, err, res.Content[0].Text)
+	}
+	return out
+}
+
+func TestMCP_ListToolsUsesRobotSchemas(t *testing.T) {
+	client := newTestMCPClient(t, []model.Issue{{ID: "A", Title: "Root", Status: model.StatusOpen, IssueType: model.TypeTask}})
+
+	tools, err := client.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(tools) != len(mcpToolNames) {
+		t.Fatalf("expected %d tools, got %d", len(mcpToolNames), len(tools))
+	}
+	inputs := generateRobotSchemas().Inputs
+	for _, tool := range tools {
+		if tool.Description == "" {
+			t.Errorf("%s: missing description", tool.Name)
+		}
+		want, ok := inputs[tool.Name]
+		if !ok {
+			t.Errorf("%s: no input schema in --robot-schema", tool.Name)
+			continue
+		}
+		gotJSON, _ := json.Marshal(tool.InputSchema)
+		wantJSON, _ := json.Marshal(want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("%s: input schema mismatch\n got %s\nwant %s", tool.Name, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestMCP_CallRobotTools(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "Root", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "B", Title: "Blocked", Status: model.StatusBlocked, Priority: 2, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+	}
+	client := newTestMCPClient(t, issues)
+	ctx := context.Background()
+
+	next := callMCPTool(t, client, "robot-next", nil)
+	if next["id"] != "A" {
+		t.Errorf("unexpected robot-next payload: %v", next)
+	}
+
+	triage := callMCPTool(t, client, "robot-triage", map[string]any{"group_by": "track"})
+	if _, ok := triage["triage"].(map[string]any); !ok || triage["data_hash"] != next["data_hash"] {
+		t.Errorf("unexpected robot-triage payload: %v", triage)
+	}
+
+	triage = callMCPTool(t, client, "robot-triage", map[string]any{"profile": "bug-burndown"})
+	if meta, _ := triage["triage"].(map[string]any)["meta"].(map[string]any); meta["profile"] != "bug-burndown" {
+		t.Errorf("robot-triage ignored the profile argument: %v", meta)
+	}
+	if res, err := client.CallTool(ctx, "robot-next", map[string]any{"profile": "bogus"}); err != nil || !res.IsError {
+		t.Errorf("expected tool error for an unknown profile, got %+v, %v", res, err)
+	}
+
+	plan := callMCPTool(t, client, "robot-plan", map[string]any{})
+	if _, ok := plan["plan"].(map[string]any); !ok {
+		t.Errorf("robot-plan missing plan: %v", plan)
+	}
+
+	chain := callMCPTool(t, client, "robot-blocker-chain", map[string]any{"id": "B"})
+	if chain["data_hash"] != next["data_hash"] {
+		t.Errorf("unexpected robot-blocker-chain payload: %v", chain)
+	}
+	res, err := client.CallTool(ctx, "robot-blocker-chain", map[string]any{"id": "NOPE"})
+	if err != nil || !res.IsError {
+		t.Errorf("expected tool error for unknown issue, got %+v, %v", res, err)
+	}
+
+	found := callMCPTool(t, client, "robot-search", map[string]any{"query": "Root", "limit": 1})
+	if results, _ := found["results"].([]any); len(results) != 1 {
+		t.Errorf("expected 1 search result, got %v", found)
+	}
+
+	// Not a git repository: correlation-backed tools fail as tool errors.
+	for _, name := range []string{"robot-impact", "robot-related"} {
+		args := map[string]any{"id": "A"}
+		if name == "robot-impact" {
+			args = map[string]any{"files": []string{"main.go"}}
+		}
+		res, err := client.CallTool(ctx, name, args)
+		if err != nil || !res.IsError {
+			t.Errorf("%s: expected tool error outside git repo, got %+v, %v", name, res, err)
+		}
+	}
+}
+
+func TestMCP_InvalidArgumentsRejected(t *testing.T) {
+	client := newTestMCPClient(t, []model.Issue{{ID: "A", Title: "Root", Status: model.StatusOpen, IssueType: model.TypeTask}})
+
+	cases := map[string]struct {
+		tool string
+		args any
+	}{
+		"missing id":      {"robot-blocker-chain", map[string]any{}},
+		"bad group":       {"robot-triage", map[string]any{"group_by": "owner"}},
+		"empty files":     {"robot-impact", map[string]any{"files": []string{}}},
+		"unknown arg":     {"robot-next", map[string]any{"limit": 3}},
+		"relevance range": {"robot-related", map[string]any{"id": "A", "min_relevance": 101}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.CallTool(context.Background(), tc.tool, tc.args)
+			var rpcErr *mcp.RPCError
+			if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.CodeInvalidParams {
+				t.Errorf("expected invalid params error, got %v", err)
+			}
+		})
+	}
+}
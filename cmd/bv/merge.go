@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+)
+
+// robotMergeOutput is the `bv merge --json` payload.
+type robotMergeOutput struct {
+	RobotEnvelope
+	*datasource.MergeResult
+	Path      string `json:"path"`
+	Base      string `json:"base,omitempty"`
+	Ours      string `json:"ours"`
+	Theirs    string `json:"theirs"`
+	Written   bool   `json:"written"`
+	StateFile string `json:"state_file,omitempty"`
+}
+
+// runMergeCommand implements `bv merge <rev>` and the git merge driver
+// `bv merge --driver %O %A %B [%P]`. It exits 1 while conflicts remain.
+func runMergeCommand(args []string) int {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "Path to beads database file or .beads directory")
+	driver := fs.Bool("driver", false, "Run as a git merge driver: bv merge --driver %O %A %B [%P]")
+	dryRun := fs.Bool("dry-run", false, "Report the merge without writing beads.jsonl")
+	jsonOut := fs.Bool("json", false, "Print the merge result as JSON")
+	format := fs.String("format", "", "Structured output format with --json: json|toon (env: BV_OUTPUT_FORMAT)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv merge [options] <rev>")
+		fmt.Fprintln(os.Stderr, "       bv merge --driver %O %A %B [%P]")
+		fmt.Fprintln(os.Stderr, "\nThree-way merge the beads issues of <rev> into the working beads.jsonl.")
+		fmt.Fprintln(os.Stderr, "Unresolved conflicts keep our value and can be resolved in the TUI (press X).")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if *dbPath != "" {
+		absDB, err := filepath.Abs(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving --db path: %v\n", err)
+			return 1
+		}
+		os.Setenv(loader.BeadsDBEnvVar, absDB)
+	}
+	robotOutputFormat = resolveRobotOutputFormat(*format)
+	robotToonEncodeOptions = resolveToonEncodeOptionsFromEnv()
+	if robotOutputFormat != "json" && robotOutputFormat != "toon" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (expected json|toon)\n", robotOutputFormat)
+		return 2
+	}
+
+	var output robotMergeOutput
+	var err error
+	if *driver {
+		if fs.NArg() < 3 || fs.NArg() > 4 {
+			fmt.Fprintln(os.Stderr, "Usage: bv merge --driver %O %A %B [%P]")
+			return 2
+		}
+		output, err = runMergeDriver(fs.Arg(0), fs.Arg(1), fs.Arg(2), fs.Arg(3))
+	} else {
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return 2
+		}
+		output, err = runMergeRevision(fs.Arg(0), *dryRun)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if *driver {
+			// git treats any failure as a conflict and keeps its own markers.
+			return 2
+		}
+		return 1
+	}
+
+	switch {
+	case *jsonOut:
+		if err := newRobotEncoder(os.Stdout).Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding merge result: %v\n", err)
+			return 1
+		}
+	case *driver:
+		// git shows driver output inline with its own; only speak up when
+		// something needs attention.
+		if n := output.Unresolved(); n > 0 {
+			fmt.Fprintf(os.Stderr, "bv merge: %d conflict(s) in %s kept our side; run bv and press X to resolve\n", n, output.Path)
+		}
+	default:
+		printMergeResult(os.Stdout, output)
+	}
+	if output.Unresolved() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runMergeRevision merges the issues at rev into the working beads.jsonl,
+// using the merge-base of HEAD and rev as the common ancestor.
+func runMergeRevision(rev string, dryRun bool) (robotMergeOutput, error) {
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+	path, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+
+	git := loader.NewGitLoader(cwd)
+	baseSHA, err := git.MergeBase("HEAD", rev)
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+	base, err := git.LoadAt(baseSHA)
+	if err != nil {
+		// Only a merge-base without beads files means they were added on both
+		// branches since; merging against an empty base otherwise would make
+		// every issue look added on both sides.
+		if present, presentErr := git.HasBeadsFileAt(baseSHA); presentErr != nil || present {
+			return robotMergeOutput{}, fmt.Errorf("loading merge-base %s: %w", baseSHA, err)
+		}
+		base = nil
+	}
+	theirs, err := git.LoadAt(rev)
+	if err != nil {
+		return robotMergeOutput{}, fmt.Errorf("loading %s: %w", rev, err)
+	}
+
+	output := robotMergeOutput{Path: path, Base: baseSHA, Ours: "working tree", Theirs: rev, Written: !dryRun}
+	if output.MergeResult, err = datasource.MergeIntoJSONL(path, base, theirs, !dryRun); err != nil {
+		return robotMergeOutput{}, err
+	}
+	if !dryRun {
+		if err := recordMergeState(beadsDir, &output); err != nil {
+			return robotMergeOutput{}, err
+		}
+	}
+	output.RobotEnvelope = NewRobotEnvelope(analysis.ComputeDataHash(output.Issues))
+	return output, nil
+}
+
+// runMergeDriver merges the three files git hands a merge driver. The result
+// is written to oursPath; repoPath (%P) names the file in the working tree
+// so conflicts can be resolved there after git finishes.
+func runMergeDriver(basePath, oursPath, theirsPath, repoPath string) (robotMergeOutput, error) {
+	result, err := datasource.MergeJSONLFiles(basePath, oursPath, theirsPath)
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+	output := robotMergeOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(result.Issues)),
+		MergeResult:   result,
+		Path:          oursPath,
+		Ours:          "ours",
+		Theirs:        "theirs",
+		Written:       true,
+	}
+	if result.Unresolved() == 0 {
+		return output, nil
+	}
+
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+	if repoPath != "" {
+		output.Path, err = filepath.Abs(repoPath)
+	} else {
+		output.Path, err = loader.FindJSONLPath(beadsDir)
+	}
+	if err != nil {
+		return robotMergeOutput{}, err
+	}
+	return output, recordMergeState(beadsDir, &output)
+}
+
+// recordMergeState saves unresolved conflicts for the TUI, or clears a stale
+// state file when the merge came out clean.
+func recordMergeState(beadsDir string, output *robotMergeOutput) error {
+	if output.Unresolved() == 0 {
+		if err := os.Remove(datasource.MergeStatePath(beadsDir)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	state := &datasource.MergeState{
+		Path:      output.Path,
+		Ours:      output.Ours,
+		Theirs:    output.Theirs,
+		CreatedAt: time.Now().UTC(),
+		Result:    output.MergeResult,
+	}
+	if err := datasource.SaveMergeState(beadsDir, state); err != nil {
+		return fmt.Errorf("saving merge state: %w", err)
+	}
+	output.StateFile = datasource.MergeStatePath(beadsDir)
+	return nil
+}
+
+// printMergeResult writes the human-readable form of a merge.
+func printMergeResult(w io.Writer, out robotMergeOutput) {
+	s := out.Stats
+	verb := "Merged"
+	if !out.Written {
+		verb = "Would merge"
+	}
+	fmt.Fprintf(w, "%s %s into %s: %d unchanged, %d merged, %d added, %d deleted, %d conflicted\n",
+		verb, out.Theirs, out.Path, s.Unchanged, s.Merged, s.Added, s.Deleted, s.Conflicted)
+	if len(out.Conflicts) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	for _, c := range out.Conflicts {
+		fmt.Fprintf(w, "  %s %s: base=%v ours=%v theirs=%v\n", c.IssueID, c.Field, c.Base, c.Ours, c.Theirs)
+	}
+	if out.StateFile != "" {
+		fmt.Fprintf(w, "\nOur side was kept for %d conflict(s). Run bv and press X to resolve them.\n", out.Unresolved())
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+)
+
+func TestMerge_GitDriverAndRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	exe := buildTestBinary(t)
+	dir := t.TempDir()
+	jsonlPath := filepath.Join(dir, ".beads", "beads.jsonl")
+	if err := os.MkdirAll(filepath.Dir(jsonlPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		out, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return string(out)
+	}
+	commit := func(msg string, lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(jsonlPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", msg)
+	}
+
+	git("init", "-q", "-b", "main")
+	git("config", "merge.beads.driver", exe+" merge --driver %O %A %B %P")
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(".beads/beads.jsonl merge=beads\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	commit("base",
+		`{"id":"A","title":"Alpha","status":"open","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"Beta","status":"open","priority":2,"issue_type":"task"}`,
+	)
+	git("checkout", "-q", "-b", "feature")
+	commit("feature",
+		`{"id":"A","title":"Alpha","status":"in_progress","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"Beta","status":"open","priority":3,"issue_type":"task"}`,
+	)
+	git("checkout", "-q", "main")
+	commit("main",
+		`{"id":"A","title":"Alpha (renamed)","status":"open","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"Beta","status":"open","priority":0,"issue_type":"task"}`,
+		`{"id":"C","title":"Gamma","status":"open","priority":1,"issue_type":"bug"}`,
+	)
+
+	run := func(args ...string) ([]byte, int) {
+		cmd := exec.Command(exe, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return out, exitErr.ExitCode()
+		}
+		if err != nil {
+			t.Fatalf("run %v: %v", args, err)
+		}
+		return out, 0
+	}
+
+	// Dry run against the branch reports the priority conflict without writing.
+	out, code := run("merge", "--dry-run", "--json", "feature")
+	if code != 1 {
+		t.Fatalf("dry run should exit 1 on conflicts, got %d\n%s", code, out)
+	}
+	var dry robotMergeOutput
+	if err := json.Unmarshal(out, &dry); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if dry.Written || len(dry.Conflicts) != 1 || dry.Conflicts[0].Field != "priority" || dry.Stats.Merged != 1 || dry.Base == "" {
+		t.Errorf("unexpected dry run: %s", out)
+	}
+
+	// A real git merge runs the driver, which merges A cleanly and leaves B's
+	// priority conflicted with our value.
+	git("merge", "-q", "--no-edit", "feature")
+	data, err := os.ReadFile(jsonlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || strings.Contains(string(data), "<<<<<<<") {
+		t.Fatalf("driver output:\n%s", data)
+	}
+	if !strings.Contains(lines[0], `"title":"Alpha (renamed)"`) || !strings.Contains(lines[0], `"status":"in_progress"`) {
+		t.Errorf("A not merged: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"priority":0`) {
+		t.Errorf("conflict should keep ours: %s", lines[1])
+	}
+
+	beadsDir := filepath.Dir(jsonlPath)
+	state, err := datasource.LoadMergeState(beadsDir)
+	if err != nil || state == nil {
+		t.Fatalf("driver should leave merge state: %v", err)
+	}
+	if state.Result.Unresolved() != 1 || state.Path != jsonlPath {
+		t.Errorf("unexpected state: path=%s conflicts=%+v", state.Path, state.Result.Conflicts)
+	}
+	if err := state.Result.Resolve("B", "priority", datasource.MergeTheirs); err != nil {
+		t.Fatal(err)
+	}
+	if open, err := datasource.ApplyMergeState(beadsDir, state); err != nil || open != 0 {
+		t.Fatalf("apply: open=%d err=%v", open, err)
+	}
+	if data, _ := os.ReadFile(jsonlPath); !strings.Contains(string(data), `{"id":"B","title":"Beta","status":"open","priority":3,"issue_type":"task"}`) {
+		t.Errorf("resolution not written:\n%s", data)
+	}
+}
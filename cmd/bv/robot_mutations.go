@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// robotMutationRequest collects the write-back flags (--robot-claim,
+// --robot-close, --robot-link, --robot-update) into a single request.
+type robotMutationRequest struct {
+	Action  string // claim | close | link | update
+	IssueID string
+
+	Assignee     string
+	Force        bool
+	Reason       string
+	Status       string
+	Priority     int // <0 leaves priority unchanged
+	AddLabels    []string
+	RemoveLabels []string
+	DependsOn    []string
+	Unlink       []string
+	DepType      string
+}
+
+// mutation translates the request into a datasource.Mutation, rejecting
+// flag combinations that make no sense for the action.
+func (req robotMutationRequest) mutation() (datasource.Mutation, error) {
+	var m datasource.Mutation
+	switch req.Action {
+	case "claim":
+		assignee := req.Assignee
+		if assignee == "" {
+			assignee = datasource.DefaultActor()
+		}
+		m = datasource.ClaimMutation(req.IssueID, assignee, req.Force)
+	case "close":
+		m = datasource.CloseMutation(req.IssueID, req.Reason)
+	case "link":
+		if len(req.DependsOn) == 0 && len(req.Unlink) == 0 {
+			return m, fmt.Errorf("--robot-link requires --depends-on or --unlink")
+		}
+		m = datasource.Mutation{IssueID: req.IssueID}
+	case "update":
+		m = datasource.Mutation{IssueID: req.IssueID}
+		if req.Status != "" {
+			status := model.Status(strings.ToLower(req.Status))
+			if !status.IsValid() {
+				return m, fmt.Errorf("invalid --set-status %q", req.Status)
+			}
+			m.Status = &status
+			if status.IsClosed() {
+				m.CloseReason = req.Reason
+			}
+		}
+		if req.Assignee != "" {
+			assignee := req.Assignee
+			if assignee == "-" {
+				assignee = "" // explicit unassign
+			}
+			m.Assignee = &assignee
+		}
+	default:
+		return m, fmt.Errorf("unknown mutation action %q", req.Action)
+	}
+
+	if req.Priority >= 0 {
+		if req.Priority > 4 {
+			return m, fmt.Errorf("invalid --set-priority %d (expected 0-4)", req.Priority)
+		}
+		p := req.Priority
+		m.Priority = &p
+	}
+	m.AddLabels = req.AddLabels
+	m.RemoveLabels = req.RemoveLabels
+
+	depType := model.DependencyType(strings.ToLower(req.DepType))
+	if depType == "" {
+		depType = model.DepBlocks
+	}
+	if !depType.IsValid() {
+		return m, fmt.Errorf("invalid --dep-type %q (expected blocks|related|parent-child|discovered-from)", req.DepType)
+	}
+	for _, id := range req.DependsOn {
+		m.AddDependencies = append(m.AddDependencies, model.Dependency{
+			DependsOnID: id,
+			Type:        depType,
+			CreatedBy:   datasource.DefaultActor(),
+		})
+	}
+	m.RemoveDependencies = req.Unlink
+	return m, nil
+}
+
+// robotIssueState is the mutable slice of an issue reported before and after
+// a write.
+type robotIssueState struct {
+	Status    model.Status `json:"status"`
+	Assignee  string       `json:"assignee,omitempty"`
+	Priority  int          `json:"priority"`
+	Labels    []string     `json:"labels,omitempty"`
+	DependsOn []string     `json:"depends_on,omitempty"`
+	ClosedAt  *time.Time   `json:"closed_at,omitempty"`
+}
+
+func newRobotIssueState(issue model.Issue) robotIssueState {
+	state := robotIssueState{
+		Status:   issue.Status,
+		Assignee: issue.Assignee,
+		Priority: issue.Priority,
+		Labels:   issue.Labels,
+		ClosedAt: issue.ClosedAt,
+	}
+	for _, dep := range issue.Dependencies {
+		if dep != nil {
+			state.DependsOn = append(state.DependsOn, dep.DependsOnID)
+		}
+	}
+	return state
+}
+
+// robotMutationOutput is the --robot-claim/--robot-close/--robot-link/
+// --robot-update payload.
+type robotMutationOutput struct {
+	RobotEnvelope
+	Action  string          `json:"action"`
+	IssueID string          `json:"issue_id"`
+	Changed []string        `json:"changed"`
+	Before  robotIssueState `json:"before"`
+	After   robotIssueState `json:"after"`
+	Source  string          `json:"source"`
+	Written []string        `json:"written"`
+}
+
+// runRobotMutation applies req to the beads data in beadsDir. dataHash in the
+// returned envelope reflects the data after the write.
+func runRobotMutation(beadsDir string, req robotMutationRequest) (robotMutationOutput, error) {
+	m, err := req.mutation()
+	if err != nil {
+		return robotMutationOutput{}, err
+	}
+	res, err := datasource.ApplyMutation(beadsDir, m, datasource.MutationOptions{})
+	if err != nil {
+		return robotMutationOutput{}, err
+	}
+
+	dataHash := ""
+	if issues, err := datasource.LoadIssuesFromDir(beadsDir); err == nil {
+		dataHash = analysis.ComputeDataHash(issues)
+	}
+	changed := res.Changed
+	if changed == nil {
+		changed = []string{}
+	}
+	written := res.Written
+	if written == nil {
+		written = []string{}
+	}
+	return robotMutationOutput{
+		RobotEnvelope: NewRobotEnvelope(dataHash),
+		Action:        req.Action,
+		IssueID:       req.IssueID,
+		Changed:       changed,
+		Before:        newRobotIssueState(res.Before),
+		After:         newRobotIssueState(res.After),
+		Source:        string(res.Source.Type),
+		Written:       written,
+	}, nil
+}
+
+// splitFlagList splits comma-separated flag values, dropping empties.
+func splitFlagList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
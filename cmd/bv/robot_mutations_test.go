@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRobotMutationRequest_Validation(t *testing.T) {
+	cases := map[string]robotMutationRequest{
+		"link without targets": {Action: "link", IssueID: "A", Priority: -1},
+		"bad status":           {Action: "update", IssueID: "A", Status: "done", Priority: -1},
+		"bad priority":         {Action: "update", IssueID: "A", Priority: 7},
+		"bad dep type":         {Action: "link", IssueID: "A", DependsOn: []string{"B"}, DepType: "needs", Priority: -1},
+		"unknown action":       {Action: "delete", IssueID: "A", Priority: -1},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := req.mutation(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	m, err := robotMutationRequest{Action: "update", IssueID: "A", Assignee: "-", Priority: -1}.mutation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Assignee == nil || *m.Assignee != "" || m.Priority != nil {
+		t.Errorf("'-' should unassign and leave priority alone: %+v", m)
+	}
+}
+
+func TestSplitFlagList(t *testing.T) {
+	got := splitFlagList([]string{"a, b", "", "c,,"})
+	if strings.Join(got, "|") != "a|b|c" {
+		t.Errorf("splitFlagList = %v", got)
+	}
+}
+
+func TestRobotMutations_EndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task"}
+{"id":"B","title":"Second","status":"open","priority":1,"issue_type":"task"}
+`
+	jsonlPath := filepath.Join(beadsDir, "beads.jsonl")
+	if err := os.WriteFile(jsonlPath, []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	run := func(args ...string) (robotMutationOutput, error) {
+		cmd := exec.Command(exe, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "BV_ACTOR=agent-1")
+		out, err := cmd.Output()
+		var res robotMutationOutput
+		if err == nil {
+			if jerr := json.Unmarshal(out, &res); jerr != nil {
+				t.Fatalf("decode %v: %v\n%s", args, jerr, out)
+			}
+		}
+		return res, err
+	}
+
+	res, err := run("--robot-claim", "A")
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if res.After.Status != "in_progress" || res.After.Assignee != "agent-1" || res.DataHash == "" {
+		t.Errorf("unexpected claim output: %+v", res)
+	}
+
+	if _, err := run("--robot-claim", "A", "--assignee", "agent-2"); err == nil {
+		t.Error("claim by second agent should fail without --force")
+	}
+
+	res, err = run("--robot-link", "B", "--depends-on", "A")
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if len(res.After.DependsOn) != 1 || res.After.DependsOn[0] != "A" {
+		t.Errorf("unexpected link output: %+v", res.After)
+	}
+	// The reverse edge would create a cycle.
+	if _, err := run("--robot-link", "A", "--depends-on", "B"); err == nil {
+		t.Error("cyclic link should fail")
+	}
+
+	res, err = run("--robot-close", "A", "--reason", "shipped")
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if res.After.Status != "closed" || res.After.ClosedAt == nil {
+		t.Errorf("unexpected close output: %+v", res.After)
+	}
+
+	if _, err := run("--robot-update", "B", "--set-priority", "9"); err == nil {
+		t.Error("invalid priority should fail")
+	} else if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+		t.Errorf("invalid arguments should exit 2, got %v", err)
+	}
+
+	data, err := os.ReadFile(jsonlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"close_reason":"shipped"`) || !strings.Contains(lines[1], `"depends_on_id":"A"`) {
+		t.Errorf("unexpected beads.jsonl after mutations:\n%s", data)
+	}
+}
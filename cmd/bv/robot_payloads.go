@@ -0,0 +1,986 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
+)
+
+// Robot payload builders shared by the one-shot --robot-* flags and the
+// long-running `bv serve` / `bv mcp` modes. Keeping the output structs here
+// guarantees every transport emits byte-for-byte identical JSON shapes.
+
+// robotScope carries the provenance fields echoed by analysis payloads.
+type robotScope struct {
+	DataHash     string
+	AsOf         string
+	AsOfCommit   string
+	LabelScope   string
+	LabelContext *analysis.LabelHealth
+}
+
+// robotTriageOutput is the --robot-triage payload.
+type robotTriageOutput struct {
+	GeneratedAt string                 `json:"generated_at"`
+	DataHash    string                 `json:"data_hash"`
+	AsOf        string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
+	AsOfCommit  string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	Triage      analysis.TriageResult  `json:"triage"`
+	Feedback    *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
+	UsageHints  []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
+}
+
+func buildRobotTriageOutput(scope robotScope, triage analysis.TriageResult, feedback *analysis.FeedbackJSON) robotTriageOutput {
+	return robotTriageOutput{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		DataHash:    scope.DataHash,
+		AsOf:        scope.AsOf,
+		AsOfCommit:  scope.AsOfCommit,
+		Triage:      triage,
+		Feedback:    feedback,
+		UsageHints: []string{
+			"jq '.triage.quick_ref.top_picks[:3]' - Top 3 picks for immediate work",
+			"jq '.triage.recommendations[3:10] | map({id,title,score})' - Next candidates after top picks",
+			"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
+			"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
+			"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
+			"jq '.triage.quick_wins' - Low-effort, high-impact items",
+			"--robot-next - Get only the single top recommendation",
+			"--robot-triage-by-track - Group by execution track for multi-agent coordination",
+			"--robot-triage-by-label - Group by label for area-focused agents",
+			"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
+			"jq '.triage.recommendations_by_label[].claim_command' - Claim commands per label",
+			"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
+		},
+	}
+}
+
+// robotNextEmptyOutput is the --robot-next payload when nothing is actionable.
+type robotNextEmptyOutput struct {
+	RobotEnvelope
+	AsOf       string `json:"as_of,omitempty"`
+	AsOfCommit string `json:"as_of_commit,omitempty"`
+	Message    string `json:"message"`
+}
+
+// robotNextOutput is the --robot-next payload for the single top pick.
+type robotNextOutput struct {
+	RobotEnvelope
+	AsOf       string   `json:"as_of,omitempty"`
+	AsOfCommit string   `json:"as_of_commit,omitempty"`
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Score      float64  `json:"score"`
+	Reasons    []string `json:"reasons"`
+	Unblocks   int      `json:"unblocks"`
+	ClaimCmd   string   `json:"claim_command"`
+	ShowCmd    string   `json:"show_command"`
+}
+
+// buildRobotNextOutput returns either a robotNextOutput or, when triage has no
+// top picks, a robotNextEmptyOutput.
+func buildRobotNextOutput(scope robotScope, triage analysis.TriageResult) any {
+	envelope := NewRobotEnvelope(scope.DataHash)
+	if len(triage.QuickRef.TopPicks) == 0 {
+		return robotNextEmptyOutput{
+			RobotEnvelope: envelope,
+			AsOf:          scope.AsOf,
+			AsOfCommit:    scope.AsOfCommit,
+			Message:       "No actionable items available",
+		}
+	}
+
+	top := triage.QuickRef.TopPicks[0]
+	return robotNextOutput{
+		RobotEnvelope: envelope,
+		AsOf:          scope.AsOf,
+		AsOfCommit:    scope.AsOfCommit,
+		ID:            top.ID,
+		Title:         top.Title,
+		Score:         top.Score,
+		Reasons:       top.Reasons,
+		Unblocks:      top.Unblocks,
+		ClaimCmd:      fmt.Sprintf("br update %s --status=in_progress", top.ID),
+		ShowCmd:       fmt.Sprintf("br show %s", top.ID),
+	}
+}
+
+// loadTriageHistory loads a best-effort git history report for staleness
+// analysis. Any failure yields nil; staleness is optional for triage.
+func loadTriageHistory(repoDir string, issues []model.Issue, historyLimit int) *correlation.HistoryReport {
+	// bv-perf: Skip history loading if no open issues exist
+	// ComputeStaleness only processes open issues, so loading git history
+	// is wasted work when all issues are closed.
+	hasOpenIssues := false
+	for _, issue := range issues {
+		if issue.Status != model.StatusClosed && issue.Status != model.StatusTombstone {
+			hasOpenIssues = true
+			break
+		}
+	}
+	if !hasOpenIssues || repoDir == "" {
+		return nil
+	}
+
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return nil
+	}
+	beadsPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return nil
+	}
+
+	// Use a smaller limit for triage to keep it fast, unless overridden
+	limit := historyLimit
+	if limit == 500 { // If default
+		limit = 200 // Use smaller default for triage
+	}
+
+	// Validate repo first
+	if correlation.ValidateRepository(repoDir) != nil {
+		return nil
+	}
+
+	correlator := correlation.NewCorrelator(repoDir, beadsPath)
+	opts := correlation.CorrelatorOptions{Limit: limit}
+
+	// Swallow errors for triage flow - staleness is optional
+	report, err := correlator.GenerateReport(beadInfosFromIssues(issues), opts)
+	if err != nil {
+		return nil
+	}
+	return report
+}
+
+// loadTriageFeedback returns the feedback loop state for triage output, or nil
+// when no feedback has been recorded.
+func loadTriageFeedback() *analysis.FeedbackJSON {
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return nil
+	}
+	feedbackData, err := analysis.LoadFeedback(beadsDir)
+	if err != nil || len(feedbackData.Events) == 0 {
+		return nil
+	}
+	info := feedbackData.ToJSON()
+	return &info
+}
+
+// beadInfosFromIssues converts issues to the minimal form the correlator needs.
+func beadInfosFromIssues(issues []model.Issue) []correlation.BeadInfo {
+	beadInfos := make([]correlation.BeadInfo, len(issues))
+	for i, issue := range issues {
+		beadInfos[i] = correlation.BeadInfo{
+			ID:     issue.ID,
+			Title:  issue.Title,
+			Status: string(issue.Status),
+		}
+	}
+	return beadInfos
+}
+
+// robotPlanAnalysisConfig returns the analysis config used for --robot-plan.
+func robotPlanAnalysisConfig(issues []model.Issue, full bool) analysis.AnalysisConfig {
+	// For --robot-plan we primarily need Phase 1 metrics (degree/topo/density).
+	// However, we still emit a stable status contract for agents. If the user
+	// explicitly asks for full analysis, honor it; otherwise, skip expensive
+	// centrality metrics and record the skip reasons deterministically.
+	if full {
+		return analysis.FullAnalysisConfig()
+	}
+	cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
+	const skipReason = "not computed for --robot-plan"
+	cfg.ComputePageRank = false
+	cfg.PageRankSkipReason = skipReason
+	cfg.ComputeBetweenness = false
+	cfg.BetweennessMode = analysis.BetweennessSkip
+	cfg.BetweennessSkipReason = skipReason
+	cfg.ComputeHITS = false
+	cfg.HITSSkipReason = skipReason
+	cfg.ComputeEigenvector = false
+	cfg.ComputeCriticalPath = false
+	cfg.ComputeCycles = false
+	cfg.CyclesSkipReason = skipReason
+	return cfg
+}
+
+// robotPlanOutput is the --robot-plan payload.
+type robotPlanOutput struct {
+	GeneratedAt    string                  `json:"generated_at"`
+	DataHash       string                  `json:"data_hash"`
+	AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+	Status         analysis.MetricStatus   `json:"status"`
+	LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	Plan           analysis.ExecutionPlan  `json:"plan"`
+	UsageHints     []string                `json:"usage_hints"` // bv-84: Agent-friendly hints
+}
+
+func buildRobotPlanOutput(scope robotScope, cfg analysis.AnalysisConfig, status analysis.MetricStatus, plan analysis.ExecutionPlan) robotPlanOutput {
+	return robotPlanOutput{
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		DataHash:       scope.DataHash,
+		AsOf:           scope.AsOf,
+		AsOfCommit:     scope.AsOfCommit,
+		AnalysisConfig: cfg,
+		Status:         status,
+		LabelScope:     scope.LabelScope,
+		LabelContext:   scope.LabelContext,
+		Plan:           plan,
+		UsageHints: []string{
+			"jq '.plan.tracks | length' - Number of parallel execution tracks",
+			"jq '.plan.tracks[0].items | map(.id)' - First track item IDs",
+			"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
+			"jq '.plan.summary' - High-level execution summary",
+			"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
+		},
+	}
+}
+
+// robotInsightsFullStats holds the (capped) raw metric maps in --robot-insights.
+type robotInsightsFullStats struct {
+	PageRank          map[string]float64 `json:"pagerank"`
+	Betweenness       map[string]float64 `json:"betweenness"`
+	Eigenvector       map[string]float64 `json:"eigenvector"`
+	Hubs              map[string]float64 `json:"hubs"`
+	Authorities       map[string]float64 `json:"authorities"`
+	CriticalPathScore map[string]float64 `json:"critical_path_score"`
+	CoreNumber        map[string]int     `json:"core_number"`
+	Slack             map[string]float64 `json:"slack"`
+	Articulation      []string           `json:"articulation_points"`
+}
+
+// robotInsightsOutput is the --robot-insights payload.
+type robotInsightsOutput struct {
+	GeneratedAt    string                  `json:"generated_at"`
+	DataHash       string                  `json:"data_hash"`
+	AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+	Status         analysis.MetricStatus   `json:"status"`
+	LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	analysis.Insights
+	FullStats        interface{}                `json:"full_stats"`
+	TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
+	AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
+	UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
+}
+
+// buildRobotInsightsOutput assembles --robot-insights from a completed analysis.
+// stats must have finished Phase 2.
+func buildRobotInsightsOutput(scope robotScope, issues []model.Issue, analyzer *analysis.Analyzer, stats *analysis.GraphStats) robotInsightsOutput {
+	// Generate top 50 lists for summary, but full stats are included in the struct
+	insights := stats.GenerateInsights(50)
+
+	// Add project-level velocity snapshot (using dedicated helper for efficiency)
+	if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
+		snap := &analysis.VelocitySnapshot{
+			Closed7:   v.ClosedLast7Days,
+			Closed30:  v.ClosedLast30Days,
+			AvgDays:   v.AvgDaysToClose,
+			Estimated: v.Estimated,
+		}
+		if len(v.Weekly) > 0 {
+			snap.Weekly = make([]int, len(v.Weekly))
+			for i := range v.Weekly {
+				snap.Weekly[i] = v.Weekly[i].Closed
+			}
+		}
+		insights.Velocity = snap
+	}
+
+	// Default cap to keep payload small; allow override via env
+	mapLimit := 200
+	if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			mapLimit = n
+		}
+	}
+
+	fullStats := robotInsightsFullStats{
+		PageRank:          limitMetricMap(stats.PageRank(), mapLimit),
+		Betweenness:       limitMetricMap(stats.Betweenness(), mapLimit),
+		Eigenvector:       limitMetricMap(stats.Eigenvector(), mapLimit),
+		Hubs:              limitMetricMap(stats.Hubs(), mapLimit),
+		Authorities:       limitMetricMap(stats.Authorities(), mapLimit),
+		CriticalPathScore: limitMetricMap(stats.CriticalPathScore(), mapLimit),
+		CoreNumber:        limitIntMetricMap(stats.CoreNumber(), mapLimit),
+		Slack:             limitMetricMap(stats.Slack(), mapLimit),
+		Articulation:      limitStringSlice(stats.ArticulationPoints(), mapLimit),
+	}
+
+	return robotInsightsOutput{
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		DataHash:       scope.DataHash,
+		AsOf:           scope.AsOf,
+		AsOfCommit:     scope.AsOfCommit,
+		AnalysisConfig: stats.Config,
+		Status:         stats.Status(),
+		LabelScope:     scope.LabelScope,
+		LabelContext:   scope.LabelContext,
+		Insights:       insights,
+		FullStats:      fullStats,
+		// Get top what-if deltas for issues with highest downstream impact (bv-83)
+		TopWhatIfs: analyzer.TopWhatIfDeltas(10),
+		// Generate advanced insights with canonical structure (bv-181)
+		AdvancedInsights: analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig()),
+		UsageHints: []string{
+			"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
+			"jq '.CriticalPath[:3]' - Top 3 critical path items",
+			"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
+			"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
+			"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
+			"jq '.full_stats.articulation_points' - Structural cut points",
+			"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
+			"jq '.Cycles | length' - Count of detected cycles",
+			"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
+			"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
+		},
+	}
+}
+
+// limitMetricMap keeps the top-N entries of a metric map (ties broken by ID).
+func limitMetricMap(m map[string]float64, limit int) map[string]float64 {
+	if limit <= 0 || limit >= len(m) {
+		return m
+	}
+	type kv struct {
+		k string
+		v float64
+	}
+	var items []kv
+	for k, v := range m {
+		items = append(items, kv{k, v})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].v == items[j].v {
+			return items[i].k < items[j].k
+		}
+		return items[i].v > items[j].v
+	})
+	trim := make(map[string]float64, limit)
+	for i := 0; i < limit; i++ {
+		trim[items[i].k] = items[i].v
+	}
+	return trim
+}
+
+func limitIntMetricMap(m map[string]int, limit int) map[string]int {
+	if limit <= 0 || len(m) <= limit {
+		return m
+	}
+	trim := make(map[string]int, limit)
+	count := 0
+	for k, v := range m {
+		trim[k] = v
+		count++
+		if count >= limit {
+			break
+		}
+	}
+	return trim
+}
+
+func limitStringSlice(s []string, limit int) []string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	return s[:limit]
+}
+
+// robotForecastSummary aggregates multi-issue forecasts.
+type robotForecastSummary struct {
+	TotalMinutes  int       `json:"total_minutes"`
+	TotalDays     float64   `json:"total_days"`
+	AvgConfidence float64   `json:"avg_confidence"`
+	EarliestETA   time.Time `json:"earliest_eta"`
+	LatestETA     time.Time `json:"latest_eta"`
+}
+
+// robotForecastOutput is the --robot-forecast payload.
+type robotForecastOutput struct {
+	RobotEnvelope
+	Agents        int                    `json:"agents"`
+	Filters       map[string]string      `json:"filters,omitempty"`
+	ForecastCount int                    `json:"forecast_count"`
+	Forecasts     []analysis.ETAEstimate `json:"forecasts"`
+	Summary       *robotForecastSummary  `json:"summary,omitempty"`
+	// Completion-date distribution for everything forecast, from resampled
+	// history; omitted when Trials is 0
+	MonteCarlo *analysis.MonteCarloForecast `json:"monte_carlo,omitempty"`
+}
+
+// robotForecastRequest describes a --robot-forecast invocation.
+type robotForecastRequest struct {
+	Target        string // Issue ID or "all"
+	Label         string
+	Sprint        string
+	SprintBeadIDs map[string]bool // Resolved members of Sprint (see sprintBeadIDSet)
+	Epic          string          // Restrict to the epic's parent-child descendants
+	Agents        int
+	Trials        int                                // Monte Carlo trials (0 = deterministic ETAs only)
+	Seed          int64                              // Monte Carlo RNG seed
+	History       map[string][]analysis.StatusChange // Optional, for cycle-time sampling
+}
+
+// sprintBeadIDSet returns the bead IDs belonging to sprintID, or nil when the
+// sprint does not exist.
+func sprintBeadIDSet(sprints []model.Sprint, sprintID string) map[string]bool {
+	for _, s := range sprints {
+		if s.ID == sprintID {
+			ids := make(map[string]bool, len(s.BeadIDs))
+			for _, bid := range s.BeadIDs {
+				ids[bid] = true
+			}
+			return ids
+		}
+	}
+	return nil
+}
+
+// buildRobotForecastOutput computes ETA forecasts for req.Target, or for every
+// open issue matching the label/sprint filters when the target is "all".
+func buildRobotForecastOutput(issues []model.Issue, graphStats *analysis.GraphStats, req robotForecastRequest, now time.Time) (robotForecastOutput, error) {
+	// Filter issues by label and sprint if specified
+	targetIssues := make([]model.Issue, 0, len(issues))
+	sprintBeadIDs := req.SprintBeadIDs
+	var epicIDs map[string]bool
+	if req.Epic != "" {
+		found := false
+		for _, iss := range issues {
+			if iss.ID == req.Epic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return robotForecastOutput{}, fmt.Errorf("epic %q not found", req.Epic)
+		}
+		epicIDs = make(map[string]bool)
+		for _, id := range analysis.EpicDescendants(issues, req.Epic) {
+			epicIDs[id] = true
+		}
+	}
+	for _, iss := range issues {
+		// Filter by label
+		if req.Label != "" {
+			hasLabel := false
+			for _, l := range iss.Labels {
+				if l == req.Label {
+					hasLabel = true
+					break
+				}
+			}
+			if !hasLabel {
+				continue
+			}
+		}
+		// Filter by sprint
+		if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
+			continue
+		}
+		if epicIDs != nil && !epicIDs[iss.ID] {
+			continue
+		}
+		targetIssues = append(targetIssues, iss)
+	}
+
+	agents := req.Agents
+	if agents <= 0 {
+		agents = 1
+	}
+
+	var forecasts []analysis.ETAEstimate
+	if req.Target == "all" {
+		// Forecast all open issues
+		for _, iss := range targetIssues {
+			if iss.Status == model.StatusClosed {
+				continue
+			}
+			eta, err := analysis.EstimateETAForIssue(issues, graphStats, iss.ID, agents, now)
+			if err != nil {
+				continue
+			}
+			forecasts = append(forecasts, eta)
+		}
+	} else {
+		// Single issue forecast
+		eta, err := analysis.EstimateETAForIssue(issues, graphStats, req.Target, agents, now)
+		if err != nil {
+			return robotForecastOutput{}, err
+		}
+		forecasts = append(forecasts, eta)
+	}
+
+	// Build summary if multiple forecasts
+	var summary *robotForecastSummary
+	if len(forecasts) > 1 {
+		totalMin := 0
+		totalConf := 0.0
+		earliest := forecasts[0].ETADate
+		latest := forecasts[0].ETADate
+		for _, f := range forecasts {
+			totalMin += f.EstimatedMinutes
+			totalConf += f.Confidence
+			if f.ETADate.Before(earliest) {
+				earliest = f.ETADate
+			}
+			if f.ETADate.After(latest) {
+				latest = f.ETADate
+			}
+		}
+		summary = &robotForecastSummary{
+			TotalMinutes:  totalMin,
+			TotalDays:     float64(totalMin) / (60.0 * 8.0), // 8hr workday
+			AvgConfidence: totalConf / float64(len(forecasts)),
+			EarliestETA:   earliest,
+			LatestETA:     latest,
+		}
+	}
+
+	filters := make(map[string]string)
+	if req.Label != "" {
+		filters["label"] = req.Label
+	}
+	if req.Sprint != "" {
+		filters["sprint"] = req.Sprint
+	}
+	if req.Epic != "" {
+		filters["epic"] = req.Epic
+	}
+
+	output := robotForecastOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Agents:        agents,
+		ForecastCount: len(forecasts),
+		Forecasts:     forecasts,
+		Summary:       summary,
+	}
+	if len(filters) > 0 {
+		output.Filters = filters
+	}
+	if req.Trials > 0 {
+		ids := make([]string, 0, len(forecasts))
+		for _, f := range forecasts {
+			ids = append(ids, f.IssueID)
+		}
+		mc := analysis.SimulateDelivery(issues, ids, analysis.MonteCarloOptions{
+			Trials:  req.Trials,
+			Agents:  agents,
+			Seed:    req.Seed,
+			Now:     now,
+			History: req.History,
+		})
+		mc.Scope, mc.Target = forecastScope(req)
+		output.MonteCarlo = &mc
+	}
+	return output, nil
+}
+
+// forecastScope names what a forecast request covers, most specific first.
+func forecastScope(req robotForecastRequest) (string, string) {
+	switch {
+	case req.Target != "all":
+		return "issue", req.Target
+	case req.Epic != "":
+		return "epic", req.Epic
+	case req.Sprint != "":
+		return "sprint", req.Sprint
+	case req.Label != "":
+		return "label", req.Label
+	}
+	return "all", ""
+}
+
+// robotScheduleOutput is the --robot-schedule payload.
+type robotScheduleOutput struct {
+	RobotEnvelope
+	Roster   string            `json:"roster"` // Roster file path, or "default" for --agents generalists
+	Schedule analysis.Schedule `json:"schedule"`
+}
+
+// resolveTeamRoster loads the roster at path, or .bv/team.yaml under
+// projectDir when path is empty. Without a roster file, agents interchangeable
+// generalists stand in; an explicit path that doesn't exist is an error.
+func resolveTeamRoster(projectDir, path string, agents int) (*analysis.TeamRoster, string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = analysis.TeamRosterPath(projectDir)
+	}
+	roster, err := analysis.LoadTeamRoster(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if roster == nil {
+		if explicit {
+			return nil, "", fmt.Errorf("team roster not found: %s", path)
+		}
+		return analysis.DefaultTeamRoster(agents), "default", nil
+	}
+	return roster, path, nil
+}
+
+// resolveScoringProfile loads the project's scoring profiles and returns the
+// named one, or the default when name is empty.
+func resolveScoringProfile(projectDir, name string) (*analysis.ScoringProfile, error) {
+	profiles, err := analysis.LoadScoringProfiles(analysis.ScoringProfilesPath(projectDir))
+	if err != nil {
+		return nil, err
+	}
+	return profiles.Get(name)
+}
+
+func buildRobotScheduleOutput(issues []model.Issue, roster *analysis.TeamRoster, source string, now time.Time) robotScheduleOutput {
+	return robotScheduleOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Roster:        source,
+		Schedule:      analysis.ScheduleWork(issues, roster, analysis.ScheduleOptions{Now: now}),
+	}
+}
+
+// robotWhatIfOutput is the --robot-whatif payload.
+type robotWhatIfOutput struct {
+	RobotEnvelope
+	Source string `json:"source"` // Scenario file path, or "inline"
+	*analysis.WhatIfReport
+}
+
+// robotWhatIfRequest describes a --robot-whatif invocation.
+type robotWhatIfRequest struct {
+	Scenarios []analysis.Scenario
+	Source    string
+	Agents    int
+	Trials    int
+	Seed      int64
+	History   map[string][]analysis.StatusChange
+	Profile   *analysis.ScoringProfile
+}
+
+// resolveWhatIfScenarios reads spec as a scenario file when one exists at
+// that path, and as inline edits otherwise. agents is the file's baseline
+// agent count, or 0 when it doesn't set one.
+func resolveWhatIfScenarios(spec string) (scenarios []analysis.Scenario, agents int, source string, err error) {
+	if info, statErr := os.Stat(spec); statErr == nil && !info.IsDir() {
+		file, err := analysis.LoadScenarioFile(spec)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		return file.Scenarios, file.Agents, spec, nil
+	}
+	edits, err := analysis.ParseScenarioEdits(spec)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return []analysis.Scenario{{Name: "inline", Edits: edits}}, 0, "inline", nil
+}
+
+func buildRobotWhatIfOutput(issues []model.Issue, req robotWhatIfRequest, now time.Time) (robotWhatIfOutput, error) {
+	report, err := analysis.RunScenarios(issues, req.Scenarios, analysis.WhatIfOptions{
+		Agents:  req.Agents,
+		Trials:  req.Trials,
+		Seed:    req.Seed,
+		Now:     now,
+		History: req.History,
+		Profile: req.Profile,
+	})
+	if err != nil {
+		return robotWhatIfOutput{}, err
+	}
+	return robotWhatIfOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Source:        req.Source,
+		WhatIfReport:  report,
+	}, nil
+}
+
+// robotTrainRankingOutput is the --robot-train-ranking payload.
+type robotTrainRankingOutput struct {
+	RobotEnvelope
+	Revisions  int    `json:"revisions"`             // Beads-file revisions replayed
+	Skipped    int    `json:"skipped,omitempty"`     // Revisions that could not be loaded
+	ExportedTo string `json:"exported_to,omitempty"` // Profile file written with --train-export
+	*analysis.RankTrainingReport
+}
+
+// robotTrainRankingRequest describes a --robot-train-ranking invocation.
+type robotTrainRankingRequest struct {
+	Revisions int // 0 replays every revision
+	Name      string
+	Export    string
+}
+
+// loadRankingSnapshots replays up to limit beads-file revisions from git,
+// oldest first, and ends with the working tree's issues so claims that are
+// not committed yet still count. skipped counts revisions that failed to load.
+func loadRankingSnapshots(repoDir string, limit int, current []model.Issue, now time.Time) (snapshots []analysis.RankingSnapshot, skipped int, err error) {
+	gitLoader := loader.NewGitLoader(repoDir)
+	revisions, err := gitLoader.ListRevisions(limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		issues, err := gitLoader.LoadAt(revisions[i].SHA)
+		if err != nil {
+			skipped++
+			continue
+		}
+		snapshots = append(snapshots, analysis.RankingSnapshot{Revision: revisions[i].SHA, At: revisions[i].Timestamp, Issues: issues})
+	}
+	snapshots = append(snapshots, analysis.RankingSnapshot{Revision: "working-tree", At: now, Issues: current})
+	return snapshots, skipped, nil
+}
+
+func buildRobotTrainRankingOutput(repoDir string, issues []model.Issue, req robotTrainRankingRequest, now time.Time) (robotTrainRankingOutput, error) {
+	snapshots, skipped, err := loadRankingSnapshots(repoDir, req.Revisions, issues, now)
+	if err != nil {
+		return robotTrainRankingOutput{}, err
+	}
+	report, err := analysis.TrainRanking(snapshots, analysis.RankTrainingOptions{Name: req.Name})
+	if err != nil {
+		return robotTrainRankingOutput{}, err
+	}
+	out := robotTrainRankingOutput{
+		RobotEnvelope:      NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Revisions:          len(snapshots) - 1,
+		Skipped:            skipped,
+		RankTrainingReport: report,
+	}
+	if req.Export != "" {
+		if err := analysis.SaveScoringProfile(req.Export, report.Profile); err != nil {
+			return robotTrainRankingOutput{}, err
+		}
+		out.ExportedTo = req.Export
+	}
+	return out, nil
+}
+
+// robotHistoryRequest describes a --robot-history invocation.
+type robotHistoryRequest struct {
+	BeadID        string
+	Since         *time.Time
+	Limit         int
+	MinConfidence float64
+}
+
+// buildRobotHistoryReport generates the --robot-history bead-to-commit report.
+func buildRobotHistoryReport(repoDir, beadsPath string, issues []model.Issue, req robotHistoryRequest) (*correlation.HistoryReport, error) {
+	opts := correlation.CorrelatorOptions{
+		BeadID: req.BeadID,
+		Since:  req.Since,
+		Limit:  req.Limit,
+	}
+
+	correlator := correlation.NewCorrelator(repoDir, beadsPath)
+	report, err := correlator.GenerateReport(beadInfosFromIssues(issues), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply confidence filter if specified
+	if req.MinConfidence > 0 {
+		scorer := correlation.NewScorer()
+		report.Histories = scorer.FilterHistoriesByConfidence(report.Histories, req.MinConfidence)
+
+		// Rebuild commit index after filtering
+		report.CommitIndex = make(correlation.CommitIndex)
+		for beadID, history := range report.Histories {
+			for _, commit := range history.Commits {
+				report.CommitIndex[commit.SHA] = append(report.CommitIndex[commit.SHA], beadID)
+			}
+		}
+
+		// Update stats
+		report.Stats.BeadsWithCommits = 0
+		for _, history := range report.Histories {
+			if len(history.Commits) > 0 {
+				report.Stats.BeadsWithCommits++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// robotTimelineOutput is the --robot-timeline payload.
+type robotTimelineOutput struct {
+	RobotEnvelope
+	Head string `json:"head"`
+	File string `json:"file"`
+	correlation.IssueTimeline
+}
+
+// buildRobotTimelineOutput reconstructs the field-level history of issueID
+// from git. It returns false when the issue is neither loaded nor present
+// anywhere in history.
+func buildRobotTimelineOutput(repoDir, beadsPath string, issues []model.Issue, issueID string) (robotTimelineOutput, bool, error) {
+	tl, err := correlation.NewTimelineBuilder(repoDir, beadsPath).Build()
+	if err != nil {
+		return robotTimelineOutput{}, false, err
+	}
+	issueTimeline := tl.ForIssue(issueID)
+	if len(issueTimeline.Events) == 0 {
+		found := false
+		for _, issue := range issues {
+			if issue.ID == issueID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return robotTimelineOutput{}, false, nil
+		}
+	}
+	return robotTimelineOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Head:          tl.Head,
+		File:          tl.File,
+		IssueTimeline: issueTimeline,
+	}, true, nil
+}
+
+// robotFlowMetricsOutput is the --robot-flow-metrics payload.
+type robotFlowMetricsOutput struct {
+	RobotEnvelope
+	analysis.FlowReport
+}
+
+// loadStatusHistory reconstructs per-issue status transitions from git. It
+// returns nil outside a git repository or when the timeline cannot be built,
+// in which case flow metrics fall back to created/closed timestamps.
+func loadStatusHistory(repoDir, beadsPath string) map[string][]analysis.StatusChange {
+	if err := correlation.ValidateRepository(repoDir); err != nil {
+		return nil
+	}
+	tl, err := correlation.NewTimelineBuilder(repoDir, beadsPath).Build()
+	if err != nil {
+		return nil
+	}
+	return analysis.StatusHistoryFromTimeline(tl)
+}
+
+// buildRobotFlowMetricsOutput computes lead time, cycle time, time in status
+// and throughput for the --robot-flow-metrics payload.
+func buildRobotFlowMetricsOutput(issues []model.Issue, history map[string][]analysis.StatusChange, cfg analysis.FlowConfig, now time.Time) robotFlowMetricsOutput {
+	return robotFlowMetricsOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		FlowReport:    analysis.ComputeFlowMetrics(issues, history, cfg, now),
+	}
+}
+
+// generateCorrelationReport builds the bead-to-commit history report used by
+// --robot-impact and --robot-related.
+func generateCorrelationReport(repoDir, beadsPath string, issues []model.Issue, limit int) (*correlation.HistoryReport, error) {
+	correlator := correlation.NewCorrelator(repoDir, beadsPath)
+	return correlator.GenerateReport(beadInfosFromIssues(issues), correlation.CorrelatorOptions{
+		Limit: limit,
+	})
+}
+
+// robotImpactOutput is the --robot-impact payload.
+type robotImpactOutput struct {
+	RobotEnvelope
+	Files         []string                   `json:"files"`
+	RiskLevel     string                     `json:"risk_level"`
+	RiskScore     float64                    `json:"risk_score"`
+	Summary       string                     `json:"summary"`
+	Warnings      []string                   `json:"warnings"`
+	AffectedBeads []correlation.AffectedBead `json:"affected_beads"`
+}
+
+func buildRobotImpactOutput(report *correlation.HistoryReport, files []string) robotImpactOutput {
+	cleaned := make([]string, len(files))
+	for i := range files {
+		cleaned[i] = strings.TrimSpace(files[i])
+	}
+	impactResult := correlation.NewFileLookup(report).ImpactAnalysis(cleaned)
+
+	return robotImpactOutput{
+		RobotEnvelope: NewRobotEnvelope(report.DataHash),
+		Files:         impactResult.Files,
+		RiskLevel:     impactResult.RiskLevel,
+		RiskScore:     impactResult.RiskScore,
+		Summary:       impactResult.Summary,
+		Warnings:      impactResult.Warnings,
+		AffectedBeads: impactResult.AffectedBeads,
+	}
+}
+
+// robotRelatedWorkOutput is the --robot-related payload.
+type robotRelatedWorkOutput struct {
+	*correlation.RelatedWorkResult
+	DataHash     string `json:"data_hash"`
+	OutputFormat string `json:"output_format,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// robotRelatedRequest describes a --robot-related invocation.
+type robotRelatedRequest struct {
+	BeadID        string
+	MinRelevance  int
+	MaxResults    int
+	IncludeClosed bool
+}
+
+// buildRobotRelatedOutput finds work related to req.BeadID. The boolean is
+// false when the bead does not appear in the history report.
+func buildRobotRelatedOutput(report *correlation.HistoryReport, issues []model.Issue, req robotRelatedRequest) (robotRelatedWorkOutput, bool) {
+	// Build dependency graph from issues
+	depGraph := make(map[string][]string)
+	for _, issue := range issues {
+		for _, dep := range issue.Dependencies {
+			depGraph[issue.ID] = append(depGraph[issue.ID], dep.DependsOnID)
+		}
+	}
+
+	opts := correlation.RelatedWorkOptions{
+		MinRelevance:      req.MinRelevance,
+		MaxResults:        req.MaxResults,
+		ConcurrencyWindow: 7 * 24 * time.Hour,
+		IncludeClosed:     req.IncludeClosed,
+		DependencyGraph:   depGraph,
+	}
+
+	result := report.FindRelatedWork(req.BeadID, opts)
+	if result == nil {
+		return robotRelatedWorkOutput{}, false
+	}
+	return robotRelatedWorkOutput{
+		RelatedWorkResult: result,
+		DataHash:          report.DataHash,
+		OutputFormat:      robotOutputFormat,
+		Version:           version.Version,
+	}, true
+}
+
+// robotBlockerChainOutput is the --robot-blocker-chain payload.
+type robotBlockerChainOutput struct {
+	RobotEnvelope
+	Result *analysis.BlockerChainResult `json:"result"`
+}
+
+// buildRobotBlockerChainOutput returns the blocker chain for issueID, or false
+// when the issue does not exist.
+func buildRobotBlockerChainOutput(an *analysis.Analyzer, dataHash, issueID string) (robotBlockerChainOutput, bool) {
+	result := an.GetBlockerChain(issueID)
+	if result == nil {
+		return robotBlockerChainOutput{}, false
+	}
+	return robotBlockerChainOutput{
+		RobotEnvelope: NewRobotEnvelope(dataHash),
+		Result:        result,
+	}, true
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/serve"
+)
+
+// robotService computes robot payloads against warm serve.Snapshots. It is
+// shared by the long-running modes (bv serve, bv mcp); expensive results are
+// memoized on the snapshot so they are recomputed only after a reload.
+type robotService struct {
+	repoDir   string
+	searcher  *semanticSearcher
+	searchErr error
+
+	// profile is the scoring profile for triage; nil uses the analyzer's.
+	profile *analysis.ScoringProfile
+
+	// analyzerMu serializes computations that walk the shared Analyzer.
+	analyzerMu sync.Mutex
+}
+
+func newRobotService(repoDir string) *robotService {
+	svc := &robotService{repoDir: repoDir}
+	// The searcher is created eagerly so the embedder and index stay warm;
+	// failures are reported per request instead of aborting startup.
+	svc.searcher, svc.searchErr = newSemanticSearcher(repoDir, search.EmbeddingConfigFromEnv())
+	return svc
+}
+
+// scoringProfile resolves a per-request profile name against
+// .bv/scoring.yaml; an empty name means the profile the service started with.
+func (svc *robotService) scoringProfile(name string) (*analysis.ScoringProfile, error) {
+	if name == "" {
+		return svc.profile, nil
+	}
+	return resolveScoringProfile(svc.repoDir, name)
+}
+
+// triageResult computes triage for snap; a nil profile uses the service's.
+func (svc *robotService) triageResult(snap *serve.Snapshot, group string, profile *analysis.ScoringProfile) (analysis.TriageResult, error) {
+	if profile == nil {
+		profile = svc.profile
+	}
+	key := "triage:" + group
+	if profile != nil {
+		key += ":" + profile.Name
+	}
+	v, err := snap.Memo(key, func() (any, error) {
+		history, _ := snap.Memo("triage-history", func() (any, error) {
+			return loadTriageHistory(svc.repoDir, snap.Issues, 500), nil
+		})
+		snap.Stats.WaitForPhase2()
+		svc.analyzerMu.Lock()
+		defer svc.analyzerMu.Unlock()
+		return analysis.ComputeTriageFromAnalyzer(snap.Analyzer, snap.Stats, snap.Issues, analysis.TriageOptions{
+			GroupByTrack:  group == "track",
+			GroupByLabel:  group == "label",
+			WaitForPhase2: true,
+			History:       history.(*correlation.HistoryReport),
+			Profile:       profile,
+		}, time.Now()), nil
+	})
+	if err != nil {
+		return analysis.TriageResult{}, err
+	}
+	return v.(analysis.TriageResult), nil
+}
+
+func (svc *robotService) triageOutput(snap *serve.Snapshot, group string, profile *analysis.ScoringProfile) (robotTriageOutput, error) {
+	triage, err := svc.triageResult(snap, group, profile)
+	if err != nil {
+		return robotTriageOutput{}, err
+	}
+	return buildRobotTriageOutput(robotScope{DataHash: snap.DataHash}, triage, loadTriageFeedback()), nil
+}
+
+func (svc *robotService) nextOutput(snap *serve.Snapshot, profile *analysis.ScoringProfile) (any, error) {
+	triage, err := svc.triageResult(snap, "", profile)
+	if err != nil {
+		return nil, err
+	}
+	return buildRobotNextOutput(robotScope{DataHash: snap.DataHash}, triage), nil
+}
+
+func (svc *robotService) planOutput(snap *serve.Snapshot) (any, error) {
+	return snap.Memo("plan", func() (any, error) {
+		// Plan status reflects the reduced --robot-plan config, which needs
+		// its own (cheap) analysis pass rather than the snapshot's full one.
+		analyzer := analysis.NewAnalyzer(snap.Issues)
+		cfg := robotPlanAnalysisConfig(snap.Issues, false)
+		plan := analyzer.GetExecutionPlan()
+		stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
+		stats.WaitForPhase2()
+		return buildRobotPlanOutput(robotScope{DataHash: snap.DataHash}, cfg, stats.Status(), plan), nil
+	})
+}
+
+func (svc *robotService) insightsOutput(snap *serve.Snapshot) (any, error) {
+	return snap.Memo("insights", func() (any, error) {
+		snap.Stats.WaitForPhase2()
+		svc.analyzerMu.Lock()
+		defer svc.analyzerMu.Unlock()
+		return buildRobotInsightsOutput(robotScope{DataHash: snap.DataHash}, snap.Issues, snap.Analyzer, snap.Stats), nil
+	})
+}
+
+// blockerChainOutput returns false when issueID is unknown.
+func (svc *robotService) blockerChainOutput(snap *serve.Snapshot, issueID string) (robotBlockerChainOutput, bool) {
+	svc.analyzerMu.Lock()
+	defer svc.analyzerMu.Unlock()
+	return buildRobotBlockerChainOutput(snap.Analyzer, snap.DataHash, issueID)
+}
+
+func (svc *robotService) searchOutput(ctx context.Context, snap *serve.Snapshot, req semanticSearchRequest) (robotSearchOutput, error) {
+	if svc.searchErr != nil {
+		return robotSearchOutput{}, svc.searchErr
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return svc.searcher.Search(ctx, snap.Issues, snap.DataHash, req)
+}
+
+// searchConfig resolves the search configuration from the environment plus
+// per-request overrides (same precedence as --search-mode/--search-preset).
+func searchConfig(mode, preset, weights string) (search.SearchConfig, error) {
+	cfg, err := search.SearchConfigFromEnv()
+	if err != nil {
+		return search.SearchConfig{}, err
+	}
+	return applySearchConfigOverrides(cfg, mode, preset, weights)
+}
+
+// beadsJSONLPath returns the beads file used for commit correlation.
+func (svc *robotService) beadsJSONLPath() (string, error) {
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return "", fmt.Errorf("getting beads directory: %w", err)
+	}
+	beadsPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return "", fmt.Errorf("finding beads file: %w", err)
+	}
+	return beadsPath, nil
+}
+
+// correlationReport returns the bead-to-commit report for snap, generated at
+// most once per snapshot and history limit.
+func (svc *robotService) correlationReport(snap *serve.Snapshot, limit int) (*correlation.HistoryReport, error) {
+	v, err := snap.Memo("correlation:"+strconv.Itoa(limit), func() (any, error) {
+		if err := correlation.ValidateRepository(svc.repoDir); err != nil {
+			return nil, err
+		}
+		beadsPath, err := svc.beadsJSONLPath()
+		if err != nil {
+			return nil, err
+		}
+		return generateCorrelationReport(svc.repoDir, beadsPath, snap.Issues, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*correlation.HistoryReport), nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+)
+
+// gridSuggestMargin is how much nDCG the grid-searched weights must gain over
+// the best preset before bv suggests custom weights instead of the preset.
+const gridSuggestMargin = 0.01
+
+// searchEvalRow is the score of one ranking configuration.
+type searchEvalRow struct {
+	Name    string            `json:"name"` // "text", "hybrid:<preset>" or "grid"
+	Mode    search.SearchMode `json:"mode"`
+	Preset  search.PresetName `json:"preset,omitempty"`
+	Weights *search.Weights   `json:"weights,omitempty"`
+	search.EvalMetrics
+}
+
+// robotSearchEvalOutput is the `bv search-eval --json` payload.
+type robotSearchEvalOutput struct {
+	RobotEnvelope
+	File       string            `json:"file"`
+	Provider   search.Provider   `json:"provider"`
+	K          int               `json:"k"`
+	Queries    int               `json:"queries"`
+	UnknownIDs []string          `json:"unknown_ids,omitempty"` // Judged IDs missing from the data
+	Results    []searchEvalRow   `json:"results"`
+	BestPreset search.PresetName `json:"best_preset"`
+	GridStep   float64           `json:"grid_step,omitempty"`
+	GridTried  int               `json:"grid_evaluated,omitempty"`
+	Suggestion string            `json:"suggestion"`
+}
+
+// runSearchEvalCommand implements `bv search-eval`.
+func runSearchEvalCommand(args []string) int {
+	fs := flag.NewFlagSet("search-eval", flag.ContinueOnError)
+	dbPath := fs.String("db", "", "Path to beads database file or .beads directory")
+	k := fs.Int("k", search.DefaultEvalK, "Cutoff for nDCG@k and recall@k")
+	gridStep := fs.Float64("grid-step", search.DefaultGridStep, "Weight increment for the grid search (0.05-0.5)")
+	noGrid := fs.Bool("no-grid", false, "Skip the weight grid search")
+	jsonOut := fs.Bool("json", false, "Print results as JSON")
+	format := fs.String("format", "", "Structured output format with --json: json|toon (env: BV_OUTPUT_FORMAT)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv search-eval [options] <queries.yaml|queries.jsonl>")
+		fmt.Fprintln(os.Stderr, "\nMeasure search relevance (MRR, nDCG@k, recall@k) on judged queries for text")
+		fmt.Fprintln(os.Stderr, "mode, hybrid mode with every preset, and grid-searched weights.")
+		fmt.Fprintln(os.Stderr, "Each query lists the issue IDs a good search should return:")
+		fmt.Fprintln(os.Stderr, `  {"query": "login timeout", "relevant": ["bv-12", "bv-40"], "grades": {"bv-12": 2}}`)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if *dbPath != "" {
+		absDB, err := filepath.Abs(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving --db path: %v\n", err)
+			return 1
+		}
+		os.Setenv(loader.BeadsDBEnvVar, absDB)
+	}
+	robotOutputFormat = resolveRobotOutputFormat(*format)
+	robotToonEncodeOptions = resolveToonEncodeOptionsFromEnv()
+	if robotOutputFormat != "json" && robotOutputFormat != "toon" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (expected json|toon)\n", robotOutputFormat)
+		return 2
+	}
+	if *noGrid {
+		*gridStep = 0
+	}
+
+	queries, err := search.LoadEvalQueries(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	issues, err := datasource.LoadIssues("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading issues: %v\n", err)
+		return 1
+	}
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	searcher, err := newSemanticSearcher(projectDir, search.EmbeddingConfigFromEnv())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !*jsonOut {
+		searcher.Progress = os.Stderr
+	}
+
+	output, err := runSearchEval(context.Background(), searcher, issues, queries, *k, *gridStep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	output.File = fs.Arg(0)
+
+	if *jsonOut {
+		if err := newRobotEncoder(os.Stdout).Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding search-eval: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	printSearchEval(os.Stdout, output)
+	return 0
+}
+
+// runSearchEval retrieves text-scored candidates for each query once, then
+// scores text mode, hybrid mode under every preset and, when gridStep > 0,
+// the best grid-searched weights.
+func runSearchEval(ctx context.Context, searcher *semanticSearcher, issues []model.Issue, queries []search.EvalQuery, k int, gridStep float64) (robotSearchEvalOutput, error) {
+	if k <= 0 {
+		k = search.DefaultEvalK
+	}
+	dataHash := analysis.ComputeDataHash(issues)
+	out := robotSearchEvalOutput{
+		RobotEnvelope: NewRobotEnvelope(dataHash),
+		Provider:      searcher.embedCfg.Provider,
+		K:             k,
+		Queries:       len(queries),
+	}
+
+	known := make(map[string]bool, len(issues))
+	for _, iss := range issues {
+		known[iss.ID] = true
+	}
+	unknown := make(map[string]bool)
+
+	cands := make([]search.EvalCandidates, len(queries))
+	for i, q := range queries {
+		for _, id := range q.Relevant {
+			if !known[id] {
+				unknown[id] = true
+			}
+		}
+		text := search.QueryText(q.Query)
+		qctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		res, err := searcher.Search(qctx, issues, dataHash, semanticSearchRequest{
+			Query:       q.Query,
+			Limit:       max(search.HybridCandidateLimit(k, len(issues), text), k),
+			Config:      search.SearchConfig{Mode: search.SearchModeText},
+			SkipMatches: true,
+		})
+		cancel()
+		if err != nil {
+			return robotSearchEvalOutput{}, fmt.Errorf("query %q: %w", q.Query, err)
+		}
+		cands[i] = search.EvalCandidates{Query: q, Text: text}
+		for _, r := range res.Results {
+			cands[i].Results = append(cands[i].Results, search.SearchResult{IssueID: r.IssueID, Score: r.Score})
+		}
+	}
+	for id := range unknown {
+		out.UnknownIDs = append(out.UnknownIDs, id)
+	}
+	sort.Strings(out.UnknownIDs)
+
+	out.Results = append(out.Results, searchEvalRow{
+		Name:        "text",
+		Mode:        search.SearchModeText,
+		EvalMetrics: search.EvaluateText(cands, k),
+	})
+
+	cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issues))
+	if err := cache.Refresh(); err != nil {
+		return robotSearchEvalOutput{}, fmt.Errorf("computing hybrid metrics: %w", err)
+	}
+	bestIdx := -1
+	for _, preset := range search.ListPresets() {
+		weights, err := search.GetPreset(preset)
+		if err != nil {
+			return robotSearchEvalOutput{}, err
+		}
+		metrics, err := search.EvaluateHybrid(cands, weights, cache, k)
+		if err != nil {
+			return robotSearchEvalOutput{}, err
+		}
+		out.Results = append(out.Results, searchEvalRow{
+			Name:        "hybrid:" + string(preset),
+			Mode:        search.SearchModeHybrid,
+			Preset:      preset,
+			Weights:     &weights,
+			EvalMetrics: metrics,
+		})
+		if i := len(out.Results) - 1; bestIdx < 0 || out.Results[i].Better(out.Results[bestIdx].EvalMetrics) {
+			bestIdx = i
+		}
+	}
+	bestPreset := out.Results[bestIdx]
+	out.BestPreset = bestPreset.Preset
+
+	var grid *searchEvalRow
+	if gridStep > 0 {
+		weights, metrics, tried, err := search.GridSearchWeights(cands, cache, k, gridStep)
+		if err != nil {
+			return robotSearchEvalOutput{}, err
+		}
+		out.Results = append(out.Results, searchEvalRow{
+			Name:        "grid",
+			Mode:        search.SearchModeHybrid,
+			Weights:     &weights,
+			EvalMetrics: metrics,
+		})
+		grid = &out.Results[len(out.Results)-1]
+		out.GridStep = gridStep
+		out.GridTried = tried
+	}
+
+	text := out.Results[0]
+	switch {
+	case grid != nil && grid.NDCG > bestPreset.NDCG+gridSuggestMargin && grid.Better(text.EvalMetrics):
+		raw, _ := json.Marshal(grid.Weights)
+		out.Suggestion = fmt.Sprintf("BV_SEARCH_MODE=hybrid BV_SEARCH_WEIGHTS='%s'", raw)
+	case bestPreset.Better(text.EvalMetrics):
+		out.Suggestion = fmt.Sprintf("BV_SEARCH_MODE=hybrid BV_SEARCH_PRESET=%s", bestPreset.Preset)
+	default:
+		out.Suggestion = "BV_SEARCH_MODE=text"
+	}
+	return out, nil
+}
+
+// printSearchEval writes the comparison table.
+func printSearchEval(w io.Writer, out robotSearchEvalOutput) {
+	fmt.Fprintf(w, "Search evaluation: %d queries, k=%d, embedder %s\n\n", out.Queries, out.K, out.Provider)
+	fmt.Fprintf(w, "%-24s %7s %9s %10s\n", "CONFIG", "MRR", fmt.Sprintf("nDCG@%d", out.K), fmt.Sprintf("Recall@%d", out.K))
+	for _, row := range out.Results {
+		marker := ""
+		if row.Preset != "" && row.Preset == out.BestPreset {
+			marker = "  ← best preset"
+		}
+		fmt.Fprintf(w, "%-24s %7.3f %9.3f %10.3f%s\n", row.Name, row.MRR, row.NDCG, row.Recall, marker)
+		if row.Name == "grid" && row.Weights != nil {
+			wt := row.Weights
+			fmt.Fprintf(w, "%-24s text %.2f pagerank %.2f status %.2f impact %.2f priority %.2f recency %.2f (%d weightings tried)\n",
+				"", wt.TextRelevance, wt.PageRank, wt.Status, wt.Impact, wt.Priority, wt.Recency, out.GridTried)
+		}
+	}
+	if len(out.UnknownIDs) > 0 {
+		fmt.Fprintf(w, "\nWarning: %d judged issue(s) not found in the data: %v\n", len(out.UnknownIDs), out.UnknownIDs)
+	}
+	fmt.Fprintf(w, "\nBest preset: %s\nSuggestion:  %s\n", out.BestPreset, out.Suggestion)
+}
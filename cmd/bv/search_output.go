@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 )
 
@@ -17,6 +22,7 @@ type robotSearchResult struct {
 	TextScore       float64            `json:"text_score,omitempty"`
 	Title           string             `json:"title,omitempty"`
 	ComponentScores map[string]float64 `json:"component_scores,omitempty"`
+	Match           *search.ChunkMatch `json:"match,omitempty"` // Passage that matched: title, text field chunk or comment
 }
 
 type robotSearchOutput struct {
@@ -29,8 +35,10 @@ type robotSearchOutput struct {
 	IndexPath   string                `json:"index_path"`
 	Index       search.IndexSyncStats `json:"index"`
 	Loaded      bool                  `json:"loaded"`
+	Approximate bool                  `json:"approximate,omitempty"` // Results from the HNSW graph rather than an exact scan
 	Limit       int                   `json:"limit"`
 	Mode        search.SearchMode     `json:"mode"`
+	ParsedQuery *search.Query         `json:"parsed_query,omitempty"` // Present when the query has filters, phrases or exclusions
 	Preset      search.PresetName     `json:"preset,omitempty"`
 	Weights     *search.Weights       `json:"weights,omitempty"`
 	Results     []robotSearchResult   `json:"results"`
@@ -148,3 +156,355 @@ func promoteExactHybridResult(query string, results []search.HybridScore) []sear
 	}
 	return results
 }
+
+// semanticSearcher runs the --search pipeline. It owns the embedder, the
+// on-disk vector index and the hybrid metrics cache so that long-running
+// modes (bv serve, bv mcp) only pay for index sync and metrics once per data
+// change instead of once per query.
+type semanticSearcher struct {
+	mu          sync.Mutex
+	embedCfg    search.EmbeddingConfig
+	embedder    search.Embedder
+	indexPath   string
+	idx         *search.VectorIndex
+	loaded      bool
+	metrics     search.MetricsCache
+	metricsHash string
+	lexical     *search.BM25Index
+	chunks      *search.ChunkIndex
+	lexicalHash string
+	chunkVecs   *search.VectorIndex // Cached chunk embeddings for semantic matches
+	chunkHash   string              // lexicalHash chunkVecs was last pruned against
+
+	// Progress, when set, receives a notice before a cold index build.
+	Progress io.Writer
+}
+
+// semanticSearchRequest is a single query against a semanticSearcher.
+type semanticSearchRequest struct {
+	Query  string
+	Limit  int
+	Config search.SearchConfig
+
+	SkipMatches bool // Omit per-result match snippets (scores only)
+}
+
+// newSemanticSearcher creates the embedder and loads (or initializes) the
+// vector index for projectDir.
+func newSemanticSearcher(projectDir string, embedCfg search.EmbeddingConfig) (*semanticSearcher, error) {
+	embedder, err := search.NewEmbedderFromConfig(embedCfg)
+	if err != nil {
+		return nil, err
+	}
+	indexPath := search.DefaultIndexPath(projectDir, embedCfg)
+	idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
+	if err != nil {
+		return nil, err
+	}
+	return &semanticSearcher{
+		embedCfg:  embedCfg,
+		embedder:  embedder,
+		indexPath: indexPath,
+		idx:       idx,
+		loaded:    loaded,
+	}, nil
+}
+
+// IndexPath returns the on-disk location of the vector index.
+func (s *semanticSearcher) IndexPath() string {
+	return s.indexPath
+}
+
+// Size returns the number of vectors currently in the index.
+func (s *semanticSearcher) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.idx.Size()
+}
+
+// Search syncs the index against issues and runs req, returning the same
+// payload emitted by --robot-search.
+func (s *semanticSearcher) Search(ctx context.Context, issues []model.Issue, dataHash string, req semanticSearchRequest) (robotSearchOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query, err := search.ParseQuery(req.Query)
+	if err != nil {
+		return robotSearchOutput{}, err
+	}
+	var allow func(id string) bool
+	if query.HasConstraints() {
+		qc := search.NewQueryContext(issues, datasource.DefaultActor(), time.Now())
+		matched := make(map[string]bool)
+		for _, iss := range issues {
+			if query.Match(iss, qc) {
+				matched[iss.ID] = true
+			}
+		}
+		allow = func(id string) bool { return matched[id] }
+	}
+
+	docs := search.DocumentsFromIssues(issues)
+	if s.Progress != nil && !s.loaded {
+		fmt.Fprintf(s.Progress, "Building semantic index (%d issues)...\n", len(docs))
+	}
+
+	syncStats, err := search.SyncVectorIndex(ctx, s.idx, s.embedder, docs, 64)
+	if err != nil {
+		return robotSearchOutput{}, fmt.Errorf("building semantic index: %w", err)
+	}
+	loaded := s.loaded
+	if !loaded || syncStats.Changed() || s.idx.NeedsANNBuild() {
+		if err := s.idx.Save(s.indexPath); err != nil {
+			return robotSearchOutput{}, fmt.Errorf("saving semantic index: %w", err)
+		}
+		s.loaded = true
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	cfg := req.Config
+	text := strings.TrimSpace(query.Text())
+
+	var results []search.SearchResult
+	var qvec []float32
+	if text == "" {
+		// Pure filter query: nothing to rank by text, so list matches by
+		// priority and leave ordering in hybrid mode to the graph signals.
+		results = filteredSearchResults(issues, allow)
+	} else {
+		qvecs, err := s.embedder.Embed(ctx, []string{text})
+		if err != nil || len(qvecs) != 1 {
+			if err == nil {
+				err = fmt.Errorf("embedder returned %d vectors for query", len(qvecs))
+			}
+			return robotSearchOutput{}, fmt.Errorf("embedding query: %w", err)
+		}
+		qvec = qvecs[0]
+
+		fetchLimit := limit
+		if cfg.Mode == search.SearchModeHybrid {
+			fetchLimit = search.HybridCandidateLimit(limit, len(issues), text)
+		}
+		semantic, err := s.idx.SearchTopKFiltered(qvec, fetchLimit, allow)
+		if err != nil {
+			return robotSearchOutput{}, fmt.Errorf("searching index: %w", err)
+		}
+		if s.lexical == nil || s.lexicalHash != dataHash {
+			s.lexical = search.NewBM25Index(issues)
+			s.chunks = search.NewChunkIndex(issues)
+			s.lexicalHash = dataHash
+		}
+		lexical := s.lexical.Search(text, fetchLimit, allow)
+
+		results = search.BlendTextScores(lexical, semantic)
+		if len(results) > fetchLimit {
+			results = results[:fetchLimit]
+		}
+		if isLikelyIssueID(text) {
+			results = promoteExactSearchResult(text, results)
+		}
+	}
+
+	titleByID := make(map[string]string, len(issues))
+	for _, iss := range issues {
+		titleByID[iss.ID] = iss.Title
+	}
+
+	out := robotSearchOutput{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		DataHash:    dataHash,
+		Query:       req.Query,
+		Provider:    s.embedCfg.Provider,
+		Model:       s.embedCfg.Model,
+		Dim:         s.embedder.Dim(),
+		IndexPath:   s.indexPath,
+		Index:       syncStats,
+		Loaded:      loaded,
+		Approximate: s.idx.UsesANN(),
+		Limit:       limit,
+		Mode:        cfg.Mode,
+	}
+	if query.HasConstraints() {
+		out.ParsedQuery = &query
+	}
+
+	if cfg.Mode != search.SearchModeHybrid {
+		if len(results) > limit {
+			results = results[:limit]
+		}
+		out.Results = make([]robotSearchResult, 0, len(results))
+		for _, r := range results {
+			out.Results = append(out.Results, robotSearchResult{
+				IssueID: r.IssueID,
+				Score:   r.Score,
+				Title:   titleByID[r.IssueID],
+			})
+		}
+		if !req.SkipMatches {
+			s.attachMatches(ctx, out.Results, text, qvec)
+		}
+		out.UsageHints = []string{
+			"jq '.results[] | {id: .issue_id, score: .score, title: .title}' - Extract results",
+			"jq '.results[] | {id: .issue_id, field: .match.field, snippet: .match.snippet}' - Where each result matched",
+			"jq '.index' - Index update stats (added/updated/removed/embedded)",
+		}
+		return out, nil
+	}
+
+	weights, presetName, err := resolveSearchWeights(cfg)
+	if err != nil {
+		return robotSearchOutput{}, err
+	}
+	weights = weights.Normalize()
+	weights = search.AdjustWeightsForQuery(weights, text)
+	out.Preset = presetName
+	out.Weights = &weights
+
+	if s.metrics == nil || s.metricsHash != dataHash {
+		cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issues))
+		if err := cache.Refresh(); err != nil {
+			return robotSearchOutput{}, fmt.Errorf("computing hybrid metrics: %w", err)
+		}
+		s.metrics = cache
+		s.metricsHash = dataHash
+	}
+
+	scorer := search.NewHybridScorer(weights, s.metrics)
+	hybridResults, err := buildHybridScores(results, scorer)
+	if err != nil {
+		return robotSearchOutput{}, fmt.Errorf("scoring hybrid results: %w", err)
+	}
+	if isLikelyIssueID(text) {
+		hybridResults = promoteExactHybridResult(text, hybridResults)
+	}
+	if len(hybridResults) > limit {
+		hybridResults = hybridResults[:limit]
+	}
+
+	out.Results = make([]robotSearchResult, 0, len(hybridResults))
+	for _, r := range hybridResults {
+		out.Results = append(out.Results, robotSearchResult{
+			IssueID:         r.IssueID,
+			Score:           r.FinalScore,
+			TextScore:       r.TextScore,
+			Title:           titleByID[r.IssueID],
+			ComponentScores: r.ComponentScores,
+		})
+	}
+	if !req.SkipMatches {
+		s.attachMatches(ctx, out.Results, text, qvec)
+	}
+	out.UsageHints = []string{
+		"jq '.results[] | {id: .issue_id, score: .score, text: .text_score}' - Extract scores",
+		"jq '.results[] | {id: .issue_id, components: .component_scores}' - Hybrid breakdown",
+		"jq '.index' - Index update stats (added/updated/removed/embedded)",
+	}
+	return out, nil
+}
+
+// filteredSearchResults lists the issues accepted by allow (all when nil)
+// ordered by priority, most recently updated first, each scored 1.
+func filteredSearchResults(issues []model.Issue, allow func(id string) bool) []search.SearchResult {
+	matched := make([]model.Issue, 0, len(issues))
+	for _, iss := range issues {
+		if allow == nil || allow(iss.ID) {
+			matched = append(matched, iss)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Priority != matched[j].Priority {
+			return matched[i].Priority < matched[j].Priority
+		}
+		return matched[i].UpdatedAt.After(matched[j].UpdatedAt)
+	})
+	results := make([]search.SearchResult, len(matched))
+	for i, iss := range matched {
+		results[i] = search.SearchResult{IssueID: iss.ID, Score: 1}
+	}
+	return results
+}
+
+// attachMatches points each result at the chunk that matched text: the best
+// BM25 chunk when the query's words occur in the issue, otherwise the chunk
+// closest to the query embedding. Matches are decoration, so a failure to
+// embed chunks leaves those results without one rather than failing the
+// search.
+func (s *semanticSearcher) attachMatches(ctx context.Context, results []robotSearchResult, text string, qvec []float32) {
+	if text == "" || s.chunks == nil || len(results) == 0 {
+		return
+	}
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.IssueID
+	}
+	matches := s.chunks.BestMatches(text, ids)
+
+	var missing []string
+	for _, id := range ids {
+		if _, ok := matches[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 && qvec != nil {
+		cache := s.chunkVectors()
+		changed := 0
+		if s.chunkHash != s.lexicalHash {
+			changed += s.chunks.PruneVectors(cache)
+			s.chunkHash = s.lexicalHash
+		}
+		embedded, err := s.chunks.EmbedChunks(ctx, s.embedder, cache, missing)
+		changed += embedded
+		if err == nil {
+			if semantic, err := s.chunks.SemanticMatches(ctx, s.embedder, cache, qvec, missing); err == nil {
+				for id, m := range semantic {
+					matches[id] = m
+				}
+			}
+		}
+		if changed > 0 {
+			_ = cache.Save(search.ChunkVectorsPath(s.indexPath))
+		}
+	}
+
+	for i := range results {
+		if m, ok := matches[results[i].IssueID]; ok {
+			results[i].Match = &m
+		}
+	}
+}
+
+// chunkVectors loads the chunk embedding cache next to the vector index on
+// first use. An unreadable or mismatched cache starts over empty.
+func (s *semanticSearcher) chunkVectors() *search.VectorIndex {
+	if s.chunkVecs == nil {
+		dim := s.embedder.Dim()
+		cache, _, err := search.LoadOrNewVectorIndex(search.ChunkVectorsPath(s.indexPath), dim)
+		if err != nil || cache.Dim != dim {
+			cache = search.NewVectorIndex(dim)
+		}
+		cache.ANNThreshold = -1 // Looked up by chunk ID only
+		s.chunkVecs = cache
+	}
+	return s.chunkVecs
+}
+
+// formatSearchMatch renders a match for human output, e.g.
+// "[comment #3 by alice] …the login timeout…", bolding matched words when
+// color is set.
+func formatSearchMatch(m *search.ChunkMatch, color bool) string {
+	label := m.Field
+	if m.Field == search.ChunkFieldComment {
+		label = fmt.Sprintf("comment #%d", m.Comment)
+		if m.Author != "" {
+			label += " by " + m.Author
+		}
+	}
+	snippet := m.Snippet
+	if color {
+		snippet = search.HighlightSnippet(snippet, m.Highlights, "\x1b[1m", "\x1b[0m")
+	}
+	return fmt.Sprintf("[%s] %s", label, snippet)
+}
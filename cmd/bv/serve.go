@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/serve"
+)
+
+// runServeCommand implements `bv serve`: a long-running HTTP/JSON API that
+// keeps issues, graph stats and the analyzer warm between requests and
+// reloads them when the beads data changes on disk.
+func runServeCommand(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", serve.DefaultAddr, "Listen address (host:port)")
+	dbPath := fs.String("db", "", "Path to beads database file or .beads directory")
+	format := fs.String("format", "", "Default output format: json|toon (env: BV_OUTPUT_FORMAT)")
+	noWatch := fs.Bool("no-watch", false, "Disable automatic reload when beads data changes")
+	verbose := fs.Bool("verbose", false, "Log every request to stderr")
+	profileName := fs.String("profile", "", "Scoring profile for triage and what-if (default: .bv/scoring.yaml's default)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv serve [options]")
+		fmt.Fprintln(os.Stderr, "\nServe robot payloads over HTTP with warm analysis state.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if *dbPath != "" {
+		absDB, err := filepath.Abs(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving --db path: %v\n", err)
+			return 1
+		}
+		os.Setenv(loader.BeadsDBEnvVar, absDB)
+	}
+
+	robotOutputFormat = resolveRobotOutputFormat(*format)
+	robotToonEncodeOptions = resolveToonEncodeOptionsFromEnv()
+	if robotOutputFormat != "json" && robotOutputFormat != "toon" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (expected json|toon)\n", robotOutputFormat)
+		return 2
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+		return 1
+	}
+	profile, err := resolveScoringProfile(cwd, *profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	store, err := serve.NewStore(func() ([]model.Issue, error) {
+		return datasource.LoadIssues("")
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'br init'.")
+		return 1
+	}
+	defer store.Close()
+
+	logf := func(msg string) {
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", time.Now().Format("15:04:05"), msg)
+	}
+	store.OnReload(func(snap *serve.Snapshot, err error) {
+		if err != nil {
+			logf(fmt.Sprintf("reload failed: %v", err))
+			return
+		}
+		logf(fmt.Sprintf("loaded %d issues (generation %d)", len(snap.Issues), snap.Generation))
+	})
+
+	if !*noWatch {
+		if sources, err := serveWatchSources(); err != nil {
+			logf(fmt.Sprintf("auto-reload disabled: %v", err))
+		} else if err := store.Watch(sources, datasource.DefaultWatcherOptions()); err != nil {
+			logf(fmt.Sprintf("auto-reload disabled: %v", err))
+		}
+	}
+
+	opts := serve.Options{
+		Addr:   *addr,
+		Format: robotOutputFormat,
+		Encode: func(w io.Writer, format string, v any) error {
+			return newRobotEncoderFor(w, format).Encode(v)
+		},
+	}
+	if *verbose {
+		opts.Logger = logf
+	}
+	srv := serve.NewServer(store, opts)
+	svc := newRobotService(cwd)
+	svc.profile = profile
+	registerServeHandlers(srv, svc)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	snap := store.Current()
+	fmt.Fprintf(os.Stderr, "bv serve listening on http://%s (%d issues, format %s)\n", srv.Addr(), len(snap.Issues), robotOutputFormat)
+	if err := srv.ListenAndServe(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// serveWatchSources returns the data sources to watch for auto-reload.
+func serveWatchSources() ([]datasource.DataSource, error) {
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return nil, err
+	}
+	sources, err := datasource.DiscoverSources(datasource.DiscoveryOptions{BeadsDir: beadsDir})
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no data sources found in %s", beadsDir)
+	}
+	return sources, nil
+}
+
+// serveHandlers adapts robotService to bv serve's HTTP endpoints.
+type serveHandlers struct {
+	svc *robotService
+}
+
+func registerServeHandlers(srv *serve.Server, svc *robotService) {
+	h := &serveHandlers{svc: svc}
+	srv.Handle("/triage", "Unified triage (?group=track|label&profile=)", h.triage)
+	srv.Handle("/next", "Single top recommendation (?profile=)", h.next)
+	srv.Handle("/plan", "Dependency-respecting execution plan", h.plan)
+	srv.Handle("/insights", "Graph analysis and insights", h.insights)
+	srv.Handle("/graph", "Dependency graph (?graph_format=json|dot|mermaid&label=&root=&depth=)", h.graph)
+	srv.Handle("/search", "Semantic search (?q=&limit=&mode=&preset=&weights=)", h.search)
+	srv.Handle("/history", "Bead-to-commit correlations (?bead=&since=&limit=&min_confidence=)", h.history)
+	srv.Handle("/forecast", "ETA forecast and Monte Carlo distribution (?id=<issue>|all&label=&sprint=&epic=&agents=&trials=<=10000&seed=)", h.forecast)
+	srv.Handle("/whatif", "Compare inline hypothetical edits with the baseline (?edits=close:ID,add-dep:A>B,...&agents=<=1000&trials=<=10000&seed=&profile=)", h.whatif)
+	srv.Handle("/schedule", "Assignment plan over .bv/team.yaml (?agents=<=1000 when there is no roster)", h.schedule)
+}
+
+func (h *serveHandlers) triage(r *http.Request, snap *serve.Snapshot) (any, error) {
+	group := strings.ToLower(r.URL.Query().Get("group"))
+	switch group {
+	case "", "track", "label":
+	default:
+		return nil, serve.Errorf(http.StatusBadRequest, "invalid group %q (expected track|label)", group)
+	}
+	profile, err := h.profile(r)
+	if err != nil {
+		return nil, err
+	}
+	return h.svc.triageOutput(snap, group, profile)
+}
+
+func (h *serveHandlers) next(r *http.Request, snap *serve.Snapshot) (any, error) {
+	profile, err := h.profile(r)
+	if err != nil {
+		return nil, err
+	}
+	return h.svc.nextOutput(snap, profile)
+}
+
+// profile resolves the ?profile= parameter; without it the server's
+// --profile applies.
+func (h *serveHandlers) profile(r *http.Request) (*analysis.ScoringProfile, error) {
+	profile, err := h.svc.scoringProfile(r.URL.Query().Get("profile"))
+	if err != nil {
+		return nil, serve.Errorf(http.StatusBadRequest, "%v", err)
+	}
+	return profile, nil
+}
+
+func (h *serveHandlers) plan(_ *http.Request, snap *serve.Snapshot) (any, error) {
+	return h.svc.planOutput(snap)
+}
+
+func (h *serveHandlers) insights(_ *http.Request, snap *serve.Snapshot) (any, error) {
+	return h.svc.insightsOutput(snap)
+}
+
+func (h *serveHandlers) graph(r *http.Request, snap *serve.Snapshot) (any, error) {
+	q := r.URL.Query()
+	var format export.GraphExportFormat
+	switch strings.ToLower(q.Get("graph_format")) {
+	case "dot":
+		format = export.GraphFormatDOT
+	case "mermaid":
+		format = export.GraphFormatMermaid
+	default:
+		format = export.GraphFormatJSON
+	}
+	depth, err := queryInt(q.Get("depth"), 0)
+	if err != nil {
+		return nil, err
+	}
+	snap.Stats.WaitForPhase2()
+	return export.ExportGraph(snap.Issues, snap.Stats, export.GraphExportConfig{
+		Format:   format,
+		Label:    q.Get("label"),
+		Root:     q.Get("root"),
+		Depth:    depth,
+		DataHash: snap.DataHash,
+	})
+}
+
+func (h *serveHandlers) search(r *http.Request, snap *serve.Snapshot) (any, error) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		return nil, serve.Errorf(http.StatusBadRequest, "missing required parameter q")
+	}
+	limit, err := queryInt(q.Get("limit"), 10)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := searchConfig(q.Get("mode"), q.Get("preset"), q.Get("weights"))
+	if err != nil {
+		return nil, serve.Errorf(http.StatusBadRequest, "%v", err)
+	}
+	return h.svc.searchOutput(r.Context(), snap, semanticSearchRequest{
+		Query:  query,
+		Limit:  limit,
+		Config: cfg,
+	})
+}
+
+func (h *serveHandlers) history(r *http.Request, snap *serve.Snapshot) (any, error) {
+	q := r.URL.Query()
+	limit, err := queryInt(q.Get("limit"), 500)
+	if err != nil {
+		return nil, err
+	}
+	minConf, err := queryFloat(q.Get("min_confidence"), 0)
+	if err != nil {
+		return nil, err
+	}
+	req := robotHistoryRequest{
+		BeadID:        q.Get("bead"),
+		Limit:         limit,
+		MinConfidence: minConf,
+	}
+	if s := q.Get("since"); s != "" {
+		since, err := recipe.ParseRelativeTime(s, time.Now())
+		if err != nil {
+			return nil, serve.Errorf(http.StatusBadRequest, "invalid since: %v", err)
+		}
+		if !since.IsZero() {
+			req.Since = &since
+		}
+	}
+
+	if err := correlation.ValidateRepository(h.svc.repoDir); err != nil {
+		return nil, serve.Errorf(http.StatusConflict, "%v", err)
+	}
+	beadsPath, err := h.svc.beadsJSONLPath()
+	if err != nil {
+		return nil, err
+	}
+	return buildRobotHistoryReport(h.svc.repoDir, beadsPath, snap.Issues, req)
+}
+
+// maxServeForecastTrials caps Monte Carlo trials per /forecast request so
+// one query cannot pin the server.
+const maxServeForecastTrials = 10000
+
+// maxServeAgents caps the agent count a request may ask for; /schedule
+// allocates one roster member per agent when there is no team.yaml.
+const maxServeAgents = 1000
+
+func (h *serveHandlers) forecast(r *http.Request, snap *serve.Snapshot) (any, error) {
+	q := r.URL.Query()
+	target := q.Get("id")
+	if target == "" {
+		target = "all"
+	}
+	agents, err := queryInt(q.Get("agents"), 1)
+	if err != nil {
+		return nil, err
+	}
+	trials, err := queryInt(q.Get("trials"), 1000)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := queryInt(q.Get("seed"), 1)
+	if err != nil {
+		return nil, err
+	}
+	if agents < 0 || trials < 0 {
+		return nil, serve.Errorf(http.StatusBadRequest, "agents and trials must not be negative")
+	}
+	if trials > maxServeForecastTrials {
+		return nil, serve.Errorf(http.StatusBadRequest, "trials %d exceeds the maximum of %d", trials, maxServeForecastTrials)
+	}
+	for _, id := range []string{target, q.Get("epic")} {
+		if id != "" && id != "all" && !snapshotHasIssue(snap, id) {
+			return nil, serve.Errorf(http.StatusNotFound, "issue not found: %s", id)
+		}
+	}
+	req := robotForecastRequest{
+		Target: target,
+		Label:  q.Get("label"),
+		Sprint: q.Get("sprint"),
+		Epic:   q.Get("epic"),
+		Agents: agents,
+		Trials: trials,
+		Seed:   int64(seed),
+	}
+	if req.Sprint != "" {
+		if sprints, err := loader.LoadSprints(h.svc.repoDir); err == nil {
+			req.SprintBeadIDs = sprintBeadIDSet(sprints, req.Sprint)
+		}
+		if req.SprintBeadIDs == nil {
+			return nil, serve.Errorf(http.StatusNotFound, "sprint not found: %s", req.Sprint)
+		}
+	}
+
+	snap.Stats.WaitForPhase2()
+	return buildRobotForecastOutput(snap.Issues, snap.Stats, req, time.Now())
+}
+
+func snapshotHasIssue(snap *serve.Snapshot, id string) bool {
+	for i := range snap.Issues {
+		if snap.Issues[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *serveHandlers) whatif(r *http.Request, snap *serve.Snapshot) (any, error) {
+	q := r.URL.Query()
+	edits, err := analysis.ParseScenarioEdits(q.Get("edits"))
+	if err != nil {
+		return nil, serve.Errorf(http.StatusBadRequest, "%v", err)
+	}
+	agents, err := queryInt(q.Get("agents"), 1)
+	if err != nil {
+		return nil, err
+	}
+	trials, err := queryInt(q.Get("trials"), 1000)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := queryInt(q.Get("seed"), 1)
+	if err != nil {
+		return nil, err
+	}
+	if agents <= 0 || agents > maxServeAgents {
+		return nil, serve.Errorf(http.StatusBadRequest, "agents must be between 1 and %d", maxServeAgents)
+	}
+	if trials <= 0 || trials > maxServeForecastTrials {
+		return nil, serve.Errorf(http.StatusBadRequest, "trials must be between 1 and %d", maxServeForecastTrials)
+	}
+	profile, err := h.profile(r)
+	if err != nil {
+		return nil, err
+	}
+	out, err := buildRobotWhatIfOutput(snap.Issues, robotWhatIfRequest{
+		Scenarios: []analysis.Scenario{{Name: "inline", Edits: edits}},
+		Source:    "inline",
+		Agents:    agents,
+		Trials:    trials,
+		Seed:      int64(seed),
+		Profile:   profile,
+	}, time.Now())
+	if err != nil {
+		return nil, serve.Errorf(http.StatusBadRequest, "%v", err)
+	}
+	return out, nil
+}
+
+func (h *serveHandlers) schedule(r *http.Request, snap *serve.Snapshot) (any, error) {
+	agents, err := queryInt(r.URL.Query().Get("agents"), 1)
+	if err != nil {
+		return nil, err
+	}
+	if agents <= 0 || agents > maxServeAgents {
+		return nil, serve.Errorf(http.StatusBadRequest, "agents must be between 1 and %d", maxServeAgents)
+	}
+	roster, source, err := resolveTeamRoster(h.svc.repoDir, "", agents)
+	if err != nil {
+		return nil, serve.Errorf(http.StatusInternalServerError, "%v", err)
+	}
+	return buildRobotScheduleOutput(snap.Issues, roster, source, time.Now()), nil
+}
+
+func queryInt(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, serve.Errorf(http.StatusBadRequest, "invalid integer %q", raw)
+	}
+	return n, nil
+}
+
+func queryFloat(raw string, def float64) (float64, error) {
+	if raw == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, serve.Errorf(http.StatusBadRequest, "invalid number %q", raw)
+	}
+	return f, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/serve"
+)
+
+func newTestServeServer(t *testing.T, issues []model.Issue) *httptest.Server {
+	t.Helper()
+	repoDir := t.TempDir()
+	t.Setenv("BEADS_DIR", repoDir)
+
+	store, err := serve.NewStore(func() ([]model.Issue, error) { return issues, nil })
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	srv := serve.NewServer(store, serve.Options{
+		Encode: func(w io.Writer, format string, v any) error {
+			return newRobotEncoderFor(w, format).Encode(v)
+		},
+	})
+	registerServeHandlers(srv, newRobotService(repoDir))
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func serveGet(t *testing.T, ts *httptest.Server, path string, wantStatus int) map[string]any {
+	t.Helper()
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s: status %d, want %d\n%s", path, resp.StatusCode, wantStatus, body)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("GET %s: invalid JSON: %v\n%s", path, err, body)
+	}
+	return out
+}
+
+func TestServe_RobotEndpoints(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "Root", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "B", Title: "Blocked", Status: model.StatusBlocked, Priority: 2, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+	}
+	ts := newTestServeServer(t, issues)
+
+	next := serveGet(t, ts, "/next", http.StatusOK)
+	if next["id"] != "A" || next["claim_command"] != "br update A --status=in_progress" {
+		t.Errorf("unexpected /next payload: %v", next)
+	}
+
+	triage := serveGet(t, ts, "/triage?group=track", http.StatusOK)
+	if _, ok := triage["triage"].(map[string]any); !ok {
+		t.Fatalf("/triage missing triage object: %v", triage)
+	}
+	if triage["data_hash"] != next["data_hash"] {
+		t.Errorf("data_hash mismatch between /triage and /next")
+	}
+	serveGet(t, ts, "/triage?group=bogus", http.StatusBadRequest)
+
+	unblock := serveGet(t, ts, "/triage?profile=unblock", http.StatusOK)
+	if meta, _ := unblock["triage"].(map[string]any)["meta"].(map[string]any); meta["profile"] != "unblock" {
+		t.Errorf("?profile=unblock not applied: %v", meta)
+	}
+	serveGet(t, ts, "/next?profile=unblock", http.StatusOK)
+	serveGet(t, ts, "/next?profile=bogus", http.StatusBadRequest)
+	serveGet(t, ts, "/whatif?edits=close:A&trials=50&profile=bogus", http.StatusBadRequest)
+
+	plan := serveGet(t, ts, "/plan", http.StatusOK)
+	if _, ok := plan["plan"].(map[string]any); !ok {
+		t.Errorf("/plan missing plan object: %v", plan)
+	}
+
+	insights := serveGet(t, ts, "/insights", http.StatusOK)
+	if _, ok := insights["full_stats"].(map[string]any); !ok {
+		t.Errorf("/insights missing full_stats: %v", insights)
+	}
+
+	graph := serveGet(t, ts, "/graph?graph_format=dot", http.StatusOK)
+	if graph["format"] != "dot" || graph["nodes"].(float64) != 2 {
+		t.Errorf("unexpected /graph payload: %v", graph)
+	}
+
+	forecast := serveGet(t, ts, "/forecast?id=B", http.StatusOK)
+	if forecast["forecast_count"].(float64) != 1 {
+		t.Errorf("unexpected /forecast payload: %v", forecast)
+	}
+	serveGet(t, ts, "/forecast?id=NOPE", http.StatusNotFound)
+	serveGet(t, ts, "/forecast?agents=x", http.StatusBadRequest)
+	serveGet(t, ts, "/forecast?trials=-1", http.StatusBadRequest)
+	serveGet(t, ts, "/forecast?trials=10001", http.StatusBadRequest)
+	serveGet(t, ts, "/forecast?epic=NOPE", http.StatusNotFound)
+
+	whatif := serveGet(t, ts, "/whatif?edits=close:A&trials=50", http.StatusOK)
+	scenarios, _ := whatif["scenarios"].([]any)
+	if len(scenarios) != 1 || whatif["source"] != "inline" {
+		t.Fatalf("unexpected /whatif payload: %v", whatif)
+	}
+	if unblocked := scenarios[0].(map[string]any)["unblocked"].([]any); len(unblocked) != 1 || unblocked[0] != "B" {
+		t.Errorf("closing A should unblock B: %v", unblocked)
+	}
+	serveGet(t, ts, "/whatif?edits=close:NOPE", http.StatusBadRequest)
+	serveGet(t, ts, "/whatif", http.StatusBadRequest)
+	serveGet(t, ts, "/whatif?edits=close:A&trials=0", http.StatusBadRequest)
+	serveGet(t, ts, "/whatif?edits=close:A&trials=10001", http.StatusBadRequest)
+	serveGet(t, ts, "/whatif?edits=close:A&agents=-1", http.StatusBadRequest)
+	serveGet(t, ts, "/whatif?edits=close:A&agents=1001", http.StatusBadRequest)
+
+	schedule := serveGet(t, ts, "/schedule?agents=2", http.StatusOK)
+	sched, _ := schedule["schedule"].(map[string]any)
+	if schedule["roster"] != "default" || len(sched["members"].([]any)) != 2 || len(sched["assignments"].([]any)) != 2 {
+		t.Errorf("unexpected /schedule payload: %v", schedule)
+	}
+	serveGet(t, ts, "/schedule?agents=0", http.StatusBadRequest)
+	serveGet(t, ts, "/schedule?agents=-3", http.StatusBadRequest)
+	serveGet(t, ts, "/schedule?agents=1001", http.StatusBadRequest)
+
+	searchOut := serveGet(t, ts, "/search?q=Root&limit=1", http.StatusOK)
+	results, _ := searchOut["results"].([]any)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 search result, got %v", searchOut)
+	}
+	serveGet(t, ts, "/search", http.StatusBadRequest)
+
+	// Not a git repository: history is unavailable but must fail cleanly.
+	serveGet(t, ts, "/history", http.StatusConflict)
+}
+
+func TestServe_ToonFormatFallsBackOrEncodes(t *testing.T) {
+	ts := newTestServeServer(t, []model.Issue{{ID: "A", Title: "Root", Status: model.StatusOpen, IssueType: model.TypeTask}})
+
+	resp, err := http.Get(ts.URL + "/next?format=toon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if len(body) == 0 {
+		t.Error("empty TOON response")
+	}
+}
@@ -0,0 +1,327 @@
+package datasource
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Source types for offline exports from other trackers. These are never
+// discovered automatically; they are loaded explicitly via ImportFile.
+const (
+	// SourceTypeGitHubJSON is the output of `gh issue list --json ...`
+	SourceTypeGitHubJSON SourceType = "github_json"
+	// SourceTypeJiraCSV is a Jira "Export CSV (all fields)" file
+	SourceTypeJiraCSV SourceType = "jira_csv"
+	// SourceTypeLinearJSON is a Linear JSON export or GraphQL issues response
+	SourceTypeLinearJSON SourceType = "linear_json"
+)
+
+// Source adapts an export from a non-beads tracker into beads issues so the
+// graph analysis, triage and export pipelines can run over it unchanged.
+type Source interface {
+	// Name is the short format name used on the command line ("github").
+	Name() string
+	// Type is the SourceType reported for DataSources backed by this adapter.
+	Type() SourceType
+	// Detect reports whether head (the first bytes of the file at path)
+	// looks like this adapter's format.
+	Detect(path string, head []byte) bool
+	// Import parses a complete export.
+	Import(r io.Reader) ([]model.Issue, error)
+}
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   []Source
+)
+
+func init() {
+	RegisterSource(githubSource{})
+	RegisterSource(jiraSource{})
+	RegisterSource(linearSource{})
+}
+
+// RegisterSource adds an adapter to the registry, replacing any adapter with
+// the same name.
+func RegisterSource(s Source) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	for i, existing := range adapters {
+		if existing.Name() == s.Name() {
+			adapters[i] = s
+			return
+		}
+	}
+	adapters = append(adapters, s)
+}
+
+// SourceNames returns the names of all registered adapters, sorted.
+func SourceNames() []string {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	return adapterNamesLocked()
+}
+
+// LookupSource finds an adapter by name or SourceType.
+func LookupSource(name string) (Source, bool) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, s := range adapters {
+		if s.Name() == name || string(s.Type()) == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// DetectSource picks the adapter for the export at path by sniffing its
+// first few kilobytes.
+func DetectSource(path string) (Source, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := make([]byte, 8192)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = bytes.TrimPrefix(head[:n], []byte("\xEF\xBB\xBF"))
+
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	for _, s := range adapters {
+		if s.Detect(path, head) {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized export format for %s (use one of: %s)", filepath.Base(path), strings.Join(adapterNamesLocked(), ", "))
+}
+
+func adapterNamesLocked() []string {
+	names := make([]string, 0, len(adapters))
+	for _, s := range adapters {
+		names = append(names, s.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ImportFile loads issues from a tracker export. format is an adapter name
+// or SourceType; empty or "auto" detects the format from the file contents.
+func ImportFile(path, format string) ([]model.Issue, Source, error) {
+	var src Source
+	if format == "" || format == "auto" {
+		detected, err := DetectSource(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		src = detected
+	} else {
+		found, ok := LookupSource(format)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown import format %q (use one of: %s)", format, strings.Join(SourceNames(), ", "))
+		}
+		src = found
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, src, err
+	}
+	defer f.Close()
+
+	issues, err := src.Import(f)
+	if err != nil {
+		return nil, src, fmt.Errorf("%s import of %s: %w", src.Name(), path, err)
+	}
+	return issues, src, nil
+}
+
+// importGraph accumulates issues and edges from an export. Edges are
+// attached after all issues are known so that links pointing outside the
+// export (other repositories, deleted issues) can be dropped.
+type importGraph struct {
+	issues []model.Issue
+	index  map[string]int
+	edges  []model.Dependency
+	seen   map[[3]string]bool
+}
+
+func newImportGraph() *importGraph {
+	return &importGraph{index: make(map[string]int), seen: make(map[[3]string]bool)}
+}
+
+// add appends issue; a later issue with the same ID replaces the earlier one.
+func (g *importGraph) add(issue model.Issue) {
+	if issue.Title == "" {
+		issue.Title = issue.ID
+	}
+	if issue.Status == "" {
+		issue.Status = model.StatusOpen
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = model.TypeTask
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+	if issue.Status == model.StatusClosed && issue.ClosedAt == nil && !issue.UpdatedAt.IsZero() {
+		closed := issue.UpdatedAt
+		issue.ClosedAt = &closed
+	}
+	issue.Labels = dedupeStrings(issue.Labels)
+	if i, ok := g.index[issue.ID]; ok {
+		g.issues[i] = issue
+		return
+	}
+	g.index[issue.ID] = len(g.issues)
+	g.issues = append(g.issues, issue)
+}
+
+// link records that from depends on to.
+func (g *importGraph) link(from, to string, depType model.DependencyType) {
+	if from == "" || to == "" || from == to {
+		return
+	}
+	key := [3]string{from, to, string(depType)}
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	g.edges = append(g.edges, model.Dependency{IssueID: from, DependsOnID: to, Type: depType})
+}
+
+func (g *importGraph) build() []model.Issue {
+	for _, e := range g.edges {
+		fi, ok := g.index[e.IssueID]
+		if !ok {
+			continue
+		}
+		if _, ok := g.index[e.DependsOnID]; !ok {
+			continue
+		}
+		dep := e
+		dep.CreatedAt = g.issues[fi].CreatedAt
+		g.issues[fi].Dependencies = append(g.issues[fi].Dependencies, &dep)
+	}
+	return g.issues
+}
+
+func dedupeStrings(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		s = strings.TrimSpace(s)
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// parseImportTime accepts the timestamp layouts used by the supported
+// exports. Unparseable or empty values yield the zero time.
+func parseImportTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.000-0700",
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+		"02/Jan/06 3:04 PM",
+		"02/Jan/06 15:04",
+		"2/Jan/06 3:04 PM",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// priorityFromLabel maps conventional priority labels ("P1", "priority:
+// high", "critical") to a beads priority.
+func priorityFromLabel(label string) (int, bool) {
+	l := strings.ToLower(strings.TrimSpace(label))
+	l = strings.TrimPrefix(l, "priority")
+	l = strings.TrimLeft(l, ":/- ")
+	if len(l) == 2 && l[0] == 'p' && l[1] >= '0' && l[1] <= '4' {
+		return int(l[1] - '0'), true
+	}
+	return priorityFromName(l)
+}
+
+// priorityFromName maps Jira/Linear style priority names to a beads priority.
+func priorityFromName(name string) (int, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "highest", "blocker", "critical", "urgent":
+		return 0, true
+	case "high", "major":
+		return 1, true
+	case "medium", "normal":
+		return 2, true
+	case "low", "minor":
+		return 3, true
+	case "lowest", "trivial":
+		return 4, true
+	}
+	return 0, false
+}
+
+// issueTypeFromName maps tracker issue types and type-like labels to beads
+// issue types.
+func issueTypeFromName(name string) (model.IssueType, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "bug", "defect", "incident":
+		return model.TypeBug, true
+	case "feature", "enhancement", "story", "new feature", "improvement", "feature request":
+		return model.TypeFeature, true
+	case "epic", "initiative":
+		return model.TypeEpic, true
+	case "chore", "maintenance", "tech debt":
+		return model.TypeChore, true
+	case "task", "sub-task", "subtask":
+		return model.TypeTask, true
+	}
+	return "", false
+}
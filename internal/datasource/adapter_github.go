@@ -0,0 +1,186 @@
+package datasource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// githubIDPrefix is prepended to issue numbers so GitHub issues get stable,
+// beads-looking IDs ("gh-123").
+const githubIDPrefix = "gh-"
+
+var (
+	githubNumberKey = regexp.MustCompile(`"number"\s*:`)
+	// "Blocked by #12, #14" / "depends on #3 and #4"
+	githubBlockedByRef = regexp.MustCompile(`(?i)\b(?:blocked by|depends on)\s*:?\s*((?:#\d+(?:\s*,\s*|\s+and\s+|\s+)?)+)`)
+	// "Blocks #7"
+	githubBlocksRef = regexp.MustCompile(`(?i)\bblocks\s*:?\s*((?:#\d+(?:\s*,\s*|\s+and\s+|\s+)?)+)`)
+	// "Parent: #5" / "Part of #5"
+	githubParentRef = regexp.MustCompile(`(?i)\b(?:parent|part of)\s*:?\s*#(\d+)`)
+	// Task list entries in an epic body: "- [ ] #9"
+	githubTaskRef  = regexp.MustCompile(`(?m)^\s*[-*]\s+\[[ xX]\]\s+#(\d+)`)
+	githubIssueRef = regexp.MustCompile(`#(\d+)`)
+)
+
+type githubUser struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type githubIssue struct {
+	Number    int          `json:"number"`
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	State     string       `json:"state"`
+	URL       string       `json:"url"`
+	CreatedAt string       `json:"createdAt"`
+	UpdatedAt string       `json:"updatedAt"`
+	ClosedAt  string       `json:"closedAt"`
+	Author    *githubUser  `json:"author"`
+	Assignees []githubUser `json:"assignees"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Comments []struct {
+		Author    *githubUser `json:"author"`
+		Body      string      `json:"body"`
+		CreatedAt string      `json:"createdAt"`
+	} `json:"comments"`
+}
+
+// githubSource imports `gh issue list --json
+// number,title,body,state,labels,assignees,createdAt,updatedAt,closedAt,url`.
+// Dependencies come from "blocked by #N", "blocks #N", "part of #N" and
+// task-list references in issue bodies.
+type githubSource struct{}
+
+func (githubSource) Name() string     { return "github" }
+func (githubSource) Type() SourceType { return SourceTypeGitHubJSON }
+
+func (githubSource) Detect(path string, head []byte) bool {
+	trimmed := strings.TrimSpace(string(head))
+	return strings.HasPrefix(trimmed, "[") && githubNumberKey.MatchString(trimmed) && !strings.Contains(trimmed, `"identifier"`)
+}
+
+func (githubSource) Import(r io.Reader) ([]model.Issue, error) {
+	var raw []githubIssue
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding GitHub issues JSON: %w", err)
+	}
+
+	g := newImportGraph()
+	for _, gi := range raw {
+		if gi.Number <= 0 {
+			continue
+		}
+		id := githubID(gi.Number)
+		issue := model.Issue{
+			ID:          id,
+			Title:       gi.Title,
+			Description: gi.Body,
+			Status:      model.StatusOpen,
+			Priority:    2,
+			CreatedAt:   parseImportTime(gi.CreatedAt),
+			UpdatedAt:   parseImportTime(gi.UpdatedAt),
+			ClosedAt:    timePtr(parseImportTime(gi.ClosedAt)),
+			ExternalRef: stringPtr(gi.URL),
+		}
+		if strings.EqualFold(gi.State, "closed") {
+			issue.Status = model.StatusClosed
+		}
+		if len(gi.Assignees) > 0 {
+			issue.Assignee = gi.Assignees[0].Login
+		}
+
+		typeSet, prioritySet := false, false
+		for _, l := range gi.Labels {
+			issue.Labels = append(issue.Labels, l.Name)
+			if t, ok := issueTypeFromName(l.Name); ok && !typeSet {
+				issue.IssueType, typeSet = t, true
+			}
+			if p, ok := priorityFromLabel(l.Name); ok && !prioritySet {
+				issue.Priority, prioritySet = p, true
+			}
+			if issue.Status == model.StatusOpen {
+				if s, ok := statusFromLabel(l.Name); ok {
+					issue.Status = s
+				}
+			}
+		}
+		if gi.Milestone != nil && gi.Milestone.Title != "" {
+			issue.Labels = append(issue.Labels, "milestone:"+gi.Milestone.Title)
+		}
+
+		for i, c := range gi.Comments {
+			author := ""
+			if c.Author != nil {
+				author = c.Author.Login
+			}
+			issue.Comments = append(issue.Comments, &model.Comment{
+				ID:        int64(i + 1),
+				IssueID:   id,
+				Author:    author,
+				Text:      c.Body,
+				CreatedAt: parseImportTime(c.CreatedAt),
+			})
+		}
+
+		for _, m := range githubBlockedByRef.FindAllStringSubmatch(gi.Body, -1) {
+			for _, n := range githubRefNumbers(m[1]) {
+				g.link(id, githubID(n), model.DepBlocks)
+			}
+		}
+		for _, m := range githubBlocksRef.FindAllStringSubmatch(gi.Body, -1) {
+			for _, n := range githubRefNumbers(m[1]) {
+				g.link(githubID(n), id, model.DepBlocks)
+			}
+		}
+		for _, m := range githubParentRef.FindAllStringSubmatch(gi.Body, -1) {
+			n, _ := strconv.Atoi(m[1])
+			g.link(id, githubID(n), model.DepParentChild)
+		}
+		for _, m := range githubTaskRef.FindAllStringSubmatch(gi.Body, -1) {
+			n, _ := strconv.Atoi(m[1])
+			g.link(githubID(n), id, model.DepParentChild)
+		}
+
+		g.add(issue)
+	}
+	return g.build(), nil
+}
+
+func githubID(number int) string {
+	return githubIDPrefix + strconv.Itoa(number)
+}
+
+func githubRefNumbers(s string) []int {
+	var out []int
+	for _, m := range githubIssueRef.FindAllStringSubmatch(s, -1) {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// statusFromLabel maps workflow labels to beads statuses for open issues.
+func statusFromLabel(label string) (model.Status, bool) {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "in progress", "in-progress", "wip", "status: in progress":
+		return model.StatusInProgress, true
+	case "blocked", "status: blocked":
+		return model.StatusBlocked, true
+	case "in review", "needs review", "review", "status: in review":
+		return model.StatusReview, true
+	}
+	return "", false
+}
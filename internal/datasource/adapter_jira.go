@@ -0,0 +1,177 @@
+package datasource
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// jiraSource imports Jira's "Export CSV (all fields)". Jira repeats a column
+// header once per value for multi-valued fields (Labels, issue links), so
+// columns are looked up by name and every matching column is read.
+type jiraSource struct{}
+
+func (jiraSource) Name() string     { return "jira" }
+func (jiraSource) Type() SourceType { return SourceTypeJiraCSV }
+
+func (jiraSource) Detect(path string, head []byte) bool {
+	firstLine, _, _ := strings.Cut(string(head), "\n")
+	if strings.Contains(firstLine, "Issue key") && strings.Contains(firstLine, "Summary") {
+		return true
+	}
+	return strings.EqualFold(filepath.Ext(path), ".csv") && strings.Contains(firstLine, "Summary")
+}
+
+type jiraColumns map[string][]int
+
+func (c jiraColumns) get(row []string, name string) string {
+	for _, i := range c[name] {
+		if i < len(row) {
+			if v := strings.TrimSpace(row[i]); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func (c jiraColumns) all(row []string, name string) []string {
+	var out []string
+	for _, i := range c[name] {
+		if i < len(row) {
+			if v := strings.TrimSpace(row[i]); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+func (jiraSource) Import(r io.Reader) ([]model.Issue, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading Jira CSV header: %w", err)
+	}
+	cols := make(jiraColumns, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\xEF\xBB\xBF"))
+		cols[h] = append(cols[h], i)
+	}
+	if len(cols["Issue key"]) == 0 {
+		return nil, fmt.Errorf("missing \"Issue key\" column in Jira CSV header")
+	}
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading Jira CSV: %w", err)
+	}
+
+	// Newer exports reference parents by numeric issue id rather than key.
+	keyByID := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if id, key := cols.get(row, "Issue id"), cols.get(row, "Issue key"); id != "" && key != "" {
+			keyByID[id] = key
+		}
+	}
+	resolve := func(ref string) string {
+		if key, ok := keyByID[ref]; ok {
+			return key
+		}
+		return ref
+	}
+
+	g := newImportGraph()
+	for _, row := range rows {
+		key := cols.get(row, "Issue key")
+		if key == "" {
+			continue
+		}
+		issue := model.Issue{
+			ID:          key,
+			Title:       cols.get(row, "Summary"),
+			Description: cols.get(row, "Description"),
+			Status:      jiraStatus(cols.get(row, "Status Category"), cols.get(row, "Status")),
+			Priority:    2,
+			Assignee:    cols.get(row, "Assignee"),
+			CreatedAt:   parseImportTime(cols.get(row, "Created")),
+			UpdatedAt:   parseImportTime(cols.get(row, "Updated")),
+			ClosedAt:    timePtr(parseImportTime(cols.get(row, "Resolved"))),
+			DueDate:     timePtr(parseImportTime(cols.get(row, "Due Date"))),
+			Labels:      cols.all(row, "Labels"),
+		}
+		if p, ok := priorityFromName(cols.get(row, "Priority")); ok {
+			issue.Priority = p
+		}
+		if t, ok := issueTypeFromName(cols.get(row, "Issue Type")); ok {
+			issue.IssueType = t
+		}
+		for _, c := range cols.all(row, "Component/s") {
+			issue.Labels = append(issue.Labels, "component:"+c)
+		}
+		if secs, err := strconv.Atoi(cols.get(row, "Original Estimate")); err == nil && secs > 0 {
+			minutes := secs / 60
+			issue.EstimatedMinutes = &minutes
+		}
+
+		// "Inward issue link (Blocks)" lists issues that block this one.
+		for _, ref := range cols.all(row, "Inward issue link (Blocks)") {
+			g.link(key, ref, model.DepBlocks)
+		}
+		for _, ref := range cols.all(row, "Outward issue link (Blocks)") {
+			g.link(ref, key, model.DepBlocks)
+		}
+		for _, ref := range cols.all(row, "Outward issue link (Relates)") {
+			g.link(key, ref, model.DepRelated)
+		}
+		for _, name := range []string{"Parent", "Parent id", "Parent key", "Custom field (Epic Link)"} {
+			if parent := cols.get(row, name); parent != "" {
+				g.link(key, resolve(parent), model.DepParentChild)
+				break
+			}
+		}
+
+		g.add(issue)
+	}
+	return g.build(), nil
+}
+
+// jiraStatus prefers the status category, which is stable across workflows,
+// and falls back to common status names.
+func jiraStatus(category, status string) model.Status {
+	switch strings.ToLower(category) {
+	case "done":
+		return model.StatusClosed
+	case "in progress":
+		if strings.Contains(strings.ToLower(status), "review") {
+			return model.StatusReview
+		}
+		return model.StatusInProgress
+	case "to do", "new":
+		if strings.EqualFold(status, "blocked") {
+			return model.StatusBlocked
+		}
+		return model.StatusOpen
+	}
+
+	s := strings.ToLower(status)
+	switch {
+	case s == "done" || s == "closed" || s == "resolved" || s == "won't do" || s == "cancelled" || s == "canceled":
+		return model.StatusClosed
+	case strings.Contains(s, "review"):
+		return model.StatusReview
+	case s == "blocked":
+		return model.StatusBlocked
+	case strings.Contains(s, "progress"):
+		return model.StatusInProgress
+	}
+	return model.StatusOpen
+}
@@ -0,0 +1,230 @@
+package datasource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// linearSource imports Linear issues as JSON: either a plain array of issue
+// objects or a GraphQL response of the form {"data":{"issues":{"nodes":[...]}}}.
+// Connections (labels, relations) may be given as arrays or as
+// {"nodes": [...]} objects.
+type linearSource struct{}
+
+func (linearSource) Name() string     { return "linear" }
+func (linearSource) Type() SourceType { return SourceTypeLinearJSON }
+
+func (linearSource) Detect(path string, head []byte) bool {
+	trimmed := bytes.TrimSpace(head)
+	if len(trimmed) == 0 || (trimmed[0] != '[' && trimmed[0] != '{') {
+		return false
+	}
+	return bytes.Contains(trimmed, []byte(`"identifier"`))
+}
+
+// linearNodes decodes a GraphQL connection or a plain array.
+type linearNodes[T any] []T
+
+func (n *linearNodes[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, (*[]T)(n))
+	}
+	var conn struct {
+		Nodes []T `json:"nodes"`
+	}
+	if err := json.Unmarshal(data, &conn); err != nil {
+		return err
+	}
+	*n = conn.Nodes
+	return nil
+}
+
+type linearRef struct {
+	Identifier string `json:"identifier"`
+}
+
+type linearRelation struct {
+	Type         string     `json:"type"`
+	Issue        *linearRef `json:"issue"`
+	RelatedIssue *linearRef `json:"relatedIssue"`
+}
+
+type linearIssue struct {
+	Identifier  string   `json:"identifier"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority"`
+	Estimate    *float64 `json:"estimate"`
+	URL         string   `json:"url"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	CompletedAt string   `json:"completedAt"`
+	CanceledAt  string   `json:"canceledAt"`
+	DueDate     string   `json:"dueDate"`
+	State       *struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Assignee *struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+		Email       string `json:"email"`
+	} `json:"assignee"`
+	Labels linearNodes[struct {
+		Name string `json:"name"`
+	}] `json:"labels"`
+	Project *struct {
+		Name string `json:"name"`
+	} `json:"project"`
+	Parent           *linearRef                  `json:"parent"`
+	Relations        linearNodes[linearRelation] `json:"relations"`
+	InverseRelations linearNodes[linearRelation] `json:"inverseRelations"`
+}
+
+func (linearSource) Import(r io.Reader) ([]model.Issue, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var raw []linearIssue
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		var wrapped struct {
+			Data struct {
+				Issues linearNodes[linearIssue] `json:"issues"`
+			} `json:"data"`
+			Issues linearNodes[linearIssue] `json:"issues"`
+		}
+		err = json.Unmarshal(data, &wrapped)
+		raw = wrapped.Data.Issues
+		if len(raw) == 0 {
+			raw = wrapped.Issues
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding Linear issues JSON: %w", err)
+	}
+
+	g := newImportGraph()
+	for _, li := range raw {
+		if li.Identifier == "" {
+			continue
+		}
+		id := li.Identifier
+		issue := model.Issue{
+			ID:          id,
+			Title:       li.Title,
+			Description: li.Description,
+			Status:      model.StatusOpen,
+			Priority:    linearPriority(li.Priority),
+			CreatedAt:   parseImportTime(li.CreatedAt),
+			UpdatedAt:   parseImportTime(li.UpdatedAt),
+			DueDate:     timePtr(parseImportTime(li.DueDate)),
+			ExternalRef: stringPtr(li.URL),
+		}
+		if li.State != nil {
+			issue.Status = linearStatus(li.State.Type, li.State.Name)
+		}
+		if closed := parseImportTime(li.CompletedAt); !closed.IsZero() {
+			issue.ClosedAt = &closed
+		} else if canceled := parseImportTime(li.CanceledAt); !canceled.IsZero() {
+			issue.ClosedAt = &canceled
+		}
+		if li.Assignee != nil {
+			switch {
+			case li.Assignee.DisplayName != "":
+				issue.Assignee = li.Assignee.DisplayName
+			case li.Assignee.Name != "":
+				issue.Assignee = li.Assignee.Name
+			default:
+				issue.Assignee = li.Assignee.Email
+			}
+		}
+		for _, l := range li.Labels {
+			issue.Labels = append(issue.Labels, l.Name)
+			if t, ok := issueTypeFromName(l.Name); ok && issue.IssueType == "" {
+				issue.IssueType = t
+			}
+		}
+		if li.Project != nil && li.Project.Name != "" {
+			issue.Labels = append(issue.Labels, "project:"+li.Project.Name)
+		}
+		if li.Estimate != nil && *li.Estimate > 0 {
+			// Linear estimates are points; treat one point as an hour.
+			minutes := int(*li.Estimate * 60)
+			issue.EstimatedMinutes = &minutes
+		}
+
+		if li.Parent != nil {
+			g.link(id, li.Parent.Identifier, model.DepParentChild)
+		}
+		for _, rel := range li.Relations {
+			linearLink(g, id, rel)
+		}
+		for _, rel := range li.InverseRelations {
+			linearLink(g, id, rel)
+		}
+
+		g.add(issue)
+	}
+	return g.build(), nil
+}
+
+// linearLink records a relation attached to issue self. A missing side of
+// the relation refers to self.
+func linearLink(g *importGraph, self string, rel linearRelation) {
+	from, to := self, self
+	if rel.Issue != nil && rel.Issue.Identifier != "" {
+		from = rel.Issue.Identifier
+	}
+	if rel.RelatedIssue != nil && rel.RelatedIssue.Identifier != "" {
+		to = rel.RelatedIssue.Identifier
+	}
+	switch rel.Type {
+	case "blocks":
+		// from blocks to, so to depends on from.
+		g.link(to, from, model.DepBlocks)
+	case "related", "duplicate":
+		g.link(from, to, model.DepRelated)
+	}
+}
+
+// linearPriority maps Linear's 0 (none), 1 (urgent) ... 4 (low) scale.
+func linearPriority(p int) int {
+	switch p {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	case 3:
+		return 2
+	case 4:
+		return 3
+	}
+	return 2
+}
+
+func linearStatus(stateType, name string) model.Status {
+	switch strings.ToLower(stateType) {
+	case "completed", "canceled":
+		return model.StatusClosed
+	case "started":
+		if strings.Contains(strings.ToLower(name), "review") {
+			return model.StatusReview
+		}
+		return model.StatusInProgress
+	}
+	if strings.EqualFold(name, "blocked") {
+		return model.StatusBlocked
+	}
+	return model.StatusOpen
+}
@@ -0,0 +1,175 @@
+package datasource
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func importFixture(t *testing.T, name, format string) (map[string]model.Issue, Source) {
+	t.Helper()
+	issues, src, err := ImportFile(filepath.Join("testdata", name), format)
+	if err != nil {
+		t.Fatalf("ImportFile(%s): %v", name, err)
+	}
+	byID := make(map[string]model.Issue, len(issues))
+	for _, issue := range issues {
+		if err := issue.Validate(); err != nil {
+			t.Errorf("%s: imported issue %s is invalid: %v", name, issue.ID, err)
+		}
+		byID[issue.ID] = issue
+	}
+	return byID, src
+}
+
+// depsOf renders an issue's dependencies as sorted "type:target" strings.
+func depsOf(issue model.Issue) string {
+	var out []string
+	for _, d := range issue.Dependencies {
+		out = append(out, string(d.Type)+":"+d.DependsOnID)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+func TestDetectSource(t *testing.T) {
+	cases := map[string]string{
+		"github_issues.json": "github",
+		"jira_export.csv":    "jira",
+		"linear_issues.json": "linear",
+	}
+	for file, want := range cases {
+		src, err := DetectSource(filepath.Join("testdata", file))
+		if err != nil {
+			t.Errorf("DetectSource(%s): %v", file, err)
+			continue
+		}
+		if src.Name() != want {
+			t.Errorf("DetectSource(%s) = %s, want %s", file, src.Name(), want)
+		}
+	}
+
+	if _, _, err := ImportFile(filepath.Join("testdata", "github_issues.json"), "trello"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestGitHubSource_Import(t *testing.T) {
+	issues, src := importFixture(t, "github_issues.json", "auto")
+	if src.Type() != SourceTypeGitHubJSON || len(issues) != 4 {
+		t.Fatalf("unexpected import: %s, %d issues", src.Type(), len(issues))
+	}
+
+	epic := issues["gh-1"]
+	if epic.IssueType != model.TypeEpic || epic.Priority != 1 || epic.ExternalRef == nil {
+		t.Errorf("unexpected epic: %+v", epic)
+	}
+	if strings.Join(epic.Labels, ",") != "epic,P1,milestone:v2" {
+		t.Errorf("unexpected epic labels: %v", epic.Labels)
+	}
+
+	bug := issues["gh-2"]
+	if bug.IssueType != model.TypeBug || bug.Priority != 1 || bug.Status != model.StatusInProgress || bug.Assignee != "carol" {
+		t.Errorf("unexpected bug mapping: %+v", bug)
+	}
+	// #9 is not in the export and is dropped.
+	if got := depsOf(bug); got != "blocks:gh-3,parent-child:gh-1" {
+		t.Errorf("gh-2 deps = %s", got)
+	}
+	if len(bug.Comments) != 1 || bug.Comments[0].Author != "alice" {
+		t.Errorf("unexpected comments: %+v", bug.Comments)
+	}
+
+	closed := issues["gh-3"]
+	if closed.Status != model.StatusClosed || closed.ClosedAt == nil || closed.IssueType != model.TypeFeature {
+		t.Errorf("unexpected closed issue: %+v", closed)
+	}
+	if got := depsOf(issues["gh-4"]); got != "blocks:gh-3" {
+		t.Errorf("gh-4 deps = %s", got)
+	}
+	if issues["gh-4"].Title != "gh-4" {
+		t.Errorf("empty title should fall back to the ID, got %q", issues["gh-4"].Title)
+	}
+}
+
+func TestJiraSource_Import(t *testing.T) {
+	issues, src := importFixture(t, "jira_export.csv", "")
+	if src.Type() != SourceTypeJiraCSV || len(issues) != 4 {
+		t.Fatalf("unexpected import: %s, %d issues", src.Type(), len(issues))
+	}
+
+	story := issues["PAY-2"]
+	if story.Status != model.StatusReview || story.IssueType != model.TypeFeature || story.Priority != 2 {
+		t.Errorf("unexpected story mapping: %+v", story)
+	}
+	if !strings.Contains(story.Description, `"ledger_v2"`) || !strings.Contains(story.Description, "\nthen backfill.") {
+		t.Errorf("multi-line quoted description not preserved: %q", story.Description)
+	}
+	if strings.Join(story.Labels, ",") != "payments,backend,component:Billing" {
+		t.Errorf("unexpected labels: %v", story.Labels)
+	}
+	if story.EstimatedMinutes == nil || *story.EstimatedMinutes != 120 || story.DueDate == nil {
+		t.Errorf("estimate/due date not mapped: %+v", story)
+	}
+	if got := depsOf(story); got != "blocks:PAY-3,parent-child:PAY-1,related:PAY-4" {
+		t.Errorf("PAY-2 deps = %s", got)
+	}
+	if story.CreatedAt.Format("2006-01-02 15:04") != "2024-03-02 09:15" {
+		t.Errorf("unexpected created time %v", story.CreatedAt)
+	}
+
+	if done := issues["PAY-3"]; done.Status != model.StatusClosed || done.ClosedAt == nil || done.Priority != 3 {
+		t.Errorf("unexpected done mapping: %+v", done)
+	}
+	if bug := issues["PAY-4"]; bug.Status != model.StatusBlocked || bug.Priority != 0 || len(bug.Dependencies) != 0 {
+		t.Errorf("unexpected bug mapping: %+v", bug)
+	}
+}
+
+func TestLinearSource_Import(t *testing.T) {
+	issues, src := importFixture(t, "linear_issues.json", "linear")
+	if src.Type() != SourceTypeLinearJSON || len(issues) != 3 {
+		t.Fatalf("unexpected import: %s, %d issues", src.Type(), len(issues))
+	}
+
+	parent := issues["ENG-10"]
+	if parent.Status != model.StatusInProgress || parent.Priority != 1 || parent.Assignee != "alice" || parent.IssueType != model.TypeFeature {
+		t.Errorf("unexpected parent mapping: %+v", parent)
+	}
+	if strings.Join(parent.Labels, ",") != "Feature,project:Growth" {
+		t.Errorf("unexpected labels: %v", parent.Labels)
+	}
+
+	done := issues["ENG-11"]
+	if done.Status != model.StatusClosed || done.Priority != 0 || done.IssueType != model.TypeBug || done.EstimatedMinutes == nil {
+		t.Errorf("unexpected done mapping: %+v", done)
+	}
+	if got := depsOf(issues["ENG-12"]); got != "blocks:ENG-10,parent-child:ENG-10" {
+		t.Errorf("ENG-12 deps = %s", got)
+	}
+	if got := depsOf(issues["ENG-11"]); got != "parent-child:ENG-10,related:ENG-12" {
+		t.Errorf("ENG-11 deps = %s", got)
+	}
+}
+
+// Imported issues must be usable by the analysis pipeline as-is.
+func TestImportedIssues_Analyze(t *testing.T) {
+	issues, _, err := ImportFile(filepath.Join("testdata", "jira_export.csv"), "jira")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := analysis.NewAnalyzer(issues).Analyze()
+	if stats.NodeCount != len(issues) {
+		t.Errorf("NodeCount = %d, want %d", stats.NodeCount, len(issues))
+	}
+
+	src := DataSource{Type: SourceTypeJiraCSV, Path: filepath.Join("testdata", "jira_export.csv")}
+	loaded, err := LoadFromSource(src)
+	if err != nil || len(loaded) != len(issues) {
+		t.Errorf("LoadFromSource = %d issues, %v", len(loaded), err)
+	}
+}
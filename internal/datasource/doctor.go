@@ -0,0 +1,642 @@
+package datasource
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/instance"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DoctorSeverity grades a doctor finding. Errors are problems the loader
+// drops or misreads; warnings are inconsistencies bv tolerates.
+type DoctorSeverity string
+
+const (
+	DoctorError   DoctorSeverity = "error"
+	DoctorWarning DoctorSeverity = "warning"
+)
+
+// Doctor check identifiers, as reported in DoctorFinding.Check.
+const (
+	CheckParseError           = "parse_error"
+	CheckDuplicateID          = "duplicate_id"
+	CheckSelfDependency       = "self_dependency"
+	CheckDanglingDependency   = "dangling_dependency"
+	CheckParentCycle          = "parent_cycle"
+	CheckUpdatedBeforeCreated = "updated_before_created"
+	CheckClosedWithoutTime    = "closed_without_closed_at"
+	CheckUnknownStatus        = "unknown_status"
+)
+
+// Doctor fix actions, as reported in DoctorFix.Action.
+const (
+	FixSetField         = "set_field"
+	FixRemoveDependency = "remove_dependency"
+	FixDropLine         = "drop_line"
+)
+
+// DoctorFix is one machine-applicable repair to a JSONL line.
+type DoctorFix struct {
+	Action      string `json:"action"`
+	Source      string `json:"source"`
+	Line        int    `json:"line"`
+	IssueID     string `json:"issue_id,omitempty"`
+	Field       string `json:"field,omitempty"`
+	Value       any    `json:"value,omitempty"`
+	DependsOnID string `json:"depends_on_id,omitempty"`
+	DepType     string `json:"dep_type,omitempty"`
+}
+
+// DoctorFinding is a single integrity problem. Line is 1-based and omitted
+// for SQLite sources.
+type DoctorFinding struct {
+	Check    string         `json:"check"`
+	Severity DoctorSeverity `json:"severity"`
+	Source   string         `json:"source"`
+	Line     int            `json:"line,omitempty"`
+	IssueID  string         `json:"issue_id,omitempty"`
+	Message  string         `json:"message"`
+	Fix      *DoctorFix     `json:"fix,omitempty"`
+}
+
+// DoctorSummary counts findings by severity.
+type DoctorSummary struct {
+	Sources  int `json:"sources"`
+	Issues   int `json:"issues"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Fixable  int `json:"fixable"`
+}
+
+// DoctorReport is the result of Diagnose. FixPlan lists the Fix of every
+// fixable finding in application order.
+type DoctorReport struct {
+	Sources  []DataSource    `json:"sources"`
+	Findings []DoctorFinding `json:"findings"`
+	FixPlan  []DoctorFix     `json:"fix_plan"`
+	Summary  DoctorSummary   `json:"summary"`
+}
+
+// HasErrors reports whether any finding is an error.
+func (r *DoctorReport) HasErrors() bool {
+	return r.Summary.Errors > 0
+}
+
+// doctorRecord is one issue as it appears in a source.
+type doctorRecord struct {
+	issue model.Issue
+	line  int
+}
+
+// DiagnoseDir checks every SQLite and JSONL source in beadsDir. Worktree
+// copies are skipped: they are expected to diverge from the main checkout.
+func DiagnoseDir(beadsDir string) (*DoctorReport, error) {
+	sources, err := DiscoverSources(DiscoveryOptions{
+		BeadsDir:               beadsDir,
+		RepoPath:               beadsDir,
+		ValidateAfterDiscovery: true,
+		IncludeInvalid:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var local []DataSource
+	for _, s := range sources {
+		if s.Type != SourceTypeJSONLWorktree {
+			local = append(local, s)
+		}
+	}
+	if len(local) == 0 {
+		return nil, fmt.Errorf("no beads data found in %s", beadsDir)
+	}
+	return Diagnose(local)
+}
+
+// Diagnose checks each source on its own and then compares issue IDs across
+// sources.
+func Diagnose(sources []DataSource) (*DoctorReport, error) {
+	report := &DoctorReport{Sources: sources, Findings: []DoctorFinding{}, FixPlan: []DoctorFix{}}
+	perSource := make([]map[string]doctorRecord, len(sources))
+
+	for i, src := range sources {
+		records, parseFindings, err := readDoctorRecords(src)
+		if err != nil {
+			return nil, err
+		}
+		report.Findings = append(report.Findings, parseFindings...)
+		report.Summary.Issues += len(records)
+		var findings []DoctorFinding
+		perSource[i], findings = diagnoseSource(src, records)
+		report.Findings = append(report.Findings, findings...)
+	}
+	report.Findings = append(report.Findings, diagnoseAcrossSources(sources, perSource)...)
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Check < b.Check
+	})
+
+	report.Summary.Sources = len(sources)
+	for _, f := range report.Findings {
+		switch f.Severity {
+		case DoctorError:
+			report.Summary.Errors++
+		case DoctorWarning:
+			report.Summary.Warnings++
+		}
+		if f.Fix != nil {
+			report.FixPlan = append(report.FixPlan, *f.Fix)
+			report.Summary.Fixable++
+		}
+	}
+	return report, nil
+}
+
+// readDoctorRecords reads a source without the loader's validation so that
+// issues the loader would drop are still checked.
+func readDoctorRecords(src DataSource) ([]doctorRecord, []DoctorFinding, error) {
+	switch src.Type {
+	case SourceTypeSQLite:
+		reader, err := NewSQLiteReader(src)
+		if err != nil {
+			return nil, []DoctorFinding{{
+				Check:    CheckParseError,
+				Severity: DoctorError,
+				Source:   src.Path,
+				Message:  fmt.Sprintf("cannot open database: %v", err),
+			}}, nil
+		}
+		defer reader.Close()
+		issues, err := reader.LoadIssues()
+		if err != nil {
+			return nil, []DoctorFinding{{
+				Check:    CheckParseError,
+				Severity: DoctorError,
+				Source:   src.Path,
+				Message:  fmt.Sprintf("cannot read issues: %v", err),
+			}}, nil
+		}
+		records := make([]doctorRecord, len(issues))
+		for i := range issues {
+			records[i] = doctorRecord{issue: issues[i]}
+		}
+		return records, nil, nil
+
+	case SourceTypeJSONLLocal, SourceTypeJSONLWorktree:
+		f, err := os.Open(src.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		return readDoctorJSONL(f, src.Path)
+	}
+	return nil, nil, fmt.Errorf("doctor does not support source type %s", src.Type)
+}
+
+func readDoctorJSONL(r io.Reader, path string) ([]doctorRecord, []DoctorFinding, error) {
+	var records []doctorRecord
+	var findings []DoctorFinding
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, readErr := reader.ReadBytes('\n')
+		if lineNum == 1 {
+			line = bytes.TrimPrefix(line, utf8BOM)
+		}
+		if body := bytes.TrimSpace(line); len(body) > 0 {
+			var issue model.Issue
+			if err := json.Unmarshal(body, &issue); err != nil {
+				findings = append(findings, DoctorFinding{
+					Check:    CheckParseError,
+					Severity: DoctorError,
+					Source:   path,
+					Line:     lineNum,
+					Message:  fmt.Sprintf("invalid JSON: %v", err),
+				})
+			} else if issue.ID == "" {
+				findings = append(findings, DoctorFinding{
+					Check:    CheckParseError,
+					Severity: DoctorError,
+					Source:   path,
+					Line:     lineNum,
+					Message:  "issue has no id",
+				})
+			} else {
+				records = append(records, doctorRecord{issue: issue, line: lineNum})
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", path, readErr)
+		}
+	}
+	return records, findings, nil
+}
+
+// diagnoseSource runs the per-issue and graph checks for one source and
+// returns the record kept for each ID.
+func diagnoseSource(src DataSource, records []doctorRecord) (map[string]doctorRecord, []DoctorFinding) {
+	var findings []DoctorFinding
+	fixable := src.Type != SourceTypeSQLite
+	finding := func(rec doctorRecord, check string, sev DoctorSeverity, msg string, fix *DoctorFix) {
+		if fix != nil {
+			if !fixable {
+				fix = nil
+			} else {
+				fix.Source, fix.Line, fix.IssueID = src.Path, rec.line, rec.issue.ID
+			}
+		}
+		findings = append(findings, DoctorFinding{
+			Check:    check,
+			Severity: sev,
+			Source:   src.Path,
+			Line:     rec.line,
+			IssueID:  rec.issue.ID,
+			Message:  msg,
+			Fix:      fix,
+		})
+	}
+
+	// Duplicates: keep the most recently updated copy (the later line on a
+	// tie) and drop the others.
+	byID := make(map[string]doctorRecord, len(records))
+	for _, rec := range records {
+		prev, dup := byID[rec.issue.ID]
+		if !dup {
+			byID[rec.issue.ID] = rec
+			continue
+		}
+		keep, drop := rec, prev
+		if prev.issue.UpdatedAt.After(rec.issue.UpdatedAt) {
+			keep, drop = prev, rec
+		}
+		byID[rec.issue.ID] = keep
+		where := "another row"
+		if keep.line > 0 {
+			where = fmt.Sprintf("line %d", keep.line)
+		}
+		finding(drop, CheckDuplicateID, DoctorError,
+			fmt.Sprintf("%s is also defined at %s, which is newer", drop.issue.ID, where),
+			&DoctorFix{Action: FixDropLine})
+	}
+
+	for _, rec := range records {
+		if byID[rec.issue.ID].line != rec.line {
+			continue // dropped duplicate, already reported
+		}
+		issue := rec.issue
+		status := model.Status(strings.ToLower(strings.TrimSpace(string(issue.Status))))
+		if !status.IsValid() {
+			fixed := model.StatusOpen
+			if issue.ClosedAt != nil {
+				fixed = model.StatusClosed
+			}
+			finding(rec, CheckUnknownStatus, DoctorError,
+				fmt.Sprintf("unknown status %q; the loader skips this issue", issue.Status),
+				&DoctorFix{Action: FixSetField, Field: "status", Value: string(fixed)})
+		}
+		if status == model.StatusClosed && issue.ClosedAt == nil {
+			var fix *DoctorFix
+			if closed := closedAtFallback(issue); !closed.IsZero() {
+				fix = &DoctorFix{Action: FixSetField, Field: "closed_at", Value: closed}
+			}
+			finding(rec, CheckClosedWithoutTime, DoctorWarning, "closed issue has no closed_at", fix)
+		}
+		if !issue.UpdatedAt.IsZero() && !issue.CreatedAt.IsZero() && issue.UpdatedAt.Before(issue.CreatedAt) {
+			finding(rec, CheckUpdatedBeforeCreated, DoctorError,
+				fmt.Sprintf("updated_at %s is before created_at %s; the loader skips this issue",
+					issue.UpdatedAt.Format(time.RFC3339), issue.CreatedAt.Format(time.RFC3339)),
+				&DoctorFix{Action: FixSetField, Field: "updated_at", Value: issue.CreatedAt})
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil {
+				continue
+			}
+			removal := &DoctorFix{Action: FixRemoveDependency, DependsOnID: dep.DependsOnID, DepType: string(dep.Type)}
+			_, known := byID[dep.DependsOnID]
+			switch {
+			case dep.DependsOnID == issue.ID:
+				finding(rec, CheckSelfDependency, DoctorError,
+					fmt.Sprintf("%s depends on itself", issue.ID), removal)
+			case !known:
+				finding(rec, CheckDanglingDependency, DoctorWarning,
+					fmt.Sprintf("%s depends on unknown issue %q", issue.ID, dep.DependsOnID), removal)
+			}
+		}
+	}
+
+	for _, cyc := range parentCycles(byID) {
+		rec := byID[cyc[len(cyc)-1]]
+		finding(rec, CheckParentCycle, DoctorError,
+			fmt.Sprintf("parent-child cycle (child -> parent): %s", strings.Join(append(cyc, cyc[0]), " -> ")),
+			&DoctorFix{Action: FixRemoveDependency, DependsOnID: cyc[0], DepType: string(model.DepParentChild)})
+	}
+	return byID, findings
+}
+
+// closedAtFallback picks the best available timestamp for a closed issue
+// that lacks closed_at.
+func closedAtFallback(issue model.Issue) time.Time {
+	if !issue.UpdatedAt.IsZero() {
+		return issue.UpdatedAt
+	}
+	return issue.CreatedAt
+}
+
+// parentCycles returns each cycle in the child -> parent graph once, as the
+// list of IDs along it. The edge from the last ID back to the first is the
+// one that closes the cycle.
+func parentCycles(byID map[string]doctorRecord) [][]string {
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parents := func(id string) []string {
+		var out []string
+		for _, dep := range byID[id].issue.Dependencies {
+			if dep != nil && dep.Type == model.DepParentChild && dep.DependsOnID != id {
+				if _, ok := byID[dep.DependsOnID]; ok {
+					out = append(out, dep.DependsOnID)
+				}
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int, len(ids))
+	var stack []string
+	var cycles [][]string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = onStack
+		stack = append(stack, id)
+		for _, p := range parents(id) {
+			switch state[p] {
+			case unvisited:
+				visit(p)
+			case onStack:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == p {
+						cycles = append(cycles, append([]string(nil), stack[i:]...))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// diagnoseAcrossSources reports IDs whose copies in different sources
+// disagree. Identical copies are expected (the JSONL export mirrors the
+// database) and are not reported.
+func diagnoseAcrossSources(sources []DataSource, perSource []map[string]doctorRecord) []DoctorFinding {
+	var findings []DoctorFinding
+	for i := 1; i < len(sources); i++ {
+		ids := make([]string, 0, len(perSource[i]))
+		for id := range perSource[i] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			rec := perSource[i][id]
+			for j := 0; j < i; j++ {
+				other, ok := perSource[j][id]
+				if !ok || sameIssueState(rec.issue, other.issue) {
+					continue
+				}
+				findings = append(findings, DoctorFinding{
+					Check:    CheckDuplicateID,
+					Severity: DoctorWarning,
+					Source:   sources[i].Path,
+					Line:     rec.line,
+					IssueID:  id,
+					Message: fmt.Sprintf("%s differs from the copy in %s (status %s vs %s, updated %s vs %s)",
+						id, sources[j].Path, rec.issue.Status, other.issue.Status,
+						rec.issue.UpdatedAt.Format(time.RFC3339), other.issue.UpdatedAt.Format(time.RFC3339)),
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func sameIssueState(a, b model.Issue) bool {
+	return a.Status == b.Status && a.UpdatedAt.Equal(b.UpdatedAt) && a.Title == b.Title
+}
+
+// DoctorFixResult describes the files rewritten by ApplyDoctorFixes.
+type DoctorFixResult struct {
+	Path    string `json:"path"`
+	Backup  string `json:"backup"`
+	Applied int    `json:"applied"`
+}
+
+// ApplyDoctorFixes rewrites each JSONL file named in plan. Lines without a
+// fix are copied byte-for-byte; patched lines keep every key the fix does
+// not touch. The original file is kept next to it with a ".backup" suffix.
+func ApplyDoctorFixes(plan []DoctorFix) ([]DoctorFixResult, error) {
+	byPath := make(map[string]map[int][]DoctorFix)
+	var paths []string
+	for _, fix := range plan {
+		if fix.Line <= 0 {
+			continue
+		}
+		if byPath[fix.Source] == nil {
+			byPath[fix.Source] = make(map[int][]DoctorFix)
+			paths = append(paths, fix.Source)
+		}
+		byPath[fix.Source][fix.Line] = append(byPath[fix.Source][fix.Line], fix)
+	}
+	sort.Strings(paths)
+
+	var results []DoctorFixResult
+	for _, path := range paths {
+		applied, err := applyDoctorFixesToFile(path, byPath[path])
+		if err != nil {
+			return results, err
+		}
+		results = append(results, DoctorFixResult{Path: path, Backup: path + ".backup", Applied: applied})
+	}
+	return results, nil
+}
+
+func applyDoctorFixesToFile(path string, fixes map[int][]DoctorFix) (int, error) {
+	// Hold the same lock as ApplyMutation so a concurrent write-back is
+	// neither lost nor overwritten.
+	lock, err := instance.AcquireWriteLock(filepath.Dir(path), 0)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var out bytes.Buffer
+	applied := 0
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		lineFixes := fixes[i+1]
+		if len(lineFixes) == 0 {
+			out.Write(line)
+			continue
+		}
+		patched, n, err := patchDoctorLine(line, i == 0, lineFixes)
+		if err != nil {
+			return 0, fmt.Errorf("%s line %d: %w", path, i+1, err)
+		}
+		out.Write(patched)
+		applied += n
+	}
+	if applied == 0 {
+		return 0, nil
+	}
+
+	if err := os.WriteFile(path+".backup", data, info.Mode().Perm()); err != nil {
+		return 0, fmt.Errorf("writing backup: %w", err)
+	}
+	pending, err := prepareJSONLWrite(path, out.Bytes(), info.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+	defer pending.abort()
+	if err := pending.commit(); err != nil {
+		return 0, err
+	}
+	return applied, nil
+}
+
+// patchDoctorLine applies fixes to one line and returns the new bytes (empty
+// when the line is dropped) and the number of fixes applied.
+func patchDoctorLine(line []byte, first bool, fixes []DoctorFix) ([]byte, int, error) {
+	for _, fix := range fixes {
+		if fix.Action == FixDropLine {
+			return nil, 1, nil
+		}
+	}
+
+	body := bytes.TrimRight(line, "\r\n")
+	ending := line[len(body):]
+	var prefix []byte
+	if first && bytes.HasPrefix(body, utf8BOM) {
+		prefix = utf8BOM
+		body = body[len(utf8BOM):]
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, 0, err
+	}
+
+	applied := 0
+	for _, fix := range fixes {
+		switch fix.Action {
+		case FixSetField:
+			raw, err := marshalJSONLValue(fix.Value)
+			if err != nil {
+				return nil, 0, err
+			}
+			fields[fix.Field] = raw
+			applied++
+		case FixRemoveDependency:
+			deps, removed, err := removeRawDependency(fields["dependencies"], fix.DependsOnID, fix.DepType)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !removed {
+				continue
+			}
+			if deps == nil {
+				delete(fields, "dependencies")
+			} else {
+				fields["dependencies"] = deps
+			}
+			applied++
+		default:
+			return nil, 0, fmt.Errorf("unknown fix action %q", fix.Action)
+		}
+	}
+	if applied == 0 {
+		return line, 0, nil
+	}
+
+	out, err := marshalJSONLValue(fields)
+	if err != nil {
+		return nil, 0, err
+	}
+	patched := make([]byte, 0, len(prefix)+len(out)+len(ending))
+	patched = append(patched, prefix...)
+	patched = append(patched, out...)
+	patched = append(patched, ending...)
+	return patched, applied, nil
+}
+
+// removeRawDependency drops the entries of a raw dependencies array that
+// point at dependsOn (and have depType, when given), keeping the rest
+// verbatim.
+func removeRawDependency(raw json.RawMessage, dependsOn, depType string) (json.RawMessage, bool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return raw, false, nil
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, false, fmt.Errorf("dependencies: %w", err)
+	}
+	var kept []json.RawMessage
+	removed := false
+	for _, entry := range entries {
+		var dep model.Dependency
+		if err := json.Unmarshal(entry, &dep); err == nil && dep.DependsOnID == dependsOn &&
+			(depType == "" || string(dep.Type) == depType) {
+			removed = true
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if !removed {
+		return raw, false, nil
+	}
+	if len(kept) == 0 {
+		return nil, true, nil
+	}
+	out, err := marshalJSONLValue(kept)
+	return out, true, err
+}
@@ -0,0 +1,165 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// doctorFixture has one problem of each kind on a known line.
+var doctorFixture = []string{
+	`{"id":"A","title":"Root","status":"open","priority":1,"issue_type":"epic","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-02T00:00:00Z","x_team":"core"}`,
+	`{"id":"B","title":"Self","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"B","type":"blocks"},{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}`,
+	`{"id":"C","title":"Dangling","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"C","depends_on_id":"ghost","type":"blocks"}]}`,
+	`not json`,
+	`{"id":"D","title":"Closed","status":"closed","priority":2,"issue_type":"task","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-05T00:00:00Z"}`,
+	`{"id":"E","title":"Backwards","status":"open","priority":2,"issue_type":"task","created_at":"2025-02-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`,
+	`{"id":"F","title":"Weird","status":"someday","priority":2,"issue_type":"task"}`,
+	`{"id":"P1","title":"Parent","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"P1","depends_on_id":"P2","type":"parent-child"}]}`,
+	`{"id":"P2","title":"Child","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"P2","depends_on_id":"P1","type":"parent-child"}]}`,
+	`{"id":"A","title":"Root (old copy)","status":"open","priority":1,"issue_type":"epic","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`,
+}
+
+func findingsByCheck(report *DoctorReport) map[string][]DoctorFinding {
+	out := make(map[string][]DoctorFinding)
+	for _, f := range report.Findings {
+		out[f.Check] = append(out[f.Check], f)
+	}
+	return out
+}
+
+func TestDiagnoseDir_ReportsEachCheck(t *testing.T) {
+	beadsDir, path := writeMutationFixture(t, doctorFixture...)
+	report, err := DiagnoseDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		line     int
+		severity DoctorSeverity
+		fixable  bool
+	}{
+		CheckParseError:           {4, DoctorError, false},
+		CheckSelfDependency:       {2, DoctorError, true},
+		CheckDanglingDependency:   {3, DoctorWarning, true},
+		CheckClosedWithoutTime:    {5, DoctorWarning, true},
+		CheckUpdatedBeforeCreated: {6, DoctorError, true},
+		CheckUnknownStatus:        {7, DoctorError, true},
+		CheckParentCycle:          {9, DoctorError, true},
+		CheckDuplicateID:          {10, DoctorError, true},
+	}
+	got := findingsByCheck(report)
+	for check, w := range want {
+		fs := got[check]
+		if len(fs) != 1 {
+			t.Errorf("%s: got %d findings, want 1: %+v", check, len(fs), fs)
+			continue
+		}
+		f := fs[0]
+		if f.Line != w.line || f.Severity != w.severity || (f.Fix != nil) != w.fixable || f.Source != path {
+			t.Errorf("%s: got %+v, want line %d severity %s fixable %v", check, f, w.line, w.severity, w.fixable)
+		}
+	}
+	if len(report.Findings) != len(want) {
+		t.Errorf("got %d findings, want %d: %+v", len(report.Findings), len(want), report.Findings)
+	}
+	if report.Summary.Errors != 6 || report.Summary.Warnings != 2 || report.Summary.Fixable != 7 || len(report.FixPlan) != 7 {
+		t.Errorf("unexpected summary %+v (fix plan %d)", report.Summary, len(report.FixPlan))
+	}
+	if !strings.Contains(got[CheckParentCycle][0].Message, "P1 -> P2 -> P1") {
+		t.Errorf("cycle message = %q", got[CheckParentCycle][0].Message)
+	}
+}
+
+func TestApplyDoctorFixes_RepairsFile(t *testing.T) {
+	beadsDir, path := writeMutationFixture(t, doctorFixture...)
+	report, err := DiagnoseDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := ApplyDoctorFixes(report.FixPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Path != path || results[0].Applied != 7 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if backup, err := os.ReadFile(path + ".backup"); err != nil || string(backup) != strings.Join(doctorFixture, "\n")+"\n" {
+		t.Errorf("backup does not hold the original file (err=%v)", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != len(doctorFixture)-1 {
+		t.Fatalf("expected the stale duplicate to be dropped, got %d lines", len(lines))
+	}
+	// Untouched lines are copied verbatim, including unknown keys.
+	if lines[0] != doctorFixture[0] || lines[3] != doctorFixture[3] {
+		t.Errorf("untouched lines changed:\n%s\n%s", lines[0], lines[3])
+	}
+	for i, want := range map[int]string{
+		1: `"depends_on_id":"A"`,
+		4: `"closed_at":"2025-01-05T00:00:00Z"`,
+		5: `"updated_at":"2025-02-01T00:00:00Z"`,
+		6: `"status":"open"`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %s, want %s", i+1, lines[i], want)
+		}
+	}
+	if strings.Contains(lines[1], `"depends_on_id":"B"`) || strings.Contains(lines[2], "dependencies") {
+		t.Errorf("bad dependencies not removed:\n%s\n%s", lines[1], lines[2])
+	}
+
+	after, err := DiagnoseDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the unparseable line is left; it has no automatic fix.
+	if len(after.Findings) != 1 || after.Findings[0].Check != CheckParseError || len(after.FixPlan) != 0 {
+		t.Errorf("unexpected findings after fix: %+v", after.Findings)
+	}
+}
+
+func TestDiagnose_ComparesSourcesAndSkipsSQLiteFixes(t *testing.T) {
+	beadsDir, _ := writeMutationFixture(t,
+		`{"id":"A","title":"One","status":"open","priority":1,"issue_type":"task","updated_at":"2025-01-02T00:00:00Z"}`,
+		`{"id":"B","title":"Two","status":"open","priority":1,"issue_type":"task"}`,
+	)
+	other := filepath.Join(beadsDir, "issues.jsonl")
+	if err := os.WriteFile(other, []byte(strings.Join([]string{
+		`{"id":"A","title":"One","status":"closed","priority":1,"issue_type":"task","updated_at":"2025-01-03T00:00:00Z","closed_at":"2025-01-03T00:00:00Z"}`,
+		`{"id":"B","title":"Two","status":"open","priority":1,"issue_type":"task"}`,
+	}, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := DiagnoseDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dups := findingsByCheck(report)[CheckDuplicateID]
+	if len(dups) != 1 || dups[0].IssueID != "A" || dups[0].Severity != DoctorWarning || dups[0].Fix != nil {
+		t.Errorf("expected one divergent-copy warning for A, got %+v", dups)
+	}
+
+	src := DataSource{Type: SourceTypeSQLite, Path: "beads.db"}
+	_, findings := diagnoseSource(src, []doctorRecord{
+		{issue: mustDoctorIssue(t, `{"id":"X","title":"x","status":"bogus","priority":1,"issue_type":"task"}`)},
+	})
+	if len(findings) != 1 || findings[0].Fix != nil || findings[0].Line != 0 {
+		t.Errorf("SQLite findings should have no line or fix: %+v", findings)
+	}
+}
+
+func mustDoctorIssue(t *testing.T, line string) model.Issue {
+	t.Helper()
+	records, findings, err := readDoctorJSONL(strings.NewReader(line), "inline")
+	if err != nil || len(findings) != 0 || len(records) != 1 {
+		t.Fatalf("bad fixture %s: %v %+v", line, err, findings)
+	}
+	return records[0].issue
+}
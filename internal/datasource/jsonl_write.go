@@ -0,0 +1,454 @@
+package datasource
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// pendingJSONLWrite is a fully written temp file waiting to replace the
+// original JSONL via rename.
+type pendingJSONLWrite struct {
+	tmpPath string
+	path    string
+	done    bool
+}
+
+func (p *pendingJSONLWrite) commit() error {
+	if err := os.Rename(p.tmpPath, p.path); err != nil {
+		return fmt.Errorf("replacing %s: %w", p.path, err)
+	}
+	p.done = true
+	return nil
+}
+
+func (p *pendingJSONLWrite) abort() {
+	if !p.done {
+		os.Remove(p.tmpPath)
+	}
+}
+
+// prepareJSONLWrite writes data to a temp file next to path, ready to
+// replace it with perm.
+func prepareJSONLWrite(path string, data []byte, perm os.FileMode) (*pendingJSONLWrite, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	pending := &pendingJSONLWrite{tmpPath: tmp.Name(), path: path}
+	if _, err := tmp.Write(data); err == nil {
+		err = tmp.Chmod(perm)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		pending.abort()
+		return nil, err
+	}
+	return pending, nil
+}
+
+// writeJSONLFile atomically replaces path with data, keeping its
+// permissions (0644 for a new file).
+func writeJSONLFile(path string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	pending, err := prepareJSONLWrite(path, data, perm)
+	if err != nil {
+		return err
+	}
+	defer pending.abort()
+	return pending.commit()
+}
+
+// prepareJSONLMutation writes a copy of path with the mutated issue's line
+// patched in place. Every other line is copied byte-for-byte; on the patched
+// line only the changed keys are rewritten, so fields bv does not model are
+// preserved. If the issue has no line (e.g. a stale export next to a SQLite
+// database), the full issue is appended instead.
+func prepareJSONLMutation(path string, m Mutation, before, after model.Issue) (*pendingJSONLWrite, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	pending := &pendingJSONLWrite{tmpPath: tmp.Name(), path: path}
+	fail := func(err error) (*pendingJSONLWrite, error) {
+		tmp.Close()
+		pending.abort()
+		return nil, err
+	}
+
+	w := bufio.NewWriter(tmp)
+	reader := bufio.NewReader(in)
+	found := false
+	lastLineTerminated := true
+	for lineNum := 1; ; lineNum++ {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lastLineTerminated = line[len(line)-1] == '\n'
+			if !found {
+				patched, ok, err := patchJSONLLine(line, lineNum == 1, m, before, after)
+				if err != nil {
+					return fail(fmt.Errorf("%s line %d: %w", path, lineNum, err))
+				}
+				if ok {
+					line = patched
+					found = true
+				}
+			}
+			if _, err := w.Write(line); err != nil {
+				return fail(err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fail(readErr)
+		}
+	}
+
+	if !found {
+		data, err := renderIssueLine(nil, after)
+		if err != nil {
+			return fail(err)
+		}
+		if !lastLineTerminated {
+			w.WriteByte('\n')
+		}
+		w.Write(data)
+		if err := w.WriteByte('\n'); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Close(); err != nil {
+		pending.abort()
+		return nil, err
+	}
+	return pending, nil
+}
+
+// patchJSONLLine returns the rewritten line when it holds before.ID.
+func patchJSONLLine(line []byte, first bool, m Mutation, before, after model.Issue) ([]byte, bool, error) {
+	body := bytes.TrimRight(line, "\r\n")
+	ending := line[len(body):]
+	var prefix []byte
+	if first && bytes.HasPrefix(body, utf8BOM) {
+		prefix = utf8BOM
+		body = body[len(utf8BOM):]
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, false, nil
+	}
+
+	var probe struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil || probe.ID != before.ID {
+		// Malformed lines are left alone, matching the loader's skip behaviour.
+		return nil, false, nil
+	}
+
+	obj, err := parseJSONLObject(body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	set := func(key string, v any) error {
+		raw, err := marshalJSONLValue(v)
+		if err != nil {
+			return err
+		}
+		obj.set(key, raw)
+		return nil
+	}
+
+	if after.Status != before.Status {
+		if err := set("status", after.Status); err != nil {
+			return nil, false, err
+		}
+	}
+	if after.Assignee != before.Assignee {
+		if after.Assignee == "" {
+			obj.remove("assignee")
+		} else if err := set("assignee", after.Assignee); err != nil {
+			return nil, false, err
+		}
+	}
+	if after.Priority != before.Priority {
+		if err := set("priority", after.Priority); err != nil {
+			return nil, false, err
+		}
+	}
+	if !timePtrEqual(after.ClosedAt, before.ClosedAt) {
+		if after.ClosedAt == nil {
+			obj.remove("closed_at")
+			obj.remove("close_reason")
+		} else {
+			if err := set("closed_at", after.ClosedAt); err != nil {
+				return nil, false, err
+			}
+			if m.CloseReason != "" {
+				if err := set("close_reason", m.CloseReason); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+	}
+	if !stringSliceEqual(after.Labels, before.Labels) {
+		if len(after.Labels) == 0 {
+			obj.remove("labels")
+		} else if err := set("labels", after.Labels); err != nil {
+			return nil, false, err
+		}
+	}
+	if len(m.AddDependencies) > 0 || len(m.RemoveDependencies) > 0 {
+		deps, err := patchDependencies(obj.get("dependencies"), after)
+		if err != nil {
+			return nil, false, err
+		}
+		if deps == nil {
+			obj.remove("dependencies")
+		} else {
+			obj.set("dependencies", deps)
+		}
+	}
+	if !after.UpdatedAt.Equal(before.UpdatedAt) {
+		if err := set("updated_at", after.UpdatedAt); err != nil {
+			return nil, false, err
+		}
+	}
+
+	out := obj.bytes()
+	patched := make([]byte, 0, len(prefix)+len(out)+len(ending))
+	patched = append(patched, prefix...)
+	patched = append(patched, out...)
+	patched = append(patched, ending...)
+	return patched, true, nil
+}
+
+// jsonlObject is a JSON object split into its members so that single values
+// can be replaced, added or removed while every other byte of the line (key
+// order and spacing included) stays as it was.
+type jsonlObject struct {
+	head    []byte // "{" and any space before the first key
+	members []jsonlMember
+	tail    []byte // any space after the last value, and "}"
+}
+
+type jsonlMember struct {
+	sep   []byte // "," and spacing before the key; unused for the first member
+	key   string
+	name  []byte // the raw key and colon, up to the value
+	value json.RawMessage
+}
+
+// parseJSONLObject splits body, a single JSON object, into members.
+func parseJSONLObject(body []byte) (*jsonlObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	obj := &jsonlObject{}
+	end := int(dec.InputOffset())
+	obj.head = body[:end]
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		valueEnd := int(dec.InputOffset())
+		keyStart := end + bytes.IndexByte(body[end:], '"')
+		member := jsonlMember{
+			sep:   body[end:keyStart],
+			key:   key,
+			name:  body[keyStart : valueEnd-len(value)],
+			value: value,
+		}
+		if len(obj.members) == 0 {
+			obj.head = body[:keyStart]
+			member.sep = nil
+		}
+		obj.members = append(obj.members, member)
+		end = valueEnd
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	obj.tail = body[end:]
+	return obj, nil
+}
+
+// get returns the raw value of key, or nil.
+func (o *jsonlObject) get(key string) json.RawMessage {
+	for i := len(o.members) - 1; i >= 0; i-- {
+		if o.members[i].key == key {
+			return o.members[i].value
+		}
+	}
+	return nil
+}
+
+// set replaces the value of key in place, or appends key when the object
+// does not have it.
+func (o *jsonlObject) set(key string, value json.RawMessage) {
+	found := false
+	for i := range o.members {
+		if o.members[i].key == key {
+			o.members[i].value = value
+			found = true
+		}
+	}
+	if found {
+		return
+	}
+	name, _ := marshalJSONLValue(key)
+	o.members = append(o.members, jsonlMember{key: key, name: append(name, ':'), value: value})
+}
+
+// remove deletes key, along with the separator before it.
+func (o *jsonlObject) remove(key string) {
+	kept := o.members[:0]
+	for _, m := range o.members {
+		if m.key != key {
+			kept = append(kept, m)
+		}
+	}
+	o.members = kept
+}
+
+// bytes re-assembles the object.
+func (o *jsonlObject) bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(o.head)
+	for i, m := range o.members {
+		if i > 0 {
+			if len(m.sep) > 0 {
+				buf.Write(m.sep)
+			} else {
+				buf.WriteByte(',')
+			}
+		}
+		buf.Write(m.name)
+		buf.Write(m.value)
+	}
+	buf.Write(o.tail)
+	return buf.Bytes()
+}
+
+// patchDependencies rewrites the raw dependencies array so it matches
+// after.Dependencies, keeping existing entries verbatim (including any
+// fields bv does not model) and appending new ones.
+func patchDependencies(raw json.RawMessage, after model.Issue) (json.RawMessage, error) {
+	var entries []json.RawMessage
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("dependencies: %w", err)
+		}
+	}
+
+	want := make(map[string]*model.Dependency, len(after.Dependencies))
+	for _, dep := range after.Dependencies {
+		want[dep.DependsOnID] = dep
+	}
+
+	var out []json.RawMessage
+	for _, entry := range entries {
+		var dep model.Dependency
+		if err := json.Unmarshal(entry, &dep); err != nil {
+			out = append(out, entry)
+			continue
+		}
+		if _, keep := want[dep.DependsOnID]; keep {
+			out = append(out, entry)
+			delete(want, dep.DependsOnID)
+		}
+	}
+	for _, dep := range after.Dependencies {
+		if _, missing := want[dep.DependsOnID]; !missing {
+			continue
+		}
+		b, err := marshalJSONLValue(dep)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return marshalJSONLValue(out)
+}
+
+// marshalJSONLValue encodes v on a single line without HTML escaping, so
+// text containing <, > or & reads the same as in untouched lines.
+func marshalJSONLValue(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func timePtrEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -85,6 +85,10 @@ func LoadFromSource(source DataSource) ([]model.Issue, error) {
 		return loader.LoadIssuesFromFile(source.Path)
 
 	default:
+		if _, ok := LookupSource(string(source.Type)); ok {
+			issues, _, err := ImportFile(source.Path, string(source.Type))
+			return issues, err
+		}
 		return nil, fmt.Errorf("unknown source type: %s", source.Type)
 	}
 }
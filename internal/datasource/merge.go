@@ -0,0 +1,626 @@
+package datasource
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// MergeSide names one input of a three-way merge.
+type MergeSide string
+
+const (
+	MergeBase   MergeSide = "base"
+	MergeOurs   MergeSide = "ours"
+	MergeTheirs MergeSide = "theirs"
+)
+
+// MergeFieldIssue is the conflict field used when one side deleted an issue
+// the other side modified.
+const MergeFieldIssue = "issue"
+
+// MergeConflict is a field both sides changed in different ways. Field is an
+// Issue JSON key ("status"), or "dependencies:<id>", "comments:<key>",
+// "custom.<name>" for collection members, or MergeFieldIssue. Values are nil
+// where the side does not have the issue or member.
+type MergeConflict struct {
+	IssueID    string    `json:"issue_id"`
+	Field      string    `json:"field"`
+	Base       any       `json:"base"`
+	Ours       any       `json:"ours"`
+	Theirs     any       `json:"theirs"`
+	Resolution MergeSide `json:"resolution,omitempty"`
+}
+
+// MergeStats counts issues by how they were merged.
+type MergeStats struct {
+	Unchanged  int `json:"unchanged"`
+	Merged     int `json:"merged"`
+	Added      int `json:"added"`
+	Deleted    int `json:"deleted"`
+	Conflicted int `json:"conflicted"`
+}
+
+// MergeTriple holds the three versions of one issue; a nil side means the
+// issue does not exist there.
+type MergeTriple struct {
+	ID     string       `json:"id"`
+	Base   *model.Issue `json:"base,omitempty"`
+	Ours   *model.Issue `json:"ours,omitempty"`
+	Theirs *model.Issue `json:"theirs,omitempty"`
+}
+
+// MergeResult is the outcome of MergeIssues. Issues holds the merged data;
+// an unresolved conflict takes the ours value until Resolve is called.
+type MergeResult struct {
+	Issues    []model.Issue   `json:"-"`
+	Conflicts []MergeConflict `json:"conflicts"`
+	Stats     MergeStats      `json:"stats"`
+
+	// Pending holds the inputs of every conflicted issue so resolutions can
+	// be applied later, possibly in another process.
+	Pending []MergeTriple `json:"pending,omitempty"`
+}
+
+// Unresolved returns the number of conflicts without a resolution.
+func (r *MergeResult) Unresolved() int {
+	n := 0
+	for _, c := range r.Conflicts {
+		if c.Resolution == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Resolve picks side for one conflict and updates Issues accordingly.
+func (r *MergeResult) Resolve(issueID, field string, side MergeSide) error {
+	switch side {
+	case MergeBase, MergeOurs, MergeTheirs:
+	default:
+		return fmt.Errorf("invalid merge side %q", side)
+	}
+	found := false
+	for i := range r.Conflicts {
+		if r.Conflicts[i].IssueID == issueID && r.Conflicts[i].Field == field {
+			r.Conflicts[i].Resolution = side
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no conflict on %s of %s", field, issueID)
+	}
+
+	var triple *MergeTriple
+	for i := range r.Pending {
+		if r.Pending[i].ID == issueID {
+			triple = &r.Pending[i]
+		}
+	}
+	if triple == nil {
+		return fmt.Errorf("no merge inputs recorded for %s", issueID)
+	}
+	merged, _ := mergeIssue(*triple, r.resolutions(issueID))
+	idx := -1
+	for i := range r.Issues {
+		if r.Issues[i].ID == issueID {
+			idx = i
+			break
+		}
+	}
+	switch {
+	case merged == nil && idx >= 0:
+		r.Issues = append(r.Issues[:idx], r.Issues[idx+1:]...)
+	case merged != nil && idx >= 0:
+		r.Issues[idx] = *merged
+	case merged != nil:
+		r.Issues = append(r.Issues, *merged)
+	}
+	return nil
+}
+
+func (r *MergeResult) resolutions(issueID string) map[string]MergeSide {
+	out := make(map[string]MergeSide)
+	for _, c := range r.Conflicts {
+		if c.IssueID == issueID && c.Resolution != "" {
+			out[c.Field] = c.Resolution
+		}
+	}
+	return out
+}
+
+// MergeIssues performs a per-field three-way merge of ours and theirs against
+// their common ancestor base. Scalar fields take whichever side changed;
+// labels, dependencies, comments and custom fields are merged member by
+// member, so additions on both sides are kept. updated_at takes the later
+// value and never conflicts. Output order follows ours, with issues added
+// only by theirs appended in their order.
+func MergeIssues(base, ours, theirs []model.Issue) *MergeResult {
+	index := func(issues []model.Issue) (map[string]*model.Issue, []string) {
+		m := make(map[string]*model.Issue, len(issues))
+		var order []string
+		for i := range issues {
+			if _, dup := m[issues[i].ID]; !dup {
+				order = append(order, issues[i].ID)
+			}
+			m[issues[i].ID] = &issues[i]
+		}
+		return m, order
+	}
+	baseByID, baseOrder := index(base)
+	oursByID, oursOrder := index(ours)
+	theirsByID, theirsOrder := index(theirs)
+
+	var order []string
+	seen := make(map[string]bool)
+	for _, ids := range [][]string{oursOrder, theirsOrder, baseOrder} {
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				order = append(order, id)
+			}
+		}
+	}
+
+	result := &MergeResult{Conflicts: []MergeConflict{}}
+	for _, id := range order {
+		t := MergeTriple{ID: id, Base: baseByID[id], Ours: oursByID[id], Theirs: theirsByID[id]}
+		merged, conflicts := mergeIssue(t, nil)
+		switch {
+		case len(conflicts) > 0:
+			result.Stats.Conflicted++
+			result.Conflicts = append(result.Conflicts, conflicts...)
+			result.Pending = append(result.Pending, cloneTriple(t))
+		case merged == nil:
+			if t.Base != nil {
+				result.Stats.Deleted++
+			}
+		case t.Base == nil && (t.Ours == nil || t.Theirs == nil):
+			result.Stats.Added++
+		case t.Ours != nil && sameIssue(*merged, *t.Ours) && (t.Base == nil || sameIssue(*t.Ours, *t.Base)):
+			result.Stats.Unchanged++
+		default:
+			result.Stats.Merged++
+		}
+		if merged != nil {
+			result.Issues = append(result.Issues, *merged)
+		}
+	}
+	return result
+}
+
+func cloneTriple(t MergeTriple) MergeTriple {
+	clone := func(i *model.Issue) *model.Issue {
+		if i == nil {
+			return nil
+		}
+		c := i.Clone()
+		return &c
+	}
+	return MergeTriple{ID: t.ID, Base: clone(t.Base), Ours: clone(t.Ours), Theirs: clone(t.Theirs)}
+}
+
+// mergeIssue merges one issue. A nil result means the issue is deleted.
+func mergeIssue(t MergeTriple, resolved map[string]MergeSide) (*model.Issue, []MergeConflict) {
+	b, o, th := t.Base, t.Ours, t.Theirs
+	switch {
+	case o == nil && th == nil:
+		return nil, nil
+	case o != nil && th == nil, o == nil && th != nil:
+		present, presentSide := o, MergeOurs
+		if present == nil {
+			present, presentSide = th, MergeTheirs
+		}
+		if b == nil {
+			c := present.Clone()
+			return &c, nil // added on one side
+		}
+		if sameIssue(*present, *b) {
+			return nil, nil // deleted on the other side, untouched here
+		}
+		conflict := MergeConflict{
+			IssueID: t.ID,
+			Field:   MergeFieldIssue,
+			Base:    "present",
+			Ours:    "deleted",
+			Theirs:  "deleted",
+		}
+		if presentSide == MergeOurs {
+			conflict.Ours = "modified"
+		} else {
+			conflict.Theirs = "modified"
+		}
+		conflict.Resolution = resolved[MergeFieldIssue]
+		pick := conflict.Resolution
+		if pick == "" {
+			pick = MergeOurs
+		}
+		var out *model.Issue
+		switch pick {
+		case presentSide:
+			c := present.Clone()
+			out = &c
+		case MergeBase:
+			c := b.Clone()
+			out = &c
+		}
+		return out, []MergeConflict{conflict}
+	}
+
+	base := model.Issue{ID: t.ID}
+	if b != nil {
+		base = *b
+	}
+	m := &mergeState{id: t.ID, resolved: resolved}
+	merged := o.Clone()
+
+	for _, f := range issueMergeFields {
+		bv, ov, tv := f.get(&base), f.get(o), f.get(th)
+		if v, ok := m.scalar(f.name, bv, ov, tv, f.equal); ok {
+			f.set(&merged, v)
+		}
+	}
+	merged.UpdatedAt = o.UpdatedAt
+	if th.UpdatedAt.After(o.UpdatedAt) {
+		merged.UpdatedAt = th.UpdatedAt
+	}
+	merged.Labels = mergeLabelSets(base.Labels, o.Labels, th.Labels)
+	merged.Dependencies = m.dependencies(base.Dependencies, o.Dependencies, th.Dependencies)
+	merged.Comments = m.comments(base.Comments, o.Comments, th.Comments)
+	merged.CustomFields = m.customFields(base.CustomFields, o.CustomFields, th.CustomFields)
+	return &merged, m.conflicts
+}
+
+// mergeState accumulates conflicts while merging one issue.
+type mergeState struct {
+	id        string
+	resolved  map[string]MergeSide
+	conflicts []MergeConflict
+}
+
+// scalar merges one value. ok is false when the ours value should be kept
+// as is; otherwise v is the merged value.
+func (m *mergeState) scalar(field string, b, o, t any, equal func(a, b any) bool) (v any, ok bool) {
+	switch {
+	case equal(o, t), equal(b, t):
+		return nil, false
+	case equal(b, o):
+		return t, true
+	}
+	c := MergeConflict{IssueID: m.id, Field: field, Base: b, Ours: o, Theirs: t, Resolution: m.resolved[field]}
+	m.conflicts = append(m.conflicts, c)
+	switch c.Resolution {
+	case MergeTheirs:
+		return t, true
+	case MergeBase:
+		return b, true
+	}
+	return nil, false
+}
+
+// mergeLabelSets merges label sets: a label is kept unless one side removed it,
+// and additions from either side are kept. Set membership never conflicts.
+func mergeLabelSets(base, ours, theirs []string) []string {
+	in := func(list []string) map[string]bool {
+		m := make(map[string]bool, len(list))
+		for _, l := range list {
+			m[l] = true
+		}
+		return m
+	}
+	b, o, t := in(base), in(ours), in(theirs)
+	var out []string
+	for _, list := range [][]string{ours, theirs} {
+		for _, l := range list {
+			if mergeBool(b[l], o[l], t[l]) {
+				out = append(out, l)
+				b[l], o[l], t[l] = false, false, false // emit once
+			}
+		}
+	}
+	return out
+}
+
+func mergeBool(b, o, t bool) bool {
+	if o == t {
+		return o
+	}
+	if b == o {
+		return t
+	}
+	return o
+}
+
+// dependencies merges dependency lists keyed by target. Both sides changing
+// the type of, or one removing and the other retyping, the same dependency
+// is a conflict.
+func (m *mergeState) dependencies(base, ours, theirs []*model.Dependency) []*model.Dependency {
+	key := func(d *model.Dependency) string { return d.DependsOnID }
+	b, o, t := indexDeps(base, key), indexDeps(ours, key), indexDeps(theirs, key)
+	var out []*model.Dependency
+	for _, k := range unionKeys(depKeys(ours, key), depKeys(theirs, key), depKeys(base, key)) {
+		bd, od, td := b[k], o[k], t[k]
+		typeOf := func(d *model.Dependency) any {
+			if d == nil {
+				return nil
+			}
+			return string(d.Type)
+		}
+		pick := od
+		if v, ok := m.scalar("dependencies:"+k, typeOf(bd), typeOf(od), typeOf(td), equalAny); ok {
+			switch {
+			case v == nil:
+				pick = nil
+			case td != nil && string(td.Type) == v:
+				pick = td
+			case bd != nil:
+				pick = bd
+			}
+		}
+		if pick != nil {
+			d := *pick
+			out = append(out, &d)
+		}
+	}
+	return out
+}
+
+func indexDeps(deps []*model.Dependency, key func(*model.Dependency) string) map[string]*model.Dependency {
+	m := make(map[string]*model.Dependency, len(deps))
+	for _, d := range deps {
+		if d != nil {
+			m[key(d)] = d
+		}
+	}
+	return m
+}
+
+func depKeys(deps []*model.Dependency, key func(*model.Dependency) string) []string {
+	var out []string
+	for _, d := range deps {
+		if d != nil {
+			out = append(out, key(d))
+		}
+	}
+	return out
+}
+
+// comments merges comment lists keyed by ID (or author and time for comments
+// without one). Edits to the same comment on both sides conflict.
+func (m *mergeState) comments(base, ours, theirs []*model.Comment) []*model.Comment {
+	index := func(list []*model.Comment) (map[string]*model.Comment, []string) {
+		idx := make(map[string]*model.Comment, len(list))
+		var keys []string
+		for _, c := range list {
+			if c == nil {
+				continue
+			}
+			k := commentKey(c)
+			idx[k] = c
+			keys = append(keys, k)
+		}
+		return idx, keys
+	}
+	b, baseKeys := index(base)
+	o, oursKeys := index(ours)
+	t, theirsKeys := index(theirs)
+	text := func(c *model.Comment) any {
+		if c == nil {
+			return nil
+		}
+		return c.Text
+	}
+
+	var out []*model.Comment
+	for _, k := range unionKeys(oursKeys, theirsKeys, baseKeys) {
+		bc, oc, tc := b[k], o[k], t[k]
+		pick := oc
+		if v, changed := m.scalar("comments:"+k, text(bc), text(oc), text(tc), equalAny); changed {
+			switch {
+			case v == nil:
+				pick = nil
+			case tc != nil && tc.Text == v:
+				pick = tc
+			case bc != nil:
+				pick = bc
+			}
+		}
+		if pick != nil {
+			c := *pick
+			out = append(out, &c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+func commentKey(c *model.Comment) string {
+	if c.ID != 0 {
+		return strconv.FormatInt(c.ID, 10)
+	}
+	return c.Author + "@" + c.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// customFields merges custom fields key by key.
+func (m *mergeState) customFields(base, ours, theirs model.CustomFields) model.CustomFields {
+	get := func(c model.CustomFields, k string) any {
+		if c == nil {
+			return nil
+		}
+		return c[k]
+	}
+	out := ours.Clone()
+	for _, k := range unionKeys(ours.Names(), theirs.Names(), base.Names()) {
+		v, changed := m.scalar("custom."+k, get(base, k), get(ours, k), get(theirs, k), equalAny)
+		if !changed {
+			continue
+		}
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		if out == nil {
+			out = make(model.CustomFields)
+		}
+		out[k] = v
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// unionKeys returns the distinct keys of all lists in first-seen order.
+func unionKeys(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range lists {
+		for _, k := range list {
+			if !seen[k] {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+	}
+	return out
+}
+
+// issueMergeField describes one scalar Issue field for merging.
+type issueMergeField struct {
+	name  string
+	get   func(*model.Issue) any
+	set   func(*model.Issue, any)
+	equal func(a, b any) bool
+}
+
+func mergeField[T any](name string, ptr func(*model.Issue) *T, equal func(a, b any) bool) issueMergeField {
+	return issueMergeField{
+		name: name,
+		get:  func(i *model.Issue) any { return *ptr(i) },
+		set: func(i *model.Issue, v any) {
+			if v == nil {
+				var zero T
+				*ptr(i) = zero
+				return
+			}
+			*ptr(i) = v.(T)
+		},
+		equal: equal,
+	}
+}
+
+// issueMergeFields lists the scalar fields merged independently. ID and
+// updated_at are handled by mergeIssue; collections have their own rules.
+var issueMergeFields = []issueMergeField{
+	mergeField("title", func(i *model.Issue) *string { return &i.Title }, equalAny),
+	mergeField("description", func(i *model.Issue) *string { return &i.Description }, equalAny),
+	mergeField("design", func(i *model.Issue) *string { return &i.Design }, equalAny),
+	mergeField("acceptance_criteria", func(i *model.Issue) *string { return &i.AcceptanceCriteria }, equalAny),
+	mergeField("notes", func(i *model.Issue) *string { return &i.Notes }, equalAny),
+	mergeField("status", func(i *model.Issue) *model.Status { return &i.Status }, equalAny),
+	mergeField("priority", func(i *model.Issue) *int { return &i.Priority }, equalAny),
+	mergeField("issue_type", func(i *model.Issue) *model.IssueType { return &i.IssueType }, equalAny),
+	mergeField("assignee", func(i *model.Issue) *string { return &i.Assignee }, equalAny),
+	mergeField("estimated_minutes", func(i *model.Issue) **int { return &i.EstimatedMinutes }, equalPtr[int]),
+	mergeField("created_at", func(i *model.Issue) *time.Time { return &i.CreatedAt }, equalTime),
+	mergeField("due_date", func(i *model.Issue) **time.Time { return &i.DueDate }, equalTime),
+	mergeField("closed_at", func(i *model.Issue) **time.Time { return &i.ClosedAt }, equalTime),
+	mergeField("external_ref", func(i *model.Issue) **string { return &i.ExternalRef }, equalPtr[string]),
+	mergeField("compaction_level", func(i *model.Issue) *int { return &i.CompactionLevel }, equalAny),
+	mergeField("compacted_at", func(i *model.Issue) **time.Time { return &i.CompactedAt }, equalTime),
+	mergeField("compacted_at_commit", func(i *model.Issue) **string { return &i.CompactedAtCommit }, equalPtr[string]),
+	mergeField("original_size", func(i *model.Issue) *int { return &i.OriginalSize }, equalAny),
+	mergeField("source_repo", func(i *model.Issue) *string { return &i.SourceRepo }, equalAny),
+}
+
+func equalAny(a, b any) bool { return reflect.DeepEqual(a, b) }
+
+func equalPtr[T comparable](a, b any) bool {
+	ap, _ := a.(*T)
+	bp, _ := b.(*T)
+	if ap == nil || bp == nil {
+		return ap == bp
+	}
+	return *ap == *bp
+}
+
+// equalTime compares time.Time or *time.Time values by instant, so the same
+// moment written with different offsets is not a change.
+func equalTime(a, b any) bool {
+	at, aok := asTime(a)
+	bt, bok := asTime(b)
+	if !aok || !bok {
+		return aok == bok
+	}
+	return at.Equal(bt)
+}
+
+func asTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sameIssue reports whether a and b carry the same data.
+func sameIssue(a, b model.Issue) bool {
+	if a.ID != b.ID || !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return false
+	}
+	for _, f := range issueMergeFields {
+		if !f.equal(f.get(&a), f.get(&b)) {
+			return false
+		}
+	}
+	return sameLabels(a.Labels, b.Labels) &&
+		sameDependencyTypes(a.Dependencies, b.Dependencies) &&
+		sameComments(a.Comments, b.Comments) &&
+		reflect.DeepEqual(a.CustomFields, b.CustomFields)
+}
+
+func sameLabels(a, b []string) bool {
+	set := func(list []string) map[string]bool {
+		m := make(map[string]bool, len(list))
+		for _, l := range list {
+			m[l] = true
+		}
+		return m
+	}
+	return reflect.DeepEqual(set(a), set(b))
+}
+
+func sameDependencyTypes(a, b []*model.Dependency) bool {
+	key := func(d *model.Dependency) string { return d.DependsOnID }
+	ai, bi := indexDeps(a, key), indexDeps(b, key)
+	if len(ai) != len(bi) {
+		return false
+	}
+	for k, d := range ai {
+		if other, ok := bi[k]; !ok || other.Type != d.Type {
+			return false
+		}
+	}
+	return true
+}
+
+func sameComments(a, b []*model.Comment) bool {
+	texts := func(list []*model.Comment) map[string]string {
+		m := make(map[string]string, len(list))
+		for _, c := range list {
+			if c != nil {
+				m[commentKey(c)] = c.Text
+			}
+		}
+		return m
+	}
+	return reflect.DeepEqual(texts(a), texts(b))
+}
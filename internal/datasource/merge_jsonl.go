@@ -0,0 +1,395 @@
+package datasource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/instance"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// mergeStateFile holds unresolved merge conflicts inside .beads/.
+const mergeStateFile = ".bv.merge.json"
+
+// jsonlSnapshot is a parsed JSONL file that remembers each issue's original
+// line, so unchanged issues can be written back byte-for-byte.
+type jsonlSnapshot struct {
+	issues []model.Issue
+	lines  map[string][]byte
+	// layout is the file's line order: an issue ID for the first line of each
+	// issue, or an opaque line that is not an issue (malformed JSON, no id).
+	// Opaque lines are carried over from ours untouched and in place.
+	layout []jsonlLayoutLine
+}
+
+type jsonlLayoutLine struct {
+	id     string
+	opaque []byte
+}
+
+func parseJSONLSnapshot(data []byte) jsonlSnapshot {
+	snap := jsonlSnapshot{lines: make(map[string][]byte)}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		issue, err := loader.DecodeIssueLine(line)
+		if err != nil || issue.ID == "" {
+			snap.layout = append(snap.layout, jsonlLayoutLine{opaque: line})
+			continue
+		}
+		if _, dup := snap.lines[issue.ID]; !dup {
+			snap.issues = append(snap.issues, issue)
+			snap.layout = append(snap.layout, jsonlLayoutLine{id: issue.ID})
+		} else {
+			for i := range snap.issues {
+				if snap.issues[i].ID == issue.ID {
+					snap.issues[i] = issue
+				}
+			}
+		}
+		snap.lines[issue.ID] = line
+	}
+	return snap
+}
+
+func readJSONLSnapshot(path string) (jsonlSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return jsonlSnapshot{}, err
+	}
+	return parseJSONLSnapshot(data), nil
+}
+
+// MergeJSONLFiles merges three versions of a beads JSONL file, in the
+// argument order git passes to a merge driver (%O %A %B), and writes the
+// result over oursPath. A missing or empty base means the file was added on
+// both sides. Unresolved conflicts keep the ours value in the written file.
+func MergeJSONLFiles(basePath, oursPath, theirsPath string) (*MergeResult, error) {
+	var base jsonlSnapshot
+	if data, err := os.ReadFile(basePath); err == nil {
+		base = parseJSONLSnapshot(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	ours, err := readJSONLSnapshot(oursPath)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := readJSONLSnapshot(theirsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := MergeIssues(base.issues, ours.issues, theirs.issues)
+	if err := writeMergedJSONL(oursPath, result.Issues, ours, theirs, base); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MergeIntoJSONL merges theirs into the JSONL file at path using base as the
+// common ancestor, as `bv merge <rev>` does with issues loaded from git. The
+// file is only rewritten when write is set, under the instance write lock of
+// its directory.
+func MergeIntoJSONL(path string, base, theirs []model.Issue, write bool) (*MergeResult, error) {
+	if write {
+		lock, err := instance.AcquireWriteLock(filepath.Dir(path), 0)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Release()
+	}
+	ours, err := readJSONLSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	result := MergeIssues(base, ours.issues, theirs)
+	if !write {
+		return result, nil
+	}
+	if err := writeMergedJSONL(path, result.Issues, ours); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// writeMergedJSONL writes issues to path. An issue identical to its version
+// in one of the snapshots reuses that line verbatim; otherwise the line is
+// rebuilt on top of the first snapshot's line, so keys bv does not model
+// survive. Issues and opaque lines of the first snapshot keep its order;
+// issues it does not have follow at the end.
+func writeMergedJSONL(path string, issues []model.Issue, snaps ...jsonlSnapshot) error {
+	var buf bytes.Buffer
+	writeIssue := func(issue model.Issue) error {
+		line, err := mergedLine(issue, snaps)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", issue.ID, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		return nil
+	}
+
+	written := make([]bool, len(issues))
+	if len(snaps) > 0 {
+		byID := make(map[string]int, len(issues))
+		for i := range issues {
+			if _, dup := byID[issues[i].ID]; !dup {
+				byID[issues[i].ID] = i
+			}
+		}
+		for _, entry := range snaps[0].layout {
+			if entry.opaque != nil {
+				buf.Write(entry.opaque)
+				buf.WriteByte('\n')
+				continue
+			}
+			i, ok := byID[entry.id]
+			if !ok || written[i] {
+				continue
+			}
+			if err := writeIssue(issues[i]); err != nil {
+				return err
+			}
+			written[i] = true
+		}
+	}
+	for i, issue := range issues {
+		if written[i] {
+			continue
+		}
+		if err := writeIssue(issue); err != nil {
+			return err
+		}
+	}
+	return writeJSONLFile(path, buf.Bytes())
+}
+
+func mergedLine(issue model.Issue, snaps []jsonlSnapshot) ([]byte, error) {
+	var template []byte
+	for _, snap := range snaps {
+		line, ok := snap.lines[issue.ID]
+		if !ok {
+			continue
+		}
+		for i := range snap.issues {
+			if snap.issues[i].ID == issue.ID && sameIssue(snap.issues[i], issue) {
+				return line, nil
+			}
+		}
+		if template == nil {
+			template = line
+		}
+	}
+	return renderIssueLine(template, issue)
+}
+
+// renderIssueLine encodes issue as a JSONL line by patching template, an
+// existing line for the issue (nil when there is none). Only keys whose
+// value changed are rewritten in place, so keys bv does not model
+// (close_reason, bd bookkeeping) and fields the template omitted stay as
+// they were. Custom fields keep the shape the template used; see
+// setCustomFields.
+func renderIssueLine(template []byte, issue model.Issue) ([]byte, error) {
+	obj := &jsonlObject{head: []byte("{"), tail: []byte("}")}
+	var old model.Issue
+	if len(template) > 0 {
+		parsed, err := parseJSONLObject(template)
+		if err != nil {
+			return nil, err
+		}
+		obj = parsed
+		if decoded, err := loader.DecodeIssueLine(template); err == nil {
+			old = decoded
+		}
+	}
+	oldCustom, newCustom := old.CustomFields, issue.CustomFields
+	old.CustomFields, issue.CustomFields = nil, nil
+
+	oldFields, err := issueJSONFields(old)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := issueJSONFields(issue)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range newFields.members {
+		if !bytes.Equal(m.value, oldFields.get(m.key)) {
+			obj.set(m.key, m.value)
+		}
+	}
+	for _, m := range oldFields.members {
+		if newFields.get(m.key) == nil {
+			obj.remove(m.key)
+		}
+	}
+	if err := setCustomFields(obj, oldCustom, newCustom); err != nil {
+		return nil, err
+	}
+	return obj.bytes(), nil
+}
+
+func issueJSONFields(issue model.Issue) (*jsonlObject, error) {
+	encoded, err := marshalJSONLValue(issue)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONLObject(encoded)
+}
+
+// setCustomFields applies the changes from old to updated to obj in the
+// shape the line already uses. The loader reads custom fields both as
+// top-level keys and from a nested "custom_fields" object, so a field found
+// only in the nested object is updated there; every other field, and new
+// fields on a line without a nested object, is a top-level key.
+func setCustomFields(obj *jsonlObject, old, updated model.CustomFields) error {
+	var nested *jsonlObject
+	if raw := obj.get("custom_fields"); len(raw) > 0 && raw[0] == '{' {
+		parsed, err := parseJSONLObject(raw)
+		if err != nil {
+			return fmt.Errorf("custom_fields: %w", err)
+		}
+		nested = parsed
+	}
+	nestedChanged := false
+	for _, name := range old.Names() {
+		if _, ok := updated[name]; ok {
+			continue
+		}
+		obj.remove(name)
+		if nested != nil && nested.get(name) != nil {
+			nested.remove(name)
+			nestedChanged = true
+		}
+	}
+	for _, name := range updated.Names() {
+		v := updated[name]
+		if prev, ok := old[name]; ok && reflect.DeepEqual(prev, v) {
+			continue
+		}
+		raw, err := marshalJSONLValue(v)
+		if err != nil {
+			return err
+		}
+		if nested != nil && obj.get(name) == nil {
+			nested.set(name, raw)
+			nestedChanged = true
+		} else {
+			obj.set(name, raw)
+		}
+	}
+	if nestedChanged {
+		if len(nested.members) == 0 {
+			obj.remove("custom_fields")
+		} else {
+			obj.set("custom_fields", nested.bytes())
+		}
+	}
+	return nil
+}
+
+// MergeState is a merge whose conflicts still need a decision. It is saved
+// in .beads/ so the TUI (or a later `bv merge --resolve`) can finish it.
+type MergeState struct {
+	Path      string       `json:"path"`
+	Ours      string       `json:"ours"`
+	Theirs    string       `json:"theirs"`
+	CreatedAt time.Time    `json:"created_at"`
+	Result    *MergeResult `json:"result"`
+}
+
+// MergeStatePath returns where the pending merge state for beadsDir lives.
+func MergeStatePath(beadsDir string) string {
+	return filepath.Join(beadsDir, mergeStateFile)
+}
+
+// SaveMergeState records state in beadsDir.
+func SaveMergeState(beadsDir string, state *MergeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONLFile(MergeStatePath(beadsDir), append(data, '\n'))
+}
+
+// LoadMergeState returns the pending merge in beadsDir, or nil if there is
+// none.
+func LoadMergeState(beadsDir string) (*MergeState, error) {
+	data, err := os.ReadFile(MergeStatePath(beadsDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state MergeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("reading merge state: %w", err)
+	}
+	if state.Result == nil {
+		return nil, fmt.Errorf("reading merge state: no result recorded")
+	}
+	return &state, nil
+}
+
+// ApplyMergeState rewrites the conflicted issues in state.Path with the
+// current resolutions (ours where none was made). Once every conflict is
+// resolved the state file is removed. The rewrite holds the instance write
+// lock of beadsDir. It returns the number of conflicts still open.
+func ApplyMergeState(beadsDir string, state *MergeState) (int, error) {
+	lock, err := instance.AcquireWriteLock(beadsDir, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+
+	snap, err := readJSONLSnapshot(state.Path)
+	if err != nil {
+		return 0, err
+	}
+	resolved := make(map[string]*model.Issue, len(state.Result.Pending))
+	for _, t := range state.Result.Pending {
+		merged, _ := mergeIssue(t, state.Result.resolutions(t.ID))
+		resolved[t.ID] = merged
+	}
+
+	var issues []model.Issue
+	for _, issue := range snap.issues {
+		merged, pending := resolved[issue.ID]
+		switch {
+		case !pending:
+			issues = append(issues, issue)
+		case merged != nil:
+			issues = append(issues, *merged)
+		}
+		delete(resolved, issue.ID)
+	}
+	for _, t := range state.Result.Pending {
+		if merged := resolved[t.ID]; merged != nil {
+			issues = append(issues, *merged) // deleted from the file but kept by a resolution
+		}
+	}
+	if err := writeMergedJSONL(state.Path, issues, snap); err != nil {
+		return 0, err
+	}
+
+	open := state.Result.Unresolved()
+	if open == 0 {
+		if err := os.Remove(MergeStatePath(beadsDir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, err
+		}
+		return 0, nil
+	}
+	return open, SaveMergeState(beadsDir, state)
+}
@@ -0,0 +1,168 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func writeMergeSide(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMergeJSONLFiles_PreservesUntouchedLines(t *testing.T) {
+	dir := t.TempDir()
+	keep := `{"id":"K","title":"Keep",  "status":"open","priority":2,"issue_type":"task","close_reason":""}`
+	base := writeMergeSide(t, dir, "base",
+		keep,
+		`{"id":"A","title":"Old","status":"open","priority":2,"issue_type":"task","labels":["core"],"x_team":"core","compaction_note":"n1"}`,
+	)
+	ours := writeMergeSide(t, dir, "ours",
+		keep,
+		`garbage we cannot parse`,
+		`{"id":"A","title":"New","status":"open","priority":2,"issue_type":"task","labels":["core"],"x_team":"core","compaction_note":"n1"}`,
+	)
+	theirsAdded := `{"id":"T","title":"From theirs","status":"open","priority":1,"issue_type":"bug","x_origin":"import"}`
+	theirs := writeMergeSide(t, dir, "theirs",
+		keep,
+		`{"id":"A","title":"Old","status":"in_progress","priority":2,"issue_type":"task","labels":["core","wip"],"x_team":"platform","compaction_note":"n1"}`,
+		theirsAdded,
+	)
+
+	result, err := MergeJSONLFiles(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Unresolved() != 0 {
+		t.Fatalf("unexpected conflicts: %+v", result.Conflicts)
+	}
+
+	lines := readLines(t, ours)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if lines[0] != keep || lines[1] != "garbage we cannot parse" || lines[3] != theirsAdded {
+		t.Errorf("unchanged, added or unparseable lines were rewritten or moved:\n%s", strings.Join(lines, "\n"))
+	}
+	for _, want := range []string{`"title":"New"`, `"status":"in_progress"`, `"wip"`, `"x_team":"platform"`, `"compaction_note":"n1"`} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("merged line missing %s: %s", want, lines[2])
+		}
+	}
+	if strings.Contains(lines[2], "custom_fields") {
+		t.Errorf("custom fields should stay top-level: %s", lines[2])
+	}
+}
+
+func TestMergeJSONLFiles_KeepsNestedCustomFields(t *testing.T) {
+	dir := t.TempDir()
+	line := func(title, customer string) string {
+		return `{"id":"A","title":"` + title + `","status":"open","priority":2,"issue_type":"task","custom_fields":{"customer":"` + customer + `","tier":1}}`
+	}
+	base := writeMergeSide(t, dir, "base", line("Old", "acme"))
+	ours := writeMergeSide(t, dir, "ours", line("New", "acme"))
+	theirs := writeMergeSide(t, dir, "theirs", line("Old", "globex"))
+
+	result, err := MergeJSONLFiles(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Unresolved() != 0 {
+		t.Fatalf("unexpected conflicts: %+v", result.Conflicts)
+	}
+	if got := readLines(t, ours)[0]; got != line("New", "globex") {
+		t.Errorf("merged line changed shape:\n got %s\nwant %s", got, line("New", "globex"))
+	}
+}
+
+func TestMergeJSONLFiles_MissingBaseMeansAddedOnBothSides(t *testing.T) {
+	dir := t.TempDir()
+	ours := writeMergeSide(t, dir, "ours", `{"id":"A","title":"Ours","status":"open","priority":2,"issue_type":"task"}`)
+	theirs := writeMergeSide(t, dir, "theirs",
+		`{"id":"A","title":"Theirs","status":"open","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"B","status":"open","priority":2,"issue_type":"task"}`,
+	)
+	result, err := MergeJSONLFiles(filepath.Join(dir, "missing"), ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Field != "title" || result.Conflicts[0].Base != "" {
+		t.Errorf("expected a title conflict against an empty base, got %+v", result.Conflicts)
+	}
+	if lines := readLines(t, ours); len(lines) != 2 || !strings.Contains(lines[0], `"Ours"`) {
+		t.Errorf("ours should win until resolved:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestMergeState_ResolveAndApply(t *testing.T) {
+	beadsDir, path := writeMutationFixture(t,
+		`{"id":"A","title":"Ours","status":"open","priority":0,"issue_type":"task","close_reason":"kept"}`,
+		`{"id":"B","title":"B","status":"open","priority":2,"issue_type":"task"}`,
+	)
+	if state, err := LoadMergeState(beadsDir); err != nil || state != nil {
+		t.Fatalf("no state expected yet: %v %v", state, err)
+	}
+
+	base := mergeIssueFixture("A")
+	base.Title, base.Priority, base.Labels = "Base", 2, nil
+	base.CreatedAt, base.UpdatedAt = time.Time{}, time.Time{}
+	theirs := base.Clone()
+	theirs.Title, theirs.Priority = "Theirs", 3
+	snap, err := readJSONLSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := MergeIssues([]model.Issue{base}, snap.issues[:1], []model.Issue{theirs})
+	if result.Unresolved() != 2 {
+		t.Fatalf("want title and priority conflicts, got %+v", result.Conflicts)
+	}
+
+	state := &MergeState{Path: path, Ours: "HEAD", Theirs: "feature", Result: result}
+	if err := SaveMergeState(beadsDir, state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadMergeState(beadsDir)
+	if err != nil || loaded == nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if err := loaded.Result.Resolve("A", "priority", MergeTheirs); err != nil {
+		t.Fatal(err)
+	}
+
+	open, err := ApplyMergeState(beadsDir, loaded)
+	if err != nil || open != 1 {
+		t.Fatalf("apply: open=%d err=%v", open, err)
+	}
+	lines := readLines(t, path)
+	if !strings.Contains(lines[0], `"priority":3`) || !strings.Contains(lines[0], `"title":"Ours"`) || !strings.Contains(lines[0], `"close_reason":"kept"`) {
+		t.Errorf("partial resolution not applied: %s", lines[0])
+	}
+	if lines[1] != `{"id":"B","title":"B","status":"open","priority":2,"issue_type":"task"}` {
+		t.Errorf("unrelated line changed: %s", lines[1])
+	}
+	if _, err := os.Stat(MergeStatePath(beadsDir)); err != nil {
+		t.Errorf("state file should remain while conflicts are open: %v", err)
+	}
+
+	if err := loaded.Result.Resolve("A", "title", MergeTheirs); err != nil {
+		t.Fatal(err)
+	}
+	if open, err := ApplyMergeState(beadsDir, loaded); err != nil || open != 0 {
+		t.Fatalf("apply: open=%d err=%v", open, err)
+	}
+	if lines := readLines(t, path); !strings.Contains(lines[0], `"title":"Theirs"`) {
+		t.Errorf("title resolution not applied: %s", lines[0])
+	}
+	if _, err := os.Stat(MergeStatePath(beadsDir)); !os.IsNotExist(err) {
+		t.Errorf("state file should be removed once everything is resolved: %v", err)
+	}
+}
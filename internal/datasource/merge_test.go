@@ -0,0 +1,202 @@
+package datasource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+var mergeT0 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func mergeIssueFixture(id string) model.Issue {
+	return model.Issue{
+		ID:        id,
+		Title:     "Issue " + id,
+		Status:    model.StatusOpen,
+		Priority:  2,
+		IssueType: model.TypeTask,
+		CreatedAt: mergeT0,
+		UpdatedAt: mergeT0,
+		Labels:    []string{"core"},
+	}
+}
+
+func findMerged(t *testing.T, r *MergeResult, id string) model.Issue {
+	t.Helper()
+	for _, issue := range r.Issues {
+		if issue.ID == id {
+			return issue
+		}
+	}
+	t.Fatalf("issue %s missing from merge result", id)
+	return model.Issue{}
+}
+
+func TestMergeIssues_TakesOneSidedChanges(t *testing.T) {
+	base := mergeIssueFixture("A")
+	ours, theirs := base.Clone(), base.Clone()
+	ours.Title = "Renamed"
+	ours.UpdatedAt = mergeT0.Add(time.Hour)
+	theirs.Status = model.StatusInProgress
+	theirs.Assignee = "sam"
+	theirs.UpdatedAt = mergeT0.Add(2 * time.Hour)
+
+	r := MergeIssues([]model.Issue{base}, []model.Issue{ours}, []model.Issue{theirs})
+	if len(r.Conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", r.Conflicts)
+	}
+	got := findMerged(t, r, "A")
+	if got.Title != "Renamed" || got.Status != model.StatusInProgress || got.Assignee != "sam" {
+		t.Errorf("one-sided changes not combined: %+v", got)
+	}
+	if !got.UpdatedAt.Equal(theirs.UpdatedAt) {
+		t.Errorf("updated_at = %v, want the later side", got.UpdatedAt)
+	}
+	if r.Stats.Merged != 1 {
+		t.Errorf("stats = %+v", r.Stats)
+	}
+}
+
+func TestMergeIssues_BothSidesChangingAFieldConflicts(t *testing.T) {
+	base := mergeIssueFixture("A")
+	ours, theirs := base.Clone(), base.Clone()
+	ours.Priority = 0
+	theirs.Priority = 3
+	same := mergeIssueFixture("B")
+	same.Status = model.StatusClosed
+	sameTheirs := same.Clone()
+
+	r := MergeIssues(
+		[]model.Issue{base, mergeIssueFixture("B")},
+		[]model.Issue{ours, same},
+		[]model.Issue{theirs, sameTheirs},
+	)
+	if len(r.Conflicts) != 1 {
+		t.Fatalf("want one conflict (identical edits merge cleanly), got %+v", r.Conflicts)
+	}
+	c := r.Conflicts[0]
+	if c.IssueID != "A" || c.Field != "priority" || c.Base != 2 || c.Ours != 0 || c.Theirs != 3 {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+	if got := findMerged(t, r, "A"); got.Priority != 0 {
+		t.Errorf("unresolved conflict should keep ours, got P%d", got.Priority)
+	}
+	if r.Unresolved() != 1 || r.Stats.Conflicted != 1 || len(r.Pending) != 1 {
+		t.Errorf("unresolved=%d stats=%+v pending=%d", r.Unresolved(), r.Stats, len(r.Pending))
+	}
+
+	if err := r.Resolve("A", "priority", MergeTheirs); err != nil {
+		t.Fatal(err)
+	}
+	if got := findMerged(t, r, "A"); got.Priority != 3 {
+		t.Errorf("resolved to theirs, got P%d", got.Priority)
+	}
+	if r.Unresolved() != 0 {
+		t.Errorf("conflict still open after Resolve")
+	}
+	if err := r.Resolve("A", "title", MergeOurs); err == nil {
+		t.Error("resolving a field without a conflict should fail")
+	}
+}
+
+func TestMergeIssues_CollectionsMergeByMember(t *testing.T) {
+	base := mergeIssueFixture("A")
+	base.Labels = []string{"core", "old"}
+	base.Dependencies = []*model.Dependency{{IssueID: "A", DependsOnID: "X", Type: model.DepBlocks}}
+	base.Comments = []*model.Comment{{ID: 1, Text: "first", CreatedAt: mergeT0}}
+	base.CustomFields = model.CustomFields{"x_team": "core"}
+
+	ours, theirs := base.Clone(), base.Clone()
+	ours.Labels = []string{"core", "backend"} // removed old, added backend
+	ours.Dependencies = append(ours.Dependencies, &model.Dependency{IssueID: "A", DependsOnID: "Y", Type: model.DepBlocks})
+	ours.Comments = append(ours.Comments, &model.Comment{ID: 2, Text: "ours", CreatedAt: mergeT0.Add(time.Hour)})
+	ours.CustomFields = model.CustomFields{"x_team": "core", "x_sprint": float64(4)}
+	theirs.Labels = []string{"core", "old", "urgent"}
+	theirs.Dependencies = nil // dropped X
+	theirs.Comments = append(theirs.Comments, &model.Comment{ID: 3, Text: "theirs", CreatedAt: mergeT0.Add(30 * time.Minute)})
+	theirs.CustomFields = model.CustomFields{"x_team": "platform"}
+
+	r := MergeIssues([]model.Issue{base}, []model.Issue{ours}, []model.Issue{theirs})
+	if len(r.Conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", r.Conflicts)
+	}
+	got := findMerged(t, r, "A")
+	if !sameLabels(got.Labels, []string{"core", "backend", "urgent"}) {
+		t.Errorf("labels = %v", got.Labels)
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].DependsOnID != "Y" {
+		t.Errorf("dependencies = %+v", got.Dependencies)
+	}
+	if len(got.Comments) != 3 || got.Comments[1].Text != "theirs" || got.Comments[2].Text != "ours" {
+		t.Errorf("comments should be unioned in time order: %+v", got.Comments)
+	}
+	if got.CustomFields["x_team"] != "platform" || got.CustomFields["x_sprint"] != float64(4) {
+		t.Errorf("custom fields = %v", got.CustomFields)
+	}
+}
+
+func TestMergeIssues_MemberConflicts(t *testing.T) {
+	base := mergeIssueFixture("A")
+	base.Dependencies = []*model.Dependency{{IssueID: "A", DependsOnID: "X", Type: model.DepBlocks}}
+	base.Comments = []*model.Comment{{ID: 1, Text: "first", CreatedAt: mergeT0}}
+
+	ours, theirs := base.Clone(), base.Clone()
+	ours.Dependencies = []*model.Dependency{{IssueID: "A", DependsOnID: "X", Type: model.DepRelated}}
+	ours.Comments = []*model.Comment{{ID: 1, Text: "edited here", CreatedAt: mergeT0}}
+	theirs.Dependencies = nil
+	theirs.Comments = []*model.Comment{{ID: 1, Text: "edited there", CreatedAt: mergeT0}}
+
+	r := MergeIssues([]model.Issue{base}, []model.Issue{ours}, []model.Issue{theirs})
+	fields := map[string]MergeConflict{}
+	for _, c := range r.Conflicts {
+		fields[c.Field] = c
+	}
+	if dep, ok := fields["dependencies:X"]; !ok || dep.Ours != "related" || dep.Theirs != nil {
+		t.Errorf("retype vs removal should conflict: %+v", r.Conflicts)
+	}
+	if _, ok := fields["comments:1"]; !ok || len(r.Conflicts) != 2 {
+		t.Errorf("concurrent comment edits should conflict: %+v", r.Conflicts)
+	}
+
+	if err := r.Resolve("A", "dependencies:X", MergeTheirs); err != nil {
+		t.Fatal(err)
+	}
+	if got := findMerged(t, r, "A"); len(got.Dependencies) != 0 || got.Comments[0].Text != "edited here" {
+		t.Errorf("after resolving: deps=%+v comments=%+v", got.Dependencies, got.Comments)
+	}
+}
+
+func TestMergeIssues_AddDeleteAndDeleteModify(t *testing.T) {
+	keep, gone, edited := mergeIssueFixture("K"), mergeIssueFixture("G"), mergeIssueFixture("E")
+	editedOurs := edited.Clone()
+	editedOurs.Title = "Still needed"
+
+	r := MergeIssues(
+		[]model.Issue{keep, gone, edited},
+		[]model.Issue{keep, gone, editedOurs},
+		[]model.Issue{keep, mergeIssueFixture("N")}, // theirs deleted G and E, added N
+	)
+	ids := map[string]bool{}
+	for _, issue := range r.Issues {
+		ids[issue.ID] = true
+	}
+	if !ids["K"] || ids["G"] || !ids["N"] || !ids["E"] {
+		t.Errorf("merged ids = %v", ids)
+	}
+	if r.Stats.Added != 1 || r.Stats.Deleted != 1 || r.Stats.Unchanged != 1 || r.Stats.Conflicted != 1 {
+		t.Errorf("stats = %+v", r.Stats)
+	}
+	if len(r.Conflicts) != 1 || r.Conflicts[0].Field != MergeFieldIssue || r.Conflicts[0].Ours != "modified" {
+		t.Fatalf("want a delete/modify conflict, got %+v", r.Conflicts)
+	}
+
+	if err := r.Resolve("E", MergeFieldIssue, MergeTheirs); err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range r.Issues {
+		if issue.ID == "E" {
+			t.Error("resolving to theirs should delete E")
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/instance"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ErrIssueNotFound is returned when a mutation targets an unknown issue.
+var ErrIssueNotFound = errors.New("issue not found")
+
+// Mutation describes a change to a single issue. Nil pointers and empty
+// slices leave the corresponding field untouched.
+type Mutation struct {
+	IssueID  string
+	Status   *model.Status
+	Assignee *string
+	Priority *int
+
+	AddLabels    []string
+	RemoveLabels []string
+
+	// AddDependencies are edges from IssueID to other issues; only
+	// DependsOnID, Type and CreatedBy are used.
+	AddDependencies    []model.Dependency
+	RemoveDependencies []string // depends_on IDs
+
+	// CloseReason is recorded when the mutation closes the issue.
+	CloseReason string
+
+	// Precondition, if set, is checked against the current issue while the
+	// write lock is held, so check-then-write sequences (like claiming) are
+	// race-free across processes.
+	Precondition func(current model.Issue) error
+}
+
+// MutationOptions configures ApplyMutation.
+type MutationOptions struct {
+	// LockTimeout bounds the wait for the beads write lock
+	// (default instance.DefaultWriteLockTimeout).
+	LockTimeout time.Duration
+	// Now overrides the timestamp recorded in updated_at/closed_at.
+	Now time.Time
+}
+
+// MutationResult reports what ApplyMutation changed.
+type MutationResult struct {
+	Before  model.Issue `json:"before"`
+	After   model.Issue `json:"after"`
+	Changed []string    `json:"changed"`
+	// Source is the data source the issue was read from.
+	Source DataSource `json:"source"`
+	// Written lists the files that were rewritten (empty when nothing changed).
+	Written []string `json:"written"`
+}
+
+// ApplyMutation applies m to the issue store in beadsDir. It holds the
+// instance write lock for the duration, reads the current issue from the
+// selected source, and writes the result atomically to the JSONL file and,
+// when SQLite is the selected source, to beads.db as well.
+func ApplyMutation(beadsDir string, m Mutation, opts MutationOptions) (*MutationResult, error) {
+	if strings.TrimSpace(m.IssueID) == "" {
+		return nil, fmt.Errorf("issue ID cannot be empty")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	lock, err := instance.AcquireWriteLock(beadsDir, opts.LockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	source, issues, err := loadForMutation(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := -1
+	for i := range issues {
+		if issues[i].ID == m.IssueID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, m.IssueID)
+	}
+	before := issues[idx]
+
+	if m.Precondition != nil {
+		if err := m.Precondition(before); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.validate(issues); err != nil {
+		return nil, err
+	}
+
+	after, changed := m.Apply(before, now)
+	result := &MutationResult{Before: before, After: after, Changed: changed, Source: source}
+	if len(changed) == 0 {
+		return result, nil
+	}
+
+	// Prepare the JSONL rewrite first so a failure there leaves both stores
+	// untouched; the rename happens only after the database commit.
+	jsonlPath := source.Path
+	if source.Type == SourceTypeSQLite {
+		jsonlPath, err = loader.FindJSONLPath(beadsDir)
+		if err != nil {
+			jsonlPath = ""
+		}
+	}
+	var pending *pendingJSONLWrite
+	if jsonlPath != "" {
+		pending, err = prepareJSONLMutation(jsonlPath, m, before, after)
+		if err != nil {
+			return nil, err
+		}
+		defer pending.abort()
+	}
+
+	if source.Type == SourceTypeSQLite {
+		if err := writeSQLiteMutation(source.Path, m, before, after); err != nil {
+			return nil, err
+		}
+		result.Written = append(result.Written, source.Path)
+	}
+	if pending != nil {
+		if err := pending.commit(); err != nil {
+			return nil, err
+		}
+		result.Written = append(result.Written, jsonlPath)
+	}
+	return result, nil
+}
+
+// loadForMutation selects the source the same way LoadIssues does and loads
+// every issue from it (needed to validate dependency targets).
+func loadForMutation(beadsDir string) (DataSource, []model.Issue, error) {
+	sources, err := DiscoverSources(DiscoveryOptions{
+		BeadsDir:               beadsDir,
+		RepoPath:               filepath.Dir(beadsDir),
+		ValidateAfterDiscovery: true,
+	})
+	if err == nil && len(sources) > 0 {
+		if best, selErr := SelectBestSource(sources); selErr == nil {
+			issues, loadErr := LoadFromSource(best)
+			if loadErr == nil {
+				return best, issues, nil
+			}
+		}
+	}
+
+	jsonlPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return DataSource{}, nil, err
+	}
+	issues, err := loader.LoadIssuesFromFile(jsonlPath)
+	if err != nil {
+		return DataSource{}, nil, err
+	}
+	return DataSource{Type: SourceTypeJSONLLocal, Path: jsonlPath, Priority: PriorityJSONLLocal, Valid: true}, issues, nil
+}
+
+// validate checks the mutation against the full issue set.
+func (m Mutation) validate(issues []model.Issue) error {
+	if m.Status != nil && !m.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", *m.Status)
+	}
+	if m.Priority != nil && (*m.Priority < 0 || *m.Priority > 4) {
+		return fmt.Errorf("invalid priority %d (expected 0-4)", *m.Priority)
+	}
+	for _, label := range m.AddLabels {
+		if strings.TrimSpace(label) == "" || strings.ContainsAny(label, ",\n") {
+			return fmt.Errorf("invalid label %q", label)
+		}
+	}
+	if len(m.AddDependencies) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool, len(issues))
+	blocking := make(map[string][]string, len(issues))
+	for _, issue := range issues {
+		known[issue.ID] = true
+		for _, dep := range issue.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() {
+				blocking[issue.ID] = append(blocking[issue.ID], dep.DependsOnID)
+			}
+		}
+	}
+	for _, dep := range m.AddDependencies {
+		if dep.DependsOnID == m.IssueID {
+			return fmt.Errorf("%s cannot depend on itself", m.IssueID)
+		}
+		if !known[dep.DependsOnID] {
+			return fmt.Errorf("%w: %s", ErrIssueNotFound, dep.DependsOnID)
+		}
+		if dep.Type != "" && !dep.Type.IsValid() {
+			return fmt.Errorf("invalid dependency type: %s", dep.Type)
+		}
+		if dep.Type.IsBlocking() && reachable(blocking, dep.DependsOnID, m.IssueID) {
+			return fmt.Errorf("adding %s -> %s would create a dependency cycle", m.IssueID, dep.DependsOnID)
+		}
+	}
+	return nil
+}
+
+// reachable reports whether to can be reached from from along edges.
+func reachable(edges map[string][]string, from, to string) bool {
+	seen := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		for _, next := range edges[id] {
+			if !seen[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
+
+// Apply returns a copy of issue with the mutation applied and the names of
+// the JSON fields that changed. updated_at is bumped only when something
+// else changed.
+func (m Mutation) Apply(issue model.Issue, now time.Time) (model.Issue, []string) {
+	out := issue.Clone()
+	var changed []string
+
+	if m.Status != nil && *m.Status != out.Status {
+		wasClosed := out.Status.IsClosed()
+		out.Status = *m.Status
+		changed = append(changed, "status")
+		switch {
+		case out.Status.IsClosed():
+			t := now
+			out.ClosedAt = &t
+			changed = append(changed, "closed_at")
+		case wasClosed && out.ClosedAt != nil:
+			out.ClosedAt = nil
+			changed = append(changed, "closed_at")
+		}
+	}
+	if m.Assignee != nil && *m.Assignee != out.Assignee {
+		out.Assignee = *m.Assignee
+		changed = append(changed, "assignee")
+	}
+	if m.Priority != nil && *m.Priority != out.Priority {
+		out.Priority = *m.Priority
+		changed = append(changed, "priority")
+	}
+
+	if labels, ok := mergeLabels(out.Labels, m.AddLabels, m.RemoveLabels); ok {
+		out.Labels = labels
+		changed = append(changed, "labels")
+	}
+
+	if deps, ok := mergeDependencies(out.ID, out.Dependencies, m.AddDependencies, m.RemoveDependencies, now); ok {
+		out.Dependencies = deps
+		changed = append(changed, "dependencies")
+	}
+
+	if len(changed) > 0 {
+		out.UpdatedAt = now
+		changed = append(changed, "updated_at")
+	}
+	return out, changed
+}
+
+// mergeLabels applies additions and removals, preserving existing order.
+func mergeLabels(labels, add, remove []string) ([]string, bool) {
+	if len(add) == 0 && len(remove) == 0 {
+		return labels, false
+	}
+	drop := make(map[string]bool, len(remove))
+	for _, l := range remove {
+		drop[strings.TrimSpace(l)] = true
+	}
+	seen := make(map[string]bool, len(labels)+len(add))
+	var out []string
+	changed := false
+	for _, l := range labels {
+		if drop[l] {
+			changed = true
+			continue
+		}
+		seen[l] = true
+		out = append(out, l)
+	}
+	for _, l := range add {
+		l = strings.TrimSpace(l)
+		if l == "" || seen[l] || drop[l] {
+			continue
+		}
+		seen[l] = true
+		out = append(out, l)
+		changed = true
+	}
+	return out, changed
+}
+
+// mergeDependencies adds new edges (skipping duplicates) and removes edges
+// whose target is listed in remove.
+func mergeDependencies(issueID string, deps []*model.Dependency, add []model.Dependency, remove []string, now time.Time) ([]*model.Dependency, bool) {
+	if len(add) == 0 && len(remove) == 0 {
+		return deps, false
+	}
+	drop := make(map[string]bool, len(remove))
+	for _, id := range remove {
+		drop[id] = true
+	}
+	existing := make(map[string]bool, len(deps))
+	var out []*model.Dependency
+	changed := false
+	for _, dep := range deps {
+		if dep == nil {
+			continue
+		}
+		if drop[dep.DependsOnID] {
+			changed = true
+			continue
+		}
+		existing[dep.DependsOnID] = true
+		out = append(out, dep)
+	}
+	for _, dep := range add {
+		if existing[dep.DependsOnID] {
+			continue
+		}
+		if dep.Type == "" {
+			dep.Type = model.DepBlocks
+		}
+		existing[dep.DependsOnID] = true
+		out = append(out, &model.Dependency{
+			IssueID:     issueID,
+			DependsOnID: dep.DependsOnID,
+			Type:        dep.Type,
+			CreatedAt:   now,
+			CreatedBy:   dep.CreatedBy,
+		})
+		changed = true
+	}
+	return out, changed
+}
+
+// labelDelta computes the labels added and removed between two versions of
+// an issue, for stores that keep labels in a separate table.
+func labelDelta(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, l := range before {
+		inBefore[l] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, l := range after {
+		inAfter[l] = true
+		if !inBefore[l] {
+			added = append(added, l)
+		}
+	}
+	for _, l := range before {
+		if !inAfter[l] {
+			removed = append(removed, l)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// ErrAlreadyClaimed is returned by ClaimMutation's precondition when another
+// assignee already has the issue in progress.
+var ErrAlreadyClaimed = errors.New("issue already claimed")
+
+// ClaimMutation moves an issue to in_progress and assigns it. Claiming a
+// closed issue fails, as does claiming one that someone else has in
+// progress unless force is set.
+func ClaimMutation(issueID, assignee string, force bool) Mutation {
+	status := model.StatusInProgress
+	m := Mutation{IssueID: issueID, Status: &status}
+	if assignee != "" {
+		m.Assignee = &assignee
+	}
+	m.Precondition = func(cur model.Issue) error {
+		if cur.Status.IsClosed() || cur.Status.IsTombstone() {
+			return fmt.Errorf("cannot claim %s: issue is %s", cur.ID, cur.Status)
+		}
+		if !force && cur.Status == model.StatusInProgress && cur.Assignee != "" && cur.Assignee != assignee {
+			return fmt.Errorf("%w: %s is in progress by %s", ErrAlreadyClaimed, cur.ID, cur.Assignee)
+		}
+		return nil
+	}
+	return m
+}
+
+// CloseMutation closes an issue, recording reason when non-empty.
+func CloseMutation(issueID, reason string) Mutation {
+	status := model.StatusClosed
+	return Mutation{IssueID: issueID, Status: &status, CloseReason: reason}
+}
+
+// DefaultActor returns the name recorded as assignee for claims when none is
+// given: $BV_ACTOR, then $USER.
+func DefaultActor() string {
+	if actor := strings.TrimSpace(os.Getenv("BV_ACTOR")); actor != "" {
+		return actor
+	}
+	return strings.TrimSpace(os.Getenv("USER"))
+}
@@ -0,0 +1,401 @@
+package datasource
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func writeMutationFixture(t *testing.T, lines ...string) (string, string) {
+	t.Helper()
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(beadsDir, "beads.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return beadsDir, path
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+func statusPtr(s model.Status) *model.Status { return &s }
+func strPtr(s string) *string                { return &s }
+func intPtr(n int) *int                      { return &n }
+
+var mutationNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestApplyMutation_JSONLPatchesOnlyTargetLine(t *testing.T) {
+	lineA := `{"id":"A","title":"First <one>","status":"open","priority":2,"issue_type":"task","x_custom":{"keep":true}}`
+	lineB := `{"id":"B","title":"Second","status":"open","priority":1,"issue_type":"bug"}`
+	beadsDir, path := writeMutationFixture(t, lineA, lineB, `not json`)
+
+	res, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:   "A",
+		Status:    statusPtr(model.StatusInProgress),
+		Assignee:  strPtr("agent-7"),
+		Priority:  intPtr(0),
+		AddLabels: []string{"backend", "urgent"},
+	}, MutationOptions{Now: mutationNow})
+	if err != nil {
+		t.Fatalf("ApplyMutation: %v", err)
+	}
+	if strings.Join(res.Changed, ",") != "status,assignee,priority,labels,updated_at" {
+		t.Errorf("unexpected changed fields: %v", res.Changed)
+	}
+	if len(res.Written) != 1 || res.Written[0] != path {
+		t.Errorf("unexpected written files: %v", res.Written)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 3 || lines[1] != lineB || lines[2] != "not json" {
+		t.Fatalf("untouched lines were modified:\n%s", strings.Join(lines, "\n"))
+	}
+	for _, want := range []string{`"status":"in_progress"`, `"assignee":"agent-7"`, `"priority":0`,
+		`"labels":["backend","urgent"]`, `"x_custom":{"keep":true}`, `"title":"First <one>"`, `"updated_at":"2025-03-01T12:00:00Z"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("patched line missing %s: %s", want, lines[0])
+		}
+	}
+
+	issues, err := LoadIssuesFromDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues[0].ID != "A" && issues[1].ID != "A" {
+		t.Fatalf("issue A missing after write: %+v", issues)
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, ".bv.write.lock")); !os.IsNotExist(err) {
+		t.Error("write lock not released")
+	}
+}
+
+func TestApplyMutation_JSONLKeepsKeyOrderAndSpacing(t *testing.T) {
+	lineA := `{"status":"open", "assignee":"bob", "title":"First", "zeta":{"b":1,"a":2}, "id":"A","priority":2,"issue_type":"task"}`
+	lineB := `{ "title" : "Second", "id" : "B", "status" : "open", "priority" : 1, "issue_type" : "bug" }`
+	lineC := `{"id":"C","title":"Third","status":"open","priority":3,"issue_type":"task","custom":[3, 2, 1]}`
+	beadsDir, path := writeMutationFixture(t, lineA, lineB, lineC)
+
+	if _, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:   "A",
+		Status:    statusPtr(model.StatusInProgress),
+		Assignee:  strPtr(""),
+		AddLabels: []string{"x"},
+	}, MutationOptions{Now: mutationNow}); err != nil {
+		t.Fatalf("ApplyMutation A: %v", err)
+	}
+	if _, err := ApplyMutation(beadsDir, Mutation{IssueID: "B", Priority: intPtr(0)}, MutationOptions{Now: mutationNow}); err != nil {
+		t.Fatalf("ApplyMutation B: %v", err)
+	}
+
+	want := []string{
+		`{"status":"in_progress", "title":"First", "zeta":{"b":1,"a":2}, "id":"A","priority":2,"issue_type":"task","labels":["x"],"updated_at":"2025-03-01T12:00:00Z"}`,
+		`{ "title" : "Second", "id" : "B", "status" : "open", "priority" : 0, "issue_type" : "bug","updated_at":"2025-03-01T12:00:00Z" }`,
+		lineC,
+	}
+	got := readLines(t, path)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(want), len(got), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d:\n got %s\nwant %s", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestApplyMutation_CustomFieldsRoundTrip(t *testing.T) {
+	lineA := `{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task","story_points":5,"sla":{"tier":"gold"}}`
+	lineB := `{"id":"B","title":"Second","status":"open","priority":2,"issue_type":"task","custom_fields":{"customer":"acme","tags":["x","y"]}}`
+	beadsDir, path := writeMutationFixture(t, lineA, lineB)
+
+	loaded, err := LoadIssuesFromDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]model.CustomFields{}
+	for _, issue := range loaded {
+		want[issue.ID] = issue.CustomFields
+	}
+
+	for _, id := range []string{"A", "B"} {
+		if _, err := ApplyMutation(beadsDir, Mutation{IssueID: id, Status: statusPtr(model.StatusInProgress)}, MutationOptions{Now: mutationNow}); err != nil {
+			t.Fatalf("ApplyMutation %s: %v", id, err)
+		}
+	}
+	// An issue with no line yet is appended with its custom fields top-level.
+	added := model.Issue{ID: "C", Title: "Third", Status: model.StatusOpen, IssueType: model.TypeTask,
+		CustomFields: model.CustomFields{"story_points": 3.0}}
+	want["C"] = added.CustomFields
+	pending, err := prepareJSONLMutation(path, Mutation{IssueID: "C"}, added, added)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pending.commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLines(t, path)
+	for i, shape := range []string{`,"story_points":5,"sla":{"tier":"gold"}`, `,"custom_fields":{"customer":"acme","tags":["x","y"]}`, `,"story_points":3`} {
+		if !strings.Contains(lines[i], shape) || (i != 1 && strings.Contains(lines[i], "custom_fields")) {
+			t.Errorf("line %d lost its custom field shape %s: %s", i+1, shape, lines[i])
+		}
+	}
+	reloaded, err := LoadIssuesFromDir(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded) != 3 {
+		t.Fatalf("expected 3 issues after reload, got %d", len(reloaded))
+	}
+	for _, issue := range reloaded {
+		if !reflect.DeepEqual(issue.CustomFields, want[issue.ID]) {
+			t.Errorf("%s custom fields changed: got %v, want %v", issue.ID, issue.CustomFields, want[issue.ID])
+		}
+	}
+}
+
+func TestApplyMutation_CloseAndReopen(t *testing.T) {
+	beadsDir, path := writeMutationFixture(t,
+		`{"id":"A","title":"First","status":"in_progress","priority":2,"issue_type":"task"}`)
+
+	res, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:     "A",
+		Status:      statusPtr(model.StatusClosed),
+		CloseReason: "done",
+	}, MutationOptions{Now: mutationNow})
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if res.After.ClosedAt == nil || !res.After.ClosedAt.Equal(mutationNow) {
+		t.Errorf("closed_at not set: %+v", res.After.ClosedAt)
+	}
+	line := readLines(t, path)[0]
+	if !strings.Contains(line, `"close_reason":"done"`) || !strings.Contains(line, `"closed_at":"2025-03-01T12:00:00Z"`) {
+		t.Errorf("close not recorded: %s", line)
+	}
+
+	if _, err := ApplyMutation(beadsDir, Mutation{IssueID: "A", Status: statusPtr(model.StatusOpen)}, MutationOptions{Now: mutationNow.Add(time.Hour)}); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	line = readLines(t, path)[0]
+	if strings.Contains(line, "closed_at") || strings.Contains(line, "close_reason") {
+		t.Errorf("reopen left close metadata: %s", line)
+	}
+}
+
+func TestApplyMutation_NoChangeDoesNotWrite(t *testing.T) {
+	beadsDir, path := writeMutationFixture(t,
+		`{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task"}`)
+	before, _ := os.Stat(path)
+
+	res, err := ApplyMutation(beadsDir, Mutation{IssueID: "A", Status: statusPtr(model.StatusOpen)}, MutationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changed) != 0 || len(res.Written) != 0 {
+		t.Errorf("expected no-op, got %+v", res)
+	}
+	after, _ := os.Stat(path)
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Error("file rewritten for a no-op mutation")
+	}
+}
+
+func TestApplyMutation_Dependencies(t *testing.T) {
+	beadsDir, path := writeMutationFixture(t,
+		`{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"Second","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks","extra":"kept"}]}`,
+		`{"id":"C","title":"Third","status":"open","priority":2,"issue_type":"task"}`)
+
+	if _, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:         "B",
+		AddDependencies: []model.Dependency{{DependsOnID: "C", CreatedBy: "bv"}},
+	}, MutationOptions{Now: mutationNow}); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	line := readLines(t, path)[1]
+	if !strings.Contains(line, `"extra":"kept"`) || !strings.Contains(line, `"depends_on_id":"C","type":"blocks"`) {
+		t.Errorf("dependency not appended verbatim: %s", line)
+	}
+
+	cases := map[string]Mutation{
+		"cycle":          {IssueID: "A", AddDependencies: []model.Dependency{{DependsOnID: "B"}}},
+		"self":           {IssueID: "A", AddDependencies: []model.Dependency{{DependsOnID: "A"}}},
+		"unknown target": {IssueID: "A", AddDependencies: []model.Dependency{{DependsOnID: "Z"}}},
+		"bad priority":   {IssueID: "A", Priority: intPtr(9)},
+		"unknown issue":  {IssueID: "Z", Status: statusPtr(model.StatusClosed)},
+	}
+	for name, m := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ApplyMutation(beadsDir, m, MutationOptions{}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	// A related (non-blocking) edge does not participate in cycle checks.
+	if _, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:         "A",
+		AddDependencies: []model.Dependency{{DependsOnID: "B", Type: model.DepRelated}},
+	}, MutationOptions{}); err != nil {
+		t.Errorf("related edge rejected: %v", err)
+	}
+
+	if _, err := ApplyMutation(beadsDir, Mutation{IssueID: "B", RemoveDependencies: []string{"A"}}, MutationOptions{}); err != nil {
+		t.Fatalf("unlink: %v", err)
+	}
+	line = readLines(t, path)[1]
+	if strings.Contains(line, `"depends_on_id":"A"`) {
+		t.Errorf("dependency not removed: %s", line)
+	}
+}
+
+func TestApplyMutation_PreconditionAborts(t *testing.T) {
+	original := `{"id":"A","title":"First","status":"in_progress","assignee":"alice","priority":2,"issue_type":"task"}`
+	beadsDir, path := writeMutationFixture(t, original)
+
+	errClaimed := errors.New("already claimed")
+	_, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:  "A",
+		Assignee: strPtr("bob"),
+		Precondition: func(cur model.Issue) error {
+			if cur.Assignee != "" && cur.Assignee != "bob" {
+				return errClaimed
+			}
+			return nil
+		},
+	}, MutationOptions{})
+	if !errors.Is(err, errClaimed) {
+		t.Fatalf("expected precondition error, got %v", err)
+	}
+	if readLines(t, path)[0] != original {
+		t.Error("file changed despite failed precondition")
+	}
+}
+
+func TestApplyMutation_SQLiteSelectedWritesBoth(t *testing.T) {
+	beadsDir, jsonlPath := writeMutationFixture(t,
+		`{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"Second","status":"open","priority":2,"issue_type":"task"}`)
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(jsonlPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	dbPath := filepath.Join(beadsDir, "beads.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE issues (id TEXT PRIMARY KEY, title TEXT NOT NULL, description TEXT, status TEXT NOT NULL,
+			priority INTEGER DEFAULT 2, issue_type TEXT DEFAULT 'task', assignee TEXT, created_at DATETIME,
+			updated_at DATETIME, closed_at DATETIME, tombstone INTEGER DEFAULT 0)`,
+		`CREATE TABLE labels (issue_id TEXT NOT NULL, label TEXT NOT NULL, PRIMARY KEY (issue_id, label))`,
+		`CREATE TABLE dependencies (issue_id TEXT NOT NULL, depends_on_id TEXT NOT NULL, type TEXT,
+			created_at DATETIME, created_by TEXT, PRIMARY KEY (issue_id, depends_on_id))`,
+		`INSERT INTO issues (id, title, status) VALUES ('A', 'First', 'open'), ('B', 'Second', 'open')`,
+		`INSERT INTO labels (issue_id, label) VALUES ('A', 'old')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	res, err := ApplyMutation(beadsDir, Mutation{
+		IssueID:         "A",
+		Status:          statusPtr(model.StatusClosed),
+		AddLabels:       []string{"new"},
+		RemoveLabels:    []string{"old"},
+		AddDependencies: []model.Dependency{{DependsOnID: "B"}},
+	}, MutationOptions{Now: mutationNow})
+	if err != nil {
+		t.Fatalf("ApplyMutation: %v", err)
+	}
+	if res.Source.Type != SourceTypeSQLite || len(res.Written) != 2 {
+		t.Fatalf("expected SQLite source and two writes, got %s %v", res.Source.Type, res.Written)
+	}
+
+	db, err = sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var status string
+	var closedAt sql.NullTime
+	if err := db.QueryRow(`SELECT status, closed_at FROM issues WHERE id = 'A'`).Scan(&status, &closedAt); err != nil {
+		t.Fatal(err)
+	}
+	if status != "closed" || !closedAt.Valid {
+		t.Errorf("db not updated: status=%s closed_at=%v", status, closedAt)
+	}
+	var labels []string
+	rows, err := db.Query(`SELECT label FROM labels WHERE issue_id = 'A'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var l string
+		rows.Scan(&l)
+		labels = append(labels, l)
+	}
+	rows.Close()
+	if strings.Join(labels, ",") != "new" {
+		t.Errorf("labels table not updated: %v", labels)
+	}
+	var depType string
+	if err := db.QueryRow(`SELECT type FROM dependencies WHERE issue_id = 'A' AND depends_on_id = 'B'`).Scan(&depType); err != nil || depType != "blocks" {
+		t.Errorf("dependency not inserted: %q %v", depType, err)
+	}
+
+	line := readLines(t, jsonlPath)[0]
+	if !strings.Contains(line, `"status":"closed"`) || !strings.Contains(line, `"labels":["new"]`) {
+		t.Errorf("jsonl not updated alongside db: %s", line)
+	}
+}
+
+func TestClaimMutation(t *testing.T) {
+	beadsDir, _ := writeMutationFixture(t,
+		`{"id":"A","title":"First","status":"open","priority":2,"issue_type":"task"}`,
+		`{"id":"B","title":"Second","status":"closed","priority":2,"issue_type":"task"}`)
+
+	res, err := ApplyMutation(beadsDir, ClaimMutation("A", "alice", false), MutationOptions{})
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if res.After.Status != model.StatusInProgress || res.After.Assignee != "alice" {
+		t.Errorf("unexpected claim result: %+v", res.After)
+	}
+
+	// Re-claiming your own issue is a no-op; someone else needs force.
+	if res, err := ApplyMutation(beadsDir, ClaimMutation("A", "alice", false), MutationOptions{}); err != nil || len(res.Changed) != 0 {
+		t.Errorf("self re-claim: %v %+v", err, res)
+	}
+	if _, err := ApplyMutation(beadsDir, ClaimMutation("A", "bob", false), MutationOptions{}); !errors.Is(err, ErrAlreadyClaimed) {
+		t.Errorf("expected ErrAlreadyClaimed, got %v", err)
+	}
+	if res, err := ApplyMutation(beadsDir, ClaimMutation("A", "bob", true), MutationOptions{}); err != nil || res.After.Assignee != "bob" {
+		t.Errorf("forced claim: %v %+v", err, res)
+	}
+	if _, err := ApplyMutation(beadsDir, ClaimMutation("B", "bob", true), MutationOptions{}); err == nil {
+		t.Error("claiming a closed issue should fail")
+	}
+}
@@ -0,0 +1,214 @@
+package datasource
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// writeSQLiteMutation applies the before→after change to a beads SQLite
+// database in a single transaction. The schema is probed rather than
+// assumed: optional columns (assignee, closed_at, close_reason, ...) are only
+// written when present, and labels go to either the JSON column (bd) or the
+// separate labels table (br).
+func writeSQLiteMutation(path string, m Mutation, before, after model.Issue) error {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return fmt.Errorf("cannot open database: %w", err)
+	}
+	defer db.Close()
+
+	issueCols, err := tableColumns(db, "issues")
+	if err != nil {
+		return err
+	}
+	if len(issueCols) == 0 {
+		return fmt.Errorf("database %s has no issues table", path)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var sets []string
+	var args []any
+	setCol := func(col string, v any) {
+		if issueCols[col] {
+			sets = append(sets, col+" = ?")
+			args = append(args, v)
+		}
+	}
+
+	if after.Status != before.Status {
+		setCol("status", string(after.Status))
+	}
+	if after.Assignee != before.Assignee {
+		setCol("assignee", after.Assignee)
+	}
+	if after.Priority != before.Priority {
+		setCol("priority", after.Priority)
+	}
+	if !timePtrEqual(after.ClosedAt, before.ClosedAt) {
+		if after.ClosedAt == nil {
+			setCol("closed_at", nil)
+			setCol("close_reason", nil)
+		} else {
+			setCol("closed_at", *after.ClosedAt)
+			if m.CloseReason != "" {
+				setCol("close_reason", m.CloseReason)
+			}
+		}
+	}
+	labelsChanged := !stringSliceEqual(after.Labels, before.Labels)
+	if labelsChanged && issueCols["labels"] {
+		raw, err := json.Marshal(after.Labels)
+		if err != nil {
+			return err
+		}
+		if len(after.Labels) == 0 {
+			raw = []byte("[]")
+		}
+		setCol("labels", string(raw))
+	}
+	if !after.UpdatedAt.Equal(before.UpdatedAt) {
+		setCol("updated_at", after.UpdatedAt)
+	}
+
+	if len(sets) > 0 {
+		args = append(args, after.ID)
+		res, err := tx.Exec("UPDATE issues SET "+strings.Join(sets, ", ")+" WHERE id = ?", args...)
+		if err != nil {
+			return fmt.Errorf("updating issue %s: %w", after.ID, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w in database: %s", ErrIssueNotFound, after.ID)
+		}
+	}
+
+	if labelsChanged && !issueCols["labels"] {
+		if err := writeLabelsTable(tx, after.ID, before.Labels, after.Labels); err != nil {
+			return err
+		}
+	}
+	if len(m.AddDependencies) > 0 || len(m.RemoveDependencies) > 0 {
+		if err := writeDependencies(tx, before, after); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// tableColumns returns the lower-cased column names of table (empty when the
+// table does not exist).
+func tableColumns(q interface {
+	Query(string, ...any) (*sql.Rows, error)
+}, table string) (map[string]bool, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s schema: %w", table, err)
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notNull, pk int
+		var dflt any
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		cols[strings.ToLower(name)] = true
+	}
+	return cols, rows.Err()
+}
+
+func writeLabelsTable(tx *sql.Tx, issueID string, before, after []string) error {
+	cols, err := tableColumns(tx, "labels")
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		// Neither a labels column nor a labels table: nothing to update.
+		return nil
+	}
+	added, removed := labelDelta(before, after)
+	for _, l := range removed {
+		if _, err := tx.Exec("DELETE FROM labels WHERE issue_id = ? AND label = ?", issueID, l); err != nil {
+			return fmt.Errorf("removing label %q: %w", l, err)
+		}
+	}
+	for _, l := range added {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)", issueID, l); err != nil {
+			return fmt.Errorf("adding label %q: %w", l, err)
+		}
+	}
+	return nil
+}
+
+func writeDependencies(tx *sql.Tx, before, after model.Issue) error {
+	cols, err := tableColumns(tx, "dependencies")
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return fmt.Errorf("database has no dependencies table")
+	}
+	typeCol := "dependency_type"
+	if !cols[typeCol] {
+		typeCol = "type"
+	}
+
+	keep := make(map[string]bool, len(after.Dependencies))
+	for _, dep := range after.Dependencies {
+		keep[dep.DependsOnID] = true
+	}
+	had := make(map[string]bool, len(before.Dependencies))
+	for _, dep := range before.Dependencies {
+		if dep == nil {
+			continue
+		}
+		had[dep.DependsOnID] = true
+		if !keep[dep.DependsOnID] {
+			if _, err := tx.Exec("DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?", after.ID, dep.DependsOnID); err != nil {
+				return fmt.Errorf("removing dependency on %s: %w", dep.DependsOnID, err)
+			}
+		}
+	}
+
+	for _, dep := range after.Dependencies {
+		if had[dep.DependsOnID] {
+			continue
+		}
+		insertCols := []string{"issue_id", "depends_on_id"}
+		args := []any{after.ID, dep.DependsOnID}
+		if cols[typeCol] {
+			insertCols = append(insertCols, typeCol)
+			args = append(args, string(dep.Type))
+		}
+		if cols["created_at"] {
+			insertCols = append(insertCols, "created_at")
+			args = append(args, dep.CreatedAt)
+		}
+		if cols["created_by"] {
+			insertCols = append(insertCols, "created_by")
+			args = append(args, dep.CreatedBy)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(insertCols)), ", ")
+		stmt := fmt.Sprintf("INSERT OR IGNORE INTO dependencies (%s) VALUES (%s)", strings.Join(insertCols, ", "), placeholders)
+		if _, err := tx.Exec(stmt, args...); err != nil {
+			return fmt.Errorf("adding dependency on %s: %w", dep.DependsOnID, err)
+		}
+	}
+	return nil
+}
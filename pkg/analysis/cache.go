@@ -294,6 +294,7 @@ func ComputeDataHash(issues []model.Issue) string {
 				h.Write([]byte{0})
 			}
 		}
+		writeCustomFieldsHash(h, issue.CustomFields)
 
 		h.Write([]byte{1}) // issue separator
 	}
@@ -378,6 +379,72 @@ func ComputeIssueDiff(oldIssues, newIssues []model.Issue) IssueDiff {
 	return diff
 }
 
+// ComputeIssueDiffForIDs builds the same IssueDiff as ComputeIssueDiff when
+// the caller already knows which IDs were added, removed or touched (for
+// example from loader.IncrementalLoader). Only the touched IDs are
+// fingerprinted; every other ID in newIssues is reported as unchanged.
+// oldByID must describe the snapshot the change set is relative to.
+func ComputeIssueDiffForIDs(oldByID map[string]*model.Issue, newIssues []model.Issue, added, removed, touched []string) IssueDiff {
+	var diff IssueDiff
+	skip := make(map[string]bool, len(added)+len(touched))
+	for _, id := range added {
+		skip[id] = true
+	}
+	diff.Added = append(diff.Added, added...)
+	diff.Removed = append(diff.Removed, removed...)
+
+	touchedSet := make(map[string]bool, len(touched))
+	for _, id := range touched {
+		touchedSet[id] = true
+	}
+	newByID := make(map[string]*model.Issue, len(touched))
+	for i := range newIssues {
+		if touchedSet[newIssues[i].ID] {
+			newByID[newIssues[i].ID] = &newIssues[i]
+		}
+	}
+	for _, id := range touched {
+		newIssue, ok := newByID[id]
+		oldIssue, existed := oldByID[id]
+		if !ok || !existed {
+			continue
+		}
+		oldFP := ComputeIssueFingerprint(*oldIssue)
+		newFP := ComputeIssueFingerprint(*newIssue)
+		contentChanged := oldFP.ContentHash != newFP.ContentHash
+		dependencyChanged := oldFP.DependencyHash != newFP.DependencyHash
+		if !contentChanged && !dependencyChanged {
+			continue
+		}
+		skip[id] = true
+		diff.Modified = append(diff.Modified, id)
+		if contentChanged {
+			diff.ContentChanged = append(diff.ContentChanged, id)
+		}
+		if dependencyChanged {
+			diff.DependencyChanged = append(diff.DependencyChanged, id)
+		}
+	}
+
+	seen := make(map[string]bool, len(newIssues))
+	for i := range newIssues {
+		id := newIssues[i].ID
+		if skip[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		diff.Unchanged = append(diff.Unchanged, id)
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+	sort.Strings(diff.ContentChanged)
+	sort.Strings(diff.DependencyChanged)
+	sort.Strings(diff.Unchanged)
+	return diff
+}
+
 func computeIssueContentHash(issue model.Issue) string {
 	h := sha256.New()
 
@@ -435,6 +502,7 @@ func computeIssueContentHash(issue model.Issue) string {
 		}
 	}
 	writeStringHash(h, "")
+	writeCustomFieldsHash(h, issue.CustomFields)
 
 	return hex.EncodeToString(h.Sum(nil))[:16]
 }
@@ -484,6 +552,16 @@ func computeIssueDependencyHash(issue model.Issue) string {
 	return hex.EncodeToString(h.Sum(nil))[:16]
 }
 
+// writeCustomFieldsHash writes nothing for issues without custom fields so
+// that their hashes are unchanged from before custom fields existed.
+func writeCustomFieldsHash(w io.Writer, fields model.CustomFields) {
+	for _, name := range fields.Names() {
+		value, _ := json.Marshal(fields[name])
+		writeStringHash(w, name)
+		writeStringHash(w, string(value))
+	}
+}
+
 func writeStringHash(w io.Writer, v string) {
 	if v != "" {
 		_, _ = io.WriteString(w, v)
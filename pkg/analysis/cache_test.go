@@ -390,6 +390,33 @@ func TestComputeIssueDiff(t *testing.T) {
 	}
 }
 
+func TestComputeIssueDiffForIDs_MatchesFullDiff(t *testing.T) {
+	oldIssues := []model.Issue{
+		{ID: "A", Title: "Title", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "B", Title: "Blocked", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask},
+		{ID: "C", Title: "Removed", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "E", Title: "Unchanged", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+	}
+	newIssues := []model.Issue{
+		{ID: "A", Title: "Title", Status: model.StatusClosed, Priority: 1, IssueType: model.TypeTask},
+		{ID: "B", Title: "Blocked", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "D", Title: "Added", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "E", Title: "Unchanged", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+	}
+	oldByID := make(map[string]*model.Issue, len(oldIssues))
+	for i := range oldIssues {
+		oldByID[oldIssues[i].ID] = &oldIssues[i]
+	}
+
+	// E is touched (its line was rewritten) but its content is identical.
+	got := analysis.ComputeIssueDiffForIDs(oldByID, newIssues, []string{"D"}, []string{"C"}, []string{"A", "B", "E"})
+	want := analysis.ComputeIssueDiff(oldIssues, newIssues)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ComputeIssueDiffForIDs = %+v, want %+v", got, want)
+	}
+}
+
 func TestGlobalCache(t *testing.T) {
 	cache := analysis.GetGlobalCache()
 	if cache == nil {
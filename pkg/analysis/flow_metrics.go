@@ -0,0 +1,307 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ============================================================================
+// Flow Metrics
+// Lead time, cycle time, time in status and throughput, with percentiles
+// sliced by label, type, assignee and priority
+// ============================================================================
+
+// StatusChange is an issue entering a status at a point in time.
+type StatusChange struct {
+	Status model.Status `json:"status"`
+	At     time.Time    `json:"at"`
+}
+
+// StatusHistoryFromTimeline extracts each issue's status transitions, oldest
+// first, from a git-reconstructed timeline.
+func StatusHistoryFromTimeline(tl *correlation.Timeline) map[string][]StatusChange {
+	if tl == nil {
+		return nil
+	}
+	history := make(map[string][]StatusChange)
+	for _, ev := range tl.Events {
+		for _, c := range ev.Changes {
+			if c.Field != "status" {
+				continue
+			}
+			if status, ok := c.To.(string); ok && status != "" {
+				history[ev.IssueID] = append(history[ev.IssueID], StatusChange{Status: model.Status(status), At: ev.Timestamp})
+			}
+		}
+	}
+	return history
+}
+
+// FlowConfig controls which issues feed the flow metrics.
+type FlowConfig struct {
+	WindowDays int // Only issues closed in the last N days count toward lead/cycle time (0 = all)
+	Weeks      int // Weeks of throughput history
+}
+
+// DefaultFlowConfig returns a 90-day window with 8 weeks of throughput.
+func DefaultFlowConfig() FlowConfig {
+	return FlowConfig{WindowDays: 90, Weeks: 8}
+}
+
+// FlowPercentiles summarizes a distribution of durations in hours.
+type FlowPercentiles struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean_hours"`
+	P50   float64 `json:"p50_hours"`
+	P85   float64 `json:"p85_hours"`
+	P95   float64 `json:"p95_hours"`
+	Max   float64 `json:"max_hours"`
+}
+
+// ThroughputMetrics is the weekly closure rate of a slice, computed the same
+// way as label HistoricalVelocity.
+type ThroughputMetrics struct {
+	Closed        int              `json:"closed"`         // Closures in the window
+	WeeklyAverage float64          `json:"weekly_average"` // Mean closures per week
+	Trend         string           `json:"trend"`          // "accelerating", "decelerating", "stable", "erratic"
+	Weekly        []WeeklySnapshot `json:"weekly"`         // Newest first
+}
+
+// FlowSlice holds the flow metrics of one group of issues.
+type FlowSlice struct {
+	Issues       int                        `json:"issues"`
+	LeadTime     FlowPercentiles            `json:"lead_time"`                // created -> closed
+	CycleTime    FlowPercentiles            `json:"cycle_time"`               // first in_progress -> closed
+	TimeInStatus map[string]FlowPercentiles `json:"time_in_status,omitempty"` // Per non-closed status
+	Throughput   ThroughputMetrics          `json:"throughput"`
+}
+
+// FlowReport is the full flow analysis of a project.
+type FlowReport struct {
+	WindowDays int `json:"window_days"`
+	Weeks      int `json:"weeks"`
+	// StatusSource is "git" when status transitions were reconstructed from
+	// history, or "snapshot" when only created/closed timestamps were
+	// available (cycle time and time in status are then empty).
+	StatusSource string               `json:"status_source"`
+	Overall      FlowSlice            `json:"overall"`
+	ByLabel      map[string]FlowSlice `json:"by_label"`
+	ByType       map[string]FlowSlice `json:"by_type"`
+	ByAssignee   map[string]FlowSlice `json:"by_assignee"`
+	ByPriority   map[string]FlowSlice `json:"by_priority"`
+}
+
+// issueFlow is the per-issue input to the slice aggregates.
+type issueFlow struct {
+	issue    model.Issue // ClosedAt resolved from history when missing
+	inWindow bool        // Closed within the window
+	active   bool        // Still open, or closed within the window
+	lead     float64     // Hours, valid when hasLead
+	hasLead  bool
+	cycle    float64 // Hours, valid when hasCycle
+	hasCycle bool
+	inStatus map[string]float64 // Hours per status
+}
+
+// ComputeFlowMetrics computes lead time, cycle time, time in status and
+// throughput. history maps issue IDs to their status transitions (see
+// StatusHistoryFromTimeline) and may be nil.
+func ComputeFlowMetrics(issues []model.Issue, history map[string][]StatusChange, cfg FlowConfig, now time.Time) FlowReport {
+	if cfg.Weeks <= 0 {
+		cfg.Weeks = DefaultFlowConfig().Weeks
+	}
+	var windowStart time.Time
+	if cfg.WindowDays > 0 {
+		windowStart = now.AddDate(0, 0, -cfg.WindowDays)
+	}
+
+	flows := make([]issueFlow, 0, len(issues))
+	for _, iss := range issues {
+		flows = append(flows, computeIssueFlow(iss, history[iss.ID], windowStart, now))
+	}
+
+	report := FlowReport{
+		WindowDays:   cfg.WindowDays,
+		Weeks:        cfg.Weeks,
+		StatusSource: "snapshot",
+		Overall:      computeFlowSlice(flows, cfg.Weeks, now),
+		ByLabel:      make(map[string]FlowSlice),
+		ByType:       make(map[string]FlowSlice),
+		ByAssignee:   make(map[string]FlowSlice),
+		ByPriority:   make(map[string]FlowSlice),
+	}
+	if len(history) > 0 {
+		report.StatusSource = "git"
+	}
+
+	byLabel := make(map[string][]issueFlow)
+	byType := make(map[string][]issueFlow)
+	byAssignee := make(map[string][]issueFlow)
+	byPriority := make(map[string][]issueFlow)
+	for _, f := range flows {
+		for _, label := range f.issue.Labels {
+			byLabel[label] = append(byLabel[label], f)
+		}
+		byType[string(f.issue.IssueType)] = append(byType[string(f.issue.IssueType)], f)
+		assignee := f.issue.Assignee
+		if assignee == "" {
+			assignee = "unassigned"
+		}
+		byAssignee[assignee] = append(byAssignee[assignee], f)
+		priority := fmt.Sprintf("P%d", f.issue.Priority)
+		byPriority[priority] = append(byPriority[priority], f)
+	}
+	for key, group := range byLabel {
+		report.ByLabel[key] = computeFlowSlice(group, cfg.Weeks, now)
+	}
+	for key, group := range byType {
+		report.ByType[key] = computeFlowSlice(group, cfg.Weeks, now)
+	}
+	for key, group := range byAssignee {
+		report.ByAssignee[key] = computeFlowSlice(group, cfg.Weeks, now)
+	}
+	for key, group := range byPriority {
+		report.ByPriority[key] = computeFlowSlice(group, cfg.Weeks, now)
+	}
+	return report
+}
+
+func computeIssueFlow(iss model.Issue, changes []StatusChange, windowStart, now time.Time) issueFlow {
+	f := issueFlow{issue: iss}
+
+	created := iss.CreatedAt
+	if created.IsZero() && len(changes) > 0 {
+		created = changes[0].At
+	}
+
+	closed := isClosedLikeStatus(iss.Status)
+	if closed && iss.ClosedAt == nil {
+		for i := len(changes) - 1; i >= 0; i-- {
+			if isClosedLikeStatus(changes[i].Status) {
+				at := changes[i].At
+				f.issue.ClosedAt = &at
+				break
+			}
+		}
+	}
+	if closed && f.issue.ClosedAt != nil {
+		closedAt := *f.issue.ClosedAt
+		f.inWindow = windowStart.IsZero() || !closedAt.Before(windowStart)
+		if f.inWindow && !created.IsZero() && !closedAt.Before(created) {
+			f.lead, f.hasLead = closedAt.Sub(created).Hours(), true
+		}
+		if f.inWindow {
+			for _, c := range changes {
+				if c.Status == model.StatusInProgress && !c.At.After(closedAt) {
+					f.cycle, f.hasCycle = closedAt.Sub(c.At).Hours(), true
+					break
+				}
+			}
+		}
+	}
+	f.active = !closed || f.inWindow
+	if !f.active || len(changes) == 0 {
+		return f
+	}
+
+	f.inStatus = make(map[string]float64)
+	for i, c := range changes {
+		if isClosedLikeStatus(c.Status) {
+			continue
+		}
+		until := now
+		if i+1 < len(changes) {
+			until = changes[i+1].At
+		} else if f.issue.ClosedAt != nil {
+			until = *f.issue.ClosedAt
+		}
+		elapsed := 0.0
+		if until.After(c.At) {
+			elapsed = until.Sub(c.At).Hours()
+		}
+		f.inStatus[string(c.Status)] += elapsed
+	}
+	return f
+}
+
+func computeFlowSlice(flows []issueFlow, weeks int, now time.Time) FlowSlice {
+	var lead, cycle []float64
+	inStatus := make(map[string][]float64)
+	closedIssues := make([]model.Issue, 0, len(flows))
+	slice := FlowSlice{Issues: len(flows)}
+
+	for _, f := range flows {
+		if f.hasLead {
+			lead = append(lead, f.lead)
+		}
+		if f.hasCycle {
+			cycle = append(cycle, f.cycle)
+		}
+		for status, hours := range f.inStatus {
+			inStatus[status] = append(inStatus[status], hours)
+		}
+		if f.inWindow {
+			slice.Throughput.Closed++
+		}
+		if isClosedLikeStatus(f.issue.Status) && f.issue.ClosedAt != nil {
+			closedIssues = append(closedIssues, f.issue)
+		}
+	}
+
+	slice.LeadTime = ComputeFlowPercentiles(lead)
+	slice.CycleTime = ComputeFlowPercentiles(cycle)
+	if len(inStatus) > 0 {
+		slice.TimeInStatus = make(map[string]FlowPercentiles, len(inStatus))
+		for status, hours := range inStatus {
+			slice.TimeInStatus[status] = ComputeFlowPercentiles(hours)
+		}
+	}
+
+	hv := historicalVelocity("", closedIssues, weeks, now)
+	for i := range hv.WeeklyVelocity {
+		hv.WeeklyVelocity[i].IssueIDs = nil
+	}
+	slice.Throughput.Weekly = hv.WeeklyVelocity
+	slice.Throughput.WeeklyAverage = roundHours(hv.GetWeeklyAverage())
+	slice.Throughput.Trend = hv.GetVelocityTrend()
+	return slice
+}
+
+// ComputeFlowPercentiles summarizes durations in hours using nearest-rank
+// percentiles.
+func ComputeFlowPercentiles(hours []float64) FlowPercentiles {
+	if len(hours) == 0 {
+		return FlowPercentiles{}
+	}
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, h := range sorted {
+		sum += h
+	}
+	rank := func(p float64) float64 {
+		idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return roundHours(sorted[idx])
+	}
+	return FlowPercentiles{
+		Count: len(sorted),
+		Mean:  roundHours(sum / float64(len(sorted))),
+		P50:   rank(50),
+		P85:   rank(85),
+		P95:   rank(95),
+		Max:   roundHours(sorted[len(sorted)-1]),
+	}
+}
+
+func roundHours(h float64) float64 {
+	return math.Round(h*100) / 100
+}
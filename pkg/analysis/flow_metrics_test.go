@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestComputeFlowPercentiles(t *testing.T) {
+	p := ComputeFlowPercentiles([]float64{10, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	if p.Count != 10 || p.P50 != 5 || p.P85 != 9 || p.P95 != 10 || p.Max != 10 || p.Mean != 5.5 {
+		t.Errorf("unexpected percentiles: %+v", p)
+	}
+	if empty := ComputeFlowPercentiles(nil); empty.Count != 0 || empty.P95 != 0 {
+		t.Errorf("empty input should give zero percentiles: %+v", empty)
+	}
+}
+
+func TestComputeFlowMetrics_WithStatusHistory(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	at := func(hoursAgo int) time.Time { return now.Add(-time.Duration(hoursAgo) * time.Hour) }
+	closedAt := func(hoursAgo int) *time.Time { t := at(hoursAgo); return &t }
+
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusClosed, IssueType: model.TypeBug, Priority: 1, Assignee: "ann",
+			Labels: []string{"api"}, CreatedAt: at(100), ClosedAt: closedAt(10)},
+		{ID: "B", Status: model.StatusClosed, IssueType: model.TypeTask, Priority: 2,
+			Labels: []string{"api", "ui"}, CreatedAt: at(60)}, // closed_at comes from history
+		{ID: "C", Status: model.StatusBlocked, IssueType: model.TypeTask, Priority: 2, Assignee: "ann",
+			CreatedAt: at(30)},
+		{ID: "OLD", Status: model.StatusClosed, IssueType: model.TypeTask, Priority: 3,
+			CreatedAt: at(5000), ClosedAt: closedAt(4000)}, // outside the 90-day window
+	}
+	history := map[string][]StatusChange{
+		"A": {{model.StatusOpen, at(100)}, {model.StatusInProgress, at(40)}, {model.StatusReview, at(20)}, {model.StatusClosed, at(10)}},
+		"B": {{model.StatusOpen, at(60)}, {model.StatusInProgress, at(50)}, {model.StatusClosed, at(20)}},
+		"C": {{model.StatusOpen, at(30)}, {model.StatusBlocked, at(10)}},
+	}
+
+	report := ComputeFlowMetrics(issues, history, DefaultFlowConfig(), now)
+	if report.StatusSource != "git" {
+		t.Errorf("status source = %q", report.StatusSource)
+	}
+
+	overall := report.Overall
+	if overall.Issues != 4 || overall.Throughput.Closed != 2 {
+		t.Errorf("overall counts: issues=%d closed=%d", overall.Issues, overall.Throughput.Closed)
+	}
+	if overall.LeadTime.Count != 2 || overall.LeadTime.Max != 90 || overall.LeadTime.P50 != 40 {
+		t.Errorf("lead time: %+v", overall.LeadTime)
+	}
+	if overall.CycleTime.Count != 2 || overall.CycleTime.P50 != 30 || overall.CycleTime.Max != 30 {
+		t.Errorf("cycle time: %+v", overall.CycleTime)
+	}
+	if blocked := overall.TimeInStatus["blocked"]; blocked.Count != 1 || blocked.Max != 10 {
+		t.Errorf("blocked time: %+v", blocked)
+	}
+	if review := overall.TimeInStatus["review"]; review.Count != 1 || review.Max != 10 {
+		t.Errorf("review time: %+v", review)
+	}
+	if _, ok := overall.TimeInStatus["closed"]; ok {
+		t.Error("closed is terminal and should not be timed")
+	}
+
+	if api := report.ByLabel["api"]; api.Issues != 2 || api.LeadTime.Count != 2 {
+		t.Errorf("api slice: %+v", api)
+	}
+	if bug := report.ByType["bug"]; bug.CycleTime.Count != 1 || bug.CycleTime.Max != 30 {
+		t.Errorf("bug slice: %+v", bug.CycleTime)
+	}
+	if ann := report.ByAssignee["ann"]; ann.Issues != 2 {
+		t.Errorf("ann slice: %+v", ann)
+	}
+	if _, ok := report.ByAssignee["unassigned"]; !ok {
+		t.Error("issues without assignee should be grouped as unassigned")
+	}
+	if p2 := report.ByPriority["P2"]; p2.Issues != 2 || p2.Throughput.Closed != 1 {
+		t.Errorf("P2 slice: %+v", p2)
+	}
+	if len(overall.Throughput.Weekly) != 8 || overall.Throughput.Weekly[0].Cumulative != 2 {
+		t.Errorf("weekly throughput: %+v", overall.Throughput.Weekly)
+	}
+}
+
+func TestComputeFlowMetrics_SnapshotOnly(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	closed := now.Add(-24 * time.Hour)
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusClosed, CreatedAt: now.Add(-72 * time.Hour), ClosedAt: &closed},
+	}
+	report := ComputeFlowMetrics(issues, nil, FlowConfig{}, now)
+	if report.StatusSource != "snapshot" || report.Weeks != 8 {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+	if report.Overall.LeadTime.P50 != 48 || report.Overall.CycleTime.Count != 0 || report.Overall.TimeInStatus != nil {
+		t.Errorf("snapshot-only metrics: %+v", report.Overall)
+	}
+}
+
+func TestStatusHistoryFromTimeline(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tl := &correlation.Timeline{Events: []correlation.TimelineEvent{
+		{IssueID: "A", Timestamp: t0, Changes: []correlation.FieldChange{{Field: "status", To: "open"}, {Field: "priority", To: 1}}},
+		{IssueID: "A", Timestamp: t0.Add(time.Hour), Changes: []correlation.FieldChange{{Field: "title", To: "x"}}},
+		{IssueID: "A", Timestamp: t0.Add(2 * time.Hour), Changes: []correlation.FieldChange{{Field: "status", From: "open", To: "closed"}}},
+	}}
+	history := StatusHistoryFromTimeline(tl)
+	if got := history["A"]; len(got) != 2 || got[1].Status != model.StatusClosed || !got[1].At.Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("unexpected history: %+v", got)
+	}
+	if StatusHistoryFromTimeline(nil) != nil {
+		t.Error("nil timeline should give nil history")
+	}
+}
@@ -1173,6 +1173,7 @@ type Analyzer struct {
 	blockerCounts    []int
 	blockerCountsMax int
 	config           *AnalysisConfig // Optional custom config, nil means use size-based defaults
+	profile          *ScoringProfile // Optional scoring profile, nil means the compiled-in weights
 }
 
 // SetConfig sets a custom analysis configuration.
@@ -1181,6 +1182,20 @@ func (a *Analyzer) SetConfig(config *AnalysisConfig) {
 	a.config = config
 }
 
+// SetScoringProfile sets the profile used for impact scores, priority
+// recommendations and triage. Pass nil for the compiled-in weights.
+func (a *Analyzer) SetScoringProfile(p *ScoringProfile) {
+	a.profile = p
+}
+
+// ScoringProfile returns the active scoring profile
+func (a *Analyzer) ScoringProfile() *ScoringProfile {
+	if a.profile == nil {
+		return DefaultScoringProfile()
+	}
+	return a.profile
+}
+
 func (a *Analyzer) graphStructureHash() string {
 	if a == nil || a.g == nil {
 		return "none"
@@ -2081,12 +2081,6 @@ func (r *LabelAttentionResult) GetLabelAttention(label string) *LabelAttentionSc
 // This enables trend analysis, anomaly detection, and forecasting.
 // Uses ClosedAt timestamps from issues to bucket closures into weeks.
 func ComputeHistoricalVelocity(issues []model.Issue, label string, numWeeks int, now time.Time) HistoricalVelocity {
-	result := HistoricalVelocity{
-		Label:          label,
-		WeeklyVelocity: make([]WeeklySnapshot, numWeeks),
-		WeeksAnalyzed:  numWeeks,
-	}
-
 	// Filter to labeled issues
 	var labeled []model.Issue
 	for _, iss := range issues {
@@ -2097,6 +2091,16 @@ func ComputeHistoricalVelocity(issues []model.Issue, label string, numWeeks int,
 			}
 		}
 	}
+	return historicalVelocity(label, labeled, numWeeks, now)
+}
+
+// historicalVelocity buckets the closures of an already-filtered issue set.
+func historicalVelocity(label string, labeled []model.Issue, numWeeks int, now time.Time) HistoricalVelocity {
+	result := HistoricalVelocity{
+		Label:          label,
+		WeeklyVelocity: make([]WeeklySnapshot, numWeeks),
+		WeeksAnalyzed:  numWeeks,
+	}
 
 	// Calculate week boundaries (weeks start on Monday)
 	// weekStart aligns to the Monday of the current week
@@ -0,0 +1,535 @@
+package analysis
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// MonteCarloOptions configures SimulateDelivery.
+type MonteCarloOptions struct {
+	Trials     int                       // Simulated futures (default 1000)
+	Agents     int                       // Issues worked in parallel (default 1)
+	Seed       int64                     // RNG seed; the same seed gives the same forecast (default 1)
+	Now        time.Time                 // Simulation start (default time.Now())
+	History    map[string][]StatusChange // Optional status transitions for cycle time (see StatusHistoryFromTimeline)
+	WindowDays int                       // Closures older than this are not sampled (default 90, negative = all)
+	Weeks      int                       // Weeks of throughput to resample (default 8)
+	// Past supplies the closures that are resampled when it differs from the
+	// issues being simulated, so hypothetical closures in a what-if scenario
+	// don't count as delivered work (default: the simulated issues)
+	Past []model.Issue
+}
+
+// ForecastBucket is one bar of the completion-date histogram.
+type ForecastBucket struct {
+	FromDays   float64 `json:"from_days"`
+	ToDays     float64 `json:"to_days"`
+	Count      int     `json:"count"`
+	Cumulative float64 `json:"cumulative"` // Share of trials done by ToDays (0..1)
+}
+
+// MonteCarloForecast is the completion-date distribution for a set of
+// issues. Scope and Target echo what was forecast ("issue", "epic",
+// "label", "sprint" or "all") and are filled in by the caller.
+type MonteCarloForecast struct {
+	Scope         string           `json:"scope,omitempty"`
+	Target        string           `json:"target,omitempty"`
+	Trials        int              `json:"trials"`
+	Agents        int              `json:"agents"`
+	Seed          int64            `json:"seed"`
+	Issues        int              `json:"issues"`        // Open issues in scope
+	Prerequisites int              `json:"prerequisites"` // Open blockers outside the scope that must finish first
+	Source        string           `json:"duration_source"`
+	Samples       int              `json:"samples"` // Historical durations available to resample
+	P50Days       float64          `json:"p50_days"`
+	P85Days       float64          `json:"p85_days"`
+	P95Days       float64          `json:"p95_days"`
+	P50           time.Time        `json:"p50_date"`
+	P85           time.Time        `json:"p85_date"`
+	P95           time.Time        `json:"p95_date"`
+	Histogram     []ForecastBucket `json:"histogram,omitempty"`
+	Cycles        bool             `json:"cycles,omitempty"` // Dependency cycles were broken to simulate
+}
+
+// Duration sources, from most to least faithful
+const (
+	SourceCycleTime = "cycle_time" // First in_progress to close, from git history
+	SourceLeadTime  = "lead_time"  // Created to close; pessimistic, includes queue time
+	SourceEstimate  = "estimate"   // No closed issues: ETA estimates with jitter
+	SourceNone      = "none"       // Nothing open in scope
+)
+
+const forecastHistogramBuckets = 16
+
+// SimulateDelivery forecasts when every open issue in targetIDs is done.
+// Each trial draws a duration for every issue from historical cycle times
+// of issues sharing its labels (falling back to all closures, then to the
+// ETA estimate), scales it by a resampled week of that label's throughput
+// relative to its average, and list-schedules the work over the blocking
+// graph with opts.Agents working in priority order. Open blockers outside
+// targetIDs are scheduled too, since the targets cannot finish before them.
+func SimulateDelivery(issues []model.Issue, targetIDs []string, opts MonteCarloOptions) MonteCarloForecast {
+	if opts.Trials <= 0 {
+		opts.Trials = 1000
+	}
+	if opts.Agents <= 0 {
+		opts.Agents = 1
+	}
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.WindowDays == 0 {
+		opts.WindowDays = DefaultFlowConfig().WindowDays
+	}
+	if opts.Weeks <= 0 {
+		opts.Weeks = DefaultFlowConfig().Weeks
+	}
+
+	fc := MonteCarloForecast{Trials: opts.Trials, Agents: opts.Agents, Seed: opts.Seed, Source: SourceNone}
+
+	issueMap := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		issueMap[iss.ID] = iss
+	}
+	targets := make(map[string]bool)
+	for _, id := range targetIDs {
+		if iss, ok := issueMap[id]; ok && !isClosedLikeStatus(iss.Status) {
+			targets[id] = true
+		}
+	}
+	fc.Issues = len(targets)
+	if len(targets) == 0 {
+		fc.P50, fc.P85, fc.P95 = opts.Now, opts.Now, opts.Now
+		return fc
+	}
+
+	work := forecastWorkSet(issueMap, targets)
+	fc.Prerequisites = len(work) - len(targets)
+
+	// Blocking edges within the work set, with cycles broken by dropping
+	// edges that point backwards in the topological order
+	ids := make([]string, 0, len(work))
+	for id := range work {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	blockers := make(map[string][]string, len(ids))
+	dependents := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		for _, dep := range issueMap[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == id || !work[dep.DependsOnID] {
+				continue
+			}
+			blockers[id] = append(blockers[id], dep.DependsOnID)
+			dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], id)
+		}
+	}
+	order, cycles := timelineOrder(ids, blockers, dependents)
+	fc.Cycles = cycles
+	sim := newForecastSim(order, blockers, issueMap, targets, opts.Agents)
+
+	past := issues
+	if opts.Past != nil {
+		past = opts.Past
+	}
+	history := buildForecastHistory(past, opts)
+	fc.Source, fc.Samples = history.source, len(history.global)
+	estimates := make(map[string]float64)
+	if fc.Source == SourceEstimate {
+		for _, id := range order {
+			if eta, err := EstimateETAForIssue(past, nil, id, 1, opts.Now); err == nil {
+				estimates[id] = eta.EstimatedDays
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	results := make([]float64, opts.Trials)
+	durations := make([]float64, len(order))
+	for trial := range results {
+		pace := history.samplePaces(rng)
+		for i, id := range order {
+			if fc.Source == SourceEstimate {
+				durations[i] = estimates[id] * triangular(rng, 0.6, 1.0, 1.8)
+				continue
+			}
+			labels := issueMap[id].Labels
+			durations[i] = history.sampleDuration(rng, labels) / history.paceFor(pace, labels)
+		}
+		results[trial] = sim.run(durations)
+	}
+
+	sort.Float64s(results)
+	fc.P50Days = roundDays(nearestRank(results, 50))
+	fc.P85Days = roundDays(nearestRank(results, 85))
+	fc.P95Days = roundDays(nearestRank(results, 95))
+	fc.P50 = opts.Now.Add(durationDays(fc.P50Days))
+	fc.P85 = opts.Now.Add(durationDays(fc.P85Days))
+	fc.P95 = opts.Now.Add(durationDays(fc.P95Days))
+	fc.Histogram = forecastHistogram(results)
+	return fc
+}
+
+// EpicDescendants returns the IDs of every issue below epicID through
+// parent-child dependencies, in ID order. The epic itself is not included.
+func EpicDescendants(issues []model.Issue, epicID string) []string {
+	children := make(map[string][]string)
+	for _, iss := range issues {
+		for _, dep := range iss.Dependencies {
+			if dep != nil && dep.Type == model.DepParentChild {
+				children[dep.DependsOnID] = append(children[dep.DependsOnID], iss.ID)
+			}
+		}
+	}
+	seen := map[string]bool{epicID: true}
+	queue := []string{epicID}
+	var out []string
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			out = append(out, child)
+			queue = append(queue, child)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// forecastWorkSet returns the targets plus their open transitive blockers.
+func forecastWorkSet(issueMap map[string]model.Issue, targets map[string]bool) map[string]bool {
+	work := make(map[string]bool, len(targets))
+	queue := make([]string, 0, len(targets))
+	for id := range targets {
+		work[id] = true
+		queue = append(queue, id)
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dep := range issueMap[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || work[dep.DependsOnID] {
+				continue
+			}
+			blocker, ok := issueMap[dep.DependsOnID]
+			if !ok || isClosedLikeStatus(blocker.Status) {
+				continue
+			}
+			work[dep.DependsOnID] = true
+			queue = append(queue, dep.DependsOnID)
+		}
+	}
+	return work
+}
+
+// forecastHistory holds the resampling pools: durations in days and weekly
+// closure counts, overall and per label.
+type forecastHistory struct {
+	source      string
+	global      []float64
+	byLabel     map[string][]float64
+	weekly      []int
+	weeklyLabel map[string][]int
+}
+
+func buildForecastHistory(issues []model.Issue, opts MonteCarloOptions) forecastHistory {
+	var windowStart time.Time
+	if opts.WindowDays > 0 {
+		windowStart = opts.Now.AddDate(0, 0, -opts.WindowDays)
+	}
+
+	h := forecastHistory{byLabel: make(map[string][]float64), weeklyLabel: make(map[string][]int)}
+	var cycle, lead []float64
+	cycleByLabel := make(map[string][]float64)
+	leadByLabel := make(map[string][]float64)
+	var closed []model.Issue
+	closedByLabel := make(map[string][]model.Issue)
+	for _, iss := range issues {
+		f := computeIssueFlow(iss, opts.History[iss.ID], windowStart, opts.Now)
+		if f.hasCycle {
+			cycle = append(cycle, f.cycle/24)
+			for _, l := range iss.Labels {
+				cycleByLabel[l] = append(cycleByLabel[l], f.cycle/24)
+			}
+		}
+		if f.hasLead {
+			lead = append(lead, f.lead/24)
+			for _, l := range iss.Labels {
+				leadByLabel[l] = append(leadByLabel[l], f.lead/24)
+			}
+		}
+		if isClosedLikeStatus(f.issue.Status) && f.issue.ClosedAt != nil {
+			closed = append(closed, f.issue)
+			for _, l := range iss.Labels {
+				closedByLabel[l] = append(closedByLabel[l], f.issue)
+			}
+		}
+	}
+
+	switch {
+	case len(cycle) > 0:
+		h.source, h.global, h.byLabel = SourceCycleTime, cycle, cycleByLabel
+	case len(lead) > 0:
+		h.source, h.global, h.byLabel = SourceLeadTime, lead, leadByLabel
+	default:
+		h.source = SourceEstimate
+	}
+
+	weekCounts := func(closed []model.Issue) []int {
+		hv := historicalVelocity("", closed, opts.Weeks, opts.Now)
+		counts := make([]int, 0, len(hv.WeeklyVelocity))
+		for _, w := range hv.WeeklyVelocity {
+			counts = append(counts, w.Closed)
+		}
+		return counts
+	}
+	h.weekly = weekCounts(closed)
+	for l, group := range closedByLabel {
+		h.weeklyLabel[l] = weekCounts(group)
+	}
+	return h
+}
+
+// samplePaces draws one week of throughput per pool and expresses it
+// relative to that pool's average: 2 means twice the usual pace. Paces are
+// clamped to 0.5-2 so the empty weeks of sparse histories don't dominate.
+// The global pace is stored under "".
+func (h forecastHistory) samplePaces(rng *rand.Rand) map[string]float64 {
+	paces := make(map[string]float64, len(h.weeklyLabel)+1)
+	paces[""] = samplePace(rng, h.weekly)
+	labels := make([]string, 0, len(h.weeklyLabel))
+	for l := range h.weeklyLabel {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels) // Fixed draw order keeps seeded runs reproducible
+	for _, l := range labels {
+		paces[l] = samplePace(rng, h.weeklyLabel[l])
+	}
+	return paces
+}
+
+func samplePace(rng *rand.Rand, weeks []int) float64 {
+	total := 0
+	for _, w := range weeks {
+		total += w
+	}
+	if total == 0 {
+		return 1
+	}
+	mean := float64(total) / float64(len(weeks))
+	return clampFloat(float64(weeks[rng.Intn(len(weeks))])/mean, 0.5, 2)
+}
+
+// paceFor returns the pace of the issue's first label with throughput
+// history, or the global pace.
+func (h forecastHistory) paceFor(paces map[string]float64, labels []string) float64 {
+	for _, l := range labels {
+		if _, ok := h.weeklyLabel[l]; ok {
+			return paces[l]
+		}
+	}
+	return paces[""]
+}
+
+// sampleDuration draws from the durations of closed issues sharing any of
+// labels, or from all closures when none do.
+func (h forecastHistory) sampleDuration(rng *rand.Rand, labels []string) float64 {
+	total := 0
+	for _, l := range labels {
+		total += len(h.byLabel[l])
+	}
+	if total == 0 {
+		return h.global[rng.Intn(len(h.global))]
+	}
+	n := rng.Intn(total)
+	for _, l := range labels {
+		if n < len(h.byLabel[l]) {
+			return h.byLabel[l][n]
+		}
+		n -= len(h.byLabel[l])
+	}
+	return h.global[0] // unreachable
+}
+
+// forecastSim list-schedules the work set for one trial. Issues are
+// indexed by their position in the topological order.
+type forecastSim struct {
+	agents     int
+	target     []bool
+	priority   []int
+	indegree   []int
+	dependents [][]int
+}
+
+func newForecastSim(order []string, blockers map[string][]string, issueMap map[string]model.Issue, targets map[string]bool, agents int) forecastSim {
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+	s := forecastSim{
+		agents:     agents,
+		target:     make([]bool, len(order)),
+		priority:   make([]int, len(order)),
+		indegree:   make([]int, len(order)),
+		dependents: make([][]int, len(order)),
+	}
+	for i, id := range order {
+		s.target[i] = targets[id]
+		s.priority[i] = issueMap[id].Priority
+		for _, b := range blockers[id] {
+			// Edges pointing backwards only exist inside broken cycles
+			if j := position[b]; j < i {
+				s.indegree[i]++
+				s.dependents[j] = append(s.dependents[j], i)
+			}
+		}
+	}
+	return s
+}
+
+// run returns the day the last target finishes given per-issue durations.
+func (s forecastSim) run(durations []float64) float64 {
+	indegree := append([]int(nil), s.indegree...)
+	ready := &readyQueue{priority: s.priority}
+	for i, n := range indegree {
+		if n == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	running := &runningQueue{}
+	now, last := 0.0, 0.0
+	for ready.Len() > 0 || running.Len() > 0 {
+		// Free agents take the most urgent ready issues
+		for ready.Len() > 0 && running.Len() < s.agents {
+			i := heap.Pop(ready).(int)
+			heap.Push(running, runningJob{issue: i, finish: now + durations[i]})
+		}
+
+		done := heap.Pop(running).(runningJob)
+		now = done.finish
+		if s.target[done.issue] {
+			last = math.Max(last, now)
+		}
+		for _, d := range s.dependents[done.issue] {
+			indegree[d]--
+			if indegree[d] == 0 {
+				heap.Push(ready, d)
+			}
+		}
+	}
+	return last
+}
+
+// readyQueue orders ready issues by priority, then topological position.
+type readyQueue struct {
+	items    []int
+	priority []int
+}
+
+func (q readyQueue) Len() int { return len(q.items) }
+func (q readyQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.priority[a] != q.priority[b] {
+		return q.priority[a] < q.priority[b]
+	}
+	return a < b
+}
+func (q readyQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *readyQueue) Push(x any)   { q.items = append(q.items, x.(int)) }
+func (q *readyQueue) Pop() any {
+	x := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return x
+}
+
+type runningJob struct {
+	issue  int
+	finish float64
+}
+
+// runningQueue orders in-flight issues by finish time.
+type runningQueue []runningJob
+
+func (q runningQueue) Len() int { return len(q) }
+func (q runningQueue) Less(i, j int) bool {
+	if q[i].finish != q[j].finish {
+		return q[i].finish < q[j].finish
+	}
+	return q[i].issue < q[j].issue
+}
+func (q runningQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *runningQueue) Push(x any)   { *q = append(*q, x.(runningJob)) }
+func (q *runningQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+// triangular samples a triangular distribution on [lo, hi] peaking at mode.
+func triangular(rng *rand.Rand, lo, mode, hi float64) float64 {
+	u := rng.Float64()
+	cut := (mode - lo) / (hi - lo)
+	if u < cut {
+		return lo + math.Sqrt(u*(hi-lo)*(mode-lo))
+	}
+	return hi - math.Sqrt((1-u)*(hi-lo)*(hi-mode))
+}
+
+// nearestRank returns the p-th percentile of sorted values.
+func nearestRank(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func roundDays(d float64) float64 {
+	return math.Round(d*10) / 10
+}
+
+// forecastHistogram buckets sorted trial results into equal-width bars.
+func forecastHistogram(sorted []float64) []ForecastBucket {
+	if len(sorted) == 0 {
+		return nil
+	}
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	buckets := forecastHistogramBuckets
+	width := (hi - lo) / float64(buckets)
+	if width <= 0 {
+		return []ForecastBucket{{FromDays: roundDays(lo), ToDays: roundDays(hi), Count: len(sorted), Cumulative: 1}}
+	}
+
+	out := make([]ForecastBucket, buckets)
+	for i := range out {
+		out[i].FromDays = roundDays(lo + float64(i)*width)
+		out[i].ToDays = roundDays(lo + float64(i+1)*width)
+	}
+	for _, v := range sorted {
+		i := min(int((v-lo)/width), buckets-1)
+		out[i].Count++
+	}
+	seen := 0
+	for i := range out {
+		seen += out[i].Count
+		out[i].Cumulative = math.Round(float64(seen)/float64(len(sorted))*1000) / 1000
+	}
+	return out
+}
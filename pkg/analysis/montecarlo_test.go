@@ -0,0 +1,166 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestSimulateDelivery(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, n) }
+	closed := func(n int) *time.Time { d := day(n); return &d }
+	est := 480
+
+	// Six api closures that took 1, 2, 2, 3, 5 and 8 days.
+	closures := []model.Issue{
+		{ID: "done-a", Status: model.StatusClosed, Labels: []string{"api"}, CreatedAt: day(-4), ClosedAt: closed(-3)},
+		{ID: "done-b", Status: model.StatusClosed, Labels: []string{"api"}, CreatedAt: day(-12), ClosedAt: closed(-10)},
+		{ID: "done-c", Status: model.StatusClosed, Labels: []string{"api"}, CreatedAt: day(-19), ClosedAt: closed(-17)},
+		{ID: "done-d", Status: model.StatusClosed, Labels: []string{"api"}, CreatedAt: day(-27), ClosedAt: closed(-24)},
+		{ID: "done-e", Status: model.StatusClosed, Labels: []string{"api"}, CreatedAt: day(-36), ClosedAt: closed(-31)},
+		{ID: "done-f", Status: model.StatusClosed, Labels: []string{"api"}, CreatedAt: day(-46), ClosedAt: closed(-38)},
+	}
+	work := []model.Issue{
+		{ID: "t1", Status: model.StatusOpen, Labels: []string{"api"}},
+		{ID: "t2", Status: model.StatusOpen, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "t2", DependsOnID: "pre", Type: model.DepBlocks}}},
+		{ID: "t3", Status: model.StatusOpen, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "t3", DependsOnID: "t1", Type: model.DepParentChild}}},
+		{ID: "pre", Status: model.StatusOpen, Labels: []string{"api"}},
+	}
+
+	tests := []struct {
+		name        string
+		issues      []model.Issue
+		targets     []string
+		wantSource  string
+		wantSamples int
+		wantIssues  int
+		wantPrereqs int
+		minP50      float64
+		maxP95      float64
+	}{
+		{
+			name:        "blocked targets resample lead times",
+			issues:      append(append([]model.Issue{}, closures...), work...),
+			targets:     []string{"t1", "t2", "t3"},
+			wantSource:  SourceLeadTime,
+			wantSamples: 6,
+			wantIssues:  3,
+			wantPrereqs: 1,
+			minP50:      4,
+			maxP95:      100,
+		},
+		{
+			// Waits for nothing: at most the slowest closure at half pace
+			name:        "single unblocked issue",
+			issues:      append(append([]model.Issue{}, closures...), work...),
+			targets:     []string{"t1"},
+			wantSource:  SourceLeadTime,
+			wantSamples: 6,
+			wantIssues:  1,
+			maxP95:      16,
+		},
+		{
+			// 480 minutes at the default velocity is 5 days, jittered 0.6-1.8x
+			name:       "no closures falls back to estimates",
+			issues:     []model.Issue{{ID: "x", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est}},
+			targets:    []string{"x"},
+			wantSource: SourceEstimate,
+			wantIssues: 1,
+			minP50:     3,
+			maxP95:     9,
+		},
+		{
+			name:       "empty scope",
+			issues:     []model.Issue{{ID: "x", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est}},
+			targets:    []string{"missing"},
+			wantSource: SourceNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := MonteCarloOptions{Trials: 500, Seed: 7, Now: now}
+			fc := SimulateDelivery(tt.issues, tt.targets, opts)
+			if again := SimulateDelivery(tt.issues, tt.targets, opts); !reflect.DeepEqual(fc, again) {
+				t.Fatal("the same seed should give the same forecast")
+			}
+			if fc.Source != tt.wantSource || fc.Samples != tt.wantSamples {
+				t.Errorf("source %s, samples %d; want %s, %d", fc.Source, fc.Samples, tt.wantSource, tt.wantSamples)
+			}
+			if fc.Issues != tt.wantIssues || fc.Prerequisites != tt.wantPrereqs {
+				t.Errorf("issues %d, prerequisites %d; want %d, %d", fc.Issues, fc.Prerequisites, tt.wantIssues, tt.wantPrereqs)
+			}
+			if tt.wantIssues == 0 {
+				if !fc.P95.Equal(now) {
+					t.Errorf("empty scope p95 %v", fc.P95)
+				}
+				return
+			}
+			if !(fc.P50Days <= fc.P85Days && fc.P85Days <= fc.P95Days) || fc.P50Days < tt.minP50 || fc.P95Days > tt.maxP95 {
+				t.Errorf("percentiles %v %v %v, want p50 >= %v and p95 <= %v", fc.P50Days, fc.P85Days, fc.P95Days, tt.minP50, tt.maxP95)
+			}
+			if !fc.P85.Equal(now.Add(durationDays(fc.P85Days))) {
+				t.Errorf("p85 date %v", fc.P85)
+			}
+			total := 0
+			for _, bucket := range fc.Histogram {
+				total += bucket.Count
+			}
+			if total != 500 || fc.Histogram[len(fc.Histogram)-1].Cumulative != 1 {
+				t.Errorf("histogram covers %d trials", total)
+			}
+		})
+	}
+}
+
+func TestSimulateDeliveryMoreAgentsFinishSooner(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	est := 480
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est},
+		{ID: "b", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est},
+		{ID: "c", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est},
+		{ID: "d", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &est},
+	}
+	targets := []string{"a", "b", "c", "d"}
+
+	one := SimulateDelivery(issues, targets, MonteCarloOptions{Trials: 500, Seed: 7, Now: now})
+	four := SimulateDelivery(issues, targets, MonteCarloOptions{Trials: 500, Seed: 7, Now: now, Agents: 4})
+	if four.P50Days >= one.P50Days {
+		t.Errorf("4 agents p50 %v should beat 1 agent %v", four.P50Days, one.P50Days)
+	}
+}
+
+func TestEpicDescendants(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "t1", Status: model.StatusOpen,
+			Dependencies: []*model.Dependency{{IssueID: "t1", DependsOnID: "epic", Type: model.DepParentChild}}},
+		{ID: "t2", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "t2", DependsOnID: "pre", Type: model.DepBlocks},
+			{IssueID: "t2", DependsOnID: "epic", Type: model.DepParentChild},
+		}},
+		{ID: "t3", Status: model.StatusOpen,
+			Dependencies: []*model.Dependency{{IssueID: "t3", DependsOnID: "t1", Type: model.DepParentChild}}},
+		{ID: "pre", Status: model.StatusOpen},
+	}
+
+	tests := []struct {
+		root string
+		want []string
+	}{
+		{"epic", []string{"t1", "t2", "t3"}},
+		{"t1", []string{"t3"}},
+		{"t3", nil},
+	}
+	for _, tt := range tests {
+		if got := EpicDescendants(issues, tt.root); len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("EpicDescendants(%s) = %v, want %v", tt.root, got, tt.want)
+		}
+	}
+}
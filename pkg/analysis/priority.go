@@ -48,6 +48,9 @@ type ScoreBreakdown struct {
 
 	// Detailed risk signals (bv-82)
 	RiskSignals *RiskSignals `json:"risk_signals,omitempty"`
+
+	// Multiplier is the scoring profile's label/type multiplier, when not 1
+	Multiplier float64 `json:"multiplier,omitempty"`
 }
 
 // Weights for composite score (total = 1.0)
@@ -87,6 +90,12 @@ func (a *Analyzer) ComputeImpactScoresAt(now time.Time) []ImpactScore {
 
 // ComputeImpactScoresFromStats calculates impact scores using provided graph stats
 func (a *Analyzer) ComputeImpactScoresFromStats(stats *GraphStats, now time.Time) []ImpactScore {
+	return a.computeImpactScores(stats, now, a.ScoringProfile())
+}
+
+// computeImpactScores scores open issues with the given profile's weights,
+// urgency rules, multipliers and exclusions
+func (a *Analyzer) computeImpactScores(stats *GraphStats, now time.Time, profile *ScoringProfile) []ImpactScore {
 	// Handle empty issue set
 	if len(a.issueMap) == 0 {
 		return nil
@@ -127,8 +136,8 @@ func (a *Analyzer) ComputeImpactScoresFromStats(stats *GraphStats, now time.Time
 	var scores []ImpactScore
 
 	for id, issue := range a.issueMap {
-		// Skip closed/tombstone issues
-		if isClosedLikeStatus(issue.Status) {
+		// Skip closed/tombstone issues and those the profile excludes
+		if isClosedLikeStatus(issue.Status) || profile.Exclude.excludes(&issue) {
 			continue
 		}
 
@@ -152,21 +161,22 @@ func (a *Analyzer) ComputeImpactScoresFromStats(stats *GraphStats, now time.Time
 		)
 
 		// Compute urgency signal
-		urgencyNorm, urgencyExplanation := computeUrgency(&issue, now)
+		urgencyNorm, urgencyExplanation := computeUrgencyWithRules(&issue, now, profile.UrgencyRules)
 
 		// Compute risk signals (bv-82)
 		riskSignals := ComputeRiskSignals(&issue, stats, a.issueMap, now)
 
 		// Compute weighted score
+		w := profile.resolved
 		breakdown := ScoreBreakdown{
-			PageRank:      prNorm * WeightPageRank,
-			Betweenness:   bwNorm * WeightBetweenness,
-			BlockerRatio:  blockerNorm * WeightBlockerRatio,
-			Staleness:     stalenessNorm * WeightStaleness,
-			PriorityBoost: priorityNorm * WeightPriorityBoost,
-			TimeToImpact:  timeToImpactNorm * WeightTimeToImpact,
-			Urgency:       urgencyNorm * WeightUrgency,
-			Risk:          riskSignals.CompositeRisk * WeightRisk,
+			PageRank:      prNorm * w.PageRank,
+			Betweenness:   bwNorm * w.Betweenness,
+			BlockerRatio:  blockerNorm * w.BlockerRatio,
+			Staleness:     stalenessNorm * w.Staleness,
+			PriorityBoost: priorityNorm * w.PriorityBoost,
+			TimeToImpact:  timeToImpactNorm * w.TimeToImpact,
+			Urgency:       urgencyNorm * w.Urgency,
+			Risk:          riskSignals.CompositeRisk * w.Risk,
 
 			PageRankNorm:      prNorm,
 			BetweennessNorm:   bwNorm,
@@ -192,6 +202,10 @@ func (a *Analyzer) ComputeImpactScoresFromStats(stats *GraphStats, now time.Time
 			breakdown.TimeToImpact +
 			breakdown.Urgency +
 			breakdown.Risk
+		if m := profile.multiplier(&issue); m != 1 {
+			score *= m
+			breakdown.Multiplier = m
+		}
 
 		scores = append(scores, ImpactScore{
 			IssueID:   id,
@@ -371,37 +385,56 @@ func computeTimeToImpact(criticalPathDepth float64, estimatedMinutes *int, media
 // computeUrgency calculates a normalized urgency score based on labels and time decay.
 // Returns a 0-1 score where higher means more urgent.
 func computeUrgency(issue *model.Issue, now time.Time) (float64, string) {
+	return computeUrgencyWithRules(issue, now, nil)
+}
+
+// computeUrgencyWithRules is computeUrgency with a scoring profile's rules in
+// place of the UrgencyLabels table; nil rules use the table.
+func computeUrgencyWithRules(issue *model.Issue, now time.Time, rules []UrgencyRule) (float64, string) {
 	var score float64
 	var reasons []string
 
-	// Check for urgency labels
-	urgentLabelFound := ""
-	for _, label := range issue.Labels {
-		lowerLabel := strings.ToLower(label)
-		for _, urgentLabel := range UrgencyLabels {
-			if strings.Contains(lowerLabel, urgentLabel) {
-				urgentLabelFound = label
-				// Different labels have different urgency weights
-				switch urgentLabel {
-				case "critical", "blocker":
-					score += 1.0
-				case "urgent", "hotfix":
-					score += 0.8
-				case "asap":
-					score += 0.6
+	if rules != nil {
+		best := -1
+		for i, r := range rules {
+			if r.matches(issue, now) && (best < 0 || r.Score > rules[best].Score) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			score = rules[best].Score
+			reasons = append(reasons, fmt.Sprintf("matches urgency rule '%s'", rules[best].label()))
+		}
+	} else {
+		// Check for urgency labels
+		urgentLabelFound := ""
+		for _, label := range issue.Labels {
+			lowerLabel := strings.ToLower(label)
+			for _, urgentLabel := range UrgencyLabels {
+				if strings.Contains(lowerLabel, urgentLabel) {
+					urgentLabelFound = label
+					// Different labels have different urgency weights
+					switch urgentLabel {
+					case "critical", "blocker":
+						score += 1.0
+					case "urgent", "hotfix":
+						score += 0.8
+					case "asap":
+						score += 0.6
+					}
+					break
 				}
+			}
+			if urgentLabelFound != "" {
 				break
 			}
 		}
+
 		if urgentLabelFound != "" {
-			break
+			reasons = append(reasons, fmt.Sprintf("has '%s' label", urgentLabelFound))
 		}
 	}
 
-	if urgentLabelFound != "" {
-		reasons = append(reasons, fmt.Sprintf("has '%s' label", urgentLabelFound))
-	}
-
 	// Apply time decay: urgency increases as issue ages without resolution
 	// Uses exponential growth with half-life of UrgencyDecayDays
 	// Handle zero CreatedAt (unknown creation date)
@@ -0,0 +1,350 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DefaultLearnedProfileName names the profile TrainRanking produces
+const DefaultLearnedProfileName = "learned"
+
+// RankingSnapshot is the issue set as of one point in history, oldest first
+// when passed to TrainRanking
+type RankingSnapshot struct {
+	Revision string
+	At       time.Time
+	Issues   []model.Issue
+}
+
+// RankedClaim is a claimed issue and where each set of weights ranked it
+// among the open alternatives (1 = top). Ties count against the claim.
+type RankedClaim struct {
+	ID           string `json:"id"`
+	BaselineRank int    `json:"baseline_rank"`
+	TrainedRank  int    `json:"trained_rank"`
+}
+
+// RankingEvent is one observed choice: the open issues that were claimed
+// (moved to in_progress or straight to closed) between two snapshots, scored
+// against every issue that was open at the earlier one.
+type RankingEvent struct {
+	Revision   string        `json:"revision"` // Snapshot where the claims appear
+	At         time.Time     `json:"at"`
+	Candidates int           `json:"candidates"`
+	Claims     []RankedClaim `json:"claims"`
+	HeldOut    bool          `json:"held_out"` // Used for evaluation, not training
+
+	features [][]float64 // ScoreComponents norms per candidate
+	claimed  []int       // Indexes into features
+}
+
+// RankTrainingOptions tunes TrainRanking. Zero values use the defaults.
+type RankTrainingOptions struct {
+	Name         string  // Profile name (default "learned")
+	Holdout      float64 // Fraction of the latest events held out for evaluation (default 0.2)
+	Iterations   int     // Gradient descent steps (default 500)
+	LearningRate float64 // Step size (default 1)
+	L2           float64 // Weight decay (default 0.001)
+}
+
+// RankingMetrics measures how well a set of weights predicts the next claim
+type RankingMetrics struct {
+	Claims           int     `json:"claims"`
+	Top1             float64 `json:"top1"` // Claimed issue ranked first
+	Top3             float64 `json:"top3"`
+	MRR              float64 `json:"mrr"`               // Mean reciprocal rank
+	PairwiseAccuracy float64 `json:"pairwise_accuracy"` // Claimed issue above an alternative
+}
+
+// RankTrainingReport is the outcome of TrainRanking
+type RankTrainingReport struct {
+	Snapshots   int                `json:"snapshots"`
+	TrainEvents int                `json:"train_events"`
+	TestEvents  int                `json:"test_events"`
+	Pairs       int                `json:"pairs"`        // Claimed-vs-alternative training pairs
+	EvaluatedOn string             `json:"evaluated_on"` // "holdout", or "training" with too few events to hold any out
+	Baseline    RankingMetrics     `json:"baseline"`     // Default weights
+	Trained     RankingMetrics     `json:"trained"`
+	Weights     map[string]float64 `json:"weights"`
+	Profile     *ScoringProfile    `json:"profile"`
+	Events      []RankingEvent     `json:"events"`
+}
+
+// minHoldoutEvents is the fewest events worth splitting into train and test
+const minHoldoutEvents = 5
+
+// ExtractRankingEvents finds the claims between consecutive snapshots and
+// computes the impact score components of every open issue at the earlier
+// snapshot, as of the later one's time.
+func ExtractRankingEvents(snapshots []RankingSnapshot) []RankingEvent {
+	var events []RankingEvent
+	for i := 1; i < len(snapshots); i++ {
+		prev, next := snapshots[i-1], snapshots[i]
+		after := make(map[string]model.Status, len(next.Issues))
+		for _, issue := range next.Issues {
+			after[issue.ID] = issue.Status
+		}
+		claimed := make(map[string]bool)
+		for _, issue := range prev.Issues {
+			if issue.Status != model.StatusOpen {
+				continue
+			}
+			if st := after[issue.ID]; st == model.StatusInProgress || st == model.StatusClosed {
+				claimed[issue.ID] = true
+			}
+		}
+		if len(claimed) == 0 {
+			continue
+		}
+
+		open := make(map[string]bool, len(prev.Issues))
+		for _, issue := range prev.Issues {
+			open[issue.ID] = issue.Status == model.StatusOpen
+		}
+		analyzer := NewAnalyzer(prev.Issues)
+		stats := analyzer.Analyze()
+		event := RankingEvent{Revision: next.Revision, At: next.At}
+		var ids []string
+		for _, score := range analyzer.ComputeImpactScoresFromStats(&stats, next.At) {
+			if !open[score.IssueID] {
+				continue
+			}
+			if claimed[score.IssueID] {
+				event.claimed = append(event.claimed, len(event.features))
+				ids = append(ids, score.IssueID)
+			}
+			event.features = append(event.features, breakdownFeatures(score.Breakdown))
+		}
+		// A choice needs an alternative
+		if len(event.claimed) == 0 || len(event.features) < 2 {
+			continue
+		}
+		event.Candidates = len(event.features)
+		for _, id := range ids {
+			event.Claims = append(event.Claims, RankedClaim{ID: id})
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func breakdownFeatures(b ScoreBreakdown) []float64 {
+	return []float64{
+		b.PageRankNorm, b.BetweennessNorm, b.BlockerRatioNorm, b.StalenessNorm,
+		b.PriorityBoostNorm, b.TimeToImpactNorm, b.UrgencyNorm, b.RiskNorm,
+	}
+}
+
+func weightVector(w ScoreWeights) []float64 {
+	out := make([]float64, len(ScoreComponents))
+	for i, name := range ScoreComponents {
+		out[i] = *w.field(name)
+	}
+	return out
+}
+
+// TrainRanking fits component weights to the claims found in snapshots with
+// pairwise logistic regression: each claimed issue should outscore each open
+// alternative. Weights start from the defaults and stay non-negative so the
+// result is a valid scoring profile. The latest events are held out to
+// compare the learned weights with the defaults.
+func TrainRanking(snapshots []RankingSnapshot, opts RankTrainingOptions) (*RankTrainingReport, error) {
+	if opts.Name == "" {
+		opts.Name = DefaultLearnedProfileName
+	}
+	if opts.Holdout <= 0 || opts.Holdout >= 1 {
+		opts.Holdout = 0.2
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = 500
+	}
+	if opts.LearningRate <= 0 {
+		opts.LearningRate = 1
+	}
+	if opts.L2 <= 0 {
+		opts.L2 = 0.001
+	}
+
+	events := ExtractRankingEvents(snapshots)
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no claimed issues found in %d snapshots of history", len(snapshots))
+	}
+
+	report := &RankTrainingReport{Snapshots: len(snapshots), EvaluatedOn: "holdout"}
+	train, test := events, events
+	if len(events) >= minHoldoutEvents {
+		nTest := max(1, int(math.Round(opts.Holdout*float64(len(events)))))
+		train, test = events[:len(events)-nTest], events[len(events)-nTest:]
+		for i := range test {
+			test[i].HeldOut = true
+		}
+	} else {
+		report.EvaluatedOn = "training"
+	}
+	report.TrainEvents, report.TestEvents = len(train), len(test)
+
+	var pairs [][]float64
+	for _, ev := range train {
+		for _, c := range ev.claimed {
+			for j, f := range ev.features {
+				if !containsInt(ev.claimed, j) {
+					pairs = append(pairs, subtractVectors(ev.features[c], f))
+				}
+			}
+		}
+	}
+	report.Pairs = len(pairs)
+
+	baseline := weightVector(DefaultScoreWeights())
+	w := fitPairwiseLogistic(pairs, append([]float64(nil), baseline...), opts)
+	sum := 0.0
+	for _, v := range w {
+		sum += v
+	}
+	if sum == 0 {
+		return nil, fmt.Errorf("training drove every weight to zero; history has no usable signal")
+	}
+
+	profile := &ScoringProfile{
+		Name:        opts.Name,
+		Description: fmt.Sprintf("Learned from %d claims in history", countClaims(train)),
+		Source:      "trained",
+		Weights:     make(map[string]float64, len(w)),
+	}
+	for i, name := range ScoreComponents {
+		// Four decimals keep the exported YAML readable
+		profile.Weights[name] = math.Round(w[i]/sum*1e4) / 1e4
+	}
+	if err := profile.Compile(); err != nil {
+		return nil, fmt.Errorf("learned profile: %w", err)
+	}
+	trained := weightVector(profile.ComponentWeights())
+
+	for i := range events {
+		for k, c := range events[i].claimed {
+			events[i].Claims[k].BaselineRank = claimRank(events[i], c, baseline)
+			events[i].Claims[k].TrainedRank = claimRank(events[i], c, trained)
+		}
+	}
+	report.Baseline = evaluateRanking(test, baseline)
+	report.Trained = evaluateRanking(test, trained)
+	report.Weights = profile.Weights
+	report.Profile = profile
+	report.Events = events
+	return report, nil
+}
+
+// fitPairwiseLogistic minimizes the mean logistic loss of w·d over the
+// pair differences with full-batch gradient descent, projecting onto w >= 0.
+func fitPairwiseLogistic(pairs [][]float64, w []float64, opts RankTrainingOptions) []float64 {
+	if len(pairs) == 0 {
+		return w
+	}
+	grad := make([]float64, len(w))
+	for it := 0; it < opts.Iterations; it++ {
+		for j := range grad {
+			grad[j] = 0
+		}
+		for _, d := range pairs {
+			// d/dz log(1+e^-z) = -1/(1+e^z)
+			g := -1 / (1 + math.Exp(dotVectors(w, d)))
+			for j, v := range d {
+				grad[j] += g * v
+			}
+		}
+		for j := range w {
+			step := grad[j]/float64(len(pairs)) + opts.L2*w[j]
+			w[j] = math.Max(0, w[j]-opts.LearningRate*step)
+		}
+	}
+	return w
+}
+
+func claimRank(ev RankingEvent, claimed int, w []float64) int {
+	score := dotVectors(w, ev.features[claimed])
+	rank := 1
+	for j, f := range ev.features {
+		if j != claimed && !containsInt(ev.claimed, j) && dotVectors(w, f) >= score {
+			rank++
+		}
+	}
+	return rank
+}
+
+func evaluateRanking(events []RankingEvent, w []float64) RankingMetrics {
+	var m RankingMetrics
+	var pairs, correct float64
+	for _, ev := range events {
+		for _, c := range ev.claimed {
+			rank := claimRank(ev, c, w)
+			m.Claims++
+			if rank == 1 {
+				m.Top1++
+			}
+			if rank <= 3 {
+				m.Top3++
+			}
+			m.MRR += 1 / float64(rank)
+
+			score := dotVectors(w, ev.features[c])
+			for j, f := range ev.features {
+				if containsInt(ev.claimed, j) {
+					continue
+				}
+				pairs++
+				switch other := dotVectors(w, f); {
+				case score > other:
+					correct++
+				case score == other:
+					correct += 0.5
+				}
+			}
+		}
+	}
+	if m.Claims > 0 {
+		n := float64(m.Claims)
+		m.Top1 /= n
+		m.Top3 /= n
+		m.MRR /= n
+	}
+	if pairs > 0 {
+		m.PairwiseAccuracy = correct / pairs
+	}
+	return m
+}
+
+func countClaims(events []RankingEvent) int {
+	n := 0
+	for _, ev := range events {
+		n += len(ev.claimed)
+	}
+	return n
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func dotVectors(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func subtractVectors(a, b []float64) []float64 {
+	out := make([]float64, len(a))
+	for i := range a {
+		out[i] = a[i] - b[i]
+	}
+	return out
+}
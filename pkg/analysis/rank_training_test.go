@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// rankingHistory replays a team that always claims the most urgent open
+// task, ignoring a low-priority hub the default weights favor.
+func rankingHistory() []RankingSnapshot {
+	start := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	issues := []model.Issue{{ID: "core", Title: "Core", Status: model.StatusOpen, Priority: 4, IssueType: model.TypeTask}}
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("dep-%d", i)
+		issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen, Priority: 4, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: id, DependsOnID: "core", Type: model.DepBlocks}}})
+	}
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("task-%d", i)
+		issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen, Priority: i / 2, IssueType: model.TypeTask})
+	}
+	for i := range issues {
+		issues[i].CreatedAt = start.Add(-30 * 24 * time.Hour)
+		issues[i].UpdatedAt = start
+	}
+	// Within a priority, the team picks the older task
+	for i := 0; i < 8; i++ {
+		issues[5+i].UpdatedAt = start.Add(-time.Duration(16-2*i) * 24 * time.Hour)
+	}
+
+	var snapshots []RankingSnapshot
+	for step := 0; step <= 8; step++ {
+		at := start.Add(time.Duration(step) * 24 * time.Hour)
+		snap := make([]model.Issue, len(issues))
+		copy(snap, issues)
+		for i := range snap {
+			// Tasks are claimed in priority order, one per day
+			var n int
+			if _, err := fmt.Sscanf(snap[i].ID, "task-%d", &n); err == nil && n < step {
+				snap[i].Status = model.StatusInProgress
+			}
+		}
+		snapshots = append(snapshots, RankingSnapshot{Revision: fmt.Sprintf("r%d", step), At: at, Issues: snap})
+	}
+	return snapshots
+}
+
+func TestTrainRankingLearnsFromClaims(t *testing.T) {
+	snapshots := rankingHistory()
+	events := ExtractRankingEvents(snapshots)
+	if len(events) != 8 || events[0].Claims[0].ID != "task-0" || events[0].Candidates != 13 {
+		t.Fatalf("events = %+v", events)
+	}
+
+	report, err := TrainRanking(snapshots, RankTrainingOptions{})
+	if err != nil {
+		t.Fatalf("TrainRanking: %v", err)
+	}
+	if report.EvaluatedOn != "holdout" || report.TrainEvents != 6 || report.TestEvents != 2 || !report.Events[7].HeldOut {
+		t.Errorf("split: %+v", report)
+	}
+	if report.Baseline.Top1 == 1 {
+		t.Fatalf("fixture should fool the default weights: %+v", report.Baseline)
+	}
+	if report.Trained.Top1 != 1 || report.Trained.MRR <= report.Baseline.MRR {
+		t.Errorf("trained %+v, baseline %+v", report.Trained, report.Baseline)
+	}
+	if report.Weights["priority_boost"] <= WeightPriorityBoost || report.Weights["pagerank"] >= WeightPageRank {
+		t.Errorf("weights = %v", report.Weights)
+	}
+	if report.Profile.Name != DefaultLearnedProfileName || report.Profile.ComponentWeights().PriorityBoost == 0 {
+		t.Errorf("profile = %+v", report.Profile)
+	}
+	if c := report.Events[0].Claims[0]; c.TrainedRank != 1 || c.BaselineRank <= 1 {
+		t.Errorf("first claim = %+v", c)
+	}
+
+	// Too few events to hold any out
+	report, err = TrainRanking(snapshots[:3], RankTrainingOptions{Name: "mine"})
+	if err != nil || report.EvaluatedOn != "training" || report.Profile.Name != "mine" {
+		t.Errorf("small history: %+v, %v", report, err)
+	}
+
+	if _, err := TrainRanking(snapshots[:1], RankTrainingOptions{}); err == nil || !strings.Contains(err.Error(), "no claimed issues") {
+		t.Errorf("no history: %v", err)
+	}
+}
+
+func TestSaveScoringProfileKeepsOtherProfiles(t *testing.T) {
+	path := ScoringProfilesPath(t.TempDir())
+	learned := &ScoringProfile{Name: "learned", Description: "v1", Weights: map[string]float64{"priority_boost": 0.5, "pagerank": 0.5}}
+	if err := SaveScoringProfile(path, learned); err != nil {
+		t.Fatalf("SaveScoringProfile (new file): %v", err)
+	}
+
+	body := "# team profiles\ndefault: bugs\nprofiles:\n  bugs:\n    type_multipliers: {bug: 2} # keep me\n  learned:\n    description: old\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	learned.Description = "v2"
+	if err := SaveScoringProfile(path, learned); err != nil {
+		t.Fatalf("SaveScoringProfile: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# team profiles") || !strings.Contains(string(data), "# keep me") {
+		t.Errorf("comments lost:\n%s", data)
+	}
+
+	profiles, err := LoadScoringProfiles(path)
+	if err != nil {
+		t.Fatalf("LoadScoringProfiles: %v\n%s", err, data)
+	}
+	got, _ := profiles.Get("learned")
+	if got.Description != "v2" || got.Weights["priority_boost"] != 0.5 {
+		t.Errorf("learned = %+v", got)
+	}
+	if _, err := profiles.Get("bugs"); err != nil || profiles.Default != "bugs" {
+		t.Errorf("other profiles lost: %v", err)
+	}
+	if leftovers, _ := filepath.Glob(path + ".tmp-*"); len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+
+	if err := os.WriteFile(path, []byte("- a list\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveScoringProfile(path, learned); err == nil {
+		t.Error("a non-mapping file should be an error")
+	}
+}
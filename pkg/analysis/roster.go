@@ -0,0 +1,169 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TeamRosterFilename is the roster file read from a project's .bv directory
+const TeamRosterFilename = "team.yaml"
+
+// DefaultHoursPerDay is the focused capacity assumed when neither the roster
+// nor the member sets one
+const DefaultHoursPerDay = 6.0
+
+// TeamRoster describes who can work on the project and when. Team-wide
+// settings apply to every member that doesn't override them.
+//
+//	hours_per_day: 6
+//	working_days: [mon, tue, wed, thu, fri]
+//	holidays: ["2025-12-25"]
+//	members:
+//	  - name: alice
+//	    skills: [backend, api]
+//	  - name: bob
+//	    capacity: 4
+//	    working_days: [mon, wed, fri]
+type TeamRoster struct {
+	// HoursPerDay is the default focused capacity per working day
+	HoursPerDay float64 `yaml:"hours_per_day,omitempty" json:"hours_per_day,omitempty"`
+	// WorkingDays are weekday names (mon..sun); defaults to mon-fri
+	WorkingDays []string `yaml:"working_days,omitempty" json:"working_days,omitempty"`
+	// Holidays are team-wide days off as YYYY-MM-DD
+	Holidays []string     `yaml:"holidays,omitempty" json:"holidays,omitempty"`
+	Members  []TeamMember `yaml:"members" json:"members"`
+}
+
+// TeamMember is one person (or agent) on the roster
+type TeamMember struct {
+	Name string `yaml:"name" json:"name"`
+	// Capacity is focused hours per working day; 0 uses the roster default
+	Capacity float64 `yaml:"capacity,omitempty" json:"capacity,omitempty"`
+	// Skills are the labels this member takes; empty means any issue
+	Skills []string `yaml:"skills,omitempty" json:"skills,omitempty"`
+	// WorkingDays replaces the roster's working days when set
+	WorkingDays []string `yaml:"working_days,omitempty" json:"working_days,omitempty"`
+	// Holidays are personal days off, on top of the team's
+	Holidays []string `yaml:"holidays,omitempty" json:"holidays,omitempty"`
+}
+
+// DefaultTeamRoster returns n interchangeable generalists, used when a
+// project has no roster file
+func DefaultTeamRoster(n int) *TeamRoster {
+	if n <= 0 {
+		n = 1
+	}
+	roster := &TeamRoster{HoursPerDay: DefaultHoursPerDay}
+	for i := 1; i <= n; i++ {
+		roster.Members = append(roster.Members, TeamMember{Name: fmt.Sprintf("agent-%d", i)})
+	}
+	return roster
+}
+
+// TeamRosterPath returns the default roster path for a project
+func TeamRosterPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", TeamRosterFilename)
+}
+
+// LoadTeamRoster reads a roster file. A missing file returns (nil, nil) so
+// callers can fall back to DefaultTeamRoster.
+func LoadTeamRoster(path string) (*TeamRoster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading team roster: %w", err)
+	}
+
+	var roster TeamRoster
+	if err := yaml.Unmarshal(data, &roster); err != nil {
+		return nil, fmt.Errorf("parsing team roster: %w", err)
+	}
+	if err := roster.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid team roster: %w", err)
+	}
+	return &roster, nil
+}
+
+// Validate checks member names, capacities, weekday names and dates
+func (r *TeamRoster) Validate() error {
+	if len(r.Members) == 0 {
+		return fmt.Errorf("no members")
+	}
+	if r.HoursPerDay < 0 || r.HoursPerDay > 24 {
+		return fmt.Errorf("hours_per_day must be between 0 and 24, got %v", r.HoursPerDay)
+	}
+	if _, err := parseWeekdays(r.WorkingDays); err != nil {
+		return err
+	}
+	if _, err := parseHolidays(r.Holidays); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(r.Members))
+	for i, m := range r.Members {
+		name := strings.TrimSpace(m.Name)
+		if name == "" {
+			return fmt.Errorf("member %d has no name", i+1)
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			return fmt.Errorf("duplicate member %q", name)
+		}
+		seen[key] = true
+		if m.Capacity < 0 || m.Capacity > 24 {
+			return fmt.Errorf("member %q: capacity must be between 0 and 24, got %v", name, m.Capacity)
+		}
+		if _, err := parseWeekdays(m.WorkingDays); err != nil {
+			return fmt.Errorf("member %q: %w", name, err)
+		}
+		if _, err := parseHolidays(m.Holidays); err != nil {
+			return fmt.Errorf("member %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWeekdays accepts three-letter or full weekday names. An empty list
+// yields nil so the caller can apply its default.
+func parseWeekdays(names []string) (*[7]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var days [7]bool
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if len(key) > 3 {
+			key = key[:3]
+		}
+		wd, ok := weekdayNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown working day %q", name)
+		}
+		days[wd] = true
+	}
+	return &days, nil
+}
+
+func parseHolidays(dates []string) (map[string]bool, error) {
+	days := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		t, err := time.Parse("2006-01-02", strings.TrimSpace(d))
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q (want YYYY-MM-DD)", d)
+		}
+		days[t.Format("2006-01-02")] = true
+	}
+	return days, nil
+}
@@ -0,0 +1,465 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ScenarioDep names a blocking dependency: Issue waits on DependsOn
+type ScenarioDep struct {
+	Issue     string `yaml:"issue" json:"issue"`
+	DependsOn string `yaml:"depends_on" json:"depends_on"`
+}
+
+// ScenarioEdit is one hypothetical change. Exactly one field is set.
+type ScenarioEdit struct {
+	Close     string       `yaml:"close,omitempty" json:"close,omitempty"`
+	Defer     string       `yaml:"defer,omitempty" json:"defer,omitempty"` // The issue and its parent-child descendants
+	AddDep    *ScenarioDep `yaml:"add_dep,omitempty" json:"add_dep,omitempty"`
+	RemoveDep *ScenarioDep `yaml:"remove_dep,omitempty" json:"remove_dep,omitempty"`
+	AddAgents int          `yaml:"add_agents,omitempty" json:"add_agents,omitempty"`
+}
+
+// Scenario is a named batch of edits evaluated together
+type Scenario struct {
+	Name        string         `yaml:"name" json:"name"`
+	Description string         `yaml:"description,omitempty" json:"description,omitempty"`
+	Edits       []ScenarioEdit `yaml:"edits" json:"edits"`
+}
+
+// ScenarioFile is the YAML form of a set of scenarios:
+//
+//	agents: 2
+//	scenarios:
+//	  - name: drop-search
+//	    edits:
+//	      - defer: bv-40
+//	      - add_agents: 1
+//	  - name: api-first
+//	    edits:
+//	      - close: bv-12
+//	      - add_dep: {issue: bv-30, depends_on: bv-21}
+//	      - remove_dep: {issue: bv-22, depends_on: bv-12}
+type ScenarioFile struct {
+	Agents    int        `yaml:"agents,omitempty" json:"agents,omitempty"` // Baseline agents for forecasts
+	Scenarios []Scenario `yaml:"scenarios" json:"scenarios"`
+}
+
+// LoadScenarioFile reads and validates a scenario file
+func LoadScenarioFile(path string) (*ScenarioFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading scenarios: %w", err)
+	}
+	var file ScenarioFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing scenarios: %w", err)
+	}
+	if len(file.Scenarios) == 0 {
+		return nil, fmt.Errorf("invalid scenarios: none defined")
+	}
+	seen := make(map[string]bool, len(file.Scenarios))
+	for i := range file.Scenarios {
+		sc := &file.Scenarios[i]
+		if sc.Name == "" {
+			sc.Name = fmt.Sprintf("scenario-%d", i+1)
+		}
+		if seen[sc.Name] {
+			return nil, fmt.Errorf("invalid scenarios: duplicate name %q", sc.Name)
+		}
+		seen[sc.Name] = true
+		for j, e := range sc.Edits {
+			if err := e.validate(); err != nil {
+				return nil, fmt.Errorf("invalid scenarios: %s edit %d: %w", sc.Name, j+1, err)
+			}
+		}
+	}
+	return &file, nil
+}
+
+// ParseScenarioEdits parses the inline form used on the command line:
+// comma-separated close:ID, defer:ID, add-dep:A>B, remove-dep:A>B and
+// add-agents:N, where A>B means A waits on B.
+func ParseScenarioEdits(spec string) ([]ScenarioEdit, error) {
+	var edits []ScenarioEdit
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		op, arg, ok := strings.Cut(part, ":")
+		if !ok || strings.TrimSpace(arg) == "" {
+			return nil, fmt.Errorf("edit %q: want op:argument", part)
+		}
+		arg = strings.TrimSpace(arg)
+		var e ScenarioEdit
+		switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(op)), "_", "-") {
+		case "close":
+			e.Close = arg
+		case "defer":
+			e.Defer = arg
+		case "add-dep", "remove-dep":
+			from, to, ok := strings.Cut(arg, ">")
+			if !ok {
+				return nil, fmt.Errorf("edit %q: want ISSUE>DEPENDS_ON", part)
+			}
+			dep := &ScenarioDep{Issue: strings.TrimSpace(from), DependsOn: strings.TrimSpace(to)}
+			if strings.HasPrefix(strings.ToLower(op), "add") {
+				e.AddDep = dep
+			} else {
+				e.RemoveDep = dep
+			}
+		case "add-agents":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, fmt.Errorf("edit %q: invalid agent count", part)
+			}
+			e.AddAgents = n
+		default:
+			return nil, fmt.Errorf("edit %q: unknown op %q (want close, defer, add-dep, remove-dep or add-agents)", part, op)
+		}
+		if err := e.validate(); err != nil {
+			return nil, fmt.Errorf("edit %q: %w", part, err)
+		}
+		edits = append(edits, e)
+	}
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("no edits in %q", spec)
+	}
+	return edits, nil
+}
+
+func (e ScenarioEdit) validate() error {
+	set := 0
+	for _, ok := range []bool{e.Close != "", e.Defer != "", e.AddDep != nil, e.RemoveDep != nil, e.AddAgents != 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("set exactly one of close, defer, add_dep, remove_dep or add_agents")
+	}
+	for _, dep := range []*ScenarioDep{e.AddDep, e.RemoveDep} {
+		if dep != nil && (dep.Issue == "" || dep.DependsOn == "") {
+			return fmt.Errorf("dependency needs both issue and depends_on")
+		}
+		if dep != nil && dep.Issue == dep.DependsOn {
+			return fmt.Errorf("%s cannot depend on itself", dep.Issue)
+		}
+	}
+	return nil
+}
+
+// String renders the edit in the inline syntax
+func (e ScenarioEdit) String() string {
+	switch {
+	case e.Close != "":
+		return "close:" + e.Close
+	case e.Defer != "":
+		return "defer:" + e.Defer
+	case e.AddDep != nil:
+		return "add-dep:" + e.AddDep.Issue + ">" + e.AddDep.DependsOn
+	case e.RemoveDep != nil:
+		return "remove-dep:" + e.RemoveDep.Issue + ">" + e.RemoveDep.DependsOn
+	}
+	return fmt.Sprintf("add-agents:%d", e.AddAgents)
+}
+
+// ApplyScenario returns a copy of issues with the edits applied, plus the
+// agent count after add_agents edits. The input is never modified.
+func ApplyScenario(issues []model.Issue, edits []ScenarioEdit, agents int, now time.Time) ([]model.Issue, int, error) {
+	out := make([]model.Issue, len(issues))
+	index := make(map[string]int, len(issues))
+	for i, iss := range issues {
+		out[i] = iss.Clone()
+		index[iss.ID] = i
+	}
+	lookup := func(id string) (*model.Issue, error) {
+		i, ok := index[id]
+		if !ok {
+			return nil, fmt.Errorf("issue %q not found", id)
+		}
+		return &out[i], nil
+	}
+
+	for _, e := range edits {
+		if err := e.validate(); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", e, err)
+		}
+		switch {
+		case e.Close != "":
+			iss, err := lookup(e.Close)
+			if err != nil {
+				return nil, 0, err
+			}
+			if isClosedLikeStatus(iss.Status) {
+				return nil, 0, fmt.Errorf("%s is already closed", iss.ID)
+			}
+			closed := now
+			iss.Status, iss.ClosedAt, iss.UpdatedAt = model.StatusClosed, &closed, now
+		case e.Defer != "":
+			if _, err := lookup(e.Defer); err != nil {
+				return nil, 0, err
+			}
+			for _, id := range append([]string{e.Defer}, EpicDescendants(out, e.Defer)...) {
+				iss := &out[index[id]]
+				if !isClosedLikeStatus(iss.Status) {
+					iss.Status, iss.UpdatedAt = model.StatusDeferred, now
+				}
+			}
+		case e.AddDep != nil:
+			iss, err := lookup(e.AddDep.Issue)
+			if err != nil {
+				return nil, 0, err
+			}
+			if _, err := lookup(e.AddDep.DependsOn); err != nil {
+				return nil, 0, err
+			}
+			for _, dep := range iss.Dependencies {
+				if dep != nil && dep.DependsOnID == e.AddDep.DependsOn && dep.Type.IsBlocking() {
+					return nil, 0, fmt.Errorf("%s already depends on %s", iss.ID, e.AddDep.DependsOn)
+				}
+			}
+			iss.Dependencies = append(iss.Dependencies, &model.Dependency{
+				IssueID: iss.ID, DependsOnID: e.AddDep.DependsOn, Type: model.DepBlocks, CreatedAt: now,
+			})
+		case e.RemoveDep != nil:
+			iss, err := lookup(e.RemoveDep.Issue)
+			if err != nil {
+				return nil, 0, err
+			}
+			var kept []*model.Dependency
+			for _, dep := range iss.Dependencies {
+				if dep != nil && dep.DependsOnID == e.RemoveDep.DependsOn && dep.Type.IsBlocking() {
+					continue
+				}
+				kept = append(kept, dep)
+			}
+			if len(kept) == len(iss.Dependencies) {
+				return nil, 0, fmt.Errorf("%s does not depend on %s", iss.ID, e.RemoveDep.DependsOn)
+			}
+			iss.Dependencies = kept
+		default:
+			agents += e.AddAgents
+			if agents < 1 {
+				return nil, 0, fmt.Errorf("%s leaves fewer than one agent", e)
+			}
+		}
+	}
+	return out, agents, nil
+}
+
+// WhatIfOptions configures RunScenarios.
+type WhatIfOptions struct {
+	Agents  int                       // Baseline agents (default 1)
+	Trials  int                       // Monte Carlo trials per forecast (0 = no forecasts)
+	Seed    int64                     // Shared by every forecast so differences come from the edits
+	Now     time.Time                 // Default time.Now()
+	History map[string][]StatusChange // Optional, for cycle-time sampling
+	Profile *ScoringProfile           // Optional scoring profile for the picks
+}
+
+// whatIfTopN is how many triage recommendations are compared for rank changes
+const whatIfTopN = 10
+
+// ScenarioOutcome summarizes the plan for one version of the issues.
+// Deferred issues are left out of the counts, picks and forecast targets.
+type ScenarioOutcome struct {
+	Open          int                 `json:"open"`
+	Deferred      int                 `json:"deferred"`
+	Actionable    int                 `json:"actionable"`
+	Blocked       int                 `json:"blocked"`
+	Cycles        int                 `json:"cycles"`
+	Tracks        int                 `json:"tracks"` // Parallel tracks in the execution plan
+	HighestImpact string              `json:"highest_impact,omitempty"`
+	TopPicks      []string            `json:"top_picks"` // Triage recommendations, best first
+	Agents        int                 `json:"agents"`
+	Forecast      *MonteCarloForecast `json:"forecast,omitempty"` // Every open issue, without the histogram
+
+	actionable map[string]bool
+	ranks      map[string]int
+	snapshot   *Snapshot
+}
+
+// ScenarioRankChange is an issue moving in or out of the top triage picks
+type ScenarioRankChange struct {
+	ID     string `json:"id"`
+	Before int    `json:"before"` // 1-based; 0 when outside the top picks
+	After  int    `json:"after"`
+}
+
+// ScenarioForecastDelta is scenario minus baseline, in days
+type ScenarioForecastDelta struct {
+	P50Days float64 `json:"p50_days"`
+	P85Days float64 `json:"p85_days"`
+	P95Days float64 `json:"p95_days"`
+}
+
+// ScenarioResult is one scenario compared against the baseline
+type ScenarioResult struct {
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description,omitempty"`
+	Edits         []string               `json:"edits"`
+	Outcome       ScenarioOutcome        `json:"outcome"`
+	Unblocked     []string               `json:"unblocked"`     // Actionable only in the scenario
+	NewlyBlocked  []string               `json:"newly_blocked"` // Actionable only in the baseline
+	RankChanges   []ScenarioRankChange   `json:"rank_changes"`
+	ForecastDelta *ScenarioForecastDelta `json:"forecast_delta,omitempty"`
+	Diff          *SnapshotDiff          `json:"diff"`
+}
+
+// WhatIfReport compares each scenario against the unmodified issues
+type WhatIfReport struct {
+	Baseline  ScenarioOutcome  `json:"baseline"`
+	Scenarios []ScenarioResult `json:"scenarios"`
+}
+
+// RunScenarios evaluates each scenario on its own copy of the issues.
+// Forecasts share a seed, so the same random draws apply before and after
+// and their difference reflects the edits rather than sampling noise.
+func RunScenarios(issues []model.Issue, scenarios []Scenario, opts WhatIfOptions) (*WhatIfReport, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Agents <= 0 {
+		opts.Agents = 1
+	}
+
+	report := &WhatIfReport{Baseline: evaluateScenario(issues, issues, opts.Agents, "baseline", opts)}
+	for _, sc := range scenarios {
+		edited, agents, err := ApplyScenario(issues, sc.Edits, opts.Agents, opts.Now)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %q: %w", sc.Name, err)
+		}
+		outcome := evaluateScenario(edited, issues, agents, "scenario:"+sc.Name, opts)
+		report.Scenarios = append(report.Scenarios, compareOutcomes(sc, report.Baseline, outcome))
+	}
+	return report, nil
+}
+
+// evaluateScenario computes the outcome for issues; past is the unedited
+// set whose history feeds the forecast.
+func evaluateScenario(issues, past []model.Issue, agents int, revision string, opts WhatIfOptions) ScenarioOutcome {
+	analyzer := NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+	triage := ComputeTriageFromAnalyzer(analyzer, &stats, issues, TriageOptions{TopN: len(issues), Profile: opts.Profile}, opts.Now)
+	plan := analyzer.GetExecutionPlan()
+
+	snap := &Snapshot{Timestamp: opts.Now, Revision: revision, Issues: issues, Stats: &stats}
+	snap.computeCounts()
+
+	out := ScenarioOutcome{
+		Agents:        agents,
+		Cycles:        len(stats.Cycles()),
+		Tracks:        len(plan.Tracks),
+		HighestImpact: plan.Summary.HighestImpact,
+		TopPicks:      []string{},
+		actionable:    make(map[string]bool),
+		ranks:         make(map[string]int),
+		snapshot:      snap,
+	}
+	deferred := make(map[string]bool)
+	var open []string
+	for _, iss := range issues {
+		switch {
+		case isClosedLikeStatus(iss.Status):
+		case iss.Status == model.StatusDeferred:
+			deferred[iss.ID] = true
+			out.Deferred++
+		default:
+			open = append(open, iss.ID)
+		}
+	}
+	out.Open = len(open)
+	for _, iss := range analyzer.GetActionableIssues() {
+		if !deferred[iss.ID] {
+			out.actionable[iss.ID] = true
+		}
+	}
+	out.Actionable = len(out.actionable)
+	out.Blocked = out.Open - out.Actionable
+
+	for _, rec := range triage.Recommendations {
+		if deferred[rec.ID] {
+			continue
+		}
+		out.ranks[rec.ID] = len(out.ranks) + 1
+		if len(out.TopPicks) < whatIfTopN {
+			out.TopPicks = append(out.TopPicks, rec.ID)
+		}
+	}
+
+	if opts.Trials > 0 {
+		fc := SimulateDelivery(issues, open, MonteCarloOptions{
+			Trials:  opts.Trials,
+			Agents:  agents,
+			Seed:    opts.Seed,
+			Now:     opts.Now,
+			History: opts.History,
+			Past:    past,
+		})
+		fc.Scope, fc.Histogram = "all", nil
+		out.Forecast = &fc
+	}
+	return out
+}
+
+func compareOutcomes(sc Scenario, before, after ScenarioOutcome) ScenarioResult {
+	res := ScenarioResult{
+		Name:         sc.Name,
+		Description:  sc.Description,
+		Outcome:      after,
+		Unblocked:    []string{},
+		NewlyBlocked: []string{},
+		RankChanges:  []ScenarioRankChange{},
+		Diff:         CompareSnapshots(before.snapshot, after.snapshot),
+	}
+	for _, e := range sc.Edits {
+		res.Edits = append(res.Edits, e.String())
+	}
+	for id := range after.actionable {
+		if !before.actionable[id] {
+			res.Unblocked = append(res.Unblocked, id)
+		}
+	}
+	for id := range before.actionable {
+		if !after.actionable[id] {
+			res.NewlyBlocked = append(res.NewlyBlocked, id)
+		}
+	}
+	sort.Strings(res.Unblocked)
+	sort.Strings(res.NewlyBlocked)
+
+	topRank := func(ranks map[string]int, id string) int {
+		if r := ranks[id]; r <= whatIfTopN {
+			return r
+		}
+		return 0
+	}
+	seen := make(map[string]bool)
+	for _, id := range append(append([]string{}, before.TopPicks...), after.TopPicks...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if b, a := topRank(before.ranks, id), topRank(after.ranks, id); a != b {
+			res.RankChanges = append(res.RankChanges, ScenarioRankChange{ID: id, Before: b, After: a})
+		}
+	}
+
+	if before.Forecast != nil && after.Forecast != nil {
+		res.ForecastDelta = &ScenarioForecastDelta{
+			P50Days: roundDays(after.Forecast.P50Days - before.Forecast.P50Days),
+			P85Days: roundDays(after.Forecast.P85Days - before.Forecast.P85Days),
+			P95Days: roundDays(after.Forecast.P95Days - before.Forecast.P95Days),
+		}
+	}
+	return res
+}
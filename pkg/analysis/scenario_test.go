@@ -0,0 +1,272 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestRunScenariosComparesAgainstBaseline(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	est := 240
+
+	tests := []struct {
+		name             string
+		issues           []model.Issue
+		edit             ScenarioEdit
+		wantEdit         string
+		wantOpen         int
+		wantDeferred     int
+		wantAgents       int
+		wantUnblocked    string
+		wantNewlyBlocked string
+		wantClosed       string
+		wantModified     string
+		wantRankChanges  bool
+		wantFaster       bool // P50 forecast moves in
+	}{
+		{
+			name: "close unblocks its dependent",
+			issues: []model.Issue{
+				{ID: "a", Title: "Schema", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+				{ID: "b", Title: "API", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+			},
+			edit:             ScenarioEdit{Close: "a"},
+			wantEdit:         "close:a",
+			wantOpen:         1,
+			wantAgents:       1,
+			wantUnblocked:    "b",
+			wantNewlyBlocked: "a",
+			wantClosed:       "a",
+			wantRankChanges:  true,
+			wantFaster:       true,
+		},
+		{
+			name: "add dependency blocks the issue",
+			issues: []model.Issue{
+				{ID: "c", Title: "UI", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+				{ID: "d", Title: "Docs", Status: model.StatusOpen, Priority: 3, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+			},
+			edit:             ScenarioEdit{AddDep: &ScenarioDep{Issue: "d", DependsOn: "c"}},
+			wantEdit:         "add-dep:d>c",
+			wantOpen:         2,
+			wantAgents:       1,
+			wantNewlyBlocked: "d",
+			wantModified:     "d",
+		},
+		{
+			name: "remove dependency unblocks the issue",
+			issues: []model.Issue{
+				{ID: "a", Title: "Schema", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+				{ID: "b", Title: "API", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+			},
+			edit:          ScenarioEdit{RemoveDep: &ScenarioDep{Issue: "b", DependsOn: "a"}},
+			wantEdit:      "remove-dep:b>a",
+			wantOpen:      2,
+			wantAgents:    1,
+			wantUnblocked: "b",
+			wantModified:  "b",
+		},
+		{
+			name: "defer an epic with its children",
+			issues: []model.Issue{
+				{ID: "d", Title: "Docs", Status: model.StatusOpen, Priority: 3, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+				{ID: "e", Title: "Search", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeEpic, CreatedAt: created},
+				{ID: "f", Title: "Index", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "f", DependsOnID: "e", Type: model.DepParentChild}}},
+				{ID: "g", Title: "Query", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "g", DependsOnID: "e", Type: model.DepParentChild}}},
+			},
+			edit:             ScenarioEdit{Defer: "e"},
+			wantEdit:         "defer:e",
+			wantOpen:         1,
+			wantDeferred:     3,
+			wantAgents:       1,
+			wantNewlyBlocked: "e,f,g",
+			wantModified:     "e,f,g",
+			wantRankChanges:  true,
+			wantFaster:       true,
+		},
+		{
+			name: "add agents",
+			issues: []model.Issue{
+				{ID: "a", Title: "Schema", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+				{ID: "c", Title: "UI", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+				{ID: "d", Title: "Docs", Status: model.StatusOpen, Priority: 3, IssueType: model.TypeTask, EstimatedMinutes: &est, CreatedAt: created},
+			},
+			edit:       ScenarioEdit{AddAgents: 2},
+			wantEdit:   "add-agents:2",
+			wantOpen:   3,
+			wantAgents: 3,
+			wantFaster: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make([]model.Issue, len(tt.issues))
+			for i := range tt.issues {
+				before[i] = tt.issues[i].Clone()
+			}
+			report, err := RunScenarios(tt.issues, []Scenario{{Name: "s", Edits: []ScenarioEdit{tt.edit}}}, WhatIfOptions{Trials: 200, Seed: 3, Now: now})
+			if err != nil {
+				t.Fatalf("RunScenarios: %v", err)
+			}
+			if base := report.Baseline; base.Open != len(tt.issues) || base.Agents != 1 || base.Forecast == nil {
+				t.Fatalf("baseline = %+v", base)
+			}
+			r := report.Scenarios[0]
+			var closed, modified []string
+			for _, issue := range r.Diff.ClosedIssues {
+				closed = append(closed, issue.ID)
+			}
+			for _, m := range r.Diff.ModifiedIssues {
+				modified = append(modified, m.IssueID)
+			}
+
+			if got := strings.Join(r.Edits, ","); got != tt.wantEdit {
+				t.Errorf("edits = %q, want %q", got, tt.wantEdit)
+			}
+			if r.Outcome.Open != tt.wantOpen || r.Outcome.Deferred != tt.wantDeferred || r.Outcome.Agents != tt.wantAgents {
+				t.Errorf("outcome open/deferred/agents = %d/%d/%d, want %d/%d/%d",
+					r.Outcome.Open, r.Outcome.Deferred, r.Outcome.Agents, tt.wantOpen, tt.wantDeferred, tt.wantAgents)
+			}
+			if got := strings.Join(r.Unblocked, ","); got != tt.wantUnblocked {
+				t.Errorf("unblocked = %q, want %q", got, tt.wantUnblocked)
+			}
+			if got := strings.Join(r.NewlyBlocked, ","); got != tt.wantNewlyBlocked {
+				t.Errorf("newly blocked = %q, want %q", got, tt.wantNewlyBlocked)
+			}
+			if got := strings.Join(closed, ","); got != tt.wantClosed {
+				t.Errorf("diff closed = %q, want %q", got, tt.wantClosed)
+			}
+			if got := strings.Join(modified, ","); got != tt.wantModified {
+				t.Errorf("diff modified = %q, want %q", got, tt.wantModified)
+			}
+			if got := len(r.RankChanges) > 0; got != tt.wantRankChanges {
+				t.Errorf("rank changes = %+v, want any: %v", r.RankChanges, tt.wantRankChanges)
+			}
+			if got := r.ForecastDelta != nil && r.ForecastDelta.P50Days < 0; got != tt.wantFaster {
+				t.Errorf("forecast delta = %+v, want faster: %v", r.ForecastDelta, tt.wantFaster)
+			}
+			for _, id := range r.Outcome.TopPicks {
+				if tt.edit.Defer != "" && id != "d" {
+					t.Errorf("deferred %s still picked: %v", id, r.Outcome.TopPicks)
+				}
+			}
+			if !reflect.DeepEqual(before, tt.issues) {
+				t.Error("scenario modified the input issues")
+			}
+		})
+	}
+}
+
+func TestApplyScenarioRejectsBadEdits(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Title: "Schema", Status: model.StatusOpen, IssueType: model.TypeTask},
+		{ID: "b", Title: "API", Status: model.StatusOpen, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+		{ID: "d", Title: "Docs", Status: model.StatusOpen, IssueType: model.TypeTask},
+		{ID: "e", Title: "Search", Status: model.StatusOpen, IssueType: model.TypeEpic},
+	}
+	now := time.Now()
+	tests := []struct {
+		edits []ScenarioEdit
+		want  string
+	}{
+		{[]ScenarioEdit{{Close: "zz"}}, "not found"},
+		{[]ScenarioEdit{{AddDep: &ScenarioDep{Issue: "b", DependsOn: "a"}}}, "already depends"},
+		{[]ScenarioEdit{{RemoveDep: &ScenarioDep{Issue: "d", DependsOn: "a"}}}, "does not depend"},
+		{[]ScenarioEdit{{AddAgents: -1}}, "fewer than one agent"},
+		{[]ScenarioEdit{{Close: "a", Defer: "e"}}, "exactly one"},
+		{[]ScenarioEdit{{Close: "a"}, {Close: "a"}}, "already closed"},
+	}
+	for _, tt := range tests {
+		if _, _, err := ApplyScenario(issues, tt.edits, 1, now); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%+v: err = %v, want %q", tt.edits, err, tt.want)
+		}
+	}
+
+	_, err := RunScenarios(issues, []Scenario{{Name: "typo", Edits: []ScenarioEdit{{Defer: "nope"}}}}, WhatIfOptions{})
+	if err == nil || !strings.Contains(err.Error(), `scenario "typo"`) {
+		t.Errorf("RunScenarios error = %v", err)
+	}
+}
+
+func TestParseScenarioEdits(t *testing.T) {
+	edits, err := ParseScenarioEdits("close:a, defer:e,add-dep:d>c,remove_dep:b>a,add-agents:2")
+	if err != nil {
+		t.Fatalf("ParseScenarioEdits: %v", err)
+	}
+	var got []string
+	for _, e := range edits {
+		got = append(got, e.String())
+	}
+	if strings.Join(got, ",") != "close:a,defer:e,add-dep:d>c,remove-dep:b>a,add-agents:2" {
+		t.Errorf("edits = %v", got)
+	}
+
+	for spec, want := range map[string]string{
+		"":               "no edits",
+		"close":          "op:argument",
+		"split:a":        "unknown op",
+		"add-dep:a":      "ISSUE>DEPENDS_ON",
+		"add-dep:a>a":    "itself",
+		"add-agents:two": "agent count",
+	} {
+		if _, err := ParseScenarioEdits(spec); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: err = %v, want %q", spec, err, want)
+		}
+	}
+}
+
+func TestLoadScenarioFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenarios.yaml")
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`agents: 2
+scenarios:
+  - name: api-first
+    description: Land the API before the UI
+    edits:
+      - close: a
+      - add_dep: {issue: d, depends_on: c}
+  - edits:
+      - add_agents: 1
+`)
+	file, err := LoadScenarioFile(path)
+	if err != nil {
+		t.Fatalf("LoadScenarioFile: %v", err)
+	}
+	if file.Agents != 2 || len(file.Scenarios) != 2 || file.Scenarios[1].Name != "scenario-2" {
+		t.Fatalf("file = %+v", file)
+	}
+	if dep := file.Scenarios[0].Edits[1].AddDep; dep == nil || dep.Issue != "d" || dep.DependsOn != "c" {
+		t.Errorf("add_dep = %+v", dep)
+	}
+
+	for body, want := range map[string]string{
+		"scenarios: []": "none defined",
+		"scenarios: [{name: x, edits: [{close: a}]}, {name: x, edits: [{close: b}]}]": "duplicate name",
+		"scenarios: [{name: x, edits: [{close: a, defer: b}]}]":                       "exactly one",
+		"scenarios: {": "parsing scenarios",
+	} {
+		write(body)
+		if _, err := LoadScenarioFile(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: err = %v, want %q", body, err, want)
+		}
+	}
+}
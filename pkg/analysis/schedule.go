@@ -0,0 +1,495 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// workdayStartHour is when each member's working hours begin
+const workdayStartHour = 9
+
+// ScheduleOptions configures ScheduleWork.
+type ScheduleOptions struct {
+	Now time.Time // Earliest start (default time.Now())
+}
+
+// ScheduledIssue is an open issue assigned to a member's calendar.
+type ScheduledIssue struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Priority int       `json:"priority"`
+	Assignee string    `json:"assignee"`
+	Pinned   bool      `json:"pinned,omitempty"` // Assignee comes from the issue itself
+	Start    time.Time `json:"start"`
+	Finish   time.Time `json:"finish"`
+	Hours    float64   `json:"hours"`
+	// EstimateSource is "issue" for estimated_minutes, or "median" when the
+	// median of the other estimates stood in
+	EstimateSource string     `json:"estimate_source"`
+	BlockedBy      []string   `json:"blocked_by,omitempty"`
+	DueDate        *time.Time `json:"due_date,omitempty"`
+	Slips          bool       `json:"slips,omitempty"`
+	SlipDays       float64    `json:"slip_days,omitempty"` // Calendar days past DueDate
+}
+
+// MemberSchedule is one member's share of the plan.
+type MemberSchedule struct {
+	Name           string    `json:"name"`
+	CapacityHours  float64   `json:"capacity_hours"` // Per working day
+	Skills         []string  `json:"skills,omitempty"`
+	Issues         []string  `json:"issues"` // In start order
+	AssignedHours  float64   `json:"assigned_hours"`
+	AvailableHours float64   `json:"available_hours"` // Working hours between the schedule's start and end
+	Utilization    float64   `json:"utilization"`     // AssignedHours / AvailableHours
+	Finish         time.Time `json:"finish"`
+}
+
+// UnscheduledIssue is an open issue nobody on the roster can take.
+type UnscheduledIssue struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// Schedule assigns open issues to roster members over their working
+// calendars, respecting blocking dependencies.
+type Schedule struct {
+	Start       time.Time          `json:"start"`
+	End         time.Time          `json:"end"`
+	Members     []MemberSchedule   `json:"members"`
+	Assignments []ScheduledIssue   `json:"assignments"` // By start, then ID
+	Slipped     []string           `json:"slipped"`     // Issues finishing after their due date
+	Unscheduled []UnscheduledIssue `json:"unscheduled,omitempty"`
+	Utilization float64            `json:"utilization"`      // Team-wide
+	Cycles      bool               `json:"cycles,omitempty"` // Dependency cycles were broken to place every issue
+}
+
+// workCalendar is a member's working hours: capacity hours from
+// workdayStartHour on each working day that isn't a holiday.
+type workCalendar struct {
+	capacity float64
+	days     [7]bool
+	holidays map[string]bool
+}
+
+func (c workCalendar) dayStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, workdayStartHour, 0, 0, 0, t.Location())
+}
+
+func (c workCalendar) works(day time.Time) bool {
+	return c.days[day.Weekday()] && !c.holidays[day.Format("2006-01-02")]
+}
+
+func (c workCalendar) dayEnd(day time.Time) time.Time {
+	return c.dayStart(day).Add(durationHours(c.capacity))
+}
+
+// next returns the first working moment at or after t.
+func (c workCalendar) next(t time.Time) time.Time {
+	for {
+		start := c.dayStart(t)
+		if c.works(start) {
+			if t.Before(start) {
+				return start
+			}
+			if t.Before(c.dayEnd(start)) {
+				return t
+			}
+		}
+		t = start.AddDate(0, 0, 1)
+	}
+}
+
+// add returns when hours of work begun at t finish.
+func (c workCalendar) add(t time.Time, hours float64) time.Time {
+	t = c.next(t)
+	for {
+		end := c.dayEnd(t)
+		left := end.Sub(t).Hours()
+		if hours <= left+1e-9 {
+			return t.Add(durationHours(hours))
+		}
+		hours -= left
+		t = c.next(end)
+	}
+}
+
+// hoursBetween counts working hours in [from, to).
+func (c workCalendar) hoursBetween(from, to time.Time) float64 {
+	total := 0.0
+	for day := c.dayStart(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !c.works(day) {
+			continue
+		}
+		s, e := day, c.dayEnd(day)
+		if from.After(s) {
+			s = from
+		}
+		if to.Before(e) {
+			e = to
+		}
+		if e.After(s) {
+			total += e.Sub(s).Hours()
+		}
+	}
+	return total
+}
+
+func durationHours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour))
+}
+
+// scheduleMember is a roster member's calendar and scheduling state.
+type scheduleMember struct {
+	name     string
+	skills   map[string]bool
+	cal      workCalendar
+	free     time.Time // When they can next start work
+	finished bool      // Nothing left they could ever take
+	plan     *MemberSchedule
+}
+
+func (m *scheduleMember) canTake(iss model.Issue) bool {
+	if len(m.skills) == 0 || len(iss.Labels) == 0 {
+		return true
+	}
+	for _, l := range iss.Labels {
+		if m.skills[strings.ToLower(l)] {
+			return true
+		}
+	}
+	return false
+}
+
+// newScheduleMembers resolves each member's calendar from the roster
+// defaults and their own overrides. The roster must have been validated.
+func newScheduleMembers(roster *TeamRoster, now time.Time) []*scheduleMember {
+	teamDays := [7]bool{false, true, true, true, true, true, false}
+	if days, _ := parseWeekdays(roster.WorkingDays); days != nil {
+		teamDays = *days
+	}
+	teamHolidays, _ := parseHolidays(roster.Holidays)
+	teamHours := roster.HoursPerDay
+	if teamHours <= 0 {
+		teamHours = DefaultHoursPerDay
+	}
+
+	members := make([]*scheduleMember, 0, len(roster.Members))
+	for _, rm := range roster.Members {
+		cal := workCalendar{capacity: rm.Capacity, days: teamDays, holidays: make(map[string]bool)}
+		if cal.capacity <= 0 {
+			cal.capacity = teamHours
+		}
+		if days, _ := parseWeekdays(rm.WorkingDays); days != nil {
+			cal.days = *days
+		}
+		own, _ := parseHolidays(rm.Holidays)
+		for _, h := range []map[string]bool{teamHolidays, own} {
+			for d := range h {
+				cal.holidays[d] = true
+			}
+		}
+
+		skills := make(map[string]bool, len(rm.Skills))
+		for _, s := range rm.Skills {
+			skills[strings.ToLower(strings.TrimSpace(s))] = true
+		}
+		name := strings.TrimSpace(rm.Name)
+		members = append(members, &scheduleMember{
+			name:   name,
+			skills: skills,
+			cal:    cal,
+			free:   cal.next(now),
+			plan:   &MemberSchedule{Name: name, CapacityHours: cal.capacity, Skills: rm.Skills, Issues: []string{}},
+		})
+	}
+	return members
+}
+
+// ScheduleWork assigns the open issues to the roster by list scheduling:
+// whenever a member is free, the best-ranked ready issue they can take goes
+// to whichever free member would finish it first. An issue is ready once
+// its open blockers have finished. Ranking puts in-progress work first,
+// then priority, then the longest chain of dependent work.
+//
+// Issues assigned to a roster member stay with that member; others go to
+// members whose skills cover one of their labels (members without skills,
+// and issues without labels, match anything). Durations come from
+// EstimatedMinutes, falling back to the median estimate like the ETA model.
+func ScheduleWork(issues []model.Issue, roster *TeamRoster, opts ScheduleOptions) Schedule {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if roster == nil || len(roster.Members) == 0 {
+		roster = DefaultTeamRoster(1)
+	}
+	members := newScheduleMembers(roster, now)
+	byName := make(map[string]*scheduleMember, len(members))
+	for _, m := range members {
+		byName[strings.ToLower(m.name)] = m
+	}
+
+	open := make(map[string]model.Issue)
+	var ids []string
+	for _, iss := range issues {
+		if isClosedLikeStatus(iss.Status) {
+			continue
+		}
+		open[iss.ID] = iss
+		ids = append(ids, iss.ID)
+	}
+	sort.Strings(ids)
+
+	sched := Schedule{Start: now, End: now, Assignments: []ScheduledIssue{}, Slipped: []string{}}
+
+	blockers := make(map[string][]string, len(ids))
+	dependents := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		seen := make(map[string]bool)
+		for _, dep := range open[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == id || seen[dep.DependsOnID] {
+				continue
+			}
+			if _, ok := open[dep.DependsOnID]; !ok {
+				continue
+			}
+			seen[dep.DependsOnID] = true
+			blockers[id] = append(blockers[id], dep.DependsOnID)
+			dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], id)
+		}
+	}
+	order, cycles := timelineOrder(ids, blockers, dependents)
+	sched.Cycles = cycles
+
+	// Only blockers earlier in the order count, which drops cycle back-edges
+	pos := make(map[string]int, len(order))
+	for i, id := range order {
+		pos[id] = i
+	}
+	waitsOn := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		for _, b := range blockers[id] {
+			if pos[b] < pos[id] {
+				waitsOn[id] = append(waitsOn[id], b)
+			}
+		}
+		sort.Strings(waitsOn[id])
+	}
+
+	median := float64(computeMedianEstimatedMinutes(issues)) / 60
+	hours := make(map[string]float64, len(ids))
+	source := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if est := open[id].EstimatedMinutes; est != nil && *est > 0 {
+			hours[id], source[id] = float64(*est)/60, "issue"
+		} else {
+			hours[id], source[id] = median, "median"
+		}
+	}
+
+	// Longest chain of work hanging off each issue, for ranking
+	tail := make(map[string]float64, len(ids))
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		longest := 0.0
+		for _, d := range dependents[id] {
+			if pos[d] > i {
+				longest = math.Max(longest, tail[d])
+			}
+		}
+		tail[id] = hours[id] + longest
+	}
+
+	// Who may take each issue; anything without a candidate, or waiting on
+	// something without one, can't be scheduled
+	candidates := make(map[string][]*scheduleMember, len(ids))
+	pinned := make(map[string]bool)
+	unscheduled := make(map[string]bool)
+	for _, id := range order {
+		iss := open[id]
+		if m, ok := byName[strings.ToLower(strings.TrimSpace(iss.Assignee))]; ok && iss.Assignee != "" {
+			candidates[id] = []*scheduleMember{m}
+			pinned[id] = true
+		} else {
+			for _, m := range members {
+				if m.canTake(iss) {
+					candidates[id] = append(candidates[id], m)
+				}
+			}
+		}
+		reason := ""
+		if len(candidates[id]) == 0 {
+			reason = fmt.Sprintf("no member has a skill matching labels %s", strings.Join(iss.Labels, ", "))
+		}
+		for _, b := range waitsOn[id] {
+			if reason == "" && unscheduled[b] {
+				reason = fmt.Sprintf("blocked by unschedulable %s", b)
+			}
+		}
+		if reason != "" {
+			unscheduled[id] = true
+			sched.Unscheduled = append(sched.Unscheduled, UnscheduledIssue{ID: id, Title: iss.Title, Reason: reason})
+		}
+	}
+
+	rank := func(a, b string) bool {
+		ia, ib := open[a], open[b]
+		if wa, wb := ia.Status == model.StatusInProgress, ib.Status == model.StatusInProgress; wa != wb {
+			return wa
+		}
+		if ia.Priority != ib.Priority {
+			return ia.Priority < ib.Priority
+		}
+		if tail[a] != tail[b] {
+			return tail[a] > tail[b]
+		}
+		return a < b
+	}
+
+	pending := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !unscheduled[id] {
+			pending[id] = true
+		}
+	}
+	finish := make(map[string]time.Time, len(ids))
+
+	for len(pending) > 0 {
+		// The next decision point is the earliest moment someone is free
+		var t time.Time
+		for _, m := range members {
+			if !m.finished && (t.IsZero() || m.free.Before(t)) {
+				t = m.free
+			}
+		}
+		if t.IsZero() {
+			break
+		}
+
+		var ready []string
+		for id := range pending {
+			ok := true
+			for _, b := range waitsOn[id] {
+				if f, done := finish[b]; !done || f.After(t) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				ready = append(ready, id)
+			}
+		}
+		sort.Slice(ready, func(i, j int) bool { return rank(ready[i], ready[j]) })
+
+		busy := make(map[*scheduleMember]bool)
+		for _, id := range ready {
+			var best *scheduleMember
+			var bestEnd time.Time
+			for _, m := range candidates[id] {
+				if m.finished || busy[m] || !m.free.Equal(t) {
+					continue
+				}
+				end := m.cal.add(t, hours[id])
+				if best == nil || end.Before(bestEnd) {
+					best, bestEnd = m, end
+				}
+			}
+			if best == nil {
+				continue
+			}
+			busy[best] = true
+			delete(pending, id)
+			finish[id] = bestEnd
+			iss := open[id]
+			item := ScheduledIssue{
+				ID:             id,
+				Title:          iss.Title,
+				Status:         string(iss.Status),
+				Priority:       iss.Priority,
+				Assignee:       best.name,
+				Pinned:         pinned[id],
+				Start:          t,
+				Finish:         bestEnd,
+				Hours:          hours[id],
+				EstimateSource: source[id],
+				BlockedBy:      waitsOn[id],
+				DueDate:        iss.DueDate,
+			}
+			if iss.DueDate != nil && bestEnd.After(*iss.DueDate) {
+				item.Slips = true
+				item.SlipDays = math.Round(bestEnd.Sub(*iss.DueDate).Hours()/24*10) / 10
+			}
+			sched.Assignments = append(sched.Assignments, item)
+			best.plan.Issues = append(best.plan.Issues, id)
+			best.plan.AssignedHours += hours[id]
+			best.plan.Finish = bestEnd
+			best.free = best.cal.next(bestEnd)
+		}
+
+		// Members left idle wait for the next finish or for someone else to
+		// free up; with neither, nothing they could take will ever be ready
+		for _, m := range members {
+			if m.finished || busy[m] || !m.free.Equal(t) {
+				continue
+			}
+			var event time.Time
+			for _, f := range finish {
+				if f.After(t) && (event.IsZero() || f.Before(event)) {
+					event = f
+				}
+			}
+			for _, o := range members {
+				if !o.finished && o.free.After(t) && (event.IsZero() || o.free.Before(event)) {
+					event = o.free
+				}
+			}
+			if event.IsZero() {
+				m.finished = true
+				continue
+			}
+			m.free = m.cal.next(event)
+		}
+	}
+
+	sort.Slice(sched.Assignments, func(i, j int) bool {
+		a, b := sched.Assignments[i], sched.Assignments[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.ID < b.ID
+	})
+	for _, a := range sched.Assignments {
+		if a.Finish.After(sched.End) {
+			sched.End = a.Finish
+		}
+		if a.Slips {
+			sched.Slipped = append(sched.Slipped, a.ID)
+		}
+	}
+
+	var assigned, available float64
+	for _, m := range members {
+		m.plan.AvailableHours = math.Round(m.cal.hoursBetween(now, sched.End)*100) / 100
+		if m.plan.AvailableHours > 0 {
+			m.plan.Utilization = math.Round(m.plan.AssignedHours/m.plan.AvailableHours*1000) / 1000
+		}
+		if m.plan.Finish.IsZero() {
+			m.plan.Finish = now
+		}
+		assigned += m.plan.AssignedHours
+		available += m.plan.AvailableHours
+		sched.Members = append(sched.Members, *m.plan)
+	}
+	if available > 0 {
+		sched.Utilization = math.Round(assigned/available*1000) / 1000
+	}
+	return sched
+}
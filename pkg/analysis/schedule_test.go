@@ -0,0 +1,233 @@
+package analysis
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func scheduleIssue(id string, minutes, priority int, labels ...string) model.Issue {
+	return model.Issue{ID: id, Title: strings.ToUpper(id), Status: model.StatusOpen, Priority: priority, EstimatedMinutes: &minutes, Labels: labels}
+}
+
+func assignmentsByID(s Schedule) map[string]ScheduledIssue {
+	out := make(map[string]ScheduledIssue, len(s.Assignments))
+	for _, a := range s.Assignments {
+		out[a.ID] = a
+	}
+	return out
+}
+
+func TestScheduleWorkListSchedulesAcrossMembers(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	b := scheduleIssue("b", 360, 2)
+	b.Dependencies = []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}
+	issues := []model.Issue{
+		scheduleIssue("a", 360, 2),
+		b,
+		scheduleIssue("c", 360, 0),
+		scheduleIssue("d", 180, 2),
+	}
+	roster := &TeamRoster{Members: []TeamMember{{Name: "alice"}, {Name: "bob"}}}
+
+	s := ScheduleWork(issues, roster, ScheduleOptions{Now: monday})
+	got := assignmentsByID(s)
+	if len(got) != 4 || len(s.Unscheduled) != 0 {
+		t.Fatalf("assignments = %d, unscheduled = %v", len(got), s.Unscheduled)
+	}
+
+	// c outranks everything on priority and a outranks d on the work behind it
+	tuesday := monday.AddDate(0, 0, 1)
+	for id, want := range map[string]struct {
+		who   string
+		start time.Time
+	}{
+		"c": {"alice", monday},
+		"a": {"bob", monday},
+		"b": {"alice", tuesday},
+		"d": {"bob", tuesday},
+	} {
+		if got[id].Assignee != want.who || !got[id].Start.Equal(want.start) {
+			t.Errorf("%s: %s at %v, want %s at %v", id, got[id].Assignee, got[id].Start, want.who, want.start)
+		}
+	}
+	if !got["b"].Start.After(got["a"].Finish) {
+		t.Errorf("b starts %v before its blocker finishes %v", got["b"].Start, got["a"].Finish)
+	}
+	if len(got["b"].BlockedBy) != 1 || got["b"].BlockedBy[0] != "a" {
+		t.Errorf("b blocked by %v", got["b"].BlockedBy)
+	}
+	if !s.End.Equal(tuesday.Add(6 * time.Hour)) {
+		t.Errorf("end = %v", s.End)
+	}
+
+	// Both members have 12 working hours up to Tuesday 15:00
+	alice, bob := s.Members[0], s.Members[1]
+	if alice.AvailableHours != 12 || alice.Utilization != 1 || bob.Utilization != 0.75 {
+		t.Errorf("alice %+v, bob %+v", alice, bob)
+	}
+	if s.Utilization != 0.875 {
+		t.Errorf("team utilization = %v", s.Utilization)
+	}
+	if strings.Join(alice.Issues, ",") != "c,b" || strings.Join(bob.Issues, ",") != "a,d" {
+		t.Errorf("issues alice=%v bob=%v", alice.Issues, bob.Issues)
+	}
+}
+
+func TestScheduleWorkMatchesSkillsAndAssignees(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	pinned := scheduleIssue("pinned", 60, 2, "backend")
+	pinned.Assignee = "Bob"
+	downstream := scheduleIssue("downstream", 60, 2, "frontend")
+	downstream.Dependencies = []*model.Dependency{{IssueID: "downstream", DependsOnID: "ops", Type: model.DepBlocks}}
+	issues := []model.Issue{
+		scheduleIssue("fe", 60, 2, "Frontend"),
+		scheduleIssue("be", 60, 2, "backend"),
+		scheduleIssue("docs", 60, 2),
+		scheduleIssue("ops", 60, 2, "ops"),
+		pinned,
+		downstream,
+	}
+	roster := &TeamRoster{Members: []TeamMember{
+		{Name: "alice", Skills: []string{"backend"}},
+		{Name: "bob", Skills: []string{"frontend"}},
+	}}
+
+	s := ScheduleWork(issues, roster, ScheduleOptions{Now: monday})
+	got := assignmentsByID(s)
+	if got["fe"].Assignee != "bob" || got["be"].Assignee != "alice" {
+		t.Errorf("fe -> %s, be -> %s", got["fe"].Assignee, got["be"].Assignee)
+	}
+	if got["pinned"].Assignee != "bob" || !got["pinned"].Pinned {
+		t.Errorf("pinned -> %+v", got["pinned"])
+	}
+	if _, ok := got["docs"]; !ok {
+		t.Error("unlabeled issues should go to anyone")
+	}
+
+	reasons := make(map[string]string)
+	for _, u := range s.Unscheduled {
+		reasons[u.ID] = u.Reason
+	}
+	if len(reasons) != 2 || !strings.Contains(reasons["ops"], "ops") || !strings.Contains(reasons["downstream"], "ops") {
+		t.Errorf("unscheduled = %+v", s.Unscheduled)
+	}
+}
+
+func TestScheduleWorkFollowsCalendarsAndFlagsSlips(t *testing.T) {
+	friday := time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC)
+	big := scheduleIssue("big", 12*60, 1)
+	big.DueDate = &due
+	roster := &TeamRoster{
+		HoursPerDay: 8,
+		Holidays:    []string{"2025-03-10"},
+		Members:     []TeamMember{{Name: "alice"}},
+	}
+
+	s := ScheduleWork([]model.Issue{big}, roster, ScheduleOptions{Now: friday})
+	got := assignmentsByID(s)["big"]
+	// 8h Friday, weekend and the Monday holiday off, 4h Tuesday
+	if want := time.Date(2025, 3, 11, 13, 0, 0, 0, time.UTC); !got.Finish.Equal(want) {
+		t.Fatalf("finish = %v, want %v", got.Finish, want)
+	}
+	if !got.Slips || got.SlipDays != 0.8 || strings.Join(s.Slipped, ",") != "big" {
+		t.Errorf("slip = %v %v, slipped %v", got.Slips, got.SlipDays, s.Slipped)
+	}
+	if s.Members[0].AvailableHours != 12 || s.Members[0].Utilization != 1 {
+		t.Errorf("member = %+v", s.Members[0])
+	}
+
+	// Starting after hours rolls to the member's next working day
+	cal := workCalendar{capacity: 4, days: [7]bool{time.Wednesday: true}}
+	if got := cal.next(friday); !got.Equal(time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("next wednesday = %v", got)
+	}
+	if got := cal.add(friday, 6); !got.Equal(time.Date(2025, 3, 19, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("6h at 4h/week = %v", got)
+	}
+}
+
+func TestScheduleWorkFallsBackAndBreaksCycles(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "x", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks}}},
+		{ID: "y", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+		{ID: "z", Status: model.StatusClosed},
+	}
+
+	s := ScheduleWork(issues, nil, ScheduleOptions{Now: monday})
+	if !s.Cycles || len(s.Assignments) != 2 {
+		t.Fatalf("cycles %v, assignments %d", s.Cycles, len(s.Assignments))
+	}
+	for _, a := range s.Assignments {
+		if a.EstimateSource != "median" || math.Abs(a.Hours-float64(DefaultEstimatedMinutes)/60) > 1e-9 {
+			t.Errorf("%s: %v hours from %s", a.ID, a.Hours, a.EstimateSource)
+		}
+		if a.Assignee != "agent-1" {
+			t.Errorf("%s assigned to %s", a.ID, a.Assignee)
+		}
+	}
+
+	empty := ScheduleWork(nil, DefaultTeamRoster(2), ScheduleOptions{Now: monday})
+	if len(empty.Members) != 2 || len(empty.Assignments) != 0 || !empty.End.Equal(monday) {
+		t.Errorf("empty schedule = %+v", empty)
+	}
+}
+
+func TestLoadTeamRoster(t *testing.T) {
+	dir := t.TempDir()
+	path := TeamRosterPath(dir)
+
+	roster, err := LoadTeamRoster(path)
+	if err != nil || roster != nil {
+		t.Fatalf("missing roster = %v, %v", roster, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`hours_per_day: 5
+working_days: [monday, tue, wed, thu]
+holidays: ["2025-12-25"]
+members:
+  - name: alice
+    skills: [backend]
+  - name: bob
+    capacity: 3
+    working_days: [fri]
+`)
+	roster, err = LoadTeamRoster(path)
+	if err != nil {
+		t.Fatalf("LoadTeamRoster: %v", err)
+	}
+	if len(roster.Members) != 2 || roster.Members[1].Capacity != 3 || roster.HoursPerDay != 5 {
+		t.Errorf("roster = %+v", roster)
+	}
+
+	for body, want := range map[string]string{
+		"members: []":                                "no members",
+		"members: [{name: a}, {name: A}]":            "duplicate member",
+		"members: [{name: a, capacity: 30}]":         "capacity",
+		"members: [{name: a, working_days: [xyz]}]":  "unknown working day",
+		"holidays: [tomorrow]\nmembers: [{name: a}]": "invalid holiday",
+		"members: {": "parsing team roster",
+	} {
+		write(body)
+		if _, err := LoadTeamRoster(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: err = %v, want %q", body, err, want)
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ScoringProfilesFilename is the profile file read from a project's .bv directory
+const ScoringProfilesFilename = "scoring.yaml"
+
+// DefaultScoringProfileName is the profile that reproduces the compiled-in weights
+const DefaultScoringProfileName = "default"
+
+// ScoreWeights are the per-component weights of the impact score. Their keys
+// in a profile file match the ScoreBreakdown JSON names.
+type ScoreWeights struct {
+	PageRank      float64 `json:"pagerank"`
+	Betweenness   float64 `json:"betweenness"`
+	BlockerRatio  float64 `json:"blocker_ratio"`
+	Staleness     float64 `json:"staleness"`
+	PriorityBoost float64 `json:"priority_boost"`
+	TimeToImpact  float64 `json:"time_to_impact"`
+	Urgency       float64 `json:"urgency"`
+	Risk          float64 `json:"risk"`
+}
+
+// DefaultScoreWeights returns the compiled-in Weight* constants
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		PageRank:      WeightPageRank,
+		Betweenness:   WeightBetweenness,
+		BlockerRatio:  WeightBlockerRatio,
+		Staleness:     WeightStaleness,
+		PriorityBoost: WeightPriorityBoost,
+		TimeToImpact:  WeightTimeToImpact,
+		Urgency:       WeightUrgency,
+		Risk:          WeightRisk,
+	}
+}
+
+// ScoreComponents lists the weight names in ScoreBreakdown order
+var ScoreComponents = []string{
+	"pagerank", "betweenness", "blocker_ratio", "staleness",
+	"priority_boost", "time_to_impact", "urgency", "risk",
+}
+
+func (w *ScoreWeights) field(name string) *float64 {
+	switch name {
+	case "pagerank":
+		return &w.PageRank
+	case "betweenness":
+		return &w.Betweenness
+	case "blocker_ratio":
+		return &w.BlockerRatio
+	case "staleness":
+		return &w.Staleness
+	case "priority_boost":
+		return &w.PriorityBoost
+	case "time_to_impact":
+		return &w.TimeToImpact
+	case "urgency":
+		return &w.Urgency
+	case "risk":
+		return &w.Risk
+	}
+	return nil
+}
+
+// Map returns the weights keyed by component name
+func (w ScoreWeights) Map() map[string]float64 {
+	out := make(map[string]float64, len(ScoreComponents))
+	for _, name := range ScoreComponents {
+		out[name] = *w.field(name)
+	}
+	return out
+}
+
+func (w ScoreWeights) sum() float64 {
+	return w.PageRank + w.Betweenness + w.BlockerRatio + w.Staleness +
+		w.PriorityBoost + w.TimeToImpact + w.Urgency + w.Risk
+}
+
+// UrgencyRule sets the urgency of issues matching every condition it names.
+// A profile's rules replace the built-in urgent/critical/... label table;
+// the age-based decay still applies on top.
+type UrgencyRule struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Label matches any label containing it, case-insensitively
+	Label string `yaml:"label,omitempty" json:"label,omitempty"`
+	Type  string `yaml:"type,omitempty" json:"type,omitempty"`
+	// MaxPriority matches P0 through this priority
+	MaxPriority *int `yaml:"max_priority,omitempty" json:"max_priority,omitempty"`
+	// DueWithinDays matches issues due in this many days or overdue
+	DueWithinDays *int `yaml:"due_within_days,omitempty" json:"due_within_days,omitempty"`
+	// Score is the 0-1 urgency of a match; the highest matching rule wins
+	Score float64 `yaml:"score" json:"score"`
+}
+
+func (r UrgencyRule) matches(issue *model.Issue, now time.Time) bool {
+	if r.Label != "" {
+		want := strings.ToLower(r.Label)
+		found := false
+		for _, label := range issue.Labels {
+			if strings.Contains(strings.ToLower(label), want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Type != "" && !strings.EqualFold(r.Type, string(issue.IssueType)) {
+		return false
+	}
+	if r.MaxPriority != nil && issue.Priority > *r.MaxPriority {
+		return false
+	}
+	if r.DueWithinDays != nil {
+		if issue.DueDate == nil || issue.DueDate.Sub(now) > time.Duration(*r.DueWithinDays)*24*time.Hour {
+			return false
+		}
+	}
+	return true
+}
+
+func (r UrgencyRule) label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	var parts []string
+	if r.Label != "" {
+		parts = append(parts, "label "+r.Label)
+	}
+	if r.Type != "" {
+		parts = append(parts, "type "+r.Type)
+	}
+	if r.MaxPriority != nil {
+		parts = append(parts, fmt.Sprintf("P%d or higher", *r.MaxPriority))
+	}
+	if r.DueWithinDays != nil {
+		parts = append(parts, fmt.Sprintf("due within %dd", *r.DueWithinDays))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ScoringExclusions drop matching issues from scoring, so they are never
+// recommended. They still count in the dependency graph.
+type ScoringExclusions struct {
+	IDs      []string `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Labels   []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Types    []string `yaml:"types,omitempty" json:"types,omitempty"`
+	Statuses []string `yaml:"statuses,omitempty" json:"statuses,omitempty"`
+}
+
+func (e ScoringExclusions) excludes(issue *model.Issue) bool {
+	has := func(list []string, v string) bool {
+		for _, item := range list {
+			if strings.EqualFold(item, v) {
+				return true
+			}
+		}
+		return false
+	}
+	if has(e.IDs, issue.ID) || has(e.Types, string(issue.IssueType)) || has(e.Statuses, string(issue.Status)) {
+		return true
+	}
+	for _, label := range issue.Labels {
+		if has(e.Labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// TriageWeights override how triage blends the impact score with its
+// unblock and quick-win boosts (see TriageScoringOptions)
+type TriageWeights struct {
+	Base     float64 `yaml:"base" json:"base"`
+	Unblock  float64 `yaml:"unblock" json:"unblock"`
+	QuickWin float64 `yaml:"quick_win" json:"quick_win"`
+}
+
+// ScoringProfile is a named set of overrides for impact and triage scoring.
+// Weights not named keep their compiled-in value, and the result is rescaled
+// to sum to 1 so scores stay comparable across profiles.
+//
+//	weights: {priority_boost: 0.25, urgency: 0.2}
+//	label_multipliers: {customer: 1.5}
+//	type_multipliers: {bug: 1.5, chore: 0.5}
+//	urgency_rules:
+//	  - {label: sev1, score: 1}
+//	  - {type: bug, max_priority: 1, score: 0.8}
+//	exclude: {labels: [wontfix], types: [epic]}
+//	triage: {base: 0.6, unblock: 0.3, quick_win: 0.1}
+type ScoringProfile struct {
+	Name        string `yaml:"-" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Source is "builtin" or the file the profile came from
+	Source  string             `yaml:"-" json:"source"`
+	Weights map[string]float64 `yaml:"weights,omitempty" json:"weights,omitempty"`
+	// Multipliers scale the final score of issues with a label or type;
+	// an issue's label multipliers compound
+	LabelMultipliers map[string]float64 `yaml:"label_multipliers,omitempty" json:"label_multipliers,omitempty"`
+	TypeMultipliers  map[string]float64 `yaml:"type_multipliers,omitempty" json:"type_multipliers,omitempty"`
+	UrgencyRules     []UrgencyRule      `yaml:"urgency_rules,omitempty" json:"urgency_rules,omitempty"`
+	Exclude          ScoringExclusions  `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	Triage           *TriageWeights     `yaml:"triage,omitempty" json:"triage,omitempty"`
+
+	resolved ScoreWeights
+}
+
+// ComponentWeights returns the effective, normalized component weights
+func (p *ScoringProfile) ComponentWeights() ScoreWeights {
+	return p.resolved
+}
+
+// TriageScoringOptions returns the triage options with the profile's
+// blend weights applied
+func (p *ScoringProfile) TriageScoringOptions() TriageScoringOptions {
+	opts := DefaultTriageScoringOptions()
+	if p.Triage != nil {
+		opts.BaseScoreWeight = p.Triage.Base
+		opts.UnblockBoostWeight = p.Triage.Unblock
+		opts.QuickWinWeight = p.Triage.QuickWin
+	}
+	return opts
+}
+
+// multiplier returns the combined label and type multiplier for issue
+func (p *ScoringProfile) multiplier(issue *model.Issue) float64 {
+	m := 1.0
+	for _, label := range issue.Labels {
+		if v, ok := lookupFold(p.LabelMultipliers, label); ok {
+			m *= v
+		}
+	}
+	if v, ok := lookupFold(p.TypeMultipliers, string(issue.IssueType)); ok {
+		m *= v
+	}
+	return m
+}
+
+func lookupFold(m map[string]float64, key string) (float64, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// Compile validates the profile and computes its effective weights. Profiles
+// built in code must be compiled before use; loaded ones already are.
+func (p *ScoringProfile) Compile() error {
+	w := DefaultScoreWeights()
+	for name, v := range p.Weights {
+		f := w.field(strings.ToLower(name))
+		if f == nil {
+			return fmt.Errorf("unknown weight %q (want one of %s)", name, strings.Join(ScoreComponents, ", "))
+		}
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("weight %s must be a non-negative number, got %v", name, v)
+		}
+		*f = v
+	}
+	total := w.sum()
+	if total <= 0 {
+		return fmt.Errorf("weights sum to zero")
+	}
+	// The built-in constants already sum to 1; only rescale real overrides
+	if math.Abs(total-1) > 1e-9 {
+		for _, name := range ScoreComponents {
+			*w.field(name) /= total
+		}
+	}
+	p.resolved = w
+
+	for kind, m := range map[string]map[string]float64{"label": p.LabelMultipliers, "type": p.TypeMultipliers} {
+		for key, v := range m {
+			if v <= 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("%s multiplier %s must be positive, got %v", kind, key, v)
+			}
+		}
+	}
+	for i, r := range p.UrgencyRules {
+		if r.Label == "" && r.Type == "" && r.MaxPriority == nil && r.DueWithinDays == nil {
+			return fmt.Errorf("urgency rule %d has no conditions", i+1)
+		}
+		if r.Score < 0 || r.Score > 1 {
+			return fmt.Errorf("urgency rule %d: score must be between 0 and 1, got %v", i+1, r.Score)
+		}
+	}
+	if t := p.Triage; t != nil {
+		if t.Base < 0 || t.Unblock < 0 || t.QuickWin < 0 {
+			return fmt.Errorf("triage weights must be non-negative")
+		}
+		if t.Base+t.Unblock+t.QuickWin == 0 {
+			return fmt.Errorf("triage weights sum to zero")
+		}
+	}
+	return nil
+}
+
+// builtinScoringProfiles are always available; a profile file may replace
+// any of them by name
+func builtinScoringProfiles() []*ScoringProfile {
+	return []*ScoringProfile{
+		{
+			Name:        DefaultScoringProfileName,
+			Description: "Compiled-in weights",
+		},
+		{
+			Name:        "unblock",
+			Description: "Favor work that unblocks the most downstream issues",
+			Weights: map[string]float64{
+				"pagerank": 0.25, "betweenness": 0.20, "blocker_ratio": 0.25, "time_to_impact": 0.15,
+				"staleness": 0.02, "priority_boost": 0.05, "urgency": 0.04, "risk": 0.04,
+			},
+			Triage: &TriageWeights{Base: 0.60, Unblock: 0.30, QuickWin: 0.10},
+		},
+		{
+			Name:        "bug-burndown",
+			Description: "Burn down bugs first, by priority and urgency",
+			Weights:     map[string]float64{"priority_boost": 0.25, "urgency": 0.20, "staleness": 0.10},
+			TypeMultipliers: map[string]float64{
+				string(model.TypeBug):     1.5,
+				string(model.TypeFeature): 0.8,
+			},
+		},
+	}
+}
+
+var defaultScoringProfile = func() *ScoringProfile {
+	p := builtinScoringProfiles()[0]
+	p.Source = "builtin"
+	if err := p.Compile(); err != nil {
+		panic(err)
+	}
+	return p
+}()
+
+// DefaultScoringProfile returns the shared profile for the compiled-in
+// weights. Callers must not modify it.
+func DefaultScoringProfile() *ScoringProfile {
+	return defaultScoringProfile
+}
+
+// ScoringProfiles is the set of profiles a project can select from
+type ScoringProfiles struct {
+	// Default is used when no profile is named
+	Default  string
+	profiles map[string]*ScoringProfile
+}
+
+// ScoringProfileFile is the on-disk format of .bv/scoring.yaml
+type ScoringProfileFile struct {
+	Default  string                     `yaml:"default,omitempty" json:"default,omitempty"`
+	Profiles map[string]*ScoringProfile `yaml:"profiles" json:"profiles"`
+}
+
+// BuiltinScoringProfiles returns the bundled profiles only
+func BuiltinScoringProfiles() *ScoringProfiles {
+	s := &ScoringProfiles{Default: DefaultScoringProfileName, profiles: make(map[string]*ScoringProfile)}
+	for _, p := range builtinScoringProfiles() {
+		p.Source = "builtin"
+		if err := p.Compile(); err != nil {
+			panic(fmt.Sprintf("builtin scoring profile %s: %v", p.Name, err))
+		}
+		s.profiles[p.Name] = p
+	}
+	return s
+}
+
+// ScoringProfilesPath returns the default profile file path for a project
+func ScoringProfilesPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", ScoringProfilesFilename)
+}
+
+// LoadScoringProfiles returns the built-in profiles plus those in the file
+// at path. A missing file yields just the built-ins.
+func LoadScoringProfiles(path string) (*ScoringProfiles, error) {
+	s := BuiltinScoringProfiles()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading scoring profiles: %w", err)
+	}
+
+	var file ScoringProfileFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parsing scoring profiles: %w", err)
+	}
+	for name, p := range file.Profiles {
+		if p == nil {
+			p = &ScoringProfile{}
+		}
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid scoring profiles: profile with an empty name")
+		}
+		p.Name = name
+		p.Source = path
+		if err := p.Compile(); err != nil {
+			return nil, fmt.Errorf("invalid scoring profile %q: %w", name, err)
+		}
+		s.profiles[name] = p
+	}
+	if file.Default != "" {
+		if _, ok := s.profiles[file.Default]; !ok {
+			return nil, fmt.Errorf("invalid scoring profiles: default profile %q is not defined", file.Default)
+		}
+		s.Default = file.Default
+	}
+	return s, nil
+}
+
+// Get returns the named profile, or the default one for "".
+func (s *ScoringProfiles) Get(name string) (*ScoringProfile, error) {
+	if name == "" {
+		name = s.Default
+	}
+	if p, ok := s.profiles[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown scoring profile %q (available: %s)", name, strings.Join(s.Names(), ", "))
+}
+
+// Names returns the profile names, the built-in default first and the rest
+// sorted
+func (s *ScoringProfiles) Names() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == DefaultScoringProfileName) != (names[j] == DefaultScoringProfileName) {
+			return names[i] == DefaultScoringProfileName
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Next returns the profile after name in Names order, wrapping around
+func (s *ScoringProfiles) Next(name string) *ScoringProfile {
+	names := s.Names()
+	for i, n := range names {
+		if n == name {
+			return s.profiles[names[(i+1)%len(names)]]
+		}
+	}
+	return s.profiles[names[0]]
+}
+
+// SaveScoringProfile writes p into the profile file at path, replacing a
+// profile of the same name. The rest of the file, comments included, is
+// kept; a missing file is created.
+func SaveScoringProfile(path string, p *ScoringProfile) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading scoring profiles: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing scoring profiles: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("parsing scoring profiles: %s is not a mapping", path)
+	}
+
+	var value yaml.Node
+	if err := value.Encode(p); err != nil {
+		return fmt.Errorf("encoding scoring profile: %w", err)
+	}
+	profiles := yamlMappingValue(root, "profiles")
+	if profiles == nil {
+		profiles = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "profiles"}, profiles)
+	} else if profiles.Kind != yaml.MappingNode {
+		// "profiles:" with nothing under it
+		*profiles = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	if existing := yamlMappingValue(profiles, p.Name); existing != nil {
+		*existing = value
+	} else {
+		profiles.Content = append(profiles.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p.Name}, &value)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding scoring profiles: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := replaceFile(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing scoring profiles: %w", err)
+	}
+	return nil
+}
+
+// replaceFile writes data to a temp file next to path and renames it over
+// path, so readers never see a half-written file. An existing file keeps
+// its permissions.
+func replaceFile(path string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value node for key in a mapping node
+func yamlMappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package analysis
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestScoringProfileChangesTriage(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	priorityOnly := map[string]float64{"pagerank": 0, "betweenness": 0, "blocker_ratio": 0, "time_to_impact": 0, "priority_boost": 1}
+
+	tests := []struct {
+		name           string
+		issues         []model.Issue
+		profile        *ScoringProfile
+		wantProfile    string
+		wantTop        string
+		wantMultiplier float64
+		wantUrgency    string
+		excluded       string
+	}{
+		{
+			name: "default profile favors the blocker",
+			issues: []model.Issue{
+				{ID: "core", Title: "Core", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created},
+				{ID: "api", Title: "API", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "api", DependsOnID: "core", Type: model.DepBlocks}}},
+				{ID: "ui", Title: "UI", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "ui", DependsOnID: "core", Type: model.DepBlocks}}},
+				{ID: "crash", Title: "Crash", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeBug, CreatedAt: created, UpdatedAt: created},
+			},
+			wantProfile: DefaultScoringProfileName,
+			wantTop:     "core",
+		},
+		{
+			name: "type multiplier",
+			issues: []model.Issue{
+				{ID: "core", Title: "Core", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created},
+				{ID: "api", Title: "API", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created,
+					Dependencies: []*model.Dependency{{IssueID: "api", DependsOnID: "core", Type: model.DepBlocks}}},
+				{ID: "crash", Title: "Crash", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeBug, CreatedAt: created, UpdatedAt: created},
+			},
+			profile: &ScoringProfile{
+				Name:            "bugs",
+				Weights:         priorityOnly,
+				TypeMultipliers: map[string]float64{"BUG": 3},
+				Triage:          &TriageWeights{Base: 1},
+			},
+			wantProfile:    "bugs",
+			wantTop:        "crash",
+			wantMultiplier: 3,
+		},
+		{
+			name: "urgency rule matches labels case-insensitively",
+			issues: []model.Issue{
+				{ID: "docs", Title: "Docs", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created},
+				{ID: "login", Title: "Login", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, Labels: []string{"Customer"}, CreatedAt: created, UpdatedAt: created},
+			},
+			profile: &ScoringProfile{
+				Name:         "customers",
+				Weights:      priorityOnly,
+				UrgencyRules: []UrgencyRule{{Name: "customer-facing", Label: "customer", Score: 1}},
+				Triage:       &TriageWeights{Base: 1},
+			},
+			wantProfile: "customers",
+			wantTop:     "login",
+			wantUrgency: "customer-facing",
+		},
+		{
+			name: "excluded label",
+			issues: []model.Issue{
+				{ID: "junk", Title: "Junk", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeTask, Labels: []string{"wontfix"}, CreatedAt: created, UpdatedAt: created},
+				{ID: "real", Title: "Real", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created},
+			},
+			profile: &ScoringProfile{
+				Name:    "cleanup",
+				Weights: priorityOnly,
+				Exclude: ScoringExclusions{Labels: []string{"WontFix"}},
+			},
+			wantProfile: "cleanup",
+			wantTop:     "real",
+			excluded:    "junk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.profile != nil {
+				if err := tt.profile.Compile(); err != nil {
+					t.Fatalf("Compile: %v", err)
+				}
+			}
+			got := ComputeTriageWithOptionsAndTime(tt.issues, TriageOptions{WaitForPhase2: true, Profile: tt.profile}, now)
+			if got.Meta.Profile != tt.wantProfile {
+				t.Errorf("meta profile = %q, want %q", got.Meta.Profile, tt.wantProfile)
+			}
+			top := got.Recommendations[0]
+			if top.ID != tt.wantTop || top.Breakdown.Multiplier != tt.wantMultiplier {
+				t.Errorf("top = %s (multiplier %v), want %s (multiplier %v)", top.ID, top.Breakdown.Multiplier, tt.wantTop, tt.wantMultiplier)
+			}
+			if !strings.Contains(top.Breakdown.UrgencyExplanation, tt.wantUrgency) {
+				t.Errorf("urgency explanation = %q, want %q", top.Breakdown.UrgencyExplanation, tt.wantUrgency)
+			}
+			if tt.excluded == "" {
+				return
+			}
+			for _, rec := range got.Recommendations {
+				if rec.ID == tt.excluded {
+					t.Error("excluded issue was recommended")
+				}
+			}
+			// The analyzer's profile applies to priority recommendations too
+			analyzer := NewAnalyzer(tt.issues)
+			analyzer.SetScoringProfile(tt.profile)
+			for _, s := range analyzer.ComputeImpactScoresAt(now) {
+				if s.IssueID == tt.excluded {
+					t.Error("excluded issue has an impact score")
+				}
+			}
+		})
+	}
+}
+
+func TestScoringProfileComponentWeights(t *testing.T) {
+	profile := &ScoringProfile{
+		Name:    "bugs",
+		Weights: map[string]float64{"pagerank": 0, "betweenness": 0, "blocker_ratio": 0, "time_to_impact": 0, "priority_boost": 1},
+	}
+	if err := profile.Compile(); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if w := profile.ComponentWeights(); math.Abs(w.PriorityBoost-1/1.25) > 1e-9 || w.PageRank != 0 {
+		t.Errorf("weights = %+v", w)
+	}
+	if DefaultScoringProfile().ComponentWeights() != DefaultScoreWeights() {
+		t.Error("default profile rescaled the built-in weights")
+	}
+}
+
+func TestDefaultScoringProfileMatchesConstants(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	analyzer := NewAnalyzer([]model.Issue{
+		{ID: "core", Title: "Core", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created},
+		{ID: "api", Title: "API", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, CreatedAt: created, UpdatedAt: created,
+			Dependencies: []*model.Dependency{{IssueID: "api", DependsOnID: "core", Type: model.DepBlocks}}},
+		{ID: "crash", Title: "Crash", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeBug, Labels: []string{"customer"}, CreatedAt: created, UpdatedAt: created},
+	})
+	stats := analyzer.Analyze()
+
+	for _, s := range analyzer.ComputeImpactScoresFromStats(&stats, now) {
+		b := s.Breakdown
+		want := b.PageRankNorm*WeightPageRank + b.BetweennessNorm*WeightBetweenness +
+			b.BlockerRatioNorm*WeightBlockerRatio + b.StalenessNorm*WeightStaleness +
+			b.PriorityBoostNorm*WeightPriorityBoost + b.TimeToImpactNorm*WeightTimeToImpact +
+			b.UrgencyNorm*WeightUrgency + b.RiskNorm*WeightRisk
+		if math.Abs(s.Score-want) > 1e-12 || b.Multiplier != 0 {
+			t.Errorf("%s: score %v, want %v", s.IssueID, s.Score, want)
+		}
+	}
+}
+
+func TestLoadScoringProfiles(t *testing.T) {
+	dir := t.TempDir()
+	path := ScoringProfilesPath(dir)
+
+	profiles, err := LoadScoringProfiles(path)
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if got := strings.Join(profiles.Names(), ","); got != "default,bug-burndown,unblock" {
+		t.Errorf("builtin names = %s", got)
+	}
+	if p, _ := profiles.Get(""); p.Name != DefaultScoringProfileName {
+		t.Errorf("default = %s", p.Name)
+	}
+	if profiles.Next("unblock").Name != DefaultScoringProfileName {
+		t.Error("Next should wrap around")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`default: bugs
+profiles:
+  bugs:
+    description: Bug burn-down
+    weights: {priority_boost: 0.4}
+    type_multipliers: {bug: 2}
+    urgency_rules:
+      - {type: bug, max_priority: 1, score: 0.9}
+    exclude: {types: [epic]}
+  unblock:
+    triage: {base: 0.5, unblock: 0.5, quick_win: 0}
+`)
+	profiles, err = LoadScoringProfiles(path)
+	if err != nil {
+		t.Fatalf("LoadScoringProfiles: %v", err)
+	}
+	bugs, err := profiles.Get("")
+	if err != nil || bugs.Name != "bugs" || bugs.Source != path {
+		t.Fatalf("default = %+v, %v", bugs, err)
+	}
+	if w := bugs.ComponentWeights(); math.Abs(w.PriorityBoost-0.4/1.3) > 1e-9 {
+		t.Errorf("priority_boost = %v", w.PriorityBoost)
+	}
+	unblock, _ := profiles.Get("unblock")
+	if unblock.Source != path || unblock.TriageScoringOptions().UnblockBoostWeight != 0.5 {
+		t.Errorf("file profile should replace the builtin: %+v", unblock)
+	}
+	if _, err := profiles.Get("nope"); err == nil || !strings.Contains(err.Error(), "available: default, bug-burndown, bugs, unblock") {
+		t.Errorf("unknown profile err = %v", err)
+	}
+
+	for body, want := range map[string]string{
+		"profiles: {x: {weights: {speed: 1}}}":     "unknown weight",
+		"profiles: {x: {weights: {pagerank: -1}}}": "non-negative",
+		"profiles: {x: {weights: {pagerank: 0, betweenness: 0, blocker_ratio: 0, staleness: 0, priority_boost: 0, time_to_impact: 0, urgency: 0, risk: 0}}}": "sum to zero",
+		"profiles: {x: {label_multipliers: {docs: 0}}}":          "must be positive",
+		"profiles: {x: {urgency_rules: [{score: 1}]}}":           "no conditions",
+		"profiles: {x: {urgency_rules: [{label: a, score: 2}]}}": "between 0 and 1",
+		"profiles: {x: {colour: red}}":                           "parsing scoring profiles",
+		"default: missing\nprofiles: {x: {}}":                    "not defined",
+	} {
+		write(body)
+		if _, err := LoadScoringProfiles(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: err = %v, want %q", body, err, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// TimelineOptions configures BuildTimeline.
+type TimelineOptions struct {
+	Agents int       // Parallel agents assumed by the ETA estimates (default 1)
+	Now    time.Time // Where the timeline starts (default time.Now())
+}
+
+// TimelineItem is an open issue placed on the calendar.
+type TimelineItem struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	Priority  int        `json:"priority"`
+	Track     string     `json:"track,omitempty"` // Execution plan track it belongs to
+	Start     time.Time  `json:"start"`
+	End       time.Time  `json:"end"`
+	Days      float64    `json:"days"`       // ETA estimate
+	SlackDays float64    `json:"slack_days"` // How far End can slip without moving the last finish
+	Critical  bool       `json:"critical"`
+	DueDate   *time.Time `json:"due_date,omitempty"`
+	MissesDue bool       `json:"misses_due,omitempty"`
+	BlockedBy []string   `json:"blocked_by,omitempty"` // Open blockers that must finish first
+}
+
+// SlackEnd returns the latest End that doesn't delay the timeline.
+func (it TimelineItem) SlackEnd() time.Time {
+	return it.End.Add(durationDays(it.SlackDays))
+}
+
+// Timeline lays open issues on a calendar: each starts when its open
+// blockers finish and lasts its ETA estimate. Slack and the critical path
+// come from a forward/backward pass over those dates, so they are in days
+// rather than the dependency hops of GraphStats.Slack.
+type Timeline struct {
+	Start        time.Time      `json:"start"`
+	End          time.Time      `json:"end"`
+	Items        []TimelineItem `json:"items"`
+	CriticalPath []string       `json:"critical_path"` // Critical items by start date
+	MissedDue    int            `json:"missed_due"`
+	Cycles       bool           `json:"cycles,omitempty"` // Dependency cycles were broken to place every issue
+}
+
+// criticalSlackDays is how little slack still counts as critical.
+const criticalSlackDays = 0.01
+
+// BuildTimeline schedules the open issues in dependency order using their
+// ETA estimates, grouping them by the execution plan's tracks.
+func BuildTimeline(issues []model.Issue, stats *GraphStats, plan ExecutionPlan, opts TimelineOptions) Timeline {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	agents := opts.Agents
+	if agents <= 0 {
+		agents = 1
+	}
+
+	open := make(map[string]model.Issue)
+	var ids []string
+	for _, iss := range issues {
+		if isClosedLikeStatus(iss.Status) {
+			continue
+		}
+		open[iss.ID] = iss
+		ids = append(ids, iss.ID)
+	}
+	sort.Strings(ids)
+	if len(ids) == 0 {
+		return Timeline{Start: now, End: now}
+	}
+
+	// Blocking edges between open issues
+	blockers := make(map[string][]string, len(ids))
+	dependents := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		seen := make(map[string]bool)
+		for _, dep := range open[id].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == id || seen[dep.DependsOnID] {
+				continue
+			}
+			if _, ok := open[dep.DependsOnID]; !ok {
+				continue
+			}
+			seen[dep.DependsOnID] = true
+			blockers[id] = append(blockers[id], dep.DependsOnID)
+			dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], id)
+		}
+		sort.Strings(blockers[id])
+	}
+	for id := range dependents {
+		sort.Strings(dependents[id])
+	}
+
+	order, cycles := timelineOrder(ids, blockers, dependents)
+
+	days := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		if eta, err := EstimateETAForIssue(issues, stats, id, agents, now); err == nil {
+			days[id] = eta.EstimatedDays
+		}
+	}
+
+	// Forward pass: earliest start and finish, in days from now
+	earlyStart := make(map[string]float64, len(ids))
+	earlyFinish := make(map[string]float64, len(ids))
+	placed := make(map[string]bool, len(ids))
+	finish := 0.0
+	for _, id := range order {
+		start := 0.0
+		for _, b := range blockers[id] {
+			if placed[b] {
+				start = math.Max(start, earlyFinish[b])
+			}
+		}
+		earlyStart[id] = start
+		earlyFinish[id] = start + days[id]
+		placed[id] = true
+		finish = math.Max(finish, earlyFinish[id])
+	}
+
+	// Backward pass: latest finish that keeps the overall finish
+	lateFinish := make(map[string]float64, len(ids))
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		lf := finish
+		for _, d := range dependents[id] {
+			if lfd, ok := lateFinish[d]; ok {
+				lf = math.Min(lf, lfd-days[d])
+			}
+		}
+		lateFinish[id] = lf
+	}
+
+	// Tracks: actionable issues take their plan track, the rest inherit
+	// the track of their first blocker
+	track := make(map[string]string)
+	for _, t := range plan.Tracks {
+		for _, item := range t.Items {
+			track[item.ID] = t.TrackID
+		}
+	}
+	for _, id := range order {
+		if track[id] != "" {
+			continue
+		}
+		for _, b := range blockers[id] {
+			if track[b] != "" {
+				track[id] = track[b]
+				break
+			}
+		}
+	}
+
+	tl := Timeline{Start: now, End: now.Add(durationDays(finish)), Cycles: cycles}
+	for _, id := range ids {
+		iss := open[id]
+		slack := math.Max(0, lateFinish[id]-earlyFinish[id])
+		item := TimelineItem{
+			ID:        id,
+			Title:     iss.Title,
+			Status:    string(iss.Status),
+			Priority:  iss.Priority,
+			Track:     track[id],
+			Start:     now.Add(durationDays(earlyStart[id])),
+			End:       now.Add(durationDays(earlyFinish[id])),
+			Days:      days[id],
+			SlackDays: slack,
+			Critical:  slack < criticalSlackDays,
+			DueDate:   iss.DueDate,
+			BlockedBy: blockers[id],
+		}
+		if item.DueDate != nil && item.End.After(*item.DueDate) {
+			item.MissesDue = true
+			tl.MissedDue++
+		}
+		tl.Items = append(tl.Items, item)
+	}
+
+	sort.SliceStable(tl.Items, func(i, j int) bool {
+		a, b := tl.Items[i], tl.Items[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if a.Critical != b.Critical {
+			return a.Critical
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	})
+	for _, it := range tl.Items {
+		if it.Critical {
+			tl.CriticalPath = append(tl.CriticalPath, it.ID)
+		}
+	}
+	return tl
+}
+
+// timelineOrder topologically sorts ids so blockers come first. Issues
+// caught in a cycle are appended in ID order and reported.
+func timelineOrder(ids []string, blockers, dependents map[string][]string) ([]string, bool) {
+	indegree := make(map[string]int, len(ids))
+	var ready []string
+	for _, id := range ids {
+		indegree[id] = len(blockers[id])
+		if indegree[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]string, 0, len(ids))
+	done := make(map[string]bool, len(ids))
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		done[id] = true
+		for _, d := range dependents[id] {
+			indegree[d]--
+			if indegree[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) == len(ids) {
+		return order, false
+	}
+	for _, id := range ids {
+		if !done[id] {
+			order = append(order, id)
+		}
+	}
+	return order, true
+}
@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	due := now.Add(7 * day)
+	// Closed outside the 30-day velocity window, so the default velocity
+	// (median 480 / 5 per day) applies: 480 minutes is 5 days.
+	longAgo := now.Add(-90 * day)
+	fullDay, halfDay, hour := 480, 240, 60
+
+	type wantItem struct {
+		start, days, slack  float64 // in days; start is relative to now
+		critical, missesDue bool
+		blockedBy           string
+	}
+	tests := []struct {
+		name         string
+		issues       []model.Issue
+		withPlan     bool
+		wantItems    map[string]wantItem
+		wantCritical string
+		wantEnd      float64 // days after now
+		wantMissed   int
+		wantCycles   bool
+		sameTrack    []string
+	}{
+		{
+			name: "dependency chain next to a short independent issue",
+			issues: []model.Issue{
+				{ID: "a", Title: "Schema", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &fullDay},
+				{ID: "b", Title: "API", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &fullDay,
+					Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+				{ID: "c", Title: "UI", Status: model.StatusBlocked, IssueType: model.TypeTask, EstimatedMinutes: &fullDay, DueDate: &due,
+					Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}}},
+				{ID: "d", Title: "Docs", Status: model.StatusOpen, IssueType: model.TypeTask, EstimatedMinutes: &halfDay},
+				{ID: "e", Title: "Done", Status: model.StatusClosed, IssueType: model.TypeTask, EstimatedMinutes: &hour, ClosedAt: &longAgo, UpdatedAt: longAgo},
+			},
+			withPlan: true,
+			wantItems: map[string]wantItem{
+				"a": {start: 0, days: 5, critical: true},
+				"b": {start: 5, days: 5, critical: true, blockedBy: "a"},
+				"c": {start: 10, days: 5, critical: true, missesDue: true, blockedBy: "b"},
+				"d": {start: 0, days: 2.5, slack: 12.5},
+			},
+			wantCritical: "a,b,c",
+			wantEnd:      15,
+			wantMissed:   1,
+			sameTrack:    []string{"a", "b", "c"},
+		},
+		{
+			name: "cycle is broken",
+			issues: []model.Issue{
+				{ID: "x", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks}}},
+				{ID: "y", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+			},
+			wantItems:  map[string]wantItem{"x": {}, "y": {}},
+			wantCycles: true,
+		},
+		{
+			name: "no issues",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plan ExecutionPlan
+			if tt.withPlan {
+				plan = NewAnalyzer(tt.issues).GetExecutionPlan()
+			}
+			tl := BuildTimeline(tt.issues, nil, plan, TimelineOptions{Now: now})
+
+			if len(tl.Items) != len(tt.wantItems) || tl.Cycles != tt.wantCycles {
+				t.Fatalf("items = %d, cycles %v; want %d, %v", len(tl.Items), tl.Cycles, len(tt.wantItems), tt.wantCycles)
+			}
+			if !tt.wantCycles {
+				if !tl.End.Equal(now.Add(durationDays(tt.wantEnd))) {
+					t.Errorf("timeline end = %v, want %v days", tl.End, tt.wantEnd)
+				}
+				if got := strings.Join(tl.CriticalPath, ","); got != tt.wantCritical {
+					t.Errorf("critical path = %s, want %s", got, tt.wantCritical)
+				}
+			}
+			if tl.MissedDue != tt.wantMissed {
+				t.Errorf("missed due = %d, want %d", tl.MissedDue, tt.wantMissed)
+			}
+
+			byID := make(map[string]TimelineItem)
+			for _, it := range tl.Items {
+				byID[it.ID] = it
+				if it.End.Before(it.Start) || it.SlackDays < 0 {
+					t.Errorf("%s: start %v end %v slack %v", it.ID, it.Start, it.End, it.SlackDays)
+				}
+				if tt.wantCycles {
+					continue
+				}
+				want := tt.wantItems[it.ID]
+				if !it.Start.Equal(now.Add(durationDays(want.start))) || math.Abs(it.Days-want.days) > 1e-9 {
+					t.Errorf("%s: start %v, %v days; want +%v, %v days", it.ID, it.Start, it.Days, want.start, want.days)
+				}
+				if math.Abs(it.SlackDays-want.slack) > 1e-6 || it.Critical != want.critical || it.MissesDue != want.missesDue {
+					t.Errorf("%s: slack %v, critical %v, misses due %v", it.ID, it.SlackDays, it.Critical, it.MissesDue)
+				}
+				if got := strings.Join(it.BlockedBy, ","); got != want.blockedBy {
+					t.Errorf("%s: blocked by %q, want %q", it.ID, got, want.blockedBy)
+				}
+				if want.slack > 0 && !it.SlackEnd().Equal(tl.End) {
+					t.Errorf("%s: slack should reach the end: %v vs %v", it.ID, it.SlackEnd(), tl.End)
+				}
+			}
+			for _, id := range tt.sameTrack {
+				if track := byID[id].Track; track == "" || track != byID[tt.sameTrack[0]].Track {
+					t.Errorf("%v should share a track, %s is on %q", tt.sameTrack, id, track)
+				}
+			}
+			if len(tl.CriticalPath) > 0 && tl.Items[0].ID != tl.CriticalPath[0] {
+				t.Errorf("critical items should sort first on ties, got %s", tl.Items[0].ID)
+			}
+		})
+	}
+}
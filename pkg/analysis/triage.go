@@ -40,6 +40,7 @@ type TriageMeta struct {
 	Phase2Ready   bool      `json:"phase2_ready"`
 	IssueCount    int       `json:"issue_count"`
 	ComputeTimeMs int64     `json:"compute_time_ms"`
+	Profile       string    `json:"profile"` // Scoring profile used for the scores
 }
 
 // QuickRef provides at-a-glance summary for fast decisions
@@ -303,6 +304,9 @@ type TriageOptions struct {
 
 	// History report for staleness analysis
 	History *correlation.HistoryReport
+
+	// Profile overrides the analyzer's scoring profile when set
+	Profile *ScoringProfile
 }
 
 // TrackRecommendationGroup groups recommendations by execution track (bv-87)
@@ -333,6 +337,7 @@ func ComputeTriageWithOptions(issues []model.Issue, opts TriageOptions) TriageRe
 func ComputeTriageWithOptionsAndTime(issues []model.Issue, opts TriageOptions, now time.Time) TriageResult {
 	// Build analyzer and stats
 	analyzer := NewAnalyzer(issues)
+	analyzer.SetScoringProfile(opts.Profile)
 
 	// bv-perf: Check if there are any open issues before computing Phase 2
 	// Phase 2 metrics (PageRank, Betweenness) are only used for scoring open issues.
@@ -399,8 +404,13 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 	// This caches actionable issues, blocker depths, etc. across all sub-functions
 	triageCtx := NewTriageContext(analyzer)
 
+	profile := opts.Profile
+	if profile == nil {
+		profile = analyzer.ScoringProfile()
+	}
+
 	// Compute impact scores using the already-computed stats
-	impactScores := analyzer.ComputeImpactScoresFromStats(stats, now)
+	impactScores := analyzer.computeImpactScores(stats, now, profile)
 
 	// Build unblocks map
 	unblocksMap := buildUnblocksMap(analyzer)
@@ -409,7 +419,7 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 	counts := computeCountsWithContext(issues, triageCtx)
 
 	// Compute enhanced triage scores (bv-147)
-	triageScores := computeTriageScoresFromImpact(impactScores, unblocksMap, analyzer, DefaultTriageScoringOptions())
+	triageScores := computeTriageScoresFromImpact(impactScores, unblocksMap, analyzer, profile.TriageScoringOptions())
 
 	// Build recommendations using enhanced scores (bv-148)
 	// Pass triageCtx instead of analyzer for cached blocker lookups (bv-k4az)
@@ -456,6 +466,7 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 			Phase2Ready:   stats.IsPhase2Ready(),
 			IssueCount:    len(issues),
 			ComputeTimeMs: elapsed.Milliseconds(),
+			Profile:       profile.Name,
 		},
 		QuickRef: QuickRef{
 			OpenCount:       counts.Open,
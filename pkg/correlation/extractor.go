@@ -186,7 +186,18 @@ func insertBefore(slice []string, marker, value string) []string {
 // parseGitLogOutput parses the combined commit info and diff output from a stream
 func (e *Extractor) parseGitLogOutput(r io.Reader, filterBeadID string) ([]BeadEvent, error) {
 	var events []BeadEvent
+	err := scanGitLog(r, func(info commitInfo, diff []byte) {
+		events = append(events, e.parseDiff(diff, info, filterBeadID)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
 
+// scanGitLog splits `git log -p --format=gitLogHeaderFormat` output into
+// commits and calls fn with each commit's metadata and non-empty diff.
+func scanGitLog(r io.Reader, fn func(info commitInfo, diff []byte)) error {
 	// Use bufio.Reader instead of Scanner to handle long lines
 	const maxScanTokenSize = 10 * 1024 * 1024 // 10MB
 	reader := bufio.NewReaderSize(r, maxScanTokenSize)
@@ -199,10 +210,8 @@ func (e *Extractor) parseGitLogOutput(r io.Reader, filterBeadID string) ([]BeadE
 		if currentCommit == nil {
 			return
 		}
-		diffBytes := diffBuffer.Bytes()
-		if len(diffBytes) > 0 {
-			diffEvents := e.parseDiff(diffBytes, *currentCommit, filterBeadID)
-			events = append(events, diffEvents...)
+		if diffBuffer.Len() > 0 {
+			fn(*currentCommit, diffBuffer.Bytes())
 		}
 		diffBuffer.Reset()
 	}
@@ -214,7 +223,7 @@ func (e *Extractor) parseGitLogOutput(r io.Reader, filterBeadID string) ([]BeadE
 			if err == io.EOF {
 				break
 			}
-			return nil, err
+			return err
 		}
 
 		if isPrefix {
@@ -223,7 +232,7 @@ func (e *Extractor) parseGitLogOutput(r io.Reader, filterBeadID string) ([]BeadE
 			for isPrefix {
 				_, isPrefix, err = reader.ReadLine()
 				if err != nil && err != io.EOF {
-					return nil, err
+					return err
 				}
 				if err == io.EOF {
 					break
@@ -263,7 +272,7 @@ func (e *Extractor) parseGitLogOutput(r io.Reader, filterBeadID string) ([]BeadE
 	// Process final commit
 	processCommit()
 
-	return events, nil
+	return nil
 }
 
 // commitPattern matches the start of a commit in our custom log format
@@ -0,0 +1,421 @@
+package correlation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// TimelineKind says what a commit did to an issue.
+type TimelineKind string
+
+const (
+	TimelineCreated TimelineKind = "created"
+	TimelineUpdated TimelineKind = "updated"
+	TimelineDeleted TimelineKind = "deleted"
+)
+
+// FieldChange is one field of an issue changing in one commit. Scalar fields
+// use From/To; labels and dependencies list their members in Added/Removed
+// (dependencies as "type:id").
+type FieldChange struct {
+	Field   string   `json:"field"`
+	From    any      `json:"from,omitempty"`
+	To      any      `json:"to,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// TimelineEvent is everything one commit changed on one issue.
+type TimelineEvent struct {
+	IssueID     string        `json:"issue_id"`
+	Kind        TimelineKind  `json:"kind"`
+	CommitSHA   string        `json:"commit_sha"`
+	Timestamp   time.Time     `json:"timestamp"`
+	Author      string        `json:"author"`
+	AuthorEmail string        `json:"author_email"`
+	CommitMsg   string        `json:"commit_message"`
+	Changes     []FieldChange `json:"changes,omitempty"`
+}
+
+// Timeline is the field-level history of every issue in a beads file, in
+// commit order.
+type Timeline struct {
+	Head   string          `json:"head"`
+	File   string          `json:"file"`
+	Events []TimelineEvent `json:"events"`
+}
+
+// IssueTimeline is the history of a single issue.
+type IssueTimeline struct {
+	IssueID string          `json:"issue_id"`
+	Events  []TimelineEvent `json:"events"`
+	// TimeInStatusHours is how long the issue spent in each status, measured
+	// between commits; the current status runs until now.
+	TimeInStatusHours map[string]float64 `json:"time_in_status_hours,omitempty"`
+}
+
+// ForIssue returns the events of one issue.
+func (t *Timeline) ForIssue(id string) IssueTimeline {
+	out := IssueTimeline{IssueID: id, Events: []TimelineEvent{}}
+	for _, ev := range t.Events {
+		if ev.IssueID == id {
+			out.Events = append(out.Events, ev)
+		}
+	}
+	out.TimeInStatusHours = timeInStatus(out.Events, time.Now())
+	return out
+}
+
+func timeInStatus(events []TimelineEvent, now time.Time) map[string]float64 {
+	hours := make(map[string]float64)
+	status := ""
+	var since time.Time
+	flush := func(until time.Time) {
+		if status == "" {
+			return
+		}
+		// Statuses left within the same second still show up, with zero hours.
+		elapsed := 0.0
+		if until.After(since) {
+			elapsed = until.Sub(since).Hours()
+		}
+		hours[status] += elapsed
+	}
+	for _, ev := range events {
+		if ev.Kind == TimelineDeleted {
+			flush(ev.Timestamp)
+			status = ""
+			continue
+		}
+		for _, c := range ev.Changes {
+			if c.Field != "status" {
+				continue
+			}
+			flush(ev.Timestamp)
+			status, _ = c.To.(string)
+			since = ev.Timestamp
+		}
+	}
+	flush(now)
+	if len(hours) == 0 {
+		return nil
+	}
+	for k, v := range hours {
+		hours[k] = math.Round(v*100) / 100
+	}
+	return hours
+}
+
+// timelineSnapshot holds the fields the timeline tracks, decoded from one
+// JSONL line.
+type timelineSnapshot struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`
+	Priority     int      `json:"priority"`
+	IssueType    string   `json:"issue_type"`
+	Assignee     string   `json:"assignee"`
+	Labels       []string `json:"labels"`
+	Dependencies []struct {
+		DependsOnID string `json:"depends_on_id"`
+		Type        string `json:"type"`
+	} `json:"dependencies"`
+	Description        string `json:"description"`
+	Design             string `json:"design"`
+	AcceptanceCriteria string `json:"acceptance_criteria"`
+	Notes              string `json:"notes"`
+}
+
+func (s timelineSnapshot) dependencyKeys() []string {
+	keys := make([]string, 0, len(s.Dependencies))
+	for _, d := range s.Dependencies {
+		if d.DependsOnID != "" {
+			keys = append(keys, d.Type+":"+d.DependsOnID)
+		}
+	}
+	return keys
+}
+
+// diffSnapshots lists the tracked fields that differ between old and new.
+// A zero old snapshot yields the initial values of a created issue.
+func diffSnapshots(old, new timelineSnapshot) []FieldChange {
+	var changes []FieldChange
+	scalar := func(field string, from, to any, zero any) {
+		if reflect.DeepEqual(from, to) {
+			return
+		}
+		c := FieldChange{Field: field}
+		if from != zero {
+			c.From = from
+		}
+		if to != zero {
+			c.To = to
+		}
+		changes = append(changes, c)
+	}
+	set := func(field string, from, to []string) {
+		added, removed := setDiff(from, to)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, FieldChange{Field: field, Added: added, Removed: removed})
+		}
+	}
+
+	scalar("title", old.Title, new.Title, "")
+	scalar("status", old.Status, new.Status, "")
+	if old.ID == "" || old.Priority != new.Priority {
+		changes = append(changes, FieldChange{Field: "priority", From: priorityValue(old), To: new.Priority})
+	}
+	scalar("issue_type", old.IssueType, new.IssueType, "")
+	scalar("assignee", old.Assignee, new.Assignee, "")
+	set("labels", old.Labels, new.Labels)
+	set("dependencies", old.dependencyKeys(), new.dependencyKeys())
+	scalar("description", old.Description, new.Description, "")
+	scalar("design", old.Design, new.Design, "")
+	scalar("acceptance_criteria", old.AcceptanceCriteria, new.AcceptanceCriteria, "")
+	scalar("notes", old.Notes, new.Notes, "")
+	return changes
+}
+
+func priorityValue(s timelineSnapshot) any {
+	if s.ID == "" {
+		return nil
+	}
+	return s.Priority
+}
+
+// setDiff returns the members of to missing from from, and vice versa, in
+// list order.
+func setDiff(from, to []string) (added, removed []string) {
+	in := func(list []string) map[string]bool {
+		m := make(map[string]bool, len(list))
+		for _, v := range list {
+			m[v] = true
+		}
+		return m
+	}
+	f, t := in(from), in(to)
+	for _, v := range to {
+		if !f[v] {
+			added = append(added, v)
+			f[v] = true
+		}
+	}
+	for _, v := range from {
+		if !t[v] {
+			removed = append(removed, v)
+			t[v] = true
+		}
+	}
+	return added, removed
+}
+
+// timelineEventsFromDiff turns one commit's diff of the beads file into
+// events, one per issue whose tracked fields changed.
+func timelineEventsFromDiff(diff []byte, info commitInfo) []TimelineEvent {
+	oldSnaps := make(map[string]timelineSnapshot)
+	newSnaps := make(map[string]timelineSnapshot)
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) < 2 || line[1] != '{' || (line[0] != '-' && line[0] != '+') {
+			continue
+		}
+		var snap timelineSnapshot
+		if err := json.Unmarshal(line[1:], &snap); err != nil || snap.ID == "" {
+			continue
+		}
+		if line[0] == '-' {
+			oldSnaps[snap.ID] = snap
+		} else {
+			newSnaps[snap.ID] = snap
+		}
+	}
+
+	ids := make([]string, 0, len(oldSnaps)+len(newSnaps))
+	for id := range newSnaps {
+		ids = append(ids, id)
+	}
+	for id := range oldSnaps {
+		if _, ok := newSnaps[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var events []TimelineEvent
+	for _, id := range ids {
+		oldSnap, hadOld := oldSnaps[id]
+		newSnap, hasNew := newSnaps[id]
+		ev := TimelineEvent{
+			IssueID:     id,
+			CommitSHA:   info.SHA,
+			Timestamp:   info.Timestamp,
+			Author:      info.Author,
+			AuthorEmail: info.AuthorEmail,
+			CommitMsg:   info.Message,
+		}
+		switch {
+		case !hasNew:
+			ev.Kind = TimelineDeleted
+		case !hadOld:
+			ev.Kind = TimelineCreated
+			ev.Changes = diffSnapshots(timelineSnapshot{}, newSnap)
+		default:
+			ev.Kind = TimelineUpdated
+			ev.Changes = diffSnapshots(oldSnap, newSnap)
+			if len(ev.Changes) == 0 {
+				continue // only untracked fields (or formatting) changed
+			}
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// timelineCacheVersion is bumped whenever the cached event format changes.
+const timelineCacheVersion = 1
+
+type timelineCache struct {
+	Version int `json:"version"`
+	Timeline
+}
+
+// TimelineBuilder reconstructs a Timeline from git history. Results are
+// cached in .bv/timeline.json; later builds only read the commits added
+// since the cached HEAD, and rebuild from scratch when history was
+// rewritten.
+type TimelineBuilder struct {
+	repoPath  string
+	file      string
+	cachePath string
+}
+
+// NewTimelineBuilder creates a builder for the repository at repoPath.
+// beadsFilePath is optional, as for NewExtractor.
+func NewTimelineBuilder(repoPath string, beadsFilePath ...string) *TimelineBuilder {
+	return &TimelineBuilder{
+		repoPath:  repoPath,
+		file:      NewExtractor(repoPath, beadsFilePath...).primaryBeadsFile(),
+		cachePath: filepath.Join(repoPath, ".bv", "timeline.json"),
+	}
+}
+
+// Build returns the timeline up to HEAD.
+func (b *TimelineBuilder) Build() (*Timeline, error) {
+	head, err := getGitHead(b.repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+
+	tl := &Timeline{File: b.file}
+	revRange := ""
+	if cached := b.loadCache(); cached != nil && cached.File == b.file {
+		switch {
+		case cached.Head == head:
+			return &cached.Timeline, nil
+		case b.isAncestor(cached.Head, head):
+			tl.Events = cached.Events
+			revRange = cached.Head + ".." + head
+		}
+	}
+
+	events, err := b.scan(revRange)
+	if err != nil {
+		return nil, err
+	}
+	tl.Events = append(tl.Events, events...)
+	tl.Head = head
+	b.saveCache(tl)
+	return tl, nil
+}
+
+// scan reads the commits in revRange (all history when empty) that touched
+// the beads file and returns their events oldest first.
+func (b *TimelineBuilder) scan(revRange string) ([]TimelineEvent, error) {
+	args := []string{"-c", "color.ui=false", "log", "-p", "-U0", "--no-ext-diff", "--follow", "--format=" + gitLogHeaderFormat}
+	if revRange != "" {
+		args = append(args, revRange)
+	}
+	args = append(args, "--", b.file)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = b.repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git log failed: %s", bytes.TrimSpace(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	// git log lists newest first; keep each commit's events together while
+	// reversing the commit order.
+	var commits [][]TimelineEvent
+	if err := scanGitLog(bytes.NewReader(out), func(info commitInfo, diff []byte) {
+		if events := timelineEventsFromDiff(diff, info); len(events) > 0 {
+			commits = append(commits, events)
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("parsing git log output: %w", err)
+	}
+	var events []TimelineEvent
+	for i := len(commits) - 1; i >= 0; i-- {
+		events = append(events, commits[i]...)
+	}
+	return events, nil
+}
+
+func (b *TimelineBuilder) isAncestor(ancestor, head string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, head)
+	cmd.Dir = b.repoPath
+	return cmd.Run() == nil
+}
+
+func (b *TimelineBuilder) loadCache() *timelineCache {
+	data, err := os.ReadFile(b.cachePath)
+	if err != nil {
+		return nil
+	}
+	var cached timelineCache
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Version != timelineCacheVersion {
+		return nil
+	}
+	return &cached
+}
+
+// saveCache writes the cache atomically. Failures are ignored: the cache
+// only saves time.
+func (b *TimelineBuilder) saveCache(tl *Timeline) {
+	data, err := json.Marshal(timelineCache{Version: timelineCacheVersion, Timeline: *tl})
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(b.cachePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, ".timeline-*.json")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), b.cachePath)
+}
@@ -0,0 +1,168 @@
+package correlation
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimelineEventsFromDiff(t *testing.T) {
+	info := commitInfo{SHA: "abc", Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Author: "Ann", AuthorEmail: "ann@example.com", Message: "work"}
+	diff := []byte(strings.Join([]string{
+		`diff --git a/.beads/issues.jsonl b/.beads/issues.jsonl`,
+		`--- a/.beads/issues.jsonl`,
+		`+++ b/.beads/issues.jsonl`,
+		`@@ -1,3 +1,3 @@`,
+		`-{"id":"bv-1","title":"One","status":"open","priority":2,"labels":["api"],"dependencies":[{"depends_on_id":"bv-9","type":"blocks"}]}`,
+		`+{"id":"bv-1","title":"One","status":"in_progress","priority":1,"assignee":"ann","labels":["api","ui"],"dependencies":[]}`,
+		`-{"id":"bv-2","title":"Two","status":"open","updated_at":"2025-01-01T00:00:00Z"}`,
+		`+{"id":"bv-2","title":"Two","status":"open","updated_at":"2025-01-02T00:00:00Z"}`,
+		`-{"id":"bv-3","title":"Gone","status":"open"}`,
+		`+{"id":"bv-4","title":"New","status":"open","priority":0,"issue_type":"bug"}`,
+	}, "\n"))
+
+	events := timelineEventsFromDiff(diff, info)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events (bv-2 only touched untracked fields), got %+v", events)
+	}
+
+	one := events[0]
+	if one.IssueID != "bv-1" || one.Kind != TimelineUpdated || one.CommitSHA != "abc" || one.Author != "Ann" {
+		t.Fatalf("unexpected event: %+v", one)
+	}
+	got := map[string]FieldChange{}
+	for _, c := range one.Changes {
+		got[c.Field] = c
+	}
+	if c := got["status"]; c.From != "open" || c.To != "in_progress" {
+		t.Errorf("status change = %+v", c)
+	}
+	if c := got["priority"]; c.From != 2 || c.To != 1 {
+		t.Errorf("priority change = %+v", c)
+	}
+	if c := got["assignee"]; c.From != nil || c.To != "ann" {
+		t.Errorf("assignee change = %+v", c)
+	}
+	if c := got["labels"]; len(c.Added) != 1 || c.Added[0] != "ui" || len(c.Removed) != 0 {
+		t.Errorf("labels change = %+v", c)
+	}
+	if c := got["dependencies"]; len(c.Removed) != 1 || c.Removed[0] != "blocks:bv-9" {
+		t.Errorf("dependencies change = %+v", c)
+	}
+	if _, ok := got["title"]; ok {
+		t.Error("unchanged title should not be reported")
+	}
+
+	if events[1].IssueID != "bv-3" || events[1].Kind != TimelineDeleted {
+		t.Errorf("expected bv-3 deletion, got %+v", events[1])
+	}
+	created := events[2]
+	if created.IssueID != "bv-4" || created.Kind != TimelineCreated {
+		t.Fatalf("expected bv-4 creation, got %+v", created)
+	}
+	for _, c := range created.Changes {
+		if c.Field == "priority" && (c.From != nil || c.To != 0) {
+			t.Errorf("created priority = %+v", c)
+		}
+	}
+}
+
+func TestTimeInStatus(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := func(at time.Time, to string) TimelineEvent {
+		return TimelineEvent{Kind: TimelineUpdated, Timestamp: at, Changes: []FieldChange{{Field: "status", To: to}}}
+	}
+	events := []TimelineEvent{
+		status(t0, "open"),
+		status(t0.Add(2*time.Hour), "in_progress"),
+		status(t0.Add(5*time.Hour), "closed"),
+	}
+	hours := timeInStatus(events, t0.Add(6*time.Hour))
+	if hours["open"] != 2 || hours["in_progress"] != 3 || hours["closed"] != 1 {
+		t.Errorf("unexpected durations: %v", hours)
+	}
+}
+
+func TestTimelineBuilder_IncrementalCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	jsonlPath := filepath.Join(dir, ".beads", "issues.jsonl")
+	if err := os.MkdirAll(filepath.Dir(jsonlPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ann", "GIT_AUTHOR_EMAIL=ann@example.com",
+			"GIT_COMMITTER_NAME=Ann", "GIT_COMMITTER_EMAIL=ann@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(msg string, lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(jsonlPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".beads")
+		git("commit", "-q", "-m", msg)
+	}
+
+	git("init", "-q")
+	commit("create", `{"id":"bv-1","title":"One","status":"open","priority":2}`)
+	commit("start", `{"id":"bv-1","title":"One","status":"in_progress","priority":2}`)
+
+	tl, err := NewTimelineBuilder(dir).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tl.Events) != 2 || tl.Events[0].Kind != TimelineCreated || tl.Events[1].CommitMsg != "start" {
+		t.Fatalf("unexpected timeline: %+v", tl.Events)
+	}
+
+	// Tag the cache so we can tell whether the next build reused it.
+	cachePath := filepath.Join(dir, ".bv", "timeline.json")
+	var cached timelineCache
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("cache not written: %v", err)
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatal(err)
+	}
+	cached.Events[0].Author = "cached"
+	data, _ = json.Marshal(cached)
+	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	commit("close",
+		`{"id":"bv-1","title":"One","status":"closed","priority":2}`,
+		`{"id":"bv-2","title":"Two","status":"open","priority":1}`,
+	)
+	tl, err = NewTimelineBuilder(dir).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tl.Events[0].Author != "cached" {
+		t.Error("events before the cached head should come from the cache")
+	}
+	one := tl.ForIssue("bv-1")
+	if len(one.Events) != 3 || one.Events[2].Changes[0].To != "closed" {
+		t.Fatalf("bv-1 timeline: %+v", one.Events)
+	}
+	if _, ok := one.TimeInStatusHours["in_progress"]; !ok {
+		t.Errorf("time in status missing in_progress: %v", one.TimeInStatusHours)
+	}
+	if two := tl.ForIssue("bv-2"); len(two.Events) != 1 || two.Events[0].Author != "Ann" {
+		t.Errorf("bv-2 timeline: %+v", two.Events)
+	}
+}
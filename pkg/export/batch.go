@@ -0,0 +1,182 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// BatchScriptOp is the bd operation a batch script applies to each issue.
+type BatchScriptOp string
+
+const (
+	BatchScriptClaim BatchScriptOp = "claim"
+	BatchScriptClose BatchScriptOp = "close"
+)
+
+// OrderByDependencies returns issues with every issue placed after the
+// blockers that are also in the slice. Otherwise the input order is kept,
+// and cycles are broken in input order.
+func OrderByDependencies(issues []model.Issue) []model.Issue {
+	index := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		index[issue.ID] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(issues))
+	ordered := make([]model.Issue, 0, len(issues))
+
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != unvisited {
+			return
+		}
+		state[i] = visiting
+		for _, dep := range issues[i].Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if j, ok := index[dep.DependsOnID]; ok {
+				visit(j)
+			}
+		}
+		state[i] = done
+		ordered = append(ordered, issues[i])
+	}
+	for i := range issues {
+		visit(i)
+	}
+	return ordered
+}
+
+// GenerateAgentPrompt writes a prompt handing the issues to a coding agent,
+// in dependency order with their descriptions, acceptance criteria and
+// blockers. all is used to describe blockers outside the batch and may be
+// nil.
+func GenerateAgentPrompt(issues []model.Issue, all []model.Issue) string {
+	ordered := OrderByDependencies(issues)
+	inBatch := make(map[string]bool, len(ordered))
+	for _, issue := range ordered {
+		inBatch[issue.ID] = true
+	}
+	lookup := make(map[string]*model.Issue, len(all))
+	for i := range all {
+		lookup[all[i].ID] = &all[i]
+	}
+
+	var sb strings.Builder
+	noun := "issues"
+	if len(ordered) == 1 {
+		noun = "issue"
+	}
+	sb.WriteString(fmt.Sprintf("# Task: work through %d %s\n\n", len(ordered), noun))
+	sb.WriteString("The issues below come from this project's beads tracker. They are in dependency order: ")
+	sb.WriteString("an issue's blockers are listed before it, so work from the top down.\n\n")
+	sb.WriteString("For each issue:\n")
+	sb.WriteString("1. Claim it with `bd update <id> --status=in_progress`.\n")
+	sb.WriteString("2. Implement it and check the acceptance criteria.\n")
+	sb.WriteString("3. Close it with `bd close <id>`.\n")
+
+	for n, issue := range ordered {
+		sb.WriteString(fmt.Sprintf("\n## %d. %s: %s\n\n", n+1, issue.ID, issue.Title))
+
+		facts := []string{
+			"Type: " + string(issue.IssueType),
+			fmt.Sprintf("Priority: P%d", issue.Priority),
+			"Status: " + string(issue.Status),
+		}
+		if len(issue.Labels) > 0 {
+			facts = append(facts, "Labels: "+strings.Join(issue.Labels, ", "))
+		}
+		sb.WriteString(strings.Join(facts, " · ") + "\n")
+
+		var blockers []string
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			switch blocker := lookup[dep.DependsOnID]; {
+			case inBatch[dep.DependsOnID]:
+				blockers = append(blockers, dep.DependsOnID+" (in this batch)")
+			case blocker != nil:
+				blockers = append(blockers, fmt.Sprintf("%s (outside this batch, %s)", dep.DependsOnID, blocker.Status))
+			default:
+				blockers = append(blockers, dep.DependsOnID+" (outside this batch)")
+			}
+		}
+		if len(blockers) > 0 {
+			sb.WriteString("Blocked by: " + strings.Join(blockers, ", ") + "\n")
+		}
+
+		if desc := strings.TrimSpace(issue.Description); desc != "" {
+			sb.WriteString("\n" + desc + "\n")
+		}
+		if ac := strings.TrimSpace(issue.AcceptanceCriteria); ac != "" {
+			sb.WriteString("\n### Acceptance criteria\n\n" + ac + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// GenerateBatchScript writes one bash script that applies op to every issue
+// with the bd CLI, in dependency order. Closed issues are listed but
+// skipped. The script honours $BD so it can drive another beads CLI.
+func GenerateBatchScript(issues []model.Issue, op BatchScriptOp) string {
+	ordered := OrderByDependencies(issues)
+
+	var sb strings.Builder
+	sb.WriteString("#!/usr/bin/env bash\n")
+	sb.WriteString(fmt.Sprintf("# Generated by bv: %s %d issue(s).\n", op, len(ordered)))
+	sb.WriteString("# Set BD to use another beads CLI, e.g. BD=br.\n")
+	sb.WriteString("set -euo pipefail\n\n")
+	sb.WriteString("BD=\"${BD:-bd}\"\n")
+
+	for _, issue := range ordered {
+		sb.WriteString(fmt.Sprintf("\n# %s: %s\n", issue.ID, scriptComment(issue.Title)))
+		if isClosedLikeStatus(issue.Status) {
+			sb.WriteString(fmt.Sprintf("# skipped: already %s\n", issue.Status))
+			continue
+		}
+		id := shellQuote(issue.ID)
+		switch op {
+		case BatchScriptClaim:
+			sb.WriteString(fmt.Sprintf("\"$BD\" update %s --status=in_progress\n", id))
+		case BatchScriptClose:
+			sb.WriteString(fmt.Sprintf("\"$BD\" close %s\n", id))
+		}
+	}
+	return sb.String()
+}
+
+// SaveJSONToFile writes issues as an indented JSON array.
+func SaveJSONToFile(issues []model.Issue, filename string) error {
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+var shellSafeRegex = regexp.MustCompile(`^[A-Za-z0-9._:/@%+=-]+$`)
+
+// shellQuote single-quotes s unless it is made only of shell-safe characters.
+func shellQuote(s string) string {
+	if shellSafeRegex.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// scriptComment flattens text onto one comment line.
+func scriptComment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,212 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestOrderByDependencies(t *testing.T) {
+	tests := []struct {
+		name   string
+		issues []model.Issue
+		want   string
+	}{
+		{
+			name: "blockers first, related links ignored",
+			issues: []model.Issue{
+				{ID: "c", Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}}},
+				{ID: "a"},
+				{ID: "b", Dependencies: []*model.Dependency{
+					{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks},
+					{IssueID: "b", DependsOnID: "c", Type: model.DepRelated},
+				}},
+			},
+			want: "a,b,c",
+		},
+		{
+			name: "blockers outside the batch are ignored",
+			issues: []model.Issue{
+				{ID: "b", Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "x", Type: model.DepBlocks}}},
+				{ID: "a"},
+			},
+			want: "b,a",
+		},
+		{
+			name: "a cycle still yields every issue once",
+			issues: []model.Issue{
+				{ID: "p", Dependencies: []*model.Dependency{{DependsOnID: "q", Type: model.DepBlocks}}},
+				{ID: "q", Dependencies: []*model.Dependency{{DependsOnID: "p", Type: model.DepBlocks}}},
+			},
+			want: "q,p",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, issue := range OrderByDependencies(tt.issues) {
+				ids = append(ids, issue.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateAgentPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		batch   []model.Issue
+		others  []model.Issue // loaded issues outside the batch
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "issues numbered in dependency order with details",
+			batch: []model.Issue{
+				{ID: "b", Title: "Auth\nmiddleware", Status: model.StatusOpen, IssueType: model.TypeFeature,
+					Description: "Add JWT checks.", AcceptanceCriteria: "Rejects expired tokens.",
+					Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+				{ID: "a", Title: "Pick a schema", Status: model.StatusClosed, IssueType: model.TypeTask},
+			},
+			want: []string{
+				"work through 2 issues",
+				"## 1. a: Pick a schema",
+				"## 2. b: Auth\nmiddleware",
+				"Add JWT checks.",
+				"### Acceptance criteria\n\nRejects expired tokens.",
+				"bd close <id>",
+			},
+		},
+		{
+			name: "blockers inside and outside the batch",
+			batch: []model.Issue{
+				{ID: "c", Title: "Wire up endpoint", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+					{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks},
+					{IssueID: "c", DependsOnID: "x", Type: model.DepBlocks},
+				}},
+				{ID: "b", Title: "Auth middleware", Status: model.StatusOpen},
+			},
+			others: []model.Issue{{ID: "x", Title: "Outside", Status: model.StatusInProgress}},
+			want:   []string{"Blocked by: b (in this batch), x (outside this batch, in_progress)"},
+		},
+		{
+			name: "related links are not blockers",
+			batch: []model.Issue{
+				{ID: "b", Title: "Auth middleware", Status: model.StatusOpen,
+					Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "c", Type: model.DepRelated}}},
+				{ID: "c", Title: "Wire up endpoint", Status: model.StatusOpen},
+			},
+			want:    []string{"work through 2 issues"},
+			notWant: []string{"Blocked by"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := GenerateAgentPrompt(tt.batch, append(append([]model.Issue{}, tt.batch...), tt.others...))
+			for _, want := range tt.want {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt missing %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(prompt, notWant) {
+					t.Errorf("prompt should not contain %q:\n%s", notWant, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBatchScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		issues  []model.Issue
+		op      BatchScriptOp
+		want    []string // in order
+		notWant []string
+	}{
+		{
+			name: "claim blockers first and skip closed issues",
+			issues: []model.Issue{
+				{ID: "c", Title: "Wire up endpoint", Status: model.StatusOpen,
+					Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}}},
+				{ID: "a", Title: "Pick a schema", Status: model.StatusClosed},
+				{ID: "b", Title: "Auth\nmiddleware", Status: model.StatusOpen,
+					Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+			},
+			op: BatchScriptClaim,
+			want: []string{
+				"#!/usr/bin/env bash",
+				`BD="${BD:-bd}"`,
+				"# a: Pick a schema\n# skipped: already closed\n",
+				"# b: Auth middleware\n\"$BD\" update b --status=in_progress\n",
+				"\"$BD\" update c --status=in_progress\n",
+			},
+		},
+		{
+			name:   "ids are shell-quoted",
+			issues: []model.Issue{{ID: "it's", Title: "Odd id", Status: model.StatusOpen}},
+			op:     BatchScriptClaim,
+			want:   []string{`"$BD" update 'it'\''s' --status=in_progress`},
+		},
+		{
+			name: "close skips closed issues",
+			issues: []model.Issue{
+				{ID: "a", Title: "Pick a schema", Status: model.StatusClosed},
+				{ID: "c", Title: "Wire up endpoint", Status: model.StatusOpen},
+			},
+			op:      BatchScriptClose,
+			want:    []string{"\"$BD\" close c\n"},
+			notWant: []string{"close a\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := GenerateBatchScript(tt.issues, tt.op)
+			rest := script
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("script missing %q (or out of order):\n%s", want, script)
+				}
+				rest = rest[i+len(want):]
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(script, notWant) {
+					t.Errorf("script should not contain %q:\n%s", notWant, script)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveJSONToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selection.json")
+	issues := []model.Issue{
+		{ID: "a", Title: "Pick a schema", Status: model.StatusClosed},
+		{ID: "b", Title: "Auth\nmiddleware", Status: model.StatusOpen, Description: "Add JWT checks."},
+	}
+	if err := SaveJSONToFile(issues, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []model.Issue
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Title != "Auth\nmiddleware" || got[1].Description != "Add JWT checks." {
+		t.Errorf("round trip = %+v", got)
+	}
+}
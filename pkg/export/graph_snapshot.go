@@ -40,41 +40,53 @@ func SaveGraphSnapshot(opts GraphSnapshotOptions) error {
 		return fmt.Errorf("graph stats are required for snapshot export")
 	}
 
-	format := strings.ToLower(strings.TrimPrefix(opts.Format, "."))
+	format, path, err := prepareSnapshotPath(opts.Path, opts.Format)
+	if err != nil {
+		return err
+	}
+	opts.Path = path
+
+	layout := buildLayout(opts)
+
+	switch format {
+	case "svg":
+		return renderSVG(opts, layout)
+	case "png":
+		return renderPNG(opts, layout)
+	default:
+		return fmt.Errorf("unhandled format %q", format)
+	}
+}
+
+// prepareSnapshotPath resolves the output format, inferring it from the
+// path's extension when format is empty, and creates the parent directory.
+// A path without an extension gets ".svg".
+func prepareSnapshotPath(path, format string) (string, string, error) {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
 	if format == "" {
-		switch strings.ToLower(filepath.Ext(opts.Path)) {
+		switch strings.ToLower(filepath.Ext(path)) {
 		case ".svg":
 			format = "svg"
 		case ".png":
 			format = "png"
 		default:
 			format = "svg" // safe default
-			if opts.Path != "" && filepath.Ext(opts.Path) == "" {
-				opts.Path = opts.Path + ".svg"
+			if path != "" && filepath.Ext(path) == "" {
+				path = path + ".svg"
 			}
 		}
 	}
 	if format != "svg" && format != "png" {
-		return fmt.Errorf("unsupported format %q (want svg or png)", format)
+		return "", "", fmt.Errorf("unsupported format %q (want svg or png)", format)
 	}
-	if opts.Path == "" {
-		return fmt.Errorf("output path is required")
-	}
-
-	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
-		return fmt.Errorf("create parent dir: %w", err)
+	if path == "" {
+		return "", "", fmt.Errorf("output path is required")
 	}
 
-	layout := buildLayout(opts)
-
-	switch format {
-	case "svg":
-		return renderSVG(opts, layout)
-	case "png":
-		return renderPNG(opts, layout)
-	default:
-		return fmt.Errorf("unhandled format %q", format)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", "", fmt.Errorf("create parent dir: %w", err)
 	}
+	return format, path, nil
 }
 
 // --- layout computation ----------------------------------------------------
@@ -173,6 +173,10 @@ func GenerateMarkdown(issues []model.Issue, title string) (string, error) {
 			}
 			sb.WriteString(fmt.Sprintf("| **Labels** | %s |\n", strings.Join(escapedLabels, ", ")))
 		}
+		for _, name := range i.CustomFields.Names() {
+			value := model.FormatCustomValue(i.CustomFields[name])
+			sb.WriteString(fmt.Sprintf("| **%s** | %s |\n", markdownCellEscaper.Replace(name), markdownCellEscaper.Replace(value)))
+		}
 		sb.WriteString("\n")
 
 		if i.Description != "" {
@@ -231,6 +235,9 @@ func GenerateMarkdown(issues []model.Issue, title string) (string, error) {
 	return sb.String(), nil
 }
 
+// markdownCellEscaper keeps free-form text inside a single table cell.
+var markdownCellEscaper = strings.NewReplacer("|", "\\|", "\n", " ", "\r", "")
+
 func issueHeadingText(i model.Issue) string {
 	typeIcon := getTypeEmoji(string(i.IssueType))
 	return fmt.Sprintf("%s %s %s", typeIcon, i.ID, i.Title)
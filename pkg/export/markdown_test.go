@@ -1607,3 +1607,29 @@ func TestGenerateMarkdown_TombstoneIssueNoCommands(t *testing.T) {
 		t.Error("Tombstone issue should not have command snippets")
 	}
 }
+
+func TestGenerateMarkdown_CustomFields(t *testing.T) {
+	issues := []model.Issue{{
+		ID:        "cf-1",
+		Title:     "Custom",
+		Status:    model.StatusOpen,
+		IssueType: model.TypeTask,
+		CreatedAt: time.Now(),
+		CustomFields: model.CustomFields{
+			"story_points": float64(5),
+			"sla_tier":     "gold|platinum",
+		},
+	}}
+	md, err := GenerateMarkdown(issues, "Report")
+	if err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	for _, want := range []string{"| **sla_tier** | gold\\|platinum |", "| **story_points** | 5 |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+	if strings.Index(md, "sla_tier") > strings.Index(md, "story_points") {
+		t.Error("custom fields should be listed in name order")
+	}
+}
@@ -187,8 +187,8 @@ func (e *SQLiteExporter) insertIssues(db *sql.DB) error {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO issues (id, title, description, status, priority, issue_type, assignee, labels, created_at, updated_at, closed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO issues (id, title, description, status, priority, issue_type, assignee, labels, created_at, updated_at, closed_at, custom_fields)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -208,6 +208,16 @@ func (e *SQLiteExporter) insertIssues(db *sql.DB) error {
 			closedAt = &s
 		}
 
+		// Custom fields are stored as a JSON object so they can be queried
+		// with json_extract; NULL when the issue has none.
+		var customFields *string
+		if len(issue.CustomFields) > 0 {
+			if data, err := json.Marshal(issue.CustomFields); err == nil {
+				s := string(data)
+				customFields = &s
+			}
+		}
+
 		_, err := stmt.Exec(
 			issue.ID,
 			issue.Title,
@@ -220,6 +230,7 @@ func (e *SQLiteExporter) insertIssues(db *sql.DB) error {
 			issue.CreatedAt.Format(time.RFC3339),
 			issue.UpdatedAt.Format(time.RFC3339),
 			closedAt,
+			customFields,
 		)
 		if err != nil {
 			return fmt.Errorf("insert issue %s: %w", issue.ID, err)
@@ -523,3 +523,39 @@ func TestStringSliceContains(t *testing.T) {
 		}
 	}
 }
+
+func TestExport_WithCustomFields(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	issue := makeTestIssue("custom-1", "Custom Test", model.StatusOpen, 2, model.TypeTask)
+	issue.CustomFields = model.CustomFields{"story_points": float64(5), "sla_tier": "gold"}
+	plain := makeTestIssue("custom-2", "Plain", model.StatusOpen, 2, model.TypeTask)
+
+	exp := NewSQLiteExporter([]*model.Issue{issue, plain}, nil, nil, nil)
+	if err := exp.Export(tmpDir); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "beads.sqlite3"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	var tier string
+	var points float64
+	if err := db.QueryRow(`SELECT json_extract(custom_fields, '$.sla_tier'), json_extract(custom_fields, '$.story_points') FROM issues WHERE id = ?`, "custom-1").Scan(&tier, &points); err != nil {
+		t.Fatalf("Query custom_fields failed: %v", err)
+	}
+	if tier != "gold" || points != 5 {
+		t.Errorf("Unexpected custom fields: tier=%q points=%v", tier, points)
+	}
+
+	var none sql.NullString
+	if err := db.QueryRow(`SELECT custom_fields FROM issue_overview_mv WHERE id = ?`, "custom-2").Scan(&none); err != nil {
+		t.Fatalf("Query overview custom_fields failed: %v", err)
+	}
+	if none.Valid {
+		t.Errorf("Expected NULL custom_fields for issue without custom fields, got %q", none.String)
+	}
+}
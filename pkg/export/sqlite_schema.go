@@ -49,7 +49,8 @@ func createCoreTables(db *sql.DB) error {
 			labels TEXT,
 			created_at TEXT NOT NULL,
 			updated_at TEXT NOT NULL,
-			closed_at TEXT
+			closed_at TEXT,
+			custom_fields TEXT
 		)
 	`
 	if _, err := db.Exec(issuesSQL); err != nil {
@@ -224,6 +225,7 @@ func CreateMaterializedViews(db *sql.DB) error {
 			i.created_at,
 			i.updated_at,
 			i.closed_at,
+			i.custom_fields,
 			COALESCE(m.pagerank, 0) as pagerank,
 			COALESCE(m.betweenness, 0) as betweenness,
 			COALESCE(m.critical_path_depth, 0) as critical_path_depth,
@@ -0,0 +1,340 @@
+package export
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"git.sr.ht/~sbinet/gg"
+	"github.com/ajstarks/svgo"
+	"golang.org/x/image/font/basicfont"
+)
+
+// TimelineSnapshotOptions controls timeline snapshot export.
+type TimelineSnapshotOptions struct {
+	Path     string            // Output path; format inferred from extension when Format empty
+	Format   string            // "svg" or "png" (case-insensitive). If empty, inferred from Path.
+	Title    string            // Optional title rendered in summary block
+	Timeline analysis.Timeline // Schedule to draw
+	DataHash string            // Hash of input issues for provenance
+}
+
+// SaveTimelineSnapshot renders the timeline as a Gantt chart (SVG or PNG):
+// one row per open issue with its ETA bar, a slack bar, due date markers
+// and the critical path outlined. It shares the graph snapshot's palette,
+// header and legend layout.
+func SaveTimelineSnapshot(opts TimelineSnapshotOptions) error {
+	if len(opts.Timeline.Items) == 0 {
+		return fmt.Errorf("no open issues to export")
+	}
+	format, path, err := prepareSnapshotPath(opts.Path, opts.Format)
+	if err != nil {
+		return err
+	}
+
+	layout := buildTimelineLayout(opts)
+	if format == "png" {
+		return renderTimelinePNG(path, layout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return renderTimelineSVGToWriter(file, layout)
+}
+
+// --- layout computation ----------------------------------------------------
+
+type timelineBar struct {
+	ID, Label         string
+	Status            model.Status
+	Y                 float64
+	X1, X2, SlackX    float64
+	DueX              float64 // 0 when the issue has no due date
+	Critical, Missing bool
+}
+
+type timelineTick struct {
+	X     float64
+	Label string
+}
+
+type timelineLayout struct {
+	Bars         []timelineBar
+	Ticks        []timelineTick
+	Width        int
+	Height       int
+	Header       float64
+	ChartX       float64 // Left edge of the calendar (the timeline start)
+	ChartTop     float64
+	ChartBottom  float64
+	Title        string
+	DataHash     string
+	Summary      string
+	CriticalLine string
+}
+
+const (
+	timelineLabelW = 300.0
+	timelineRowH   = 26.0
+	timelineBarH   = 14.0
+	timelinePad    = 36.0
+	timelineHeader = 120.0
+	timelineAxisH  = 28.0
+)
+
+func buildTimelineLayout(opts TimelineSnapshotOptions) timelineLayout {
+	tl := opts.Timeline
+
+	// Scale so the whole span (including slack and due dates) fits ~1000px
+	end := tl.End
+	for _, it := range tl.Items {
+		if it.DueDate != nil && it.DueDate.After(end) {
+			end = *it.DueDate
+		}
+	}
+	spanDays := math.Max(end.Sub(tl.Start).Hours()/24, 1)
+	pxPerDay := math.Min(math.Max(1000/spanDays, 4), 60)
+
+	chartX := timelinePad + timelineLabelW
+	chartTop := timelinePad + timelineHeader + timelineAxisH
+	xOf := func(t time.Time) float64 {
+		return chartX + t.Sub(tl.Start).Hours()/24*pxPerDay
+	}
+
+	bars := make([]timelineBar, 0, len(tl.Items))
+	for i, it := range tl.Items {
+		bar := timelineBar{
+			ID:       it.ID,
+			Label:    truncate(it.Title, 32),
+			Status:   model.Status(it.Status),
+			Y:        chartTop + float64(i)*timelineRowH,
+			X1:       xOf(it.Start),
+			X2:       math.Max(xOf(it.End), xOf(it.Start)+2),
+			SlackX:   xOf(it.SlackEnd()),
+			Critical: it.Critical,
+			Missing:  it.MissesDue,
+		}
+		if it.DueDate != nil {
+			bar.DueX = math.Max(xOf(*it.DueDate), chartX)
+		}
+		bars = append(bars, bar)
+	}
+
+	// Weekly ticks, daily when the span is short
+	step := 7
+	if spanDays <= 14 {
+		step = 1
+	}
+	var ticks []timelineTick
+	for d := 0; float64(d) <= spanDays; d += step {
+		day := tl.Start.Add(time.Duration(d) * 24 * time.Hour)
+		ticks = append(ticks, timelineTick{X: xOf(day), Label: day.Format("Jan 02")})
+	}
+
+	width := int(chartX + spanDays*pxPerDay + timelinePad + 40)
+	if width < 640 {
+		width = 640
+	}
+	chartBottom := chartTop + float64(len(bars))*timelineRowH
+	height := int(chartBottom + timelinePad)
+	if height < 320 {
+		height = 320
+	}
+
+	title := opts.Title
+	if strings.TrimSpace(title) == "" {
+		title = "Timeline"
+	}
+	critical := strings.Join(tl.CriticalPath, " → ")
+	if critical == "" {
+		critical = "n/a"
+	}
+
+	return timelineLayout{
+		Bars:        bars,
+		Ticks:       ticks,
+		Width:       width,
+		Height:      height,
+		Header:      timelineHeader,
+		ChartX:      chartX,
+		ChartTop:    chartTop,
+		ChartBottom: chartBottom,
+		Title:       title,
+		DataHash:    opts.DataHash,
+		Summary: fmt.Sprintf("%s → %s  issues: %d  missed due dates: %d",
+			tl.Start.Format("2006-01-02"), tl.End.Format("2006-01-02"), len(tl.Items), tl.MissedDue),
+		CriticalLine: "critical path: " + truncate(critical, 90),
+	}
+}
+
+// --- rendering -------------------------------------------------------------
+
+var (
+	colorSlack    = color.RGBA{0xd6, 0xdb, 0xe4, 0xff}
+	colorCritical = color.RGBA{0xc6, 0x28, 0x28, 0xff}
+	colorDue      = color.RGBA{0x37, 0x47, 0x4f, 0xff}
+	colorGrid     = color.RGBA{0xe0, 0xe3, 0xe8, 0xff}
+)
+
+func renderTimelinePNG(path string, layout timelineLayout) error {
+	dc := gg.NewContext(layout.Width, layout.Height)
+	dc.SetColor(colorBackdrop)
+	dc.Clear()
+
+	dc.SetColor(colorHeaderBG)
+	dc.DrawRoundedRectangle(16, 16, float64(layout.Width)-32, layout.Header-24, 10)
+	dc.Fill()
+	dc.SetFontFace(basicfont.Face7x13)
+
+	dc.SetColor(colorText)
+	dc.DrawStringAnchored(layout.Title, 32, 44, 0, 0.5)
+	dc.SetColor(colorSubtle)
+	dc.DrawStringAnchored(fmt.Sprintf("data_hash: %s", layout.DataHash), 32, 64, 0, 0.5)
+	dc.DrawStringAnchored(layout.Summary, 32, 84, 0, 0.5)
+	dc.DrawStringAnchored(layout.CriticalLine, 32, 104, 0, 0.5)
+	drawTimelineLegend(dc, layout)
+
+	// Grid and axis
+	dc.SetLineWidth(1)
+	for _, tick := range layout.Ticks {
+		dc.SetColor(colorGrid)
+		dc.DrawLine(tick.X, layout.ChartTop-4, tick.X, layout.ChartBottom)
+		dc.Stroke()
+		dc.SetColor(colorSubtle)
+		dc.DrawStringAnchored(tick.Label, tick.X, layout.ChartTop-14, 0.5, 0.5)
+	}
+
+	for _, bar := range layout.Bars {
+		top := bar.Y + (timelineRowH-timelineBarH)/2
+		dc.SetColor(colorText)
+		dc.DrawStringAnchored(bar.ID, timelinePad, bar.Y+timelineRowH/2, 0, 0.5)
+		dc.SetColor(colorSubtle)
+		dc.DrawStringAnchored(bar.Label, timelinePad+70, bar.Y+timelineRowH/2, 0, 0.5)
+
+		if bar.SlackX > bar.X2 {
+			dc.SetColor(colorSlack)
+			dc.DrawRectangle(bar.X2, top+3, bar.SlackX-bar.X2, timelineBarH-6)
+			dc.Fill()
+		}
+		dc.SetColor(statusColor(bar.Status))
+		dc.DrawRoundedRectangle(bar.X1, top, bar.X2-bar.X1, timelineBarH, 3)
+		dc.Fill()
+		if bar.Critical {
+			dc.SetColor(colorCritical)
+			dc.SetLineWidth(2)
+		} else {
+			dc.SetColor(colorStroke)
+			dc.SetLineWidth(1)
+		}
+		dc.DrawRoundedRectangle(bar.X1, top, bar.X2-bar.X1, timelineBarH, 3)
+		dc.Stroke()
+
+		if bar.DueX > 0 {
+			dc.SetColor(colorDue)
+			if bar.Missing {
+				dc.SetColor(colorCritical)
+			}
+			dc.SetLineWidth(2)
+			dc.DrawLine(bar.DueX, bar.Y+2, bar.DueX, bar.Y+timelineRowH-2)
+			dc.Stroke()
+		}
+	}
+
+	// Today
+	dc.SetColor(colorEdge)
+	dc.SetLineWidth(1.5)
+	dc.DrawLine(layout.ChartX, layout.ChartTop-4, layout.ChartX, layout.ChartBottom)
+	dc.Stroke()
+
+	return dc.SavePNG(path)
+}
+
+func drawTimelineLegend(dc *gg.Context, layout timelineLayout) {
+	boxW := 200.0
+	boxH := 96.0
+	x := float64(layout.Width) - boxW - 20
+	y := 24.0
+	dc.SetColor(colorLegendBG)
+	dc.DrawRoundedRectangle(x, y, boxW, boxH, 10)
+	dc.Fill()
+	dc.SetColor(colorStroke)
+	dc.SetLineWidth(1)
+	dc.DrawRoundedRectangle(x, y, boxW, boxH, 10)
+	dc.Stroke()
+
+	dc.SetColor(colorText)
+	dc.DrawStringAnchored("Legend", x+12, y+18, 0, 0.5)
+	drawLegendRow(dc, x+12, y+36, colorOpen, "ETA (by status)")
+	drawLegendRow(dc, x+12, y+52, colorSlack, "Slack")
+	drawLegendRow(dc, x+12, y+68, colorCritical, "Critical path")
+	drawLegendRow(dc, x+12, y+84, colorDue, "Due date (red: missed)")
+}
+
+func renderTimelineSVGToWriter(w io.Writer, layout timelineLayout) error {
+	canvas := svg.New(w)
+	canvas.Start(layout.Width, layout.Height)
+	canvas.Rect(0, 0, layout.Width, layout.Height, fmt.Sprintf("fill:%s", css(colorBackdrop)))
+	canvas.Roundrect(16, 16, layout.Width-32, int(layout.Header-24), 10, 10, fmt.Sprintf("fill:%s", css(colorHeaderBG)))
+
+	subtle := fmt.Sprintf("fill:%s;font-size:13px;font-family:monospace", css(colorSubtle))
+	canvas.Text(32, 44, layout.Title, fmt.Sprintf("fill:%s;font-size:16px;font-family:monospace;font-weight:bold", css(colorText)))
+	canvas.Text(32, 64, fmt.Sprintf("data_hash: %s", layout.DataHash), subtle)
+	canvas.Text(32, 84, layout.Summary, subtle)
+	canvas.Text(32, 104, layout.CriticalLine, subtle)
+
+	boxW := 200
+	x := layout.Width - boxW - 20
+	canvas.Roundrect(x, 24, boxW, 96, 10, 10, fmt.Sprintf("fill:%s;stroke:%s;stroke-width:1", css(colorLegendBG), css(colorStroke)))
+	canvas.Text(x+12, 42, "Legend", fmt.Sprintf("fill:%s;font-size:13px;font-family:monospace;font-weight:bold", css(colorText)))
+	drawLegendRowSVG(canvas, x+12, 60, colorOpen, "ETA (by status)")
+	drawLegendRowSVG(canvas, x+12, 76, colorSlack, "Slack")
+	drawLegendRowSVG(canvas, x+12, 92, colorCritical, "Critical path")
+	drawLegendRowSVG(canvas, x+12, 108, colorDue, "Due date (red: missed)")
+
+	for _, tick := range layout.Ticks {
+		tx := int(tick.X)
+		canvas.Line(tx, int(layout.ChartTop)-4, tx, int(layout.ChartBottom), fmt.Sprintf("stroke:%s;stroke-width:1", css(colorGrid)))
+		canvas.Text(tx, int(layout.ChartTop)-10, tick.Label, fmt.Sprintf("fill:%s;font-size:11px;font-family:monospace;text-anchor:middle", css(colorSubtle)))
+	}
+
+	for _, bar := range layout.Bars {
+		y := int(bar.Y)
+		top := int(bar.Y + (timelineRowH-timelineBarH)/2)
+		mid := int(bar.Y + timelineRowH/2 + 4)
+		canvas.Text(int(timelinePad), mid, bar.ID, fmt.Sprintf("fill:%s;font-size:12px;font-family:monospace;font-weight:bold", css(colorText)))
+		canvas.Text(int(timelinePad)+70, mid, bar.Label, fmt.Sprintf("fill:%s;font-size:12px;font-family:monospace", css(colorSubtle)))
+
+		if bar.SlackX > bar.X2 {
+			canvas.Rect(int(bar.X2), top+3, int(bar.SlackX-bar.X2), int(timelineBarH)-6, fmt.Sprintf("fill:%s", css(colorSlack)))
+		}
+		stroke, strokeW := colorStroke, 1.0
+		if bar.Critical {
+			stroke, strokeW = colorCritical, 2.0
+		}
+		canvas.Roundrect(int(bar.X1), top, max(int(bar.X2-bar.X1), 2), int(timelineBarH), 3, 3,
+			fmt.Sprintf("fill:%s;stroke:%s;stroke-width:%.1f", css(statusColor(bar.Status)), css(stroke), strokeW))
+
+		if bar.DueX > 0 {
+			due := colorDue
+			if bar.Missing {
+				due = colorCritical
+			}
+			canvas.Line(int(bar.DueX), y+2, int(bar.DueX), y+int(timelineRowH)-2, fmt.Sprintf("stroke:%s;stroke-width:2", css(due)))
+		}
+	}
+
+	cx := int(layout.ChartX)
+	canvas.Line(cx, int(layout.ChartTop)-4, cx, int(layout.ChartBottom), fmt.Sprintf("stroke:%s;stroke-width:1.5", css(colorEdge)))
+
+	canvas.End()
+	return nil
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestSaveTimelineSnapshot(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	due := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		issues  []model.Issue
+		file    string
+		format  string
+		wantErr string
+		wantSVG []string
+	}{
+		{
+			name: "svg",
+			issues: []model.Issue{
+				{ID: "A", Title: "Root task", Status: model.StatusOpen},
+				{ID: "B", Title: "Depends on A", Status: model.StatusBlocked, DueDate: &due,
+					Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+				{ID: "C", Title: "Independent", Status: model.StatusInProgress},
+			},
+			file:    "timeline.svg",
+			wantSVG: []string{"Root task", "Depends on A", "data_hash: abc123", "missed due dates: 1", css(colorCritical)},
+		},
+		{
+			name: "png",
+			issues: []model.Issue{
+				{ID: "A", Title: "Root task", Status: model.StatusOpen},
+				{ID: "C", Title: "Independent", Status: model.StatusInProgress},
+			},
+			file: "timeline.png",
+		},
+		{
+			name:    "empty timeline",
+			issues:  []model.Issue{{ID: "D", Title: "Done", Status: model.StatusClosed}},
+			file:    "timeline.svg",
+			wantErr: "no open issues",
+		},
+		{
+			name:    "unsupported format",
+			issues:  []model.Issue{{ID: "A", Title: "Root task", Status: model.StatusOpen}},
+			file:    "timeline.gif",
+			format:  "gif",
+			wantErr: "unsupported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := analysis.NewAnalyzer(tt.issues).GetExecutionPlan()
+			tl := analysis.BuildTimeline(tt.issues, nil, plan, analysis.TimelineOptions{Now: now})
+			out := filepath.Join(t.TempDir(), tt.file)
+
+			err := SaveTimelineSnapshot(TimelineSnapshotOptions{Path: out, Format: tt.format, Timeline: tl, DataHash: "abc123"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %q error, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SaveTimelineSnapshot error: %v", err)
+			}
+			data, err := os.ReadFile(out)
+			if err != nil || len(data) == 0 {
+				t.Fatalf("output missing or empty: %v", err)
+			}
+			if len(tt.wantSVG) == 0 {
+				return
+			}
+
+			dec := xml.NewDecoder(strings.NewReader(string(data)))
+			for {
+				if _, err := dec.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("invalid SVG: %v", err)
+				}
+			}
+			for _, want := range tt.wantSVG {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("SVG missing %q", want)
+				}
+			}
+		})
+	}
+}
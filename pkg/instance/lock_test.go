@@ -2,6 +2,7 @@ package instance
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -328,3 +329,73 @@ func TestNewLock_ConcurrentStaleTakeover(t *testing.T) {
 		t.Errorf("Expected exactly 1 goroutine to be first instance, got %d", firstCount)
 	}
 }
+
+func TestAcquireWriteLock_Exclusive(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	lock, err := AcquireWriteLock(tmpDir, time.Second)
+	if err != nil {
+		t.Fatalf("AcquireWriteLock failed: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := AcquireWriteLock(tmpDir, 50*time.Millisecond); !errors.Is(err, ErrWriteLockTimeout) {
+		t.Fatalf("expected ErrWriteLockTimeout while held, got %v", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("second acquire returned before the timeout")
+	}
+
+	lock.Release()
+	lock.Release() // idempotent
+
+	lock2, err := AcquireWriteLock(tmpDir, time.Second)
+	if err != nil {
+		t.Fatalf("AcquireWriteLock after release failed: %v", err)
+	}
+	defer lock2.Release()
+	if _, err := os.Stat(filepath.Join(tmpDir, WriteLockFileName)); err != nil {
+		t.Errorf("write lock file missing: %v", err)
+	}
+}
+
+func TestAcquireWriteLock_WaitsForRelease(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	lock, err := AcquireWriteLock(tmpDir, time.Second)
+	if err != nil {
+		t.Fatalf("AcquireWriteLock failed: %v", err)
+	}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		lock.Release()
+	}()
+
+	lock2, err := AcquireWriteLock(tmpDir, 2*time.Second)
+	if err != nil {
+		t.Fatalf("expected to acquire after release, got %v", err)
+	}
+	lock2.Release()
+}
+
+func TestAcquireWriteLock_TakesOverStaleLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	lockPath := filepath.Join(tmpDir, WriteLockFileName)
+
+	stale := LockInfo{PID: 999999999, StartedAt: time.Now()}
+	data, _ := json.Marshal(stale)
+	if err := os.WriteFile(lockPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := AcquireWriteLock(tmpDir, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected stale lock takeover, got %v", err)
+	}
+	defer lock.Release()
+
+	info, err := readLockFile(lockPath)
+	if err != nil || info.PID != os.Getpid() {
+		t.Errorf("lock not owned by this process: %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,116 @@
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WriteLockFileName is the name of the short-lived lock file held while bv
+// writes to the beads data files. Unlike LockFileName, which only detects
+// concurrent viewers, this lock is exclusive: writers wait for each other.
+const WriteLockFileName = ".bv.write.lock"
+
+// DefaultWriteLockTimeout is how long AcquireWriteLock waits for another
+// writer before giving up.
+const DefaultWriteLockTimeout = 5 * time.Second
+
+// writeLockMaxAge bounds how long a write lock may be held. Writes take
+// milliseconds, so an older lock whose holder cannot be verified is stale.
+const writeLockMaxAge = 2 * time.Minute
+
+// ErrWriteLockTimeout is returned when the write lock could not be acquired
+// within the requested timeout.
+var ErrWriteLockTimeout = errors.New("timed out waiting for beads write lock")
+
+// WriteLock is an exclusive, cross-process lock on a beads directory.
+type WriteLock struct {
+	path string
+}
+
+// AcquireWriteLock blocks until it holds the write lock for beadsDir or the
+// timeout expires. Locks left behind by dead processes are taken over.
+// A non-positive timeout uses DefaultWriteLockTimeout.
+func AcquireWriteLock(beadsDir string, timeout time.Duration) (*WriteLock, error) {
+	if timeout <= 0 {
+		timeout = DefaultWriteLockTimeout
+	}
+	lockPath := filepath.Join(beadsDir, WriteLockFileName)
+	deadline := time.Now().Add(timeout)
+	backoff := 5 * time.Millisecond
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+		if err == nil {
+			hostname, _ := os.Hostname()
+			encErr := json.NewEncoder(file).Encode(LockInfo{
+				PID:       os.Getpid(),
+				StartedAt: time.Now(),
+				Hostname:  hostname,
+			})
+			closeErr := file.Close()
+			if encErr == nil {
+				encErr = closeErr
+			}
+			if encErr != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("writing write lock: %w", encErr)
+			}
+			return &WriteLock{path: lockPath}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("creating write lock: %w", err)
+		}
+
+		if writeLockIsStale(lockPath) {
+			// Remove and retry immediately; O_EXCL arbitrates between
+			// processes racing for the takeover.
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			if info, rerr := readLockFile(lockPath); rerr == nil {
+				return nil, fmt.Errorf("%w (held by pid %d)", ErrWriteLockTimeout, info.PID)
+			}
+			return nil, ErrWriteLockTimeout
+		}
+		time.Sleep(backoff)
+		if backoff < 100*time.Millisecond {
+			backoff *= 2
+		}
+	}
+}
+
+// writeLockIsStale reports whether the lock at path belongs to a dead process
+// or has outlived writeLockMaxAge.
+func writeLockIsStale(path string) bool {
+	info, err := readLockFile(path)
+	if err != nil {
+		// Unreadable: either mid-write by its creator or corrupt. Only treat
+		// it as stale once it is clearly abandoned.
+		st, statErr := os.Stat(path)
+		return statErr == nil && time.Since(st.ModTime()) > writeLockMaxAge
+	}
+	if info.PID != os.Getpid() && !isProcessAlive(info.PID) {
+		return true
+	}
+	return time.Since(info.StartedAt) > writeLockMaxAge
+}
+
+// Release removes the lock file. It is safe to call more than once.
+func (l *WriteLock) Release() {
+	if l == nil || l.path == "" {
+		return
+	}
+	os.Remove(l.path)
+	l.path = ""
+}
+
+// Path returns the path to the lock file.
+func (l *WriteLock) Path() string {
+	return l.path
+}
@@ -0,0 +1,164 @@
+package loader
+
+import (
+	"bytes"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DecodeIssueLine decodes one JSONL line the way the loader does (status
+// normalized, custom fields attached) but without validating the result.
+func DecodeIssueLine(line []byte) (model.Issue, error) {
+	var issue model.Issue
+	if err := json.Unmarshal(line, &issue); err != nil {
+		return model.Issue{}, err
+	}
+	issue.Status = normalizeIssueStatus(issue.Status)
+	attachCustomFields(&issue, line)
+	return issue, nil
+}
+
+// attachCustomFields copies top-level keys of line that Issue does not model
+// into issue.CustomFields. Keys from a nested "custom_fields" object (already
+// decoded) are kept; a top-level key of the same name wins.
+func attachCustomFields(issue *model.Issue, line []byte) {
+	extra := extractCustomFields(line)
+	if len(extra) == 0 {
+		return
+	}
+	if issue.CustomFields == nil {
+		issue.CustomFields = extra
+		return
+	}
+	for k, v := range extra {
+		issue.CustomFields[k] = v
+	}
+}
+
+// extractCustomFields scans the top level of a JSON object and decodes only
+// the values of unknown keys. Lines without custom fields cost one pass over
+// the bytes and no allocations.
+func extractCustomFields(line []byte) model.CustomFields {
+	i := skipJSONSpace(line, 0)
+	if i >= len(line) || line[i] != '{' {
+		return nil
+	}
+	i++
+
+	var out model.CustomFields
+	for {
+		i = skipJSONSpace(line, i)
+		if i >= len(line) || line[i] == '}' {
+			return out
+		}
+		if line[i] == ',' {
+			i++
+			continue
+		}
+		if line[i] != '"' {
+			return out
+		}
+		keyStart, keyEnd := i, skipJSONString(line, i)
+		if keyEnd < 0 {
+			return out
+		}
+		rawKey := line[keyStart+1 : keyEnd-1]
+		i = skipJSONSpace(line, keyEnd)
+		if i >= len(line) || line[i] != ':' {
+			return out
+		}
+		valStart := skipJSONSpace(line, i+1)
+		i = skipJSONValue(line, valStart)
+		if i < 0 {
+			return out
+		}
+
+		if bytes.IndexByte(rawKey, '\\') >= 0 {
+			var key string
+			if err := json.Unmarshal(line[keyStart:keyEnd], &key); err != nil || !model.IsCustomFieldName(key) {
+				continue
+			}
+			rawKey = []byte(key)
+		} else if !model.IsCustomFieldKey(rawKey) {
+			continue
+		}
+
+		var v any
+		if err := json.Unmarshal(line[valStart:i], &v); err != nil {
+			continue
+		}
+		if out == nil {
+			out = make(model.CustomFields)
+		}
+		out[string(rawKey)] = v
+	}
+}
+
+func skipJSONSpace(b []byte, i int) int {
+	for i < len(b) {
+		switch b[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipJSONString returns the index just past the string starting at b[i],
+// or -1 if it is unterminated.
+func skipJSONString(b []byte, i int) int {
+	for j := i + 1; j < len(b); j++ {
+		switch b[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// skipJSONValue returns the index just past the value starting at b[i], or
+// -1 if it is malformed.
+func skipJSONValue(b []byte, i int) int {
+	if i >= len(b) {
+		return -1
+	}
+	switch b[i] {
+	case '"':
+		return skipJSONString(b, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(b); j++ {
+			switch b[j] {
+			case '"':
+				end := skipJSONString(b, j)
+				if end < 0 {
+					return -1
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			}
+		}
+		return -1
+	}
+	j := i
+	for j < len(b) {
+		switch b[j] {
+		case ',', '}', ']', ' ', '\t', '\r', '\n':
+			return j
+		}
+		j++
+	}
+	return j
+}
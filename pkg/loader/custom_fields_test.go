@@ -0,0 +1,65 @@
+package loader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestExtractCustomFields(t *testing.T) {
+	line := []byte(`{"id":"a-1","title":"x, {y}","story_points":5, "sla_tier" : "gold",` +
+		`"tags":["p","q"],"owner":{"team":"core","note":"}\""},"content_hash":"abc","flag_x":true}`)
+	got := extractCustomFields(line)
+	want := model.CustomFields{
+		"story_points": float64(5),
+		"sla_tier":     "gold",
+		"tags":         []any{"p", "q"},
+		"owner":        map[string]any{"team": "core", "note": `}"`},
+		"flag_x":       true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractCustomFields = %#v, want %#v", got, want)
+	}
+
+	for _, bad := range []string{``, `[]`, `{"id":`, `{"story_points":"unterminated}`} {
+		if got := extractCustomFields([]byte(bad)); len(got) != 0 {
+			t.Errorf("extractCustomFields(%q) = %v, want empty", bad, got)
+		}
+	}
+}
+
+func TestExtractCustomFields_KnownKeysDoNotAllocate(t *testing.T) {
+	line := []byte(`{"id":"a-1","title":"t","status":"open","priority":1,"issue_type":"task","labels":["x"],"dependencies":[{"issue_id":"a-1","depends_on_id":"a-0","type":"blocks"}]}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		if extractCustomFields(line) != nil {
+			t.Fatal("unexpected custom fields")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("extractCustomFields allocated %.0f times for a line without custom fields", allocs)
+	}
+}
+
+func TestParseIssues_CustomFields(t *testing.T) {
+	data := strings.Join([]string{
+		`{"id":"a-1","title":"One","status":"open","priority":1,"issue_type":"task","story_points":3,"custom_fields":{"sla_tier":"gold","story_points":1}}`,
+		`{"id":"a-2","title":"Two","status":"open","priority":2,"issue_type":"bug"}`,
+	}, "\n")
+	issues, err := ParseIssues(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues", len(issues))
+	}
+	// A top-level key overrides the nested custom_fields entry of the same name.
+	want := model.CustomFields{"sla_tier": "gold", "story_points": float64(3)}
+	if !reflect.DeepEqual(issues[0].CustomFields, want) {
+		t.Errorf("a-1 custom fields = %#v, want %#v", issues[0].CustomFields, want)
+	}
+	if issues[1].CustomFields != nil {
+		t.Errorf("a-2 should have no custom fields, got %#v", issues[1].CustomFields)
+	}
+}
@@ -3,6 +3,7 @@ package loader
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -174,6 +175,38 @@ func (g *GitLoader) resolveRevision(revision string) (string, error) {
 	return "", fmt.Errorf("git rev-parse failed: %w", err)
 }
 
+// MergeBase returns the best common ancestor commit of two revisions.
+func (g *GitLoader) MergeBase(a, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", "--end-of-options", a, b)
+	cmd.Dir = g.repoPath
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s failed: %w", a, b, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HasBeadsFileAt reports whether revision contains one of the beads files
+// LoadAt reads, so callers can tell a revision that predates the beads
+// files from one whose files fail to load.
+func (g *GitLoader) HasBeadsFileAt(revision string) (bool, error) {
+	for _, name := range PreferredJSONLNames {
+		cmd := exec.Command("git", "cat-file", "-e", fmt.Sprintf("%s:.beads/%s", revision, name))
+		cmd.Dir = g.repoPath
+
+		err := cmd.Run()
+		if err == nil {
+			return true, nil
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return false, fmt.Errorf("git cat-file %s failed: %w", revision, err)
+		}
+	}
+	return false, nil
+}
+
 // parseDateString attempts to parse common date/time formats used by users.
 // Returns the parsed time and true on success.
 func parseDateString(s string) (time.Time, bool) {
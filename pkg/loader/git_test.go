@@ -143,6 +143,29 @@ func TestGitLoader_LoadAt_OlderCommit(t *testing.T) {
 	}
 }
 
+func TestGitLoader_HasBeadsFileAt(t *testing.T) {
+	repoDir, cleanup := setupTestGitRepo(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(repoDir, "README"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repoDir, "rm", "-q", "-r", ".beads")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "Drop beads")
+
+	loader := NewGitLoader(repoDir)
+	for rev, want := range map[string]bool{"HEAD~1": true, "HEAD": false} {
+		got, err := loader.HasBeadsFileAt(rev)
+		if err != nil {
+			t.Fatalf("HasBeadsFileAt(%s): %v", rev, err)
+		}
+		if got != want {
+			t.Errorf("HasBeadsFileAt(%s) = %v, want %v", rev, got, want)
+		}
+	}
+}
+
 func TestGitLoader_ResolveRevision(t *testing.T) {
 	repoDir, cleanup := setupTestGitRepo(t)
 	defer cleanup()
@@ -0,0 +1,443 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ChangeSet describes how the issues in a JSONL file changed between two
+// loads of an IncrementalLoader. IDs are sorted.
+type ChangeSet struct {
+	Added   []string
+	Updated []string
+	Removed []string
+
+	// Initial is true when there was no previous state to compare against
+	// (first load, after Reset, or after a failed load). The ID lists are
+	// empty in that case.
+	Initial bool
+	// Appended is true when only bytes past the previous end of file were
+	// read (the append fast path).
+	Appended bool
+
+	LinesParsed int // lines decoded as JSON on this load
+	LinesReused int // lines whose bytes matched a previous load
+}
+
+// Empty reports whether the load observed no issue-level changes.
+func (c ChangeSet) Empty() bool {
+	return !c.Initial && len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// Changed returns the added and updated IDs.
+func (c ChangeSet) Changed() []string {
+	out := make([]string, 0, len(c.Added)+len(c.Updated))
+	out = append(out, c.Added...)
+	return append(out, c.Updated...)
+}
+
+// IncrementalLoader re-reads a beads JSONL file while parsing only the lines
+// that changed since the previous load.
+//
+// It remembers each line's byte offset and content hash. On reload:
+//   - if the file is the same inode, has grown, and every previous line is
+//     byte-identical, only the appended bytes are parsed;
+//   - otherwise every line is read and hashed, but only lines whose hash was
+//     not seen on the previous load are decoded. Rewrites that reorder or
+//     insert lines (e.g. a full bd export) therefore still reuse the parsed
+//     issues for untouched lines.
+//
+// Issues returned by Load share label, dependency and comment slices with
+// the loader's cache and must be treated as read-only. The loader is safe for
+// concurrent use.
+type IncrementalLoader struct {
+	path string
+
+	mu    sync.Mutex
+	state *incrementalState
+}
+
+type incrementalState struct {
+	info  os.FileInfo
+	size  int64
+	lines []lineEntry
+	// ids maps issue ID to a hash of every line holding that ID, so duplicate
+	// IDs are still diffed correctly.
+	ids map[string]uint64
+}
+
+// lineEntry is one physical line of the JSONL file.
+type lineEntry struct {
+	offset     int64
+	length     int64 // bytes, including the line terminator
+	hash       uint64
+	terminated bool
+	issue      *model.Issue // nil for blank, skipped or filtered-out lines
+	skip       lineSkip
+	skipErr    string
+}
+
+type lineSkip int
+
+const (
+	lineOK lineSkip = iota
+	lineBlank
+	lineTooLong
+	lineMalformed
+	lineInvalid
+)
+
+// NewIncrementalLoader returns a loader for the JSONL file at path. Nothing
+// is read until the first Load.
+func NewIncrementalLoader(path string) *IncrementalLoader {
+	return &IncrementalLoader{path: path}
+}
+
+// Path returns the file the loader reads.
+func (l *IncrementalLoader) Path() string {
+	return l.path
+}
+
+// Reset drops the cached state; the next Load re-parses the whole file.
+func (l *IncrementalLoader) Reset() {
+	l.mu.Lock()
+	l.state = nil
+	l.mu.Unlock()
+}
+
+// Load returns the file's issues in line order, along with what changed
+// since the previous Load. opts.IssueFilter is applied to the returned slice
+// only; the ChangeSet always describes the unfiltered file. Warnings are
+// reported for every skipped line on every load, matching
+// LoadIssuesFromFileWithOptions.
+func (l *IncrementalLoader) Load(opts ParseOptions) ([]model.Issue, ChangeSet, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	info, err := os.Stat(l.path)
+	if err != nil {
+		l.state = nil
+		if os.IsNotExist(err) {
+			return nil, ChangeSet{}, fmt.Errorf("no beads issues found at %s", l.path)
+		}
+		return nil, ChangeSet{}, fmt.Errorf("failed to open issues file: %w", err)
+	}
+
+	maxLine := opts.BufferSize
+	if maxLine <= 0 {
+		maxLine = DefaultMaxBufferSize
+	}
+
+	prev := l.state
+	var next *incrementalState
+	var changes ChangeSet
+	if prev != nil && os.SameFile(prev.info, info) && info.Size() == prev.size && info.ModTime().Equal(prev.info.ModTime()) {
+		next = prev
+		changes.LinesReused = len(prev.lines)
+	} else if prev != nil && l.canAppend(prev, info, maxLine) {
+		next, changes, err = l.loadAppended(prev, info, maxLine)
+	} else {
+		next, changes, err = l.loadFull(prev, info, maxLine)
+	}
+	if err != nil {
+		l.state = nil
+		return nil, ChangeSet{}, err
+	}
+	if prev == nil {
+		changes = ChangeSet{Initial: true, LinesParsed: changes.LinesParsed}
+	}
+	l.state = next
+
+	return next.materialize(opts), changes, nil
+}
+
+// canAppend reports whether the previous content is still a prefix of the
+// file: same inode, grown, and every previous line byte-identical at its old
+// offset. The prefix is re-read and hashed but not decoded, so an in-place
+// rewrite of any earlier line followed by an append falls back to loadFull.
+func (l *IncrementalLoader) canAppend(prev *incrementalState, info os.FileInfo, maxLine int) bool {
+	if !os.SameFile(prev.info, info) || info.Size() <= prev.size || len(prev.lines) == 0 {
+		return false
+	}
+	if !prev.lines[len(prev.lines)-1].terminated {
+		return false
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	i := 0
+	same := true
+	err = scanLines(io.NewSectionReader(f, 0, prev.size), 0, 1, maxLine, func(e lineEntry, _ []byte) {
+		if !same {
+			return
+		}
+		if i >= len(prev.lines) || e.length != prev.lines[i].length || e.hash != prev.lines[i].hash {
+			same = false
+		}
+		i++
+	})
+	return err == nil && same && i == len(prev.lines)
+}
+
+func (l *IncrementalLoader) loadAppended(prev *incrementalState, info os.FileInfo, maxLine int) (*incrementalState, ChangeSet, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, ChangeSet{}, fmt.Errorf("failed to open issues file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(prev.size, io.SeekStart); err != nil {
+		return nil, ChangeSet{}, err
+	}
+
+	next := &incrementalState{
+		info:  info,
+		size:  prev.size,
+		lines: prev.lines,
+		ids:   prev.ids,
+	}
+	changes := ChangeSet{Appended: true, LinesReused: len(prev.lines)}
+	added := make(map[string]bool)
+	updated := make(map[string]bool)
+	err = scanLines(f, prev.size, len(prev.lines)+1, maxLine, func(e lineEntry, body []byte) {
+		if e.skip == lineOK {
+			e.issue, e.skip, e.skipErr = parseLine(body)
+			changes.LinesParsed++
+		}
+		next.lines = append(next.lines, e)
+		next.size = e.offset + e.length
+		if e.issue == nil {
+			return
+		}
+		id := e.issue.ID
+		if old, ok := next.ids[id]; ok {
+			next.ids[id] = combineLineHash(old, e.hash)
+			if !added[id] {
+				updated[id] = true
+			}
+		} else {
+			next.ids[id] = e.hash
+			added[id] = true
+		}
+	})
+	if err != nil {
+		return nil, ChangeSet{}, err
+	}
+	changes.Added = sortedKeys(added)
+	changes.Updated = sortedKeys(updated)
+	return next, changes, nil
+}
+
+func (l *IncrementalLoader) loadFull(prev *incrementalState, info os.FileInfo, maxLine int) (*incrementalState, ChangeSet, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, ChangeSet{}, fmt.Errorf("failed to open issues file: %w", err)
+	}
+	defer f.Close()
+
+	// Index previously parsed lines by content hash so moved lines are reused.
+	var known map[uint64]*lineEntry
+	lineCap := 64
+	if prev != nil {
+		known = make(map[uint64]*lineEntry, len(prev.lines))
+		for i := range prev.lines {
+			if prev.lines[i].skip != lineTooLong {
+				known[prev.lines[i].hash] = &prev.lines[i]
+			}
+		}
+		lineCap = len(prev.lines) + 16
+	}
+
+	next := &incrementalState{
+		info:  info,
+		lines: make([]lineEntry, 0, lineCap),
+		ids:   make(map[string]uint64, lineCap),
+	}
+	var changes ChangeSet
+	err = scanLines(f, 0, 1, maxLine, func(e lineEntry, body []byte) {
+		if e.skip == lineOK {
+			if old, ok := known[e.hash]; ok {
+				e.issue, e.skip, e.skipErr = old.issue, old.skip, old.skipErr
+				changes.LinesReused++
+			} else {
+				e.issue, e.skip, e.skipErr = parseLine(body)
+				changes.LinesParsed++
+			}
+		}
+		next.lines = append(next.lines, e)
+		next.size = e.offset + e.length
+		if e.issue != nil {
+			if old, ok := next.ids[e.issue.ID]; ok {
+				next.ids[e.issue.ID] = combineLineHash(old, e.hash)
+			} else {
+				next.ids[e.issue.ID] = e.hash
+			}
+		}
+	})
+	if err != nil {
+		return nil, ChangeSet{}, err
+	}
+
+	if prev != nil {
+		for id, h := range next.ids {
+			if old, ok := prev.ids[id]; !ok {
+				changes.Added = append(changes.Added, id)
+			} else if old != h {
+				changes.Updated = append(changes.Updated, id)
+			}
+		}
+		for id := range prev.ids {
+			if _, ok := next.ids[id]; !ok {
+				changes.Removed = append(changes.Removed, id)
+			}
+		}
+		sort.Strings(changes.Added)
+		sort.Strings(changes.Updated)
+		sort.Strings(changes.Removed)
+	}
+	return next, changes, nil
+}
+
+// materialize copies the cached issues into a fresh slice, reporting
+// warnings and applying the filter.
+func (s *incrementalState) materialize(opts ParseOptions) []model.Issue {
+	warn := opts.WarningHandler
+	if warn == nil {
+		if os.Getenv("BV_ROBOT") == "1" {
+			warn = func(string) {}
+		} else {
+			warn = func(msg string) {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+			}
+		}
+	}
+	maxLine := opts.BufferSize
+	if maxLine <= 0 {
+		maxLine = DefaultMaxBufferSize
+	}
+
+	issues := make([]model.Issue, 0, len(s.ids))
+	for i := range s.lines {
+		e := &s.lines[i]
+		lineNum := i + 1
+		switch e.skip {
+		case lineTooLong:
+			warn(fmt.Sprintf("skipping line %d: line too long (exceeds %d bytes)", lineNum, maxLine))
+		case lineMalformed:
+			warn(fmt.Sprintf("skipping malformed JSON on line %d: %s", lineNum, e.skipErr))
+		case lineInvalid:
+			warn(fmt.Sprintf("skipping invalid issue on line %d: %s", lineNum, e.skipErr))
+		}
+		if e.issue == nil {
+			continue
+		}
+		issues = append(issues, *e.issue)
+		if opts.IssueFilter != nil && !opts.IssueFilter(&issues[len(issues)-1]) {
+			issues = issues[:len(issues)-1]
+		}
+	}
+	return issues
+}
+
+// scanLines reads r line by line starting at byte offset start, calling fn
+// with each line's entry and its body (terminator and, on line 1, BOM
+// stripped). Lines longer than maxLine are reported with skip lineTooLong.
+func scanLines(r io.Reader, start int64, firstLine, maxLine int, fn func(lineEntry, []byte)) error {
+	reader := bufio.NewReaderSize(r, maxLine)
+	offset := start
+	lineNum := firstLine
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(chunk) == 0 && err == io.EOF {
+			return nil
+		}
+		e := lineEntry{offset: offset}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			h := fnv.New64a()
+			h.Write(chunk)
+			e.length = int64(len(chunk))
+			for errors.Is(err, bufio.ErrBufferFull) {
+				chunk, err = reader.ReadSlice('\n')
+				h.Write(chunk)
+				e.length += int64(len(chunk))
+			}
+			if err != nil && err != io.EOF {
+				return fmt.Errorf("error skipping long line at line %d: %w", lineNum, err)
+			}
+			e.terminated = err == nil
+			e.hash = h.Sum64()
+			e.skip = lineTooLong
+			fn(e, nil)
+		} else {
+			if err != nil && err != io.EOF {
+				return fmt.Errorf("error reading issues stream at line %d: %w", lineNum, err)
+			}
+			e.length = int64(len(chunk))
+			e.terminated = err == nil
+			body := trimLineEnding(chunk)
+			e.hash = hashLine(body)
+			if lineNum == 1 {
+				body = stripBOM(body)
+			}
+			if len(body) == 0 {
+				e.skip = lineBlank
+			}
+			fn(e, body)
+		}
+		offset += e.length
+		lineNum++
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// parseLine decodes and validates a single JSONL line.
+func parseLine(body []byte) (*model.Issue, lineSkip, string) {
+	issue, err := DecodeIssueLine(body)
+	if err != nil {
+		return nil, lineMalformed, err.Error()
+	}
+	if err := issue.Validate(); err != nil {
+		return nil, lineInvalid, err.Error()
+	}
+	return &issue, lineOK, ""
+}
+
+func trimLineEnding(b []byte) []byte {
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	return bytes.TrimSuffix(b, []byte("\r"))
+}
+
+func hashLine(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+func combineLineHash(a, b uint64) uint64 {
+	return a*1099511628211 ^ b
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,191 @@
+package loader_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func issueLine(id, status string, priority int) string {
+	return fmt.Sprintf(`{"id":%q,"title":"Issue %s","status":%q,"priority":%d,"issue_type":"task"}`, id, id, status, priority)
+}
+
+// replaceFile writes content via temp file + rename, as bd and bv do.
+func replaceFile(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadIncremental(t *testing.T, l *loader.IncrementalLoader) ([]model.Issue, loader.ChangeSet) {
+	t.Helper()
+	issues, changes, err := l.Load(loader.ParseOptions{WarningHandler: func(string) {}})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	full, err := loader.LoadIssuesFromFileWithOptions(l.Path(), loader.ParseOptions{WarningHandler: func(string) {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(issues, full) {
+		t.Fatalf("incremental load differs from full load:\n got %+v\nwant %+v", issues, full)
+	}
+	return issues, changes
+}
+
+func TestIncrementalLoader_InitialAndUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.jsonl")
+	replaceFile(t, path, issueLine("A", "open", 1)+"\n"+issueLine("B", "open", 2)+"\n")
+
+	l := loader.NewIncrementalLoader(path)
+	issues, changes := loadIncremental(t, l)
+	if len(issues) != 2 || !changes.Initial || changes.LinesParsed != 2 {
+		t.Fatalf("unexpected initial load: %d issues, %+v", len(issues), changes)
+	}
+
+	_, changes = loadIncremental(t, l)
+	if !changes.Empty() || changes.LinesParsed != 0 || changes.LinesReused != 2 {
+		t.Errorf("reload of unchanged file should be empty: %+v", changes)
+	}
+}
+
+func TestIncrementalLoader_AppendOnlyParsesNewLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.jsonl")
+	replaceFile(t, path, issueLine("A", "open", 1)+"\n"+issueLine("B", "open", 2)+"\n")
+	l := loader.NewIncrementalLoader(path)
+	loadIncremental(t, l)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(issueLine("C", "open", 0) + "\n" + issueLine("A", "closed", 1) + "\n")
+	f.Close()
+
+	issues, changes := loadIncremental(t, l)
+	if !changes.Appended || changes.LinesParsed != 2 || changes.LinesReused != 2 {
+		t.Errorf("expected append fast path: %+v", changes)
+	}
+	if strings.Join(changes.Added, ",") != "C" || strings.Join(changes.Updated, ",") != "A" || len(changes.Removed) != 0 {
+		t.Errorf("unexpected change set: %+v", changes)
+	}
+	if len(issues) != 4 {
+		t.Errorf("expected duplicate A to be kept like the full loader, got %d issues", len(issues))
+	}
+}
+
+func TestIncrementalLoader_InPlaceRewriteThenAppendRereadsPrefix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.jsonl")
+	replaceFile(t, path, issueLine("A", "open", 1)+"\n"+issueLine("B", "open", 2)+"\n")
+	l := loader.NewIncrementalLoader(path)
+	loadIncremental(t, l)
+
+	// Same inode and same-length first line, then an append: the last line
+	// still matches, but the prefix does not.
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte(issueLine("A", "open", 3)), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(issueLine("C", "open", 0) + "\n")
+	f.Close()
+
+	issues, changes := loadIncremental(t, l)
+	if changes.Appended {
+		t.Errorf("rewritten prefix must not take the append fast path: %+v", changes)
+	}
+	if strings.Join(changes.Added, ",") != "C" || strings.Join(changes.Updated, ",") != "A" {
+		t.Errorf("unexpected change set: %+v", changes)
+	}
+	if issues[0].Priority != 3 {
+		t.Errorf("A kept stale priority %d", issues[0].Priority)
+	}
+}
+
+func TestIncrementalLoader_RewriteReparsesOnlyChangedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.jsonl")
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, issueLine(fmt.Sprintf("bv-%02d", i), "open", 2))
+	}
+	replaceFile(t, path, strings.Join(lines, "\n")+"\n")
+	l := loader.NewIncrementalLoader(path)
+	loadIncremental(t, l)
+
+	// One status change, one removal and one insertion in the middle, written
+	// as a full rewrite that shifts every following line.
+	lines[10] = issueLine("bv-10", "in_progress", 2)
+	lines = append(lines[:20], lines[21:]...)
+	lines = append(lines[:30], append([]string{issueLine("bv-new", "open", 1)}, lines[30:]...)...)
+	replaceFile(t, path, strings.Join(lines, "\n")+"\n")
+
+	_, changes := loadIncremental(t, l)
+	if changes.Appended || changes.LinesParsed != 2 || changes.LinesReused != 48 {
+		t.Errorf("expected only the two new lines to be parsed: %+v", changes)
+	}
+	if strings.Join(changes.Added, ",") != "bv-new" || strings.Join(changes.Updated, ",") != "bv-10" || strings.Join(changes.Removed, ",") != "bv-20" {
+		t.Errorf("unexpected change set: %+v", changes)
+	}
+}
+
+func TestIncrementalLoader_WarningsFilterAndErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.jsonl")
+	content := "\xEF\xBB\xBF" + issueLine("A", "open", 1) + "\r\n" +
+		"not json\n" +
+		`{"id":"","title":"x","status":"open","issue_type":"task"}` + "\n\n" +
+		issueLine("B", "closed", 2)
+	replaceFile(t, path, content)
+
+	l := loader.NewIncrementalLoader(path)
+	for round := 0; round < 2; round++ {
+		var warnings []string
+		issues, _, err := l.Load(loader.ParseOptions{
+			WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+			IssueFilter:    func(i *model.Issue) bool { return i.Status != model.StatusClosed },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 1 || issues[0].ID != "A" {
+			t.Errorf("round %d: filter not applied: %+v", round, issues)
+		}
+		if len(warnings) != 2 || !strings.Contains(warnings[0], "malformed JSON on line 2") || !strings.Contains(warnings[1], "invalid issue on line 3") {
+			t.Errorf("round %d: unexpected warnings %v", round, warnings)
+		}
+	}
+
+	// The last line had no terminator, so appending falls back to a full scan.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString("\n" + issueLine("C", "open", 3) + "\n")
+	f.Close()
+	_, changes := loadIncremental(t, l)
+	if changes.Appended || strings.Join(changes.Added, ",") != "C" {
+		t.Errorf("unexpected change set after appending to unterminated file: %+v", changes)
+	}
+
+	os.Remove(path)
+	if _, _, err := l.Load(loader.ParseOptions{}); err == nil {
+		t.Error("expected error for missing file")
+	}
+	replaceFile(t, path, issueLine("A", "open", 1)+"\n")
+	if _, changes := loadIncremental(t, l); !changes.Initial {
+		t.Errorf("load after an error should start over: %+v", changes)
+	}
+}
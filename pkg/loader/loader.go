@@ -433,6 +433,7 @@ func parseIssuesWithOptions(r io.Reader, opts ParseOptions, usePool bool) ([]mod
 			}
 
 			issue.Status = normalizeIssueStatus(issue.Status)
+			attachCustomFields(issue, line)
 
 			// Validate issue
 			if err := issue.Validate(); err != nil {
@@ -458,6 +459,7 @@ func parseIssuesWithOptions(r io.Reader, opts ParseOptions, usePool bool) ([]mod
 			}
 
 			issue.Status = normalizeIssueStatus(issue.Status)
+			attachCustomFields(&issue, line)
 
 			// Validate issue
 			if err := issue.Validate(); err != nil {
@@ -127,4 +127,5 @@ func clearIssueReferences(issue *model.Issue) {
 	issue.ExternalRef = nil
 	issue.CompactedAt = nil
 	issue.CompactedAtCommit = nil
+	issue.CustomFields = nil
 }
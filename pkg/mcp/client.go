@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Client is a minimal MCP stdio client. It is primarily intended for tests
+// and for embedding bv's tools in Go programs: connect it to a Server with a
+// pair of io.Pipes, or to a subprocess's stdin/stdout.
+type Client struct {
+	out *lineWriter
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan clientResponse
+	err     error
+	done    chan struct{}
+}
+
+type clientResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// ErrClientClosed is returned for calls made after the server stream ended.
+var ErrClientClosed = errors.New("mcp: client closed")
+
+// NewClient starts reading responses from r; requests are written to w.
+func NewClient(r io.Reader, w io.Writer) *Client {
+	c := &Client{
+		out:     &lineWriter{w: w},
+		pending: make(map[string]chan clientResponse),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop(r)
+	return c
+}
+
+func (c *Client) readLoop(r io.Reader) {
+	reader := bufio.NewReader(r)
+	var loopErr error
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var resp clientResponse
+			if jerr := json.Unmarshal(line, &resp); jerr == nil {
+				c.mu.Lock()
+				ch, ok := c.pending[string(resp.ID)]
+				delete(c.pending, string(resp.ID))
+				c.mu.Unlock()
+				if ok {
+					ch <- resp
+				}
+			}
+		}
+		if err != nil {
+			loopErr = err
+			break
+		}
+	}
+
+	c.mu.Lock()
+	if errors.Is(loopErr, io.EOF) {
+		loopErr = ErrClientClosed
+	}
+	c.err = loopErr
+	c.mu.Unlock()
+	close(c.done)
+}
+
+// call sends a request and decodes its result into out (which may be nil).
+func (c *Client) call(ctx context.Context, method string, params any, out any) error {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return err
+	}
+	c.nextID++
+	id := strconv.FormatInt(c.nextID, 10)
+	ch := make(chan clientResponse, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	var raw json.RawMessage
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		raw = b
+	}
+	c.out.write(request{JSONRPC: "2.0", ID: json.RawMessage(id), Method: method, Params: raw})
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if out != nil {
+			if err := json.Unmarshal(resp.Result, out); err != nil {
+				return fmt.Errorf("mcp: decoding %s result: %w", method, err)
+			}
+		}
+		return nil
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// notify sends a notification (no response expected).
+func (c *Client) notify(method string) {
+	c.out.write(request{JSONRPC: "2.0", Method: method})
+}
+
+// Initialize performs the initialize handshake and sends the initialized
+// notification.
+func (c *Client) Initialize(ctx context.Context, info Implementation) (*InitializeResult, error) {
+	var result InitializeResult
+	err := c.call(ctx, "initialize", InitializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      info,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	c.notify("notifications/initialized")
+	return &result, nil
+}
+
+// Ping checks that the server is responsive.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+// ListTools returns the server's tools.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var result ListToolsResult
+	if err := c.call(ctx, "tools/list", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool invokes a tool. args is marshalled to JSON; nil means no arguments.
+func (c *Client) CallTool(ctx context.Context, name string, args any) (*CallToolResult, error) {
+	params := CallToolParams{Name: name}
+	if args != nil {
+		b, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		params.Arguments = b
+	}
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
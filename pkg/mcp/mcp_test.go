@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startPair connects a Client to srv through in-memory pipes.
+func startPair(t *testing.T, srv *Server) *Client {
+	t.Helper()
+	clientToServerR, clientToServerW := io.Pipe()
+	serverToClientR, serverToClientW := io.Pipe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, clientToServerR, serverToClientW)
+		serverToClientW.Close()
+	}()
+	t.Cleanup(func() {
+		clientToServerW.Close()
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Error("server did not stop")
+		}
+	})
+	return NewClient(serverToClientR, clientToServerW)
+}
+
+func echoServer() *Server {
+	srv := NewServer(ServerOptions{Name: "test", Version: "0.0.1"})
+	srv.AddTool(Tool{
+		Name: "echo",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"text":  map[string]any{"type": "string"},
+				"times": map[string]any{"type": "integer", "minimum": 1},
+				"mode":  map[string]any{"type": "string", "enum": []string{"upper", "lower"}},
+			},
+			"required":             []string{"text"},
+			"additionalProperties": false,
+		},
+	}, func(_ context.Context, args json.RawMessage) (any, error) {
+		var in struct {
+			Text  string `json:"text"`
+			Times int    `json:"times"`
+		}
+		if err := json.Unmarshal(args, &in); err != nil {
+			return nil, err
+		}
+		if in.Times == 0 {
+			in.Times = 1
+		}
+		return map[string]string{"echo": strings.Repeat(in.Text, in.Times)}, nil
+	})
+	srv.AddTool(Tool{Name: "fail"}, func(context.Context, json.RawMessage) (any, error) {
+		return nil, errors.New("tool exploded")
+	})
+	return srv
+}
+
+func TestClientServer_Handshake(t *testing.T) {
+	client := startPair(t, echoServer())
+	ctx := context.Background()
+
+	init, err := client.Initialize(ctx, Implementation{Name: "client", Version: "1"})
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if init.ServerInfo.Name != "test" || init.ProtocolVersion != ProtocolVersion {
+		t.Errorf("unexpected initialize result: %+v", init)
+	}
+	if _, ok := init.Capabilities["tools"]; !ok {
+		t.Error("tools capability not advertised")
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "echo" || tools[1].Name != "fail" {
+		t.Errorf("unexpected tools: %+v", tools)
+	}
+	if tools[1].InputSchema["type"] != "object" {
+		t.Errorf("default input schema not applied: %+v", tools[1].InputSchema)
+	}
+}
+
+func TestClientServer_CallTool(t *testing.T) {
+	client := startPair(t, echoServer())
+	ctx := context.Background()
+
+	res, err := client.CallTool(ctx, "echo", map[string]any{"text": "ab", "times": 2})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if res.IsError || len(res.Content) != 1 || res.Content[0].Text != `{"echo":"abab"}` {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	res, err = client.CallTool(ctx, "fail", nil)
+	if err != nil {
+		t.Fatalf("CallTool(fail): %v", err)
+	}
+	if !res.IsError || res.Content[0].Text != "tool exploded" {
+		t.Errorf("expected tool error result, got %+v", res)
+	}
+
+	var rpcErr *RPCError
+	_, err = client.CallTool(ctx, "nope", nil)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("expected invalid params for unknown tool, got %v", err)
+	}
+}
+
+func TestClientServer_ArgumentValidation(t *testing.T) {
+	client := startPair(t, echoServer())
+	ctx := context.Background()
+
+	cases := map[string]any{
+		"missing required": map[string]any{},
+		"wrong type":       map[string]any{"text": 5},
+		"not integer":      map[string]any{"text": "a", "times": 1.5},
+		"below minimum":    map[string]any{"text": "a", "times": 0},
+		"bad enum":         map[string]any{"text": "a", "mode": "title"},
+		"unknown argument": map[string]any{"text": "a", "extra": true},
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.CallTool(ctx, "echo", args)
+			var rpcErr *RPCError
+			if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+				t.Errorf("expected invalid params error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServer_ParseErrorAndUnknownMethod(t *testing.T) {
+	srv := echoServer()
+	in := strings.NewReader("not json\n" +
+		`{"jsonrpc":"2.0","id":7,"method":"resources/list"}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n")
+	var out strings.Builder
+	if err := srv.Serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 responses (notification gets none), got %d: %q", len(lines), out.String())
+	}
+	codes := map[int]bool{}
+	for _, line := range lines {
+		var resp clientResponse
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("bad response %q: %v", line, err)
+		}
+		if resp.Error == nil {
+			t.Fatalf("expected error response, got %q", line)
+		}
+		codes[resp.Error.Code] = true
+	}
+	if !codes[CodeParseError] || !codes[CodeMethodNotFound] {
+		t.Errorf("unexpected error codes: %v", codes)
+	}
+}
@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidateArguments checks args against the subset of JSON Schema used by
+// tool input schemas: an object with typed properties, required keys, enums,
+// numeric bounds and additionalProperties=false. Anything else in the schema
+// is ignored rather than rejected.
+func ValidateArguments(schema map[string]any, args json.RawMessage) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+	if obj == nil {
+		return fmt.Errorf("arguments must be a JSON object")
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for _, name := range stringList(schema["required"]) {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("missing required argument %q", name)
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propSchema, known := props[name].(map[string]any)
+		if !known {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				return fmt.Errorf("unknown argument %q", name)
+			}
+			continue
+		}
+		if err := validateValue(propSchema, obj[name]); err != nil {
+			return fmt.Errorf("argument %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func validateValue(schema map[string]any, v any) error {
+	typ, _ := schema["type"].(string)
+	switch typ {
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("expected string")
+		}
+		if enum := stringList(schema["enum"]); len(enum) > 0 {
+			for _, allowed := range enum {
+				if s == allowed {
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of %s", strings.Join(enum, ", "))
+		}
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected %s", typ)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return fmt.Errorf("expected %s", typ)
+		}
+		if typ == "integer" && f != math.Trunc(f) {
+			return fmt.Errorf("expected integer")
+		}
+		if min, ok := numberValue(schema["minimum"]); ok && f < min {
+			return fmt.Errorf("must be >= %v", min)
+		}
+		if max, ok := numberValue(schema["maximum"]); ok && f > max {
+			return fmt.Errorf("must be <= %v", max)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("expected boolean")
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("expected array")
+		}
+		if min, ok := numberValue(schema["minItems"]); ok && float64(len(items)) < min {
+			return fmt.Errorf("expected at least %v items", min)
+		}
+		if itemSchema, ok := schema["items"].(map[string]any); ok {
+			for i, item := range items {
+				if err := validateValue(itemSchema, item); err != nil {
+					return fmt.Errorf("item %d: %w", i, err)
+				}
+			}
+		}
+	case "object":
+		if _, ok := v.(map[string]any); !ok {
+			return fmt.Errorf("expected object")
+		}
+	}
+	return nil
+}
+
+// stringList accepts both []string (schemas built in Go) and []any (schemas
+// decoded from JSON).
+func stringList(v any) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func numberValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
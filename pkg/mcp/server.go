@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ToolHandler executes a tool call. args holds the raw JSON arguments, which
+// have already been validated against the tool's input schema. The returned
+// value is rendered with ServerOptions.Encode into the result text.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (any, error)
+
+// ServerOptions configures a Server.
+type ServerOptions struct {
+	Name         string
+	Version      string
+	Instructions string
+	// Encode renders tool results as text. Default: compact JSON.
+	Encode func(v any) (string, error)
+}
+
+type registeredTool struct {
+	tool    Tool
+	handler ToolHandler
+}
+
+// Server dispatches MCP requests to registered tools.
+type Server struct {
+	opts ServerOptions
+
+	mu    sync.RWMutex
+	tools map[string]registeredTool
+}
+
+// NewServer creates a server with no tools.
+func NewServer(opts ServerOptions) *Server {
+	if opts.Encode == nil {
+		opts.Encode = func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		}
+	}
+	return &Server{opts: opts, tools: make(map[string]registeredTool)}
+}
+
+// AddTool registers a tool, replacing any existing tool with the same name.
+// A nil InputSchema is treated as an object schema with no properties.
+func (s *Server) AddTool(tool Tool, handler ToolHandler) {
+	if tool.InputSchema == nil {
+		tool.InputSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[tool.Name] = registeredTool{tool: tool, handler: handler}
+}
+
+// Tools returns the registered tools sorted by name.
+func (s *Server) Tools() []Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Tool, 0, len(s.tools))
+	for _, t := range s.tools {
+		out = append(out, t.tool)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes responses
+// to w until r reaches EOF or ctx is cancelled. Requests are handled
+// concurrently; responses may therefore arrive out of order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := &lineWriter{w: w}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					readErr <- ctx.Err()
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case line := <-lines:
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			var req request
+			if err := json.Unmarshal(line, &req); err != nil {
+				out.write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &RPCError{Code: CodeParseError, Message: "parse error: " + err.Error()}})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, rpcErr := s.dispatch(ctx, &req)
+				if req.isNotification() {
+					return
+				}
+				resp := response{JSONRPC: "2.0", ID: req.ID}
+				if rpcErr != nil {
+					resp.Error = rpcErr
+				} else {
+					resp.Result = result
+				}
+				out.write(resp)
+			}()
+		}
+	}
+}
+
+func (s *Server) dispatch(ctx context.Context, req *request) (any, *RPCError) {
+	if req.JSONRPC != "2.0" {
+		return nil, &RPCError{Code: CodeInvalidRequest, Message: `jsonrpc must be "2.0"`}
+	}
+	switch req.Method {
+	case "initialize":
+		var params InitializeParams
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+			}
+		}
+		version := ProtocolVersion
+		if supportedProtocolVersions[params.ProtocolVersion] {
+			version = params.ProtocolVersion
+		}
+		return InitializeResult{
+			ProtocolVersion: version,
+			Capabilities:    map[string]any{"tools": map[string]any{"listChanged": false}},
+			ServerInfo:      Implementation{Name: s.opts.Name, Version: s.opts.Version},
+			Instructions:    s.opts.Instructions,
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return ListToolsResult{Tools: s.Tools()}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			return nil, nil
+		}
+		return nil, &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (any, *RPCError) {
+	var params CallToolParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+
+	s.mu.RLock()
+	reg, ok := s.tools[params.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name}
+	}
+
+	args := params.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	if err := ValidateArguments(reg.tool.InputSchema, args); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: fmt.Sprintf("invalid arguments for %s: %v", params.Name, err)}
+	}
+
+	value, err := reg.handler(ctx, args)
+	if err != nil {
+		return CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	text, err := s.opts.Encode(value)
+	if err != nil {
+		return nil, &RPCError{Code: CodeInternalError, Message: "encoding result: " + err.Error()}
+	}
+	return CallToolResult{Content: []Content{{Type: "text", Text: text}}}, nil
+}
+
+// lineWriter serializes newline-delimited JSON messages onto w.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lineWriter) write(v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	_, _ = lw.w.Write(append(b, '\n'))
+}
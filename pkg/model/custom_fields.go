@@ -0,0 +1,185 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CustomFields holds JSONL keys that are not part of the beads schema, such as
+// "story_points" or "sla_tier". Values keep their decoded JSON types: string,
+// float64, bool, nil, []any or map[string]any.
+type CustomFields map[string]any
+
+// Names returns the field names in sorted order.
+func (c CustomFields) Names() []string {
+	if len(c) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Clone returns a deep copy.
+func (c CustomFields) Clone() CustomFields {
+	if c == nil {
+		return nil
+	}
+	out := make(CustomFields, len(c))
+	for k, v := range c {
+		out[k] = cloneCustomValue(v)
+	}
+	return out
+}
+
+func cloneCustomValue(v any) any {
+	switch t := v.(type) {
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = cloneCustomValue(e)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[k] = cloneCustomValue(e)
+		}
+		return out
+	}
+	return v
+}
+
+// CustomField returns the named custom field, if present.
+func (i *Issue) CustomField(name string) (any, bool) {
+	if i.CustomFields == nil {
+		return nil, false
+	}
+	v, ok := i.CustomFields[name]
+	return v, ok
+}
+
+// reservedIssueFields are keys bd writes that bv does not model but that are
+// bookkeeping rather than user data, so they are not surfaced as custom
+// fields.
+var reservedIssueFields = map[string]bool{
+	"close_reason":      true,
+	"closed_by_session": true,
+	"content_hash":      true,
+	"deleted_at":        true,
+	"deleted_by":        true,
+	"delete_reason":     true,
+	"original_type":     true,
+	"source_system":     true,
+	"ephemeral":         true,
+	"is_template":       true,
+}
+
+// issueJSONFields is the set of JSON keys decoded into Issue's typed fields.
+var issueJSONFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(Issue{})
+	for idx := 0; idx < t.NumField(); idx++ {
+		name, _, _ := strings.Cut(t.Field(idx).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// IsIssueField reports whether key is a JSON key of one of Issue's typed
+// fields.
+func IsIssueField(key string) bool {
+	return issueJSONFields[key]
+}
+
+// IsCustomFieldName reports whether a top-level JSONL key should be kept as a
+// custom field rather than being decoded into Issue or ignored.
+func IsCustomFieldName(key string) bool {
+	return key != "" && !issueJSONFields[key] && !reservedIssueFields[key]
+}
+
+// IsCustomFieldKey is IsCustomFieldName for a raw key, without allocating.
+func IsCustomFieldKey(key []byte) bool {
+	return len(key) > 0 && !issueJSONFields[string(key)] && !reservedIssueFields[string(key)]
+}
+
+// FormatCustomValue renders a custom field value for display and text
+// matching: numbers without trailing zeros, lists comma-separated, objects as
+// compact JSON.
+func FormatCustomValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(t)
+	case []any:
+		parts := make([]string, 0, len(t))
+		for _, e := range t {
+			parts = append(parts, FormatCustomValue(e))
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+// CustomValueNumber returns v as a number if it is numeric or a string that
+// parses as one.
+func CustomValueNumber(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// CompareCustomValues orders two custom field values: numbers numerically,
+// everything else by case-insensitive text. A nil (missing) value sorts after
+// any present value.
+func CompareCustomValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	if af, ok := CustomValueNumber(a); ok {
+		if bf, ok := CustomValueNumber(b); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(FormatCustomValue(a)), strings.ToLower(FormatCustomValue(b)))
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsCustomFieldName(t *testing.T) {
+	cases := map[string]bool{
+		"story_points":  true,
+		"sla_tier":      true,
+		"":              false,
+		"id":            false,
+		"labels":        false,
+		"custom_fields": false,
+		"content_hash":  false,
+		"close_reason":  false,
+	}
+	for name, want := range cases {
+		if got := IsCustomFieldName(name); got != want {
+			t.Errorf("IsCustomFieldName(%q) = %v, want %v", name, got, want)
+		}
+		if got := IsCustomFieldKey([]byte(name)); got != want {
+			t.Errorf("IsCustomFieldKey(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFormatCustomValue(t *testing.T) {
+	cases := []struct {
+		in   any
+		want string
+	}{
+		{nil, ""},
+		{"gold", "gold"},
+		{true, "true"},
+		{float64(5), "5"},
+		{2.5, "2.5"},
+		{[]any{"a", float64(1)}, "a, 1"},
+		{map[string]any{"k": "v"}, `{"k":"v"}`},
+	}
+	for _, tc := range cases {
+		if got := FormatCustomValue(tc.in); got != tc.want {
+			t.Errorf("FormatCustomValue(%#v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCompareCustomValues(t *testing.T) {
+	cases := []struct {
+		a, b any
+		want int
+	}{
+		{float64(2), float64(10), -1},
+		{"10", float64(2), 1},
+		{"Gold", "gold", 0},
+		{"bronze", "silver", -1},
+		{nil, "x", 1},
+		{"x", nil, -1},
+		{nil, nil, 0},
+	}
+	for _, tc := range cases {
+		if got := CompareCustomValues(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareCustomValues(%#v, %#v) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestCustomFields_CloneIsDeep(t *testing.T) {
+	issue := Issue{ID: "a", CustomFields: CustomFields{
+		"tags":  []any{"x"},
+		"owner": map[string]any{"team": "core"},
+	}}
+	clone := issue.Clone()
+	clone.CustomFields["tags"].([]any)[0] = "y"
+	clone.CustomFields["owner"].(map[string]any)["team"] = "web"
+	clone.CustomFields["new"] = true
+
+	want := CustomFields{"tags": []any{"x"}, "owner": map[string]any{"team": "core"}}
+	if !reflect.DeepEqual(issue.CustomFields, want) {
+		t.Errorf("clone mutated original: %#v", issue.CustomFields)
+	}
+	if got := clone.CustomFields.Names(); !reflect.DeepEqual(got, []string{"new", "owner", "tags"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	if v, ok := clone.CustomField("new"); !ok || v != true {
+		t.Errorf("CustomField(new) = %v, %v", v, ok)
+	}
+}
@@ -32,6 +32,7 @@ type Issue struct {
 	Dependencies       []*Dependency `json:"dependencies,omitempty"`
 	Comments           []*Comment    `json:"comments,omitempty"`
 	SourceRepo         string        `json:"source_repo,omitempty"`
+	CustomFields       CustomFields  `json:"custom_fields,omitempty"` // Unknown JSONL keys, see IsCustomFieldName
 }
 
 // Clone creates a deep copy of the issue
@@ -78,6 +79,8 @@ func (i Issue) Clone() Issue {
 		}
 	}
 
+	clone.CustomFields = i.CustomFields.Clone()
+
 	if i.Comments != nil {
 		clone.Comments = make([]*Comment, len(i.Comments))
 		for idx, comment := range i.Comments {
@@ -0,0 +1,120 @@
+package recipe
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// CustomFieldSortPrefix marks a sort field that names a custom field, e.g.
+// "custom.story_points".
+const CustomFieldSortPrefix = "custom."
+
+// CustomSortField returns the custom field name for a sort field of the form
+// "custom.<name>".
+func CustomSortField(field string) (string, bool) {
+	name, ok := strings.CutPrefix(field, CustomFieldSortPrefix)
+	return name, ok && name != ""
+}
+
+// CompareCustomField compares two issues by a custom field. Issues without
+// the field sort last whichever direction the caller then applies.
+func CompareCustomField(a, b *model.Issue, name string, desc bool) int {
+	av, aok := a.CustomField(name)
+	bv, bok := b.CustomField(name)
+	aok = aok && av != nil
+	bok = bok && bv != nil
+	last := 1
+	if desc {
+		last = -1
+	}
+	switch {
+	case !aok && !bok:
+		return 0
+	case !aok:
+		return last
+	case !bok:
+		return -last
+	}
+	return model.CompareCustomValues(av, bv)
+}
+
+// MatchCustomFields reports whether fields satisfy every condition in
+// f.CustomFields. Each field matches if any of its patterns matches:
+//
+//	"*"            field is present
+//	">=5", "<3"    numeric comparison (also ">" and "<=")
+//	"!=gold"       field is absent or differs
+//	"gold"         case-insensitive equality; list values match any element
+func (f FilterConfig) MatchCustomFields(fields model.CustomFields) bool {
+	for name, patterns := range f.CustomFields {
+		if len(patterns) == 0 {
+			continue
+		}
+		value, present := fields[name]
+		present = present && value != nil
+		matched := false
+		for _, p := range patterns {
+			if matchCustomPattern(value, present, strings.TrimSpace(p)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCustomPattern(value any, present bool, pattern string) bool {
+	if pattern == "*" {
+		return present
+	}
+	if rest, ok := strings.CutPrefix(pattern, "!="); ok {
+		return !present || !customValueEquals(value, strings.TrimSpace(rest))
+	}
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		rest, ok := strings.CutPrefix(pattern, op)
+		if !ok {
+			continue
+		}
+		want, err := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+		if err != nil || !present {
+			return false
+		}
+		got, ok := model.CustomValueNumber(value)
+		if !ok {
+			return false
+		}
+		switch op {
+		case ">=":
+			return got >= want
+		case "<=":
+			return got <= want
+		case ">":
+			return got > want
+		default:
+			return got < want
+		}
+	}
+	return present && customValueEquals(value, pattern)
+}
+
+func customValueEquals(value any, want string) bool {
+	if list, ok := value.([]any); ok {
+		for _, e := range list {
+			if customValueEquals(e, want) {
+				return true
+			}
+		}
+		return false
+	}
+	if got, ok := model.CustomValueNumber(value); ok {
+		if w, err := strconv.ParseFloat(want, 64); err == nil {
+			return got == w
+		}
+	}
+	return strings.EqualFold(model.FormatCustomValue(value), want)
+}
@@ -0,0 +1,77 @@
+package recipe_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+func TestFilterConfig_MatchCustomFields(t *testing.T) {
+	fields := model.CustomFields{
+		"story_points": float64(5),
+		"sla_tier":     "Gold",
+		"tags":         []any{"infra", "q3"},
+	}
+	cases := []struct {
+		name   string
+		filter map[string][]string
+		want   bool
+	}{
+		{"empty filter", nil, true},
+		{"present", map[string][]string{"sla_tier": {"*"}}, true},
+		{"absent", map[string][]string{"customer": {"*"}}, false},
+		{"equality ignores case", map[string][]string{"sla_tier": {"gold"}}, true},
+		{"any of", map[string][]string{"sla_tier": {"silver", "gold"}}, true},
+		{"numeric equality", map[string][]string{"story_points": {"5.0"}}, true},
+		{"numeric range", map[string][]string{"story_points": {">=5"}}, true},
+		{"numeric range miss", map[string][]string{"story_points": {"<5"}}, false},
+		{"list element", map[string][]string{"tags": {"q3"}}, true},
+		{"not equal", map[string][]string{"sla_tier": {"!=silver"}}, true},
+		{"not equal absent", map[string][]string{"customer": {"!=acme"}}, true},
+		{"all fields must match", map[string][]string{"sla_tier": {"gold"}, "story_points": {">8"}}, false},
+	}
+	for _, tc := range cases {
+		f := recipe.FilterConfig{CustomFields: tc.filter}
+		if got := f.MatchCustomFields(fields); got != tc.want {
+			t.Errorf("%s: MatchCustomFields = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCompareCustomField_MissingSortsLast(t *testing.T) {
+	issues := []*model.Issue{
+		{ID: "none"},
+		{ID: "three", CustomFields: model.CustomFields{"points": float64(3)}},
+		{ID: "eight", CustomFields: model.CustomFields{"points": "8"}},
+	}
+	order := func(desc bool) []string {
+		sorted := append([]*model.Issue(nil), issues...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			cmp := recipe.CompareCustomField(sorted[i], sorted[j], "points", desc)
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+		ids := make([]string, len(sorted))
+		for i, issue := range sorted {
+			ids[i] = issue.ID
+		}
+		return ids
+	}
+	if got := order(false); got[0] != "three" || got[1] != "eight" || got[2] != "none" {
+		t.Errorf("ascending order = %v", got)
+	}
+	if got := order(true); got[0] != "eight" || got[1] != "three" || got[2] != "none" {
+		t.Errorf("descending order = %v", got)
+	}
+
+	if name, ok := recipe.CustomSortField("custom.points"); !ok || name != "points" {
+		t.Errorf("CustomSortField = %q, %v", name, ok)
+	}
+	if _, ok := recipe.CustomSortField("priority"); ok {
+		t.Error("priority is not a custom sort field")
+	}
+}
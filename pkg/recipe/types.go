@@ -32,11 +32,14 @@ type FilterConfig struct {
 	Actionable    *bool    `yaml:"actionable,omitempty" json:"actionable,omitempty"`         // true = no open blockers
 	TitleContains string   `yaml:"title_contains,omitempty" json:"title_contains,omitempty"` // Substring match
 	IDPrefix      string   `yaml:"id_prefix,omitempty" json:"id_prefix,omitempty"`           // e.g., "bv-" for project filtering
+
+	// CustomFields filters on custom JSONL fields; see MatchCustomFields.
+	CustomFields map[string][]string `yaml:"custom_fields,omitempty" json:"custom_fields,omitempty"`
 }
 
 // SortConfig defines how to order issues
 type SortConfig struct {
-	Field     string      `yaml:"field" json:"field"`                             // priority, created, updated, title, id, pagerank, betweenness
+	Field     string      `yaml:"field" json:"field"`                             // priority, created, updated, title, id, pagerank, betweenness, custom.<name>
 	Direction string      `yaml:"direction,omitempty" json:"direction,omitempty"` // asc, desc (default: asc for priority, desc for dates)
 	Secondary *SortConfig `yaml:"secondary,omitempty" json:"secondary,omitempty"` // Tie-breaker
 }
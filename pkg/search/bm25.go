@@ -0,0 +1,317 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// BM25 parameters (standard Okapi defaults).
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// LexicalBlendWeight is the share of BM25 in the blended text score; the
+// remainder comes from semantic similarity.
+const LexicalBlendWeight = 0.6
+
+// Indexed fields, in boost order.
+const (
+	fieldID = iota
+	fieldTitle
+	fieldLabels
+	fieldDescription
+	fieldComments
+	numFields
+)
+
+// FieldBoosts weights each field's term frequency in BM25F scoring.
+type FieldBoosts struct {
+	ID          float64
+	Title       float64
+	Labels      float64
+	Description float64 // Also design, acceptance criteria and notes
+	Comments    float64
+}
+
+// DefaultFieldBoosts ranks title above labels above description above
+// comments; exact ID hits outrank everything.
+var DefaultFieldBoosts = FieldBoosts{ID: 4, Title: 3, Labels: 2, Description: 1, Comments: 0.5}
+
+func (b FieldBoosts) array() [numFields]float64 {
+	return [numFields]float64{b.ID, b.Title, b.Labels, b.Description, b.Comments}
+}
+
+// IssueFields is the per-field counterpart of IssueDocument used by the
+// lexical index. It covers the same text as ChunksFromIssue; design,
+// acceptance criteria and notes share the description slot, as they do in
+// the chunk index.
+type IssueFields struct {
+	ID                 string
+	Title              string
+	Labels             string
+	Description        string
+	Design             string
+	AcceptanceCriteria string
+	Notes              string
+	Comments           string
+}
+
+// IssueFieldsFor extracts the searchable fields of issue.
+func IssueFieldsFor(issue model.Issue) IssueFields {
+	var comments []string
+	for _, c := range issue.Comments {
+		if c != nil && c.Text != "" {
+			comments = append(comments, c.Text)
+		}
+	}
+	return IssueFields{
+		ID:                 issue.ID,
+		Title:              issue.Title,
+		Labels:             strings.Join(issue.Labels, " "),
+		Description:        issue.Description,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Notes:              issue.Notes,
+		Comments:           strings.Join(comments, "\n"),
+	}
+}
+
+// Text joins all fields, for phrase matching.
+func (f IssueFields) Text() string {
+	return strings.Join([]string{f.ID, f.Title, f.Labels, f.longText(), f.Comments}, "\n")
+}
+
+func (f IssueFields) array() [numFields]string {
+	return [numFields]string{f.ID, f.Title, f.Labels, f.longText(), f.Comments}
+}
+
+// longText joins the fields indexed in the description slot.
+func (f IssueFields) longText() string {
+	parts := make([]string, 0, 4)
+	for _, s := range []string{f.Description, f.Design, f.AcceptanceCriteria, f.Notes} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// TokenizeText lowercases text and splits it into letter/digit runs.
+func TokenizeText(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isTokenRune(r)
+	})
+}
+
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type bm25Posting struct {
+	doc int32
+	tf  [numFields]uint16
+}
+
+// BM25Index is an in-memory inverted index scoring issues with BM25F.
+type BM25Index struct {
+	boosts   [numFields]float64
+	ids      []string
+	lengths  [][numFields]int
+	avgLen   [numFields]float64
+	postings map[string][]bm25Posting
+}
+
+// NewBM25Index indexes issues with DefaultFieldBoosts.
+func NewBM25Index(issues []model.Issue) *BM25Index {
+	return NewBM25IndexWithBoosts(issues, DefaultFieldBoosts)
+}
+
+// NewBM25IndexWithBoosts indexes issues with custom field boosts.
+func NewBM25IndexWithBoosts(issues []model.Issue, boosts FieldBoosts) *BM25Index {
+	docs := make([]bm25Doc, 0, len(issues))
+	for _, issue := range issues {
+		if issue.ID == "" {
+			continue
+		}
+		docs = append(docs, bm25Doc{id: issue.ID, fields: IssueFieldsFor(issue).array()})
+	}
+	return newBM25Index(docs, boosts)
+}
+
+// bm25Doc is one indexed unit: an issue, or a chunk of one.
+type bm25Doc struct {
+	id     string
+	fields [numFields]string
+}
+
+func newBM25Index(docs []bm25Doc, boosts FieldBoosts) *BM25Index {
+	idx := &BM25Index{
+		boosts:   boosts.array(),
+		postings: make(map[string][]bm25Posting),
+	}
+	var totals, present [numFields]int
+	for _, d := range docs {
+		doc := int32(len(idx.ids))
+		idx.ids = append(idx.ids, d.id)
+
+		var lengths [numFields]int
+		tfs := make(map[string]*[numFields]uint16)
+		for field, text := range d.fields {
+			tokens := TokenizeText(text)
+			lengths[field] = len(tokens)
+			if len(tokens) > 0 {
+				totals[field] += len(tokens)
+				present[field]++
+			}
+			for _, tok := range tokens {
+				tf := tfs[tok]
+				if tf == nil {
+					tf = new([numFields]uint16)
+					tfs[tok] = tf
+				}
+				if tf[field] < math.MaxUint16 {
+					tf[field]++
+				}
+			}
+		}
+		idx.lengths = append(idx.lengths, lengths)
+		for tok, tf := range tfs {
+			idx.postings[tok] = append(idx.postings[tok], bm25Posting{doc: doc, tf: *tf})
+		}
+	}
+	// Average over documents that have the field, so sparse fields (comments,
+	// or any field in a chunk index) are not normalized against empty ones.
+	for f := range totals {
+		if present[f] > 0 {
+			idx.avgLen[f] = float64(totals[f]) / float64(present[f])
+		}
+	}
+	return idx
+}
+
+// Len returns the number of indexed issues.
+func (idx *BM25Index) Len() int {
+	return len(idx.ids)
+}
+
+// Search scores issues against the words of text and returns matches in
+// descending score order, normalized so the best match scores 1. allow, when
+// non-nil, restricts the candidates; limit <= 0 returns all matches.
+func (idx *BM25Index) Search(text string, limit int, allow func(id string) bool) []SearchResult {
+	terms := uniqueTokens(TokenizeText(text))
+	if len(terms) == 0 || len(idx.ids) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.ids))
+	scores := make(map[int32]float64)
+	for _, term := range terms {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for _, p := range postings {
+			// BM25F: length-normalize and boost each field, then saturate once.
+			var tf float64
+			for f := 0; f < numFields; f++ {
+				if p.tf[f] == 0 || idx.boosts[f] == 0 {
+					continue
+				}
+				norm := 1.0
+				if idx.avgLen[f] > 0 {
+					norm = 1 - bm25B + bm25B*float64(idx.lengths[p.doc][f])/idx.avgLen[f]
+				}
+				tf += idx.boosts[f] * float64(p.tf[f]) / norm
+			}
+			if tf > 0 {
+				scores[p.doc] += idf * tf / (bm25K1 + tf)
+			}
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for doc, score := range scores {
+		id := idx.ids[doc]
+		if allow != nil && !allow(id) {
+			continue
+		}
+		results = append(results, SearchResult{IssueID: id, Score: score})
+	}
+	sortResults(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	if len(results) > 0 && results[0].Score > 0 {
+		top := results[0].Score
+		for i := range results {
+			results[i].Score /= top
+		}
+	}
+	return results
+}
+
+// BlendTextScore combines a normalized BM25 score with a semantic similarity
+// into the text component fed to HybridScorer. Negative similarities count
+// as zero.
+func BlendTextScore(lexical, semantic float64) float64 {
+	return LexicalBlendWeight*lexical + (1-LexicalBlendWeight)*math.Max(semantic, 0)
+}
+
+// BlendTextScores merges normalized BM25 and semantic results into one
+// BlendTextScore per issue, covering issues found by either side.
+func BlendTextScores(lexical, semantic []SearchResult) []SearchResult {
+	type pair struct{ lex, sem float64 }
+	merged := make(map[string]*pair, len(lexical)+len(semantic))
+	get := func(id string) *pair {
+		p := merged[id]
+		if p == nil {
+			p = &pair{}
+			merged[id] = p
+		}
+		return p
+	}
+	for _, r := range lexical {
+		get(r.IssueID).lex = r.Score
+	}
+	for _, r := range semantic {
+		get(r.IssueID).sem = r.Score
+	}
+
+	results := make([]SearchResult, 0, len(merged))
+	for id, p := range merged {
+		results = append(results, SearchResult{
+			IssueID: id,
+			Score:   BlendTextScore(p.lex, p.sem),
+		})
+	}
+	sortResults(results)
+	return results
+}
+
+func sortResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].IssueID < results[j].IssueID
+		}
+		return results[i].Score > results[j].Score
+	})
+}
+
+func uniqueTokens(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	out := tokens[:0]
+	for _, t := range tokens {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestBM25FieldBoosts(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "desc", Title: "Unrelated", Description: "the cache layer is slow"},
+		{ID: "title", Title: "Cache eviction", Description: "details elsewhere"},
+		{ID: "label", Title: "Something", Labels: []string{"cache"}},
+		{ID: "comment", Title: "Other", Comments: []*model.Comment{{Text: "maybe the cache?"}}},
+		{ID: "none", Title: "Nothing relevant"},
+	}
+	idx := NewBM25Index(issues)
+	if idx.Len() != len(issues) {
+		t.Fatalf("Len = %d", idx.Len())
+	}
+
+	results := idx.Search("cache", 0, nil)
+	var order []string
+	for _, r := range results {
+		order = append(order, r.IssueID)
+	}
+	want := []string{"title", "label", "desc", "comment"}
+	if len(order) != len(want) {
+		t.Fatalf("results = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("results = %v, want %v", order, want)
+		}
+	}
+	if results[0].Score != 1 {
+		t.Errorf("top score should normalize to 1, got %f", results[0].Score)
+	}
+	for _, r := range results[1:] {
+		if r.Score <= 0 || r.Score >= 1 {
+			t.Errorf("score out of range for %s: %f", r.IssueID, r.Score)
+		}
+	}
+}
+
+func TestBM25IndexesSameFieldsAsChunks(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "design", Title: "A", Design: "use a write-ahead journal"},
+		{ID: "ac", Title: "B", AcceptanceCriteria: "journal replays after a crash"},
+		{ID: "notes", Title: "C", Notes: "journal size grows"},
+		{ID: "none", Title: "D", Description: "unrelated"},
+	}
+	results := NewBM25Index(issues).Search("journal", 0, nil)
+	if len(results) != 3 {
+		t.Fatalf("results = %+v, want design, ac and notes", results)
+	}
+	for _, issue := range issues[:3] {
+		if len(ChunksFromIssue(issue)) != 2 {
+			t.Errorf("%s: chunk index should cover the same field", issue.ID)
+		}
+	}
+}
+
+func TestBM25RareTermsWeighMore(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Title: "bug in parser"},
+		{ID: "b", Title: "bug in renderer"},
+		{ID: "c", Title: "bug in exporter"},
+		{ID: "d", Title: "parser rewrite"},
+	}
+	results := NewBM25Index(issues).Search("parser bug", 0, nil)
+	if len(results) != 4 || results[0].IssueID != "a" {
+		t.Fatalf("expected a first, got %+v", results)
+	}
+	// "parser" is rarer than "bug", so d outranks b and c.
+	if results[1].IssueID != "d" {
+		t.Errorf("expected d second, got %+v", results)
+	}
+}
+
+func TestBM25AllowAndLimit(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Title: "search index"},
+		{ID: "b", Title: "search ui"},
+		{ID: "c", Title: "search api"},
+	}
+	idx := NewBM25Index(issues)
+	results := idx.Search("search", 1, func(id string) bool { return id != "a" })
+	if len(results) != 1 || results[0].IssueID != "b" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got := idx.Search("   ", 0, nil); got != nil {
+		t.Errorf("empty query should return nil, got %+v", got)
+	}
+}
+
+func TestBlendTextScores(t *testing.T) {
+	lexical := []SearchResult{{IssueID: "a", Score: 1}, {IssueID: "b", Score: 0.5}}
+	semantic := []SearchResult{{IssueID: "b", Score: 0.9}, {IssueID: "c", Score: 0.8}, {IssueID: "d", Score: -0.3}}
+	results := BlendTextScores(lexical, semantic)
+	if len(results) != 4 {
+		t.Fatalf("expected union of 4, got %+v", results)
+	}
+	scores := make(map[string]float64)
+	for _, r := range results {
+		scores[r.IssueID] = r.Score
+	}
+	approx := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if !approx(scores["a"], LexicalBlendWeight) {
+		t.Errorf("a = %f", scores["a"])
+	}
+	if !approx(scores["b"], LexicalBlendWeight*0.5+(1-LexicalBlendWeight)*0.9) {
+		t.Errorf("b = %f", scores["b"])
+	}
+	if scores["d"] != 0 {
+		t.Errorf("negative similarity should count as zero, got %f", scores["d"])
+	}
+	if results[0].IssueID != "b" || results[3].IssueID != "d" {
+		t.Errorf("unexpected order: %+v", results)
+	}
+}
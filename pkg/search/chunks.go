@@ -0,0 +1,432 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Chunk fields, as reported in ChunkMatch.Field.
+const (
+	ChunkFieldTitle              = "title"
+	ChunkFieldDescription        = "description"
+	ChunkFieldDesign             = "design"
+	ChunkFieldAcceptanceCriteria = "acceptance_criteria"
+	ChunkFieldNotes              = "notes"
+	ChunkFieldComment            = "comment"
+)
+
+const (
+	// maxChunkWords bounds a chunk; longer fields are split at paragraph
+	// breaks, and single long paragraphs into word windows.
+	maxChunkWords = 120
+
+	// DefaultSnippetLength is the target snippet size in bytes.
+	DefaultSnippetLength = 160
+)
+
+// chunkFieldBoosts ranks chunks of the same issue: a title hit is the most
+// telling, a comment the least.
+var chunkFieldBoosts = map[string]float64{
+	ChunkFieldTitle:              3,
+	ChunkFieldAcceptanceCriteria: 1.2,
+	ChunkFieldDescription:        1,
+	ChunkFieldDesign:             1,
+	ChunkFieldNotes:              0.8,
+	ChunkFieldComment:            0.8,
+}
+
+// Chunk is a searchable piece of an issue: its title, a part of a long text
+// field, or a single comment.
+type Chunk struct {
+	ID      string `json:"chunk_id"` // "<issue>#<field>:<n>"
+	IssueID string `json:"issue_id"`
+	Field   string `json:"field"`
+	Comment int    `json:"comment,omitempty"` // 1-based comment number for comment chunks
+	Author  string `json:"author,omitempty"`
+	Text    string `json:"-"`
+}
+
+// ChunksFromIssue splits issue into chunks. The title is always one chunk;
+// description, design, acceptance criteria and notes are split when long;
+// each comment is its own chunk (or several, if long).
+func ChunksFromIssue(issue model.Issue) []Chunk {
+	if issue.ID == "" {
+		return nil
+	}
+	var chunks []Chunk
+	seq := make(map[string]int)
+	add := func(field, text string, comment int, author string) {
+		for _, part := range splitChunkText(text) {
+			chunks = append(chunks, Chunk{
+				ID:      fmt.Sprintf("%s#%s:%d", issue.ID, field, seq[field]),
+				IssueID: issue.ID,
+				Field:   field,
+				Comment: comment,
+				Author:  author,
+				Text:    part,
+			})
+			seq[field]++
+		}
+	}
+
+	add(ChunkFieldTitle, issue.Title, 0, "")
+	add(ChunkFieldDescription, issue.Description, 0, "")
+	add(ChunkFieldDesign, issue.Design, 0, "")
+	add(ChunkFieldAcceptanceCriteria, issue.AcceptanceCriteria, 0, "")
+	add(ChunkFieldNotes, issue.Notes, 0, "")
+	for i, c := range issue.Comments {
+		if c != nil {
+			add(ChunkFieldComment, c.Text, i+1, c.Author)
+		}
+	}
+	return chunks
+}
+
+// splitChunkText splits text into pieces of at most maxChunkWords words,
+// preferring paragraph boundaries.
+func splitChunkText(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if len(strings.Fields(text)) <= maxChunkWords {
+		return []string{text}
+	}
+
+	var parts []string
+	var cur []string
+	curWords := 0
+	flush := func() {
+		if len(cur) > 0 {
+			parts = append(parts, strings.Join(cur, "\n\n"))
+			cur, curWords = nil, 0
+		}
+	}
+	for _, para := range strings.Split(text, "\n\n") {
+		para = strings.TrimSpace(para)
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			continue
+		}
+		if len(words) > maxChunkWords {
+			flush()
+			for start := 0; start < len(words); start += maxChunkWords {
+				end := min(start+maxChunkWords, len(words))
+				parts = append(parts, strings.Join(words[start:end], " "))
+			}
+			continue
+		}
+		if curWords+len(words) > maxChunkWords {
+			flush()
+		}
+		cur = append(cur, para)
+		curWords += len(words)
+	}
+	flush()
+	return parts
+}
+
+// ChunkMatch is the chunk of an issue that best matches a query.
+type ChunkMatch struct {
+	Chunk
+	Score      float64  `json:"score"`
+	Snippet    string   `json:"snippet"`
+	Highlights [][2]int `json:"highlights,omitempty"` // Byte ranges of matched words within Snippet
+	Semantic   bool     `json:"semantic,omitempty"`   // Chosen by embedding similarity rather than matching words
+}
+
+// ChunkIndex is a BM25 index over issue chunks, used to point search results
+// at the passage that matched.
+type ChunkIndex struct {
+	bm25    *BM25Index
+	chunks  map[string]Chunk
+	byIssue map[string][]string
+}
+
+// NewChunkIndex chunks and indexes issues.
+func NewChunkIndex(issues []model.Issue) *ChunkIndex {
+	ci := &ChunkIndex{
+		chunks:  make(map[string]Chunk),
+		byIssue: make(map[string][]string),
+	}
+	var docs []bm25Doc
+	for _, issue := range issues {
+		for _, c := range ChunksFromIssue(issue) {
+			ci.chunks[c.ID] = c
+			ci.byIssue[c.IssueID] = append(ci.byIssue[c.IssueID], c.ID)
+			d := bm25Doc{id: c.ID}
+			d.fields[chunkSlot(c.Field)] = c.Text
+			docs = append(docs, d)
+		}
+	}
+	// Boosts are applied per chunk field in BestMatches; the slots only keep
+	// length normalization separate for titles, long text and comments.
+	ci.bm25 = newBM25Index(docs, FieldBoosts{Title: 1, Description: 1, Comments: 1})
+	return ci
+}
+
+func chunkSlot(field string) int {
+	switch field {
+	case ChunkFieldTitle:
+		return fieldTitle
+	case ChunkFieldComment:
+		return fieldComments
+	default:
+		return fieldDescription
+	}
+}
+
+// Chunks returns the chunks of issueID in document order.
+func (ci *ChunkIndex) Chunks(issueID string) []Chunk {
+	ids := ci.byIssue[issueID]
+	out := make([]Chunk, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, ci.chunks[id])
+	}
+	return out
+}
+
+// BestMatches returns, for each of issueIDs with a chunk containing words of
+// text, its best chunk with a highlighted snippet.
+func (ci *ChunkIndex) BestMatches(text string, issueIDs []string) map[string]ChunkMatch {
+	wanted := make(map[string]bool, len(issueIDs))
+	for _, id := range issueIDs {
+		wanted[id] = true
+	}
+	hits := ci.bm25.Search(text, 0, func(chunkID string) bool {
+		return wanted[ci.chunks[chunkID].IssueID]
+	})
+	for i := range hits {
+		hits[i].Score *= chunkFieldBoosts[ci.chunks[hits[i].IssueID].Field]
+	}
+	sortResults(hits)
+
+	terms := TokenizeText(text)
+	out := make(map[string]ChunkMatch)
+	for _, h := range hits {
+		c := ci.chunks[h.IssueID]
+		if _, ok := out[c.IssueID]; ok {
+			continue
+		}
+		snippet, highlights := Snippet(c.Text, terms, DefaultSnippetLength)
+		out[c.IssueID] = ChunkMatch{Chunk: c, Score: h.Score, Snippet: snippet, Highlights: highlights}
+	}
+	return out
+}
+
+// ChunkVectorsPath returns where the chunk embeddings for the vector index at
+// indexPath are cached.
+func ChunkVectorsPath(indexPath string) string {
+	return strings.TrimSuffix(indexPath, ".bvvi") + "-chunks.bvvi"
+}
+
+// EmbedChunks brings cache up to date for the chunks of issueIDs, embedding
+// only chunks that are missing or whose text changed. A cache built by a
+// different model is cleared first. It returns the number of chunks embedded.
+func (ci *ChunkIndex) EmbedChunks(ctx context.Context, embedder Embedder, cache *VectorIndex, issueIDs []string) (int, error) {
+	if cache.Dim != embedder.Dim() {
+		return 0, fmt.Errorf("chunk cache dim %d does not match embedder dim %d", cache.Dim, embedder.Dim())
+	}
+	cache.adoptModel(EmbedderModelID(embedder))
+
+	var todo []Chunk
+	var hashes []ContentHash
+	for _, id := range issueIDs {
+		for _, c := range ci.Chunks(id) {
+			h := ComputeContentHash(c.Text)
+			if e, ok := cache.Get(c.ID); ok && e.ContentHash == h {
+				continue
+			}
+			todo = append(todo, c)
+			hashes = append(hashes, h)
+		}
+	}
+	if len(todo) == 0 {
+		return 0, nil
+	}
+	texts := make([]string, len(todo))
+	for i, c := range todo {
+		texts[i] = c.Text
+	}
+	vecs, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return 0, err
+	}
+	if len(vecs) != len(todo) {
+		return 0, fmt.Errorf("embedder returned %d vectors for %d chunks", len(vecs), len(todo))
+	}
+	for i, c := range todo {
+		if err := cache.Upsert(c.ID, hashes[i], vecs[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(todo), nil
+}
+
+// PruneVectors drops cached embeddings of chunks that are no longer in the
+// index and returns how many it removed.
+func (ci *ChunkIndex) PruneVectors(cache *VectorIndex) int {
+	removed := 0
+	for _, id := range cache.sortedIDs() {
+		if _, ok := ci.chunks[id]; !ok {
+			cache.Remove(id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// SemanticMatches picks, for each of issueIDs, the chunk whose embedding is
+// closest to query. Chunk embeddings are taken from cache and only computed
+// for the chunks of issueIDs that it lacks, so callers should pass the
+// handful of results that had no lexical match. A nil cache embeds them all.
+func (ci *ChunkIndex) SemanticMatches(ctx context.Context, embedder Embedder, cache *VectorIndex, query []float32, issueIDs []string) (map[string]ChunkMatch, error) {
+	if cache == nil {
+		cache = NewVectorIndex(embedder.Dim())
+	}
+	if _, err := ci.EmbedChunks(ctx, embedder, cache, issueIDs); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]ChunkMatch)
+	for _, id := range issueIDs {
+		for _, c := range ci.Chunks(id) {
+			e, ok := cache.Get(c.ID)
+			if !ok {
+				continue
+			}
+			score := dotFloat32(query, e.Vector)
+			if best, ok := out[c.IssueID]; ok && best.Score >= score {
+				continue
+			}
+			snippet, _ := Snippet(c.Text, nil, DefaultSnippetLength)
+			out[c.IssueID] = ChunkMatch{Chunk: c, Score: score, Snippet: snippet, Semantic: true}
+		}
+	}
+	return out, nil
+}
+
+// Snippet returns an excerpt of text of about maxLen bytes around the densest
+// run of terms, with whitespace collapsed, plus the byte ranges of the
+// matched words within it. With no matches it returns the start of text.
+func Snippet(text string, terms []string, maxLen int) (string, [][2]int) {
+	text = strings.Join(strings.Fields(text), " ")
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[strings.ToLower(t)] = true
+	}
+
+	var hits [][2]int
+	if len(want) > 0 {
+		for _, span := range wordSpans(text) {
+			if want[strings.ToLower(text[span[0]:span[1]])] {
+				hits = append(hits, span)
+			}
+		}
+	}
+
+	if len(text) <= maxLen {
+		return text, hits
+	}
+
+	// Pick the window containing the most hits, starting a little before
+	// its first hit for context.
+	start := 0
+	if len(hits) > 0 {
+		best, bestCount := 0, 0
+		for i := range hits {
+			count := sort.Search(len(hits), func(j int) bool { return hits[j][1] > hits[i][0]+maxLen }) - i
+			if count > bestCount {
+				best, bestCount = i, count
+			}
+		}
+		start = max(hits[best][0]-maxLen/5, 0)
+	}
+	end := min(start+maxLen, len(text))
+	if end == len(text) {
+		start = max(end-maxLen, 0)
+	}
+	// Snap to word boundaries.
+	if start > 0 {
+		if i := strings.IndexByte(text[start:end], ' '); i >= 0 {
+			start += i + 1
+		}
+	}
+	if end < len(text) {
+		if i := strings.LastIndexByte(text[start:end], ' '); i > 0 {
+			end = start + i
+		}
+	}
+	// Text without spaces (e.g. CJK) keeps the byte window; never split a rune.
+	for start < end && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	for end > start && end < len(text) && !utf8.RuneStart(text[end]) {
+		end--
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(text) {
+		suffix = "…"
+	}
+	snippet := prefix + text[start:end] + suffix
+
+	var shifted [][2]int
+	for _, h := range hits {
+		if h[0] >= start && h[1] <= end {
+			shifted = append(shifted, [2]int{h[0] - start + len(prefix), h[1] - start + len(prefix)})
+		}
+	}
+	return snippet, shifted
+}
+
+// HighlightSnippet wraps each highlighted range of snippet in open/close,
+// e.g. "**" for markdown or ANSI bold for terminals.
+func HighlightSnippet(snippet string, highlights [][2]int, open, close string) string {
+	if len(highlights) == 0 {
+		return snippet
+	}
+	var sb strings.Builder
+	prev := 0
+	for _, h := range highlights {
+		if h[0] < prev || h[1] > len(snippet) {
+			continue
+		}
+		sb.WriteString(snippet[prev:h[0]])
+		sb.WriteString(open)
+		sb.WriteString(snippet[h[0]:h[1]])
+		sb.WriteString(close)
+		prev = h[1]
+	}
+	sb.WriteString(snippet[prev:])
+	return sb.String()
+}
+
+// wordSpans returns the byte ranges of the letter/digit runs in text,
+// matching TokenizeText.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		if isTokenRune(r) {
+			if start < 0 {
+				start = i
+			}
+		} else if start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
@@ -0,0 +1,195 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestChunksFromIssue(t *testing.T) {
+	long := strings.Repeat("word ", maxChunkWords) + "\n\n" + strings.Repeat("more ", 10)
+	issue := model.Issue{
+		ID:                 "bv-1",
+		Title:              "Login timeout",
+		Description:        long,
+		AcceptanceCriteria: "Login completes in 2s",
+		Comments: []*model.Comment{
+			{Author: "alice", Text: "First"},
+			nil,
+			{Author: "bob", Text: "Third"},
+		},
+	}
+	chunks := ChunksFromIssue(issue)
+
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.ID)
+	}
+	want := []string{
+		"bv-1#title:0",
+		"bv-1#description:0",
+		"bv-1#description:1",
+		"bv-1#acceptance_criteria:0",
+		"bv-1#comment:0",
+		"bv-1#comment:1",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("chunk ids = %v, want %v", got, want)
+	}
+	last := chunks[len(chunks)-1]
+	if last.Comment != 3 || last.Author != "bob" || last.Text != "Third" {
+		t.Errorf("unexpected comment chunk: %+v", last)
+	}
+	if ChunksFromIssue(model.Issue{Title: "no id"}) != nil {
+		t.Error("expected no chunks without an ID")
+	}
+}
+
+func TestSplitChunkTextLongParagraph(t *testing.T) {
+	parts := splitChunkText(strings.Repeat("x ", maxChunkWords*2+5))
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(parts))
+	}
+	for _, p := range parts {
+		if n := len(strings.Fields(p)); n > maxChunkWords {
+			t.Errorf("chunk has %d words", n)
+		}
+	}
+}
+
+func TestChunkIndexBestMatches(t *testing.T) {
+	var comments []*model.Comment
+	for i := 0; i < 30; i++ {
+		comments = append(comments, &model.Comment{Author: "carol", Text: "nothing to see"})
+	}
+	comments[29] = &model.Comment{Author: "dave", Text: "Root cause: the oauth refresh token expires early"}
+	issues := []model.Issue{
+		{ID: "a", Title: "Auth flakiness", Description: "Users are logged out.", Comments: comments},
+		{ID: "b", Title: "Oauth settings page", Description: "Add a page."},
+		{ID: "c", Title: "Unrelated"},
+	}
+	ci := NewChunkIndex(issues)
+	matches := ci.BestMatches("oauth token", []string{"a", "b", "c"})
+
+	m, ok := matches["a"]
+	if !ok {
+		t.Fatalf("expected a match for a: %+v", matches)
+	}
+	if m.Field != ChunkFieldComment || m.Comment != 30 || m.Author != "dave" {
+		t.Errorf("expected the 30th comment, got %+v", m.Chunk)
+	}
+	hl := HighlightSnippet(m.Snippet, m.Highlights, "[", "]")
+	if !strings.Contains(hl, "[oauth]") || !strings.Contains(hl, "[token]") {
+		t.Errorf("expected highlighted terms, got %q", hl)
+	}
+	if matches["b"].Field != ChunkFieldTitle {
+		t.Errorf("expected title match for b, got %+v", matches["b"])
+	}
+	if _, ok := matches["c"]; ok {
+		t.Error("c should not match")
+	}
+	if got := ci.BestMatches("oauth", []string{"c"}); len(got) != 0 {
+		t.Errorf("matches outside issueIDs: %+v", got)
+	}
+}
+
+func TestChunkIndexSemanticMatches(t *testing.T) {
+	issues := []model.Issue{{
+		ID:          "a",
+		Title:       "Something",
+		Description: "kraken kraken kraken",
+		Comments:    []*model.Comment{{Text: "unrelated chatter"}},
+	}}
+	ci := NewChunkIndex(issues)
+	emb := NewHashEmbedder(256)
+	q, err := emb.Embed(context.Background(), []string{"kraken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := ci.SemanticMatches(context.Background(), emb, nil, q[0], []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := matches["a"]
+	if !m.Semantic || m.Field != ChunkFieldDescription {
+		t.Errorf("expected semantic description match, got %+v", m)
+	}
+}
+
+func TestChunkIndexEmbedChunksCaches(t *testing.T) {
+	ctx := context.Background()
+	issues := []model.Issue{
+		{ID: "a", Title: "Alpha", Description: "first"},
+		{ID: "b", Title: "Beta", Comments: []*model.Comment{{Text: "second"}}},
+	}
+	emb := NewHashEmbedder(64)
+	cache := NewVectorIndex(64)
+	ci := NewChunkIndex(issues)
+	if n, err := ci.EmbedChunks(ctx, emb, cache, []string{"a", "b"}); err != nil || n != 4 {
+		t.Fatalf("first embed: n=%d err=%v", n, err)
+	}
+	if n, _ := ci.EmbedChunks(ctx, emb, cache, []string{"a", "b"}); n != 0 {
+		t.Errorf("cached chunks were embedded again: %d", n)
+	}
+
+	issues[0].Description = "changed"
+	issues = issues[:1]
+	ci = NewChunkIndex(issues)
+	if n, _ := ci.EmbedChunks(ctx, emb, cache, []string{"a"}); n != 1 {
+		t.Errorf("only the changed chunk should be embedded, got %d", n)
+	}
+	if removed := ci.PruneVectors(cache); removed != 2 || cache.Size() != 2 {
+		t.Errorf("prune removed %d, %d left", removed, cache.Size())
+	}
+
+	path := ChunkVectorsPath(filepath.Join(t.TempDir(), "index-hash-64.bvvi"))
+	if err := cache.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := ci.EmbedChunks(ctx, emb, loaded, []string{"a"}); n != 0 {
+		t.Errorf("persisted cache was not reused: %d embedded", n)
+	}
+}
+
+func TestSnippetWindow(t *testing.T) {
+	text := strings.Repeat("filler ", 60) + "the needle is here " + strings.Repeat("padding ", 60)
+	snippet, highlights := Snippet(text, []string{"needle"}, 80)
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Errorf("expected ellipses on both ends: %q", snippet)
+	}
+	if len(highlights) != 1 || snippet[highlights[0][0]:highlights[0][1]] != "needle" {
+		t.Fatalf("bad highlights %v in %q", highlights, snippet)
+	}
+	if len(snippet) > 80+2*len("…") {
+		t.Errorf("snippet too long: %d", len(snippet))
+	}
+
+	// Text without spaces is cut on rune boundaries, whatever the window
+	cjk := strings.Repeat("索引数据", 30) + " 缓存 " + strings.Repeat("搜索结果", 30)
+	for maxLen := 10; maxLen < 40; maxLen++ {
+		for _, terms := range [][]string{nil, {"缓存"}} {
+			snippet, hl := Snippet(cjk, terms, maxLen)
+			if !utf8.ValidString(snippet) {
+				t.Fatalf("maxLen %d, terms %v: snippet splits a rune: %q", maxLen, terms, snippet)
+			}
+			for _, h := range hl {
+				if snippet[h[0]:h[1]] != "缓存" {
+					t.Errorf("maxLen %d: bad highlight %v in %q", maxLen, h, snippet)
+				}
+			}
+		}
+	}
+
+	short, hl := Snippet("Short\ntext  here", []string{"TEXT"}, 80)
+	if short != "Short text here" || len(hl) != 1 || short[hl[0][0]:hl[0][1]] != "text" {
+		t.Errorf("unexpected short snippet %q %v", short, hl)
+	}
+}
@@ -14,17 +14,32 @@ import (
 //   - BV_SEMANTIC_EMBEDDER: embedding provider (default: "hash")
 //   - BV_SEMANTIC_MODEL: model identifier (provider-specific, optional)
 //   - BV_SEMANTIC_DIM: embedding dimension (default: DefaultEmbeddingDim)
+//   - BV_SEMANTIC_ENDPOINT: base URL of an OpenAI-compatible API (default: DefaultOpenAIEndpoint)
+//   - BV_SEMANTIC_API_KEY: API key for the endpoint (falls back to OPENAI_API_KEY)
+//   - BV_SEMANTIC_COMMAND: embedding worker command line for python-sentence-transformers
+//   - BV_SEMANTIC_BATCH: texts per embedding request (default: DefaultEmbeddingBatchSize)
 func EmbeddingConfigFromEnv() EmbeddingConfig {
 	provider := strings.ToLower(strings.TrimSpace(os.Getenv(EnvSemanticEmbedder)))
 	cfg := EmbeddingConfig{
 		Provider: Provider(provider),
 		Model:    strings.TrimSpace(os.Getenv(EnvSemanticModel)),
+		Endpoint: strings.TrimSpace(os.Getenv(EnvSemanticEndpoint)),
+		APIKey:   strings.TrimSpace(os.Getenv(EnvSemanticAPIKey)),
+		Command:  strings.Fields(os.Getenv(EnvSemanticCommand)),
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = strings.TrimSpace(os.Getenv("OPENAI_API_KEY"))
 	}
 	if dimStr := os.Getenv(EnvSemanticDim); dimStr != "" {
 		if dim, err := strconv.Atoi(dimStr); err == nil {
 			cfg.Dim = dim
 		}
 	}
+	if batchStr := os.Getenv(EnvSemanticBatch); batchStr != "" {
+		if batch, err := strconv.Atoi(batchStr); err == nil {
+			cfg.BatchSize = batch
+		}
+	}
 	if cfg.Provider == "" {
 		cfg.Provider = ProviderHash
 	}
@@ -38,11 +53,20 @@ func NewEmbedderFromConfig(cfg EmbeddingConfig) (Embedder, error) {
 	case "", ProviderHash:
 		return NewHashEmbedder(cfg.Dim), nil
 	case ProviderPythonSentenceTransformers:
-		return nil, fmt.Errorf("semantic embedder %q not implemented (mvp placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
+		e, err := NewSubprocessEmbedder(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return e, nil
 	case ProviderOpenAI:
-		return nil, fmt.Errorf("semantic embedder %q not implemented (placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
+		e, err := NewOpenAIEmbedder(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return e, nil
 	default:
-		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q", cfg.Provider, ProviderHash)
+		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q, %q or %q",
+			cfg.Provider, ProviderHash, ProviderPythonSentenceTransformers, ProviderOpenAI)
 	}
 }
 
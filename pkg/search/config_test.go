@@ -336,16 +336,42 @@ func TestNewEmbedderFromConfig(t *testing.T) {
 			},
 		},
 		{
-			name:        "python-sentence-transformers not implemented",
-			cfg:         EmbeddingConfig{Provider: ProviderPythonSentenceTransformers, Dim: 384},
+			name:        "python-sentence-transformers missing worker",
+			cfg:         EmbeddingConfig{Provider: ProviderPythonSentenceTransformers, Dim: 384, Command: []string{"bv-no-such-embedder"}},
 			wantErr:     true,
-			errContains: "not implemented",
+			errContains: "bv-no-such-embedder",
 		},
 		{
-			name:        "openai not implemented",
+			name:    "python-sentence-transformers with worker command",
+			cfg:     EmbeddingConfig{Provider: ProviderPythonSentenceTransformers, Command: []string{os.Args[0]}},
+			wantErr: false,
+			checkEmbed: func(t *testing.T, e Embedder) {
+				if e.Provider() != ProviderPythonSentenceTransformers || e.Dim() != DefaultEmbeddingDim {
+					t.Errorf("unexpected embedder: %s/%d", e.Provider(), e.Dim())
+				}
+			},
+		},
+		{
+			name:        "openai requires api key for hosted endpoint",
 			cfg:         EmbeddingConfig{Provider: ProviderOpenAI, Dim: 1536},
 			wantErr:     true,
-			errContains: "not implemented",
+			errContains: "API key",
+		},
+		{
+			name:    "openai local endpoint without key",
+			cfg:     EmbeddingConfig{Provider: ProviderOpenAI, Dim: 768, Endpoint: "http://127.0.0.1:8080/v1/"},
+			wantErr: false,
+			checkEmbed: func(t *testing.T, e Embedder) {
+				if e.Provider() != ProviderOpenAI || e.Dim() != 768 {
+					t.Errorf("unexpected embedder: %s/%d", e.Provider(), e.Dim())
+				}
+			},
+		},
+		{
+			name:        "openai rejects non-http endpoint",
+			cfg:         EmbeddingConfig{Provider: ProviderOpenAI, Endpoint: "localhost:8080"},
+			wantErr:     true,
+			errContains: EnvSemanticEndpoint,
 		},
 		{
 			name:        "unknown provider error",
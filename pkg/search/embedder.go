@@ -1,6 +1,11 @@
 package search
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
 // Provider identifies an embedding backend.
 type Provider string
@@ -14,7 +19,8 @@ const (
 	// sentence-transformers to generate high-quality embeddings (MVP choice for bv-9gf).
 	ProviderPythonSentenceTransformers Provider = "python-sentence-transformers"
 
-	// ProviderOpenAI uses a hosted embedding API.
+	// ProviderOpenAI uses an OpenAI-compatible /embeddings HTTP API. The endpoint
+	// is configurable, so local stand-ins (llama.cpp, Ollama, vLLM) work too.
 	ProviderOpenAI Provider = "openai"
 )
 
@@ -24,20 +30,40 @@ const (
 	EnvSemanticEmbedder = "BV_SEMANTIC_EMBEDDER"
 	EnvSemanticModel    = "BV_SEMANTIC_MODEL"
 	EnvSemanticDim      = "BV_SEMANTIC_DIM"
+	EnvSemanticEndpoint = "BV_SEMANTIC_ENDPOINT"
+	EnvSemanticAPIKey   = "BV_SEMANTIC_API_KEY"
+	EnvSemanticCommand  = "BV_SEMANTIC_COMMAND"
+	EnvSemanticBatch    = "BV_SEMANTIC_BATCH"
 )
 
+// DefaultEmbeddingBatchSize is the number of texts sent per embedding request
+// by providers that talk to an external process or server.
+const DefaultEmbeddingBatchSize = 32
+
 // EmbeddingConfig captures embedder selection/configuration.
 // Provider implementations may ignore fields they don't use.
 type EmbeddingConfig struct {
 	Provider Provider
 	Model    string
 	Dim      int
+
+	// Endpoint is the base URL of an OpenAI-compatible API (ProviderOpenAI).
+	Endpoint string
+	// APIKey is sent as a bearer token (ProviderOpenAI). Optional for local servers.
+	APIKey string
+	// Command overrides the embedding subprocess (ProviderPythonSentenceTransformers).
+	Command []string
+	// BatchSize caps how many texts go into a single request.
+	BatchSize int
 }
 
 func (c EmbeddingConfig) Normalized() EmbeddingConfig {
 	if c.Dim <= 0 {
 		c.Dim = DefaultEmbeddingDim
 	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultEmbeddingBatchSize
+	}
 	return c
 }
 
@@ -47,3 +73,79 @@ type Embedder interface {
 	Dim() int
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
+
+// ModelIdentifier is implemented by embedders whose vectors depend on more
+// than provider and dimension (e.g. the model name). SyncVectorIndex discards
+// an index built under a different identifier.
+type ModelIdentifier interface {
+	ModelID() string
+}
+
+// EmbedderModelID returns the identifier recorded in a vector index for
+// vectors produced by e.
+func EmbedderModelID(e Embedder) string {
+	if mi, ok := e.(ModelIdentifier); ok {
+		return mi.ModelID()
+	}
+	return string(e.Provider())
+}
+
+// validateEmbeddings checks that an external backend returned one vector of
+// the configured dimension per input text.
+func validateEmbeddings(vecs [][]float32, texts, dim int) error {
+	if len(vecs) != texts {
+		return fmt.Errorf("embedder returned %d vectors for %d texts", len(vecs), texts)
+	}
+	for i, vec := range vecs {
+		if len(vec) != dim {
+			return fmt.Errorf("embedding %d has dimension %d, expected %d (set %s to match the model)", i, len(vec), dim, EnvSemanticDim)
+		}
+	}
+	return nil
+}
+
+// retryableError marks a failure worth retrying (transport errors, crashed
+// subprocess, HTTP 429/5xx).
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// withRetry runs fn up to 1+retries times, backing off exponentially between
+// retryable failures.
+func withRetry(ctx context.Context, retries int, backoff time.Duration, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		var re retryableError
+		if !errors.As(err, &re) || attempt >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// embedInBatches splits texts into chunks of at most size and concatenates
+// the results of embed.
+func embedInBatches(ctx context.Context, texts []string, size int, embed func([]string) ([][]float32, error)) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += size {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(start+size, len(texts))
+		vecs, err := embed(texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
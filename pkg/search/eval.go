@@ -0,0 +1,309 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Relevance evaluation for search tuning: judged queries are run through the
+// text pipeline once, then re-ranked under each weighting to compare MRR,
+// nDCG@k and recall@k.
+
+// DefaultEvalK is the default cutoff for nDCG@k and recall@k.
+const DefaultEvalK = 10
+
+// DefaultGridStep is the default weight increment for GridSearchWeights.
+const DefaultGridStep = 0.1
+
+// EvalQuery is one judged query. Relevant issues have grade 1 unless Grades
+// says otherwise; graded judgments only change nDCG.
+type EvalQuery struct {
+	Query    string         `json:"query" yaml:"query"`
+	Relevant []string       `json:"relevant" yaml:"relevant"`
+	Grades   map[string]int `json:"grades,omitempty" yaml:"grades,omitempty"`
+}
+
+func (q EvalQuery) grade(id string) int {
+	if g, ok := q.Grades[id]; ok {
+		return g
+	}
+	for _, r := range q.Relevant {
+		if r == id {
+			return 1
+		}
+	}
+	return 0
+}
+
+// LoadEvalQueries reads judged queries from a .yaml/.yml file (a list, or a
+// map with a "queries" list), a .json array, or .jsonl with one query per
+// line.
+func LoadEvalQueries(path string) ([]EvalQuery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []EvalQuery
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &queries); err != nil {
+			var doc struct {
+				Queries []EvalQuery `yaml:"queries"`
+			}
+			if err2 := yaml.Unmarshal(data, &doc); err2 != nil {
+				return nil, fmt.Errorf("parsing %s: %w", path, err)
+			}
+			queries = doc.Queries
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &queries); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			var q EvalQuery
+			if err := json.Unmarshal([]byte(text), &q); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			queries = append(queries, q)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, q := range queries {
+		if strings.TrimSpace(q.Query) == "" {
+			return nil, fmt.Errorf("%s: query %d is empty", path, i+1)
+		}
+		if len(q.Relevant) == 0 && len(q.Grades) == 0 {
+			return nil, fmt.Errorf("%s: query %q has no relevant issues", path, q.Query)
+		}
+		// Graded issues count as relevant even if not listed.
+		for id, g := range q.Grades {
+			if g > 0 && !containsString(q.Relevant, id) {
+				queries[i].Relevant = append(queries[i].Relevant, id)
+			}
+		}
+		sort.Strings(queries[i].Relevant)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("%s: no queries", path)
+	}
+	return queries, nil
+}
+
+// EvalMetrics are ranking metrics averaged over queries. MRR only credits a
+// relevant issue within the top K.
+type EvalMetrics struct {
+	Queries int     `json:"queries"`
+	K       int     `json:"k"`
+	MRR     float64 `json:"mrr"`
+	NDCG    float64 `json:"ndcg"`
+	Recall  float64 `json:"recall"`
+}
+
+// Better reports whether m ranks better than other: higher nDCG, then MRR.
+func (m EvalMetrics) Better(other EvalMetrics) bool {
+	const eps = 1e-9
+	if math.Abs(m.NDCG-other.NDCG) > eps {
+		return m.NDCG > other.NDCG
+	}
+	return m.MRR > other.MRR+eps
+}
+
+// EvaluateRankings scores rankings[i] (issue IDs, best first) against
+// queries[i].
+func EvaluateRankings(queries []EvalQuery, rankings [][]string, k int) EvalMetrics {
+	if k <= 0 {
+		k = DefaultEvalK
+	}
+	m := EvalMetrics{Queries: len(queries), K: k}
+	if len(queries) == 0 {
+		return m
+	}
+	for i, q := range queries {
+		var ranking []string
+		if i < len(rankings) {
+			ranking = rankings[i]
+		}
+		rr, ndcg, recall := evalQuery(q, ranking, k)
+		m.MRR += rr
+		m.NDCG += ndcg
+		m.Recall += recall
+	}
+	n := float64(len(queries))
+	m.MRR /= n
+	m.NDCG /= n
+	m.Recall /= n
+	return m
+}
+
+func evalQuery(q EvalQuery, ranking []string, k int) (rr, ndcg, recall float64) {
+	if len(ranking) > k {
+		ranking = ranking[:k]
+	}
+	var dcg float64
+	found := 0
+	for i, id := range ranking {
+		g := q.grade(id)
+		if g <= 0 {
+			continue
+		}
+		if rr == 0 {
+			rr = 1 / float64(i+1)
+		}
+		found++
+		dcg += (math.Pow(2, float64(g)) - 1) / math.Log2(float64(i+2))
+	}
+
+	ideal := make([]int, 0, len(q.Relevant))
+	for _, id := range q.Relevant {
+		ideal = append(ideal, q.grade(id))
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ideal)))
+	var idcg float64
+	for i, g := range ideal {
+		if i >= k {
+			break
+		}
+		idcg += (math.Pow(2, float64(g)) - 1) / math.Log2(float64(i+2))
+	}
+	if idcg > 0 {
+		ndcg = dcg / idcg
+	}
+	if len(q.Relevant) > 0 {
+		recall = float64(found) / float64(len(q.Relevant))
+	}
+	return rr, ndcg, recall
+}
+
+// EvalCandidates are the text-scored candidates retrieved for one query.
+type EvalCandidates struct {
+	Query   EvalQuery
+	Text    string         // Free text of the query, which drives AdjustWeightsForQuery
+	Results []SearchResult // Text score order
+}
+
+// EvaluateText ranks candidates by text score alone.
+func EvaluateText(cands []EvalCandidates, k int) EvalMetrics {
+	queries := make([]EvalQuery, len(cands))
+	rankings := make([][]string, len(cands))
+	for i, c := range cands {
+		queries[i] = c.Query
+		rankings[i] = make([]string, len(c.Results))
+		for j, r := range c.Results {
+			rankings[i][j] = r.IssueID
+		}
+	}
+	return EvaluateRankings(queries, rankings, k)
+}
+
+// EvaluateHybrid re-ranks candidates with HybridScorer under weights, the way
+// hybrid search does, and scores the result.
+func EvaluateHybrid(cands []EvalCandidates, weights Weights, cache MetricsCache, k int) (EvalMetrics, error) {
+	queries := make([]EvalQuery, len(cands))
+	rankings := make([][]string, len(cands))
+	normalized := weights.Normalize()
+	for i, c := range cands {
+		queries[i] = c.Query
+		scorer := NewHybridScorer(AdjustWeightsForQuery(normalized, c.Text), cache)
+		scored := make([]SearchResult, 0, len(c.Results))
+		for _, r := range c.Results {
+			s, err := scorer.Score(r.IssueID, r.Score)
+			if err != nil {
+				return EvalMetrics{}, err
+			}
+			scored = append(scored, SearchResult{IssueID: r.IssueID, Score: s.FinalScore})
+		}
+		sortResults(scored)
+		rankings[i] = make([]string, len(scored))
+		for j, r := range scored {
+			rankings[i][j] = r.IssueID
+		}
+	}
+	return EvaluateRankings(queries, rankings, k), nil
+}
+
+// GridSearchWeights evaluates every weighting on a grid of the given step
+// (weights summing to 1, text at least 0.1) and returns the best one
+// with its metrics and the number of weightings tried.
+func GridSearchWeights(cands []EvalCandidates, cache MetricsCache, k int, step float64) (Weights, EvalMetrics, int, error) {
+	// Below 0.05 the grid grows past ~50k weightings.
+	if step < 0.05 || step > 0.5 {
+		return Weights{}, EvalMetrics{}, 0, fmt.Errorf("grid step must be in [0.05, 0.5], got %g", step)
+	}
+	units := int(math.Round(1 / step))
+	minText := max(1, int(math.Ceil(0.1*float64(units)-1e-9)))
+	var best Weights
+	var bestMetrics EvalMetrics
+	tried := 0
+	var err error
+
+	// Enumerate compositions of units into 6 parts.
+	var parts [6]int
+	var walk func(dim, remaining int)
+	walk = func(dim, remaining int) {
+		if err != nil {
+			return
+		}
+		if dim == len(parts)-1 {
+			parts[dim] = remaining
+			w := Weights{
+				TextRelevance: float64(parts[0]) / float64(units),
+				PageRank:      float64(parts[1]) / float64(units),
+				Status:        float64(parts[2]) / float64(units),
+				Impact:        float64(parts[3]) / float64(units),
+				Priority:      float64(parts[4]) / float64(units),
+				Recency:       float64(parts[5]) / float64(units),
+			}
+			m, evalErr := EvaluateHybrid(cands, w, cache, k)
+			if evalErr != nil {
+				err = evalErr
+				return
+			}
+			if tried == 0 || m.Better(bestMetrics) {
+				best, bestMetrics = w, m
+			}
+			tried++
+			return
+		}
+		lo := 0
+		if dim == 0 {
+			lo = minText
+		}
+		for v := lo; v <= remaining; v++ {
+			parts[dim] = v
+			walk(dim+1, remaining-v)
+		}
+	}
+	walk(0, units)
+	return best, bestMetrics, tried, err
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package search
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEvaluateRankings(t *testing.T) {
+	queries := []EvalQuery{
+		{Query: "a", Relevant: []string{"x", "y"}},
+		{Query: "b", Relevant: []string{"z"}, Grades: map[string]int{"z": 2}},
+	}
+	rankings := [][]string{
+		{"n", "x", "y"}, // first hit at rank 2
+		{"n", "n2"},     // miss
+	}
+	m := EvaluateRankings(queries, rankings, 3)
+
+	if m.Queries != 2 || m.K != 3 {
+		t.Fatalf("unexpected header %+v", m)
+	}
+	if math.Abs(m.MRR-0.25) > 1e-9 {
+		t.Errorf("MRR = %f, want 0.25", m.MRR)
+	}
+	if math.Abs(m.Recall-0.5) > 1e-9 {
+		t.Errorf("Recall = %f, want 0.5", m.Recall)
+	}
+	dcg := 1/math.Log2(3) + 1/math.Log2(4)
+	idcg := 1 + 1/math.Log2(3)
+	if want := dcg / idcg / 2; math.Abs(m.NDCG-want) > 1e-9 {
+		t.Errorf("NDCG = %f, want %f", m.NDCG, want)
+	}
+
+	// Hits past k do not count.
+	if m := EvaluateRankings(queries[:1], [][]string{{"n", "x"}}, 1); m.MRR != 0 || m.Recall != 0 {
+		t.Errorf("expected no credit past k: %+v", m)
+	}
+}
+
+func TestEvalMetricsBetter(t *testing.T) {
+	a := EvalMetrics{NDCG: 0.5, MRR: 0.4}
+	if !a.Better(EvalMetrics{NDCG: 0.4, MRR: 0.9}) {
+		t.Error("higher nDCG should win")
+	}
+	if !a.Better(EvalMetrics{NDCG: 0.5, MRR: 0.3}) {
+		t.Error("equal nDCG should fall back to MRR")
+	}
+	if a.Better(a) {
+		t.Error("equal metrics are not better")
+	}
+}
+
+func TestLoadEvalQueries(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	yamlPath := write("q.yaml", `queries:
+  - query: login timeout
+    relevant: [bv-2, bv-1]
+  - query: export
+    grades: {bv-3: 2}
+`)
+	qs, err := LoadEvalQueries(yamlPath)
+	if err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	if len(qs) != 2 || qs[0].Relevant[0] != "bv-1" || len(qs[1].Relevant) != 1 || qs[1].grade("bv-3") != 2 {
+		t.Fatalf("unexpected yaml queries: %+v", qs)
+	}
+
+	listPath := write("list.yml", "- query: a\n  relevant: [x]\n")
+	if qs, err := LoadEvalQueries(listPath); err != nil || len(qs) != 1 {
+		t.Fatalf("yaml list: %v %+v", err, qs)
+	}
+
+	jsonlPath := write("q.jsonl", `# judged queries
+{"query":"a","relevant":["x"]}
+
+{"query":"b","relevant":["y","z"]}
+`)
+	if qs, err := LoadEvalQueries(jsonlPath); err != nil || len(qs) != 2 {
+		t.Fatalf("jsonl: %v %+v", err, qs)
+	}
+
+	for name, content := range map[string]string{
+		"empty.jsonl":    "",
+		"norel.jsonl":    `{"query":"a"}`,
+		"noquery.jsonl":  `{"relevant":["x"]}`,
+		"bad.jsonl":      `{"query":`,
+		"invalid.yaml":   "queries: [",
+		"wrongtype.json": `{"query":"a"}`,
+	} {
+		if _, err := LoadEvalQueries(write(name, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestEvaluateHybridAndGridSearch(t *testing.T) {
+	now := time.Now()
+	cache := &stubMetricsCache{metrics: map[string]IssueMetrics{
+		"old":   {IssueID: "old", Status: "closed", Priority: 4, UpdatedAt: now.AddDate(-2, 0, 0)},
+		"fresh": {IssueID: "fresh", Status: "open", Priority: 0, PageRank: 1, UpdatedAt: now},
+	}}
+	// Text ranks "old" first, but the judged answer is the open, high
+	// priority "fresh": any graph-aware weighting should fix that.
+	cands := []EvalCandidates{{
+		Query:   EvalQuery{Query: "flaky deploy pipeline on staging", Relevant: []string{"fresh"}},
+		Text:    "flaky deploy pipeline on staging",
+		Results: []SearchResult{{IssueID: "old", Score: 0.9}, {IssueID: "fresh", Score: 0.85}},
+	}}
+
+	if m := EvaluateText(cands, 10); m.MRR != 0.5 {
+		t.Fatalf("text MRR = %f, want 0.5", m.MRR)
+	}
+	textOnly, _ := GetPreset(PresetTextOnly)
+	if m, err := EvaluateHybrid(cands, textOnly, cache, 10); err != nil || m.MRR != 0.5 {
+		t.Fatalf("text-only preset MRR = %f (%v)", m.MRR, err)
+	}
+	impact, _ := GetPreset(PresetImpactFirst)
+	if m, err := EvaluateHybrid(cands, impact, cache, 10); err != nil || m.MRR != 1 {
+		t.Fatalf("impact-first MRR = %f (%v)", m.MRR, err)
+	}
+
+	best, m, tried, err := GridSearchWeights(cands, cache, 10, 0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MRR != 1 || best.TextRelevance < 0.1 {
+		t.Errorf("grid best %+v scored %+v", best, m)
+	}
+	// Compositions of 4 units into 6 parts with text >= 1: C(8,5) = 56.
+	if tried != 56 {
+		t.Errorf("tried %d weightings, want 56", tried)
+	}
+	if _, _, _, err := GridSearchWeights(cands, cache, 10, 0.01); err == nil {
+		t.Error("expected error for too fine a grid")
+	}
+}
@@ -0,0 +1,567 @@
+package search
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// Approximate nearest-neighbour search over a VectorIndex using a Hierarchical
+// Navigable Small World graph (Malkov & Yashunin, 2016). Similarity is the dot
+// product, matching the exact scan (vectors are L2-normalized by embedders).
+//
+// The graph is kept in memory alongside the entries, updated on Upsert/Remove,
+// and persisted to a sidecar file next to the BVVI index (see ANNIndexPath).
+// Removals leave tombstones that still route searches but never appear in
+// results; the graph is rebuilt once tombstones outnumber live nodes.
+
+const (
+	// DefaultANNThreshold is the index size at which SearchTopK switches from
+	// the exact scan to the HNSW graph.
+	DefaultANNThreshold = 10000
+
+	hnswM              = 16  // Links per node on upper levels (2*M on level 0)
+	hnswEfConstruction = 100 // Candidate list size while inserting
+	hnswEfSearch       = 64  // Minimum candidate list size while searching
+
+	hnswMagic   = "BVHN"
+	hnswVersion = uint16(1)
+)
+
+// ANNIndexPath returns the sidecar path holding the HNSW graph for the BVVI
+// index at indexPath.
+func ANNIndexPath(indexPath string) string {
+	return indexPath + ".hnsw"
+}
+
+type hnswNode struct {
+	id      string
+	hash    ContentHash
+	vec     []float32
+	links   [][]int32 // links[level] are neighbour node indexes
+	deleted bool
+}
+
+type hnswIndex struct {
+	m              int
+	efConstruction int
+	efSearch       int
+	levelMult      float64
+
+	nodes    []hnswNode
+	byID     map[string]int32 // Live nodes only
+	entry    int32            // -1 when empty
+	maxLevel int
+	deleted  int
+	rng      *rand.Rand
+}
+
+func newHNSWIndex() *hnswIndex {
+	return &hnswIndex{
+		m:              hnswM,
+		efConstruction: hnswEfConstruction,
+		efSearch:       hnswEfSearch,
+		levelMult:      1 / math.Log(hnswM),
+		byID:           make(map[string]int32),
+		entry:          -1,
+		// Fixed seed keeps graph construction reproducible.
+		rng: rand.New(rand.NewSource(1)),
+	}
+}
+
+// buildHNSWIndex indexes entries in sorted ID order for determinism.
+func buildHNSWIndex(ids []string, entries map[string]VectorEntry) *hnswIndex {
+	h := newHNSWIndex()
+	h.nodes = make([]hnswNode, 0, len(ids))
+	for _, id := range ids {
+		if e, ok := entries[id]; ok {
+			h.insert(id, e.ContentHash, e.Vector)
+		}
+	}
+	return h
+}
+
+func (h *hnswIndex) live() int { return len(h.nodes) - h.deleted }
+
+// needsRebuild reports whether tombstones have degraded the graph enough
+// that rebuilding from the live entries is worthwhile.
+func (h *hnswIndex) needsRebuild() bool {
+	return h.deleted > h.live()
+}
+
+func (h *hnswIndex) maxLinks(level int) int {
+	if level == 0 {
+		return 2 * h.m
+	}
+	return h.m
+}
+
+func (h *hnswIndex) randomLevel() int {
+	return int(math.Floor(-math.Log(1-h.rng.Float64()) * h.levelMult))
+}
+
+func (h *hnswIndex) remove(id string) {
+	n, ok := h.byID[id]
+	if !ok {
+		return
+	}
+	h.nodes[n].deleted = true
+	delete(h.byID, id)
+	h.deleted++
+}
+
+// insert adds vec under id, replacing any previous vector for id.
+func (h *hnswIndex) insert(id string, hash ContentHash, vec []float32) {
+	h.remove(id)
+
+	level := h.randomLevel()
+	n := int32(len(h.nodes))
+	h.nodes = append(h.nodes, hnswNode{id: id, hash: hash, vec: vec, links: make([][]int32, level+1)})
+	h.byID[id] = n
+	if h.entry < 0 {
+		h.entry, h.maxLevel = n, level
+		return
+	}
+
+	ep := h.entry
+	for lc := h.maxLevel; lc > level; lc-- {
+		ep = h.greedyClosest(vec, ep, lc)
+	}
+	for lc := min(level, h.maxLevel); lc >= 0; lc-- {
+		cands := h.searchLayer(vec, ep, h.efConstruction, lc)
+		neighbours := h.selectNeighbours(cands, h.m)
+		h.nodes[n].links[lc] = neighbours
+		for _, nb := range neighbours {
+			h.addLink(nb, n, lc)
+		}
+		ep = cands[0].node
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = n, level
+	}
+}
+
+// addLink connects from -> to on level, pruning from's links if it now has
+// too many.
+func (h *hnswIndex) addLink(from, to int32, level int) {
+	node := &h.nodes[from]
+	node.links[level] = append(node.links[level], to)
+	limit := h.maxLinks(level)
+	if len(node.links[level]) <= limit {
+		return
+	}
+	cands := make([]hnswCandidate, len(node.links[level]))
+	for i, nb := range node.links[level] {
+		cands[i] = hnswCandidate{node: nb, score: hnswDot(node.vec, h.nodes[nb].vec)}
+	}
+	sortCandidates(cands)
+	node.links[level] = h.selectNeighbours(cands, limit)
+}
+
+// selectNeighbours applies the HNSW neighbour heuristic: a candidate is kept
+// only if it is closer to the query than to any already selected neighbour,
+// which spreads links across clusters. Remaining slots are filled with the
+// best pruned candidates. cands must be sorted best first.
+func (h *hnswIndex) selectNeighbours(cands []hnswCandidate, m int) []int32 {
+	selected := make([]int32, 0, m)
+	var pruned []int32
+	for _, c := range cands {
+		if len(selected) >= m {
+			break
+		}
+		keep := true
+		for _, s := range selected {
+			if hnswDot(h.nodes[c.node].vec, h.nodes[s].vec) > c.score {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			selected = append(selected, c.node)
+		} else {
+			pruned = append(pruned, c.node)
+		}
+	}
+	for _, p := range pruned {
+		if len(selected) >= m {
+			break
+		}
+		selected = append(selected, p)
+	}
+	return selected
+}
+
+func (h *hnswIndex) greedyClosest(q []float32, ep int32, level int) int32 {
+	best := hnswDot(q, h.nodes[ep].vec)
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range h.nodes[ep].links[level] {
+			if s := hnswDot(q, h.nodes[nb].vec); s > best {
+				best, ep, changed = s, nb, true
+			}
+		}
+	}
+	return ep
+}
+
+// searchLayer returns up to ef nodes closest to q on level, best first.
+func (h *hnswIndex) searchLayer(q []float32, ep int32, ef int, level int) []hnswCandidate {
+	visited := make([]uint64, (len(h.nodes)+63)/64)
+	visit := func(n int32) bool {
+		word, bit := n/64, uint64(1)<<(n%64)
+		if visited[word]&bit != 0 {
+			return false
+		}
+		visited[word] |= bit
+		return true
+	}
+
+	start := hnswCandidate{node: ep, score: hnswDot(q, h.nodes[ep].vec)}
+	visit(ep)
+	frontier := &candidateHeap{best: true, items: []hnswCandidate{start}}
+	results := &candidateHeap{items: []hnswCandidate{start}}
+
+	for frontier.Len() > 0 {
+		c := heap.Pop(frontier).(hnswCandidate)
+		if results.Len() >= ef && c.score < results.items[0].score {
+			break
+		}
+		for _, nb := range h.nodes[c.node].links[level] {
+			if !visit(nb) {
+				continue
+			}
+			s := hnswDot(q, h.nodes[nb].vec)
+			if results.Len() < ef || s > results.items[0].score {
+				heap.Push(frontier, hnswCandidate{node: nb, score: s})
+				heap.Push(results, hnswCandidate{node: nb, score: s})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := results.items
+	sortCandidates(out)
+	return out
+}
+
+// search returns the approximate top-k live nodes for q.
+func (h *hnswIndex) search(q []float32, k int) []SearchResult {
+	if h.entry < 0 || h.live() == 0 {
+		return nil
+	}
+	ef := max(h.efSearch, k)
+	// Tombstones occupy candidate slots; widen the beam proportionally.
+	ef += ef * h.deleted / len(h.nodes)
+
+	ep := h.entry
+	for lc := h.maxLevel; lc > 0; lc-- {
+		ep = h.greedyClosest(q, ep, lc)
+	}
+	cands := h.searchLayer(q, ep, ef, 0)
+
+	results := make([]SearchResult, 0, k)
+	for _, c := range cands {
+		node := h.nodes[c.node]
+		if node.deleted {
+			continue
+		}
+		// Re-score in float64 so scores match the exact scan.
+		results = append(results, SearchResult{IssueID: node.id, Score: dotFloat32(q, node.vec)})
+	}
+	// Match the exact scan's deterministic ordering: score desc, ID asc.
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].IssueID < results[j].IssueID
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// hnswDot is a float32 dot product, unrolled for speed. Graph traversal only
+// compares scores, so float32 precision is enough.
+func hnswDot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		s0 += a[i] * b[i]
+		s1 += a[i+1] * b[i+1]
+		s2 += a[i+2] * b[i+2]
+		s3 += a[i+3] * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return float64(s0 + s1 + s2 + s3)
+}
+
+type hnswCandidate struct {
+	node  int32
+	score float64
+}
+
+func sortCandidates(c []hnswCandidate) {
+	sort.Slice(c, func(i, j int) bool {
+		if c[i].score != c[j].score {
+			return c[i].score > c[j].score
+		}
+		return c[i].node < c[j].node
+	})
+}
+
+// candidateHeap is a max-heap by score when best is set, otherwise a min-heap
+// (worst result on top).
+type candidateHeap struct {
+	items []hnswCandidate
+	best  bool
+}
+
+func (h *candidateHeap) Len() int { return len(h.items) }
+func (h *candidateHeap) Less(i, j int) bool {
+	if h.best {
+		return h.items[i].score > h.items[j].score
+	}
+	return h.items[i].score < h.items[j].score
+}
+func (h *candidateHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *candidateHeap) Push(x any)    { h.items = append(h.items, x.(hnswCandidate)) }
+func (h *candidateHeap) Pop() any {
+	old := h.items
+	x := old[len(old)-1]
+	h.items = old[:len(old)-1]
+	return x
+}
+
+// ============================================================================
+// Persistence
+// ============================================================================
+
+// writeHNSW serializes the graph. Live nodes reference vectors stored in the
+// BVVI file by ID and content hash; tombstones carry their own vectors since
+// they are still needed for routing.
+func writeHNSW(path string, h *hnswIndex, dim int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "bvhn-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	w := bufio.NewWriter(tmp)
+	le := binary.LittleEndian
+	header := []any{
+		hnswVersion, uint16(h.m), uint16(h.efConstruction), uint16(h.efSearch),
+		uint32(dim), uint32(len(h.nodes)), h.entry, uint16(h.maxLevel),
+	}
+	if _, err := w.WriteString(hnswMagic); err != nil {
+		return fmt.Errorf("write magic: %w", err)
+	}
+	for _, v := range header {
+		if err := binary.Write(w, le, v); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+	}
+
+	for _, node := range h.nodes {
+		if len(node.id) > math.MaxUint16 {
+			return fmt.Errorf("issue id too long: %d", len(node.id))
+		}
+		if err := binary.Write(w, le, uint16(len(node.id))); err != nil {
+			return fmt.Errorf("write id len: %w", err)
+		}
+		if _, err := w.WriteString(node.id); err != nil {
+			return fmt.Errorf("write id: %w", err)
+		}
+		if _, err := w.Write(node.hash[:]); err != nil {
+			return fmt.Errorf("write content hash: %w", err)
+		}
+		flags := uint8(0)
+		if node.deleted {
+			flags = 1
+		}
+		if err := binary.Write(w, le, flags); err != nil {
+			return fmt.Errorf("write flags: %w", err)
+		}
+		if node.deleted {
+			for _, v := range node.vec {
+				if err := binary.Write(w, le, math.Float32bits(v)); err != nil {
+					return fmt.Errorf("write vector: %w", err)
+				}
+			}
+		}
+		if err := binary.Write(w, le, uint8(len(node.links))); err != nil {
+			return fmt.Errorf("write levels: %w", err)
+		}
+		for _, links := range node.links {
+			if err := binary.Write(w, le, uint16(len(links))); err != nil {
+				return fmt.Errorf("write link count: %w", err)
+			}
+			if err := binary.Write(w, le, links); err != nil {
+				return fmt.Errorf("write links: %w", err)
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("flush: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp: %w", err)
+	}
+	if runtime.GOOS == "windows" {
+		_ = os.Remove(path)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
+}
+
+// readHNSW loads a graph and binds it to the entries of idx. It fails if the
+// graph does not describe exactly the live entries (same IDs and content
+// hashes), in which case the caller should rebuild.
+func readHNSW(path string, dim int, entries map[string]VectorEntry) (*hnswIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	r := bufio.NewReader(f)
+	le := binary.LittleEndian
+
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(magic[:]) != hnswMagic {
+		return nil, fmt.Errorf("invalid magic %q", string(magic[:]))
+	}
+	var hdr struct {
+		Version        uint16
+		M              uint16
+		EfConstruction uint16
+		EfSearch       uint16
+		Dim            uint32
+		Count          uint32
+		Entry          int32
+		MaxLevel       uint16
+	}
+	if err := binary.Read(r, le, &hdr); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if hdr.Version != hnswVersion {
+		return nil, fmt.Errorf("unsupported version %d", hdr.Version)
+	}
+	if int(hdr.Dim) != dim {
+		return nil, fmt.Errorf("graph dim %d does not match index dim %d", hdr.Dim, dim)
+	}
+	if hdr.M == 0 || hdr.Entry < -1 || hdr.Entry >= int32(hdr.Count) {
+		return nil, fmt.Errorf("invalid graph header")
+	}
+
+	h := newHNSWIndex()
+	h.m, h.efConstruction, h.efSearch = int(hdr.M), int(hdr.EfConstruction), int(hdr.EfSearch)
+	h.levelMult = 1 / math.Log(float64(max(h.m, 2)))
+	h.entry, h.maxLevel = hdr.Entry, int(hdr.MaxLevel)
+	h.rng = rand.New(rand.NewSource(int64(hdr.Count) + 1))
+	h.nodes = make([]hnswNode, hdr.Count)
+
+	for i := range h.nodes {
+		node := &h.nodes[i]
+		var idLen uint16
+		if err := binary.Read(r, le, &idLen); err != nil {
+			return nil, fmt.Errorf("read id len: %w", err)
+		}
+		id := make([]byte, idLen)
+		if _, err := io.ReadFull(r, id); err != nil {
+			return nil, fmt.Errorf("read id: %w", err)
+		}
+		node.id = string(id)
+		if _, err := io.ReadFull(r, node.hash[:]); err != nil {
+			return nil, fmt.Errorf("read content hash: %w", err)
+		}
+		var flags uint8
+		if err := binary.Read(r, le, &flags); err != nil {
+			return nil, fmt.Errorf("read flags: %w", err)
+		}
+		if flags&1 != 0 {
+			node.deleted = true
+			h.deleted++
+			node.vec = make([]float32, dim)
+			for j := range node.vec {
+				var bits uint32
+				if err := binary.Read(r, le, &bits); err != nil {
+					return nil, fmt.Errorf("read vector: %w", err)
+				}
+				node.vec[j] = math.Float32frombits(bits)
+			}
+		} else {
+			entry, ok := entries[node.id]
+			if !ok || entry.ContentHash != node.hash {
+				return nil, fmt.Errorf("graph is stale for %s", node.id)
+			}
+			if _, dup := h.byID[node.id]; dup {
+				return nil, fmt.Errorf("duplicate live node %s", node.id)
+			}
+			node.vec = entry.Vector
+			h.byID[node.id] = int32(i)
+		}
+
+		var levels uint8
+		if err := binary.Read(r, le, &levels); err != nil {
+			return nil, fmt.Errorf("read levels: %w", err)
+		}
+		if levels == 0 || int(levels) > int(hdr.MaxLevel)+1 {
+			return nil, fmt.Errorf("invalid level count %d", levels)
+		}
+		node.links = make([][]int32, levels)
+		for lc := range node.links {
+			var n uint16
+			if err := binary.Read(r, le, &n); err != nil {
+				return nil, fmt.Errorf("read link count: %w", err)
+			}
+			links := make([]int32, n)
+			if err := binary.Read(r, le, links); err != nil {
+				return nil, fmt.Errorf("read links: %w", err)
+			}
+			node.links[lc] = links
+		}
+	}
+
+	// Links must point at nodes that exist on that level.
+	for _, node := range h.nodes {
+		for lc, links := range node.links {
+			for _, nb := range links {
+				if nb < 0 || int(nb) >= len(h.nodes) || len(h.nodes[nb].links) <= lc {
+					return nil, fmt.Errorf("invalid link to %d on level %d", nb, lc)
+				}
+			}
+		}
+	}
+	if len(h.byID) != len(entries) {
+		return nil, fmt.Errorf("graph has %d live nodes, index has %d entries", len(h.byID), len(entries))
+	}
+	if h.entry >= 0 && len(h.nodes[h.entry].links) != h.maxLevel+1 {
+		return nil, fmt.Errorf("entry point level mismatch")
+	}
+	return h, nil
+}
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+)
+
+// BenchmarkVectorIndexSearchTopK compares the exact scan with the HNSW graph
+// and reports recall@10 of the graph against the scan.
+func BenchmarkVectorIndexSearchTopK(b *testing.B) {
+	for _, size := range []int{10000, 50000} {
+		vecs := clusteredVectors(size, 384, 1)
+		queries := clusteredVectors(64, 384, 2)
+
+		exact := buildTestIndex(b, vecs, -1)
+		b.Run(fmt.Sprintf("exact_%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = exact.SearchTopK(queries[i%len(queries)], 10)
+			}
+		})
+
+		ann := buildTestIndex(b, vecs, 1)
+		ann.ensureANN()
+		b.Run(fmt.Sprintf("hnsw_%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = ann.SearchTopK(queries[i%len(queries)], 10)
+			}
+			b.StopTimer()
+			b.ReportMetric(recallAt(b, ann, queries, 10), "recall@10")
+		})
+	}
+}
+
+func BenchmarkHNSWBuild(b *testing.B) {
+	vecs := clusteredVectors(5000, 384, 1)
+	idx := buildTestIndex(b, vecs, 1)
+	ids := idx.sortedIDs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = buildHNSWIndex(ids, idx.entries)
+	}
+}
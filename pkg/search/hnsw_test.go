@@ -0,0 +1,223 @@
+package search
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+// clusteredVectors returns n normalized vectors drawn around a fixed set of
+// centroids in a low-dimensional subspace, which resembles real embeddings
+// (low intrinsic dimension) far better than uniform noise. Different seeds
+// sample different points from the same distribution.
+func clusteredVectors(n, dim int, seed int64) [][]float32 {
+	const latentDim, clusters = 12, 24
+	shape := rand.New(rand.NewSource(0))
+	projection := make([][]float64, latentDim)
+	for i := range projection {
+		projection[i] = make([]float64, dim)
+		for j := range projection[i] {
+			projection[i][j] = shape.NormFloat64()
+		}
+	}
+	centroids := make([][]float64, clusters)
+	for i := range centroids {
+		centroids[i] = make([]float64, latentDim)
+		for j := range centroids[i] {
+			centroids[i][j] = shape.NormFloat64() * 2
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	out := make([][]float32, n)
+	for i := range out {
+		c := centroids[rng.Intn(clusters)]
+		vec := make([]float32, dim)
+		for l := 0; l < latentDim; l++ {
+			z := c[l] + rng.NormFloat64()
+			for j := range vec {
+				vec[j] += float32(z * projection[l][j])
+			}
+		}
+		for j := range vec {
+			vec[j] += float32(rng.NormFloat64() * 0.5)
+		}
+		normalizeL2(vec)
+		out[i] = vec
+	}
+	return out
+}
+
+func buildTestIndex(tb testing.TB, vecs [][]float32, threshold int) *VectorIndex {
+	tb.Helper()
+	idx := NewVectorIndex(len(vecs[0]))
+	idx.ANNThreshold = threshold
+	for i, vec := range vecs {
+		id := fmt.Sprintf("issue-%d", i)
+		if err := idx.Upsert(id, ComputeContentHash(id), vec); err != nil {
+			tb.Fatalf("Upsert: %v", err)
+		}
+	}
+	return idx
+}
+
+// recallAt compares approximate results with the exact scan.
+func recallAt(tb testing.TB, idx *VectorIndex, queries [][]float32, k int) float64 {
+	tb.Helper()
+	hits, total := 0, 0
+	for _, q := range queries {
+		approx, err := idx.SearchTopK(q, k)
+		if err != nil {
+			tb.Fatalf("SearchTopK: %v", err)
+		}
+		exact := idx.searchExact(q, k, nil)
+		want := make(map[string]bool, len(exact))
+		for _, r := range exact {
+			want[r.IssueID] = true
+		}
+		for _, r := range approx {
+			if want[r.IssueID] {
+				hits++
+			}
+		}
+		total += len(exact)
+	}
+	return float64(hits) / float64(total)
+}
+
+func TestHNSW_RecallAgainstExactScan(t *testing.T) {
+	vecs := clusteredVectors(3000, 32, 1)
+	idx := buildTestIndex(t, vecs, 1000)
+	if !idx.UsesANN() {
+		t.Fatal("expected ANN above threshold")
+	}
+
+	queries := clusteredVectors(100, 32, 2)
+	if recall := recallAt(t, idx, queries, 10); recall < 0.95 {
+		t.Errorf("recall@10 = %.3f, want >= 0.95", recall)
+	}
+}
+
+func TestHNSW_FallsBackBelowThreshold(t *testing.T) {
+	vecs := clusteredVectors(200, 8, 3)
+
+	idx := buildTestIndex(t, vecs, 0)
+	if idx.UsesANN() {
+		t.Error("default threshold should use the exact scan for 200 entries")
+	}
+	if _, err := idx.SearchTopK(vecs[0], 5); err != nil {
+		t.Fatal(err)
+	}
+	if idx.ann != nil {
+		t.Error("graph should not be built below the threshold")
+	}
+
+	idx.ANNThreshold = -1
+	if idx.UsesANN() {
+		t.Error("negative threshold should disable ANN")
+	}
+}
+
+func TestHNSW_IncrementalUpsertAndRemove(t *testing.T) {
+	vecs := clusteredVectors(600, 16, 4)
+	idx := buildTestIndex(t, vecs[:500], 100)
+	if _, err := idx.SearchTopK(vecs[0], 1); err != nil || idx.ann == nil {
+		t.Fatalf("expected graph after first search: %v", err)
+	}
+	graph := idx.ann
+
+	// New entries are linked into the existing graph and are findable.
+	for i := 500; i < 600; i++ {
+		id := fmt.Sprintf("issue-%d", i)
+		if err := idx.Upsert(id, ComputeContentHash(id), vecs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 500; i < 600; i += 10 {
+		res, _ := idx.SearchTopK(vecs[i], 1)
+		if len(res) != 1 || res[0].IssueID != fmt.Sprintf("issue-%d", i) {
+			t.Fatalf("inserted vector %d not found: %+v", i, res)
+		}
+	}
+
+	// Removed entries never come back, even though they still route searches.
+	for i := 0; i < 100; i++ {
+		idx.Remove(fmt.Sprintf("issue-%d", i))
+	}
+	for i := 0; i < 100; i += 7 {
+		res, _ := idx.SearchTopK(vecs[i], 10)
+		for _, r := range res {
+			var n int
+			fmt.Sscanf(r.IssueID, "issue-%d", &n)
+			if n < 100 {
+				t.Fatalf("removed entry %s returned", r.IssueID)
+			}
+		}
+	}
+	if idx.ann != graph {
+		t.Error("graph should be updated in place, not rebuilt")
+	}
+
+	// Updating a vector replaces its node.
+	moved := vecs[550]
+	if err := idx.Upsert("issue-150", ComputeContentHash("moved"), moved); err != nil {
+		t.Fatal(err)
+	}
+	res, _ := idx.SearchTopK(moved, 2)
+	if len(res) != 2 || (res[0].IssueID != "issue-150" && res[1].IssueID != "issue-150") {
+		t.Errorf("updated vector not found: %+v", res)
+	}
+
+	// Once tombstones dominate, the next search rebuilds the graph.
+	for i := 100; i < 400; i++ {
+		idx.Remove(fmt.Sprintf("issue-%d", i))
+	}
+	if _, err := idx.SearchTopK(vecs[450], 5); err != nil {
+		t.Fatal(err)
+	}
+	if idx.ann == graph || idx.ann.deleted != 0 {
+		t.Error("expected a compacted graph after heavy removal")
+	}
+}
+
+func TestHNSW_PersistedNextToIndex(t *testing.T) {
+	vecs := clusteredVectors(400, 16, 5)
+	idx := buildTestIndex(t, vecs, 100)
+	idx.ensureANN()
+	idx.Remove("issue-3") // persist a tombstone too
+	query := vecs[42]
+	want, _ := idx.SearchTopK(query, 5)
+
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex: %v", err)
+	}
+	if loaded.ann == nil || loaded.ann.deleted != 1 {
+		t.Fatal("expected graph with its tombstone to be loaded from sidecar")
+	}
+	loaded.ANNThreshold = 100
+	got, _ := loaded.SearchTopK(query, 5)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("results differ after reload:\n got %v\nwant %v", got, want)
+	}
+
+	// A graph that no longer matches the entries is ignored.
+	if err := loaded.Upsert("issue-7", ComputeContentHash("changed"), vecs[8]); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.saveEntries(path); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex: %v", err)
+	}
+	if stale.ann != nil {
+		t.Error("stale graph should be discarded")
+	}
+}
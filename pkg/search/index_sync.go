@@ -29,10 +29,13 @@ type IndexSyncStats struct {
 	Removed  int `json:"removed"`
 	Skipped  int `json:"skipped"`
 	Embedded int `json:"embedded"`
+	// Invalidated is set when the index was built by a different embedder
+	// model and was discarded before syncing.
+	Invalidated bool `json:"invalidated,omitempty"`
 }
 
 func (s IndexSyncStats) Changed() bool {
-	return s.Added+s.Updated+s.Removed > 0
+	return s.Invalidated || s.Added+s.Updated+s.Removed > 0
 }
 
 // LoadOrNewVectorIndex loads an existing vector index if present, otherwise creates a new one.
@@ -65,8 +68,9 @@ func LoadOrNewVectorIndex(path string, dim int) (*VectorIndex, bool, error) {
 
 // SyncVectorIndex updates idx to match docs using embedder, incrementally embedding only changed items.
 //
-// This is intended for offline, deterministic embedding providers. Callers should persist idx
-// with (*VectorIndex).Save when desired.
+// Vectors from different models are not comparable, so an index whose Model differs from the
+// embedder's (see EmbedderModelID) is cleared first. Indexes without a recorded model adopt the
+// embedder's. Callers should persist idx with (*VectorIndex).Save when desired.
 func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, docs map[string]string, batchSize int) (IndexSyncStats, error) {
 	var stats IndexSyncStats
 	if idx == nil {
@@ -84,6 +88,8 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 
 	stats.Total = len(docs)
 
+	stats.Invalidated = idx.adoptModel(EmbedderModelID(embedder))
+
 	// Remove stale IDs.
 	docIDs := make(map[string]struct{}, len(docs))
 	for id := range docs {
@@ -0,0 +1,183 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOpenAIEndpoint is the base URL used when no endpoint is configured.
+	DefaultOpenAIEndpoint = "https://api.openai.com/v1"
+	// DefaultOpenAIModel is used when no model is configured.
+	DefaultOpenAIModel = "text-embedding-3-small"
+)
+
+// OpenAIEmbedder calls an OpenAI-compatible POST {endpoint}/embeddings API.
+type OpenAIEmbedder struct {
+	endpoint  string
+	apiKey    string
+	model     string
+	dim       int
+	batchSize int
+	client    *http.Client
+
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewOpenAIEmbedder creates an HTTP embedder. The API key is optional so that
+// unauthenticated local servers can be used.
+func NewOpenAIEmbedder(cfg EmbeddingConfig) (*OpenAIEmbedder, error) {
+	cfg = cfg.Normalized()
+	endpoint := strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultOpenAIEndpoint
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("invalid %s %q: expected http(s) URL", EnvSemanticEndpoint, cfg.Endpoint)
+	}
+	if endpoint == DefaultOpenAIEndpoint && cfg.APIKey == "" {
+		return nil, fmt.Errorf("semantic embedder %q needs an API key (%s or OPENAI_API_KEY); set %s=%q for deterministic fallback",
+			ProviderOpenAI, EnvSemanticAPIKey, EnvSemanticEmbedder, ProviderHash)
+	}
+	model := cfg.Model
+	if model == "" {
+		model = DefaultOpenAIModel
+	}
+	return &OpenAIEmbedder{
+		endpoint:   endpoint,
+		apiKey:     cfg.APIKey,
+		model:      model,
+		dim:        cfg.Dim,
+		batchSize:  cfg.BatchSize,
+		client:     &http.Client{Timeout: 60 * time.Second},
+		maxRetries: 3,
+		backoff:    500 * time.Millisecond,
+	}, nil
+}
+
+func (*OpenAIEmbedder) Provider() Provider { return ProviderOpenAI }
+func (e *OpenAIEmbedder) Dim() int         { return e.dim }
+
+// ModelID identifies the endpoint and model so that switching either
+// invalidates existing vectors.
+func (e *OpenAIEmbedder) ModelID() string {
+	return fmt.Sprintf("%s:%s@%s", ProviderOpenAI, e.model, e.endpoint)
+}
+
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedInBatches(ctx, texts, e.batchSize, func(batch []string) ([][]float32, error) {
+		var vecs [][]float32
+		err := withRetry(ctx, e.maxRetries, e.backoff, func() error {
+			var err error
+			vecs, err = e.embedBatch(ctx, batch)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := validateEmbeddings(vecs, len(batch), e.dim); err != nil {
+			return nil, err
+		}
+		for _, vec := range vecs {
+			normalizeL2(vec)
+		}
+		return vecs, nil
+	})
+}
+
+type openAIEmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+// requestDimensions returns the output size to ask the API for. The
+// text-embedding-3 models default to 1536 or 3072 dimensions but can shorten
+// their vectors, so they are asked for the configured size; other models
+// (and most local servers) reject or ignore the field and are left alone.
+func (e *OpenAIEmbedder) requestDimensions() int {
+	if strings.HasPrefix(e.model, "text-embedding-3") {
+		return e.dim
+	}
+	return 0
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (e *OpenAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(openAIEmbeddingRequest{Model: e.model, Input: texts, Dimensions: e.requestDimensions()})
+	if err != nil {
+		return nil, fmt.Errorf("encode embedding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, retryableError{fmt.Errorf("embedding request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 256<<20))
+	if err != nil {
+		return nil, retryableError{fmt.Errorf("read embedding response: %w", err)}
+	}
+	var parsed openAIEmbeddingResponse
+	decodeErr := json.Unmarshal(raw, &parsed)
+
+	if resp.StatusCode != http.StatusOK {
+		msg := strings.TrimSpace(string(raw))
+		if decodeErr == nil && parsed.Error != nil && parsed.Error.Message != "" {
+			msg = parsed.Error.Message
+		}
+		err := fmt.Errorf("embedding request failed: %s: %s", resp.Status, truncateErrorBody(msg))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, retryableError{err}
+		}
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("decode embedding response: %w", decodeErr)
+	}
+
+	// The API may return items out of order; index is authoritative.
+	sort.SliceStable(parsed.Data, func(i, j int) bool { return parsed.Data[i].Index < parsed.Data[j].Index })
+	vecs := make([][]float32, len(parsed.Data))
+	for i, d := range parsed.Data {
+		vecs[i] = d.Embedding
+	}
+	return vecs, nil
+}
+
+func truncateErrorBody(s string) string {
+	const limit = 300
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "..."
+}
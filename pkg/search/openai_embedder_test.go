@@ -0,0 +1,214 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeEmbeddingsServer is a minimal OpenAI-compatible /embeddings stand-in.
+// failFirst requests get a 503 before it starts answering.
+func fakeEmbeddingsServer(t *testing.T, dim int, failFirst int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if r.URL.Path != "/v1/embeddings" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"bad key"}}`))
+			return
+		}
+		if n <= failFirst {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req openAIEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "local-model" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		data := make([]item, len(req.Input))
+		for i, text := range req.Input {
+			vec := make([]float32, dim)
+			vec[len(text)%dim] = 2 // not normalized; the client normalizes
+			// Answer in reverse order to exercise index sorting.
+			data[len(req.Input)-1-i] = item{Index: i, Embedding: vec}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newTestOpenAIEmbedder(t *testing.T, url, key string, dim int) *OpenAIEmbedder {
+	t.Helper()
+	e, err := NewOpenAIEmbedder(EmbeddingConfig{
+		Provider:  ProviderOpenAI,
+		Model:     "local-model",
+		Dim:       dim,
+		Endpoint:  url + "/v1",
+		APIKey:    key,
+		BatchSize: 3,
+	})
+	if err != nil {
+		t.Fatalf("NewOpenAIEmbedder: %v", err)
+	}
+	e.backoff = time.Millisecond
+	return e
+}
+
+func TestOpenAIEmbedder_BatchesAndNormalizes(t *testing.T) {
+	srv, calls := fakeEmbeddingsServer(t, 4, 0)
+	e := newTestOpenAIEmbedder(t, srv.URL, "test-key", 4)
+
+	vecs, err := e.Embed(context.Background(), []string{"a", "bb", "ccc", "dddd"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 4 {
+		t.Fatalf("expected 4 vectors, got %d", len(vecs))
+	}
+	if vecs[0][1] != 1 || vecs[2][3] != 1 || vecs[3][0] != 1 {
+		t.Errorf("vectors out of order or not normalized: %v", vecs)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 batched requests, got %d", got)
+	}
+}
+
+func TestOpenAIEmbedder_RetriesServerErrors(t *testing.T) {
+	srv, calls := fakeEmbeddingsServer(t, 4, 2)
+	e := newTestOpenAIEmbedder(t, srv.URL, "test-key", 4)
+
+	if _, err := e.Embed(context.Background(), []string{"x"}); err != nil {
+		t.Fatalf("Embed should succeed after retries: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+
+	srv2, calls2 := fakeEmbeddingsServer(t, 4, 100)
+	e2 := newTestOpenAIEmbedder(t, srv2.URL, "test-key", 4)
+	if _, err := e2.Embed(context.Background(), []string{"x"}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected 503 after exhausting retries, got %v", err)
+	}
+	if got := calls2.Load(); got != int32(1+e2.maxRetries) {
+		t.Errorf("expected %d attempts, got %d", 1+e2.maxRetries, got)
+	}
+}
+
+func TestOpenAIEmbedder_ClientErrorsAreNotRetried(t *testing.T) {
+	srv, calls := fakeEmbeddingsServer(t, 4, 0)
+	e := newTestOpenAIEmbedder(t, srv.URL, "wrong-key", 4)
+
+	_, err := e.Embed(context.Background(), []string{"x"})
+	if err == nil || !strings.Contains(err.Error(), "bad key") {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestOpenAIEmbedder_ValidatesDimension(t *testing.T) {
+	srv, _ := fakeEmbeddingsServer(t, 8, 0)
+	e := newTestOpenAIEmbedder(t, srv.URL, "test-key", 4)
+
+	_, err := e.Embed(context.Background(), []string{"x"})
+	if err == nil || !strings.Contains(err.Error(), "dimension 8, expected 4") || !strings.Contains(err.Error(), EnvSemanticDim) {
+		t.Fatalf("expected dimension error, got %v", err)
+	}
+
+	idx := NewVectorIndex(8)
+	if _, err := SyncVectorIndex(context.Background(), idx, e, map[string]string{"A": "x"}, 0); err == nil {
+		t.Fatal("expected index/embedder dim mismatch error")
+	}
+}
+
+func TestOpenAIEmbedder_DefaultModelRequestsConfiguredDim(t *testing.T) {
+	// Behaves like the real API: text-embedding-3 models return 1536
+	// dimensions unless the request asks for fewer.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != DefaultOpenAIModel {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		dim := 1536
+		if req.Dimensions > 0 {
+			dim = req.Dimensions
+		}
+		data := make([]map[string]any, len(req.Input))
+		for i := range req.Input {
+			vec := make([]float32, dim)
+			vec[0] = 1
+			data[i] = map[string]any{"index": i, "embedding": vec}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv(EnvSemanticEmbedder, "openai")
+	t.Setenv(EnvSemanticEndpoint, srv.URL+"/v1")
+	t.Setenv(EnvSemanticAPIKey, "test-key")
+	t.Setenv(EnvSemanticModel, "")
+	t.Setenv(EnvSemanticDim, "")
+	e, err := NewEmbedderFromConfig(EmbeddingConfigFromEnv())
+	if err != nil {
+		t.Fatalf("NewEmbedderFromConfig: %v", err)
+	}
+
+	vecs, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed with the default model and dim: %v", err)
+	}
+	if len(vecs) != 2 || len(vecs[0]) != DefaultEmbeddingDim || e.Dim() != DefaultEmbeddingDim {
+		t.Errorf("expected 2 vectors of dim %d, got %d of dim %d", DefaultEmbeddingDim, len(vecs), len(vecs[0]))
+	}
+}
+
+func TestOpenAIEmbedder_ModelIDIncludesEndpoint(t *testing.T) {
+	e := newTestOpenAIEmbedder(t, "http://localhost:1234", "", 4)
+	if got := EmbedderModelID(e); got != "openai:local-model@http://localhost:1234/v1" {
+		t.Errorf("EmbedderModelID = %q", got)
+	}
+	if got := EmbedderModelID(NewHashEmbedder(4)); got != "hash" {
+		t.Errorf("hash EmbedderModelID = %q", got)
+	}
+}
+
+func TestEmbeddingConfigFromEnv_ProviderSettings(t *testing.T) {
+	t.Setenv(EnvSemanticEmbedder, "openai")
+	t.Setenv(EnvSemanticEndpoint, "http://localhost:8080/v1")
+	t.Setenv(EnvSemanticAPIKey, "")
+	t.Setenv("OPENAI_API_KEY", "from-openai-env")
+	t.Setenv(EnvSemanticCommand, "python3 -u worker.py")
+	t.Setenv(EnvSemanticBatch, "8")
+
+	cfg := EmbeddingConfigFromEnv()
+	if cfg.Endpoint != "http://localhost:8080/v1" || cfg.APIKey != "from-openai-env" || cfg.BatchSize != 8 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if len(cfg.Command) != 3 || cfg.Command[2] != "worker.py" {
+		t.Errorf("unexpected command: %v", cfg.Command)
+	}
+
+	t.Setenv(EnvSemanticAPIKey, "bv-key")
+	t.Setenv(EnvSemanticBatch, "")
+	if cfg := EmbeddingConfigFromEnv(); cfg.APIKey != "bv-key" || cfg.BatchSize != DefaultEmbeddingBatchSize {
+		t.Errorf("BV_SEMANTIC_API_KEY should win and batch default: %+v", cfg)
+	}
+}
@@ -0,0 +1,414 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Structured search queries, shared by --search, --robot-search and the TUI
+// "/" filter. A query mixes free text with field filters:
+//
+//	status:open label:backend priority:<2 assignee:me -label:wontfix
+//	"exact phrase" updated:>7d blocks:bv-12 login timeout
+//
+// Filters are ANDed; comma-separated values within one filter are ORed
+// (status:open,blocked). A leading "-" negates a filter, word or phrase.
+// Free-text words rank results and are never required to match.
+
+// Query operators for ordered fields (priority and dates).
+const (
+	OpEq = "="
+	OpLt = "<"
+	OpLe = "<="
+	OpGt = ">"
+	OpGe = ">="
+)
+
+// queryFieldAliases maps accepted field names to their canonical form.
+var queryFieldAliases = map[string]string{
+	"status":     "status",
+	"is":         "status",
+	"label":      "label",
+	"labels":     "label",
+	"priority":   "priority",
+	"p":          "priority",
+	"type":       "type",
+	"assignee":   "assignee",
+	"id":         "id",
+	"updated":    "updated",
+	"created":    "created",
+	"closed":     "closed",
+	"blocks":     "blocks",
+	"blocked-by": "blocked-by",
+	"depends":    "blocked-by",
+}
+
+// QueryFilter is one field condition of a Query.
+type QueryFilter struct {
+	Field  string   `json:"field"`
+	Op     string   `json:"op"`
+	Values []string `json:"values"`
+	Negate bool     `json:"negate,omitempty"`
+
+	priority int           // Parsed value for priority filters
+	age      time.Duration // Relative date value ("7d"), resolved against QueryContext.Now
+	at       time.Time     // Absolute date value ("2025-01-31")
+}
+
+// Query is a parsed search query.
+type Query struct {
+	Raw      string        `json:"raw"`
+	Terms    []string      `json:"terms,omitempty"`    // Free-text words, used for ranking
+	Phrases  []string      `json:"phrases,omitempty"`  // Quoted text that must appear
+	Excluded []string      `json:"excluded,omitempty"` // Negated words and phrases that must not appear
+	Filters  []QueryFilter `json:"filters,omitempty"`
+}
+
+// ParseQuery parses raw into a Query. Tokens that look like filters on
+// unknown fields (e.g. "http://x") are kept as free text; malformed values
+// on known fields are errors.
+func ParseQuery(raw string) (Query, error) {
+	q := Query{Raw: raw}
+	for _, tok := range splitQueryTokens(raw) {
+		negate := false
+		if len(tok) > 1 && tok[0] == '-' {
+			negate = true
+			tok = tok[1:]
+		}
+
+		if strings.HasPrefix(tok, `"`) {
+			phrase := strings.ToLower(strings.TrimSpace(strings.Trim(tok, `"`)))
+			if phrase == "" {
+				continue
+			}
+			if negate {
+				q.Excluded = append(q.Excluded, phrase)
+			} else {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+			continue
+		}
+
+		if name, value, ok := strings.Cut(tok, ":"); ok {
+			if field, known := queryFieldAliases[strings.ToLower(name)]; known {
+				f, err := parseQueryFilter(field, value)
+				if err != nil {
+					return Query{}, err
+				}
+				f.Negate = negate
+				q.Filters = append(q.Filters, f)
+				continue
+			}
+		}
+
+		word := strings.ToLower(tok)
+		if negate {
+			q.Excluded = append(q.Excluded, word)
+		} else {
+			q.Terms = append(q.Terms, word)
+		}
+	}
+	return q, nil
+}
+
+// QueryText returns the free text of raw (words and phrases) with filters
+// removed, or raw itself if it does not parse.
+func QueryText(raw string) string {
+	q, err := ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	return q.Text()
+}
+
+// Text returns the words and phrases used for ranking.
+func (q Query) Text() string {
+	return strings.Join(append(append([]string(nil), q.Terms...), q.Phrases...), " ")
+}
+
+// HasConstraints reports whether the query restricts which issues match
+// (filters, phrases or exclusions), as opposed to only ranking them.
+func (q Query) HasConstraints() bool {
+	return len(q.Filters) > 0 || len(q.Phrases) > 0 || len(q.Excluded) > 0
+}
+
+// splitQueryTokens splits on whitespace, keeping double-quoted sections
+// (including a field value like label:"needs review") together.
+func splitQueryTokens(raw string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuote := false
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+func parseQueryFilter(field, value string) (QueryFilter, error) {
+	f := QueryFilter{Field: field, Op: OpEq}
+	switch field {
+	case "priority", "updated", "created", "closed":
+		for _, op := range []string{OpLe, OpGe, OpLt, OpGt, OpEq} {
+			if strings.HasPrefix(value, op) {
+				f.Op = op
+				value = value[len(op):]
+				break
+			}
+		}
+	}
+	value = strings.Trim(value, `"`)
+	if value == "" {
+		return QueryFilter{}, fmt.Errorf("search filter %s: missing value", field)
+	}
+
+	switch field {
+	case "priority":
+		p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(value), "P"))
+		if err != nil || p < 0 {
+			return QueryFilter{}, fmt.Errorf("search filter priority: invalid value %q (expected 0-4 or P0-P4)", value)
+		}
+		f.priority = p
+		f.Values = []string{strconv.Itoa(p)}
+	case "updated", "created", "closed":
+		if err := f.parseDate(value); err != nil {
+			return QueryFilter{}, err
+		}
+		if f.Op == OpEq && f.at.IsZero() {
+			f.Op = OpGe // updated:7d reads as "within the last 7 days"
+		}
+		f.Values = []string{value}
+	default:
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+				f.Values = append(f.Values, v)
+			}
+		}
+		if len(f.Values) == 0 {
+			return QueryFilter{}, fmt.Errorf("search filter %s: missing value", field)
+		}
+	}
+	return f, nil
+}
+
+// parseDate accepts an age ("36h", "7d", "2w") or a date ("2025-01-31").
+func (f *QueryFilter) parseDate(value string) error {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		f.at = t
+		return nil
+	}
+	unit := value[len(value)-1]
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err == nil && n >= 0 {
+		switch unit {
+		case 'h':
+			f.age = time.Duration(n) * time.Hour
+			return nil
+		case 'd':
+			f.age = time.Duration(n) * 24 * time.Hour
+			return nil
+		case 'w':
+			f.age = time.Duration(n) * 7 * 24 * time.Hour
+			return nil
+		}
+	}
+	return fmt.Errorf("search filter %s: invalid date %q (expected e.g. 7d, 2w, 36h or 2025-01-31)", f.Field, value)
+}
+
+// QueryContext carries what filters need beyond the issue itself.
+type QueryContext struct {
+	Now time.Time
+	Me  string // Resolves assignee:me
+
+	blocks map[string]map[string]bool // blocker ID -> IDs it blocks
+}
+
+// NewQueryContext indexes blocking dependencies of issues for blocks: filters.
+func NewQueryContext(issues []model.Issue, me string, now time.Time) *QueryContext {
+	qc := &QueryContext{Now: now, Me: me, blocks: make(map[string]map[string]bool)}
+	for _, iss := range issues {
+		for _, dep := range iss.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			blocker := strings.ToLower(dep.DependsOnID)
+			if qc.blocks[blocker] == nil {
+				qc.blocks[blocker] = make(map[string]bool)
+			}
+			qc.blocks[blocker][strings.ToLower(iss.ID)] = true
+		}
+	}
+	return qc
+}
+
+// Match reports whether issue satisfies every filter, phrase and exclusion
+// of q. Free-text terms are not required to match.
+func (q Query) Match(issue model.Issue, qc *QueryContext) bool {
+	if qc == nil {
+		qc = &QueryContext{Now: time.Now()}
+	}
+	for _, f := range q.Filters {
+		if f.match(issue, qc) == f.Negate {
+			return false
+		}
+	}
+	if len(q.Phrases) == 0 && len(q.Excluded) == 0 {
+		return true
+	}
+	text := strings.ToLower(IssueFieldsFor(issue).Text())
+	for _, p := range q.Phrases {
+		if !strings.Contains(text, p) {
+			return false
+		}
+	}
+	for _, x := range q.Excluded {
+		if containsWord(text, x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f QueryFilter) match(issue model.Issue, qc *QueryContext) bool {
+	switch f.Field {
+	case "status":
+		return f.anyValue(func(v string) bool { return strings.EqualFold(string(issue.Status), v) })
+	case "label":
+		return f.anyValue(func(v string) bool {
+			for _, l := range issue.Labels {
+				if strings.EqualFold(l, v) {
+					return true
+				}
+			}
+			return false
+		})
+	case "type":
+		return f.anyValue(func(v string) bool { return strings.EqualFold(string(issue.IssueType), v) })
+	case "assignee":
+		return f.anyValue(func(v string) bool {
+			switch v {
+			case "me":
+				return qc.Me != "" && strings.EqualFold(issue.Assignee, qc.Me)
+			case "none":
+				return issue.Assignee == ""
+			}
+			return strings.EqualFold(issue.Assignee, v)
+		})
+	case "id":
+		return f.anyValue(func(v string) bool { return strings.EqualFold(issue.ID, v) })
+	case "blocks":
+		return f.anyValue(func(v string) bool { return qc.blocks[strings.ToLower(issue.ID)][v] })
+	case "blocked-by":
+		return f.anyValue(func(v string) bool {
+			for _, dep := range issue.Dependencies {
+				if dep != nil && dep.Type.IsBlocking() && strings.EqualFold(dep.DependsOnID, v) {
+					return true
+				}
+			}
+			return false
+		})
+	case "priority":
+		return compareOrdered(f.Op, issue.Priority-f.priority)
+	case "updated":
+		return f.matchDate(issue.UpdatedAt, qc.Now)
+	case "created":
+		return f.matchDate(issue.CreatedAt, qc.Now)
+	case "closed":
+		if issue.ClosedAt == nil {
+			return false
+		}
+		return f.matchDate(*issue.ClosedAt, qc.Now)
+	}
+	return false
+}
+
+func (f QueryFilter) anyValue(pred func(string) bool) bool {
+	for _, v := range f.Values {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDate compares timestamps, later being greater: updated:>7d means
+// updated after 7 days ago, created:<2025-01-01 means created before then.
+// A bare date with "=" matches that whole day.
+func (f QueryFilter) matchDate(t time.Time, now time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	ref := f.at
+	if ref.IsZero() {
+		ref = now.Add(-f.age)
+	}
+	if f.Op == OpEq && !f.at.IsZero() {
+		y1, m1, d1 := t.UTC().Date()
+		y2, m2, d2 := ref.Date()
+		return y1 == y2 && m1 == m2 && d1 == d2
+	}
+	switch {
+	case t.Before(ref):
+		return compareOrdered(f.Op, -1)
+	case t.After(ref):
+		return compareOrdered(f.Op, 1)
+	default:
+		return compareOrdered(f.Op, 0)
+	}
+}
+
+// compareOrdered applies op to the sign of (actual - wanted).
+func compareOrdered(op string, diff int) bool {
+	switch op {
+	case OpLt:
+		return diff < 0
+	case OpLe:
+		return diff <= 0
+	case OpGt:
+		return diff > 0
+	case OpGe:
+		return diff >= 0
+	default:
+		return diff == 0
+	}
+}
+
+// containsWord reports whether needle occurs in text at word boundaries.
+func containsWord(text, needle string) bool {
+	for start := 0; ; {
+		i := strings.Index(text[start:], needle)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(needle)
+		before := i == 0 || !isQueryWordByte(text[i-1])
+		after := end == len(text) || !isQueryWordByte(text[end])
+		if before && after {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+func isQueryWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '_' || b >= 0x80
+}
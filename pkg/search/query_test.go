@@ -0,0 +1,152 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery(`status:open label:backend priority:<2 assignee:me -label:wontfix "exact phrase" updated:>7d blocks:bv-12 login -flaky`)
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if !reflect.DeepEqual(q.Terms, []string{"login"}) {
+		t.Errorf("terms = %v", q.Terms)
+	}
+	if !reflect.DeepEqual(q.Phrases, []string{"exact phrase"}) {
+		t.Errorf("phrases = %v", q.Phrases)
+	}
+	if !reflect.DeepEqual(q.Excluded, []string{"flaky"}) {
+		t.Errorf("excluded = %v", q.Excluded)
+	}
+
+	type f struct {
+		field, op string
+		negate    bool
+	}
+	var got []f
+	for _, filter := range q.Filters {
+		got = append(got, f{filter.Field, filter.Op, filter.Negate})
+	}
+	want := []f{
+		{"status", OpEq, false},
+		{"label", OpEq, false},
+		{"priority", OpLt, false},
+		{"assignee", OpEq, false},
+		{"label", OpEq, true},
+		{"updated", OpGt, false},
+		{"blocks", OpEq, false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filters = %+v, want %+v", got, want)
+	}
+	if q.Text() != "login exact phrase" {
+		t.Errorf("Text() = %q", q.Text())
+	}
+	if !q.HasConstraints() {
+		t.Error("expected constraints")
+	}
+}
+
+func TestParseQueryUnknownFieldIsText(t *testing.T) {
+	q, err := ParseQuery("see http://example.com foo:bar")
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if len(q.Filters) != 0 || q.HasConstraints() {
+		t.Fatalf("unexpected filters: %+v", q.Filters)
+	}
+	if q.Text() != "see http://example.com foo:bar" {
+		t.Errorf("Text() = %q", q.Text())
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, raw := range []string{"priority:high", "updated:>soon", "status:", "created:7y"} {
+		if _, err := ParseQuery(raw); err == nil {
+			t.Errorf("ParseQuery(%q): expected error", raw)
+		}
+	}
+	if got := QueryText("status: login"); got != "status: login" {
+		t.Errorf("QueryText falls back to raw on error, got %q", got)
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	closed := now.Add(-48 * time.Hour)
+	issues := []model.Issue{
+		{
+			ID: "bv-1", Title: "Login timeout", Status: model.StatusOpen, Priority: 1,
+			IssueType: model.TypeBug, Assignee: "alice", Labels: []string{"backend"},
+			Description: "Users see an exact phrase here", UpdatedAt: now.Add(-2 * 24 * time.Hour),
+			CreatedAt: now.Add(-30 * 24 * time.Hour),
+		},
+		{
+			ID: "bv-2", Title: "Docs", Status: model.StatusClosed, Priority: 3,
+			IssueType: model.TypeTask, Labels: []string{"docs", "wontfix"},
+			UpdatedAt: now.Add(-20 * 24 * time.Hour), CreatedAt: now.Add(-40 * 24 * time.Hour),
+			ClosedAt: &closed,
+		},
+		{
+			ID: "bv-12", Title: "Blocked work", Status: model.StatusBlocked, Priority: 0,
+			Dependencies: []*model.Dependency{
+				{IssueID: "bv-12", DependsOnID: "bv-1", Type: model.DepBlocks},
+				{IssueID: "bv-12", DependsOnID: "bv-2", Type: model.DepRelated},
+			},
+			Comments:  []*model.Comment{{Text: "Flaky in CI"}},
+			UpdatedAt: now, CreatedAt: now,
+		},
+	}
+	qc := NewQueryContext(issues, "Alice", now)
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"status:open", []string{"bv-1"}},
+		{"status:open,blocked", []string{"bv-1", "bv-12"}},
+		{"-status:closed", []string{"bv-1", "bv-12"}},
+		{"label:backend", []string{"bv-1"}},
+		{"-label:wontfix", []string{"bv-1", "bv-12"}},
+		{"priority:<2", []string{"bv-1", "bv-12"}},
+		{"priority:>=P3", []string{"bv-2"}},
+		{"priority:1", []string{"bv-1"}},
+		{"type:bug", []string{"bv-1"}},
+		{"assignee:me", []string{"bv-1"}},
+		{"assignee:none", []string{"bv-2", "bv-12"}},
+		{"updated:>7d", []string{"bv-1", "bv-12"}},
+		{"updated:7d", []string{"bv-1", "bv-12"}},
+		{"updated:<1w", []string{"bv-2"}},
+		{"created:<2025-05-10", []string{"bv-2"}},
+		{"created:2025-06-15", []string{"bv-12"}},
+		{"closed:>3d", []string{"bv-2"}},
+		{"blocks:bv-12", []string{"bv-1"}},
+		{"blocked-by:bv-1", []string{"bv-12"}},
+		{"blocked-by:bv-2", nil},
+		{"id:BV-2", []string{"bv-2"}},
+		{`"exact phrase"`, []string{"bv-1"}},
+		{`-"exact phrase"`, []string{"bv-2", "bv-12"}},
+		{"-flaky", []string{"bv-1", "bv-2"}},
+		{"login anything", []string{"bv-1", "bv-2", "bv-12"}},
+		{"status:open label:backend priority:<2 assignee:me -label:wontfix updated:>7d", []string{"bv-1"}},
+	}
+	for _, tc := range cases {
+		q, err := ParseQuery(tc.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", tc.query, err)
+		}
+		var got []string
+		for _, iss := range issues {
+			if q.Match(iss, qc) {
+				got = append(got, iss.ID)
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q matched %v, want %v", tc.query, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+# Embedding worker for bv's python-sentence-transformers provider.
+#
+# Protocol (JSON lines over stdin/stdout):
+#   request:  {"id": 1, "model": "...", "texts": ["...", ...]}
+#   response: {"id": 1, "embeddings": [[...], ...]}  or  {"id": 1, "error": "..."}
+# The process serves requests until stdin is closed.
+import json
+import sys
+
+_models = {}
+
+
+def load(name):
+    if name not in _models:
+        from sentence_transformers import SentenceTransformer
+
+        _models[name] = SentenceTransformer(name)
+    return _models[name]
+
+
+def main():
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+        req_id = None
+        try:
+            req = json.loads(line)
+            req_id = req.get("id")
+            model = load(req["model"])
+            vecs = model.encode(req.get("texts", []), normalize_embeddings=True)
+            resp = {"id": req_id, "embeddings": [v.tolist() for v in vecs]}
+        except Exception as exc:  # report to bv instead of dying
+            resp = {"id": req_id, "error": "%s: %s" % (type(exc).__name__, exc)}
+        sys.stdout.write(json.dumps(resp) + "\n")
+        sys.stdout.flush()
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,231 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSentenceTransformersModel produces DefaultEmbeddingDim-sized vectors.
+const DefaultSentenceTransformersModel = "sentence-transformers/all-MiniLM-L6-v2"
+
+//go:embed sentence_transformers.py
+var sentenceTransformersScript string
+
+// SubprocessEmbedder talks to a long-lived embedding worker over a JSON-lines
+// protocol on stdin/stdout. By default the worker is the bundled
+// sentence-transformers script run with python3; any command speaking the
+// same protocol can be substituted.
+type SubprocessEmbedder struct {
+	command   []string
+	model     string
+	dim       int
+	batchSize int
+
+	maxRetries int
+	backoff    time.Duration
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr *strings.Builder
+	nextID int
+}
+
+// NewSubprocessEmbedder creates an embedder for ProviderPythonSentenceTransformers.
+// The worker is started lazily on the first Embed call.
+func NewSubprocessEmbedder(cfg EmbeddingConfig) (*SubprocessEmbedder, error) {
+	cfg = cfg.Normalized()
+	command := cfg.Command
+	if len(command) == 0 {
+		command = []string{"python3", "-u", "-c", sentenceTransformersScript}
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return nil, fmt.Errorf("semantic embedder %q: %w; set %s=%q for deterministic fallback",
+			ProviderPythonSentenceTransformers, err, EnvSemanticEmbedder, ProviderHash)
+	}
+	model := cfg.Model
+	if model == "" {
+		model = DefaultSentenceTransformersModel
+	}
+	return &SubprocessEmbedder{
+		command:    command,
+		model:      model,
+		dim:        cfg.Dim,
+		batchSize:  cfg.BatchSize,
+		maxRetries: 2,
+		backoff:    250 * time.Millisecond,
+	}, nil
+}
+
+func (*SubprocessEmbedder) Provider() Provider { return ProviderPythonSentenceTransformers }
+func (e *SubprocessEmbedder) Dim() int         { return e.dim }
+
+// ModelID identifies the model so that switching it invalidates existing vectors.
+func (e *SubprocessEmbedder) ModelID() string {
+	return fmt.Sprintf("%s:%s", ProviderPythonSentenceTransformers, e.model)
+}
+
+func (e *SubprocessEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return embedInBatches(ctx, texts, e.batchSize, func(batch []string) ([][]float32, error) {
+		var vecs [][]float32
+		err := withRetry(ctx, e.maxRetries, e.backoff, func() error {
+			var err error
+			vecs, err = e.roundTrip(ctx, batch)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := validateEmbeddings(vecs, len(batch), e.dim); err != nil {
+			return nil, err
+		}
+		return vecs, nil
+	})
+}
+
+// Close stops the worker process, if running.
+func (e *SubprocessEmbedder) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stop()
+	return nil
+}
+
+type subprocessRequest struct {
+	ID    int      `json:"id"`
+	Model string   `json:"model"`
+	Texts []string `json:"texts"`
+}
+
+type subprocessResponse struct {
+	ID         int         `json:"id"`
+	Embeddings [][]float32 `json:"embeddings"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// roundTrip sends one request and waits for its response. Transport failures
+// tear the worker down so the next attempt starts a fresh one.
+func (e *SubprocessEmbedder) roundTrip(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.cmd == nil {
+		if err := e.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	e.nextID++
+	line, err := json.Marshal(subprocessRequest{ID: e.nextID, Model: e.model, Texts: texts})
+	if err != nil {
+		return nil, fmt.Errorf("encode embedding request: %w", err)
+	}
+	if _, err := e.stdin.Write(append(line, '\n')); err != nil {
+		e.stop()
+		return nil, retryableError{fmt.Errorf("write to embedding worker: %w", err)}
+	}
+
+	type result struct {
+		line []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	stdout := e.stdout
+	go func() {
+		b, err := stdout.ReadBytes('\n')
+		done <- result{b, err}
+	}()
+
+	var res result
+	select {
+	case <-ctx.Done():
+		e.stop()
+		return nil, ctx.Err()
+	case res = <-done:
+	}
+	if res.err != nil {
+		if stderr := e.stop(); stderr != "" {
+			return nil, retryableError{fmt.Errorf("embedding worker exited: %s", stderr)}
+		}
+		return nil, retryableError{fmt.Errorf("read from embedding worker: %w", res.err)}
+	}
+
+	var resp subprocessResponse
+	if err := json.Unmarshal(res.line, &resp); err != nil {
+		e.stop()
+		return nil, retryableError{fmt.Errorf("decode embedding worker response: %w", err)}
+	}
+	if resp.ID != e.nextID {
+		e.stop()
+		return nil, retryableError{fmt.Errorf("embedding worker answered request %d, expected %d", resp.ID, e.nextID)}
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("embedding worker: %s", resp.Error)
+	}
+	return resp.Embeddings, nil
+}
+
+func (e *SubprocessEmbedder) start() error {
+	cmd := exec.Command(e.command[0], e.command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("embedding worker stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("embedding worker stdout: %w", err)
+	}
+	stderr := &strings.Builder{}
+	cmd.Stderr = &limitedWriter{w: stderr, n: 4096}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start embedding worker %q: %w", e.command[0], err)
+	}
+	e.cmd = cmd
+	e.stdin = stdin
+	e.stdout = bufio.NewReaderSize(stdout, 1<<20)
+	e.stderr = stderr
+	return nil
+}
+
+// stop kills the worker and returns whatever it wrote to stderr.
+func (e *SubprocessEmbedder) stop() string {
+	if e.cmd == nil {
+		return ""
+	}
+	_ = e.stdin.Close()
+	if e.cmd.Process != nil {
+		_ = e.cmd.Process.Kill()
+	}
+	_ = e.cmd.Wait()
+	stderr := strings.TrimSpace(e.stderr.String())
+	e.cmd, e.stdin, e.stdout, e.stderr = nil, nil, nil, nil
+	return stderr
+}
+
+// limitedWriter keeps the first n bytes written and discards the rest, so a
+// chatty worker cannot grow memory without bound.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		chunk := p
+		if len(chunk) > l.n {
+			chunk = chunk[:l.n]
+		}
+		l.n -= len(chunk)
+		_, _ = l.w.Write(chunk)
+	}
+	return len(p), nil
+}
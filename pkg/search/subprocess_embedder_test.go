@@ -0,0 +1,174 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHelperEmbedderProcess is not a real test: it is re-executed as the fake
+// embedding worker. BV_FAKE_EMBEDDER selects its behavior.
+func TestHelperEmbedderProcess(t *testing.T) {
+	mode := os.Getenv("BV_FAKE_EMBEDDER")
+	if mode == "" {
+		return
+	}
+	// Requests served by earlier worker processes, shared via a counter file.
+	counter := os.Getenv("BV_FAKE_EMBEDDER_COUNTER")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1<<20), 1<<24)
+	for scanner.Scan() {
+		var req subprocessRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(3)
+		}
+		served := 0
+		if counter != "" {
+			b, _ := os.ReadFile(counter)
+			fmt.Sscanf(string(b), "%d", &served)
+			_ = os.WriteFile(counter, []byte(fmt.Sprint(served+1)), 0o644)
+		}
+		resp := subprocessResponse{ID: req.ID}
+		switch {
+		case mode == "crash-first" && served == 0:
+			fmt.Fprintln(os.Stderr, "simulated crash")
+			os.Exit(2)
+		case mode == "error":
+			resp.Error = "model " + req.Model + " not found"
+		case mode == "wrong-dim":
+			for range req.Texts {
+				resp.Embeddings = append(resp.Embeddings, []float32{1})
+			}
+		default:
+			for _, text := range req.Texts {
+				vec := make([]float32, 4)
+				vec[len(text)%4] = 1
+				resp.Embeddings = append(resp.Embeddings, vec)
+			}
+		}
+		out, _ := json.Marshal(resp)
+		fmt.Println(string(out))
+	}
+	os.Exit(0)
+}
+
+func newFakeSubprocessEmbedder(t *testing.T, mode string) (*SubprocessEmbedder, string) {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "served")
+	t.Setenv("BV_FAKE_EMBEDDER", mode)
+	t.Setenv("BV_FAKE_EMBEDDER_COUNTER", counter)
+	e, err := NewSubprocessEmbedder(EmbeddingConfig{
+		Provider:  ProviderPythonSentenceTransformers,
+		Model:     "fake-model",
+		Dim:       4,
+		BatchSize: 2,
+		Command:   []string{os.Args[0], "-test.run=^TestHelperEmbedderProcess$"},
+	})
+	if err != nil {
+		t.Fatalf("NewSubprocessEmbedder: %v", err)
+	}
+	e.backoff = time.Millisecond
+	t.Cleanup(func() { _ = e.Close() })
+	return e, counter
+}
+
+func servedRequests(t *testing.T, counter string) string {
+	t.Helper()
+	b, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("read counter: %v", err)
+	}
+	return string(b)
+}
+
+func TestSubprocessEmbedder_BatchesOverOneWorker(t *testing.T) {
+	e, counter := newFakeSubprocessEmbedder(t, "ok")
+
+	vecs, err := e.Embed(context.Background(), []string{"a", "bb", "ccc", "dddd", "e"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 5 || vecs[1][2] != 1 || vecs[3][0] != 1 {
+		t.Fatalf("unexpected vectors: %v", vecs)
+	}
+	// 5 texts with batch size 2 is 3 requests, all served by the same worker.
+	if got := servedRequests(t, counter); got != "3" {
+		t.Errorf("served %s requests, want 3", got)
+	}
+	if e.ModelID() != "python-sentence-transformers:fake-model" {
+		t.Errorf("ModelID = %q", e.ModelID())
+	}
+}
+
+func TestSubprocessEmbedder_RestartsCrashedWorker(t *testing.T) {
+	e, _ := newFakeSubprocessEmbedder(t, "crash-first")
+
+	vecs, err := e.Embed(context.Background(), []string{"retry me"})
+	if err != nil {
+		t.Fatalf("Embed should succeed after restart: %v", err)
+	}
+	if len(vecs) != 1 {
+		t.Fatalf("expected 1 vector, got %d", len(vecs))
+	}
+}
+
+func TestSubprocessEmbedder_Errors(t *testing.T) {
+	e, counter := newFakeSubprocessEmbedder(t, "error")
+	_, err := e.Embed(context.Background(), []string{"x"})
+	if err == nil || !strings.Contains(err.Error(), "model fake-model not found") {
+		t.Fatalf("expected worker error, got %v", err)
+	}
+	// Errors reported by the worker are not retried.
+	if got := servedRequests(t, counter); got != "1" {
+		t.Errorf("served %s requests, want 1", got)
+	}
+
+	e2, _ := newFakeSubprocessEmbedder(t, "wrong-dim")
+	if _, err := e2.Embed(context.Background(), []string{"x"}); err == nil || !strings.Contains(err.Error(), "dimension 1, expected 4") {
+		t.Fatalf("expected dimension error, got %v", err)
+	}
+}
+
+func TestSubprocessEmbedder_SyncInvalidatesOnModelChange(t *testing.T) {
+	e, _ := newFakeSubprocessEmbedder(t, "ok")
+	idx := NewVectorIndex(4)
+	docs := map[string]string{"A": "alpha", "B": "beta"}
+
+	if _, err := SyncVectorIndex(context.Background(), idx, e, docs, 0); err != nil {
+		t.Fatalf("SyncVectorIndex: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex: %v", err)
+	}
+	if loaded.Model != e.ModelID() {
+		t.Fatalf("model not persisted: %q", loaded.Model)
+	}
+
+	// Same docs, same model: nothing to do.
+	stats, err := SyncVectorIndex(context.Background(), loaded, e, docs, 0)
+	if err != nil || stats.Changed() || stats.Skipped != 2 {
+		t.Fatalf("unexpected resync: %+v, %v", stats, err)
+	}
+
+	// Switching models re-embeds everything.
+	e.model = "other-model"
+	stats, err = SyncVectorIndex(context.Background(), loaded, e, docs, 0)
+	if err != nil {
+		t.Fatalf("SyncVectorIndex: %v", err)
+	}
+	if !stats.Invalidated || stats.Added != 2 || stats.Embedded != 2 || loaded.Model != "python-sentence-transformers:other-model" {
+		t.Fatalf("expected invalidation: %+v model=%q", stats, loaded.Model)
+	}
+}
@@ -18,8 +18,9 @@ import (
 )
 
 const (
-	vectorIndexMagic   = "BVVI"
-	vectorIndexVersion = uint16(1)
+	vectorIndexMagic = "BVVI"
+	// Version 2 adds the embedder model identifier after the entry count.
+	vectorIndexVersion = uint16(2)
 )
 
 type ContentHash [32]byte
@@ -52,11 +53,18 @@ type VectorEntry struct {
 
 type VectorIndex struct {
 	Dim int
+	// Model identifies the embedder that produced the vectors (see
+	// EmbedderModelID). Empty for indexes written before it was recorded.
+	Model string
+	// ANNThreshold is the size at which SearchTopK uses the HNSW graph instead
+	// of an exact scan. 0 means DefaultANNThreshold; negative disables ANN.
+	ANNThreshold int
 
 	mu       sync.RWMutex
 	entries  map[string]VectorEntry
 	idsCache []string
 	idsDirty bool
+	ann      *hnswIndex // Built lazily once the index reaches ANNThreshold
 }
 
 func NewVectorIndex(dim int) *VectorIndex {
@@ -91,7 +99,7 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
 		return nil, fmt.Errorf("read version: %w", err)
 	}
-	if version != vectorIndexVersion {
+	if version != 1 && version != vectorIndexVersion {
 		return nil, fmt.Errorf("unsupported version %d", version)
 	}
 
@@ -114,6 +122,17 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	}
 
 	idx := NewVectorIndex(int(dimU32))
+	if version >= 2 {
+		var modelLen uint16
+		if err := binary.Read(r, binary.LittleEndian, &modelLen); err != nil {
+			return nil, fmt.Errorf("read model len: %w", err)
+		}
+		model := make([]byte, modelLen)
+		if _, err := io.ReadFull(r, model); err != nil {
+			return nil, fmt.Errorf("read model: %w", err)
+		}
+		idx.Model = string(model)
+	}
 	for i := uint32(0); i < count; i++ {
 		var idLen uint16
 		if err := binary.Read(r, binary.LittleEndian, &idLen); err != nil {
@@ -148,10 +167,40 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 		}
 	}
 
+	// A missing or stale graph is not an error; it is rebuilt on demand.
+	if ann, err := readHNSW(ANNIndexPath(path), idx.Dim, idx.entries); err == nil {
+		idx.ann = ann
+	}
+
 	return idx, nil
 }
 
+// Save writes the index to path and, when the index is large enough to use
+// one, the HNSW graph to ANNIndexPath(path).
 func (idx *VectorIndex) Save(path string) error {
+	if idx.UsesANN() {
+		idx.ensureANN()
+	}
+	if err := idx.saveEntries(path); err != nil {
+		return err
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	annPath := ANNIndexPath(path)
+	if idx.ann == nil {
+		if err := os.Remove(annPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove stale ann index: %w", err)
+		}
+		return nil
+	}
+	if err := writeHNSW(annPath, idx.ann, idx.Dim); err != nil {
+		return fmt.Errorf("save ann index: %w", err)
+	}
+	return nil
+}
+
+func (idx *VectorIndex) saveEntries(path string) error {
 	// Acquire sorted IDs before locking to avoid deadlock (sortedIDs needs Write lock if dirty)
 	ids := idx.sortedIDs()
 
@@ -191,6 +240,15 @@ func (idx *VectorIndex) Save(path string) error {
 	if err := binary.Write(w, binary.LittleEndian, uint32(len(ids))); err != nil {
 		return fmt.Errorf("write count: %w", err)
 	}
+	if len(idx.Model) > math.MaxUint16 {
+		return fmt.Errorf("model id too long: %d", len(idx.Model))
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(idx.Model))); err != nil {
+		return fmt.Errorf("write model len: %w", err)
+	}
+	if _, err := w.WriteString(idx.Model); err != nil {
+		return fmt.Errorf("write model: %w", err)
+	}
 
 	for _, issueID := range ids {
 		entry, ok := idx.entries[issueID]
@@ -268,6 +326,9 @@ func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32)
 	if !exists {
 		idx.idsDirty = true
 	}
+	if idx.ann != nil {
+		idx.ann.insert(issueID, hash, cp)
+	}
 	return nil
 }
 
@@ -280,6 +341,36 @@ func (idx *VectorIndex) Remove(issueID string) {
 	}
 	delete(idx.entries, issueID)
 	idx.idsDirty = true
+	if idx.ann != nil {
+		idx.ann.remove(issueID)
+	}
+}
+
+// Reset removes all entries, keeping Dim and Model.
+func (idx *VectorIndex) Reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.resetLocked()
+}
+
+func (idx *VectorIndex) resetLocked() {
+	idx.entries = make(map[string]VectorEntry)
+	idx.idsCache = nil
+	idx.idsDirty = true
+	idx.ann = nil
+}
+
+// adoptModel records modelID as the index's model, clearing the entries
+// first when they came from a different one. It reports whether it cleared.
+func (idx *VectorIndex) adoptModel(modelID string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	reset := idx.Model != "" && idx.Model != modelID
+	if reset {
+		idx.resetLocked()
+	}
+	idx.Model = modelID
+	return reset
 }
 
 func (idx *VectorIndex) Get(issueID string) (VectorEntry, bool) {
@@ -326,6 +417,19 @@ type SearchResult struct {
 	Score   float64 `json:"score"`
 }
 
+// UsesANN reports whether SearchTopK currently answers from the HNSW graph
+// rather than an exact scan.
+func (idx *VectorIndex) UsesANN() bool {
+	threshold := idx.ANNThreshold
+	if threshold == 0 {
+		threshold = DefaultANNThreshold
+	}
+	return threshold > 0 && idx.Size() >= threshold
+}
+
+// SearchTopK returns the k entries with the highest dot product with query.
+// Large indexes (see ANNThreshold) are searched approximately via HNSW;
+// smaller ones are scanned exactly.
 func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, error) {
 	if k <= 0 {
 		return nil, nil
@@ -333,6 +437,77 @@ func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, erro
 	if len(query) != idx.Dim {
 		return nil, fmt.Errorf("query dim mismatch: %d != %d", len(query), idx.Dim)
 	}
+	if idx.UsesANN() {
+		idx.ensureANN()
+		idx.mu.RLock()
+		defer idx.mu.RUnlock()
+		return idx.ann.search(query, k), nil
+	}
+	return idx.searchExact(query, k, nil), nil
+}
+
+// SearchTopKFiltered is SearchTopK restricted to IDs accepted by allow, for
+// queries whose structured filters already narrowed the candidate set. The
+// HNSW graph is tried first with an oversampled k; if too few of its hits
+// pass the filter, the exact scan is used instead.
+func (idx *VectorIndex) SearchTopKFiltered(query []float32, k int, allow func(id string) bool) ([]SearchResult, error) {
+	if allow == nil {
+		return idx.SearchTopK(query, k)
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+	if len(query) != idx.Dim {
+		return nil, fmt.Errorf("query dim mismatch: %d != %d", len(query), idx.Dim)
+	}
+	if idx.UsesANN() {
+		idx.ensureANN()
+		idx.mu.RLock()
+		hits := idx.ann.search(query, k*4)
+		idx.mu.RUnlock()
+		filtered := hits[:0]
+		for _, h := range hits {
+			if allow(h.IssueID) {
+				filtered = append(filtered, h)
+			}
+		}
+		if len(filtered) >= k {
+			return filtered[:k], nil
+		}
+	}
+	return idx.searchExact(query, k, allow), nil
+}
+
+// NeedsANNBuild reports whether SearchTopK would have to (re)build the HNSW
+// graph first. Callers can use it to decide to Save so the graph persists.
+func (idx *VectorIndex) NeedsANNBuild() bool {
+	if !idx.UsesANN() {
+		return false
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.ann == nil || idx.ann.needsRebuild()
+}
+
+// ensureANN builds the HNSW graph if missing or degraded by removals.
+func (idx *VectorIndex) ensureANN() {
+	idx.mu.RLock()
+	ok := idx.ann != nil && !idx.ann.needsRebuild()
+	idx.mu.RUnlock()
+	if ok {
+		return
+	}
+
+	ids := idx.sortedIDs()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.ann == nil || idx.ann.needsRebuild() {
+		idx.ann = buildHNSWIndex(ids, idx.entries)
+	}
+}
+
+// searchExact scores every entry accepted by allow (all when nil).
+func (idx *VectorIndex) searchExact(query []float32, k int, allow func(id string) bool) []SearchResult {
 
 	// sortedIDs now handles its own locking safely
 	ids := idx.sortedIDs()
@@ -346,6 +521,9 @@ func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, erro
 	})
 
 	for _, issueID := range ids {
+		if allow != nil && !allow(issueID) {
+			continue
+		}
 		entry, ok := idx.entries[issueID]
 		if !ok {
 			// This can happen if the issue was removed concurrently between sortedIDs() and RLock()
@@ -355,7 +533,7 @@ func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, erro
 		collector.Add(SearchResult{IssueID: issueID, Score: score}, score)
 	}
 
-	return collector.Results(), nil
+	return collector.Results()
 }
 
 func dotFloat32(a, b []float32) float64 {
@@ -1,6 +1,10 @@
 package search
 
 import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -43,6 +47,34 @@ func TestVectorIndex_SaveLoad_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestVectorIndex_LoadVersion1(t *testing.T) {
+	// Version 1 files have no model identifier after the entry count.
+	var buf bytes.Buffer
+	buf.WriteString(vectorIndexMagic)
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(0))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	buf.WriteString("A")
+	hash := ComputeContentHash("a")
+	buf.Write(hash[:])
+	_ = binary.Write(&buf, binary.LittleEndian, math.Float32bits(1))
+	_ = binary.Write(&buf, binary.LittleEndian, math.Float32bits(0))
+
+	path := filepath.Join(t.TempDir(), "v1.bvvi")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex: %v", err)
+	}
+	if idx.Model != "" || idx.Size() != 1 {
+		t.Fatalf("unexpected v1 index: model=%q size=%d", idx.Model, idx.Size())
+	}
+}
+
 func TestVectorIndex_SearchTopK_OrderAndTieBreak(t *testing.T) {
 	idx := NewVectorIndex(2)
 
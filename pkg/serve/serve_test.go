@@ -0,0 +1,267 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func sampleIssues(ids ...string) []model.Issue {
+	issues := make([]model.Issue, 0, len(ids))
+	for _, id := range ids {
+		issues = append(issues, model.Issue{ID: id, Title: "Issue " + id, Status: model.StatusOpen, IssueType: model.TypeTask})
+	}
+	return issues
+}
+
+func TestStore_ReloadPublishesNewSnapshotOnlyOnChange(t *testing.T) {
+	var mu sync.Mutex
+	data := sampleIssues("A-1", "A-2")
+	var loads int32
+	store, err := NewStore(func() ([]model.Issue, error) {
+		atomic.AddInt32(&loads, 1)
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]model.Issue(nil), data...), nil
+	})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	first := store.Current()
+	if first.Generation != 1 || len(first.Issues) != 2 {
+		t.Fatalf("unexpected initial snapshot: gen=%d issues=%d", first.Generation, len(first.Issues))
+	}
+
+	same, err := store.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if same != first {
+		t.Error("expected unchanged data to keep the current snapshot")
+	}
+
+	mu.Lock()
+	data = sampleIssues("A-1", "A-2", "A-3")
+	mu.Unlock()
+
+	next, err := store.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if next == first || next.Generation != 2 || len(next.Issues) != 3 {
+		t.Errorf("expected new snapshot gen 2 with 3 issues, got gen=%d issues=%d", next.Generation, len(next.Issues))
+	}
+	if got := atomic.LoadInt32(&loads); got != 3 {
+		t.Errorf("expected 3 loads, got %d", got)
+	}
+}
+
+func TestStore_ReloadFailureKeepsPreviousSnapshot(t *testing.T) {
+	fail := false
+	store, err := NewStore(func() ([]model.Issue, error) {
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return sampleIssues("A-1"), nil
+	})
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	before := store.Current()
+
+	fail = true
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("expected reload error")
+	}
+	if store.Current() != before {
+		t.Error("failed reload replaced the snapshot")
+	}
+	if store.LastError() == nil {
+		t.Error("expected LastError to be set")
+	}
+
+	fail = false
+	if _, err := store.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if store.LastError() != nil {
+		t.Errorf("expected LastError cleared, got %v", store.LastError())
+	}
+}
+
+func TestSnapshot_MemoComputesOnce(t *testing.T) {
+	snap := newSnapshot(sampleIssues("A-1"), 1)
+	var calls int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := snap.Memo("k", func() (any, error) {
+				atomic.AddInt32(&calls, 1)
+				return 42, nil
+			})
+			if err != nil || v.(int) != 42 {
+				t.Errorf("Memo = %v, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("expected 1 computation, got %d", calls)
+	}
+}
+
+func TestServer_Endpoints(t *testing.T) {
+	store, err := NewStore(func() ([]model.Issue, error) { return sampleIssues("A-1", "A-2"), nil })
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	var gotFormat string
+	srv := NewServer(store, Options{
+		Encode: func(w io.Writer, format string, v any) error {
+			gotFormat = format
+			return json.NewEncoder(w).Encode(v)
+		},
+	})
+	srv.Handle("/count", "Issue count", func(_ *http.Request, snap *Snapshot) (any, error) {
+		return map[string]int{"count": len(snap.Issues)}, nil
+	})
+	srv.Handle("/missing", "Always 404", func(r *http.Request, _ *Snapshot) (any, error) {
+		return nil, Errorf(http.StatusNotFound, "issue %s not found", r.URL.Query().Get("id"))
+	})
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	getJSON := func(path string, wantStatus int, out any) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("GET %s: status %d, want %d", path, resp.StatusCode, wantStatus)
+		}
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				t.Fatalf("GET %s: decode: %v", path, err)
+			}
+		}
+	}
+
+	var count map[string]int
+	getJSON("/count", http.StatusOK, &count)
+	if count["count"] != 2 || gotFormat != "json" {
+		t.Errorf("count=%v format=%q", count, gotFormat)
+	}
+
+	getJSON("/count?format=toon", http.StatusOK, &count)
+	if gotFormat != "toon" {
+		t.Errorf("expected toon format, got %q", gotFormat)
+	}
+	getJSON("/count?format=yaml", http.StatusBadRequest, nil)
+
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	getJSON("/missing?id=X-9", http.StatusNotFound, &errBody)
+	if errBody.Error != "issue X-9 not found" {
+		t.Errorf("unexpected error body %q", errBody.Error)
+	}
+
+	var health healthStatus
+	getJSON("/healthz", http.StatusOK, &health)
+	if health.Status != "ok" || health.IssueCount != 2 || health.DataHash == "" {
+		t.Errorf("unexpected health: %+v", health)
+	}
+
+	var index struct {
+		Routes []Route `json:"routes"`
+	}
+	getJSON("/", http.StatusOK, &index)
+	if len(index.Routes) != 5 {
+		t.Errorf("expected 5 routes, got %+v", index.Routes)
+	}
+	getJSON("/nope", http.StatusNotFound, nil)
+
+	resp, err := http.Get(ts.URL + "/reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /reload: status %d", resp.StatusCode)
+	}
+	resp, err = http.Post(ts.URL+"/reload", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("POST /reload: status %d", resp.StatusCode)
+	}
+}
+
+func TestStore_WatchReloadsOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"id":"W-1","title":"one","status":"open","issue_type":"task"}` + "\n")
+
+	store, err := NewStore(func() ([]model.Issue, error) { return loader.LoadIssuesFromFile(path) })
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer store.Close()
+
+	reloaded := make(chan *Snapshot, 4)
+	store.OnReload(func(s *Snapshot, err error) {
+		if err != nil {
+			return
+		}
+		select {
+		case reloaded <- s:
+		default:
+		}
+	})
+	opts := datasource.DefaultWatcherOptions()
+	opts.Debounce = 10 * time.Millisecond
+	if err := store.Watch([]datasource.DataSource{{Type: datasource.SourceTypeJSONLLocal, Path: path}}, opts); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	write(`{"id":"W-1","title":"one","status":"open","issue_type":"task"}` + "\n" +
+		`{"id":"W-2","title":"two","status":"open","issue_type":"task"}` + "\n")
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case snap := <-reloaded:
+			if len(snap.Issues) == 2 {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("store did not reload; current has %d issues", len(store.Current().Issues))
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HandlerFunc computes the payload for an endpoint from the current snapshot.
+// The returned value is encoded in the negotiated output format. Returning an
+// *HTTPError controls the response status; any other error yields a 500.
+type HandlerFunc func(r *http.Request, snap *Snapshot) (any, error)
+
+// Encoder writes v to w in the given output format ("json" or "toon").
+type Encoder func(w io.Writer, format string, v any) error
+
+// HTTPError is an error with an explicit HTTP status code.
+type HTTPError struct {
+	Status int
+	Err    error
+}
+
+func (e *HTTPError) Error() string { return e.Err.Error() }
+
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// Errorf returns an *HTTPError with the given status and formatted message.
+func Errorf(status int, format string, args ...any) error {
+	return &HTTPError{Status: status, Err: fmt.Errorf(format, args...)}
+}
+
+// Options configures a Server.
+type Options struct {
+	// Addr is the listen address for ListenAndServe (default 127.0.0.1:9595).
+	Addr string
+	// Format is the default output format when a request has no ?format=.
+	// Default: json
+	Format string
+	// Encode renders payloads. Default: plain JSON for every format.
+	Encode Encoder
+	// Logger receives one line per request when set.
+	Logger func(msg string)
+}
+
+// DefaultAddr is the listen address used when Options.Addr is empty.
+const DefaultAddr = "127.0.0.1:9595"
+
+// Route describes a registered endpoint.
+type Route struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	Description string `json:"description"`
+}
+
+// Server serves Store snapshots over HTTP.
+type Server struct {
+	store *Store
+	opts  Options
+	mux   *http.ServeMux
+
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewServer creates a server with the built-in endpoints registered:
+// GET / (route index), GET /healthz and POST /reload.
+func NewServer(store *Store, opts Options) *Server {
+	if opts.Addr == "" {
+		opts.Addr = DefaultAddr
+	}
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	if opts.Encode == nil {
+		opts.Encode = func(w io.Writer, _ string, v any) error {
+			return json.NewEncoder(w).Encode(v)
+		}
+	}
+
+	s := &Server{
+		store: store,
+		opts:  opts,
+		mux:   http.NewServeMux(),
+	}
+
+	s.addRoute(Route{Path: "/", Method: http.MethodGet, Description: "List available endpoints"})
+	s.mux.HandleFunc("/", s.handleIndex)
+	s.addRoute(Route{Path: "/healthz", Method: http.MethodGet, Description: "Liveness and snapshot status"})
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.addRoute(Route{Path: "/reload", Method: http.MethodPost, Description: "Force a reload of the issue data"})
+	s.mux.HandleFunc("/reload", s.handleReload)
+	return s
+}
+
+// Handle registers a GET endpoint backed by h.
+func (s *Server) Handle(path, description string, h HandlerFunc) {
+	s.addRoute(Route{Path: path, Method: http.MethodGet, Description: description})
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			s.writeError(w, Errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method))
+			return
+		}
+		format, err := s.format(r)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		snap := s.store.Current()
+		if snap == nil {
+			s.writeError(w, Errorf(http.StatusServiceUnavailable, "no data loaded"))
+			return
+		}
+		payload, err := h(r, snap)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.write(w, format, http.StatusOK, payload)
+	})
+}
+
+// Routes returns the registered endpoints sorted by path.
+func (s *Server) Routes() []Route {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := append([]Route(nil), s.routes...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// Addr returns the configured listen address.
+func (s *Server) Addr() string {
+	return s.opts.Addr
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(rec, r)
+	if s.opts.Logger != nil {
+		s.opts.Logger(fmt.Sprintf("%s %s %d %s", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Microsecond)))
+	}
+}
+
+// ListenAndServe serves until ctx is cancelled, then shuts down gracefully.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.opts.Addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *Server) addRoute(r Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, r)
+}
+
+func (s *Server) format(r *http.Request) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		return s.opts.Format, nil
+	}
+	switch format {
+	case "json", "toon":
+		return format, nil
+	default:
+		return "", Errorf(http.StatusBadRequest, "invalid format %q (expected json|toon)", format)
+	}
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		s.writeError(w, Errorf(http.StatusNotFound, "unknown endpoint %s", r.URL.Path))
+		return
+	}
+	s.write(w, "json", http.StatusOK, struct {
+		Routes []Route `json:"routes"`
+	}{Routes: s.Routes()})
+}
+
+// healthStatus is the /healthz payload.
+type healthStatus struct {
+	Status     string `json:"status"`
+	DataHash   string `json:"data_hash,omitempty"`
+	Generation uint64 `json:"generation"`
+	IssueCount int    `json:"issue_count"`
+	LoadedAt   string `json:"loaded_at,omitempty"`
+	Phase2     bool   `json:"phase2_ready"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
+func (s *Server) health() healthStatus {
+	h := healthStatus{Status: "ok"}
+	if snap := s.store.Current(); snap != nil {
+		h.DataHash = snap.DataHash
+		h.Generation = snap.Generation
+		h.IssueCount = len(snap.Issues)
+		h.LoadedAt = snap.LoadedAt.UTC().Format(time.RFC3339)
+		h.Phase2 = snap.Stats.IsPhase2Ready()
+	}
+	if err := s.store.LastError(); err != nil {
+		h.Status = "degraded"
+		h.LastError = err.Error()
+	}
+	return h
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	s.write(w, "json", http.StatusOK, s.health())
+}
+
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, Errorf(http.StatusMethodNotAllowed, "use POST to reload"))
+		return
+	}
+	if _, err := s.store.Reload(); err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.write(w, "json", http.StatusOK, s.health())
+}
+
+func (s *Server) write(w http.ResponseWriter, format string, status int, v any) {
+	if format == "toon" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	if err := s.opts.Encode(w, format, v); err != nil && s.opts.Logger != nil {
+		s.opts.Logger(fmt.Sprintf("encode error: %v", err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error  string `json:"error"`
+		Status int    `json:"status"`
+	}{Error: err.Error(), Status: status})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
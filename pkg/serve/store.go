@@ -0,0 +1,210 @@
+// Package serve implements the long-running `bv serve` mode.
+//
+// A Store keeps the loaded issues together with a warm Analyzer and
+// GraphStats so that repeated robot queries skip loading and graph
+// construction. The Store reloads itself when the underlying beads data
+// changes on disk. A Server exposes the Store over a small HTTP/JSON API.
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// LoadFunc loads the current issue set.
+type LoadFunc func() ([]model.Issue, error)
+
+// Snapshot is an immutable view of the issue set and its graph analysis.
+// Handlers must treat Issues as read-only; a reload publishes a new Snapshot
+// rather than mutating the current one.
+type Snapshot struct {
+	Issues     []model.Issue
+	DataHash   string
+	Analyzer   *analysis.Analyzer
+	Stats      *analysis.GraphStats
+	LoadedAt   time.Time
+	Generation uint64
+
+	mu   sync.Mutex
+	memo map[string]*memoEntry
+}
+
+type memoEntry struct {
+	once  sync.Once
+	value any
+	err   error
+}
+
+// Memo returns the value cached under key for this snapshot, calling compute
+// on first use. Concurrent callers for the same key share one computation.
+// Cached values are dropped automatically when the store reloads.
+func (s *Snapshot) Memo(key string, compute func() (any, error)) (any, error) {
+	s.mu.Lock()
+	if s.memo == nil {
+		s.memo = make(map[string]*memoEntry)
+	}
+	entry, ok := s.memo[key]
+	if !ok {
+		entry = &memoEntry{}
+		s.memo[key] = entry
+	}
+	s.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.value, entry.err = compute()
+	})
+	return entry.value, entry.err
+}
+
+// newSnapshot builds a snapshot and starts graph analysis in the background.
+// Phase 1 metrics are available immediately; callers that need Phase 2 should
+// call Stats.WaitForPhase2.
+func newSnapshot(issues []model.Issue, generation uint64) *Snapshot {
+	analyzer := analysis.NewAnalyzer(issues)
+	return &Snapshot{
+		Issues:     issues,
+		DataHash:   analysis.ComputeDataHash(issues),
+		Analyzer:   analyzer,
+		Stats:      analyzer.AnalyzeAsync(context.Background()),
+		LoadedAt:   time.Now(),
+		Generation: generation,
+	}
+}
+
+// Store holds the current Snapshot and refreshes it on demand or when a
+// watched data source changes.
+type Store struct {
+	load LoadFunc
+
+	mu         sync.RWMutex
+	current    *Snapshot
+	generation uint64
+	lastErr    error
+
+	reloadMu sync.Mutex
+	watcher  *datasource.SourceWatcher
+	onReload func(*Snapshot, error)
+}
+
+// NewStore performs the initial load and returns a ready Store.
+func NewStore(load LoadFunc) (*Store, error) {
+	if load == nil {
+		return nil, errors.New("serve: nil LoadFunc")
+	}
+	s := &Store{load: load}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OnReload registers a callback invoked after every reload attempt, with the
+// new snapshot on success or the load error on failure.
+func (s *Store) OnReload(fn func(*Snapshot, error)) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	s.onReload = fn
+}
+
+// Current returns the most recently published snapshot.
+func (s *Store) Current() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// LastError returns the error from the most recent failed reload, or nil if
+// the last reload succeeded.
+func (s *Store) LastError() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastErr
+}
+
+// Reload loads the issues again and publishes a new snapshot. When the data
+// hash is unchanged the current snapshot (and its warm caches) is kept. On
+// failure the previous snapshot stays in place.
+func (s *Store) Reload() (*Snapshot, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	snap, err := s.reloadLocked()
+	if s.onReload != nil {
+		s.onReload(snap, err)
+	}
+	return snap, err
+}
+
+func (s *Store) reloadLocked() (*Snapshot, error) {
+	issues, err := s.load()
+	if err != nil {
+		err = fmt.Errorf("loading issues: %w", err)
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	s.mu.RLock()
+	cur := s.current
+	s.mu.RUnlock()
+	if cur != nil && cur.DataHash == analysis.ComputeDataHash(issues) {
+		s.mu.Lock()
+		s.lastErr = nil
+		s.mu.Unlock()
+		return cur, nil
+	}
+
+	s.mu.Lock()
+	s.generation++
+	gen := s.generation
+	s.mu.Unlock()
+
+	snap := newSnapshot(issues, gen)
+
+	s.mu.Lock()
+	s.current = snap
+	s.lastErr = nil
+	s.mu.Unlock()
+	return snap, nil
+}
+
+// Watch reloads the store whenever one of sources changes on disk.
+func (s *Store) Watch(sources []datasource.DataSource, opts datasource.WatcherOptions) error {
+	if len(sources) == 0 {
+		return errors.New("serve: no data sources to watch")
+	}
+	w, err := datasource.NewSourceWatcher(sources, func(datasource.DataSource) {
+		_, _ = s.Reload()
+	}, opts)
+	if err != nil {
+		return err
+	}
+
+	s.reloadMu.Lock()
+	if s.watcher != nil {
+		s.watcher.Stop()
+	}
+	s.watcher = w
+	s.reloadMu.Unlock()
+
+	w.Start()
+	return nil
+}
+
+// Close stops watching for changes.
+func (s *Store) Close() {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	if s.watcher != nil {
+		s.watcher.Stop()
+		s.watcher = nil
+	}
+}
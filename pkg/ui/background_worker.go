@@ -189,9 +189,12 @@ type BackgroundWorker struct {
 	generation        uint64
 	lastHash          string // Content hash of last processed snapshot (for dedup)
 	forceNext         bool   // Force the next snapshot build even if content hash matches
+	incremental       *loader.IncrementalLoader
+	incrementalHash   string // Content hash of the incremental loader's previous unfiltered load
 	currentRecipe     *recipe.Recipe
 	currentRecipeID   string // Recipe identifier for snapshot rebuild keys
 	currentRecipeHash string // Recipe fingerprint for rebuild keys (bv-4ilb)
+	scoringProfile    *analysis.ScoringProfile
 	logLevel          WorkerLogLevel
 	logJSON           bool
 	metricsEnabled    bool
@@ -252,6 +255,9 @@ type WorkerConfig struct {
 	DebounceDelay time.Duration
 	MessageBuffer int // Buffer size for worker -> UI messages (default: 8)
 
+	// ScoringProfile scores snapshot triage; nil uses the default weights.
+	ScoringProfile *analysis.ScoringProfile
+
 	IdleGC *IdleGCConfig
 
 	// Watchdog configuration (bv-03h1). Zero values use defaults.
@@ -298,6 +304,7 @@ func NewBackgroundWorker(cfg WorkerConfig) (*BackgroundWorker, error) {
 	metricsEnabled := envBool("BV_WORKER_METRICS")
 	tracePath := strings.TrimSpace(os.Getenv("BV_WORKER_TRACE"))
 	logJSON := os.Getenv("BV_ROBOT") == "1"
+	incrementalLoad := envBoolOr("BV_INCREMENTAL_LOAD", true)
 
 	idleGCConfig := IdleGCConfig{
 		Enabled:     true,
@@ -330,6 +337,7 @@ func NewBackgroundWorker(cfg WorkerConfig) (*BackgroundWorker, error) {
 		heartbeatTimeout:  cfg.HeartbeatTimeout,
 		processingTimeout: cfg.ProcessingTimeout,
 		maxRecoveries:     cfg.MaxRecoveries,
+		scoringProfile:    cfg.ScoringProfile,
 		state:             WorkerIdle,
 		msgCh:             make(chan tea.Msg, cfg.MessageBuffer),
 		ctx:               ctx,
@@ -348,6 +356,9 @@ func NewBackgroundWorker(cfg WorkerConfig) (*BackgroundWorker, error) {
 		idleGCFunc:        runtime.GC,
 	}
 	w.lastActivityUnixNano.Store(time.Now().UnixNano())
+	if incrementalLoad && cfg.BeadsPath != "" {
+		w.incremental = loader.NewIncrementalLoader(cfg.BeadsPath)
+	}
 
 	// Initialize file watcher
 	if cfg.BeadsPath != "" {
@@ -868,6 +879,10 @@ func (w *BackgroundWorker) ForceRefresh() {
 
 	w.lastHash = ""
 	w.forceNext = true
+	if w.incremental != nil {
+		// A forced refresh also re-parses every line.
+		w.incremental.Reset()
+	}
 
 	if w.state == WorkerProcessing {
 		w.dirty = true
@@ -925,6 +940,23 @@ func (w *BackgroundWorker) SetRecipe(r *recipe.Recipe) {
 	}
 }
 
+// SetScoringProfile sets the profile snapshots score triage with and
+// triggers a refresh when it changes.
+func (w *BackgroundWorker) SetScoringProfile(p *analysis.ScoringProfile) {
+	w.mu.Lock()
+	if w.state == WorkerStopped {
+		w.mu.Unlock()
+		return
+	}
+	changed := w.scoringProfile != p
+	w.scoringProfile = p
+	w.mu.Unlock()
+
+	if changed {
+		w.ForceRefresh()
+	}
+}
+
 // GetSnapshot returns the current snapshot (may be nil).
 func (w *BackgroundWorker) GetSnapshot() *DataSnapshot {
 	w.mu.RLock()
@@ -1309,6 +1341,7 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 	currentRecipe := w.currentRecipe
 	recipeID := w.currentRecipeID
 	recipeHash := w.currentRecipeHash
+	scoringProfile := w.scoringProfile
 	w.mu.RUnlock()
 
 	// Determine dataset tier using a fast line count (bv-9thm).
@@ -1344,6 +1377,8 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 	var issues []model.Issue
 	var pooledRefs []*model.Issue
 	var loadWarnings []string
+	var changes loader.ChangeSet
+	incrementalUsed := false
 	var loadStart time.Time
 	if profileSnapshot {
 		loadStart = time.Now()
@@ -1362,6 +1397,11 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 				return i.Status != model.StatusClosed && i.Status != model.StatusTombstone
 			}
 		}
+		if w.incremental != nil {
+			issues, changes, err = w.incremental.Load(opts)
+			incrementalUsed = err == nil
+			return err
+		}
 		loaded, err = loader.LoadIssuesFromFileWithOptionsPooled(w.beadsPath, opts)
 		if err == nil {
 			issues = loaded.Issues
@@ -1395,6 +1435,13 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 
 	// Check if content is unchanged (dedup optimization)
 	w.mu.Lock()
+	// The change set is relative to the loader's previous load, which only
+	// matches the previous snapshot if that snapshot was built from it.
+	incrementalBase := w.incrementalHash
+	w.incrementalHash = ""
+	if incrementalUsed && !loadOpenOnly {
+		w.incrementalHash = hash
+	}
 	forceNext := w.forceNext
 	if forceNext {
 		w.forceNext = false
@@ -1419,10 +1466,18 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 
 	var diff *analysis.IssueDiff
 	if prevSnapshot != nil {
-		diffValue := analysis.ComputeIssueDiff(prevSnapshot.Issues, issues)
+		var diffValue analysis.IssueDiff
+		fromChanges := incrementalUsed && !changes.Initial && !loadOpenOnly &&
+			!prevSnapshot.LoadedOpenOnly && incrementalBase != "" && prevSnapshot.DataHash == incrementalBase
+		if fromChanges {
+			diffValue = analysis.ComputeIssueDiffForIDs(prevSnapshot.IssueMap, issues, changes.Added, changes.Removed, changes.Updated)
+		} else {
+			diffValue = analysis.ComputeIssueDiff(prevSnapshot.Issues, issues)
+		}
 		diff = &diffValue
 		if w.logLevel >= LogLevelDebug || w.traceFile != nil {
 			w.logEvent(LogLevelDebug, "snapshot_diff", map[string]any{
+				"from_changes":       fromChanges,
 				"added":              len(diffValue.Added),
 				"removed":            len(diffValue.Removed),
 				"modified":           len(diffValue.Modified),
@@ -1441,6 +1496,7 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 	analyzeErr := w.safeCompute("analyze_phase1", func() error {
 		builder := NewSnapshotBuilder(issues).
 			WithRecipe(currentRecipe).
+			WithScoringProfile(scoringProfile).
 			WithBuildConfig(snapshotBuildConfigForTier(tier))
 		if prevSnapshot != nil {
 			builder.WithPreviousSnapshot(prevSnapshot, diff)
@@ -1523,6 +1579,11 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 		"total_ms":  float64(totalDuration.Microseconds()) / 1000.0,
 		"hash":      hashPrefix(hash),
 	}
+	if incrementalUsed {
+		fields["lines_parsed"] = changes.LinesParsed
+		fields["lines_reused"] = changes.LinesReused
+		fields["appended"] = changes.Appended
+	}
 	if metricsEnabled {
 		fields["snapshot_bytes"] = w.metrics.lastSnapshotSizeBytes.Load()
 		fields["pool_hits"] = w.metrics.poolHits.Load()
@@ -1651,6 +1712,19 @@ func envBool(name string) bool {
 	}
 }
 
+// envBoolOr is like envBool but returns fallback when name is unset or not a
+// recognised boolean.
+func envBoolOr(name string, fallback bool) bool {
+	switch strings.TrimSpace(strings.ToLower(os.Getenv(name))) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	case "0", "false", "no", "n", "off":
+		return false
+	default:
+		return fallback
+	}
+}
+
 func envPositiveInt(name string) (int, bool) {
 	v := strings.TrimSpace(os.Getenv(name))
 	if v == "" {
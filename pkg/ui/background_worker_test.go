@@ -345,6 +345,85 @@ func TestBackgroundWorker_ContentHashChanges(t *testing.T) {
 	}
 }
 
+func TestBackgroundWorker_IncrementalLoadDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsPath := filepath.Join(tmpDir, "beads.jsonl")
+
+	content := `{"id":"test-1","title":"One","status":"open","priority":1,"issue_type":"task"}` + "\n" +
+		`{"id":"test-2","title":"Two","status":"open","priority":2,"issue_type":"task"}` + "\n"
+	if err := os.WriteFile(beadsPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	worker, err := NewBackgroundWorker(WorkerConfig{
+		BeadsPath:     beadsPath,
+		DebounceDelay: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewBackgroundWorker failed: %v", err)
+	}
+	defer worker.Stop()
+	if worker.incremental == nil {
+		t.Fatal("incremental loading should be enabled by default")
+	}
+
+	worker.TriggerRefresh()
+	waitForSnapshotVersion(t, worker, 1)
+
+	// Append a new issue; only the new line needs parsing.
+	f, err := os.OpenFile(beadsPath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"test-3","title":"Three","status":"open","priority":0,"issue_type":"task"}` + "\n")
+	f.Close()
+
+	worker.TriggerRefresh()
+	waitForSnapshotVersion(t, worker, 2)
+
+	diff := worker.GetSnapshot().IssueDiff
+	if diff == nil {
+		t.Fatal("Expected an issue diff on the second snapshot")
+	}
+	if len(diff.Added) != 1 || diff.Added[0] != "test-3" {
+		t.Errorf("Added = %v, want [test-3]", diff.Added)
+	}
+	if len(diff.Modified) != 0 || len(diff.Removed) != 0 {
+		t.Errorf("unexpected diff after append: %+v", diff)
+	}
+	if len(diff.Unchanged) != 2 {
+		t.Errorf("Unchanged = %v, want test-1 and test-2", diff.Unchanged)
+	}
+
+	// Rewrite the file with a status change to test-2.
+	content = `{"id":"test-1","title":"One","status":"open","priority":1,"issue_type":"task"}` + "\n" +
+		`{"id":"test-2","title":"Two","status":"in_progress","priority":2,"issue_type":"task"}` + "\n" +
+		`{"id":"test-3","title":"Three","status":"open","priority":0,"issue_type":"task"}` + "\n"
+	if err := os.WriteFile(beadsPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to rewrite test file: %v", err)
+	}
+
+	worker.TriggerRefresh()
+	waitForSnapshotVersion(t, worker, 3)
+
+	diff = worker.GetSnapshot().IssueDiff
+	if diff == nil || len(diff.Modified) != 1 || diff.Modified[0] != "test-2" || len(diff.Added) != 0 {
+		t.Errorf("unexpected diff after status change: %+v", diff)
+	}
+}
+
+func TestBackgroundWorker_IncrementalLoadDisabled(t *testing.T) {
+	t.Setenv("BV_INCREMENTAL_LOAD", "0")
+	worker, err := NewBackgroundWorker(WorkerConfig{BeadsPath: filepath.Join(t.TempDir(), "beads.jsonl")})
+	if err != nil {
+		t.Fatalf("NewBackgroundWorker failed: %v", err)
+	}
+	defer worker.Stop()
+	if worker.incremental != nil {
+		t.Error("BV_INCREMENTAL_LOAD=0 should disable incremental loading")
+	}
+}
+
 func TestBackgroundWorker_MetricsSnapshot(t *testing.T) {
 	t.Setenv("BV_WORKER_METRICS", "1")
 
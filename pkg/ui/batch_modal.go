@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// batchOp is an operation the batch modal runs on the marked issues.
+type batchOp int
+
+const (
+	batchNone batchOp = iota
+	batchCopyMarkdown
+	batchExportMarkdown
+	batchExportJSON
+	batchExportGraph
+	batchAgentPrompt
+	batchClaimScript
+	batchCloseScript
+)
+
+// batchMenu lists the modal's operations in display order.
+var batchMenu = []struct {
+	key  string
+	op   batchOp
+	desc string
+}{
+	{"m", batchCopyMarkdown, "copy as Markdown"},
+	{"e", batchExportMarkdown, "export Markdown file"},
+	{"j", batchExportJSON, "export JSON file"},
+	{"g", batchExportGraph, "export dependency graph (DOT)"},
+	{"a", batchAgentPrompt, "copy agent prompt"},
+	{"c", batchClaimScript, "bd script: claim all"},
+	{"x", batchCloseScript, "bd script: close all"},
+}
+
+// BatchModal picks an operation to run on the marked issues.
+type BatchModal struct {
+	count     int
+	chosen    batchOp
+	cancelled bool
+	theme     Theme
+	width     int
+}
+
+// NewBatchModal creates a modal for count marked issues.
+func NewBatchModal(count int, theme Theme) BatchModal {
+	return BatchModal{count: count, theme: theme, width: 46}
+}
+
+// Chosen returns the picked operation, or batchNone.
+func (m BatchModal) Chosen() batchOp { return m.chosen }
+
+// IsCancelled reports whether the user dismissed the modal.
+func (m BatchModal) IsCancelled() bool { return m.cancelled }
+
+// Update handles key input.
+func (m BatchModal) Update(msg tea.Msg) BatchModal {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m
+	}
+	switch s := key.String(); s {
+	case "esc", "q", "B":
+		m.cancelled = true
+	default:
+		for _, item := range batchMenu {
+			if item.key == s {
+				m.chosen = item.op
+			}
+		}
+	}
+	return m
+}
+
+// View renders the modal.
+func (m BatchModal) View() string {
+	r := m.theme.Renderer
+
+	modalStyle := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(1, 2).
+		Width(m.width)
+	headerStyle := r.NewStyle().Bold(true).Foreground(m.theme.Primary)
+	keyStyle := r.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+	footerStyle := r.NewStyle().Foreground(ColorFooterHint).Italic(true)
+
+	noun := "issues"
+	if m.count == 1 {
+		noun = "issue"
+	}
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("☑ Batch: %d %s marked", m.count, noun)))
+	b.WriteString("\n\n")
+	for _, item := range batchMenu {
+		b.WriteString(keyStyle.Render(fmt.Sprintf("%-3s", item.key)))
+		b.WriteString(" ")
+		b.WriteString(item.desc)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footerStyle.Render("esc cancel"))
+
+	return modalStyle.Render(b.String())
+}
+
+// CenterModal returns the modal centered in the given dimensions.
+func (m BatchModal) CenterModal(termWidth, termHeight int) string {
+	return lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, m.View())
+}
@@ -57,6 +57,9 @@ type BoardModel struct {
 	// expandedCardID tracks which card is currently expanded inline
 	// Empty string means no card is expanded
 	expandedCardID string
+
+	// Issues marked for batch actions, shared with the list and tree
+	marks *Selection
 }
 
 // searchMatch holds info about a matching card (bv-yg39)
@@ -350,6 +353,15 @@ func (b *BoardModel) CycleSwimLaneMode() {
 	b.regroupIssues()
 }
 
+// SetSwimLaneMode switches to mode and regroups issues if it changed
+func (b *BoardModel) SetSwimLaneMode(mode SwimLaneMode) {
+	if mode == b.swimLaneMode {
+		return
+	}
+	b.swimLaneMode = mode
+	b.regroupIssues()
+}
+
 // regroupIssues rebuilds columns based on current swimlane mode (bv-wjs0)
 func (b *BoardModel) regroupIssues() {
 	if b.boardState != nil {
@@ -868,6 +880,23 @@ func (b *BoardModel) TotalCount() int {
 // Inline card expansion (bv-i3ii)
 // ═══════════════════════════════════════════════════════════════════════════
 
+// SetSelection shares the marked-issue set used to badge cards.
+func (b *BoardModel) SetSelection(s *Selection) {
+	b.marks = s
+}
+
+// VisibleIssueIDs returns the IDs of every card on the board, column by
+// column.
+func (b *BoardModel) VisibleIssueIDs() []string {
+	var ids []string
+	for col := 0; col < 4; col++ {
+		for _, issue := range b.columns[col] {
+			ids = append(ids, issue.ID)
+		}
+	}
+	return ids
+}
+
 // ToggleExpand toggles inline expansion for the selected card
 // If a different card is expanded, it collapses that and expands the new one
 func (b *BoardModel) ToggleExpand() {
@@ -1155,7 +1184,7 @@ func (b BoardModel) View(width, height int) string {
 			Width(baseWidth).
 			Height(colHeight).
 			Padding(0, 1).
-			Border(lipgloss.RoundedBorder())
+			Border(t.PanelBorder)
 
 		if isFocused {
 			colStyle = colStyle.BorderForeground(columnColors[colIdx])
@@ -1300,17 +1329,17 @@ func (b BoardModel) renderCard(issue model.Issue, width int, selected bool, colI
 	if selected {
 		cardStyle = cardStyle.
 			Background(t.Highlight).
-			Border(lipgloss.RoundedBorder()).
+			Border(t.CardBorder).
 			BorderForeground(borderColor)
 	} else if isCurrentMatch {
 		// Highlight current match with subtle background (bv-yg39)
 		cardStyle = cardStyle.
 			Background(lipgloss.AdaptiveColor{Light: "#e1bee7", Dark: "#4a148c"}).
-			Border(lipgloss.RoundedBorder()).
+			Border(t.CardBorder).
 			BorderForeground(borderColor)
 	} else {
 		cardStyle = cardStyle.
-			Border(lipgloss.RoundedBorder()).
+			Border(t.CardBorder).
 			BorderForeground(borderColor)
 	}
 
@@ -1330,6 +1359,10 @@ func (b BoardModel) renderCard(issue model.Issue, width int, selected bool, colI
 
 	// Truncate ID for narrow cards - reserve space for age indicator
 	maxIDLen := width - 14 // Icon(2) + space + P#(2) + space + age(6) + spacing
+	marked := b.marks.Has(issue.ID)
+	if marked {
+		maxIDLen -= 2
+	}
 	if maxIDLen < 6 {
 		maxIDLen = 6
 	}
@@ -1349,6 +1382,9 @@ func (b BoardModel) renderCard(issue model.Issue, width int, selected bool, colI
 		t.Renderer.NewStyle().Bold(true).Foreground(t.Secondary).Render(displayID),
 		ageStyled,
 	)
+	if marked {
+		line1 = t.Renderer.NewStyle().Foreground(t.Primary).Bold(true).Render("✓") + " " + line1
+	}
 
 	// ══════════════════════════════════════════════════════════════════════════
 	// LINE 2: Title with full available width (bv-1daf)
@@ -1647,6 +1683,7 @@ func (b *BoardModel) renderDetailPanel(width, height int) string {
 			if len(issue.Labels) > 0 {
 				content.WriteString(fmt.Sprintf("**Labels:** %s\n\n", strings.Join(issue.Labels, ", ")))
 			}
+			content.WriteString(customFieldsMarkdown(issue.CustomFields))
 
 			// Dependencies - show with titles and status (bv-kklp)
 			// First count blocking deps to avoid empty "Blocked by:" header
@@ -1723,7 +1760,7 @@ func (b *BoardModel) renderDetailPanel(width, height int) string {
 
 	// Panel border style
 	panelStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.PanelBorder).
 		BorderForeground(t.Primary).
 		Width(width).
 		Height(height).
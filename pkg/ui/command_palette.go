@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteEntry is one command in the palette.
+type paletteEntry struct {
+	action   Action
+	category string
+	desc     string
+	keys     string // Current binding, "" when unbound
+}
+
+// CommandPaletteModel is a fuzzy-searchable list of every global and list
+// action with its current binding.
+type CommandPaletteModel struct {
+	entries       []paletteEntry
+	filtered      []paletteEntry
+	input         textinput.Model
+	selectedIndex int
+	width         int
+	height        int
+	theme         Theme
+}
+
+// NewCommandPaletteModel lists every action with its binding in keymap.
+func NewCommandPaletteModel(keymap *Keymap, theme Theme) CommandPaletteModel {
+	ti := textinput.New()
+	ti.Placeholder = "type a command..."
+	ti.CharLimit = 50
+	ti.Width = 40
+	ti.Focus()
+
+	p := CommandPaletteModel{input: ti, theme: theme}
+	p.SetKeymap(keymap)
+	return p
+}
+
+// SetKeymap refreshes the bindings shown for each action.
+func (p *CommandPaletteModel) SetKeymap(keymap *Keymap) {
+	p.entries = nil
+	for _, a := range Actions() {
+		if a.inView() {
+			continue // Board, tree, ... keys only work inside their view
+		}
+		p.entries = append(p.entries, paletteEntry{
+			action:   a,
+			category: a.Category(),
+			desc:     a.Desc(),
+			keys:     keymap.Label(a),
+		})
+	}
+	p.filter()
+}
+
+// SetSize updates the palette dimensions
+func (p *CommandPaletteModel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// MoveUp moves selection up
+func (p *CommandPaletteModel) MoveUp() {
+	if p.selectedIndex > 0 {
+		p.selectedIndex--
+	}
+}
+
+// MoveDown moves selection down
+func (p *CommandPaletteModel) MoveDown() {
+	if p.selectedIndex < len(p.filtered)-1 {
+		p.selectedIndex++
+	}
+}
+
+// Selected returns the highlighted action, or ActionNone if nothing matches.
+func (p *CommandPaletteModel) Selected() Action {
+	if p.selectedIndex < 0 || p.selectedIndex >= len(p.filtered) {
+		return ActionNone
+	}
+	return p.filtered[p.selectedIndex].action
+}
+
+// UpdateInput processes a key message for the text input
+func (p *CommandPaletteModel) UpdateInput(msg interface{}) {
+	p.input, _ = p.input.Update(msg)
+	p.filter()
+}
+
+// Reset clears the input and resets selection
+func (p *CommandPaletteModel) Reset() {
+	p.input.SetValue("")
+	p.selectedIndex = 0
+	p.filter()
+}
+
+// filter ranks entries against the query by the best fuzzy score of their
+// description, category or action name, keeping palette order on ties.
+func (p *CommandPaletteModel) filter() {
+	query := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	if query == "" {
+		p.filtered = p.entries
+		p.clampSelection()
+		return
+	}
+
+	type scored struct {
+		entry paletteEntry
+		score int
+	}
+	var matches []scored
+	for _, e := range p.entries {
+		score := fuzzyScore(e.desc, query)
+		if s := fuzzyScore(e.category+" "+e.desc, query); s > score {
+			score = s
+		}
+		if s := fuzzyScore(string(e.action), query); s > score {
+			score = s
+		}
+		if score > 0 {
+			matches = append(matches, scored{e, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	p.filtered = make([]paletteEntry, len(matches))
+	for i, match := range matches {
+		p.filtered[i] = match.entry
+	}
+	p.clampSelection()
+}
+
+func (p *CommandPaletteModel) clampSelection() {
+	if p.selectedIndex >= len(p.filtered) {
+		p.selectedIndex = len(p.filtered) - 1
+	}
+	if p.selectedIndex < 0 {
+		p.selectedIndex = 0
+	}
+}
+
+// View renders the command palette overlay
+func (p *CommandPaletteModel) View() string {
+	if p.width == 0 {
+		p.width = 80
+	}
+	if p.height == 0 {
+		p.height = 24
+	}
+
+	t := p.theme
+
+	boxWidth := 60
+	if p.width < 70 {
+		boxWidth = p.width - 10
+	}
+	if boxWidth < 36 {
+		boxWidth = 36
+	}
+
+	maxVisible := 14
+	if p.height < 24 {
+		maxVisible = p.height - 10
+	}
+	if maxVisible < 3 {
+		maxVisible = 3
+	}
+
+	var lines []string
+
+	titleStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+	lines = append(lines, titleStyle.Render("Command Palette"))
+	lines = append(lines, "")
+
+	inputStyle := t.Renderer.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(t.Secondary).
+		Padding(0, 1).
+		Width(boxWidth - 6)
+	lines = append(lines, inputStyle.Render(p.input.View()))
+	lines = append(lines, "")
+
+	if len(p.filtered) == 0 {
+		dimStyle := t.Renderer.NewStyle().
+			Foreground(t.Secondary).
+			Italic(true)
+		lines = append(lines, dimStyle.Render("  No matching commands"))
+	} else {
+		start := 0
+		if p.selectedIndex >= maxVisible {
+			start = p.selectedIndex - maxVisible + 1
+		}
+		end := start + maxVisible
+		if end > len(p.filtered) {
+			end = len(p.filtered)
+		}
+
+		keyWidth := 12
+		catWidth := 11
+		descWidth := boxWidth - 8 - keyWidth - catWidth
+		for i := start; i < end; i++ {
+			e := p.filtered[i]
+			isSelected := i == p.selectedIndex
+
+			descStyle := t.Renderer.NewStyle().Width(descWidth)
+			catStyle := t.Renderer.NewStyle().Foreground(t.Secondary).Width(catWidth)
+			keyStyle := t.Renderer.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "#7D56F4", Dark: "#BD93F9"}).
+				Bold(true).
+				Width(keyWidth).
+				Align(lipgloss.Right)
+			prefix := "  "
+			if isSelected {
+				prefix = "> "
+				descStyle = descStyle.Foreground(t.Primary).Bold(true)
+			} else {
+				descStyle = descStyle.Foreground(t.Base.GetForeground())
+			}
+
+			keys := e.keys
+			if keys == "" {
+				keys = "unbound"
+				keyStyle = keyStyle.Foreground(ColorFooterHint).Bold(false).Italic(true)
+			}
+			lines = append(lines, prefix+
+				catStyle.Render(e.category)+
+				descStyle.Render(truncateRunesHelper(e.desc, descWidth, "…"))+
+				keyStyle.Render(truncateRunesHelper(keys, keyWidth, "…")))
+		}
+
+		if len(p.filtered) > maxVisible {
+			countStyle := t.Renderer.NewStyle().
+				Foreground(t.Secondary).
+				Italic(true)
+			lines = append(lines, "")
+			lines = append(lines, countStyle.Render(
+				"  "+strings.Repeat(" ", boxWidth/2-10)+
+					"("+itoa(p.selectedIndex+1)+"/"+itoa(len(p.filtered))+")",
+			))
+		}
+	}
+
+	lines = append(lines, "")
+	footerStyle := t.Renderer.NewStyle().
+		Foreground(ColorFooterHint).
+		Italic(true)
+	lines = append(lines, footerStyle.Render("↑/↓: navigate | enter: run | esc: cancel"))
+
+	content := strings.Join(lines, "\n")
+
+	boxStyle := t.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		p.width,
+		p.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCommandPaletteListsEveryAction(t *testing.T) {
+	km := DefaultKeymap()
+	if err := km.Apply(map[string][]string{"board": {"B"}, "graph": {}}); err != nil {
+		t.Fatal(err)
+	}
+	p := NewCommandPaletteModel(km, DefaultTheme(nil))
+	want := 0
+	for _, a := range Actions() {
+		if !a.inView() {
+			want++
+		}
+	}
+	if len(p.filtered) != want {
+		t.Fatalf("expected %d entries, got %d", want, len(p.filtered))
+	}
+	for _, e := range p.filtered {
+		if e.action == ActionBoardLeft {
+			t.Error("board keys only work on the board and should not be listed")
+		}
+	}
+	p.SetSize(100, 60)
+	view := p.View()
+	for _, want := range []string{"Command Palette", "Kanban board", "unbound"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+}
+
+func TestCommandPaletteFuzzyFilter(t *testing.T) {
+	p := NewCommandPaletteModel(DefaultKeymap(), DefaultTheme(nil))
+	for _, r := range "flow met" {
+		p.UpdateInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := p.Selected(); got != ActionFlowMetrics {
+		t.Fatalf("expected flow metrics first, got %q", got)
+	}
+
+	p.Reset()
+	for _, r := range "zzzz" {
+		p.UpdateInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := p.Selected(); got != ActionNone {
+		t.Errorf("no match should select nothing, got %q", got)
+	}
+}
+
+func TestCommandPaletteRunsActions(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "1", Title: "One", Status: model.StatusOpen},
+		{ID: "2", Title: "Two", Status: model.StatusClosed},
+	}
+	m := NewModel(issues, nil, "")
+	send := func(msg tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	typeText := func(s string) {
+		t.Helper()
+		for _, r := range s {
+			send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if !m.showCommandPalette || m.FocusState() != "command_palette" {
+		t.Fatalf("expected palette open, focus %s", m.FocusState())
+	}
+	// Letters go to the palette input, not to global bindings.
+	typeText("board")
+	if m.IsBoardView() {
+		t.Fatal("typing in the palette must not trigger bindings")
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.showCommandPalette || !m.IsBoardView() {
+		t.Fatalf("enter should run the board action, focus %s", m.FocusState())
+	}
+
+	// Actions without a key are reachable from the palette, and Esc restores
+	// the previous focus.
+	send(tea.KeyMsg{Type: tea.KeyCtrlP})
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showCommandPalette || m.FocusState() != "board" {
+		t.Fatalf("esc should close the palette back to the board, focus %s", m.FocusState())
+	}
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m.currentFilter = "open"
+	m.applyFilter()
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	typeText("all issues")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentFilter != "all" {
+		t.Errorf("expected filter_all to run, filter %q", m.currentFilter)
+	}
+}
+
+func TestHelpAndSidebarFollowKeymap(t *testing.T) {
+	m := NewModel([]model.Issue{{ID: "1", Title: "One", Status: model.StatusOpen}}, nil, "")
+	km := DefaultKeymap()
+	if err := km.Apply(map[string][]string{"board": {"ctrl+b"}, "flow_matrix": {}}); err != nil {
+		t.Fatal(err)
+	}
+	m.keymap = km
+	m.width, m.height = 160, 60
+
+	help := m.renderHelpOverlay()
+	if !strings.Contains(help, "Ctrl+B") {
+		t.Error("help should show the remapped board key")
+	}
+	if strings.Contains(help, "Flow matrix") {
+		t.Error("help should omit unbound actions")
+	}
+
+	sidebar := NewShortcutsSidebar(m.theme)
+	sidebar.SetKeymap(km)
+	sidebar.SetSize(34, 200)
+	view := sidebar.View()
+	if !strings.Contains(view, "Ctrl+B") {
+		t.Error("sidebar should show the remapped board key")
+	}
+}
@@ -11,6 +11,8 @@ const (
 	// Overlays (highest priority)
 	ContextLabelPicker        Context = "label-picker"
 	ContextRecipePicker       Context = "recipe-picker"
+	ContextSessionPicker      Context = "session-picker"
+	ContextThemePicker        Context = "theme-picker"
 	ContextHelp               Context = "help"
 	ContextQuitConfirm        Context = "quit-confirm"
 	ContextLabelHealthDetail  Context = "label-health-detail"
@@ -81,6 +83,16 @@ func (m Model) CurrentContext() Context {
 		return ContextRecipePicker
 	}
 
+	// Session picker overlay
+	if m.showSessionPicker {
+		return ContextSessionPicker
+	}
+
+	// Theme picker overlay
+	if m.showThemePicker {
+		return ContextThemePicker
+	}
+
 	// Label health detail modal
 	if m.showLabelHealthDetail {
 		return ContextLabelHealthDetail
@@ -191,6 +203,8 @@ func (c Context) Description() string {
 	descriptions := map[Context]string{
 		ContextLabelPicker:        "Label picker",
 		ContextRecipePicker:       "Recipe picker",
+		ContextSessionPicker:      "Session picker",
+		ContextThemePicker:        "Theme picker",
 		ContextHelp:               "Help overlay",
 		ContextQuitConfirm:        "Quit confirmation",
 		ContextLabelHealthDetail:  "Label health detail",
@@ -225,7 +239,7 @@ func (c Context) Description() string {
 // IsOverlay returns true if the context is an overlay (modal/popup)
 func (c Context) IsOverlay() bool {
 	switch c {
-	case ContextLabelPicker, ContextRecipePicker, ContextHelp, ContextQuitConfirm,
+	case ContextLabelPicker, ContextRecipePicker, ContextSessionPicker, ContextThemePicker, ContextHelp, ContextQuitConfirm,
 		ContextLabelHealthDetail, ContextLabelDrilldown, ContextLabelGraphAnalysis,
 		ContextTimeTravelInput, ContextAlerts, ContextRepoPicker, ContextAgentPrompt,
 		ContextCassSession:
@@ -268,6 +282,8 @@ func (c Context) TutorialPages() []int {
 		ContextAlerts:             {15},      // Alerts
 		ContextLabelPicker:        {11, 3},   // Labels, Filtering
 		ContextRecipePicker:       {3, 12},   // Filtering, Advanced
+		ContextSessionPicker:      {12},      // Advanced
+		ContextThemePicker:        {12},      // Advanced
 		ContextRepoPicker:         {12},      // Advanced (workspace)
 		ContextAgentPrompt:        {16},      // AI Agent Integration
 		ContextLabelHealthDetail:  {11},      // Labels
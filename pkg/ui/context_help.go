@@ -20,6 +20,8 @@ var ContextHelpContent = map[Context]string{
 	ContextFilter:         contextHelpFilter,
 	ContextLabelPicker:    contextHelpLabelPicker,
 	ContextRecipePicker:   contextHelpRecipePicker,
+	ContextSessionPicker:  contextHelpSessionPicker,
+	ContextThemePicker:    contextHelpThemePicker,
 	ContextHelp:           contextHelpHelp,
 	ContextTimeTravel:     contextHelpTimeTravel,
 	ContextLabelDashboard: contextHelpLabelDashboard,
@@ -115,8 +117,11 @@ const contextHelpList = `## List View
   h         History view
 
 **Actions**
+  M         Claim, close, relabel, link
   U         Self-update bv
-  V         Preview cass sessions`
+  V         Preview cass sessions
+  Space     Mark (Shift+↑↓ range, Ctrl+A all)
+  B         Batch actions on marked`
 
 const contextHelpGraph = `## Graph View
 
@@ -299,6 +304,36 @@ Pre-configured filters and sorts:
 • By Priority
 • Recently Updated`
 
+const contextHelpSessionPicker = `## Session Picker
+
+**Navigation**
+  j/k       Move selection
+  Enter     Switch to session
+  n         Save current view as...
+  d         Delete session
+  Esc       Close
+
+**Sessions**
+A session remembers the view, recipe,
+filters, search, sort, selected issue,
+board grouping and pane layout. The
+active session (●) is saved on exit and
+restored the next time bv starts.`
+
+const contextHelpThemePicker = `## Theme Picker
+
+**Navigation**
+  j/k       Preview theme
+  Enter     Use theme
+  Esc       Revert and close
+
+**Themes**
+Bundled: solarized, gruvbox,
+high-contrast, colorblind-safe.
+Add your own in ~/.config/bv/themes/
+or .bv/theme.yaml (loaded at startup).
+Start with one via --theme NAME|FILE.`
+
 const contextHelpHelp = `## Help Overlay
 
 You're looking at the help overlay!
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,8 +18,10 @@ type IssueDelegate struct {
 	Theme             Theme
 	ShowPriorityHints bool
 	PriorityHints     map[string]*analysis.PriorityRecommendation
-	WorkspaceMode     bool // When true, shows repo prefix badges
-	ShowSearchScores  bool // Show semantic/hybrid score badge when search is active
+	WorkspaceMode     bool           // When true, shows repo prefix badges
+	ShowSearchScores  bool           // Show semantic/hybrid score badge when search is active
+	Marks             *Selection     // Issues marked for batch actions
+	Matches           *searchMatches // Where each issue matched the "/" filter text
 }
 
 func (d IssueDelegate) Height() int {
@@ -176,13 +179,31 @@ func (d IssueDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 		titleWidth = 5
 	}
 
+	// Where a "/" search matched, when not the title, trails the title
+	var matchText string
+	if d.Matches != nil && m.FilterState() != list.Unfiltered {
+		if match, ok := d.Matches.match(search.QueryText(m.FilterInput.Value()), i.Issue.ID); ok && match.Field != search.ChunkFieldTitle {
+			matchText = searchMatchSource(match) + ": " + match.Snippet
+		}
+	}
+	if matchText != "" && titleWidth >= 30 {
+		title = truncateRunesHelper(title, titleWidth/2, "…")
+		matchText = truncateRunesHelper(matchText, titleWidth-lipgloss.Width(title)-3, "…")
+	} else {
+		matchText = ""
+	}
+
 	// Truncate title if needed
 	title = truncateRunesHelper(title, titleWidth, "…")
 
 	// Pad title to fill space
 	currentWidth := lipgloss.Width(title)
+	if matchText != "" {
+		currentWidth += 3 + lipgloss.Width(matchText)
+	}
+	titlePad := ""
 	if currentWidth < titleWidth {
-		title = title + strings.Repeat(" ", titleWidth-currentWidth)
+		titlePad = strings.Repeat(" ", titleWidth-currentWidth)
 	}
 
 	// ══════════════════════════════════════════════════════════════════════════
@@ -190,9 +211,17 @@ func (d IssueDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 	// ══════════════════════════════════════════════════════════════════════════
 	var leftSide strings.Builder
 
-	// Selection indicator with accent color (using pre-computed style)
+	// Selection indicator with accent color (using pre-computed style);
+	// the second cell shows the batch mark
+	cursor, mark := " ", " "
 	if isSelected {
-		leftSide.WriteString(t.PrimaryBold.Render("▸ "))
+		cursor = "▸"
+	}
+	if d.Marks.Has(i.Issue.ID) {
+		mark = "✓"
+	}
+	if isSelected || mark != " " {
+		leftSide.WriteString(t.PrimaryBold.Render(cursor + mark))
 	} else {
 		leftSide.WriteString("  ")
 	}
@@ -271,6 +300,10 @@ func (d IssueDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 		titleStyle = titleStyle.Foreground(lipgloss.AdaptiveColor{Light: "#333333", Dark: "#E8E8E8"})
 	}
 	leftSide.WriteString(titleStyle.Render(title))
+	if matchText != "" {
+		leftSide.WriteString(t.MutedText.Render(" · " + matchText))
+	}
+	leftSide.WriteString(titlePad)
 
 	// Right side
 	rightSide := strings.Join(rightParts, " ")
@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// flowDimension selects how the flow metrics table is sliced
+type flowDimension int
+
+const (
+	flowByLabel flowDimension = iota
+	flowByType
+	flowByAssignee
+	flowByPriority
+	flowDimensionCount
+)
+
+func (d flowDimension) String() string {
+	switch d {
+	case flowByType:
+		return "Type"
+	case flowByAssignee:
+		return "Assignee"
+	case flowByPriority:
+		return "Priority"
+	default:
+		return "Label"
+	}
+}
+
+// flowRow is one slice of the flow metrics table
+type flowRow struct {
+	Key   string
+	Slice analysis.FlowSlice
+}
+
+// FlowMetricsModel is a dashboard of lead time, cycle time, time in status
+// and throughput, sliced by label, type, assignee or priority
+type FlowMetricsModel struct {
+	report       analysis.FlowReport
+	dimension    flowDimension
+	rows         []flowRow
+	cursor       int
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewFlowMetricsModel creates an empty flow metrics dashboard
+func NewFlowMetricsModel(theme Theme) FlowMetricsModel {
+	return FlowMetricsModel{theme: theme}
+}
+
+// SetData recomputes the flow metrics. history may be nil, in which case
+// only lead time and throughput are available.
+func (m *FlowMetricsModel) SetData(issues []model.Issue, history map[string][]analysis.StatusChange) {
+	m.report = analysis.ComputeFlowMetrics(issues, history, analysis.DefaultFlowConfig(), time.Now().UTC())
+	m.rebuildRows()
+}
+
+// SetSize updates the view dimensions
+func (m *FlowMetricsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Report returns the computed flow report
+func (m *FlowMetricsModel) Report() analysis.FlowReport {
+	return m.report
+}
+
+func (m *FlowMetricsModel) rebuildRows() {
+	var slices map[string]analysis.FlowSlice
+	switch m.dimension {
+	case flowByType:
+		slices = m.report.ByType
+	case flowByAssignee:
+		slices = m.report.ByAssignee
+	case flowByPriority:
+		slices = m.report.ByPriority
+	default:
+		slices = m.report.ByLabel
+	}
+
+	m.rows = make([]flowRow, 0, len(slices))
+	for key, slice := range slices {
+		m.rows = append(m.rows, flowRow{Key: key, Slice: slice})
+	}
+	// Priorities read best in order; everything else by throughput
+	sort.Slice(m.rows, func(i, j int) bool {
+		if m.dimension != flowByPriority && m.rows[i].Slice.Throughput.Closed != m.rows[j].Slice.Throughput.Closed {
+			return m.rows[i].Slice.Throughput.Closed > m.rows[j].Slice.Throughput.Closed
+		}
+		return m.rows[i].Key < m.rows[j].Key
+	})
+
+	if m.cursor >= len(m.rows) {
+		m.cursor = max(len(m.rows)-1, 0)
+	}
+	m.ensureVisible()
+}
+
+// Update handles navigation keys; returns the selected label on enter when
+// slicing by label
+func (m *FlowMetricsModel) Update(msg tea.KeyMsg) string {
+	switch msg.String() {
+	case "j", "down":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "g", "home":
+		m.cursor = 0
+	case "G", "end":
+		m.cursor = max(len(m.rows)-1, 0)
+	case "tab", "l", "right":
+		m.dimension = (m.dimension + 1) % flowDimensionCount
+		m.cursor, m.scrollOffset = 0, 0
+		m.rebuildRows()
+	case "shift+tab", "h", "left":
+		m.dimension = (m.dimension + flowDimensionCount - 1) % flowDimensionCount
+		m.cursor, m.scrollOffset = 0, 0
+		m.rebuildRows()
+	case "enter":
+		if m.dimension == flowByLabel && m.cursor < len(m.rows) {
+			return m.rows[m.cursor].Key
+		}
+	}
+	m.ensureVisible()
+	return ""
+}
+
+// ensureVisible adjusts scroll offset to keep cursor visible
+func (m *FlowMetricsModel) ensureVisible() {
+	visibleRows := m.visibleRowCount()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	} else if m.cursor >= m.scrollOffset+visibleRows {
+		m.scrollOffset = m.cursor - visibleRows + 1
+	}
+}
+
+// visibleRowCount returns how many table rows fit between the summary,
+// header, time-in-status panel and footer
+func (m *FlowMetricsModel) visibleRowCount() int {
+	available := m.height - 12
+	if available < 1 {
+		return 1
+	}
+	return available
+}
+
+// formatFlowHours renders a percentile in hours compactly; "-" when there
+// are no samples
+func formatFlowHours(hours float64, count int) string {
+	if count == 0 {
+		return "-"
+	}
+	return formatDuration(time.Duration(hours * float64(time.Hour)))
+}
+
+// View renders the dashboard
+func (m *FlowMetricsModel) View() string {
+	if m.width == 0 {
+		m.width = 80
+	}
+	if m.height == 0 {
+		m.height = 20
+	}
+
+	t := m.theme
+	r := t.Renderer
+	dimStyle := r.NewStyle().Foreground(t.Secondary).Italic(true)
+
+	var sb strings.Builder
+
+	// Title and overall summary
+	sb.WriteString(r.NewStyle().Foreground(t.Primary).Bold(true).Render("Flow Metrics"))
+	source := fmt.Sprintf("  last %d days • status history: %s", m.report.WindowDays, m.report.StatusSource)
+	if m.report.WindowDays == 0 {
+		source = fmt.Sprintf("  all time • status history: %s", m.report.StatusSource)
+	}
+	sb.WriteString(dimStyle.Render(source))
+	sb.WriteString("\n\n")
+
+	overall := m.report.Overall
+	labelStyle := r.NewStyle().Foreground(t.Secondary).Bold(true)
+	summary := func(name string, p analysis.FlowPercentiles) string {
+		return labelStyle.Render(name) + fmt.Sprintf(" p50 %s  p85 %s  p95 %s  (n=%d)",
+			formatFlowHours(p.P50, p.Count), formatFlowHours(p.P85, p.Count), formatFlowHours(p.P95, p.Count), p.Count)
+	}
+	sb.WriteString(summary("Lead time ", overall.LeadTime))
+	sb.WriteString("\n")
+	sb.WriteString(summary("Cycle time", overall.CycleTime))
+	sb.WriteString("\n")
+	sb.WriteString(labelStyle.Render("Throughput"))
+	sb.WriteString(fmt.Sprintf(" %d closed • %.1f/week • %s %s",
+		overall.Throughput.Closed, overall.Throughput.WeeklyAverage, overall.Throughput.Trend,
+		throughputSparkline(overall.Throughput)))
+	sb.WriteString("\n\n")
+
+	// Dimension tabs
+	for d := flowDimension(0); d < flowDimensionCount; d++ {
+		style := r.NewStyle().Foreground(t.Secondary)
+		if d == m.dimension {
+			style = r.NewStyle().Foreground(t.Primary).Bold(true).Underline(true)
+		}
+		sb.WriteString(style.Render(d.String()))
+		sb.WriteString("  ")
+	}
+	sb.WriteString("\n")
+
+	// Table
+	keyWidth := 18
+	if m.width > 100 {
+		keyWidth = min(m.width-82, 30)
+	}
+	header := fmt.Sprintf("  %-*s %5s %5s │ %6s %6s %6s │ %6s %6s %6s │ %s",
+		keyWidth, m.dimension.String(), "N", "Done",
+		"Lead50", "85", "95", "Cycl50", "85", "95", "Weekly")
+	sb.WriteString(r.NewStyle().Foreground(t.Secondary).Bold(true).Render(header))
+	sb.WriteString("\n")
+
+	if len(m.rows) == 0 {
+		sb.WriteString(dimStyle.Render("  No flow data available"))
+		sb.WriteString("\n")
+	} else {
+		end := min(m.scrollOffset+m.visibleRowCount(), len(m.rows))
+		for i := m.scrollOffset; i < end; i++ {
+			row := m.rows[i]
+			s := row.Slice
+			prefix := "  "
+			rowStyle := r.NewStyle()
+			if i == m.cursor {
+				prefix = "> "
+				rowStyle = rowStyle.Foreground(t.Primary).Bold(true).Background(ThemeBg("#333"))
+			}
+			line := fmt.Sprintf("%-*s %5d %5d │ %6s %6s %6s │ %6s %6s %6s │ ",
+				keyWidth, truncateRunesHelper(row.Key, keyWidth, "…"), s.Issues, s.Throughput.Closed,
+				formatFlowHours(s.LeadTime.P50, s.LeadTime.Count),
+				formatFlowHours(s.LeadTime.P85, s.LeadTime.Count),
+				formatFlowHours(s.LeadTime.P95, s.LeadTime.Count),
+				formatFlowHours(s.CycleTime.P50, s.CycleTime.Count),
+				formatFlowHours(s.CycleTime.P85, s.CycleTime.Count),
+				formatFlowHours(s.CycleTime.P95, s.CycleTime.Count))
+			sb.WriteString(rowStyle.Render(prefix + line))
+			sb.WriteString(r.NewStyle().Foreground(ThemeFg("#88aaff")).Render(throughputSparkline(s.Throughput)))
+			sb.WriteString("\n")
+		}
+		if len(m.rows) > m.visibleRowCount() {
+			sb.WriteString(dimStyle.Render(fmt.Sprintf("  [%d-%d of %d]", m.scrollOffset+1, end, len(m.rows))))
+			sb.WriteString("\n")
+		}
+	}
+
+	// Time in status for the selected slice
+	sb.WriteString("\n")
+	sb.WriteString(m.renderTimeInStatus())
+	sb.WriteString("\n\n")
+
+	footerStyle := r.NewStyle().Foreground(ColorFooterHint).Italic(true)
+	sb.WriteString(footerStyle.Render("j/k: navigate | tab: slice by | enter: filter by label | esc: back"))
+
+	return sb.String()
+}
+
+// renderTimeInStatus summarizes the p85 time spent in each status for the
+// selected row
+func (m *FlowMetricsModel) renderTimeInStatus() string {
+	t := m.theme
+	r := t.Renderer
+	if m.report.StatusSource != "git" {
+		return r.NewStyle().Foreground(t.Secondary).Italic(true).
+			Render("Time in status needs git history of the beads file")
+	}
+	if len(m.rows) == 0 || m.cursor >= len(m.rows) {
+		return ""
+	}
+	row := m.rows[m.cursor]
+	statuses := make([]string, 0, len(row.Slice.TimeInStatus))
+	for status := range row.Slice.TimeInStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var parts []string
+	for _, status := range statuses {
+		p := row.Slice.TimeInStatus[status]
+		style := r.NewStyle().Foreground(t.GetStatusColor(status))
+		parts = append(parts, style.Render(status)+" p85 "+formatFlowHours(p.P85, p.Count))
+	}
+	label := r.NewStyle().Foreground(t.Secondary).Bold(true).Render("Time in status (" + row.Key + "): ")
+	if len(parts) == 0 {
+		return label + "no transitions recorded"
+	}
+	return label + strings.Join(parts, " • ")
+}
+
+// throughputSparkline renders weekly closures oldest to newest
+func throughputSparkline(tp analysis.ThroughputMetrics) string {
+	values := make([]int, len(tp.Weekly))
+	maxVal := 0
+	for i, w := range tp.Weekly {
+		values[len(tp.Weekly)-1-i] = w.Closed
+		if w.Closed > maxVal {
+			maxVal = w.Closed
+		}
+	}
+	if maxVal == 0 {
+		return strings.Repeat(" ", len(values))
+	}
+	return buildSparkline(values, maxVal)
+}
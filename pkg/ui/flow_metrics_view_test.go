@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFlowMetricsSetData(t *testing.T) {
+	now := time.Now().UTC()
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusClosed, IssueType: model.TypeBug, Priority: 1, Labels: []string{"api"},
+			CreatedAt: now.Add(-72 * time.Hour), ClosedAt: timePtr(now.Add(-24 * time.Hour))},
+		{ID: "B", Status: model.StatusClosed, IssueType: model.TypeTask, Priority: 2, Labels: []string{"api", "ui"},
+			CreatedAt: now.Add(-48 * time.Hour), ClosedAt: timePtr(now.Add(-2 * time.Hour))},
+		{ID: "C", Status: model.StatusBlocked, IssueType: model.TypeTask, Priority: 2, Labels: []string{"ui"},
+			CreatedAt: now.Add(-10 * time.Hour)},
+	}
+
+	tests := []struct {
+		name       string
+		history    map[string][]analysis.StatusChange
+		wantSource string
+		wantLead   int
+		wantCycle  int
+	}{
+		{
+			name: "git history",
+			history: map[string][]analysis.StatusChange{
+				"A": {{Status: model.StatusOpen, At: now.Add(-72 * time.Hour)}, {Status: model.StatusInProgress, At: now.Add(-36 * time.Hour)}, {Status: model.StatusClosed, At: now.Add(-24 * time.Hour)}},
+				"C": {{Status: model.StatusOpen, At: now.Add(-10 * time.Hour)}, {Status: model.StatusBlocked, At: now.Add(-5 * time.Hour)}},
+			},
+			wantSource: "git",
+			wantLead:   2,
+			wantCycle:  1,
+		},
+		{name: "snapshot only", wantSource: "snapshot", wantLead: 2, wantCycle: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewFlowMetricsModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+			m.SetData(issues, tt.history)
+
+			report := m.Report()
+			if report.StatusSource != tt.wantSource || report.Overall.LeadTime.Count != tt.wantLead || report.Overall.CycleTime.Count != tt.wantCycle {
+				t.Errorf("unexpected report: source %s, %+v", report.StatusSource, report.Overall)
+			}
+			// Label rows are ordered by closures: api (2) before ui (1)
+			if len(m.rows) != 2 || m.rows[0].Key != "api" {
+				t.Fatalf("unexpected label rows: %+v", m.rows)
+			}
+		})
+	}
+}
+
+func TestFlowMetricsDimensionSwitching(t *testing.T) {
+	now := time.Now().UTC()
+	m := NewFlowMetricsModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+	m.SetData([]model.Issue{
+		{ID: "A", Status: model.StatusClosed, IssueType: model.TypeBug, Priority: 1, Labels: []string{"api"},
+			CreatedAt: now.Add(-72 * time.Hour), ClosedAt: timePtr(now.Add(-24 * time.Hour))},
+		{ID: "B", Status: model.StatusClosed, IssueType: model.TypeTask, Priority: 2, Labels: []string{"api", "ui"},
+			CreatedAt: now.Add(-48 * time.Hour), ClosedAt: timePtr(now.Add(-2 * time.Hour))},
+		{ID: "C", Status: model.StatusBlocked, IssueType: model.TypeTask, Priority: 2, Labels: []string{"ui"},
+			CreatedAt: now.Add(-10 * time.Hour)},
+	}, nil)
+
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	shiftTab := tea.KeyMsg{Type: tea.KeyShiftTab}
+	steps := []struct {
+		name          string
+		keys          []tea.KeyMsg
+		wantDimension flowDimension
+		wantRows      int // -1 to skip
+		wantFirstRow  string
+		wantEnter     string
+	}{
+		{name: "tab moves to type", keys: []tea.KeyMsg{tab}, wantDimension: flowByType, wantRows: -1},
+		{name: "priority rows are sorted by key", keys: []tea.KeyMsg{tab, tab}, wantDimension: flowByPriority, wantRows: 2, wantFirstRow: "P1"},
+		{name: "shift+tab cycles back to label", keys: []tea.KeyMsg{shiftTab, shiftTab, shiftTab}, wantDimension: flowByLabel, wantRows: 2, wantFirstRow: "api", wantEnter: "api"},
+		{name: "enter filters by the selected label", keys: []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune{'j'}}}, wantDimension: flowByLabel, wantRows: 2, wantEnter: "ui"},
+	}
+	for _, step := range steps {
+		for _, k := range step.keys {
+			m.Update(k)
+		}
+		if m.dimension != step.wantDimension {
+			t.Fatalf("%s: dimension %s, want %s", step.name, m.dimension, step.wantDimension)
+		}
+		if step.wantRows >= 0 && len(m.rows) != step.wantRows {
+			t.Fatalf("%s: rows %+v", step.name, m.rows)
+		}
+		if step.wantFirstRow != "" && m.rows[0].Key != step.wantFirstRow {
+			t.Errorf("%s: first row %s, want %s", step.name, m.rows[0].Key, step.wantFirstRow)
+		}
+		// Enter only filters when slicing by label
+		if got := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); got != step.wantEnter {
+			t.Errorf("%s: enter returned %q, want %q", step.name, got, step.wantEnter)
+		}
+	}
+}
+
+func TestFlowMetricsView(t *testing.T) {
+	now := time.Now().UTC()
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusClosed, IssueType: model.TypeBug, Priority: 1, Labels: []string{"api"},
+			CreatedAt: now.Add(-72 * time.Hour), ClosedAt: timePtr(now.Add(-24 * time.Hour))},
+		{ID: "C", Status: model.StatusBlocked, IssueType: model.TypeTask, Priority: 2,
+			CreatedAt: now.Add(-10 * time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		history map[string][]analysis.StatusChange
+		want    []string
+		notWant string
+	}{
+		{
+			name: "git history",
+			history: map[string][]analysis.StatusChange{
+				"A": {{Status: model.StatusOpen, At: now.Add(-72 * time.Hour)}, {Status: model.StatusInProgress, At: now.Add(-36 * time.Hour)}, {Status: model.StatusClosed, At: now.Add(-24 * time.Hour)}},
+			},
+			want:    []string{"Flow Metrics", "Lead time", "Cycle time", "Throughput", "api", "Time in status"},
+			notWant: "needs git history",
+		},
+		{name: "snapshot only", want: []string{"Flow Metrics", "needs git history"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewFlowMetricsModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+			m.SetData(issues, tt.history)
+			m.SetSize(120, 30)
+
+			out := m.View()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("view missing %q", want)
+				}
+			}
+			if tt.notWant != "" && strings.Contains(out, tt.notWant) {
+				t.Errorf("view should not contain %q", tt.notWant)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// forecastTrials keeps the TUI responsive; --robot-forecast can run more
+const forecastTrials = 1000
+
+// forecastScope is one set of issues the forecast view can simulate
+type forecastScope struct {
+	Kind   string // "issue", "epic", "label" or "all"
+	Target string
+	IDs    []string
+}
+
+// ForecastModel shows the Monte Carlo completion-date distribution for the
+// selected issue, its epic, its label or every open issue as a histogram
+type ForecastModel struct {
+	issues   []model.Issue
+	history  map[string][]analysis.StatusChange
+	scopes   []forecastScope
+	scope    int
+	agents   int
+	forecast analysis.MonteCarloForecast
+	now      time.Time
+	width    int
+	height   int
+	theme    Theme
+}
+
+// NewForecastModel creates an empty forecast view
+func NewForecastModel(theme Theme) ForecastModel {
+	return ForecastModel{theme: theme, agents: 1}
+}
+
+// SetData builds the scopes around issueID and simulates the first one.
+// history may be nil, in which case lead times stand in for cycle times.
+func (m *ForecastModel) SetData(issues []model.Issue, issueID string, history map[string][]analysis.StatusChange) {
+	m.issues = issues
+	m.history = history
+	m.now = time.Now()
+	m.scopes = forecastScopes(issues, issueID)
+	m.scope = 0
+	m.simulate()
+}
+
+// SetSize updates the view dimensions
+func (m *ForecastModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Forecast returns the distribution for the current scope
+func (m *ForecastModel) Forecast() analysis.MonteCarloForecast {
+	return m.forecast
+}
+
+// Update handles scope and agent keys
+func (m *ForecastModel) Update(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "tab", "l", "right":
+		if len(m.scopes) > 0 {
+			m.scope = (m.scope + 1) % len(m.scopes)
+			m.simulate()
+		}
+	case "shift+tab", "h", "left":
+		if len(m.scopes) > 0 {
+			m.scope = (m.scope + len(m.scopes) - 1) % len(m.scopes)
+			m.simulate()
+		}
+	case "+", "=":
+		m.agents++
+		m.simulate()
+	case "-":
+		if m.agents > 1 {
+			m.agents--
+			m.simulate()
+		}
+	}
+}
+
+func (m *ForecastModel) simulate() {
+	if m.scope >= len(m.scopes) {
+		m.forecast = analysis.MonteCarloForecast{}
+		return
+	}
+	sc := m.scopes[m.scope]
+	m.forecast = analysis.SimulateDelivery(m.issues, sc.IDs, analysis.MonteCarloOptions{
+		Trials:  forecastTrials,
+		Agents:  m.agents,
+		Now:     m.now,
+		History: m.history,
+	})
+	m.forecast.Scope, m.forecast.Target = sc.Kind, sc.Target
+}
+
+// forecastScopes lists the scopes around an issue: the issue itself, the
+// epic it belongs to (or its own descendants when it is an epic), each of
+// its labels and the whole project
+func forecastScopes(issues []model.Issue, issueID string) []forecastScope {
+	byID := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		byID[iss.ID] = iss
+	}
+	var scopes []forecastScope
+	iss, ok := byID[issueID]
+	if ok {
+		if iss.IssueType == model.TypeEpic {
+			scopes = append(scopes, forecastScope{Kind: "epic", Target: iss.ID, IDs: analysis.EpicDescendants(issues, iss.ID)})
+		} else {
+			scopes = append(scopes, forecastScope{Kind: "issue", Target: iss.ID, IDs: []string{iss.ID}})
+			if epic := enclosingEpic(byID, iss); epic != "" {
+				scopes = append(scopes, forecastScope{Kind: "epic", Target: epic, IDs: analysis.EpicDescendants(issues, epic)})
+			}
+		}
+		for _, label := range iss.Labels {
+			var ids []string
+			for _, other := range issues {
+				for _, l := range other.Labels {
+					if l == label {
+						ids = append(ids, other.ID)
+						break
+					}
+				}
+			}
+			scopes = append(scopes, forecastScope{Kind: "label", Target: label, IDs: ids})
+		}
+	}
+	all := make([]string, 0, len(issues))
+	for _, other := range issues {
+		all = append(all, other.ID)
+	}
+	return append(scopes, forecastScope{Kind: "all", IDs: all})
+}
+
+// enclosingEpic walks parent-child links up from iss to the nearest epic
+func enclosingEpic(byID map[string]model.Issue, iss model.Issue) string {
+	seen := map[string]bool{iss.ID: true}
+	for {
+		parent := ""
+		for _, dep := range iss.Dependencies {
+			if dep != nil && dep.Type == model.DepParentChild {
+				parent = dep.DependsOnID
+				break
+			}
+		}
+		next, ok := byID[parent]
+		if !ok || seen[parent] {
+			return ""
+		}
+		if next.IssueType == model.TypeEpic {
+			return parent
+		}
+		seen[parent] = true
+		iss = next
+	}
+}
+
+// View renders the percentiles and the histogram
+func (m *ForecastModel) View() string {
+	if m.width == 0 {
+		m.width = 80
+	}
+	if m.height == 0 {
+		m.height = 20
+	}
+
+	t := m.theme
+	r := t.Renderer
+	fc := m.forecast
+	dimStyle := r.NewStyle().Foreground(t.Secondary).Italic(true)
+	labelStyle := r.NewStyle().Foreground(t.Secondary).Bold(true)
+
+	var sb strings.Builder
+	sb.WriteString(r.NewStyle().Foreground(t.Primary).Bold(true).Render("Delivery Forecast"))
+	sb.WriteString("  ")
+	for i, sc := range m.scopes {
+		name := sc.Kind
+		if sc.Target != "" {
+			name += " " + sc.Target
+		}
+		style := r.NewStyle().Foreground(t.Secondary)
+		if i == m.scope {
+			style = r.NewStyle().Foreground(t.Primary).Bold(true).Underline(true)
+		}
+		sb.WriteString(style.Render(name))
+		sb.WriteString("  ")
+	}
+	sb.WriteString("\n")
+	source := fmt.Sprintf("%d open", fc.Issues)
+	if fc.Prerequisites > 0 {
+		source += fmt.Sprintf(" + %d blockers", fc.Prerequisites)
+	}
+	source += fmt.Sprintf(" • %d agent(s) • %d trials • durations: %s", fc.Agents, fc.Trials, strings.ReplaceAll(fc.Source, "_", " "))
+	if fc.Samples > 0 {
+		source += fmt.Sprintf(" (%d samples)", fc.Samples)
+	}
+	sb.WriteString(dimStyle.Render(source))
+	sb.WriteString("\n\n")
+
+	if fc.Issues == 0 {
+		sb.WriteString(dimStyle.Render("  Nothing open in this scope"))
+		sb.WriteString("\n\n")
+		sb.WriteString(r.NewStyle().Foreground(ColorFooterHint).Italic(true).Render("tab: scope | esc: back"))
+		return sb.String()
+	}
+
+	for _, p := range []struct {
+		name string
+		days float64
+		at   time.Time
+	}{{"p50", fc.P50Days, fc.P50}, {"p85", fc.P85Days, fc.P85}, {"p95", fc.P95Days, fc.P95}} {
+		sb.WriteString(labelStyle.Render(p.name))
+		sb.WriteString(fmt.Sprintf(" %s  (%.1f days)\n", p.at.Format("Mon Jan 02, 2006"), p.days))
+	}
+	sb.WriteString("\n")
+
+	// One row per bucket, widest bar scaled to the space left of the labels
+	maxCount := 0
+	for _, b := range fc.Histogram {
+		maxCount = max(maxCount, b.Count)
+	}
+	barWidth := max(m.width-40, 10)
+	barStyle := r.NewStyle().Foreground(t.Primary)
+	markStyle := r.NewStyle().Foreground(ColorWarning).Bold(true)
+	rows := min(len(fc.Histogram), max(m.height-12, 1))
+	for _, b := range fc.Histogram[:rows] {
+		bar := 0
+		if maxCount > 0 {
+			bar = b.Count * barWidth / maxCount
+		}
+		if b.Count > 0 && bar == 0 {
+			bar = 1
+		}
+		var marks []string
+		for _, p := range []struct {
+			name string
+			days float64
+		}{{"p50", fc.P50Days}, {"p85", fc.P85Days}, {"p95", fc.P95Days}} {
+			if p.days >= b.FromDays && p.days <= b.ToDays {
+				marks = append(marks, p.name)
+			}
+		}
+		end := m.now.Add(time.Duration(b.ToDays * 24 * float64(time.Hour)))
+		sb.WriteString(fmt.Sprintf("  %6.1fd %s ", b.ToDays, end.Format("Jan 02")))
+		sb.WriteString(barStyle.Render(strings.Repeat("█", bar)))
+		sb.WriteString(dimStyle.Render(fmt.Sprintf(" %d  %3.0f%%", b.Count, b.Cumulative*100)))
+		if len(marks) > 0 {
+			sb.WriteString(" " + markStyle.Render("◀ "+strings.Join(marks, " ")))
+		}
+		sb.WriteString("\n")
+	}
+	if fc.Cycles {
+		sb.WriteString(r.NewStyle().Foreground(ColorWarning).Render("  Dependency cycles were broken to simulate"))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	footerStyle := r.NewStyle().Foreground(ColorFooterHint).Italic(true)
+	sb.WriteString(footerStyle.Render("tab: scope | +/-: agents | esc: back"))
+	return sb.String()
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestForecastScopes(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "e", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "f", Title: "Feature", Status: model.StatusOpen, IssueType: model.TypeFeature,
+			Dependencies: []*model.Dependency{{IssueID: "f", DependsOnID: "e", Type: model.DepParentChild}}},
+		{ID: "t", Title: "Task", Status: model.StatusOpen, IssueType: model.TypeTask, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "t", DependsOnID: "f", Type: model.DepParentChild}}},
+		{ID: "x", Title: "Other", Status: model.StatusOpen, IssueType: model.TypeTask},
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		want   string
+	}{
+		{name: "task in an epic", cursor: "t", want: "issue:t:t|epic:e:f,t|label:api:t|all::e,f,t,x"},
+		{name: "an epic forecasts its descendants", cursor: "e", want: "epic:e:f,t|all::e,f,t,x"},
+		{name: "unparented task", cursor: "x", want: "issue:x:x|all::e,f,t,x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, sc := range forecastScopes(issues, tt.cursor) {
+				got = append(got, sc.Kind+":"+sc.Target+":"+strings.Join(sc.IDs, ","))
+			}
+			if strings.Join(got, "|") != tt.want {
+				t.Errorf("scopes = %s, want %s", strings.Join(got, "|"), tt.want)
+			}
+		})
+	}
+}
+
+func TestForecastViewScopesAndAgents(t *testing.T) {
+	m := NewForecastModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+	m.SetData([]model.Issue{
+		{ID: "e", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "f", Title: "Feature", Status: model.StatusOpen, IssueType: model.TypeFeature,
+			Dependencies: []*model.Dependency{{IssueID: "f", DependsOnID: "e", Type: model.DepParentChild}}},
+		{ID: "t", Title: "Task", Status: model.StatusOpen, IssueType: model.TypeTask, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "t", DependsOnID: "f", Type: model.DepParentChild}}},
+		{ID: "x", Title: "Other", Status: model.StatusOpen, IssueType: model.TypeTask},
+	}, "t", nil)
+	m.SetSize(120, 40)
+
+	if fc := m.Forecast(); fc.Trials != forecastTrials {
+		t.Fatalf("trials = %d, want %d", fc.Trials, forecastTrials)
+	}
+	out := m.View()
+	for _, want := range []string{"Delivery Forecast", "p50", "p85", "p95", "durations: estimate", "◀"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+
+	steps := []struct {
+		name       string
+		key        *tea.KeyMsg
+		wantScope  string
+		wantTarget string
+		wantIssues int
+		wantAgents int
+		fasterThan bool // p50 should beat the previous step's
+	}{
+		{name: "starts at the selected issue", wantScope: "issue", wantTarget: "t", wantIssues: 1, wantAgents: 1},
+		{name: "tab moves to the epic", key: &tea.KeyMsg{Type: tea.KeyTab}, wantScope: "epic", wantTarget: "e", wantIssues: 2, wantAgents: 1},
+		{name: "+ adds an agent", key: &tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}}, wantScope: "epic", wantTarget: "e", wantIssues: 2, wantAgents: 2, fasterThan: true},
+		{name: "shift+tab moves back to the issue", key: &tea.KeyMsg{Type: tea.KeyShiftTab}, wantScope: "issue", wantTarget: "t", wantIssues: 1, wantAgents: 2},
+	}
+	prev := m.Forecast().P50Days
+	for _, step := range steps {
+		if step.key != nil {
+			m.Update(*step.key)
+		}
+		fc := m.Forecast()
+		if fc.Scope != step.wantScope || fc.Target != step.wantTarget || fc.Issues != step.wantIssues || fc.Agents != step.wantAgents {
+			t.Errorf("%s: forecast = %+v", step.name, fc)
+		}
+		if step.fasterThan && fc.P50Days >= prev {
+			t.Errorf("%s: p50 %v should beat %v", step.name, fc.P50Days, prev)
+		}
+		prev = fc.P50Days
+	}
+}
+
+func TestForecastOpensFromList(t *testing.T) {
+	m := NewModel([]model.Issue{
+		{ID: "e", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "t", Title: "Task", Status: model.StatusOpen, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "t", DependsOnID: "e", Type: model.DepParentChild}}},
+	}, nil, "")
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlF})
+	if m.FocusState() != "forecast" {
+		t.Fatalf("ctrl+f should open the forecast, focus %s", m.FocusState())
+	}
+	if fc := m.forecast.Forecast(); fc.Target != m.cursorIssueID() {
+		t.Errorf("forecast should start at the selected issue, got %s %s", fc.Scope, fc.Target)
+	}
+	m = sendKeys(t, m, keyEsc)
+	if m.focused != focusList {
+		t.Errorf("esc should close the forecast, focus %s", m.FocusState())
+	}
+}
@@ -75,6 +75,22 @@ func truncate(s string, maxRunes int) string {
 	return truncateRunesHelper(s, maxRunes, "…")
 }
 
+// customFieldsMarkdown renders custom fields as a two-column Markdown table,
+// or "" if there are none.
+func customFieldsMarkdown(fields model.CustomFields) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer("|", "\\|", "\n", " ", "\r", "")
+	var sb strings.Builder
+	sb.WriteString("| Field | Value |\n|---|---|\n")
+	for _, name := range fields.Names() {
+		fmt.Fprintf(&sb, "| %s | %s |\n", escape.Replace(name), escape.Replace(model.FormatCustomValue(fields[name])))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 // DependencyNode represents a visual node in the dependency tree
 type DependencyNode struct {
 	ID       string
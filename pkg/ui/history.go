@@ -73,6 +73,7 @@ type timelineEntryType int
 
 const (
 	timelineEntryEvent   timelineEntryType = iota // Lifecycle event (created, claimed, closed)
+	timelineEntryChange                           // Field-level change from the git timeline
 	timelineEntryCommit                           // Code commit
 	timelineEntrySession                          // Cass coding session (bv-pr1l)
 )
@@ -86,6 +87,10 @@ type TimelineEntry struct {
 	Confidence float64 // For commits: correlation confidence (0-1)
 	EventType  string  // For events: "created", "claimed", "closed", etc.
 
+	// Field change fields
+	Author string // For changes: commit author
+	Status string // For changes: new status, if the commit changed it
+
 	// Session fields (bv-pr1l)
 	SessionAgent        string  // For sessions: "claude", "cursor", etc.
 	SessionMessageCount int     // For sessions: number of messages in session
@@ -161,6 +166,9 @@ type HistoryModel struct {
 	// Cass session integration state (bv-pr1l)
 	sessionCache map[string][]cass.ScoredResult // Cached sessions per bead ID
 
+	// Field-level change history per bead (nil until loaded)
+	fieldEvents map[string][]correlation.TimelineEvent
+
 	// View mode transition state (bv-kvlx)
 	modeChangedAt time.Time // Timestamp of last mode toggle for transition animation
 }
@@ -193,6 +201,19 @@ func (h *HistoryModel) SetReport(report *correlation.HistoryReport) {
 	h.rebuildFilteredList()
 }
 
+// SetFieldTimeline attaches the field-level change history used by the
+// timeline panel in place of coarse lifecycle milestones.
+func (h *HistoryModel) SetFieldTimeline(tl *correlation.Timeline) {
+	h.fieldEvents = nil
+	if tl == nil {
+		return
+	}
+	h.fieldEvents = make(map[string][]correlation.TimelineEvent)
+	for _, ev := range tl.Events {
+		h.fieldEvents[ev.IssueID] = append(h.fieldEvents[ev.IssueID], ev)
+	}
+}
+
 // SetSessionsForBead stores correlated sessions for a bead in the cache (bv-pr1l)
 // This is called when sessions are loaded asynchronously from the main model.
 func (h *HistoryModel) SetSessionsForBead(beadID string, sessions []cass.ScoredResult) {
@@ -1368,44 +1389,47 @@ func (h *HistoryModel) renderThreePaneView() string {
 
 // buildTimeline creates timeline entries from a bead's history (bv-1x6o)
 func (h *HistoryModel) buildTimeline(hist correlation.BeadHistory) []TimelineEntry {
-	var entries []TimelineEntry
-
-	// Add lifecycle events from milestones (more reliable than Events slice)
-	if hist.Milestones.Created != nil {
-		entries = append(entries, TimelineEntry{
-			Timestamp: hist.Milestones.Created.Timestamp,
-			EntryType: timelineEntryEvent,
-			Label:     "○ Created",
-			Detail:    hist.Title,
-			EventType: "created",
-		})
-	}
-	if hist.Milestones.Claimed != nil {
-		entries = append(entries, TimelineEntry{
-			Timestamp: hist.Milestones.Claimed.Timestamp,
-			EntryType: timelineEntryEvent,
-			Label:     "● Claimed",
-			Detail:    fmt.Sprintf("by %s", hist.Milestones.Claimed.Author),
-			EventType: "claimed",
-		})
-	}
-	if hist.Milestones.Reopened != nil {
-		entries = append(entries, TimelineEntry{
-			Timestamp: hist.Milestones.Reopened.Timestamp,
-			EntryType: timelineEntryEvent,
-			Label:     "↻ Reopened",
-			Detail:    "",
-			EventType: "reopened",
-		})
-	}
-	if hist.Milestones.Closed != nil {
-		entries = append(entries, TimelineEntry{
-			Timestamp: hist.Milestones.Closed.Timestamp,
-			EntryType: timelineEntryEvent,
-			Label:     "✓ Closed",
-			Detail:    "",
-			EventType: "closed",
-		})
+	// Prefer field-level changes when the git timeline is loaded; otherwise
+	// fall back to lifecycle events from milestones (more reliable than
+	// Events slice)
+	entries := h.fieldChangeEntries(hist.BeadID)
+	if len(entries) == 0 {
+		if hist.Milestones.Created != nil {
+			entries = append(entries, TimelineEntry{
+				Timestamp: hist.Milestones.Created.Timestamp,
+				EntryType: timelineEntryEvent,
+				Label:     "○ Created",
+				Detail:    hist.Title,
+				EventType: "created",
+			})
+		}
+		if hist.Milestones.Claimed != nil {
+			entries = append(entries, TimelineEntry{
+				Timestamp: hist.Milestones.Claimed.Timestamp,
+				EntryType: timelineEntryEvent,
+				Label:     "● Claimed",
+				Detail:    fmt.Sprintf("by %s", hist.Milestones.Claimed.Author),
+				EventType: "claimed",
+			})
+		}
+		if hist.Milestones.Reopened != nil {
+			entries = append(entries, TimelineEntry{
+				Timestamp: hist.Milestones.Reopened.Timestamp,
+				EntryType: timelineEntryEvent,
+				Label:     "↻ Reopened",
+				Detail:    "",
+				EventType: "reopened",
+			})
+		}
+		if hist.Milestones.Closed != nil {
+			entries = append(entries, TimelineEntry{
+				Timestamp: hist.Milestones.Closed.Timestamp,
+				EntryType: timelineEntryEvent,
+				Label:     "✓ Closed",
+				Detail:    "",
+				EventType: "closed",
+			})
+		}
 	}
 
 	// Add commits
@@ -1447,6 +1471,86 @@ func (h *HistoryModel) buildTimeline(hist correlation.BeadHistory) []TimelineEnt
 	return entries
 }
 
+// fieldChangeEntries converts the field-level timeline of a bead into
+// timeline entries, one per commit that changed it.
+func (h *HistoryModel) fieldChangeEntries(beadID string) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, ev := range h.fieldEvents[beadID] {
+		entry := TimelineEntry{
+			Timestamp: ev.Timestamp,
+			EntryType: timelineEntryChange,
+			EventType: string(ev.Kind),
+			Author:    ev.Author,
+			Detail:    summarizeFieldChanges(ev.Changes),
+		}
+		switch ev.Kind {
+		case correlation.TimelineCreated:
+			entry.Label = "○ Created"
+		case correlation.TimelineDeleted:
+			entry.Label = "✗ Deleted"
+		default:
+			entry.Label = "✎ Updated"
+		}
+		for _, c := range ev.Changes {
+			if c.Field == "status" {
+				entry.Status = fmt.Sprint(c.To)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// summarizeFieldChanges renders changes compactly, e.g.
+// "status open→closed, P2→P1, +ui".
+func summarizeFieldChanges(changes []correlation.FieldChange) string {
+	var parts []string
+	value := func(v any) string {
+		if v == nil {
+			return "∅"
+		}
+		return fmt.Sprint(v)
+	}
+	for _, c := range changes {
+		switch c.Field {
+		case "priority":
+			if c.From == nil {
+				parts = append(parts, "P"+value(c.To))
+			} else {
+				parts = append(parts, fmt.Sprintf("P%s→P%s", value(c.From), value(c.To)))
+			}
+		case "assignee":
+			if c.To == nil {
+				parts = append(parts, "unassigned")
+			} else {
+				parts = append(parts, "@"+value(c.To))
+			}
+		case "labels", "dependencies":
+			prefix := ""
+			if c.Field == "dependencies" {
+				prefix = "dep "
+			}
+			for _, v := range c.Added {
+				parts = append(parts, "+"+prefix+v)
+			}
+			for _, v := range c.Removed {
+				parts = append(parts, "-"+prefix+v)
+			}
+		case "description", "design", "acceptance_criteria", "notes":
+			parts = append(parts, strings.ReplaceAll(c.Field, "_", " ")+" edited")
+		case "title":
+			parts = append(parts, fmt.Sprintf("title %q", value(c.To)))
+		default:
+			if c.From == nil {
+				parts = append(parts, fmt.Sprintf("%s %s", c.Field, value(c.To)))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s %s→%s", c.Field, value(c.From), value(c.To)))
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 // capitalizeFirst capitalizes the first letter of a string (bv-pr1l)
 func capitalizeFirst(s string) string {
 	if s == "" {
@@ -1583,6 +1687,25 @@ func (h *HistoryModel) renderTimelinePanel(width, height int) string {
 					b.WriteString(detailStyle.Render(detail))
 				}
 
+			case timelineEntryChange:
+				// Field change colored by the status it moved to, if any
+				changeColor := lipgloss.TerminalColor(t.Secondary)
+				if entry.Status != "" {
+					changeColor = t.GetStatusColor(entry.Status)
+				} else if entry.EventType == string(correlation.TimelineDeleted) {
+					changeColor = t.Blocked
+				}
+				b.WriteString(r.NewStyle().Foreground(changeColor).Bold(true).Render(entry.Label))
+				if entry.Author != "" {
+					b.WriteString(r.NewStyle().Foreground(t.Subtext).Render(" " + truncateRunesHelper(entry.Author, width-32, "...")))
+				}
+				if entry.Detail != "" {
+					b.WriteString("\n")
+					b.WriteString(timestampStyle.Render(""))
+					b.WriteString(r.NewStyle().Foreground(lineColor).Render(" ┃   "))
+					b.WriteString(r.NewStyle().Foreground(t.Subtext).Render(truncateRunesHelper(entry.Detail, width-16, "...")))
+				}
+
 			case timelineEntryCommit:
 				// Commit with confidence coloring
 				var confColor lipgloss.TerminalColor
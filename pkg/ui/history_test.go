@@ -1664,6 +1664,55 @@ func TestBuildTimelineWithSessions(t *testing.T) {
 	}
 }
 
+func TestBuildTimelineWithFieldTimeline(t *testing.T) {
+	now := time.Now()
+	history := correlation.BeadHistory{
+		BeadID: "bv-1",
+		Title:  "Test Bead",
+		Milestones: correlation.BeadMilestones{
+			Created: &correlation.BeadEvent{Timestamp: now.Add(-48 * time.Hour)},
+		},
+		Commits: []correlation.CorrelatedCommit{
+			{ShortSHA: "abc1234", Message: "Fix", Timestamp: now.Add(-12 * time.Hour), Confidence: 0.9},
+		},
+	}
+	report := &correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{"bv-1": history}}
+	h := NewHistoryModel(report, testTheme())
+	h.SetSize(160, 40)
+
+	h.SetFieldTimeline(&correlation.Timeline{Events: []correlation.TimelineEvent{
+		{IssueID: "bv-1", Kind: correlation.TimelineCreated, Timestamp: now.Add(-48 * time.Hour), Author: "ann",
+			Changes: []correlation.FieldChange{{Field: "status", To: "open"}, {Field: "priority", To: 2}}},
+		{IssueID: "bv-2", Kind: correlation.TimelineCreated, Timestamp: now.Add(-40 * time.Hour)},
+		{IssueID: "bv-1", Kind: correlation.TimelineUpdated, Timestamp: now.Add(-24 * time.Hour), Author: "bob",
+			Changes: []correlation.FieldChange{
+				{Field: "status", From: "open", To: "in_progress"},
+				{Field: "priority", From: 2, To: 1},
+				{Field: "labels", Added: []string{"ui"}},
+			}},
+	}})
+
+	entries := h.buildTimeline(history)
+	if len(entries) != 3 {
+		t.Fatalf("want 2 changes and 1 commit instead of milestones, got %+v", entries)
+	}
+	updated := entries[1]
+	if updated.EntryType != timelineEntryChange || updated.Author != "bob" || updated.Status != "in_progress" {
+		t.Errorf("unexpected change entry: %+v", updated)
+	}
+	if want := "status open→in_progress, P2→P1, +ui"; updated.Detail != want {
+		t.Errorf("summary = %q, want %q", updated.Detail, want)
+	}
+	if entries[2].EntryType != timelineEntryCommit {
+		t.Errorf("commit should follow the changes: %+v", entries[2])
+	}
+
+	panel := h.renderTimelinePanel(80, 20)
+	if !strings.Contains(panel, "Updated") || !strings.Contains(panel, "P2→P1") {
+		t.Errorf("timeline panel missing field change:\n%s", panel)
+	}
+}
+
 func TestBuildTimelineSessionOrderingOnTimeTie(t *testing.T) {
 	theme := testTheme()
 	now := time.Now()
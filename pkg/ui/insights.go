@@ -745,7 +745,7 @@ func (m *InsightsModel) renderMetricPanel(panel MetricPanel, width, height int,
 	}
 
 	panelStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.PanelBorder).
 		BorderForeground(borderColor).
 		Width(width).
 		Height(height).
@@ -960,7 +960,7 @@ func (m *InsightsModel) renderCyclesPanel(width, height int, t Theme) string {
 	}
 
 	panelStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.PanelBorder).
 		BorderForeground(borderColor).
 		Width(width).
 		Height(height).
@@ -1090,7 +1090,7 @@ func (m *InsightsModel) renderPriorityPanel(width, height int, t Theme) string {
 	}
 
 	panelStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.PanelBorder).
 		BorderForeground(borderColor).
 		Width(width).
 		Height(height).
@@ -1233,7 +1233,7 @@ func (m *InsightsModel) renderMiniBar(label string, value float64, width int, t
 // renderPriorityItem renders a single priority recommendation item
 func (m *InsightsModel) renderPriorityItem(pick analysis.TopPick, width, height int, isSelected bool, t Theme) string {
 	itemStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.CardBorder).
 		Width(width-2).
 		Height(height).
 		Padding(0, 1)
@@ -1348,7 +1348,7 @@ func (m *InsightsModel) renderHeatmapPanel(width, height int, t Theme) string {
 	}
 
 	panelStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.PanelBorder).
 		BorderForeground(borderColor).
 		Width(width).
 		Height(height).
@@ -1984,7 +1984,7 @@ Navigate to a metric panel and select an item to view its details here.
 	// width padding. The border + content naturally determines the panel width.
 	// Height is safe to set for vertical space utilization.
 	panelStyle := t.Renderer.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.PanelBorder).
 		BorderForeground(t.Primary).
 		Height(height).
 		Padding(0, 1)
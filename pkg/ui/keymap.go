@@ -0,0 +1,751 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+	"gopkg.in/yaml.v3"
+)
+
+// KeymapFilename is the keymap file name under .bv/ (project) and
+// ~/.config/bv/ (user).
+const KeymapFilename = "keymap.yaml"
+
+// Action identifies a remappable TUI command. The string value is the name
+// used in keymap.yaml.
+type Action string
+
+const (
+	ActionNone Action = ""
+
+	// Global actions work from any view when the list filter is not active.
+	ActionHelp           Action = "help"
+	ActionTutorial       Action = "tutorial"
+	ActionCommandPalette Action = "command_palette"
+	ActionRefresh        Action = "refresh"
+	ActionShortcuts      Action = "shortcuts_sidebar"
+	ActionQuit           Action = "quit"
+	ActionBack           Action = "back"
+	ActionToggleFocus    Action = "toggle_focus"
+	ActionShrinkList     Action = "shrink_list"
+	ActionGrowList       Action = "grow_list"
+	ActionBoard          Action = "board"
+	ActionGraph          Action = "graph"
+	ActionActionable     Action = "actionable"
+	ActionTree           Action = "tree"
+	ActionInsights       Action = "insights"
+	ActionHistory        Action = "history"
+	ActionLabelDashboard Action = "label_dashboard"
+	ActionAttention      Action = "attention"
+	ActionFlowMatrix     Action = "flow_matrix"
+	ActionFlowMetrics    Action = "flow_metrics"
+	ActionTimeline       Action = "timeline"
+	ActionForecast       Action = "forecast"
+	ActionPriorityHints  Action = "priority_hints"
+	ActionAlerts         Action = "alerts"
+	ActionRecipes        Action = "recipes"
+	ActionSessions       Action = "sessions"
+	ActionThemes         Action = "themes"
+	ActionScoringProfile Action = "scoring_profile"
+	ActionRepoPicker     Action = "repo_picker"
+	ActionExportMarkdown Action = "export_markdown"
+	ActionLabelPicker    Action = "label_picker"
+	ActionMutate         Action = "mutate"
+	ActionMerge          Action = "merge_conflicts"
+	ActionSemanticSearch Action = "semantic_search"
+	ActionHybridSearch   Action = "hybrid_search"
+	ActionHybridPreset   Action = "hybrid_preset"
+
+	// Selection actions mark issues in the list, board and tree; elsewhere
+	// their keys reach the focused view.
+	ActionToggleSelect   Action = "toggle_select"
+	ActionSelectDown     Action = "select_down"
+	ActionSelectUp       Action = "select_up"
+	ActionSelectAll      Action = "select_all"
+	ActionClearSelection Action = "clear_selection"
+	ActionBatch          Action = "batch_actions"
+
+	// List actions apply while the issue list has focus.
+	ActionMoveDown        Action = "move_down"
+	ActionMoveUp          Action = "move_up"
+	ActionListTop         Action = "list_top"
+	ActionListBottom      Action = "list_bottom"
+	ActionPageDown        Action = "page_down"
+	ActionPageUp          Action = "page_up"
+	ActionOpenDetails     Action = "open_details"
+	ActionSearch          Action = "search"
+	ActionFilterOpen      Action = "filter_open"
+	ActionFilterClosed    Action = "filter_closed"
+	ActionFilterReady     Action = "filter_ready"
+	ActionFilterAll       Action = "filter_all"
+	ActionCycleSort       Action = "cycle_sort"
+	ActionTriageSort      Action = "triage_sort"
+	ActionTimeTravel      Action = "time_travel"
+	ActionQuickTimeTravel Action = "quick_time_travel"
+	ActionCopyID          Action = "copy_id"
+	ActionCopyIssue       Action = "copy_issue"
+	ActionOpenEditor      Action = "open_in_editor"
+	ActionCassSessions    Action = "cass_sessions"
+	ActionSelfUpdate      Action = "self_update"
+
+	// Board actions apply while the kanban board has focus. The list's
+	// filter_open, filter_closed and filter_ready keys also filter the board.
+	ActionBoardLeft          Action = "board_left"
+	ActionBoardRight         Action = "board_right"
+	ActionBoardDown          Action = "board_down"
+	ActionBoardUp            Action = "board_up"
+	ActionBoardTop           Action = "board_top"
+	ActionBoardTopChord      Action = "board_top_chord"
+	ActionBoardBottom        Action = "board_bottom"
+	ActionBoardPageDown      Action = "board_page_down"
+	ActionBoardPageUp        Action = "board_page_up"
+	ActionBoardColOpen       Action = "board_column_open"
+	ActionBoardColInProgress Action = "board_column_in_progress"
+	ActionBoardColBlocked    Action = "board_column_blocked"
+	ActionBoardColClosed     Action = "board_column_closed"
+	ActionBoardFirstCol      Action = "board_first_column"
+	ActionBoardLastCol       Action = "board_last_column"
+	ActionBoardSearch        Action = "board_search"
+	ActionBoardNextMatch     Action = "board_next_match"
+	ActionBoardPrevMatch     Action = "board_prev_match"
+	ActionBoardCopyID        Action = "board_copy_id"
+	ActionBoardSwimLanes     Action = "board_swimlanes"
+	ActionBoardEmptyCols     Action = "board_empty_columns"
+	ActionBoardExpandCard    Action = "board_expand_card"
+	ActionBoardDetail        Action = "board_detail"
+	ActionBoardDetailDown    Action = "board_detail_down"
+	ActionBoardDetailUp      Action = "board_detail_up"
+	ActionBoardOpen          Action = "board_open"
+
+	// Graph actions apply while the dependency graph has focus.
+	ActionGraphLeft        Action = "graph_left"
+	ActionGraphRight       Action = "graph_right"
+	ActionGraphDown        Action = "graph_down"
+	ActionGraphUp          Action = "graph_up"
+	ActionGraphPageDown    Action = "graph_page_down"
+	ActionGraphPageUp      Action = "graph_page_up"
+	ActionGraphScrollLeft  Action = "graph_scroll_left"
+	ActionGraphScrollRight Action = "graph_scroll_right"
+	ActionGraphOpen        Action = "graph_open"
+
+	// Tree actions apply while the hierarchy tree has focus.
+	ActionTreeDown        Action = "tree_down"
+	ActionTreeUp          Action = "tree_up"
+	ActionTreeToggle      Action = "tree_toggle"
+	ActionTreeCollapse    Action = "tree_collapse"
+	ActionTreeExpand      Action = "tree_expand"
+	ActionTreeTop         Action = "tree_top"
+	ActionTreeBottom      Action = "tree_bottom"
+	ActionTreeExpandAll   Action = "tree_expand_all"
+	ActionTreeCollapseAll Action = "tree_collapse_all"
+	ActionTreePageDown    Action = "tree_page_down"
+	ActionTreePageUp      Action = "tree_page_up"
+	ActionTreeClose       Action = "tree_close"
+	ActionTreeDetail      Action = "tree_detail"
+
+	// History actions apply while the history view has focus. Left, right
+	// and back also drive the file tree when it has focus.
+	ActionHistorySearch     Action = "history_search"
+	ActionHistoryMode       Action = "history_mode"
+	ActionHistoryDown       Action = "history_down"
+	ActionHistoryUp         Action = "history_up"
+	ActionHistoryNext       Action = "history_next"
+	ActionHistoryPrev       Action = "history_prev"
+	ActionHistoryFocus      Action = "history_focus"
+	ActionHistoryOpen       Action = "history_open"
+	ActionHistoryCopySHA    Action = "history_copy_sha"
+	ActionHistoryConfidence Action = "history_confidence"
+	ActionHistoryFileTree   Action = "history_file_tree"
+	ActionHistoryBrowser    Action = "history_browser"
+	ActionHistoryGraph      Action = "history_graph"
+	ActionHistoryLeft       Action = "history_left"
+	ActionHistoryRight      Action = "history_right"
+	ActionHistoryBack       Action = "history_back"
+
+	// Insights actions apply while the insights dashboard has focus.
+	ActionInsightsDown       Action = "insights_down"
+	ActionInsightsUp         Action = "insights_up"
+	ActionInsightsDetailDown Action = "insights_detail_down"
+	ActionInsightsDetailUp   Action = "insights_detail_up"
+	ActionInsightsPrevPanel  Action = "insights_prev_panel"
+	ActionInsightsNextPanel  Action = "insights_next_panel"
+	ActionInsightsExplain    Action = "insights_explanations"
+	ActionInsightsCalc       Action = "insights_calculation"
+	ActionInsightsHeatmap    Action = "insights_heatmap"
+	ActionInsightsOpen       Action = "insights_open"
+	ActionInsightsClose      Action = "insights_close"
+)
+
+// keyScope says where an action's keys are checked.
+type keyScope int
+
+const (
+	scopeGlobal keyScope = iota
+	scopeList
+	scopeBoard
+	scopeGraph
+	scopeTree
+	scopeHistory
+	scopeInsights
+)
+
+// keySpace returns the scope whose keys s shares. Global and list actions
+// share one key space; each view has its own, so a view action may reuse a
+// list key such as j.
+func (s keyScope) keySpace() keyScope {
+	if s == scopeList {
+		return scopeGlobal
+	}
+	return s
+}
+
+// actionSpec describes an action: its palette category and description, and
+// its default keys in bubbletea's KeyMsg.String() form.
+type actionSpec struct {
+	Action   Action
+	Category string
+	Desc     string
+	Scope    keyScope
+	Defaults []string
+}
+
+// actionSpecs lists every remappable action in palette order.
+var actionSpecs = []actionSpec{
+	{ActionBoard, "View", "Kanban board", scopeGlobal, []string{"b"}},
+	{ActionGraph, "View", "Dependency graph", scopeGlobal, []string{"g"}},
+	{ActionActionable, "View", "Actionable plan", scopeGlobal, []string{"a"}},
+	{ActionTree, "View", "Hierarchy tree", scopeGlobal, []string{"E"}},
+	{ActionInsights, "View", "Insights", scopeGlobal, []string{"i"}},
+	{ActionHistory, "View", "History", scopeGlobal, []string{"h"}},
+	{ActionLabelDashboard, "View", "Label dashboard", scopeGlobal, []string{"[", "f3"}},
+	{ActionAttention, "View", "Attention view", scopeGlobal, []string{"]", "f4"}},
+	{ActionFlowMatrix, "View", "Flow matrix", scopeGlobal, []string{"f"}},
+	{ActionFlowMetrics, "View", "Flow metrics", scopeGlobal, []string{"D"}},
+	{ActionTimeline, "View", "Timeline", scopeGlobal, []string{"Z", "f6"}},
+	{ActionForecast, "View", "Delivery forecast", scopeGlobal, []string{"ctrl+f"}},
+
+	{ActionHelp, "Global", "Help", scopeGlobal, []string{"?", "f1"}},
+	{ActionCommandPalette, "Global", "Command palette", scopeGlobal, []string{":", "ctrl+p"}},
+	{ActionShortcuts, "Global", "Shortcuts sidebar", scopeGlobal, []string{";", "f2"}},
+	{ActionTutorial, "Global", "Tutorial", scopeGlobal, []string{"`"}},
+	{ActionAlerts, "Global", "Alerts panel", scopeGlobal, []string{"!"}},
+	{ActionRecipes, "Global", "Recipes", scopeGlobal, []string{"'"}},
+	{ActionSessions, "Global", "Saved sessions", scopeGlobal, []string{"W"}},
+	{ActionThemes, "Global", "Switch theme", scopeGlobal, []string{"ctrl+t"}},
+	{ActionScoringProfile, "Global", "Cycle scoring profile", scopeGlobal, []string{"alt+p"}},
+	{ActionRepoPicker, "Global", "Repo picker", scopeGlobal, []string{"w"}},
+	{ActionRefresh, "Global", "Force refresh", scopeGlobal, []string{"ctrl+r", "f5"}},
+	{ActionBack, "Global", "Back / clear filters", scopeGlobal, []string{"esc"}},
+	{ActionQuit, "Global", "Back / quit", scopeGlobal, []string{"q"}},
+	{ActionToggleFocus, "Global", "Switch list/detail focus", scopeGlobal, []string{"tab"}},
+	{ActionShrinkList, "Global", "Shrink list pane", scopeGlobal, []string{"<"}},
+	{ActionGrowList, "Global", "Grow list pane", scopeGlobal, []string{">"}},
+
+	{ActionSearch, "Filter", "Search", scopeList, []string{"/"}},
+	{ActionSemanticSearch, "Filter", "Toggle semantic search", scopeGlobal, []string{"ctrl+s"}},
+	{ActionHybridSearch, "Filter", "Toggle hybrid ranking", scopeGlobal, []string{"H"}},
+	{ActionHybridPreset, "Filter", "Cycle hybrid preset", scopeGlobal, []string{"alt+h", "alt+H"}},
+	{ActionFilterOpen, "Filter", "Open issues", scopeList, []string{"o"}},
+	{ActionFilterClosed, "Filter", "Closed issues", scopeList, []string{"c"}},
+	{ActionFilterReady, "Filter", "Ready (unblocked)", scopeList, []string{"r"}},
+	{ActionFilterAll, "Filter", "All issues", scopeList, nil},
+	{ActionLabelPicker, "Filter", "Filter by label", scopeGlobal, []string{"l"}},
+	{ActionCycleSort, "Filter", "Cycle sort", scopeList, []string{"s"}},
+	{ActionTriageSort, "Filter", "Triage sort", scopeList, []string{"S"}},
+
+	{ActionPriorityHints, "Action", "Priority hints", scopeGlobal, []string{"p"}},
+	{ActionTimeTravel, "Action", "Time-travel", scopeList, []string{"t"}},
+	{ActionQuickTimeTravel, "Action", "Quick time-travel", scopeList, []string{"T"}},
+	{ActionExportMarkdown, "Action", "Export markdown", scopeGlobal, []string{"x"}},
+	{ActionCopyID, "Action", "Copy ID", scopeList, []string{"y"}},
+	{ActionCopyIssue, "Action", "Copy to clipboard", scopeList, []string{"C"}},
+	{ActionOpenEditor, "Action", "Open in editor", scopeList, []string{"O"}},
+	{ActionMutate, "Action", "Claim/close/update", scopeGlobal, []string{"M"}},
+	{ActionMerge, "Action", "Resolve merge conflicts", scopeGlobal, []string{"X"}},
+	{ActionCassSessions, "Action", "Cass sessions", scopeList, []string{"V"}},
+	{ActionSelfUpdate, "Action", "Self-update", scopeList, []string{"U"}},
+
+	{ActionToggleSelect, "Select", "Mark / unmark issue", scopeGlobal, []string{" "}},
+	{ActionSelectDown, "Select", "Extend marks down", scopeGlobal, []string{"shift+down"}},
+	{ActionSelectUp, "Select", "Extend marks up", scopeGlobal, []string{"shift+up"}},
+	{ActionSelectAll, "Select", "Mark all shown", scopeGlobal, []string{"ctrl+a"}},
+	{ActionClearSelection, "Select", "Clear marks", scopeGlobal, nil},
+	{ActionBatch, "Select", "Batch actions on marked", scopeGlobal, []string{"B"}},
+
+	{ActionMoveDown, "Navigation", "Move down", scopeList, []string{"j", "down"}},
+	{ActionMoveUp, "Navigation", "Move up", scopeList, []string{"k", "up"}},
+	{ActionListTop, "Navigation", "Go to first", scopeList, []string{"home"}},
+	{ActionListBottom, "Navigation", "Go to last", scopeList, []string{"G", "end"}},
+	{ActionPageDown, "Navigation", "Page down", scopeList, []string{"ctrl+d"}},
+	{ActionPageUp, "Navigation", "Page up", scopeList, []string{"ctrl+u"}},
+	{ActionOpenDetails, "Navigation", "View details", scopeList, []string{"enter"}},
+
+	{ActionBoardLeft, "Board", "Column left", scopeBoard, []string{"h", "left"}},
+	{ActionBoardRight, "Board", "Column right", scopeBoard, []string{"l", "right"}},
+	{ActionBoardDown, "Board", "Card down", scopeBoard, []string{"j", "down"}},
+	{ActionBoardUp, "Board", "Card up", scopeBoard, []string{"k", "up"}},
+	{ActionBoardTop, "Board", "First card in column", scopeBoard, []string{"home", "0"}},
+	{ActionBoardTopChord, "Board", "First card (press twice)", scopeBoard, []string{"g"}},
+	{ActionBoardBottom, "Board", "Last card in column", scopeBoard, []string{"G", "end", "$"}},
+	{ActionBoardPageDown, "Board", "Page down", scopeBoard, []string{"ctrl+d"}},
+	{ActionBoardPageUp, "Board", "Page up", scopeBoard, []string{"ctrl+u"}},
+	{ActionBoardColOpen, "Board", "Jump to Open", scopeBoard, []string{"1"}},
+	{ActionBoardColInProgress, "Board", "Jump to In Progress", scopeBoard, []string{"2"}},
+	{ActionBoardColBlocked, "Board", "Jump to Blocked", scopeBoard, []string{"3"}},
+	{ActionBoardColClosed, "Board", "Jump to Closed", scopeBoard, []string{"4"}},
+	{ActionBoardFirstCol, "Board", "First column", scopeBoard, []string{"H"}},
+	{ActionBoardLastCol, "Board", "Last column", scopeBoard, []string{"L"}},
+	{ActionBoardSearch, "Board", "Search cards", scopeBoard, []string{"/"}},
+	{ActionBoardNextMatch, "Board", "Next match", scopeBoard, []string{"n"}},
+	{ActionBoardPrevMatch, "Board", "Previous match", scopeBoard, []string{"N"}},
+	{ActionBoardCopyID, "Board", "Copy ID", scopeBoard, []string{"y"}},
+	{ActionBoardSwimLanes, "Board", "Cycle swimlanes", scopeBoard, []string{"s"}},
+	{ActionBoardEmptyCols, "Board", "Toggle empty columns", scopeBoard, []string{"e"}},
+	{ActionBoardExpandCard, "Board", "Expand card", scopeBoard, []string{"d"}},
+	{ActionBoardDetail, "Board", "Toggle detail panel", scopeBoard, []string{"tab"}},
+	{ActionBoardDetailDown, "Board", "Scroll detail down", scopeBoard, []string{"ctrl+j"}},
+	{ActionBoardDetailUp, "Board", "Scroll detail up", scopeBoard, []string{"ctrl+k"}},
+	{ActionBoardOpen, "Board", "Open issue", scopeBoard, []string{"enter"}},
+
+	{ActionGraphLeft, "Graph", "Move left", scopeGraph, []string{"h", "left"}},
+	{ActionGraphRight, "Graph", "Move right", scopeGraph, []string{"l", "right"}},
+	{ActionGraphDown, "Graph", "Move down", scopeGraph, []string{"j", "down"}},
+	{ActionGraphUp, "Graph", "Move up", scopeGraph, []string{"k", "up"}},
+	{ActionGraphPageDown, "Graph", "Page down", scopeGraph, []string{"ctrl+d", "pgdown"}},
+	{ActionGraphPageUp, "Graph", "Page up", scopeGraph, []string{"ctrl+u", "pgup"}},
+	{ActionGraphScrollLeft, "Graph", "Scroll left", scopeGraph, []string{"H"}},
+	{ActionGraphScrollRight, "Graph", "Scroll right", scopeGraph, []string{"L"}},
+	{ActionGraphOpen, "Graph", "Open issue", scopeGraph, []string{"enter"}},
+
+	{ActionTreeDown, "Tree", "Move down", scopeTree, []string{"j", "down"}},
+	{ActionTreeUp, "Tree", "Move up", scopeTree, []string{"k", "up"}},
+	{ActionTreeToggle, "Tree", "Expand / collapse", scopeTree, []string{"enter"}},
+	{ActionTreeCollapse, "Tree", "Collapse or go to parent", scopeTree, []string{"h", "left"}},
+	{ActionTreeExpand, "Tree", "Expand or go to child", scopeTree, []string{"l", "right"}},
+	{ActionTreeTop, "Tree", "Go to top", scopeTree, []string{"g"}},
+	{ActionTreeBottom, "Tree", "Go to bottom", scopeTree, []string{"G"}},
+	{ActionTreeExpandAll, "Tree", "Expand all", scopeTree, []string{"o"}},
+	{ActionTreeCollapseAll, "Tree", "Collapse all", scopeTree, []string{"O"}},
+	{ActionTreePageDown, "Tree", "Page down", scopeTree, []string{"ctrl+d", "pgdown"}},
+	{ActionTreePageUp, "Tree", "Page up", scopeTree, []string{"ctrl+u", "pgup"}},
+	{ActionTreeClose, "Tree", "Back to list", scopeTree, []string{"E", "esc"}},
+	{ActionTreeDetail, "Tree", "Show in detail pane", scopeTree, []string{"tab"}},
+
+	{ActionHistorySearch, "History", "Search", scopeHistory, []string{"/"}},
+	{ActionHistoryMode, "History", "Toggle git/bead mode", scopeHistory, []string{"v"}},
+	{ActionHistoryDown, "History", "Move down", scopeHistory, []string{"j", "down"}},
+	{ActionHistoryUp, "History", "Move up", scopeHistory, []string{"k", "up"}},
+	{ActionHistoryNext, "History", "Next commit / related bead", scopeHistory, []string{"J"}},
+	{ActionHistoryPrev, "History", "Previous commit / related bead", scopeHistory, []string{"K"}},
+	{ActionHistoryFocus, "History", "Cycle pane focus", scopeHistory, []string{"tab"}},
+	{ActionHistoryOpen, "History", "Jump to bead", scopeHistory, []string{"enter"}},
+	{ActionHistoryCopySHA, "History", "Copy commit SHA", scopeHistory, []string{"y"}},
+	{ActionHistoryConfidence, "History", "Cycle confidence filter", scopeHistory, []string{"c"}},
+	{ActionHistoryFileTree, "History", "Toggle file tree", scopeHistory, []string{"f", "F"}},
+	{ActionHistoryBrowser, "History", "Open commit in browser", scopeHistory, []string{"o"}},
+	{ActionHistoryGraph, "History", "Show bead in graph", scopeHistory, []string{"g"}},
+	{ActionHistoryLeft, "History", "Collapse folder / close", scopeHistory, []string{"h"}},
+	{ActionHistoryRight, "History", "Expand folder / select file", scopeHistory, []string{"l"}},
+	{ActionHistoryBack, "History", "Clear file filter / close", scopeHistory, []string{"esc"}},
+
+	{ActionInsightsDown, "Insights", "Move down", scopeInsights, []string{"j", "down"}},
+	{ActionInsightsUp, "Insights", "Move up", scopeInsights, []string{"k", "up"}},
+	{ActionInsightsDetailDown, "Insights", "Scroll detail down", scopeInsights, []string{"ctrl+j"}},
+	{ActionInsightsDetailUp, "Insights", "Scroll detail up", scopeInsights, []string{"ctrl+k"}},
+	{ActionInsightsPrevPanel, "Insights", "Previous panel", scopeInsights, []string{"h", "left"}},
+	{ActionInsightsNextPanel, "Insights", "Next panel", scopeInsights, []string{"l", "right", "tab"}},
+	{ActionInsightsExplain, "Insights", "Toggle explanations", scopeInsights, []string{"e"}},
+	{ActionInsightsCalc, "Insights", "Toggle calculation proof", scopeInsights, []string{"x"}},
+	{ActionInsightsHeatmap, "Insights", "Toggle heatmap", scopeInsights, []string{"m"}},
+	{ActionInsightsOpen, "Insights", "Jump to issue", scopeInsights, []string{"enter"}},
+	{ActionInsightsClose, "Insights", "Back to list", scopeInsights, []string{"esc"}},
+}
+
+var actionSpecByName = func() map[Action]actionSpec {
+	m := make(map[Action]actionSpec, len(actionSpecs))
+	for _, s := range actionSpecs {
+		m[s.Action] = s
+	}
+	return m
+}()
+
+// Actions returns every remappable action in palette order.
+func Actions() []Action {
+	out := make([]Action, len(actionSpecs))
+	for i, s := range actionSpecs {
+		out[i] = s.Action
+	}
+	return out
+}
+
+// Desc returns the short description of a.
+func (a Action) Desc() string {
+	return actionSpecByName[a].Desc
+}
+
+// Category returns the palette category of a ("View", "Filter", ...).
+func (a Action) Category() string {
+	return actionSpecByName[a].Category
+}
+
+// Keymap maps keys to actions. Global and list actions share one key space;
+// each view has its own. Global keys are checked first, so a list or view
+// action bound to a global key would never fire.
+type Keymap struct {
+	bindings map[Action][]string
+	byKey    map[keyScope]map[string]Action
+	sources  []string // Files that were applied, in order
+}
+
+// DefaultKeymap returns the built-in bindings.
+func DefaultKeymap() *Keymap {
+	k := &Keymap{bindings: make(map[Action][]string, len(actionSpecs))}
+	for _, s := range actionSpecs {
+		k.bindings[s.Action] = append([]string(nil), s.Defaults...)
+	}
+	k.reindex()
+	return k
+}
+
+var builtinKeymap = DefaultKeymap()
+
+func (k *Keymap) reindex() {
+	k.byKey = make(map[keyScope]map[string]Action)
+	for _, s := range actionSpecs {
+		space := s.Scope.keySpace()
+		if k.byKey[space] == nil {
+			k.byKey[space] = make(map[string]Action)
+		}
+		for _, key := range k.bindings[s.Action] {
+			k.byKey[space][key] = s.Action
+		}
+	}
+}
+
+func (k *Keymap) orDefault() *Keymap {
+	if k == nil {
+		return builtinKeymap
+	}
+	return k
+}
+
+// Lookup returns the global or list action bound to key (a
+// KeyMsg.String()), or ActionNone.
+func (k *Keymap) Lookup(key string) Action {
+	return k.lookupIn(scopeGlobal, key)
+}
+
+// lookupIn returns the action bound to key in scope's key space.
+func (k *Keymap) lookupIn(scope keyScope, key string) Action {
+	return k.orDefault().byKey[scope.keySpace()][key]
+}
+
+// Global reports whether a is checked before focus-specific keys.
+func (a Action) Global() bool {
+	s, ok := actionSpecByName[a]
+	return ok && s.Scope == scopeGlobal
+}
+
+// inView reports whether a only applies inside one view, such as the board.
+func (a Action) inView() bool {
+	return actionSpecByName[a].Scope.keySpace() != scopeGlobal
+}
+
+// Matches reports whether key is bound to a.
+func (k *Keymap) Matches(a Action, key string) bool {
+	return a != ActionNone && k.lookupIn(actionSpecByName[a].Scope, key) == a
+}
+
+// Keys returns the keys bound to a, in KeyMsg.String() form.
+func (k *Keymap) Keys(a Action) []string {
+	return k.orDefault().bindings[a]
+}
+
+// Label formats the keys bound to a for display, e.g. "?/F1". It is empty
+// when a is unbound.
+func (k *Keymap) Label(a Action) string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, key := range k.Keys(a) {
+		l := KeyLabel(key)
+		if !seen[l] {
+			seen[l] = true
+			labels = append(labels, l)
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// ShortLabel formats the first key bound to a, for narrow columns.
+func (k *Keymap) ShortLabel(a Action) string {
+	keys := k.Keys(a)
+	if len(keys) == 0 {
+		return ""
+	}
+	return KeyLabel(keys[0])
+}
+
+// joinKeyLabels joins the non-empty labels with "/", as in "j/k".
+func joinKeyLabels(labels ...string) string {
+	var out []string
+	for _, l := range labels {
+		if l != "" {
+			out = append(out, l)
+		}
+	}
+	return strings.Join(out, "/")
+}
+
+// Sources returns the keymap files that were applied, lowest precedence
+// first.
+func (k *Keymap) Sources() []string {
+	return k.orDefault().sources
+}
+
+// ApplyToList sets the bubbles list's own cursor and filter keys, which the
+// list handles itself rather than through handleListKeys.
+func (k *Keymap) ApplyToList(km *list.KeyMap) {
+	km.CursorDown.SetKeys(k.Keys(ActionMoveDown)...)
+	km.CursorUp.SetKeys(k.Keys(ActionMoveUp)...)
+	km.Filter.SetKeys(k.Keys(ActionSearch)...)
+}
+
+var arrowLabels = map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→"}
+
+// KeyLabel formats a KeyMsg.String() key for display: "ctrl+s" → "Ctrl+S",
+// "f5" → "F5", " " → "Space", "shift+down" → "Shift+↓".
+func KeyLabel(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "+":
+		return key
+	}
+	parts := splitKey(key)
+	for i, p := range parts {
+		switch arrow, isArrow := arrowLabels[p]; {
+		case isArrow:
+			parts[i] = arrow
+		case utf8.RuneCountInString(p) > 1:
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		case i > 0:
+			parts[i] = strings.ToUpper(p)
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// splitKey splits "ctrl+s" into modifiers and key, keeping a literal "+"
+// key ("ctrl++") intact.
+func splitKey(key string) []string {
+	if strings.HasSuffix(key, "++") {
+		return append(strings.Split(strings.TrimSuffix(key, "++"), "+"), "+")
+	}
+	return strings.Split(key, "+")
+}
+
+// keymapFile is the keymap.yaml format:
+//
+//	bindings:
+//	  board: B
+//	  graph: [G, ctrl+g]
+//	  history: []        # unbind
+type keymapFile struct {
+	Bindings map[string]keyList `yaml:"bindings"`
+}
+
+// keyList accepts a single key or a list of keys.
+type keyList []string
+
+func (l *keyList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || node.Value == "" {
+			*l = keyList{}
+			return nil
+		}
+		*l = keyList{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var keys []string
+		if err := node.Decode(&keys); err != nil {
+			return err
+		}
+		*l = keys
+		return nil
+	default:
+		return fmt.Errorf("line %d: expected a key or a list of keys", node.Line)
+	}
+}
+
+// normalizeKey converts a user-written key to KeyMsg.String() form.
+// Modifiers and named keys are case-insensitive ("Ctrl+S" is "ctrl+s");
+// single characters keep their case ("G" is not "g").
+func normalizeKey(key string) (string, error) {
+	if key == " " {
+		return key, nil
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("empty key")
+	}
+	if utf8.RuneCountInString(key) == 1 {
+		return key, nil
+	}
+	parts := splitKey(key)
+	for i, p := range parts {
+		if p == "" {
+			return "", fmt.Errorf("invalid key %q", key)
+		}
+		last := i == len(parts)-1
+		if last && utf8.RuneCountInString(p) == 1 {
+			if parts[0] == "ctrl" {
+				// bubbletea reports ctrl+letter in lower case.
+				p = strings.ToLower(p)
+			}
+			parts[i] = p
+			continue
+		}
+		p = strings.ToLower(p)
+		switch p {
+		case "space":
+			if len(parts) == 1 {
+				return " ", nil
+			}
+			p = " "
+		case "escape":
+			p = "esc"
+		case "return":
+			p = "enter"
+		case "pageup":
+			p = "pgup"
+		case "pagedown":
+			p = "pgdown"
+		case "control":
+			p = "ctrl"
+		case "option", "meta":
+			p = "alt"
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, "+"), nil
+}
+
+// Apply overrides bindings from a keymap file. Keys claimed by an override
+// are taken from the actions in the same key space that had them by
+// default, so moving an action onto a key does not require unbinding the
+// old owner. Two overrides in the same file claiming one key in one key
+// space is an error, and nothing is applied.
+func (k *Keymap) Apply(overrides map[string][]string) error {
+	next := make(map[Action][]string, len(overrides))
+	claimed := make(map[keyScope]map[string]Action)
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action := Action(name)
+		spec, ok := actionSpecByName[action]
+		if !ok {
+			return fmt.Errorf("unknown action %q", name)
+		}
+		space := spec.Scope.keySpace()
+		if claimed[space] == nil {
+			claimed[space] = make(map[string]Action)
+		}
+		keys := []string{}
+		for _, raw := range overrides[name] {
+			key, err := normalizeKey(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if other, ok := claimed[space][key]; ok && other != action {
+				return fmt.Errorf("key %q is bound to both %s and %s", KeyLabel(key), other, action)
+			}
+			if key == "ctrl+c" {
+				return fmt.Errorf("%s: ctrl+c is reserved for quitting", name)
+			}
+			claimed[space][key] = action
+			keys = append(keys, key)
+		}
+		next[action] = keys
+	}
+
+	for action, keys := range next {
+		k.bindings[action] = keys
+	}
+	for action, keys := range k.bindings {
+		if _, overridden := next[action]; overridden {
+			continue
+		}
+		space := actionSpecByName[action].Scope.keySpace()
+		kept := keys[:0:0]
+		for _, key := range keys {
+			if _, taken := claimed[space][key]; !taken {
+				kept = append(kept, key)
+			}
+		}
+		k.bindings[action] = kept
+	}
+	k.reindex()
+	return nil
+}
+
+// loadFile applies the keymap file at path. A missing file is not an error.
+func (k *Keymap) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var file keymapFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	overrides := make(map[string][]string, len(file.Bindings))
+	for name, keys := range file.Bindings {
+		overrides[name] = keys
+	}
+	if err := k.Apply(overrides); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	k.sources = append(k.sources, path)
+	return nil
+}
+
+// UserKeymapPath returns ~/.config/bv/keymap.yaml, or "" if the home
+// directory is unknown.
+func UserKeymapPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "bv", KeymapFilename)
+}
+
+// LoadKeymap builds the keymap from the defaults, the user file and then
+// projectDir/.bv/keymap.yaml, each overriding the last. A file that fails
+// to parse or validate is skipped and reported in warnings.
+func LoadKeymap(userPath, projectDir string) (*Keymap, []string) {
+	k := DefaultKeymap()
+	var warnings []string
+	if userPath != "" {
+		if err := k.loadFile(userPath); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	if projectDir != "" {
+		if err := k.loadFile(filepath.Join(projectDir, ".bv", KeymapFilename)); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	return k, warnings
+}
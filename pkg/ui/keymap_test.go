@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDefaultKeymapIsUnambiguous(t *testing.T) {
+	owner := make(map[keyScope]map[string]Action)
+	for _, a := range Actions() {
+		if a.Desc() == "" || a.Category() == "" {
+			t.Errorf("action %q has no description or category", a)
+		}
+		space := actionSpecByName[a].Scope.keySpace()
+		if owner[space] == nil {
+			owner[space] = make(map[string]Action)
+		}
+		for _, key := range DefaultKeymap().Keys(a) {
+			if prev, ok := owner[space][key]; ok {
+				t.Errorf("key %q bound to both %s and %s", key, prev, a)
+			}
+			owner[space][key] = a
+		}
+	}
+	// The board falls back to the list's filter keys, so it must not use them.
+	for _, f := range []Action{ActionFilterOpen, ActionFilterClosed, ActionFilterReady} {
+		for _, key := range DefaultKeymap().Keys(f) {
+			if a := owner[scopeBoard][key]; a != ActionNone {
+				t.Errorf("board action %s hides %s on %q", a, f, key)
+			}
+		}
+	}
+	k := DefaultKeymap()
+	if got := k.Lookup("b"); got != ActionBoard {
+		t.Errorf("b = %q", got)
+	}
+	if !k.Matches(ActionHelp, "f1") || k.Matches(ActionNone, "zz") {
+		t.Error("Matches")
+	}
+	// A nil keymap behaves like the defaults.
+	var nilKeymap *Keymap
+	if nilKeymap.Lookup("g") != ActionGraph || nilKeymap.Label(ActionRefresh) != "Ctrl+R/F5" {
+		t.Error("nil keymap should fall back to defaults")
+	}
+}
+
+func TestKeyLabelAndNormalize(t *testing.T) {
+	labels := map[string]string{
+		"ctrl+s": "Ctrl+S",
+		"alt+H":  "Alt+H",
+		"f5":     "F5",
+		"esc":    "Esc",
+		" ":      "Space",
+		"G":      "G",
+		"?":      "?",
+		"down":   "↓",
+		"ctrl++": "Ctrl++",
+	}
+	for key, want := range labels {
+		if got := KeyLabel(key); got != want {
+			t.Errorf("KeyLabel(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	normalized := map[string]string{
+		"Ctrl+S":      "ctrl+s",
+		"ctrl+G":      "ctrl+g",
+		"Alt+H":       "alt+H",
+		"G":           "G",
+		"space":       " ",
+		"Escape":      "esc",
+		"PageDown":    "pgdown",
+		"Option+x":    "alt+x",
+		" shift+tab ": "shift+tab",
+	}
+	for in, want := range normalized {
+		got, err := normalizeKey(in)
+		if err != nil || got != want {
+			t.Errorf("normalizeKey(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "ctrl+", "+x"} {
+		if _, err := normalizeKey(bad); err == nil {
+			t.Errorf("normalizeKey(%q) should fail", bad)
+		}
+	}
+}
+
+func TestKeymapApply(t *testing.T) {
+	k := DefaultKeymap()
+	err := k.Apply(map[string][]string{
+		"board":     {"B"},
+		"graph":     {"b", "ctrl+g"}, // Takes b from the board's default
+		"history":   {},
+		"tree":      {"h"}, // Takes h from history, which is unbound anyway
+		"list_top":  {"g"}, // Takes g from graph
+		"move_down": {"n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]Action{
+		"B": ActionBoard, "b": ActionGraph, "ctrl+g": ActionGraph,
+		"h": ActionTree, "E": ActionNone, "g": ActionListTop, "n": ActionMoveDown, "j": ActionNone,
+	}
+	for key, want := range checks {
+		if got := k.Lookup(key); got != want {
+			t.Errorf("Lookup(%q) = %q, want %q", key, got, want)
+		}
+	}
+	if k.Label(ActionHistory) != "" {
+		t.Errorf("history should be unbound, got %q", k.Label(ActionHistory))
+	}
+	if k.Label(ActionGraph) != "b/Ctrl+G" {
+		t.Errorf("graph label = %q", k.Label(ActionGraph))
+	}
+
+	for name, overrides := range map[string]map[string][]string{
+		"unknown action": {"teleport": {"z"}},
+		"duplicate key":  {"board": {"z"}, "graph": {"z"}},
+		"reserved key":   {"quit": {"ctrl+c"}},
+		"invalid key":    {"quit": {"ctrl+"}},
+	} {
+		before := k.Lookup("B")
+		if err := k.Apply(overrides); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if k.Lookup("B") != before || k.Lookup("z") != ActionNone {
+			t.Errorf("%s: failed Apply must not change bindings", name)
+		}
+	}
+}
+
+func TestLoadKeymapPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user.yaml")
+	if err := os.WriteFile(userPath, []byte("bindings:\n  board: B\n  graph: [G, ctrl+g]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(dir, "project")
+	if err := os.MkdirAll(filepath.Join(project, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, ".bv", KeymapFilename), []byte("bindings:\n  board: ctrl+b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	k, warnings := LoadKeymap(userPath, project)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if k.Lookup("ctrl+b") != ActionBoard || k.Lookup("B") != ActionNone {
+		t.Errorf("project file should override the user file for board")
+	}
+	if k.Lookup("G") != ActionGraph || k.Lookup("g") != ActionNone {
+		t.Errorf("user binding for graph should survive")
+	}
+	if len(k.Sources()) != 2 {
+		t.Errorf("sources = %v", k.Sources())
+	}
+
+	// A broken project file is skipped with a warning; the user file still applies.
+	if err := os.WriteFile(filepath.Join(project, ".bv", KeymapFilename), []byte("bindings:\n  board: {x: 1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	k, warnings = LoadKeymap(userPath, project)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], KeymapFilename) {
+		t.Fatalf("expected one warning naming the file, got %v", warnings)
+	}
+	if k.Lookup("B") != ActionBoard {
+		t.Errorf("user file should still apply")
+	}
+
+	// Missing files are fine.
+	k, warnings = LoadKeymap(filepath.Join(dir, "missing.yaml"), filepath.Join(dir, "nowhere"))
+	if len(warnings) != 0 || k.Lookup("b") != ActionBoard {
+		t.Errorf("missing files should yield defaults, warnings %v", warnings)
+	}
+}
+
+func TestRemappedKeysDriveModel(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "1", Title: "One", Status: model.StatusOpen},
+		{ID: "2", Title: "Two", Status: model.StatusClosed},
+	}
+	m := NewModel(issues, nil, "")
+	km := DefaultKeymap()
+	if err := km.Apply(map[string][]string{
+		"board":       {"B"},
+		"filter_open": {"O"}, // Takes O from open_in_editor
+		"move_down":   {"n"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	m.keymap = km
+	km.ApplyToList(&m.list.KeyMap)
+
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+
+	press("b")
+	if m.IsBoardView() {
+		t.Fatal("b should no longer open the board")
+	}
+	press("B")
+	if !m.IsBoardView() || m.FocusState() != "board" {
+		t.Fatalf("B should open the board, focus %s", m.FocusState())
+	}
+	press("B")
+	if m.IsBoardView() {
+		t.Fatal("B should close the board")
+	}
+
+	press("O")
+	if m.currentFilter != "open" {
+		t.Errorf("O should filter open issues, filter %q", m.currentFilter)
+	}
+	m.currentFilter = "all"
+	m.applyFilter()
+	m.list.Select(0)
+	press("j")
+	if m.list.Index() != 0 {
+		t.Errorf("j should be unbound from the list cursor")
+	}
+	press("n")
+	if m.list.Index() != 1 {
+		t.Errorf("n should move the cursor down, index %d", m.list.Index())
+	}
+}
+
+func TestKeymapViewScopes(t *testing.T) {
+	k := DefaultKeymap()
+	if k.Lookup("h") != ActionHistory || k.lookupIn(scopeBoard, "h") != ActionBoardLeft || k.lookupIn(scopeTree, "h") != ActionTreeCollapse {
+		t.Error("h should mean different things per view")
+	}
+	if !k.Matches(ActionBoardLeft, "h") || k.Matches(ActionBoardLeft, "l") {
+		t.Error("Matches should check the action's own scope")
+	}
+	if err := k.Apply(map[string][]string{
+		"graph_scroll_left": {"x"}, // x stays export_markdown outside the graph
+		"board_down":        {"n"}, // Takes n from board_next_match only
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if k.Lookup("x") != ActionExportMarkdown || k.lookupIn(scopeGraph, "x") != ActionGraphScrollLeft {
+		t.Error("a view binding must not steal a global key")
+	}
+	if k.Label(ActionBoardNextMatch) != "" || k.lookupIn(scopeBoard, "j") != ActionNone {
+		t.Errorf("board keys not moved: next match %q", k.Label(ActionBoardNextMatch))
+	}
+	if k.lookupIn(scopeInsights, "j") != ActionInsightsDown {
+		t.Error("other views keep their own j")
+	}
+	if err := k.Apply(map[string][]string{"board_up": {"z"}, "tree_up": {"z"}}); err != nil {
+		t.Errorf("one key in two views is fine: %v", err)
+	}
+	if err := k.Apply(map[string][]string{"board_up": {"q"}, "board_down": {"q"}}); err == nil {
+		t.Error("one key twice in a view should fail")
+	}
+}
+
+func TestRemappedBoardKeys(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "1", Title: "One", Status: model.StatusOpen, Priority: 1},
+		{ID: "2", Title: "Two", Status: model.StatusOpen, Priority: 2},
+		{ID: "3", Title: "Three", Status: model.StatusClosed},
+	}
+	m := NewModel(issues, nil, "")
+	km := DefaultKeymap()
+	if err := km.Apply(map[string][]string{
+		"filter_open": {"O"}, // Takes O from open_in_editor
+		"board_down":  {"n"}, // Takes n from board_next_match
+	}); err != nil {
+		t.Fatal(err)
+	}
+	m.keymap = km
+	m = sendKeys(t, m, runeKey("b"))
+	if m.FocusState() != "board" {
+		t.Fatalf("focus %s, want board", m.FocusState())
+	}
+
+	first := m.board.SelectedIssue()
+	m = sendKeys(t, m, runeKey("j"))
+	if m.board.SelectedIssue() != first {
+		t.Error("j should no longer move down the board")
+	}
+	m = sendKeys(t, m, runeKey("n"))
+	if got := m.board.SelectedIssue(); got == nil || got == first {
+		t.Errorf("n should move down the board, selected %v", got)
+	}
+
+	m = sendKeys(t, m, runeKey("o"))
+	if m.currentFilter == "open" {
+		t.Error("o should no longer filter the board")
+	}
+	m = sendKeys(t, m, runeKey("O"))
+	if m.currentFilter != "open" {
+		t.Errorf("the remapped filter_open key should filter the board, filter %q", m.currentFilter)
+	}
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MergeStateMsg reports a pending merge found in the beads directory at
+// startup. State is nil when there is none.
+type MergeStateMsg struct {
+	State *datasource.MergeState
+	Err   error
+}
+
+// CheckMergeStateCmd looks for unresolved conflicts left by `bv merge`.
+func CheckMergeStateCmd(beadsDir string) tea.Cmd {
+	return func() tea.Msg {
+		state, err := datasource.LoadMergeState(beadsDir)
+		return MergeStateMsg{State: state, Err: err}
+	}
+}
+
+// MergeAppliedMsg is sent when the merge modal has written its resolutions.
+type MergeAppliedMsg struct {
+	Resolved int
+	Open     int
+	Err      error
+}
+
+// ApplyMergeStateCmd writes the resolutions recorded in state back to the
+// merged beads file in the background.
+func ApplyMergeStateCmd(beadsDir string, state *datasource.MergeState) tea.Cmd {
+	resolved := len(state.Result.Conflicts) - state.Result.Unresolved()
+	return func() tea.Msg {
+		open, err := datasource.ApplyMergeState(beadsDir, state)
+		return MergeAppliedMsg{Resolved: resolved, Open: open, Err: err}
+	}
+}
+
+// MergeModal steps through the conflicts of a pending merge and lets the
+// user pick the base, ours or theirs value for each.
+type MergeModal struct {
+	state     *datasource.MergeState
+	beadsDir  string
+	cursor    int
+	pending   bool
+	cancelled bool
+	err       string
+	theme     Theme
+	width     int
+}
+
+// NewMergeModal creates a modal for state, writing to beadsDir. The cursor
+// starts on the first unresolved conflict.
+func NewMergeModal(state *datasource.MergeState, beadsDir string, theme Theme) MergeModal {
+	m := MergeModal{state: state, beadsDir: beadsDir, theme: theme, width: 72}
+	m.cursor = m.nextOpen(0)
+	return m
+}
+
+// IsCancelled reports whether the user dismissed the modal.
+func (m MergeModal) IsCancelled() bool { return m.cancelled }
+
+// IsPending reports whether the resolutions are being written.
+func (m MergeModal) IsPending() bool { return m.pending }
+
+// Update handles key input. A non-nil command means a write was started.
+func (m MergeModal) Update(msg tea.Msg) (MergeModal, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || m.pending {
+		return m, nil
+	}
+	conflicts := m.state.Result.Conflicts
+	switch key.String() {
+	case "esc", "q", "X":
+		m.cancelled = true
+	case "n", "j", "down", "tab":
+		if m.cursor < len(conflicts)-1 {
+			m.cursor++
+		}
+	case "p", "k", "up", "shift+tab":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "o":
+		m.resolve(datasource.MergeOurs)
+	case "t":
+		m.resolve(datasource.MergeTheirs)
+	case "b":
+		m.resolve(datasource.MergeBase)
+	case "enter", "w":
+		m.pending = true
+		return m, ApplyMergeStateCmd(m.beadsDir, m.state)
+	}
+	return m, nil
+}
+
+func (m *MergeModal) resolve(side datasource.MergeSide) {
+	conflicts := m.state.Result.Conflicts
+	if len(conflicts) == 0 {
+		return
+	}
+	c := conflicts[m.cursor]
+	if err := m.state.Result.Resolve(c.IssueID, c.Field, side); err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.err = ""
+	m.cursor = m.nextOpen(m.cursor)
+}
+
+// nextOpen returns the first unresolved conflict at or after from, wrapping
+// around; it keeps from when everything is resolved.
+func (m MergeModal) nextOpen(from int) int {
+	conflicts := m.state.Result.Conflicts
+	for i := range conflicts {
+		idx := (from + i) % len(conflicts)
+		if conflicts[idx].Resolution == "" {
+			return idx
+		}
+	}
+	return from
+}
+
+// formatMergeValue renders one side of a conflict on a single line.
+func formatMergeValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if val == "" {
+			return `""`
+		}
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return t.Format("2006-01-02 15:04")
+		}
+		return strings.Join(strings.Fields(val), " ")
+	case time.Time:
+		return val.Format("2006-01-02 15:04")
+	case *time.Time:
+		if val == nil {
+			return "(none)"
+		}
+		return val.Format("2006-01-02 15:04")
+	case *string:
+		if val == nil {
+			return "(none)"
+		}
+		return formatMergeValue(*val)
+	case *int:
+		if val == nil {
+			return "(none)"
+		}
+		return fmt.Sprint(*val)
+	}
+	return fmt.Sprint(v)
+}
+
+// View renders the modal.
+func (m MergeModal) View() string {
+	r := m.theme.Renderer
+
+	modalStyle := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(1, 2).
+		Width(m.width)
+	headerStyle := r.NewStyle().Bold(true).Foreground(m.theme.Primary)
+	subStyle := r.NewStyle().Foreground(m.theme.Subtext)
+	keyStyle := r.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+	pickStyle := r.NewStyle().Bold(true).Foreground(m.theme.Open)
+	errStyle := r.NewStyle().Foreground(m.theme.Blocked)
+	footerStyle := r.NewStyle().Foreground(ColorFooterHint).Italic(true)
+
+	result := m.state.Result
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("⇄ Merge conflicts (%d open)", result.Unresolved())))
+	b.WriteString("\n")
+	b.WriteString(subStyle.Render(truncateRunesHelper(
+		fmt.Sprintf("%s ← %s in %s", m.state.Ours, m.state.Theirs, m.state.Path), m.width-6, "…")))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.pending:
+		b.WriteString(subStyle.Render("Writing resolutions…"))
+	case len(result.Conflicts) == 0:
+		b.WriteString("No conflicts recorded.\n\n")
+		b.WriteString(footerStyle.Render("enter clear • esc close"))
+	default:
+		c := result.Conflicts[m.cursor]
+		b.WriteString(fmt.Sprintf("%d/%d  ", m.cursor+1, len(result.Conflicts)))
+		b.WriteString(keyStyle.Render(c.IssueID))
+		b.WriteString("  " + c.Field + "\n\n")
+		rows := []struct {
+			key   string
+			side  datasource.MergeSide
+			value any
+		}{
+			{"b", datasource.MergeBase, c.Base},
+			{"o", datasource.MergeOurs, c.Ours},
+			{"t", datasource.MergeTheirs, c.Theirs},
+		}
+		for _, row := range rows {
+			label := fmt.Sprintf("%-7s", row.side)
+			value := truncateRunesHelper(formatMergeValue(row.value), m.width-18, "…")
+			mark := "  "
+			if c.Resolution == row.side || (c.Resolution == "" && row.side == datasource.MergeOurs) {
+				mark = "→ "
+			}
+			line := mark + label + value
+			if c.Resolution == row.side {
+				line = pickStyle.Render(line + " ✓")
+			}
+			b.WriteString(keyStyle.Render(row.key) + " " + line + "\n")
+		}
+		if m.err != "" {
+			b.WriteString("\n" + errStyle.Render(m.err) + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render("o/t/b pick • n/p next/prev • enter write • esc close"))
+	}
+
+	return modalStyle.Render(b.String())
+}
+
+// CenterModal returns the modal centered in the given dimensions.
+func (m MergeModal) CenterModal(termWidth, termHeight int) string {
+	return lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, m.View())
+}
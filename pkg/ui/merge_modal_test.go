@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestMergeModal_ResolvesAndWrites(t *testing.T) {
+	ours := mutationTestIssue()
+	ours.Priority = 0
+	ours.Title = "Ours"
+	beadsPath := writeTempBeadsFile(t, t.TempDir(), ours)
+	beadsDir := filepath.Dir(beadsPath)
+
+	base := mutationTestIssue()
+	theirs := base
+	theirs.Priority = 3
+	theirs.Title = "Theirs"
+	result := datasource.MergeIssues([]model.Issue{base}, []model.Issue{ours}, []model.Issue{theirs})
+	state := &datasource.MergeState{Path: beadsPath, Ours: "HEAD", Theirs: "feature", Result: result}
+	if err := datasource.SaveMergeState(beadsDir, state); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMergeModal(state, beadsDir, newTestTheme())
+	view := m.View()
+	if !strings.Contains(view, "2 open") || !strings.Contains(view, "title") || !strings.Contains(view, "Theirs") {
+		t.Errorf("view should show the first conflict:\n%s", view)
+	}
+
+	m, _ = m.Update(runeKey("t")) // title -> theirs, cursor moves to priority
+	if !strings.Contains(m.View(), "priority") {
+		t.Errorf("cursor should advance to the next open conflict:\n%s", m.View())
+	}
+	m, _ = m.Update(runeKey("b")) // priority -> base
+	if state.Result.Unresolved() != 0 {
+		t.Fatalf("conflicts still open: %+v", state.Result.Conflicts)
+	}
+
+	m, cmd := m.Update(runeKey("w"))
+	if cmd == nil || !m.IsPending() {
+		t.Fatal("w should start writing")
+	}
+	msg, ok := cmd().(MergeAppliedMsg)
+	if !ok || msg.Err != nil || msg.Open != 0 || msg.Resolved != 2 {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	data, err := os.ReadFile(beadsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"title":"Theirs"`) || !strings.Contains(string(data), `"priority":2`) {
+		t.Errorf("resolutions not written:\n%s", data)
+	}
+	if state, err := datasource.LoadMergeState(beadsDir); err != nil || state != nil {
+		t.Errorf("merge state should be cleared: %v %v", state, err)
+	}
+}
+
+func TestMergeModal_EscCancels(t *testing.T) {
+	state := &datasource.MergeState{Result: &datasource.MergeResult{}}
+	m := NewMergeModal(state, t.TempDir(), newTestTheme())
+	if !strings.Contains(m.View(), "No conflicts") {
+		t.Errorf("empty state view:\n%s", m.View())
+	}
+	m, _ = m.Update(runeKey("X"))
+	if !m.IsCancelled() {
+		t.Error("X should close the modal")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/agents"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
@@ -68,6 +69,15 @@ const (
 	focusTutorial    // Interactive tutorial (bv-8y31)
 	focusCassModal   // Cass session preview modal (bv-5bqh)
 	focusUpdateModal // Self-update modal (bv-182)
+	focusMutationModal
+	focusMergeModal
+	focusFlowMetrics // Lead/cycle time and throughput dashboard
+	focusCommandPalette
+	focusBatchModal
+	focusSessionPicker
+	focusThemePicker
+	focusTimeline // Gantt view of ETAs, slack and the critical path
+	focusForecast // Monte Carlo completion-date histogram
 )
 
 // SortMode represents the current list sorting mode (bv-3ita)
@@ -222,8 +232,9 @@ func CheckUpdateCmd() tea.Cmd {
 
 // HistoryLoadedMsg is sent when background history loading completes
 type HistoryLoadedMsg struct {
-	Report *correlation.HistoryReport
-	Error  error
+	Report   *correlation.HistoryReport
+	Timeline *correlation.Timeline // Field-level changes; nil if unavailable
+	Error    error
 }
 
 // AgentFileCheckMsg is sent after checking for AGENTS.md integration (bv-i8dk)
@@ -306,7 +317,14 @@ func LoadHistoryCmd(issues []model.Issue, beadsPath string) tea.Cmd {
 		}
 
 		report, err := correlator.GenerateReport(beads, opts)
-		return HistoryLoadedMsg{Report: report, Error: err}
+		if err != nil {
+			return HistoryLoadedMsg{Error: err}
+		}
+
+		// The field-level timeline only enriches the view, so a failure here
+		// falls back to lifecycle milestones instead of failing the load.
+		timeline, _ := correlation.NewTimelineBuilder(repoPath, beadsPath).Build()
+		return HistoryLoadedMsg{Report: report, Timeline: timeline}
 	}
 }
 
@@ -325,7 +343,8 @@ func cloneIssuesForAsync(issues []model.Issue) []model.Issue {
 type Model struct {
 	// Data
 	issues       []model.Issue
-	pooledIssues []*model.Issue // Issue pool refs for sync reloads (return to pool on replace)
+	pooledIssues []*model.Issue            // Issue pool refs for sync reloads (return to pool on replace)
+	incremental  *loader.IncrementalLoader // Line-level reload cache for sync reloads (nil if disabled)
 	issueMap     map[string]*model.Issue
 	analyzer     *analysis.Analyzer
 	analysis     *analysis.GraphStats
@@ -357,7 +376,10 @@ type Model struct {
 	graphView          GraphModel
 	tree               TreeModel // Hierarchical tree view (bv-gllx)
 	insightsPanel      InsightsModel
-	flowMatrix         FlowMatrixModel // Cross-label flow matrix
+	flowMatrix         FlowMatrixModel  // Cross-label flow matrix
+	flowMetrics        FlowMetricsModel // Lead/cycle time dashboard
+	timeline           TimelineModel    // Gantt view of open issues
+	forecast           ForecastModel    // Monte Carlo delivery forecast
 	theme              Theme
 
 	// Update State
@@ -405,12 +427,17 @@ type Model struct {
 	historyLoading    bool // True while history is being loaded in background
 	historyLoadFailed bool // True if history loading failed
 
+	// Per-issue status transitions from the git timeline (nil until history loads)
+	statusHistory map[string][]analysis.StatusChange
+
 	// Filter and sort state
 	currentFilter          string
 	sortMode               SortMode // bv-3ita: current sort mode
 	semanticSearchEnabled  bool
 	semanticIndexBuilding  bool
 	semanticSearch         *SemanticSearch
+	queryFilter            *QueryFilter   // Structured query syntax for the "/" filter
+	searchMatches          *searchMatches // Where the "/" text matched; shared with the list delegate
 	semanticHybridEnabled  bool
 	semanticHybridPreset   search.PresetName
 	semanticHybridBuilding bool
@@ -444,6 +471,12 @@ type Model struct {
 	showLabelPicker bool
 	labelPicker     LabelPickerModel
 
+	// Keymap and command palette
+	keymap             *Keymap
+	showCommandPalette bool
+	commandPalette     CommandPaletteModel
+	focusBeforePalette focus
+
 	// Repo picker (workspace mode)
 	showRepoPicker bool
 	repoPicker     RepoPickerModel
@@ -502,6 +535,42 @@ type Model struct {
 	// Self-update modal (bv-182)
 	showUpdateModal bool
 	updateModal     UpdateModal
+
+	// Write-back mutation modal (claim/close/link/update)
+	showMutationModal   bool
+	mutationModal       MutationModal
+	mutationReturnFocus focus
+
+	// Merge conflict resolution modal (pending `bv merge` state)
+	showMergeModal bool
+	mergeModal     MergeModal
+	mergeReturn    focus
+
+	// Multi-select: issues marked in the list, board or tree, and the modal
+	// that runs batch operations on them
+	selection      *Selection
+	showBatchModal bool
+	batchModal     BatchModal
+	batchReturn    focus
+
+	// Saved sessions: view state persisted to .beads/sessions.json under a
+	// name, restored on startup and switchable from a picker
+	sessionPath       string // "" disables persistence
+	sessionName       string
+	showSessionPicker bool
+	sessionPicker     SessionPickerModel
+	sessionReturn     focus
+
+	// Themes: presets, ~/.config/bv/themes and .bv/theme.yaml, switchable
+	// from a live-preview picker
+	themes          *ThemeCatalog
+	showThemePicker bool
+	themePicker     ThemePickerModel
+	themeReturn     focus
+
+	// Scoring profiles: built-ins and .bv/scoring.yaml, cycled with alt+p
+	scoringProfiles *analysis.ScoringProfiles
+	scoringProfile  *analysis.ScoringProfile
 }
 
 // labelCount is a simple label->count pair for display
@@ -600,20 +669,42 @@ type WorkspaceInfo struct {
 }
 
 func (m *Model) updateSemanticIDs(items []list.Item) {
+	if m.queryFilter != nil {
+		m.queryFilter.SetItems(items, m.issues)
+	}
+	m.searchMatches.reset(m.issues)
 	if m.semanticSearch == nil {
 		return
 	}
 	ids := make([]string, 0, len(items))
 	docs := make(map[string]string, len(items))
+	issues := make([]model.Issue, 0, len(items))
 	for _, it := range items {
 		if issueItem, ok := it.(IssueItem); ok {
 			id := issueItem.Issue.ID
 			ids = append(ids, id)
 			docs[id] = search.IssueDocument(issueItem.Issue)
+			issues = append(issues, issueItem.Issue)
 		}
 	}
 	m.semanticSearch.SetIDs(ids)
 	m.semanticSearch.SetDocs(docs)
+	m.semanticSearch.SetLexicalIndex(search.NewBM25Index(issues))
+}
+
+// searchMatch returns the passage of issueID that matches the active "/"
+// filter text, if any.
+func (m *Model) searchMatch(issueID string) (search.ChunkMatch, bool) {
+	if m.list.FilterState() == list.Unfiltered {
+		return search.ChunkMatch{}, false
+	}
+	return m.searchMatches.match(m.searchText(), issueID)
+}
+
+// searchText returns the free-text part of the "/" filter input, which is
+// what semantic results and scores are keyed by.
+func (m *Model) searchText() string {
+	return search.QueryText(m.list.FilterInput.Value())
 }
 
 func (m *Model) shouldShowSearchScores() bool {
@@ -623,7 +714,7 @@ func (m *Model) shouldShowSearchScores() bool {
 	if m.list.FilterState() == list.Unfiltered {
 		return false
 	}
-	if strings.TrimSpace(m.list.FilterInput.Value()) == "" {
+	if strings.TrimSpace(m.searchText()) == "" {
 		return false
 	}
 	return true
@@ -636,6 +727,8 @@ func (m *Model) updateListDelegate() {
 		PriorityHints:     m.priorityHints,
 		WorkspaceMode:     m.workspaceMode,
 		ShowSearchScores:  m.shouldShowSearchScores(),
+		Marks:             m.selection,
+		Matches:           m.searchMatches,
 	})
 }
 
@@ -733,6 +826,13 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 			case "pagerank":
 				less = graphStats.GetPageRankScore(issues[i].ID) < graphStats.GetPageRankScore(issues[j].ID)
 			default:
+				if name, ok := recipe.CustomSortField(r.Sort.Field); ok {
+					cmp := recipe.CompareCustomField(&issues[i], &issues[j], name, descending)
+					if descending {
+						return cmp > 0
+					}
+					return cmp < 0
+				}
 				less = issues[i].Priority < issues[j].Priority
 			}
 			if descending {
@@ -802,9 +902,33 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 		}
 	}
 
-	// Theme
+	// Theme: defaults < .bv/theme.yaml; --theme and the picker switch later
+	projectDir := ""
+	if beadsPath != "" {
+		projectDir = filepath.Dir(filepath.Dir(beadsPath))
+	} else {
+		projectDir, _ = os.Getwd()
+	}
+	themes := LoadThemeCatalog(UserThemesDir(), projectDir)
+	themeWarnings := themes.Warnings()
+	palette, err := themes.Resolve(themes.Default())
+	if err != nil {
+		themeWarnings = append(themeWarnings, err.Error())
+		palette = Palette{Name: DefaultThemeName}
+	}
+	UseTheme(palette)
 	theme := DefaultTheme(lipgloss.NewRenderer(os.Stdout))
 
+	// Scoring profile: .bv/scoring.yaml's default; --profile and alt+p switch later
+	scoringWarning := ""
+	scoringProfiles, err := analysis.LoadScoringProfiles(analysis.ScoringProfilesPath(projectDir))
+	if err != nil {
+		scoringWarning = err.Error()
+		scoringProfiles = analysis.BuiltinScoringProfiles()
+	}
+	scoringProfile, _ := scoringProfiles.Get("")
+	analyzer.SetScoringProfile(scoringProfile)
+
 	// Default dimensions for immediate ready state (updated when WindowSizeMsg arrives)
 	// This eliminates the "Initializing..." phase entirely, fixing slow startup issues
 	// in tmux, SSH, and slow terminal emulators where the terminal may delay sending size.
@@ -812,7 +936,9 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	const defaultHeight = 40
 
 	// List setup - initialize with default dimensions so UI is immediately usable
-	delegate := IssueDelegate{Theme: theme, WorkspaceMode: false}
+	selection := NewSelection()
+	matches := newSearchMatches(issues)
+	delegate := IssueDelegate{Theme: theme, WorkspaceMode: false, Marks: selection, Matches: matches}
 	l := list.New(items, delegate, defaultWidth, defaultHeight-3)
 	l.Title = ""
 	l.SetShowTitle(false)
@@ -821,6 +947,9 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	l.SetShowPagination(false)
 	l.SetFilteringEnabled(true)
 	l.DisableQuitKeybindings()
+	queryFilter := NewQueryFilter()
+	queryFilter.SetItems(items, issues)
+	l.Filter = queryFilter.Wrap(list.DefaultFilter)
 	// Clear all default styles that might add extra lines
 	l.Styles.Title = lipgloss.NewStyle()
 	l.Styles.TitleBar = lipgloss.NewStyle()
@@ -842,6 +971,7 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 
 	// Initialize sub-components
 	board := NewBoardModel(issues, theme)
+	board.SetSelection(selection)
 	labelDashboard := NewLabelDashboardModel(theme)
 	labelDashboard.SetSize(defaultWidth, defaultHeight-1)
 	velocityComparison := NewVelocityComparisonModel(theme) // bv-125
@@ -901,6 +1031,12 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	_ = recipeLoader.Load() // Load recipes (errors are non-fatal, will just show empty)
 	recipePicker := NewRecipePickerModel(recipeLoader.List(), theme)
 
+	// Keymap: defaults < ~/.config/bv/keymap.yaml < .bv/keymap.yaml
+	keymap, keymapWarnings := LoadKeymap(UserKeymapPath(), projectDir)
+	keymap.ApplyToList(&l.KeyMap)
+	shortcutsSidebar.SetKeymap(keymap)
+	commandPalette := NewCommandPaletteModel(keymap, theme)
+
 	// Initialize label picker (bv-126)
 	labelExtraction := analysis.ExtractLabels(issues)
 	labelCounts := extractLabelCounts(labelExtraction.Stats)
@@ -932,8 +1068,9 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 
 	if beadsPath != "" && backgroundModeRequested {
 		bw, err := NewBackgroundWorker(WorkerConfig{
-			BeadsPath:     beadsPath,
-			DebounceDelay: 200 * time.Millisecond,
+			BeadsPath:      beadsPath,
+			DebounceDelay:  200 * time.Millisecond,
+			ScoringProfile: scoringProfile,
 		})
 		if err != nil {
 			backgroundModeErr = err
@@ -988,6 +1125,15 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	} else if watcherErr != nil {
 		initialStatus = fmt.Sprintf("Live reload unavailable: %v", watcherErr)
 		initialStatusErr = true
+	} else if len(keymapWarnings) > 0 {
+		initialStatus = "Keymap ignored: " + keymapWarnings[0]
+		initialStatusErr = true
+	} else if len(themeWarnings) > 0 {
+		initialStatus = "Theme ignored: " + themeWarnings[0]
+		initialStatusErr = true
+	} else if scoringWarning != "" {
+		initialStatus = "Scoring profiles ignored: " + scoringWarning
+		initialStatusErr = true
 	}
 
 	// Precompute drift/health alerts (bv-168)
@@ -1004,8 +1150,11 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 
 	// Tree view state should persist alongside the beads directory (e.g. BEADS_DIR overrides).
 	treeModel := NewTreeModel(theme)
+	treeModel.SetSelection(selection)
+	sessionPath := ""
 	if beadsPath != "" {
 		treeModel.SetBeadsDir(filepath.Dir(beadsPath))
+		sessionPath = SessionPath(filepath.Dir(beadsPath))
 	}
 
 	return Model{
@@ -1031,6 +1180,7 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 		theme:                  theme,
 		currentFilter:          "all",
 		semanticSearch:         semanticSearch,
+		queryFilter:            queryFilter,
 		semanticHybridEnabled:  false,
 		semanticHybridPreset:   search.PresetDefault,
 		semanticHybridBuilding: false,
@@ -1057,6 +1207,15 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 		recipePicker:        recipePicker,
 		activeRecipe:        activeRecipe,
 		labelPicker:         labelPicker,
+		keymap:              keymap,
+		commandPalette:      commandPalette,
+		selection:           selection,
+		searchMatches:       matches,
+		sessionPath:         sessionPath,
+		sessionName:         DefaultSessionName,
+		themes:              themes,
+		scoringProfiles:     scoringProfiles,
+		scoringProfile:      scoringProfile,
 		labelDrilldownCache: make(map[string][]model.Issue),
 		timeTravelInput:     ti,
 		statusMsg:           initialStatus,
@@ -1099,6 +1258,10 @@ func (m Model) Init() tea.Cmd {
 	} else if m.watcher != nil {
 		cmds = append(cmds, WatchFileCmd(m.watcher))
 	}
+	// Surface conflicts left by bv merge
+	if m.beadsPath != "" && !m.workspaceMode {
+		cmds = append(cmds, CheckMergeStateCmd(filepath.Dir(m.beadsPath)))
+	}
 	// Start loading history in background
 	if len(m.issues) > 0 {
 		cmds = append(cmds, LoadHistoryCmd(m.issuesForAsync(), m.beadsPath))
@@ -1134,6 +1297,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case MutationResultMsg:
+		m.showMutationModal = false
+		if m.focused == focusMutationModal {
+			m.focused = m.focusBeforeMutation()
+		}
+		switch {
+		case msg.Err != nil:
+			m.statusMsg = fmt.Sprintf("%s %s failed: %v", msg.Action, msg.IssueID, msg.Err)
+			m.statusIsError = true
+		case msg.Result == nil || len(msg.Result.Changed) == 0:
+			m.statusMsg = fmt.Sprintf("%s: nothing to change", msg.IssueID)
+			m.statusIsError = false
+		default:
+			m.statusMsg = fmt.Sprintf("✓ %s %s (%s)", msg.Action, msg.IssueID, strings.Join(msg.Result.Changed, ", "))
+			m.statusIsError = false
+		}
+
+	case MergeStateMsg:
+		if msg.Err != nil {
+			m.statusMsg = fmt.Sprintf("Reading merge state: %v", msg.Err)
+			m.statusIsError = true
+		} else if msg.State != nil {
+			if n := msg.State.Result.Unresolved(); n > 0 {
+				m.statusMsg = fmt.Sprintf("⇄ %d unresolved merge conflict(s) from %s — press X to resolve", n, msg.State.Theirs)
+				m.statusIsError = false
+			}
+		}
+
+	case MergeAppliedMsg:
+		m.showMergeModal = false
+		if m.focused == focusMergeModal {
+			m.focused = m.mergeReturn
+		}
+		switch {
+		case msg.Err != nil:
+			m.statusMsg = fmt.Sprintf("Writing merge resolutions failed: %v", msg.Err)
+			m.statusIsError = true
+		case msg.Open > 0:
+			m.statusMsg = fmt.Sprintf("⇄ Merge: %d resolved, %d still open (ours kept)", msg.Resolved, msg.Open)
+			m.statusIsError = false
+		default:
+			m.statusMsg = fmt.Sprintf("✓ Merge complete: %d conflict(s) resolved", msg.Resolved)
+			m.statusIsError = false
+		}
+
 	case UpdateProgressMsg:
 		// Forward to the update modal
 		if m.showUpdateModal {
@@ -1160,7 +1368,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Error != nil {
 			// If indexing fails, revert to fuzzy mode for predictable behavior.
 			m.semanticSearchEnabled = false
-			m.list.Filter = list.DefaultFilter
+			m.list.Filter = m.queryFilter.Wrap(list.DefaultFilter)
 			m.statusMsg = fmt.Sprintf("Semantic search unavailable: %v", msg.Error)
 			m.statusIsError = true
 			break
@@ -1209,7 +1417,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Recompute semantic results if hybrid is enabled and search is active.
 		if m.semanticHybridEnabled && m.semanticSearchEnabled && m.list.FilterState() != list.Unfiltered {
-			currentTerm := m.list.FilterInput.Value()
+			currentTerm := m.searchText()
 			if currentTerm != "" {
 				m.semanticSearch.ResetCache()
 				cmds = append(cmds, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm))
@@ -1223,7 +1431,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Refresh list if still filtering with the same term
 			currentTerm := m.list.FilterInput.Value()
-			if m.semanticSearchEnabled && currentTerm == msg.Term {
+			if m.semanticSearchEnabled && search.QueryText(currentTerm) == msg.Term {
 				m.applySemanticScores(msg.Term)
 				prevState := m.list.FilterState()
 				m.list.SetFilterText(currentTerm)
@@ -1287,50 +1495,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.graphView.SetIssues(m.issues, &ins)
 		}
 
-		// Generate triage for priority panel (bv-91) - reuse existing analyzer/stats (bv-runn.12)
-		triage := analysis.ComputeTriageFromAnalyzer(m.analyzer, m.analysis, m.issues, analysis.TriageOptions{}, time.Now())
-		triageScores := make(map[string]float64, len(triage.Recommendations))
-		triageReasons := make(map[string]analysis.TriageReasons, len(triage.Recommendations))
-		quickWinSet := make(map[string]bool, len(triage.QuickWins))
-		blockerSet := make(map[string]bool, len(triage.BlockersToClear))
-		unblocksMap := make(map[string][]string, len(triage.Recommendations))
-
-		for _, rec := range triage.Recommendations {
-			triageScores[rec.ID] = rec.Score
-			if len(rec.Reasons) > 0 {
-				triageReasons[rec.ID] = analysis.TriageReasons{
-					Primary:    rec.Reasons[0],
-					All:        rec.Reasons,
-					ActionHint: rec.Action,
-				}
-			}
-			unblocksMap[rec.ID] = rec.UnblocksIDs
-		}
-		for _, qw := range triage.QuickWins {
-			quickWinSet[qw.ID] = true
-		}
-		for _, bl := range triage.BlockersToClear {
-			blockerSet[bl.ID] = true
-		}
-
-		m.triageScores = triageScores
-		m.triageReasons = triageReasons
-		m.quickWinSet = quickWinSet
-		m.blockerSet = blockerSet
-		m.unblocksMap = unblocksMap
-
-		m.insightsPanel.SetTopPicks(triage.QuickRef.TopPicks)
-
-		// Set full recommendations with breakdown for priority radar (bv-93)
-		dataHash := fmt.Sprintf("v%s@%s#%d", triage.Meta.Version, triage.Meta.GeneratedAt.Format("15:04:05"), triage.Meta.IssueCount)
-		m.insightsPanel.SetRecommendations(triage.Recommendations, dataHash)
-
-		// Generate priority recommendations now that Phase 2 is ready
-		recommendations := m.analyzer.GenerateRecommendations()
-		m.priorityHints = make(map[string]*analysis.PriorityRecommendation, len(recommendations))
-		for i := range recommendations {
-			m.priorityHints[recommendations[i].IssueID] = &recommendations[i]
-		}
+		// Triage for the priority panel (bv-91) and priority hints
+		m.refreshTriage()
+		m.refreshPriorityHints()
 
 		// Refresh alerts now that full Phase 2 metrics (cycles, etc.) are available
 		m.alerts, m.alertsCritical, m.alertsWarning, m.alertsInfo = computeAlerts(m.issues, m.analysis, m.analyzer)
@@ -1431,6 +1598,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusIsError = true
 		} else if msg.Report != nil {
 			m.historyView = NewHistoryModel(msg.Report, m.theme)
+			m.historyView.SetFieldTimeline(msg.Timeline)
+			m.statusHistory = analysis.StatusHistoryFromTimeline(msg.Timeline)
+			if m.focused == focusFlowMetrics {
+				m.flowMetrics.SetData(m.issues, m.statusHistory)
+			}
 			m.historyView.SetSize(m.width, m.height-1)
 			// Refresh detail pane if visible
 			if m.isSplitView || m.showDetails {
@@ -1848,12 +2020,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if profileRefresh {
 			loadStart = time.Now()
 		}
-		loadedIssues, err := loader.LoadIssuesFromFileWithOptionsPooled(m.beadsPath, loader.ParseOptions{
+		reloadOpts := loader.ParseOptions{
 			WarningHandler: func(msg string) {
 				reloadWarnings = append(reloadWarnings, msg)
 			},
 			BufferSize: envMaxLineSizeBytes(),
-		})
+		}
+		if m.incremental == nil && envBoolOr("BV_INCREMENTAL_LOAD", true) {
+			m.incremental = loader.NewIncrementalLoader(m.beadsPath)
+		}
+		var newIssues []model.Issue
+		var newPoolRefs []*model.Issue
+		var err error
+		if m.incremental != nil {
+			// Incrementally loaded issues share storage with the loader's line
+			// cache, so they are never returned to the pool.
+			var changes loader.ChangeSet
+			newIssues, changes, err = m.incremental.Load(reloadOpts)
+			if profileRefresh && err == nil {
+				debug.Log("refresh: incremental load parsed=%d reused=%d added=%d updated=%d removed=%d",
+					changes.LinesParsed, changes.LinesReused, len(changes.Added), len(changes.Updated), len(changes.Removed))
+			}
+		} else {
+			var loadedIssues loader.PooledIssues
+			loadedIssues, err = loader.LoadIssuesFromFileWithOptionsPooled(m.beadsPath, reloadOpts)
+			newIssues, newPoolRefs = loadedIssues.Issues, loadedIssues.PoolRefs
+		}
 		if profileRefresh {
 			recordTiming("load_issues", time.Since(loadStart))
 		}
@@ -1869,8 +2061,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(m.pooledIssues) > 0 {
 			loader.ReturnIssuePtrsToPool(m.pooledIssues)
 		}
-		m.pooledIssues = loadedIssues.PoolRefs
-		newIssues := loadedIssues.Issues
+		m.pooledIssues = newPoolRefs
 
 		// Store selected issue ID to restore position after reload
 		var selectedID string
@@ -1908,6 +2099,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cachedAnalyzer := analysis.NewCachedAnalyzer(newIssues, nil)
 		m.analyzer = cachedAnalyzer.Analyzer
+		m.analyzer.SetScoringProfile(m.scoringProfile)
 		m.analysis = cachedAnalyzer.AnalyzeAsync(context.Background())
 		cacheHit := cachedAnalyzer.WasCacheHit()
 		if profileRefresh {
@@ -2302,6 +2494,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+		// Handle merge conflict modal
+		if m.showMergeModal {
+			m.mergeModal, cmd = m.mergeModal.Update(msg)
+			if m.mergeModal.IsCancelled() {
+				m.showMergeModal = false
+				m.focused = m.mergeReturn
+			}
+			return m, cmd
+		}
+
+		// Handle batch operation modal
+		if m.showBatchModal {
+			m.batchModal = m.batchModal.Update(msg)
+			op := m.batchModal.Chosen()
+			if op != batchNone || m.batchModal.IsCancelled() {
+				m.showBatchModal = false
+				m.focused = m.batchReturn
+				m.runBatchOp(op)
+			}
+			return m, nil
+		}
+
+		// Handle write-back mutation modal
+		if m.showMutationModal {
+			m.mutationModal, cmd = m.mutationModal.Update(msg)
+			if m.mutationModal.IsCancelled() {
+				m.showMutationModal = false
+				m.focused = m.focusBeforeMutation()
+			}
+			return m, cmd
+		}
+
 		// Close label health detail modal if open
 		if m.showLabelHealthDetail {
 			s := msg.String()
@@ -2479,6 +2703,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle session picker before global keys: its name prompt takes typed text
+		if m.showSessionPicker {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m = m.handleSessionPickerKeys(msg)
+			return m, nil
+		}
+
+		if m.showThemePicker {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m = m.handleThemePickerKeys(msg)
+			return m, nil
+		}
+
+		// Handle command palette before global keys: it takes typed text
+		if m.showCommandPalette {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m.handleCommandPaletteKeys(msg)
+		}
+
 		// Handle quit confirmation first
 		if m.showQuitConfirm {
 			switch msg.String() {
@@ -2491,69 +2740,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Command palette (: or Ctrl+P)
+		if m.keymap.Matches(ActionCommandPalette, msg.String()) && m.list.FilterState() != list.Filtering {
+			m.openCommandPalette()
+			return m, nil
+		}
+
 		// Handle help overlay toggle (? or F1)
-		if (msg.String() == "?" || msg.String() == "f1") && m.list.FilterState() != list.Filtering {
-			m.showHelp = !m.showHelp
-			if m.showHelp {
-				m.focusBeforeHelp = m.focused // Store current focus before switching to help
-				m.focused = focusHelp
-				m.helpScroll = 0 // Reset scroll position when opening help
-			} else {
-				m.focused = m.restoreFocusFromHelp()
-			}
+		if m.keymap.Matches(ActionHelp, msg.String()) && m.list.FilterState() != list.Filtering {
+			m.toggleHelp()
 			return m, nil
 		}
 
 		// Handle tutorial toggle (backtick `) - bv-8y31
-		if msg.String() == "`" && m.list.FilterState() != list.Filtering {
-			m.showTutorial = !m.showTutorial
-			if m.showTutorial {
-				m.showHelp = false // Close help if open
-				m.tutorialModel.SetSize(m.width, m.height)
-				m.focused = focusTutorial
-			} else {
-				m.focused = focusList
-			}
+		if m.keymap.Matches(ActionTutorial, msg.String()) && m.list.FilterState() != list.Filtering {
+			m.toggleTutorial()
 			return m, nil
 		}
 
 		// Force refresh (bv-4auz): Ctrl+R / F5 triggers an immediate reload.
-		if (msg.String() == "ctrl+r" || msg.String() == "f5") && m.list.FilterState() != list.Filtering {
-			now := time.Now()
-			if !m.lastForceRefresh.IsZero() && now.Sub(m.lastForceRefresh) < time.Second {
-				return m, nil
-			}
-			m.lastForceRefresh = now
-
-			m.statusMsg = "Refreshing…"
-			m.statusIsError = false
-
-			if m.backgroundWorker != nil {
-				m.backgroundWorker.ForceRefresh()
-				cmds = append(cmds, WaitForBackgroundWorkerMsgCmd(m.backgroundWorker))
-				return m, tea.Batch(cmds...)
-			}
-
-			if m.beadsPath == "" && m.watcher == nil {
-				m.statusMsg = "Refresh unavailable"
-				m.statusIsError = true
-				return m, nil
-			}
-
-			cmds = append(cmds, func() tea.Msg { return FileChangedMsg{} })
-			return m, tea.Batch(cmds...)
+		if m.keymap.Matches(ActionRefresh, msg.String()) && m.list.FilterState() != list.Filtering {
+			return m, m.forceRefresh()
 		}
 
 		// Handle shortcuts sidebar toggle (; or F2) - bv-3qi5
-		if (msg.String() == ";" || msg.String() == "f2") && m.list.FilterState() != list.Filtering {
-			m.showShortcutsSidebar = !m.showShortcutsSidebar
-			if m.showShortcutsSidebar {
-				m.shortcutsSidebar.ResetScroll()
-				m.statusMsg = "Shortcuts sidebar: ; hide | ctrl+j/k scroll"
-				m.statusIsError = false
-			} else {
-				m.statusMsg = ""
-			}
+		if m.keymap.Matches(ActionShortcuts, msg.String()) && m.list.FilterState() != list.Filtering {
+			m.toggleShortcutsSidebar()
 			return m, nil
 		}
 
@@ -2571,104 +2783,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Hybrid search toggle/preset cycle (bv-xbar.6)
 		if m.focused == focusList && m.list.FilterState() != list.Filtering {
-			switch msg.String() {
-			case "H":
-				m.statusIsError = false
-				m.semanticHybridEnabled = !m.semanticHybridEnabled
-				if m.semanticSearch == nil {
-					m.semanticHybridEnabled = false
-					m.statusMsg = "Hybrid search unavailable"
-					m.statusIsError = true
-					return m, nil
-				}
-				m.semanticSearch.SetHybridConfig(m.semanticHybridEnabled, m.semanticHybridPreset)
-				m.semanticSearch.ResetCache()
-				m.clearSemanticScores()
-				if m.semanticHybridEnabled && !m.semanticHybridReady && !m.semanticHybridBuilding {
-					m.semanticHybridBuilding = true
-					m.statusMsg = "Hybrid search: computing metrics…"
-					cmds = append(cmds, BuildHybridMetricsCmd(m.issuesForAsync()))
-				} else if m.semanticHybridEnabled {
-					m.statusMsg = fmt.Sprintf("Hybrid search enabled (%s)", m.semanticHybridPreset)
-				} else {
-					m.statusMsg = "Semantic search: text-only"
-				}
-				if m.semanticSearchEnabled && m.list.FilterState() != list.Unfiltered {
-					currentTerm := m.list.FilterInput.Value()
-					if currentTerm != "" && !m.semanticHybridBuilding {
-						cmds = append(cmds, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm))
-					}
-				}
-				m.updateListDelegate()
-				return m, tea.Batch(cmds...)
-			case "alt+h", "alt+H":
-				m.statusIsError = false
-				m.semanticHybridPreset = nextHybridPreset(m.semanticHybridPreset)
-				if m.semanticSearch != nil {
-					m.semanticSearch.SetHybridConfig(m.semanticHybridEnabled, m.semanticHybridPreset)
-					m.semanticSearch.ResetCache()
-				}
-				m.clearSemanticScores()
-				if m.semanticHybridEnabled {
-					m.statusMsg = fmt.Sprintf("Hybrid preset: %s", m.semanticHybridPreset)
-				} else {
-					m.statusMsg = fmt.Sprintf("Hybrid preset set (%s)", m.semanticHybridPreset)
-				}
-				if m.semanticSearchEnabled && m.semanticHybridEnabled && m.list.FilterState() != list.Unfiltered {
-					currentTerm := m.list.FilterInput.Value()
-					if currentTerm != "" && !m.semanticHybridBuilding {
-						cmds = append(cmds, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm))
-					}
-				}
-				m.updateListDelegate()
-				return m, tea.Batch(cmds...)
+			switch m.keymap.Lookup(msg.String()) {
+			case ActionHybridSearch:
+				return m, m.toggleHybridSearch()
+			case ActionHybridPreset:
+				return m, m.cycleHybridPreset()
 			}
 		}
 
 		// Semantic search toggle (bv-9gf.3)
-		if msg.String() == "ctrl+s" && m.focused == focusList {
-			m.statusIsError = false
-			m.semanticSearchEnabled = !m.semanticSearchEnabled
-			if m.semanticSearchEnabled {
-				if m.semanticSearch != nil {
-					m.list.Filter = m.semanticSearch.Filter
-					if !m.semanticSearch.Snapshot().Ready && !m.semanticIndexBuilding {
-						m.semanticIndexBuilding = true
-						m.statusMsg = "Semantic search: building index…"
-						cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
-					} else if !m.semanticSearch.Snapshot().Ready && m.semanticIndexBuilding {
-						m.statusMsg = "Semantic search: indexing…"
-					} else {
-						m.statusMsg = "Semantic search enabled"
-					}
-				} else {
-					m.semanticSearchEnabled = false
-					m.list.Filter = list.DefaultFilter
-					m.statusMsg = "Semantic search unavailable"
-					m.statusIsError = true
-				}
-				if m.semanticHybridEnabled && !m.semanticHybridReady && !m.semanticHybridBuilding {
-					m.semanticHybridBuilding = true
-					cmds = append(cmds, BuildHybridMetricsCmd(m.issuesForAsync()))
-				}
-			} else {
-				m.list.Filter = list.DefaultFilter
-				m.statusMsg = "Fuzzy search enabled"
-				m.clearSemanticScores()
-			}
-
-			// Refresh the current list filter results immediately.
-			prevState := m.list.FilterState()
-			filterText := m.list.FilterInput.Value()
-			if prevState != list.Unfiltered {
-				m.list.SetFilterText(filterText)
-				if prevState == list.Filtering {
-					m.list.SetFilterState(list.Filtering)
-				}
-			}
-
-			m.updateListDelegate()
-			return m, tea.Batch(cmds...)
+		if m.keymap.Matches(ActionSemanticSearch, msg.String()) && m.focused == focusList {
+			return m, m.toggleSemanticSearch()
 		}
 
 		// If help is showing, handle navigation keys for scrolling
@@ -2702,473 +2827,110 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle keys when not filtering
 		if m.list.FilterState() != list.Filtering {
-			switch msg.String() {
-			case "ctrl+c":
+			if msg.String() == "ctrl+c" {
 				return m, tea.Quit
-
-			case "q":
-				// q closes current view or quits if at top level
-				if m.showDetails && !m.isSplitView {
-					m.showDetails = false
-					m.focused = focusList
-					return m, nil
+			}
+			if action := m.keymap.Lookup(msg.String()); action.Global() {
+				var done bool
+				if m, cmd, done = m.runGlobalAction(action); done {
+					return m, cmd
 				}
-				if m.focused == focusInsights {
+			}
+
+			// Focus-specific key handling
+			switch m.focused {
+			case focusRecipePicker:
+				m = m.handleRecipePickerKeys(msg)
+
+			case focusRepoPicker:
+				m = m.handleRepoPickerKeys(msg)
+
+			case focusLabelPicker:
+				m = m.handleLabelPickerKeys(msg)
+
+			case focusInsights:
+				m = m.handleInsightsKeys(msg)
+
+			case focusBoard:
+				m = m.handleBoardKeys(msg)
+
+			case focusLabelDashboard:
+				if selectedLabel, cmd := m.labelDashboard.Update(msg); selectedLabel != "" {
+					// Filter list by selected label and jump back to list view
+					m.currentFilter = "label:" + selectedLabel
+					m.applyFilter()
 					m.focused = focusList
-					return m, nil
+					return m, cmd
 				}
-				if m.focused == focusFlowMatrix {
-					if m.flowMatrix.showDrilldown {
-						m.flowMatrix.showDrilldown = false
+				// Open detail modal on 'h'
+				if msg.String() == "h" && len(m.labelDashboard.labels) > 0 {
+					idx := m.labelDashboard.cursor
+					if idx >= 0 && idx < len(m.labelDashboard.labels) {
+						lh := m.labelDashboard.labels[idx]
+						m.showLabelHealthDetail = true
+						m.labelHealthDetail = &lh
+						// Precompute cross-label flows for this label
+						m.labelHealthDetailFlow = m.getCrossFlowsForLabel(lh.Label)
 						return m, nil
 					}
-					m.focused = focusList
-					return m, nil
-				}
-				if m.isGraphView {
-					m.isGraphView = false
-					m.focused = focusList
-					return m, nil
-				}
-				if m.isBoardView {
-					m.isBoardView = false
-					m.focused = focusList
-					return m, nil
-				}
-				return m, tea.Quit
-
-			case "esc":
-				// Escape closes modals and goes back
-				if m.showDetails && !m.isSplitView {
-					m.showDetails = false
-					m.focused = focusList
-					return m, nil
-				}
-				if m.focused == focusInsights {
-					m.focused = focusList
-					return m, nil
 				}
-				if m.focused == focusFlowMatrix {
-					if m.flowMatrix.showDrilldown {
-						m.flowMatrix.showDrilldown = false
+				// Open drilldown overlay on 'd'
+				if msg.String() == "d" && len(m.labelDashboard.labels) > 0 {
+					idx := m.labelDashboard.cursor
+					if idx >= 0 && idx < len(m.labelDashboard.labels) {
+						lh := m.labelDashboard.labels[idx]
+						m.labelDrilldownLabel = lh.Label
+						m.labelDrilldownIssues = m.filterIssuesByLabel(lh.Label)
+						m.showLabelDrilldown = true
 						return m, nil
 					}
-					m.focused = focusList
-					return m, nil
 				}
-				if m.isGraphView {
-					m.isGraphView = false
+
+			case focusGraph:
+				m = m.handleGraphKeys(msg)
+
+			case focusTree:
+				m = m.handleTreeKeys(msg)
+
+			case focusActionable:
+				m = m.handleActionableKeys(msg)
+
+			case focusHistory:
+				m = m.handleHistoryKeys(msg)
+
+			case focusSprint:
+				m = m.handleSprintKeys(msg)
+
+			case focusFlowMatrix:
+				m = m.handleFlowMatrixKeys(msg)
+
+			case focusFlowMetrics:
+				if label := m.flowMetrics.Update(msg); label != "" {
+					m.currentFilter = "label:" + label
+					m.applyFilter()
 					m.focused = focusList
-					return m, nil
 				}
-				if m.isBoardView {
-					m.isBoardView = false
-					m.focused = focusList
-					return m, nil
-				}
-				if m.isActionableView {
-					m.isActionableView = false
-					m.focused = focusList
-					return m, nil
-				}
-				if m.isHistoryView {
-					m.isHistoryView = false
-					m.focused = focusList
-					return m, nil
-				}
-				// Close label picker if open (bv-126 fix)
-				if m.showLabelPicker {
-					m.showLabelPicker = false
-					m.focused = focusList
-					return m, nil
-				}
-				// Close label dashboard if open
-				if m.focused == focusLabelDashboard {
-					m.focused = focusList
-					return m, nil
-				}
-				// At main list - first ESC clears filters, second shows quit confirm
-				if m.hasActiveFilters() {
-					m.clearAllFilters()
-					return m, nil
-				}
-				// No filters active - show quit confirmation
-				m.showQuitConfirm = true
-				m.focused = focusQuitConfirm
-				return m, nil
-
-			case "tab":
-				if m.isSplitView && !m.isBoardView {
-					if m.focused == focusList {
-						m.focused = focusDetail
-					} else {
-						m.focused = focusList
-					}
-				}
-
-			case "<":
-				// Shrink list pane (move divider left)
-				if m.isSplitView {
-					m.splitPaneRatio -= 0.05
-					if m.splitPaneRatio < 0.2 {
-						m.splitPaneRatio = 0.2
-					}
-					m.recalculateSplitPaneSizes()
-				}
-
-			case ">":
-				// Expand list pane (move divider right)
-				if m.isSplitView {
-					m.splitPaneRatio += 0.05
-					if m.splitPaneRatio > 0.8 {
-						m.splitPaneRatio = 0.8
-					}
-					m.recalculateSplitPaneSizes()
-				}
-
-			case "b":
-				m.clearAttentionOverlay()
-				m.isBoardView = !m.isBoardView
-				m.isGraphView = false
-				m.isActionableView = false
-				m.isHistoryView = false
-				if m.isBoardView {
-					m.focused = focusBoard
-					m.refreshBoardAndGraphForCurrentFilter()
-				} else {
-					m.focused = focusList
-				}
-				return m, nil
-
-			case "g":
-				// Toggle graph view
-				m.clearAttentionOverlay()
-				m.isGraphView = !m.isGraphView
-				m.isBoardView = false
-				m.isActionableView = false
-				m.isHistoryView = false
-				if m.isGraphView {
-					m.focused = focusGraph
-					m.refreshBoardAndGraphForCurrentFilter()
-				} else {
-					m.focused = focusList
-				}
-				return m, nil
-
-			case "a":
-				// Toggle actionable view
-				m.clearAttentionOverlay()
-				m.isActionableView = !m.isActionableView
-				m.isGraphView = false
-				m.isBoardView = false
-				m.isHistoryView = false
-				if m.isActionableView {
-					// Build execution plan
-					analyzer := analysis.NewAnalyzer(m.issues)
-					plan := analyzer.GetExecutionPlan()
-					m.actionableView = NewActionableModel(plan, m.theme)
-					m.actionableView.SetSize(m.width, m.height-2)
-					m.focused = focusActionable
-				} else {
-					m.focused = focusList
-				}
-				return m, nil
 
-			case "E":
-				// Toggle hierarchical tree view (bv-gllx)
-				m.clearAttentionOverlay()
-				if m.focused == focusTree {
-					m.focused = focusList
-				} else {
-					m.isGraphView = false
-					m.isBoardView = false
-					m.isActionableView = false
-					m.isHistoryView = false
-					// Build tree from snapshot when available (bv-t435)
-					if m.snapshot != nil {
-						m.tree.BuildFromSnapshot(m.snapshot)
-					} else {
-						m.tree.Build(m.issues)
-					}
-					m.tree.SetSize(m.width, m.height-2)
-					m.focused = focusTree
-				}
-				return m, nil
+			case focusForecast:
+				m.forecast.Update(msg)
 
-			case "i":
-				m.clearAttentionOverlay()
-				if m.focused == focusInsights {
-					m.focused = focusList
-				} else {
-					m.isGraphView = false
-					m.isBoardView = false
-					m.isActionableView = false
-					m.isHistoryView = false
-					m.focused = focusInsights
-					// Refresh insights using the current snapshot when available (bv-mpqz).
-					var ins analysis.Insights
-					hasInsights := false
-					if m.snapshot != nil {
-						ins = m.snapshot.Insights
-						hasInsights = true
-					} else if m.analysis != nil {
-						ins = m.analysis.GenerateInsights(len(m.issues))
-						hasInsights = true
-					}
-					if hasInsights {
-						m.insightsPanel = NewInsightsModel(ins, m.issueMap, m.theme)
-						// Include priority triage (bv-91) - reuse existing analyzer/stats (bv-runn.12)
-						triage := analysis.ComputeTriageFromAnalyzer(m.analyzer, m.analysis, m.issues, analysis.TriageOptions{}, time.Now())
-						m.insightsPanel.SetTopPicks(triage.QuickRef.TopPicks)
-						// Set full recommendations with breakdown for priority radar (bv-93)
-						dataHash := fmt.Sprintf("v%s@%s#%d", triage.Meta.Version, triage.Meta.GeneratedAt.Format("15:04:05"), triage.Meta.IssueCount)
-						m.insightsPanel.SetRecommendations(triage.Recommendations, dataHash)
-						panelHeight := m.height - 2
-						if panelHeight < 3 {
-							panelHeight = 3
+			case focusTimeline:
+				if id := m.timeline.Update(msg); id != "" {
+					// Jump to the issue in the list and open its details
+					for i, item := range m.list.Items() {
+						if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == id {
+							m.list.Select(i)
+							break
 						}
-						m.insightsPanel.SetSize(m.width, panelHeight)
-					}
-				}
-				return m, nil
-
-			case "p":
-				// Toggle priority hints
-				m.showPriorityHints = !m.showPriorityHints
-				// Update delegate with new state
-				m.updateListDelegate()
-				// Show explanatory status message
-				if m.showPriorityHints {
-					count := len(m.priorityHints)
-					if count > 0 {
-						m.statusMsg = fmt.Sprintf("Priority hints: ↑ increase ↓ decrease (%d suggestions)", count)
-					} else {
-						m.statusMsg = "Priority hints: No misalignments detected (analysis ongoing)"
-					}
-				} else {
-					m.statusMsg = ""
-				}
-				return m, nil
-
-			case "h":
-				// Toggle history view
-				m.clearAttentionOverlay()
-				m.isHistoryView = !m.isHistoryView
-				m.isGraphView = false
-				m.isBoardView = false
-				m.isActionableView = false
-				if m.isHistoryView {
-					// Ensure history model has latest sizing
-					bodyHeight := m.height - 1
-					if bodyHeight < 5 {
-						bodyHeight = 5
-					}
-					m.historyView.SetSize(m.width, bodyHeight)
-					m.focused = focusHistory
-				} else {
-					m.focused = focusList
-				}
-				return m, nil
-
-			case "[", "f3":
-				// Open label dashboard (phase 1: table view)
-				m.clearAttentionOverlay()
-				m.isGraphView = false
-				m.isBoardView = false
-				m.isActionableView = false
-				m.isHistoryView = false
-				m.focused = focusLabelDashboard
-				// Compute label health (fast; phase1 metrics only needed) with caching
-				if !m.labelHealthCached {
-					cfg := analysis.DefaultLabelHealthConfig()
-					m.labelHealthCache = analysis.ComputeAllLabelHealth(m.issues, cfg, time.Now().UTC(), m.analysis)
-					m.labelHealthCached = true
-				}
-				m.labelDashboard.SetData(m.labelHealthCache.Labels)
-				m.labelDashboard.SetSize(m.width, m.height-1)
-				m.statusMsg = fmt.Sprintf("Labels: %d total • critical %d • warning %d", m.labelHealthCache.TotalLabels, m.labelHealthCache.CriticalCount, m.labelHealthCache.WarningCount)
-				m.statusIsError = false
-				return m, nil
-
-			case "]", "f4":
-				// Attention view: compute attention scores (cached) and render as text
-				if !m.attentionCached {
-					cfg := analysis.DefaultLabelHealthConfig()
-					m.attentionCache = analysis.ComputeLabelAttentionScores(m.issues, cfg, time.Now().UTC())
-					m.attentionCached = true
-				}
-				attText, _ := ComputeAttentionView(m.issues, max(40, m.width-4))
-				m.isGraphView = false
-				m.isBoardView = false
-				m.isActionableView = false
-				m.isHistoryView = false
-				m.focused = focusInsights
-				m.showAttentionView = true
-				m.insightsPanel = NewInsightsModel(analysis.Insights{}, m.issueMap, m.theme)
-				m.insightsPanel.labelAttention = m.attentionCache.Labels
-				m.insightsPanel.extraText = attText
-				panelHeight := m.height - 2
-				if panelHeight < 3 {
-					panelHeight = 3
-				}
-				m.insightsPanel.SetSize(m.width, panelHeight)
-				return m, nil
-
-			case "f":
-				// Flow matrix view (cross-label dependencies)
-				m.clearAttentionOverlay()
-				cfg := analysis.DefaultLabelHealthConfig()
-				flow := analysis.ComputeCrossLabelFlow(m.issues, cfg)
-				m.isGraphView = false
-				m.isBoardView = false
-				m.isActionableView = false
-				m.isHistoryView = false
-				m.focused = focusFlowMatrix
-				m.flowMatrix = NewFlowMatrixModel(m.theme)
-				m.flowMatrix.SetData(&flow, m.issues)
-				panelHeight := m.height - 2
-				if panelHeight < 3 {
-					panelHeight = 3
-				}
-				m.flowMatrix.SetSize(m.width, panelHeight)
-				return m, nil
-
-			case "!":
-				// Toggle alerts panel (bv-168)
-				// Only show if there are active alerts
-				activeCount := 0
-				for _, a := range m.alerts {
-					if !m.dismissedAlerts[alertKey(a)] {
-						activeCount++
-					}
-				}
-				if activeCount > 0 {
-					m.showAlertsPanel = !m.showAlertsPanel
-					m.alertsCursor = 0 // Reset cursor when opening
-				} else {
-					m.statusMsg = "No active alerts"
-					m.statusIsError = false
-				}
-				return m, nil
-
-			case "'":
-				// Toggle recipe picker overlay
-				m.showRecipePicker = !m.showRecipePicker
-				if m.showRecipePicker {
-					m.recipePicker.SetSize(m.width, m.height-1)
-					m.focused = focusRecipePicker
-				} else {
-					m.focused = focusList
-				}
-				return m, nil
-
-			case "w":
-				// Toggle repo picker overlay (workspace mode)
-				if !m.workspaceMode || len(m.availableRepos) == 0 {
-					m.statusMsg = "Repo filter available only in workspace mode"
-					m.statusIsError = false
-					return m, nil
-				}
-				m.showRepoPicker = !m.showRepoPicker
-				if m.showRepoPicker {
-					m.repoPicker = NewRepoPickerModel(m.availableRepos, m.theme)
-					m.repoPicker.SetActiveRepos(m.activeRepos)
-					m.repoPicker.SetSize(m.width, m.height-1)
-					m.focused = focusRepoPicker
-				} else {
-					m.focused = focusList
-				}
-				return m, nil
-
-			case "x":
-				// Export to Markdown file
-				m.exportToMarkdown()
-				return m, nil
-
-			case "l":
-				// Open label picker for quick filter (bv-126)
-				if len(m.issues) == 0 {
-					return m, nil
-				}
-				// Update labels in case they changed
-				labelExtraction := analysis.ExtractLabels(m.issues)
-				labelCounts := extractLabelCounts(labelExtraction.Stats)
-				m.labelPicker.SetLabels(labelExtraction.Labels, labelCounts)
-				m.labelPicker.Reset()
-				m.labelPicker.SetSize(m.width, m.height-1)
-				m.showLabelPicker = true
-				m.focused = focusLabelPicker
-				return m, nil
-
-			}
-
-			// Focus-specific key handling
-			switch m.focused {
-			case focusRecipePicker:
-				m = m.handleRecipePickerKeys(msg)
-
-			case focusRepoPicker:
-				m = m.handleRepoPickerKeys(msg)
-
-			case focusLabelPicker:
-				m = m.handleLabelPickerKeys(msg)
-
-			case focusInsights:
-				m = m.handleInsightsKeys(msg)
-
-			case focusBoard:
-				m = m.handleBoardKeys(msg)
-
-			case focusLabelDashboard:
-				if selectedLabel, cmd := m.labelDashboard.Update(msg); selectedLabel != "" {
-					// Filter list by selected label and jump back to list view
-					m.currentFilter = "label:" + selectedLabel
-					m.applyFilter()
-					m.focused = focusList
-					return m, cmd
-				}
-				// Open detail modal on 'h'
-				if msg.String() == "h" && len(m.labelDashboard.labels) > 0 {
-					idx := m.labelDashboard.cursor
-					if idx >= 0 && idx < len(m.labelDashboard.labels) {
-						lh := m.labelDashboard.labels[idx]
-						m.showLabelHealthDetail = true
-						m.labelHealthDetail = &lh
-						// Precompute cross-label flows for this label
-						m.labelHealthDetailFlow = m.getCrossFlowsForLabel(lh.Label)
-						return m, nil
 					}
-				}
-				// Open drilldown overlay on 'd'
-				if msg.String() == "d" && len(m.labelDashboard.labels) > 0 {
-					idx := m.labelDashboard.cursor
-					if idx >= 0 && idx < len(m.labelDashboard.labels) {
-						lh := m.labelDashboard.labels[idx]
-						m.labelDrilldownLabel = lh.Label
-						m.labelDrilldownIssues = m.filterIssuesByLabel(lh.Label)
-						m.showLabelDrilldown = true
-						return m, nil
+					m.focused = focusDetail
+					if !m.isSplitView {
+						m.showDetails = true
+						m.viewport.GotoTop()
 					}
+					m.updateViewportContent()
 				}
 
-			case focusGraph:
-				m = m.handleGraphKeys(msg)
-
-			case focusTree:
-				m = m.handleTreeKeys(msg)
-
-			case focusActionable:
-				m = m.handleActionableKeys(msg)
-
-			case focusHistory:
-				m = m.handleHistoryKeys(msg)
-
-			case focusSprint:
-				m = m.handleSprintKeys(msg)
-
-			case focusFlowMatrix:
-				m = m.handleFlowMatrixKeys(msg)
-
 			case focusList:
 				m = m.handleListKeys(msg)
 
@@ -3311,8 +3073,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		if m.semanticSearchEnabled && m.semanticHybridEnabled && m.list.FilterState() != list.Unfiltered {
-			if strings.TrimSpace(currentTerm) != "" {
-				m.applySemanticScores(currentTerm)
+			if text := m.searchText(); strings.TrimSpace(text) != "" {
+				m.applySemanticScores(text)
 			}
 		}
 		m.updateListDelegate()
@@ -3339,7 +3101,682 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	return m, tea.Batch(cmds...)
+	return m, tea.Batch(cmds...)
+}
+
+// runGlobalAction performs an action from the main key switch. It reports
+// false when the key should still reach the focused view's handler.
+func (m Model) runGlobalAction(action Action) (Model, tea.Cmd, bool) {
+	switch action {
+	case ActionQuit:
+		// q closes current view or quits if at top level
+		if m.showDetails && !m.isSplitView {
+			m.showDetails = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.focused == focusInsights || m.focused == focusFlowMetrics || m.focused == focusTimeline || m.focused == focusForecast {
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.focused == focusFlowMatrix {
+			if m.flowMatrix.showDrilldown {
+				m.flowMatrix.showDrilldown = false
+				return m, nil, true
+			}
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.isGraphView {
+			m.isGraphView = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.isBoardView {
+			m.isBoardView = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		return m, tea.Quit, true
+
+	case ActionBack:
+		// Escape clears marks first, then closes modals and goes back
+		if m.selection.Len() > 0 && m.canMarkIssues() {
+			m.selection.Clear()
+			m.statusMsg = "Marks cleared"
+			m.statusIsError = false
+			return m, nil, true
+		}
+		if m.showDetails && !m.isSplitView {
+			m.showDetails = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.focused == focusInsights || m.focused == focusFlowMetrics || m.focused == focusTimeline || m.focused == focusForecast {
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.focused == focusFlowMatrix {
+			if m.flowMatrix.showDrilldown {
+				m.flowMatrix.showDrilldown = false
+				return m, nil, true
+			}
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.isGraphView {
+			m.isGraphView = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.isBoardView {
+			m.isBoardView = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.isActionableView {
+			m.isActionableView = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		if m.isHistoryView {
+			m.isHistoryView = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		// Close label picker if open (bv-126 fix)
+		if m.showLabelPicker {
+			m.showLabelPicker = false
+			m.focused = focusList
+			return m, nil, true
+		}
+		// Close label dashboard if open
+		if m.focused == focusLabelDashboard {
+			m.focused = focusList
+			return m, nil, true
+		}
+		// At main list - first ESC clears filters, second shows quit confirm
+		if m.hasActiveFilters() {
+			m.clearAllFilters()
+			return m, nil, true
+		}
+		// No filters active - show quit confirmation
+		m.showQuitConfirm = true
+		m.focused = focusQuitConfirm
+		return m, nil, true
+
+	case ActionToggleFocus:
+		if m.isSplitView && !m.isBoardView {
+			if m.focused == focusList {
+				m.focused = focusDetail
+			} else {
+				m.focused = focusList
+			}
+		}
+
+	case ActionShrinkList:
+		// Shrink list pane (move divider left)
+		if m.isSplitView {
+			m.splitPaneRatio -= 0.05
+			if m.splitPaneRatio < 0.2 {
+				m.splitPaneRatio = 0.2
+			}
+			m.recalculateSplitPaneSizes()
+		}
+
+	case ActionGrowList:
+		// Expand list pane (move divider right)
+		if m.isSplitView {
+			m.splitPaneRatio += 0.05
+			if m.splitPaneRatio > 0.8 {
+				m.splitPaneRatio = 0.8
+			}
+			m.recalculateSplitPaneSizes()
+		}
+
+	case ActionBoard:
+		m.clearAttentionOverlay()
+		m.isBoardView = !m.isBoardView
+		m.isGraphView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		if m.isBoardView {
+			m.focused = focusBoard
+			m.refreshBoardAndGraphForCurrentFilter()
+		} else {
+			m.focused = focusList
+		}
+		return m, nil, true
+
+	case ActionGraph:
+		// Toggle graph view
+		m.clearAttentionOverlay()
+		m.isGraphView = !m.isGraphView
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		if m.isGraphView {
+			m.focused = focusGraph
+			m.refreshBoardAndGraphForCurrentFilter()
+		} else {
+			m.focused = focusList
+		}
+		return m, nil, true
+
+	case ActionActionable:
+		// Toggle actionable view
+		m.clearAttentionOverlay()
+		m.isActionableView = !m.isActionableView
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isHistoryView = false
+		if m.isActionableView {
+			// Build execution plan
+			analyzer := analysis.NewAnalyzer(m.issues)
+			plan := analyzer.GetExecutionPlan()
+			m.actionableView = NewActionableModel(plan, m.theme)
+			m.actionableView.SetSize(m.width, m.height-2)
+			m.focused = focusActionable
+		} else {
+			m.focused = focusList
+		}
+		return m, nil, true
+
+	case ActionTree:
+		// Toggle hierarchical tree view (bv-gllx)
+		m.clearAttentionOverlay()
+		if m.focused == focusTree {
+			m.focused = focusList
+		} else {
+			m.isGraphView = false
+			m.isBoardView = false
+			m.isActionableView = false
+			m.isHistoryView = false
+			// Build tree from snapshot when available (bv-t435)
+			if m.snapshot != nil {
+				m.tree.BuildFromSnapshot(m.snapshot)
+			} else {
+				m.tree.Build(m.issues)
+			}
+			m.tree.SetSize(m.width, m.height-2)
+			m.focused = focusTree
+		}
+		return m, nil, true
+
+	case ActionInsights:
+		m.clearAttentionOverlay()
+		if m.focused == focusInsights {
+			m.focused = focusList
+		} else {
+			m.isGraphView = false
+			m.isBoardView = false
+			m.isActionableView = false
+			m.isHistoryView = false
+			m.focused = focusInsights
+			// Refresh insights using the current snapshot when available (bv-mpqz).
+			var ins analysis.Insights
+			hasInsights := false
+			if m.snapshot != nil {
+				ins = m.snapshot.Insights
+				hasInsights = true
+			} else if m.analysis != nil {
+				ins = m.analysis.GenerateInsights(len(m.issues))
+				hasInsights = true
+			}
+			if hasInsights {
+				m.insightsPanel = NewInsightsModel(ins, m.issueMap, m.theme)
+				// Include priority triage (bv-91) - reuse existing analyzer/stats (bv-runn.12)
+				triage := analysis.ComputeTriageFromAnalyzer(m.analyzer, m.analysis, m.issues, analysis.TriageOptions{}, time.Now())
+				m.insightsPanel.SetTopPicks(triage.QuickRef.TopPicks)
+				// Set full recommendations with breakdown for priority radar (bv-93)
+				dataHash := fmt.Sprintf("v%s@%s#%d", triage.Meta.Version, triage.Meta.GeneratedAt.Format("15:04:05"), triage.Meta.IssueCount)
+				m.insightsPanel.SetRecommendations(triage.Recommendations, dataHash)
+				panelHeight := m.height - 2
+				if panelHeight < 3 {
+					panelHeight = 3
+				}
+				m.insightsPanel.SetSize(m.width, panelHeight)
+			}
+		}
+		return m, nil, true
+
+	case ActionPriorityHints:
+		// Toggle priority hints
+		m.showPriorityHints = !m.showPriorityHints
+		// Update delegate with new state
+		m.updateListDelegate()
+		// Show explanatory status message
+		if m.showPriorityHints {
+			count := len(m.priorityHints)
+			if count > 0 {
+				m.statusMsg = fmt.Sprintf("Priority hints: ↑ increase ↓ decrease (%d suggestions)", count)
+			} else {
+				m.statusMsg = "Priority hints: No misalignments detected (analysis ongoing)"
+			}
+		} else {
+			m.statusMsg = ""
+		}
+		return m, nil, true
+
+	case ActionHistory:
+		// Toggle history view
+		m.clearAttentionOverlay()
+		m.isHistoryView = !m.isHistoryView
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		if m.isHistoryView {
+			// Ensure history model has latest sizing
+			bodyHeight := m.height - 1
+			if bodyHeight < 5 {
+				bodyHeight = 5
+			}
+			m.historyView.SetSize(m.width, bodyHeight)
+			m.focused = focusHistory
+		} else {
+			m.focused = focusList
+		}
+		return m, nil, true
+
+	case ActionLabelDashboard:
+		// Open label dashboard (phase 1: table view)
+		m.clearAttentionOverlay()
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		m.focused = focusLabelDashboard
+		// Compute label health (fast; phase1 metrics only needed) with caching
+		if !m.labelHealthCached {
+			cfg := analysis.DefaultLabelHealthConfig()
+			m.labelHealthCache = analysis.ComputeAllLabelHealth(m.issues, cfg, time.Now().UTC(), m.analysis)
+			m.labelHealthCached = true
+		}
+		m.labelDashboard.SetData(m.labelHealthCache.Labels)
+		m.labelDashboard.SetSize(m.width, m.height-1)
+		m.statusMsg = fmt.Sprintf("Labels: %d total • critical %d • warning %d", m.labelHealthCache.TotalLabels, m.labelHealthCache.CriticalCount, m.labelHealthCache.WarningCount)
+		m.statusIsError = false
+		return m, nil, true
+
+	case ActionAttention:
+		// Attention view: compute attention scores (cached) and render as text
+		if !m.attentionCached {
+			cfg := analysis.DefaultLabelHealthConfig()
+			m.attentionCache = analysis.ComputeLabelAttentionScores(m.issues, cfg, time.Now().UTC())
+			m.attentionCached = true
+		}
+		attText, _ := ComputeAttentionView(m.issues, max(40, m.width-4))
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		m.focused = focusInsights
+		m.showAttentionView = true
+		m.insightsPanel = NewInsightsModel(analysis.Insights{}, m.issueMap, m.theme)
+		m.insightsPanel.labelAttention = m.attentionCache.Labels
+		m.insightsPanel.extraText = attText
+		panelHeight := m.height - 2
+		if panelHeight < 3 {
+			panelHeight = 3
+		}
+		m.insightsPanel.SetSize(m.width, panelHeight)
+		return m, nil, true
+
+	case ActionFlowMatrix:
+		// Flow matrix view (cross-label dependencies)
+		m.clearAttentionOverlay()
+		cfg := analysis.DefaultLabelHealthConfig()
+		flow := analysis.ComputeCrossLabelFlow(m.issues, cfg)
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		m.focused = focusFlowMatrix
+		m.flowMatrix = NewFlowMatrixModel(m.theme)
+		m.flowMatrix.SetData(&flow, m.issues)
+		panelHeight := m.height - 2
+		if panelHeight < 3 {
+			panelHeight = 3
+		}
+		m.flowMatrix.SetSize(m.width, panelHeight)
+		return m, nil, true
+
+	case ActionFlowMetrics:
+		// Flow metrics dashboard (lead/cycle time, time in status, throughput)
+		if m.focused == focusFlowMetrics {
+			m.focused = focusList
+			return m, nil, true
+		}
+		m.clearAttentionOverlay()
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		m.focused = focusFlowMetrics
+		m.flowMetrics = NewFlowMetricsModel(m.theme)
+		m.flowMetrics.SetData(m.issues, m.statusHistory)
+		m.flowMetrics.SetSize(m.width, m.height-1)
+		if m.statusHistory == nil {
+			if m.historyLoading {
+				m.statusMsg = "Flow metrics: loading git history for cycle time..."
+			} else {
+				m.statusMsg = "Flow metrics: no git history, showing lead time and throughput only"
+			}
+		} else {
+			m.statusMsg = ""
+		}
+		m.statusIsError = false
+		return m, nil, true
+
+	case ActionTimeline:
+		// Gantt timeline of ETAs in dependency order
+		if m.focused == focusTimeline {
+			m.focused = focusList
+			return m, nil, true
+		}
+		m.clearAttentionOverlay()
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		m.focused = focusTimeline
+		m.timeline = NewTimelineModel(m.theme)
+		m.timeline.SetData(m.issues, m.analysis)
+		m.timeline.SetSize(m.width, m.height-1)
+		return m, nil, true
+
+	case ActionForecast:
+		// Monte Carlo completion dates around the selected issue
+		if m.focused == focusForecast {
+			m.focused = focusList
+			return m, nil, true
+		}
+		issueID := m.cursorIssueID()
+		m.clearAttentionOverlay()
+		m.isGraphView = false
+		m.isBoardView = false
+		m.isActionableView = false
+		m.isHistoryView = false
+		m.focused = focusForecast
+		m.forecast = NewForecastModel(m.theme)
+		m.forecast.SetData(m.issues, issueID, m.statusHistory)
+		m.forecast.SetSize(m.width, m.height-1)
+		return m, nil, true
+
+	case ActionAlerts:
+		// Toggle alerts panel (bv-168)
+		// Only show if there are active alerts
+		activeCount := 0
+		for _, a := range m.alerts {
+			if !m.dismissedAlerts[alertKey(a)] {
+				activeCount++
+			}
+		}
+		if activeCount > 0 {
+			m.showAlertsPanel = !m.showAlertsPanel
+			m.alertsCursor = 0 // Reset cursor when opening
+		} else {
+			m.statusMsg = "No active alerts"
+			m.statusIsError = false
+		}
+		return m, nil, true
+
+	case ActionRecipes:
+		// Toggle recipe picker overlay
+		m.showRecipePicker = !m.showRecipePicker
+		if m.showRecipePicker {
+			m.recipePicker.SetSize(m.width, m.height-1)
+			m.focused = focusRecipePicker
+		} else {
+			m.focused = focusList
+		}
+		return m, nil, true
+
+	case ActionSessions:
+		// Saved sessions picker
+		m.openSessionPicker()
+		return m, nil, true
+
+	case ActionThemes:
+		// Theme switcher with live preview
+		m.openThemePicker()
+		return m, nil, true
+
+	case ActionScoringProfile:
+		m.cycleScoringProfile()
+		return m, nil, true
+
+	case ActionRepoPicker:
+		// Toggle repo picker overlay (workspace mode)
+		if !m.workspaceMode || len(m.availableRepos) == 0 {
+			m.statusMsg = "Repo filter available only in workspace mode"
+			m.statusIsError = false
+			return m, nil, true
+		}
+		m.showRepoPicker = !m.showRepoPicker
+		if m.showRepoPicker {
+			m.repoPicker = NewRepoPickerModel(m.availableRepos, m.theme)
+			m.repoPicker.SetActiveRepos(m.activeRepos)
+			m.repoPicker.SetSize(m.width, m.height-1)
+			m.focused = focusRepoPicker
+		} else {
+			m.focused = focusList
+		}
+		return m, nil, true
+
+	case ActionExportMarkdown:
+		// Export the marked issues, or everything when nothing is marked
+		if len(m.markedIssues()) > 0 {
+			m.runBatchOp(batchExportMarkdown)
+		} else {
+			m.exportToMarkdown()
+		}
+		return m, nil, true
+
+	case ActionLabelPicker:
+		// Open label picker for quick filter (bv-126)
+		if len(m.issues) == 0 {
+			return m, nil, true
+		}
+		// Update labels in case they changed
+		labelExtraction := analysis.ExtractLabels(m.issues)
+		labelCounts := extractLabelCounts(labelExtraction.Stats)
+		m.labelPicker.SetLabels(labelExtraction.Labels, labelCounts)
+		m.labelPicker.Reset()
+		m.labelPicker.SetSize(m.width, m.height-1)
+		m.showLabelPicker = true
+		m.focused = focusLabelPicker
+		return m, nil, true
+
+	case ActionMutate:
+		// Write-back actions for the selected issue
+		if m.focused == focusList || m.focused == focusDetail || m.focused == focusBoard {
+			m.openMutationModal()
+			return m, nil, true
+		}
+
+	case ActionMerge:
+		// Resolve conflicts left by bv merge
+		if m.focused == focusList || m.focused == focusDetail || m.focused == focusBoard {
+			m.openMergeModal()
+			return m, nil, true
+		}
+
+	case ActionToggleSelect, ActionSelectDown, ActionSelectUp, ActionSelectAll, ActionClearSelection, ActionBatch:
+		if m.canMarkIssues() {
+			m.runSelectionAction(action)
+			return m, nil, true
+		}
+	}
+	return m, nil, false
+}
+
+// toggleHelp opens or closes the help overlay.
+func (m *Model) toggleHelp() {
+	m.showHelp = !m.showHelp
+	if m.showHelp {
+		m.focusBeforeHelp = m.focused // Store current focus before switching to help
+		m.focused = focusHelp
+		m.helpScroll = 0 // Reset scroll position when opening help
+	} else {
+		m.focused = m.restoreFocusFromHelp()
+	}
+}
+
+// toggleTutorial opens or closes the interactive tutorial (bv-8y31).
+func (m *Model) toggleTutorial() {
+	m.showTutorial = !m.showTutorial
+	if m.showTutorial {
+		m.showHelp = false // Close help if open
+		m.tutorialModel.SetSize(m.width, m.height)
+		m.focused = focusTutorial
+	} else {
+		m.focused = focusList
+	}
+}
+
+// forceRefresh triggers an immediate reload (bv-4auz), at most once a second.
+func (m *Model) forceRefresh() tea.Cmd {
+	now := time.Now()
+	if !m.lastForceRefresh.IsZero() && now.Sub(m.lastForceRefresh) < time.Second {
+		return nil
+	}
+	m.lastForceRefresh = now
+
+	m.statusMsg = "Refreshing…"
+	m.statusIsError = false
+
+	if m.backgroundWorker != nil {
+		m.backgroundWorker.ForceRefresh()
+		return WaitForBackgroundWorkerMsgCmd(m.backgroundWorker)
+	}
+
+	if m.beadsPath == "" && m.watcher == nil {
+		m.statusMsg = "Refresh unavailable"
+		m.statusIsError = true
+		return nil
+	}
+
+	return func() tea.Msg { return FileChangedMsg{} }
+}
+
+// toggleShortcutsSidebar shows or hides the shortcuts sidebar (bv-3qi5).
+func (m *Model) toggleShortcutsSidebar() {
+	m.showShortcutsSidebar = !m.showShortcutsSidebar
+	if m.showShortcutsSidebar {
+		m.shortcutsSidebar.ResetScroll()
+		m.statusMsg = fmt.Sprintf("Shortcuts sidebar: %s hide | ctrl+j/k scroll", m.keymap.ShortLabel(ActionShortcuts))
+		m.statusIsError = false
+	} else {
+		m.statusMsg = ""
+	}
+}
+
+// toggleHybridSearch switches semantic ranking between text-only and
+// hybrid (bv-xbar.6).
+func (m *Model) toggleHybridSearch() tea.Cmd {
+	var cmds []tea.Cmd
+	m.statusIsError = false
+	m.semanticHybridEnabled = !m.semanticHybridEnabled
+	if m.semanticSearch == nil {
+		m.semanticHybridEnabled = false
+		m.statusMsg = "Hybrid search unavailable"
+		m.statusIsError = true
+		return nil
+	}
+	m.semanticSearch.SetHybridConfig(m.semanticHybridEnabled, m.semanticHybridPreset)
+	m.semanticSearch.ResetCache()
+	m.clearSemanticScores()
+	if m.semanticHybridEnabled && !m.semanticHybridReady && !m.semanticHybridBuilding {
+		m.semanticHybridBuilding = true
+		m.statusMsg = "Hybrid search: computing metrics…"
+		cmds = append(cmds, BuildHybridMetricsCmd(m.issuesForAsync()))
+	} else if m.semanticHybridEnabled {
+		m.statusMsg = fmt.Sprintf("Hybrid search enabled (%s)", m.semanticHybridPreset)
+	} else {
+		m.statusMsg = "Semantic search: text-only"
+	}
+	if m.semanticSearchEnabled && m.list.FilterState() != list.Unfiltered {
+		currentTerm := m.searchText()
+		if currentTerm != "" && !m.semanticHybridBuilding {
+			cmds = append(cmds, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm))
+		}
+	}
+	m.updateListDelegate()
+	return tea.Batch(cmds...)
+}
+
+// cycleHybridPreset moves to the next hybrid ranking preset.
+func (m *Model) cycleHybridPreset() tea.Cmd {
+	var cmds []tea.Cmd
+	m.statusIsError = false
+	m.semanticHybridPreset = nextHybridPreset(m.semanticHybridPreset)
+	if m.semanticSearch != nil {
+		m.semanticSearch.SetHybridConfig(m.semanticHybridEnabled, m.semanticHybridPreset)
+		m.semanticSearch.ResetCache()
+	}
+	m.clearSemanticScores()
+	if m.semanticHybridEnabled {
+		m.statusMsg = fmt.Sprintf("Hybrid preset: %s", m.semanticHybridPreset)
+	} else {
+		m.statusMsg = fmt.Sprintf("Hybrid preset set (%s)", m.semanticHybridPreset)
+	}
+	if m.semanticSearchEnabled && m.semanticHybridEnabled && m.list.FilterState() != list.Unfiltered {
+		currentTerm := m.searchText()
+		if currentTerm != "" && !m.semanticHybridBuilding {
+			cmds = append(cmds, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm))
+		}
+	}
+	m.updateListDelegate()
+	return tea.Batch(cmds...)
+}
+
+// toggleSemanticSearch switches the list filter between fuzzy and semantic
+// matching (bv-9gf.3).
+func (m *Model) toggleSemanticSearch() tea.Cmd {
+	var cmds []tea.Cmd
+	m.statusIsError = false
+	m.semanticSearchEnabled = !m.semanticSearchEnabled
+	if m.semanticSearchEnabled {
+		if m.semanticSearch != nil {
+			m.list.Filter = m.queryFilter.Wrap(m.semanticSearch.Filter)
+			if !m.semanticSearch.Snapshot().Ready && !m.semanticIndexBuilding {
+				m.semanticIndexBuilding = true
+				m.statusMsg = "Semantic search: building index…"
+				cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
+			} else if !m.semanticSearch.Snapshot().Ready && m.semanticIndexBuilding {
+				m.statusMsg = "Semantic search: indexing…"
+			} else {
+				m.statusMsg = "Semantic search enabled"
+			}
+		} else {
+			m.semanticSearchEnabled = false
+			m.list.Filter = m.queryFilter.Wrap(list.DefaultFilter)
+			m.statusMsg = "Semantic search unavailable"
+			m.statusIsError = true
+		}
+		if m.semanticHybridEnabled && !m.semanticHybridReady && !m.semanticHybridBuilding {
+			m.semanticHybridBuilding = true
+			cmds = append(cmds, BuildHybridMetricsCmd(m.issuesForAsync()))
+		}
+	} else {
+		m.list.Filter = m.queryFilter.Wrap(list.DefaultFilter)
+		m.statusMsg = "Fuzzy search enabled"
+		m.clearSemanticScores()
+	}
+
+	// Refresh the current list filter results immediately.
+	prevState := m.list.FilterState()
+	filterText := m.list.FilterInput.Value()
+	if prevState != list.Unfiltered {
+		m.list.SetFilterText(filterText)
+		if prevState == list.Filtering {
+			m.list.SetFilterState(list.Filtering)
+		}
+	}
+
+	m.updateListDelegate()
+	return tea.Batch(cmds...)
 }
 
 // handleBoardKeys handles keyboard input when the board is focused (bv-yg39)
@@ -3358,25 +3795,37 @@ func (m Model) handleBoardKeys(msg tea.KeyMsg) Model {
 			m.board.FinishSearch()
 		case "backspace":
 			m.board.BackspaceSearch()
-		case "n":
-			m.board.NextMatch()
-		case "N":
-			m.board.PrevMatch()
 		default:
-			// Append printable characters to search query
-			if len(key) == 1 {
-				m.board.AppendSearchChar(rune(key[0]))
+			switch m.keymap.lookupIn(scopeBoard, key) {
+			case ActionBoardNextMatch:
+				m.board.NextMatch()
+			case ActionBoardPrevMatch:
+				m.board.PrevMatch()
+			default:
+				// Append printable characters to search query
+				if len(key) == 1 {
+					m.board.AppendSearchChar(rune(key[0]))
+				}
 			}
 		}
 		return m
 	}
 
+	action := m.keymap.lookupIn(scopeBoard, key)
+	if action == ActionNone {
+		// The list's filter keys also filter the board (bv-naov)
+		switch filter := m.keymap.Lookup(key); filter {
+		case ActionFilterOpen, ActionFilterClosed, ActionFilterReady:
+			action = filter
+		}
+	}
+
 	// ═══════════════════════════════════════════════════════════════════════════
 	// Vim 'gg' combo handling (bv-yg39)
 	// ═══════════════════════════════════════════════════════════════════════════
 	if m.board.IsWaitingForG() {
 		m.board.ClearWaitingForG()
-		if key == "g" {
+		if action == ActionBoardTopChord {
 			m.board.MoveToTop()
 			return m
 		}
@@ -3386,63 +3835,59 @@ func (m Model) handleBoardKeys(msg tea.KeyMsg) Model {
 	// ═══════════════════════════════════════════════════════════════════════════
 	// Normal key handling (bv-yg39 enhanced)
 	// ═══════════════════════════════════════════════════════════════════════════
-	switch key {
+	switch action {
 	// Basic navigation (existing)
-	case "h", "left":
+	case ActionBoardLeft:
 		m.board.MoveLeft()
-	case "l", "right":
+	case ActionBoardRight:
 		m.board.MoveRight()
-	case "j", "down":
+	case ActionBoardDown:
 		m.board.MoveDown()
-	case "k", "up":
+	case ActionBoardUp:
 		m.board.MoveUp()
-	case "home":
-		m.board.MoveToTop()
-	case "G", "end":
-		m.board.MoveToBottom()
-	case "ctrl+d":
+	case ActionBoardTop:
+		m.board.MoveToTop() // First item in column
+	case ActionBoardBottom:
+		m.board.MoveToBottom() // Last item in column
+	case ActionBoardPageDown:
 		m.board.PageDown(m.height / 3)
-	case "ctrl+u":
+	case ActionBoardPageUp:
 		m.board.PageUp(m.height / 3)
 
 	// Column jumping (bv-yg39)
-	case "1":
+	case ActionBoardColOpen:
 		m.board.JumpToColumn(ColOpen)
-	case "2":
+	case ActionBoardColInProgress:
 		m.board.JumpToColumn(ColInProgress)
-	case "3":
+	case ActionBoardColBlocked:
 		m.board.JumpToColumn(ColBlocked)
-	case "4":
+	case ActionBoardColClosed:
 		m.board.JumpToColumn(ColClosed)
-	case "H":
+	case ActionBoardFirstCol:
 		m.board.JumpToFirstColumn()
-	case "L":
+	case ActionBoardLastCol:
 		m.board.JumpToLastColumn()
 
 	// Vim-style navigation (bv-yg39)
-	case "g":
-		m.board.SetWaitingForG() // Wait for second 'g'
-	case "0":
-		m.board.MoveToTop() // First item in column
-	case "$":
-		m.board.MoveToBottom() // Last item in column
+	case ActionBoardTopChord:
+		m.board.SetWaitingForG() // Wait for the second press
 
 	// Search (bv-yg39)
-	case "/":
+	case ActionBoardSearch:
 		m.board.StartSearch()
 
 	// Search navigation when not in search mode (bv-yg39)
-	case "n":
+	case ActionBoardNextMatch:
 		if m.board.SearchMatchCount() > 0 {
 			m.board.NextMatch()
 		}
-	case "N":
+	case ActionBoardPrevMatch:
 		if m.board.SearchMatchCount() > 0 {
 			m.board.PrevMatch()
 		}
 
 	// Copy ID to clipboard (bv-yg39)
-	case "y":
+	case ActionBoardCopyID:
 		if selected := m.board.SelectedIssue(); selected != nil {
 			if err := clipboard.WriteAll(selected.ID); err != nil {
 				m.statusMsg = fmt.Sprintf("❌ Clipboard error: %v", err)
@@ -3454,31 +3899,31 @@ func (m Model) handleBoardKeys(msg tea.KeyMsg) Model {
 		}
 
 	// Global filter keys (bv-naov) - consistent with list view
-	case "o":
+	case ActionFilterOpen:
 		m.currentFilter = "open"
 		m.applyFilter()
 		m.statusMsg = "Filter: Open issues"
 		m.statusIsError = false
-	case "c":
+	case ActionFilterClosed:
 		m.currentFilter = "closed"
 		m.applyFilter()
 		m.statusMsg = "Filter: Closed issues"
 		m.statusIsError = false
-	case "r":
+	case ActionFilterReady:
 		m.currentFilter = "ready"
 		m.applyFilter()
 		m.statusMsg = "Filter: Ready (no blockers)"
 		m.statusIsError = false
 
 	// Swimlane mode cycling (bv-wjs0)
-	case "s":
+	case ActionBoardSwimLanes:
 		m.board.CycleSwimLaneMode()
 		modeName := m.board.GetSwimLaneModeName()
 		m.statusMsg = fmt.Sprintf("🔀 Swimlane: %s", modeName)
 		m.statusIsError = false
 
 	// Empty column visibility toggle (bv-tf6j)
-	case "e":
+	case ActionBoardEmptyCols:
 		m.board.ToggleEmptyColumns()
 		visMode := m.board.GetEmptyColumnVisibilityMode()
 		hidden := m.board.HiddenColumnCount()
@@ -3490,29 +3935,31 @@ func (m Model) handleBoardKeys(msg tea.KeyMsg) Model {
 		m.statusIsError = false
 
 	// Inline card expansion (bv-i3ii)
-	case "d":
+	case ActionBoardExpandCard:
 		m.board.ToggleExpand()
 		if m.board.HasExpandedCard() {
-			m.statusMsg = "📋 Card expanded (d=collapse, j/k=auto-collapse)"
+			m.statusMsg = fmt.Sprintf("📋 Card expanded (%s=collapse, %s=auto-collapse)",
+				m.keymap.ShortLabel(ActionBoardExpandCard),
+				joinKeyLabels(m.keymap.ShortLabel(ActionBoardDown), m.keymap.ShortLabel(ActionBoardUp)))
 		} else {
 			m.statusMsg = "📋 Card collapsed"
 		}
 		m.statusIsError = false
 
 	// Detail panel (bv-r6kh)
-	case "tab":
+	case ActionBoardDetail:
 		m.board.ToggleDetail()
-	case "ctrl+j":
+	case ActionBoardDetailDown:
 		if m.board.IsDetailShown() {
 			m.board.DetailScrollDown(3)
 		}
-	case "ctrl+k":
+	case ActionBoardDetailUp:
 		if m.board.IsDetailShown() {
 			m.board.DetailScrollUp(3)
 		}
 
 	// Exit to detail view
-	case "enter":
+	case ActionBoardOpen:
 		if selected := m.board.SelectedIssue(); selected != nil {
 			for i, item := range m.list.Items() {
 				if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == selected.ID {
@@ -3537,24 +3984,24 @@ func (m Model) handleBoardKeys(msg tea.KeyMsg) Model {
 
 // handleGraphKeys handles keyboard input when the graph view is focused
 func (m Model) handleGraphKeys(msg tea.KeyMsg) Model {
-	switch msg.String() {
-	case "h", "left":
+	switch m.keymap.lookupIn(scopeGraph, msg.String()) {
+	case ActionGraphLeft:
 		m.graphView.MoveLeft()
-	case "l", "right":
+	case ActionGraphRight:
 		m.graphView.MoveRight()
-	case "j", "down":
+	case ActionGraphDown:
 		m.graphView.MoveDown()
-	case "k", "up":
+	case ActionGraphUp:
 		m.graphView.MoveUp()
-	case "ctrl+d", "pgdown":
+	case ActionGraphPageDown:
 		m.graphView.PageDown()
-	case "ctrl+u", "pgup":
+	case ActionGraphPageUp:
 		m.graphView.PageUp()
-	case "H":
+	case ActionGraphScrollLeft:
 		m.graphView.ScrollLeft()
-	case "L":
+	case ActionGraphScrollRight:
 		m.graphView.ScrollRight()
-	case "enter":
+	case ActionGraphOpen:
 		if selected := m.graphView.SelectedIssue(); selected != nil {
 			// Find and select in list
 			for i, item := range m.list.Items() {
@@ -3580,34 +4027,34 @@ func (m Model) handleGraphKeys(msg tea.KeyMsg) Model {
 
 // handleTreeKeys handles keyboard input when tree view is focused (bv-gllx)
 func (m Model) handleTreeKeys(msg tea.KeyMsg) Model {
-	switch msg.String() {
-	case "j", "down":
+	switch m.keymap.lookupIn(scopeTree, msg.String()) {
+	case ActionTreeDown:
 		m.tree.MoveDown()
-	case "k", "up":
+	case ActionTreeUp:
 		m.tree.MoveUp()
-	case "enter", " ":
+	case ActionTreeToggle:
 		m.tree.ToggleExpand()
-	case "h", "left":
+	case ActionTreeCollapse:
 		m.tree.CollapseOrJumpToParent()
-	case "l", "right":
+	case ActionTreeExpand:
 		m.tree.ExpandOrMoveToChild()
-	case "g":
+	case ActionTreeTop:
 		// Jump to top (vim-style)
 		m.tree.JumpToTop()
-	case "G":
+	case ActionTreeBottom:
 		m.tree.JumpToBottom()
-	case "o":
+	case ActionTreeExpandAll:
 		m.tree.ExpandAll()
-	case "O":
+	case ActionTreeCollapseAll:
 		m.tree.CollapseAll()
-	case "ctrl+d", "pgdown":
+	case ActionTreePageDown:
 		m.tree.PageDown()
-	case "ctrl+u", "pgup":
+	case ActionTreePageUp:
 		m.tree.PageUp()
-	case "E", "esc":
+	case ActionTreeClose:
 		// Return to list view
 		m.focused = focusList
-	case "tab":
+	case ActionTreeDetail:
 		// Toggle detail panel (sync selection and jump to detail)
 		if m.isSplitView {
 			if selected := m.tree.SelectedIssue(); selected != nil {
@@ -3687,15 +4134,16 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 	}
 
 	// Handle file tree navigation when file tree has focus (bv-190l)
+	action := m.keymap.lookupIn(scopeHistory, msg.String())
 	if m.historyView.FileTreeHasFocus() {
-		switch msg.String() {
-		case "j", "down":
+		switch action {
+		case ActionHistoryDown:
 			m.historyView.MoveDownFileTree()
 			return m
-		case "k", "up":
+		case ActionHistoryUp:
 			m.historyView.MoveUpFileTree()
 			return m
-		case "enter", "l":
+		case ActionHistoryOpen, ActionHistoryRight:
 			// Expand directory or select file for filtering
 			node := m.historyView.SelectedFileNode()
 			if node != nil {
@@ -3709,35 +4157,35 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 				}
 			}
 			return m
-		case "h":
+		case ActionHistoryLeft:
 			// Collapse directory
 			m.historyView.CollapseFileNode()
 			return m
-		case "esc":
+		case ActionHistoryBack:
 			// If filter is active, clear it; otherwise close file tree
 			if m.historyView.GetFileFilter() != "" {
 				m.historyView.ClearFileFilter()
 				m.statusMsg = "📁 File filter cleared"
 			} else {
 				m.historyView.SetFileTreeFocus(false)
-				m.statusMsg = "📁 File tree: press Tab to return focus"
+				m.statusMsg = fmt.Sprintf("📁 File tree: press %s to return focus", m.keymap.ShortLabel(ActionHistoryFocus))
 			}
 			m.statusIsError = false
 			return m
-		case "tab":
+		case ActionHistoryFocus:
 			// Switch focus away from file tree
 			m.historyView.SetFileTreeFocus(false)
 			return m
 		}
 	}
 
-	switch msg.String() {
-	case "/":
+	switch action {
+	case ActionHistorySearch:
 		// Start search (bv-nkrj)
 		m.historyView.StartSearch()
 		m.statusMsg = "🔍 Type to search commits, beads, authors..."
 		m.statusIsError = false
-	case "v":
+	case ActionHistoryMode:
 		// Toggle between Bead mode and Git mode (bv-tl3n)
 		m.historyView.ToggleViewMode()
 		if m.historyView.IsGitMode() {
@@ -3746,33 +4194,33 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			m.statusMsg = "📦 Bead Mode: beads on left, commits on right"
 		}
 		m.statusIsError = false
-	case "j", "down":
+	case ActionHistoryDown:
 		if m.historyView.IsGitMode() {
 			m.historyView.MoveDownGit()
 		} else {
 			m.historyView.MoveDown()
 		}
-	case "k", "up":
+	case ActionHistoryUp:
 		if m.historyView.IsGitMode() {
 			m.historyView.MoveUpGit()
 		} else {
 			m.historyView.MoveUp()
 		}
-	case "J":
+	case ActionHistoryNext:
 		// In git mode: navigate to next related bead; in bead mode: next commit
 		if m.historyView.IsGitMode() {
 			m.historyView.NextRelatedBead()
 		} else {
 			m.historyView.NextCommit()
 		}
-	case "K":
+	case ActionHistoryPrev:
 		// In git mode: navigate to prev related bead; in bead mode: prev commit
 		if m.historyView.IsGitMode() {
 			m.historyView.PrevRelatedBead()
 		} else {
 			m.historyView.PrevCommit()
 		}
-	case "tab":
+	case ActionHistoryFocus:
 		// Cycle focus: list -> detail -> file tree (if visible) -> list (bv-190l)
 		if m.historyView.IsFileTreeVisible() {
 			if m.historyView.FileTreeHasFocus() {
@@ -3788,7 +4236,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 		} else {
 			m.historyView.ToggleFocus()
 		}
-	case "enter":
+	case ActionHistoryOpen:
 		// Jump to selected bead in main list
 		var selectedID string
 		if m.historyView.IsGitMode() {
@@ -3814,7 +4262,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			}
 			m.updateViewportContent()
 		}
-	case "y":
+	case ActionHistoryCopySHA:
 		// Copy selected commit SHA to clipboard
 		var sha, shortSHA string
 		if m.historyView.IsGitMode() {
@@ -3840,7 +4288,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			m.statusMsg = "❌ No commit selected"
 			m.statusIsError = true
 		}
-	case "c":
+	case ActionHistoryConfidence:
 		// Cycle confidence threshold (only in bead mode)
 		if !m.historyView.IsGitMode() {
 			m.historyView.CycleConfidence()
@@ -3852,7 +4300,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			}
 			m.statusIsError = false
 		}
-	case "f", "F":
+	case ActionHistoryFileTree:
 		// Toggle file tree panel (bv-190l)
 		m.historyView.ToggleFileTree()
 		if m.historyView.IsFileTreeVisible() {
@@ -3861,7 +4309,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			m.statusMsg = "📁 File tree hidden"
 		}
 		m.statusIsError = false
-	case "o":
+	case ActionHistoryBrowser:
 		// Open commit in browser (bv-xf4p)
 		var sha string
 		if m.historyView.IsGitMode() {
@@ -3896,7 +4344,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			m.statusMsg = "❌ No commit selected"
 			m.statusIsError = true
 		}
-	case "g":
+	case ActionHistoryGraph:
 		// Jump to graph view for selected bead (bv-xf4p)
 		var selectedID string
 		if m.historyView.IsGitMode() {
@@ -3922,7 +4370,7 @@ func (m Model) handleHistoryKeys(msg tea.KeyMsg) Model {
 			m.statusMsg = "❌ No bead selected"
 			m.statusIsError = true
 		}
-	case "h", "esc":
+	case ActionHistoryLeft, ActionHistoryBack:
 		// Exit history view
 		m.isHistoryView = false
 		m.focused = focusList
@@ -4135,35 +4583,112 @@ func (m Model) handleLabelPickerKeys(msg tea.KeyMsg) Model {
 	return m
 }
 
-// handleInsightsKeys handles keyboard input when insights panel is focused
-func (m Model) handleInsightsKeys(msg tea.KeyMsg) Model {
+// openCommandPalette shows the command palette over the current view.
+func (m *Model) openCommandPalette() {
+	m.commandPalette.Reset()
+	m.commandPalette.SetSize(m.width, m.height-1)
+	m.showCommandPalette = true
+	m.focusBeforePalette = m.focused
+	m.focused = focusCommandPalette
+}
+
+// handleCommandPaletteKeys handles keyboard input while the command palette
+// is open. Enter closes it and runs the selected action from the view it was
+// opened in.
+func (m Model) handleCommandPaletteKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
+		m.showCommandPalette = false
+		m.focused = m.focusBeforePalette
+	case "down", "ctrl+n", "tab":
+		m.commandPalette.MoveDown()
+	case "up", "ctrl+p", "shift+tab":
+		m.commandPalette.MoveUp()
+	case "enter":
+		action := m.commandPalette.Selected()
+		m.showCommandPalette = false
+		m.focused = m.focusBeforePalette
+		if action != ActionNone {
+			return m.runAction(action)
+		}
+	default:
+		m.commandPalette.UpdateInput(msg)
+	}
+	return m, nil
+}
+
+// runAction performs action as if its key had been pressed, regardless of
+// what it is bound to.
+func (m Model) runAction(action Action) (Model, tea.Cmd) {
+	switch action {
+	case ActionCommandPalette:
+		m.openCommandPalette()
+		return m, nil
+	case ActionHelp:
+		m.toggleHelp()
+		return m, nil
+	case ActionTutorial:
+		m.toggleTutorial()
+		return m, nil
+	case ActionRefresh:
+		return m, m.forceRefresh()
+	case ActionShortcuts:
+		m.toggleShortcutsSidebar()
+		return m, nil
+	case ActionSemanticSearch:
+		return m, m.toggleSemanticSearch()
+	case ActionHybridSearch:
+		return m, m.toggleHybridSearch()
+	case ActionHybridPreset:
+		return m, m.cycleHybridPreset()
+	case ActionSearch:
 		m.focused = focusList
-	case "j", "down":
+		m.list.SetFilterState(list.Filtering)
+		m.list.FilterInput.Focus()
+		return m, textinput.Blink
+	case ActionMoveDown:
+		m.list.CursorDown()
+		return m, nil
+	case ActionMoveUp:
+		m.list.CursorUp()
+		return m, nil
+	}
+	if action.Global() {
+		m, cmd, _ := m.runGlobalAction(action)
+		return m, cmd
+	}
+	return m.runListAction(action), nil
+}
+
+// handleInsightsKeys handles keyboard input when insights panel is focused
+func (m Model) handleInsightsKeys(msg tea.KeyMsg) Model {
+	switch m.keymap.lookupIn(scopeInsights, msg.String()) {
+	case ActionInsightsClose:
+		m.focused = focusList
+	case ActionInsightsDown:
 		m.insightsPanel.MoveDown()
-	case "k", "up":
+	case ActionInsightsUp:
 		m.insightsPanel.MoveUp()
-	case "ctrl+j":
+	case ActionInsightsDetailDown:
 		// Scroll detail panel down
 		m.insightsPanel.ScrollDetailDown()
-	case "ctrl+k":
+	case ActionInsightsDetailUp:
 		// Scroll detail panel up
 		m.insightsPanel.ScrollDetailUp()
-	case "h", "left":
+	case ActionInsightsPrevPanel:
 		m.insightsPanel.PrevPanel()
-	case "l", "right", "tab":
+	case ActionInsightsNextPanel:
 		m.insightsPanel.NextPanel()
-	case "e":
+	case ActionInsightsExplain:
 		// Toggle explanations
 		m.insightsPanel.ToggleExplanations()
-	case "x":
+	case ActionInsightsCalc:
 		// Toggle calculation details
 		m.insightsPanel.ToggleCalculation()
-	case "m":
+	case ActionInsightsHeatmap:
 		// Toggle heatmap view (bv-95) - "m" for heatMap
 		m.insightsPanel.ToggleHeatmap()
-	case "enter":
+	case ActionInsightsOpen:
 		// Jump to selected issue in list view
 		selectedID := m.insightsPanel.SelectedIssueID()
 		if selectedID != "" {
@@ -4189,21 +4714,26 @@ func (m Model) handleInsightsKeys(msg tea.KeyMsg) Model {
 
 // handleListKeys handles keyboard input when the list is focused
 func (m Model) handleListKeys(msg tea.KeyMsg) Model {
-	switch msg.String() {
-	case "enter":
+	return m.runListAction(m.keymap.Lookup(msg.String()))
+}
+
+// runListAction performs an action that applies to the issue list.
+func (m Model) runListAction(action Action) Model {
+	switch action {
+	case ActionOpenDetails:
 		if !m.isSplitView {
 			m.showDetails = true
 			m.focused = focusDetail
 			m.viewport.GotoTop() // Reset scroll position for new issue
 			m.updateViewportContent()
 		}
-	case "home":
+	case ActionListTop:
 		m.list.Select(0)
-	case "G", "end":
+	case ActionListBottom:
 		if len(m.list.Items()) > 0 {
 			m.list.Select(len(m.list.Items()) - 1)
 		}
-	case "ctrl+d":
+	case ActionPageDown:
 		// Page down
 		itemCount := len(m.list.Items())
 		if itemCount > 0 {
@@ -4214,7 +4744,7 @@ func (m Model) handleListKeys(msg tea.KeyMsg) Model {
 			}
 			m.list.Select(newIdx)
 		}
-	case "ctrl+u":
+	case ActionPageUp:
 		// Page up
 		if len(m.list.Items()) > 0 {
 			currentIdx := m.list.Index()
@@ -4224,19 +4754,19 @@ func (m Model) handleListKeys(msg tea.KeyMsg) Model {
 			}
 			m.list.Select(newIdx)
 		}
-	case "o":
+	case ActionFilterOpen:
 		m.currentFilter = "open"
 		m.applyFilter()
-	case "c":
+	case ActionFilterClosed:
 		m.currentFilter = "closed"
 		m.applyFilter()
-	case "r":
+	case ActionFilterReady:
 		m.currentFilter = "ready"
 		m.applyFilter()
-	case "a":
+	case ActionFilterAll:
 		m.currentFilter = "all"
 		m.applyFilter()
-	case "t":
+	case ActionTimeTravel:
 		// Toggle time-travel mode off, or show prompt for custom revision
 		if m.timeTravelMode {
 			m.exitTimeTravelMode()
@@ -4247,40 +4777,44 @@ func (m Model) handleListKeys(msg tea.KeyMsg) Model {
 			m.timeTravelInput.Focus()
 			m.focused = focusTimeTravelInput
 		}
-	case "T":
+	case ActionQuickTimeTravel:
 		// Quick time-travel with default HEAD~5
 		if m.timeTravelMode {
 			m.exitTimeTravelMode()
 		} else {
 			m.enterTimeTravelMode("HEAD~5")
 		}
-	case "C":
-		// Copy selected issue to clipboard
-		m.copyIssueToClipboard()
-	case "O":
+	case ActionCopyIssue:
+		// Copy the marked issues, or the one under the cursor
+		if len(m.markedIssues()) > 0 {
+			m.runBatchOp(batchCopyMarkdown)
+		} else {
+			m.copyIssueToClipboard()
+		}
+	case ActionOpenEditor:
 		// Open beads.jsonl in editor
 		m.openInEditor()
-	case "h":
+	case ActionHistory:
 		// Toggle history view
 		if !m.isHistoryView {
 			m.enterHistoryView()
 		}
-	case "S":
+	case ActionTriageSort:
 		// Apply triage recipe - sort by triage score (bv-151)
 		if r := m.recipeLoader.Get("triage"); r != nil {
 			m.setActiveRecipe(r)
 			m.applyRecipe(r)
 		}
-	case "s":
+	case ActionCycleSort:
 		// Cycle sort mode (bv-3ita)
 		m.cycleSortMode()
-	case "V":
+	case ActionCassSessions:
 		// Show cass session preview modal (bv-5bqh)
 		m.showCassSessionModal()
-	case "U":
+	case ActionSelfUpdate:
 		// Show self-update modal (bv-182)
 		m.showSelfUpdateModal()
-	case "y":
+	case ActionCopyID:
 		// Copy ID to clipboard (consistent with board view - bv-yg39)
 		selectedItem := m.list.SelectedItem()
 		if selectedItem == nil {
@@ -4359,6 +4893,15 @@ func (m Model) restoreFocusFromHelp() focus {
 	if m.focusBeforeHelp == focusFlowMatrix {
 		return focusFlowMatrix
 	}
+	if m.focusBeforeHelp == focusFlowMetrics {
+		return focusFlowMetrics
+	}
+	if m.focusBeforeHelp == focusTimeline {
+		return focusTimeline
+	}
+	if m.focusBeforeHelp == focusForecast {
+		return focusForecast
+	}
 	if m.focusBeforeHelp == focusAttention {
 		return focusAttention
 	}
@@ -4455,6 +4998,12 @@ func (m Model) View() string {
 	} else if m.showUpdateModal {
 		// Self-update modal (bv-182)
 		body = m.updateModal.CenterModal(m.width, m.height-1)
+	} else if m.showMutationModal {
+		body = m.mutationModal.CenterModal(m.width, m.height-1)
+	} else if m.showBatchModal {
+		body = m.batchModal.CenterModal(m.width, m.height-1)
+	} else if m.showMergeModal {
+		body = m.mergeModal.CenterModal(m.width, m.height-1)
 	} else if m.showLabelHealthDetail && m.labelHealthDetail != nil {
 		body = m.renderLabelHealthDetail(*m.labelHealthDetail)
 	} else if m.showLabelGraphAnalysis && m.labelGraphAnalysisResult != nil {
@@ -4465,8 +5014,14 @@ func (m Model) View() string {
 		body = m.renderAlertsPanel()
 	} else if m.showTimeTravelPrompt {
 		body = m.renderTimeTravelPrompt()
+	} else if m.showCommandPalette {
+		body = m.commandPalette.View()
 	} else if m.showRecipePicker {
 		body = m.recipePicker.View()
+	} else if m.showSessionPicker {
+		body = m.sessionPicker.View()
+	} else if m.showThemePicker {
+		body = m.themePicker.View()
 	} else if m.showRepoPicker {
 		body = m.repoPicker.View()
 	} else if m.showLabelPicker {
@@ -4484,6 +5039,15 @@ func (m Model) View() string {
 	} else if m.focused == focusFlowMatrix {
 		m.flowMatrix.SetSize(m.width, m.height-1)
 		body = m.flowMatrix.View()
+	} else if m.focused == focusFlowMetrics {
+		m.flowMetrics.SetSize(m.width, m.height-1)
+		body = m.flowMetrics.View()
+	} else if m.focused == focusTimeline {
+		m.timeline.SetSize(m.width, m.height-1)
+		body = m.timeline.View()
+	} else if m.focused == focusForecast {
+		m.forecast.SetSize(m.width, m.height-1)
+		body = m.forecast.View()
 	} else if m.focused == focusTree {
 		// Hierarchical tree view (bv-gllx)
 		m.tree.SetSize(m.width, m.height-1)
@@ -4790,6 +5354,9 @@ func (m *Model) renderHelpOverlay() string {
 		content.WriteString("\n")
 
 		for _, s := range shortcuts {
+			if s.key == "" {
+				continue // Unbound in the active keymap
+			}
 			content.WriteString(keyStyle.Render(s.key))
 			content.WriteString(descStyle.Render(s.desc))
 			content.WriteString("\n")
@@ -4804,85 +5371,108 @@ func (m *Model) renderHelpOverlay() string {
 		return panelStyle.Render(content.String())
 	}
 
-	// Define all sections
+	// Define all sections; remappable keys come from the active keymap
+	km := m.keymap
 	navSection := []struct{ key, desc string }{
-		{"j / ↓", "Move down"},
-		{"k / ↑", "Move up"},
-		{"G/end", "Go to last"},
-		{"Ctrl+d", "Page down"},
-		{"Ctrl+u", "Page up"},
-		{"Tab", "Switch focus"},
-		{"Enter", "View details"},
-		{"Esc", "Back / close"},
+		{km.Label(ActionMoveDown), "Move down"},
+		{km.Label(ActionMoveUp), "Move up"},
+		{km.Label(ActionListBottom), "Go to last"},
+		{km.Label(ActionPageDown), "Page down"},
+		{km.Label(ActionPageUp), "Page up"},
+		{km.Label(ActionToggleFocus), "Switch focus"},
+		{km.Label(ActionOpenDetails), "View details"},
+		{km.Label(ActionBack), "Back / close"},
 	}
 
 	viewsSection := []struct{ key, desc string }{
-		{"b", "Kanban board"},
-		{"g", "Graph view"},
-		{"i", "Insights"},
-		{"h", "History view"},
-		{"a", "Actionable"},
-		{"f", "Flow matrix"},
-		{"[", "Label dashboard"},
-		{"]", "Attention view"},
+		{km.Label(ActionBoard), "Kanban board"},
+		{km.Label(ActionGraph), "Graph view"},
+		{km.Label(ActionInsights), "Insights"},
+		{km.Label(ActionHistory), "History view"},
+		{km.Label(ActionActionable), "Actionable"},
+		{km.Label(ActionTree), "Hierarchy tree"},
+		{km.Label(ActionFlowMatrix), "Flow matrix"},
+		{km.Label(ActionFlowMetrics), "Flow metrics"},
+		{km.Label(ActionTimeline), "Timeline"},
+		{km.Label(ActionForecast), "Delivery forecast"},
+		{km.ShortLabel(ActionLabelDashboard), "Label dashboard"},
+		{km.ShortLabel(ActionAttention), "Attention view"},
 	}
 
 	globalSection := []struct{ key, desc string }{
-		{"?", "This help"},
-		{";", "Shortcuts bar"},
-		{"!", "Alerts panel"},
-		{"'", "Recipes"},
-		{"w", "Repo picker"},
-		{"q", "Back / Quit"},
+		{km.ShortLabel(ActionHelp), "This help"},
+		{km.Label(ActionCommandPalette), "Command palette"},
+		{km.ShortLabel(ActionShortcuts), "Shortcuts bar"},
+		{km.Label(ActionAlerts), "Alerts panel"},
+		{km.Label(ActionRecipes), "Recipes"},
+		{km.Label(ActionSessions), "Saved sessions"},
+		{km.Label(ActionThemes), "Switch theme"},
+		{km.Label(ActionScoringProfile), "Scoring profile"},
+		{km.Label(ActionRepoPicker), "Repo picker"},
+		{km.Label(ActionQuit), "Back / Quit"},
 		{"Ctrl+c", "Force quit"},
 	}
 
 	filterSection := []struct{ key, desc string }{
-		{"/", "Fuzzy search"},
-		{"Ctrl+S", "Semantic search"},
-		{"H", "Hybrid ranking"},
-		{"Alt+H", "Hybrid preset"},
-		{"o", "Open issues"},
-		{"c", "Closed issues"},
-		{"r", "Ready (unblocked)"},
-		{"l", "Filter by label"},
-		{"s", "Cycle sort"},
-		{"S", "Triage sort"},
-	}
-
+		{km.Label(ActionSearch), "Fuzzy search"},
+		{km.Label(ActionSemanticSearch), "Semantic search"},
+		{km.Label(ActionHybridSearch), "Hybrid ranking"},
+		{km.Label(ActionHybridPreset), "Hybrid preset"},
+		{km.Label(ActionFilterOpen), "Open issues"},
+		{km.Label(ActionFilterClosed), "Closed issues"},
+		{km.Label(ActionFilterReady), "Ready (unblocked)"},
+		{km.Label(ActionFilterAll), "All issues"},
+		{km.Label(ActionLabelPicker), "Filter by label"},
+		{km.Label(ActionCycleSort), "Cycle sort"},
+		{km.Label(ActionTriageSort), "Triage sort"},
+	}
+
+	short := km.ShortLabel
 	graphSection := []struct{ key, desc string }{
-		{"hjkl", "Navigate nodes"},
-		{"H/L", "Scroll left/right"},
-		{"PgUp/Dn", "Scroll up/down"},
-		{"Enter", "Jump to issue"},
+		{short(ActionGraphLeft) + short(ActionGraphDown) + short(ActionGraphUp) + short(ActionGraphRight), "Navigate nodes"},
+		{joinKeyLabels(short(ActionGraphScrollLeft), short(ActionGraphScrollRight)), "Scroll left/right"},
+		{km.Label(ActionGraphPageDown), "Page down"},
+		{km.Label(ActionGraphPageUp), "Page up"},
+		{short(ActionGraphOpen), "Jump to issue"},
 	}
 
 	insightsSection := []struct{ key, desc string }{
-		{"h/l/Tab", "Switch panels"},
-		{"j/k", "Navigate items"},
-		{"e", "Explanations"},
-		{"x", "Calc details"},
-		{"m", "Toggle heatmap"},
-		{"Enter", "Jump to issue"},
+		{joinKeyLabels(short(ActionInsightsPrevPanel), short(ActionInsightsNextPanel)), "Switch panels"},
+		{joinKeyLabels(short(ActionInsightsDown), short(ActionInsightsUp)), "Navigate items"},
+		{short(ActionInsightsExplain), "Explanations"},
+		{short(ActionInsightsCalc), "Calc details"},
+		{short(ActionInsightsHeatmap), "Toggle heatmap"},
+		{short(ActionInsightsOpen), "Jump to issue"},
 	}
 
 	historySection := []struct{ key, desc string }{
-		{"j/k", "Navigate beads"},
-		{"J/K", "Navigate commits"},
-		{"Tab", "Toggle focus"},
-		{"y", "Copy SHA"},
-		{"c", "Confidence filter"},
+		{joinKeyLabels(short(ActionHistoryDown), short(ActionHistoryUp)), "Navigate beads"},
+		{joinKeyLabels(short(ActionHistoryNext), short(ActionHistoryPrev)), "Navigate commits"},
+		{short(ActionHistoryFocus), "Toggle focus"},
+		{short(ActionHistoryCopySHA), "Copy SHA"},
+		{short(ActionHistoryConfidence), "Confidence filter"},
 	}
 
 	actionsSection := []struct{ key, desc string }{
-		{"p", "Priority hints"},
-		{"Ctrl+R", "Force refresh"},
-		{"F5", "Force refresh"},
-		{"t", "Time-travel"},
-		{"T", "Quick time-travel"},
-		{"x", "Export markdown"},
-		{"C", "Copy to clipboard"},
-		{"O", "Open in editor"},
+		{km.Label(ActionPriorityHints), "Priority hints"},
+		{km.Label(ActionRefresh), "Force refresh"},
+		{km.Label(ActionTimeTravel), "Time-travel"},
+		{km.Label(ActionQuickTimeTravel), "Quick time-travel"},
+		{km.Label(ActionExportMarkdown), "Export markdown"},
+		{km.Label(ActionCopyID), "Copy ID"},
+		{km.Label(ActionCopyIssue), "Copy to clipboard"},
+		{km.Label(ActionOpenEditor), "Open in editor"},
+		{km.Label(ActionMutate), "Claim/close/update"},
+		{km.Label(ActionMerge), "Resolve merge conflicts"},
+	}
+
+	selectSection := []struct{ key, desc string }{
+		{km.Label(ActionToggleSelect), "Mark / unmark"},
+		{km.ShortLabel(ActionSelectDown), "Extend marks down"},
+		{km.ShortLabel(ActionSelectUp), "Extend marks up"},
+		{km.Label(ActionSelectAll), "Mark all shown"},
+		{km.Label(ActionClearSelection), "Clear marks"},
+		{km.Label(ActionBatch), "Batch actions"},
 	}
 
 	statusSection := []struct{ key, desc string }{
@@ -4906,6 +5496,7 @@ func (m *Model) renderHelpOverlay() string {
 		renderPanel("Status", "🩺", 2, statusSection),
 		renderPanel("History", "📜", 0, historySection),
 		renderPanel("Actions", "⚡", 1, actionsSection),
+		renderPanel("Multi-select", "☑", 3, selectSection),
 	}
 
 	// Arrange panels into columns
@@ -4940,7 +5531,7 @@ func (m *Model) renderHelpOverlay() string {
 		Italic(true)
 
 	title := titleStyle.Render("⌨️  Keyboard Shortcuts")
-	subtitle := subtitleStyle.Render("Space: Tutorial │ ? or Esc to close")
+	subtitle := subtitleStyle.Render("Space: Tutorial │ " + m.keymap.ShortLabel(ActionCommandPalette) + " Commands │ Esc to close")
 	titleBar := lipgloss.JoinHorizontal(lipgloss.Center, title, "  ", subtitle)
 
 	// Combine title and body
@@ -5534,6 +6125,17 @@ func (m *Model) renderFooter() string {
 			Render(fmt.Sprintf("↕ %s", m.sortMode.String()))
 	}
 
+	// Marked-issue count for batch actions
+	marksBadge := ""
+	if n := m.selection.Len(); n > 0 {
+		marksBadge = lipgloss.NewStyle().
+			Background(ColorBgHighlight).
+			Foreground(ColorPrimary).
+			Bold(true).
+			Padding(0, 1).
+			Render(fmt.Sprintf("✓ %d marked", n))
+	}
+
 	labelHint := lipgloss.NewStyle().
 		Foreground(ColorFooterHint).
 		Padding(0, 1).
@@ -5904,6 +6506,8 @@ func (m *Model) renderFooter() string {
 	var keyHints []string
 	if m.showHelp {
 		keyHints = append(keyHints, "Press any key to close")
+	} else if m.showCommandPalette {
+		keyHints = append(keyHints, "type to filter", keyStyle.Render("↑/↓")+" nav", keyStyle.Render("⏎")+" run", keyStyle.Render("esc")+" cancel")
 	} else if m.showRecipePicker {
 		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("⏎")+" apply", keyStyle.Render("esc")+" cancel")
 	} else if m.showRepoPicker {
@@ -5913,6 +6517,12 @@ func (m *Model) renderFooter() string {
 	} else if m.focused == focusInsights {
 		keyHints = append(keyHints, keyStyle.Render("h/l")+" panels", keyStyle.Render("e")+" explain", keyStyle.Render("⏎")+" jump", keyStyle.Render("?")+" help")
 		keyHints = append(keyHints, keyStyle.Render("A")+" attention", keyStyle.Render("F")+" flow")
+	} else if m.focused == focusFlowMetrics {
+		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("tab")+" slice", keyStyle.Render("⏎")+" filter", keyStyle.Render("esc")+" back")
+	} else if m.focused == focusTimeline {
+		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("⏎")+" open", keyStyle.Render("esc")+" back")
+	} else if m.focused == focusForecast {
+		keyHints = append(keyHints, keyStyle.Render("tab")+" scope", keyStyle.Render("+/-")+" agents", keyStyle.Render("esc")+" back")
 	} else if m.focused == focusFlowMatrix {
 		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("tab")+" panel", keyStyle.Render("⏎")+" drill", keyStyle.Render("esc")+" back", keyStyle.Render("f")+" close")
 	} else if m.isGraphView {
@@ -5938,18 +6548,32 @@ func (m *Model) renderFooter() string {
 	} else if m.showTimeTravelPrompt {
 		keyHints = append(keyHints, keyStyle.Render("⏎")+" compare", keyStyle.Render("esc")+" cancel")
 	} else {
+		// Hints for remappable actions follow the keymap and drop out when unbound
+		hint := func(a Action, desc string) string {
+			if k := m.keymap.ShortLabel(a); k != "" {
+				return keyStyle.Render(k) + " " + desc
+			}
+			return ""
+		}
 		if m.timeTravelMode {
-			keyHints = append(keyHints, keyStyle.Render("t")+" exit diff", keyStyle.Render("C")+" copy", keyStyle.Render("abgi")+" views", keyStyle.Render("?")+" help")
+			keyHints = append(keyHints, hint(ActionTimeTravel, "exit diff"), hint(ActionCopyIssue, "copy"), keyStyle.Render("abgi")+" views", hint(ActionHelp, "help"))
 		} else if m.isSplitView {
-			keyHints = append(keyHints, keyStyle.Render("tab")+" focus", keyStyle.Render("C")+" copy", keyStyle.Render("x")+" export", keyStyle.Render("Ctrl+R")+" refresh", keyStyle.Render("?")+" help")
+			keyHints = append(keyHints, hint(ActionToggleFocus, "focus"), hint(ActionCopyIssue, "copy"), hint(ActionExportMarkdown, "export"), hint(ActionRefresh, "refresh"), hint(ActionHelp, "help"))
 		} else if m.showDetails {
-			keyHints = append(keyHints, keyStyle.Render("esc")+" back", keyStyle.Render("C")+" copy", keyStyle.Render("O")+" edit", keyStyle.Render("Ctrl+R")+" refresh", keyStyle.Render("?")+" help")
+			keyHints = append(keyHints, hint(ActionBack, "back"), hint(ActionCopyIssue, "copy"), hint(ActionOpenEditor, "edit"), hint(ActionRefresh, "refresh"), hint(ActionHelp, "help"))
 		} else {
-			keyHints = append(keyHints, keyStyle.Render("⏎")+" details", keyStyle.Render("t")+" diff", keyStyle.Render("S")+" triage", keyStyle.Render("l")+" labels", keyStyle.Render("Ctrl+R")+" refresh", keyStyle.Render("?")+" help")
+			keyHints = append(keyHints, keyStyle.Render("⏎")+" details", hint(ActionTimeTravel, "diff"), hint(ActionTriageSort, "triage"), hint(ActionLabelPicker, "labels"), hint(ActionRefresh, "refresh"), hint(ActionHelp, "help"))
 			if m.workspaceMode {
-				keyHints = append(keyHints, keyStyle.Render("w")+" repos")
+				keyHints = append(keyHints, hint(ActionRepoPicker, "repos"))
+			}
+		}
+		bound := keyHints[:0]
+		for _, h := range keyHints {
+			if h != "" {
+				bound = append(bound, h)
 			}
 		}
+		keyHints = bound
 	}
 
 	keysSection := lipgloss.NewStyle().
@@ -5984,6 +6608,9 @@ func (m *Model) renderFooter() string {
 	if sortBadge != "" {
 		leftWidth += lipgloss.Width(sortBadge) + 1
 	}
+	if marksBadge != "" {
+		leftWidth += lipgloss.Width(marksBadge) + 1
+	}
 	if alertsSection != "" {
 		leftWidth += lipgloss.Width(alertsSection) + 1
 	}
@@ -6022,6 +6649,9 @@ func (m *Model) renderFooter() string {
 	if sortBadge != "" {
 		parts = append(parts, sortBadge)
 	}
+	if marksBadge != "" {
+		parts = append(parts, marksBadge)
+	}
 	parts = append(parts, labelHint)
 	if alertsSection != "" {
 		parts = append(parts, alertsSection)
@@ -6284,6 +6914,58 @@ func (m *Model) applyFilter() {
 	m.updateViewportContent()
 }
 
+// refreshTriage recomputes triage scores, reasons, top picks and the
+// priority radar from the current analyzer and its scoring profile.
+func (m *Model) refreshTriage() {
+	// Reuse existing analyzer/stats (bv-runn.12)
+	triage := analysis.ComputeTriageFromAnalyzer(m.analyzer, m.analysis, m.issues, analysis.TriageOptions{}, time.Now())
+	triageScores := make(map[string]float64, len(triage.Recommendations))
+	triageReasons := make(map[string]analysis.TriageReasons, len(triage.Recommendations))
+	quickWinSet := make(map[string]bool, len(triage.QuickWins))
+	blockerSet := make(map[string]bool, len(triage.BlockersToClear))
+	unblocksMap := make(map[string][]string, len(triage.Recommendations))
+
+	for _, rec := range triage.Recommendations {
+		triageScores[rec.ID] = rec.Score
+		if len(rec.Reasons) > 0 {
+			triageReasons[rec.ID] = analysis.TriageReasons{
+				Primary:    rec.Reasons[0],
+				All:        rec.Reasons,
+				ActionHint: rec.Action,
+			}
+		}
+		unblocksMap[rec.ID] = rec.UnblocksIDs
+	}
+	for _, qw := range triage.QuickWins {
+		quickWinSet[qw.ID] = true
+	}
+	for _, bl := range triage.BlockersToClear {
+		blockerSet[bl.ID] = true
+	}
+
+	m.triageScores = triageScores
+	m.triageReasons = triageReasons
+	m.quickWinSet = quickWinSet
+	m.blockerSet = blockerSet
+	m.unblocksMap = unblocksMap
+
+	m.insightsPanel.SetTopPicks(triage.QuickRef.TopPicks)
+
+	// Set full recommendations with breakdown for priority radar (bv-93)
+	dataHash := fmt.Sprintf("v%s@%s#%d", triage.Meta.Version, triage.Meta.GeneratedAt.Format("15:04:05"), triage.Meta.IssueCount)
+	m.insightsPanel.SetRecommendations(triage.Recommendations, dataHash)
+}
+
+// refreshPriorityHints regenerates the priority hints overlay. It runs the
+// full analysis, so callers wait for Phase 2 first.
+func (m *Model) refreshPriorityHints() {
+	recommendations := m.analyzer.GenerateRecommendations()
+	m.priorityHints = make(map[string]*analysis.PriorityRecommendation, len(recommendations))
+	for i := range recommendations {
+		m.priorityHints[recommendations[i].IssueID] = &recommendations[i]
+	}
+}
+
 // refreshListItemsPhase2 updates visible items with Phase 2 scores and triage data
 // without rebuilding the filtered set.
 func (m *Model) refreshListItemsPhase2() {
@@ -6318,6 +7000,9 @@ func (m *Model) refreshListItemsPhase2() {
 	}
 
 	m.list.SetItems(items)
+	if m.queryFilter != nil {
+		m.queryFilter.SetItems(items, m.issues)
+	}
 	if selectedIdx >= 0 && selectedIdx < len(items) {
 		m.list.Select(selectedIdx)
 	}
@@ -6477,6 +7162,11 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 			include = !isBlocked
 		}
 
+		// Apply custom field filter
+		if include && !r.Filters.MatchCustomFields(issue.CustomFields) {
+			include = false
+		}
+
 		if include {
 			item := IssueItem{
 				Issue:      issue,
@@ -6575,6 +7265,9 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 					return 0
 				}
 			default:
+				if name, ok := recipe.CustomSortField(field); ok {
+					return recipe.CompareCustomField(&a, &b, name, descending)
+				}
 				switch {
 				case a.Priority < b.Priority:
 					return -1
@@ -6703,6 +7396,9 @@ func (m *Model) updateViewportContent() {
 		sb.WriteString(fmt.Sprintf("**Labels:** %s\n\n", strings.Join(item.Labels, ", ")))
 	}
 
+	// Custom fields (extra JSONL keys such as story_points or sla_tier)
+	sb.WriteString(customFieldsMarkdown(item.CustomFields))
+
 	// Triage Insights (bv-151)
 	if issueItem.TriageScore > 0 || issueItem.TriageReason != "" || issueItem.UnblocksCount > 0 || issueItem.IsQuickWin || issueItem.IsBlocker {
 		sb.WriteString("### 🎯 Triage Insights\n")
@@ -6762,6 +7458,12 @@ func (m *Model) updateViewportContent() {
 		sb.WriteString("\n")
 	}
 
+	// Where the "/" filter text matched, when not just the title
+	if match, ok := m.searchMatch(item.ID); ok && match.Field != search.ChunkFieldTitle {
+		sb.WriteString("### 🔎 Search Match\n")
+		sb.WriteString(fmt.Sprintf("*%s:* %s\n\n", searchMatchSource(match), search.HighlightSnippet(match.Snippet, match.Highlights, "**", "**")))
+	}
+
 	// Graph Analysis (using thread-safe accessors)
 	pr := m.analysis.GetPageRankScore(item.ID)
 	bt := m.analysis.GetBetweennessScore(item.ID)
@@ -7180,6 +7882,24 @@ func (m Model) FocusState() string {
 		return "cass_modal"
 	case focusUpdateModal:
 		return "update_modal"
+	case focusMutationModal:
+		return "mutation_modal"
+	case focusMergeModal:
+		return "merge_modal"
+	case focusFlowMetrics:
+		return "flow_metrics"
+	case focusTimeline:
+		return "timeline"
+	case focusForecast:
+		return "forecast"
+	case focusCommandPalette:
+		return "command_palette"
+	case focusBatchModal:
+		return "batch_modal"
+	case focusSessionPicker:
+		return "session_picker"
+	case focusThemePicker:
+		return "theme_picker"
 	default:
 		return "unknown"
 	}
@@ -7224,6 +7944,12 @@ func (m *Model) exportToMarkdown() {
 
 // generateExportFilename creates a smart filename based on project and date
 func (m *Model) generateExportFilename() string {
+	return exportFilename("report", "md")
+}
+
+// exportFilename returns beads_<kind>_<project>_YYYY-MM-DD.<ext>, with the
+// project name taken from the current directory.
+func exportFilename(kind, ext string) string {
 	// Get project name from current directory
 	projectName := "beads"
 	if cwd, err := os.Getwd(); err == nil {
@@ -7237,9 +7963,8 @@ func (m *Model) generateExportFilename() string {
 		}, projectName)
 	}
 
-	// Format: beads_report_<project>_YYYY-MM-DD.md
 	timestamp := time.Now().Format("2006-01-02")
-	return fmt.Sprintf("beads_report_%s_%s.md", projectName, timestamp)
+	return fmt.Sprintf("beads_%s_%s_%s.%s", kind, projectName, timestamp, ext)
 }
 
 // renderTimeTravelPrompt renders the time-travel revision input overlay
@@ -7306,7 +8031,19 @@ func (m *Model) copyIssueToClipboard() {
 	}
 	issue := issueItem.Issue
 
-	// Format issue as Markdown
+	err := clipboard.WriteAll(issueClipboardMarkdown(issue))
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("❌ Clipboard error: %v", err)
+		m.statusIsError = true
+		return
+	}
+
+	m.statusMsg = fmt.Sprintf("📋 Copied %s to clipboard", issue.ID)
+	m.statusIsError = false
+}
+
+// issueClipboardMarkdown formats one issue the way C copies it.
+func issueClipboardMarkdown(issue model.Issue) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("# %s %s\n\n", GetTypeIconMD(string(issue.IssueType)), issue.Title))
@@ -7321,6 +8058,9 @@ func (m *Model) copyIssueToClipboard() {
 	if len(issue.Labels) > 0 {
 		sb.WriteString(fmt.Sprintf("**Labels:** %s  \n", strings.Join(issue.Labels, ", ")))
 	}
+	if len(issue.CustomFields) > 0 {
+		sb.WriteString("\n" + customFieldsMarkdown(issue.CustomFields))
+	}
 
 	if issue.Description != "" {
 		sb.WriteString(fmt.Sprintf("\n## Description\n\n%s\n", issue.Description))
@@ -7340,17 +8080,7 @@ func (m *Model) copyIssueToClipboard() {
 			sb.WriteString(fmt.Sprintf("- %s (%s)\n", dep.DependsOnID, dep.Type))
 		}
 	}
-
-	// Copy to clipboard
-	err := clipboard.WriteAll(sb.String())
-	if err != nil {
-		m.statusMsg = fmt.Sprintf("❌ Clipboard error: %v", err)
-		m.statusIsError = true
-		return
-	}
-
-	m.statusMsg = fmt.Sprintf("📋 Copied %s to clipboard", issue.ID)
-	m.statusIsError = false
+	return sb.String()
 }
 
 // showCassSessionModal shows the cass session preview modal for the selected issue (bv-5bqh)
@@ -7401,6 +8131,269 @@ func (m *Model) showCassSessionModal() {
 	m.focused = focusCassModal
 }
 
+// canMarkIssues reports whether the focused view supports batch marks: the
+// issue list, the board (outside its search prompt) and the tree.
+func (m Model) canMarkIssues() bool {
+	switch m.focused {
+	case focusList, focusTree:
+		return true
+	case focusBoard:
+		return !m.board.IsSearchMode()
+	}
+	return false
+}
+
+// cursorIssueID returns the ID of the issue under the cursor in the focused
+// view, or "".
+func (m Model) cursorIssueID() string {
+	switch m.focused {
+	case focusBoard:
+		if issue := m.board.SelectedIssue(); issue != nil {
+			return issue.ID
+		}
+	case focusTree:
+		if issue := m.tree.SelectedIssue(); issue != nil {
+			return issue.ID
+		}
+	default:
+		if item, ok := m.list.SelectedItem().(IssueItem); ok {
+			return item.Issue.ID
+		}
+	}
+	return ""
+}
+
+// moveMarkCursor moves the focused view's cursor by one row.
+func (m *Model) moveMarkCursor(down bool) {
+	switch m.focused {
+	case focusBoard:
+		if down {
+			m.board.MoveDown()
+		} else {
+			m.board.MoveUp()
+		}
+	case focusTree:
+		if down {
+			m.tree.MoveDown()
+		} else {
+			m.tree.MoveUp()
+		}
+	default:
+		if down {
+			m.list.CursorDown()
+		} else {
+			m.list.CursorUp()
+		}
+		if m.isSplitView {
+			m.updateViewportContent()
+		}
+	}
+}
+
+// markableIssueIDs returns every issue shown in the focused view: all board
+// columns, the whole tree, or the list after filtering.
+func (m Model) markableIssueIDs() []string {
+	switch m.focused {
+	case focusBoard:
+		return m.board.VisibleIssueIDs()
+	case focusTree:
+		return m.tree.AllIssueIDs()
+	}
+	var ids []string
+	for _, item := range m.list.VisibleItems() {
+		if issueItem, ok := item.(IssueItem); ok {
+			ids = append(ids, issueItem.Issue.ID)
+		}
+	}
+	return ids
+}
+
+// runSelectionAction marks or unmarks issues, or opens the batch modal.
+func (m *Model) runSelectionAction(action Action) {
+	switch action {
+	case ActionToggleSelect:
+		if id := m.cursorIssueID(); id != "" {
+			m.selection.Toggle(id)
+		}
+	case ActionSelectDown, ActionSelectUp:
+		// Shift-range: both the row the cursor leaves and the one it lands
+		// on end up marked
+		m.selection.Add(m.cursorIssueID())
+		m.moveMarkCursor(action == ActionSelectDown)
+		m.selection.Add(m.cursorIssueID())
+	case ActionSelectAll:
+		// Marks everything shown, or unmarks it if it was all marked already
+		ids := m.markableIssueIDs()
+		if m.selection.ContainsAll(ids) {
+			m.selection.Remove(ids...)
+		} else {
+			m.selection.Add(ids...)
+		}
+		m.statusMsg = fmt.Sprintf("%d issues marked", m.selection.Len())
+		m.statusIsError = false
+	case ActionClearSelection:
+		m.selection.Clear()
+	case ActionBatch:
+		m.openBatchModal()
+	}
+}
+
+// markedIssues returns the marked issues that still exist, in data order.
+func (m Model) markedIssues() []model.Issue {
+	return m.selection.Filter(m.issues)
+}
+
+// openBatchModal shows the batch operations for the marked issues.
+func (m *Model) openBatchModal() {
+	marked := m.markedIssues()
+	if len(marked) == 0 {
+		m.statusMsg = "No issues marked"
+		if key := m.keymap.ShortLabel(ActionToggleSelect); key != "" {
+			m.statusMsg += " (" + key + " marks the issue under the cursor)"
+		}
+		m.statusIsError = true
+		return
+	}
+	m.batchModal = NewBatchModal(len(marked), m.theme)
+	m.batchReturn = m.focused
+	m.showBatchModal = true
+	m.focused = focusBatchModal
+}
+
+// runBatchOp applies op to the marked issues: clipboard operations copy a
+// single combined document, the others write a file to the working
+// directory named like the Markdown export.
+func (m *Model) runBatchOp(op batchOp) {
+	if op == batchNone {
+		return
+	}
+	issues := m.markedIssues()
+	if len(issues) == 0 {
+		m.statusMsg = "No issues marked"
+		m.statusIsError = true
+		return
+	}
+
+	var filename, copied string
+	var err error
+	switch op {
+	case batchCopyMarkdown:
+		parts := make([]string, len(issues))
+		for i, issue := range issues {
+			parts[i] = issueClipboardMarkdown(issue)
+		}
+		copied = fmt.Sprintf("%d issues", len(issues))
+		err = clipboard.WriteAll(strings.Join(parts, "\n---\n\n"))
+	case batchAgentPrompt:
+		copied = fmt.Sprintf("agent prompt for %d issues", len(issues))
+		err = clipboard.WriteAll(export.GenerateAgentPrompt(issues, m.issues))
+	case batchExportMarkdown:
+		filename = exportFilename("selection", "md")
+		err = export.SaveMarkdownToFile(issues, filename)
+	case batchExportJSON:
+		filename = exportFilename("selection", "json")
+		err = export.SaveJSONToFile(issues, filename)
+	case batchExportGraph:
+		filename = exportFilename("selection", "dot")
+		var res *export.GraphExportResult
+		res, err = export.ExportGraph(issues, nil, export.GraphExportConfig{Format: export.GraphFormatDOT})
+		if err == nil {
+			err = os.WriteFile(filename, []byte(res.Graph), 0644)
+		}
+	case batchClaimScript, batchCloseScript:
+		scriptOp := export.BatchScriptClaim
+		if op == batchCloseScript {
+			scriptOp = export.BatchScriptClose
+		}
+		filename = exportFilename(string(scriptOp), "sh")
+		err = os.WriteFile(filename, []byte(export.GenerateBatchScript(issues, scriptOp)), 0755)
+	}
+
+	switch {
+	case err != nil && filename != "":
+		m.statusMsg = fmt.Sprintf("❌ Export failed: %v", err)
+		m.statusIsError = true
+	case err != nil:
+		m.statusMsg = fmt.Sprintf("❌ Clipboard error: %v", err)
+		m.statusIsError = true
+	case filename != "":
+		m.statusMsg = fmt.Sprintf("✅ Wrote %d issues to %s", len(issues), filename)
+		m.statusIsError = false
+	default:
+		m.statusMsg = fmt.Sprintf("📋 Copied %s to clipboard", copied)
+		m.statusIsError = false
+	}
+}
+
+// openMutationModal shows write-back actions for the selected issue.
+// Mutations are disabled where there is no single beads file to write to
+// (workspace mode) or the data shown is historical (time-travel).
+func (m *Model) openMutationModal() {
+	switch {
+	case m.timeTravelMode:
+		m.statusMsg = "Cannot modify issues while time-traveling"
+		m.statusIsError = true
+		return
+	case m.workspaceMode || m.beadsPath == "":
+		m.statusMsg = "Issue updates are only available for a single local .beads directory"
+		m.statusIsError = true
+		return
+	}
+
+	var issue *model.Issue
+	if m.focused == focusBoard {
+		issue = m.board.SelectedIssue()
+	} else if item, ok := m.list.SelectedItem().(IssueItem); ok {
+		issue = &item.Issue
+	}
+	if issue == nil {
+		m.statusMsg = "❌ No issue selected"
+		m.statusIsError = true
+		return
+	}
+
+	m.mutationModal = NewMutationModal(*issue, filepath.Dir(m.beadsPath), m.theme)
+	m.mutationReturnFocus = m.focused
+	m.showMutationModal = true
+	m.focused = focusMutationModal
+}
+
+// openMergeModal shows the conflicts of a pending `bv merge`, if any.
+func (m *Model) openMergeModal() {
+	if m.workspaceMode || m.beadsPath == "" {
+		m.statusMsg = "Merge resolution is only available for a single local .beads directory"
+		m.statusIsError = true
+		return
+	}
+	beadsDir := filepath.Dir(m.beadsPath)
+	state, err := datasource.LoadMergeState(beadsDir)
+	switch {
+	case err != nil:
+		m.statusMsg = fmt.Sprintf("Reading merge state: %v", err)
+		m.statusIsError = true
+		return
+	case state == nil:
+		m.statusMsg = "No pending merge (run bv merge <rev> or use bv as a git merge driver)"
+		m.statusIsError = false
+		return
+	}
+
+	m.mergeModal = NewMergeModal(state, beadsDir, m.theme)
+	m.mergeReturn = m.focused
+	m.showMergeModal = true
+	m.focused = focusMergeModal
+}
+
+// focusBeforeMutation returns the view the mutation modal was opened from.
+func (m Model) focusBeforeMutation() focus {
+	switch m.mutationReturnFocus {
+	case focusDetail, focusBoard:
+		return m.mutationReturnFocus
+	default:
+		return focusList
+	}
+}
+
 // showSelfUpdateModal shows the self-update modal (bv-182)
 func (m *Model) showSelfUpdateModal() {
 	// Check if an update is available
@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mutationInputKind identifies which free-text value the modal is collecting.
+type mutationInputKind int
+
+const (
+	mutationInputNone mutationInputKind = iota
+	mutationInputAssignee
+	mutationInputAddLabel
+	mutationInputRemoveLabel
+	mutationInputDependsOn
+	mutationInputUnlink
+)
+
+func (k mutationInputKind) prompt() string {
+	switch k {
+	case mutationInputAssignee:
+		return "Assignee (empty to unassign):"
+	case mutationInputAddLabel:
+		return "Add labels (comma-separated):"
+	case mutationInputRemoveLabel:
+		return "Remove labels (comma-separated):"
+	case mutationInputDependsOn:
+		return "Blocked by issue ID:"
+	case mutationInputUnlink:
+		return "Remove dependency on issue ID:"
+	default:
+		return ""
+	}
+}
+
+// MutationResultMsg is sent when a write-back started from the mutation
+// modal finishes.
+type MutationResultMsg struct {
+	IssueID string
+	Action  string
+	Result  *datasource.MutationResult
+	Err     error
+}
+
+// ApplyMutationCmd writes m to the beads data in beadsDir in the background.
+func ApplyMutationCmd(beadsDir, action string, m datasource.Mutation) tea.Cmd {
+	return func() tea.Msg {
+		res, err := datasource.ApplyMutation(beadsDir, m, datasource.MutationOptions{})
+		return MutationResultMsg{IssueID: m.IssueID, Action: action, Result: res, Err: err}
+	}
+}
+
+// MutationModal offers quick write-back actions (claim, close, reopen,
+// priority, labels, dependencies, assignee) for the selected issue.
+type MutationModal struct {
+	issue     model.Issue
+	beadsDir  string
+	actor     string
+	inputKind mutationInputKind
+	input     textinput.Model
+	pending   string // action being written; empty when idle
+	cancelled bool
+	theme     Theme
+	width     int
+}
+
+// NewMutationModal creates a modal for issue, writing to beadsDir.
+func NewMutationModal(issue model.Issue, beadsDir string, theme Theme) MutationModal {
+	ti := textinput.New()
+	ti.CharLimit = 200
+	ti.Width = 40
+	return MutationModal{
+		issue:    issue,
+		beadsDir: beadsDir,
+		actor:    datasource.DefaultActor(),
+		input:    ti,
+		theme:    theme,
+		width:    56,
+	}
+}
+
+// IsCancelled reports whether the user dismissed the modal.
+func (m MutationModal) IsCancelled() bool { return m.cancelled }
+
+// IsPending reports whether a write is in flight.
+func (m MutationModal) IsPending() bool { return m.pending != "" }
+
+// IsEditing reports whether the modal is collecting text input.
+func (m MutationModal) IsEditing() bool { return m.inputKind != mutationInputNone }
+
+// Update handles key input. A non-nil command means a write was started.
+func (m MutationModal) Update(msg tea.Msg) (MutationModal, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || m.pending != "" {
+		return m, nil
+	}
+	if m.inputKind != mutationInputNone {
+		return m.updateInput(key)
+	}
+
+	id := m.issue.ID
+	switch s := key.String(); s {
+	case "esc", "q", "M":
+		m.cancelled = true
+	case "c":
+		return m.submit("claim", datasource.ClaimMutation(id, m.actor, false))
+	case "x":
+		return m.submit("close", datasource.CloseMutation(id, ""))
+	case "o":
+		open := model.StatusOpen
+		return m.submit("reopen", datasource.Mutation{IssueID: id, Status: &open})
+	case "0", "1", "2", "3", "4":
+		p, _ := strconv.Atoi(s)
+		return m.submit("priority", datasource.Mutation{IssueID: id, Priority: &p})
+	case "a":
+		m.startInput(mutationInputAssignee, m.issue.Assignee)
+	case "+":
+		m.startInput(mutationInputAddLabel, "")
+	case "-":
+		m.startInput(mutationInputRemoveLabel, "")
+	case "d":
+		m.startInput(mutationInputDependsOn, "")
+	case "D":
+		m.startInput(mutationInputUnlink, "")
+	}
+	return m, nil
+}
+
+func (m *MutationModal) startInput(kind mutationInputKind, value string) {
+	m.inputKind = kind
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+func (m MutationModal) updateInput(key tea.KeyMsg) (MutationModal, tea.Cmd) {
+	switch key.String() {
+	case "esc":
+		m.inputKind = mutationInputNone
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		kind := m.inputKind
+		value := strings.TrimSpace(m.input.Value())
+		m.inputKind = mutationInputNone
+		m.input.Blur()
+		mut := datasource.Mutation{IssueID: m.issue.ID}
+		switch kind {
+		case mutationInputAssignee:
+			mut.Assignee = &value
+			return m.submit("assign", mut)
+		case mutationInputAddLabel:
+			mut.AddLabels = splitCommaList(value)
+			return m.submit("label", mut)
+		case mutationInputRemoveLabel:
+			mut.RemoveLabels = splitCommaList(value)
+			return m.submit("unlabel", mut)
+		case mutationInputDependsOn:
+			for _, target := range splitCommaList(value) {
+				mut.AddDependencies = append(mut.AddDependencies, model.Dependency{
+					DependsOnID: target,
+					Type:        model.DepBlocks,
+					CreatedBy:   m.actor,
+				})
+			}
+			return m.submit("link", mut)
+		case mutationInputUnlink:
+			mut.RemoveDependencies = splitCommaList(value)
+			return m.submit("unlink", mut)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(key)
+	return m, cmd
+}
+
+func (m MutationModal) submit(action string, mut datasource.Mutation) (MutationModal, tea.Cmd) {
+	m.pending = action
+	return m, ApplyMutationCmd(m.beadsDir, action, mut)
+}
+
+func splitCommaList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// View renders the modal.
+func (m MutationModal) View() string {
+	r := m.theme.Renderer
+
+	modalStyle := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(1, 2).
+		Width(m.width)
+	headerStyle := r.NewStyle().Bold(true).Foreground(m.theme.Primary)
+	subStyle := r.NewStyle().Foreground(m.theme.Subtext)
+	keyStyle := r.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+	footerStyle := r.NewStyle().Foreground(ColorFooterHint).Italic(true)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("✎ Update " + m.issue.ID))
+	b.WriteString("\n")
+	b.WriteString(subStyle.Render(truncateRunesHelper(m.issue.Title, m.width-6, "…")))
+	b.WriteString("\n")
+	state := fmt.Sprintf("%s · P%d", m.issue.Status, m.issue.Priority)
+	if m.issue.Assignee != "" {
+		state += " · @" + m.issue.Assignee
+	}
+	b.WriteString(subStyle.Render(state))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.pending != "":
+		b.WriteString(subStyle.Render("Writing " + m.pending + "…"))
+	case m.inputKind != mutationInputNone:
+		b.WriteString(m.inputKind.prompt())
+		b.WriteString("\n")
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+		b.WriteString(footerStyle.Render("enter apply • esc back"))
+	default:
+		rows := [][2]string{
+			{"c", "claim (in_progress, @" + m.actor + ")"},
+			{"x", "close"},
+			{"o", "reopen"},
+			{"0-4", "set priority"},
+			{"a", "set assignee"},
+			{"+ / -", "add / remove labels"},
+			{"d / D", "add / remove blocking dependency"},
+		}
+		for _, row := range rows {
+			b.WriteString(keyStyle.Render(fmt.Sprintf("%-6s", row[0])))
+			b.WriteString(" ")
+			b.WriteString(row[1])
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render("esc cancel"))
+	}
+
+	return modalStyle.Render(b.String())
+}
+
+// CenterModal returns the modal centered in the given dimensions.
+func (m MutationModal) CenterModal(termWidth, termHeight int) string {
+	return lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, m.View())
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func mutationTestIssue() model.Issue {
+	return model.Issue{
+		ID:        "A-1",
+		Title:     "Test Issue",
+		Status:    model.StatusOpen,
+		Priority:  2,
+		IssueType: model.TypeTask,
+	}
+}
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestMutationModal_ClaimWritesBeadsFile(t *testing.T) {
+	t.Setenv("BV_ACTOR", "tester")
+	issue := mutationTestIssue()
+	beadsPath := writeTempBeadsFile(t, t.TempDir(), issue)
+
+	m := NewMutationModal(issue, filepath.Dir(beadsPath), newTestTheme())
+	if !strings.Contains(m.View(), "@tester") {
+		t.Errorf("view should show the claiming actor:\n%s", m.View())
+	}
+
+	m, cmd := m.Update(runeKey("c"))
+	if cmd == nil || !m.IsPending() {
+		t.Fatal("claim should start a write")
+	}
+	// Keys are ignored while the write is in flight.
+	if _, again := m.Update(runeKey("x")); again != nil {
+		t.Error("second action started while pending")
+	}
+
+	msg, ok := cmd().(MutationResultMsg)
+	if !ok {
+		t.Fatalf("unexpected message %T", cmd())
+	}
+	if msg.Err != nil {
+		t.Fatalf("claim failed: %v", msg.Err)
+	}
+	if msg.Result.After.Status != model.StatusInProgress || msg.Result.After.Assignee != "tester" {
+		t.Errorf("unexpected claim result: %+v", msg.Result.After)
+	}
+	data, err := os.ReadFile(beadsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"assignee":"tester"`) {
+		t.Errorf("claim not written: %s", data)
+	}
+}
+
+func TestMutationModal_LabelInput(t *testing.T) {
+	issue := mutationTestIssue()
+	beadsPath := writeTempBeadsFile(t, t.TempDir(), issue)
+	m := NewMutationModal(issue, filepath.Dir(beadsPath), newTestTheme())
+
+	m, _ = m.Update(runeKey("+"))
+	if !m.IsEditing() {
+		t.Fatal("+ should open label input")
+	}
+	// esc leaves the input without cancelling the modal
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.IsEditing() || m.IsCancelled() {
+		t.Fatal("esc in input should return to the action menu")
+	}
+
+	m, _ = m.Update(runeKey("+"))
+	m, _ = m.Update(runeKey("ui, backend"))
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should start a write")
+	}
+	msg := cmd().(MutationResultMsg)
+	if msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if strings.Join(msg.Result.After.Labels, ",") != "ui,backend" {
+		t.Errorf("unexpected labels: %v", msg.Result.After.Labels)
+	}
+
+	m = NewMutationModal(issue, filepath.Dir(beadsPath), newTestTheme())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.IsCancelled() {
+		t.Error("esc should cancel the modal")
+	}
+}
+
+func TestModel_MutationModalLifecycle(t *testing.T) {
+	issue := mutationTestIssue()
+	beadsPath := writeTempBeadsFile(t, t.TempDir(), issue)
+	m := NewModel([]model.Issue{issue}, nil, beadsPath)
+	defer m.Stop()
+
+	next, _ := m.Update(runeKey("M"))
+	m = next.(Model)
+	if m.FocusState() != "mutation_modal" || !m.showMutationModal {
+		t.Fatalf("M should open the mutation modal, focus=%s", m.FocusState())
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.showMutationModal || m.FocusState() != "list" {
+		t.Fatalf("esc should close the modal, focus=%s", m.FocusState())
+	}
+
+	next, _ = m.Update(MutationResultMsg{IssueID: "A-1", Action: "close", Err: os.ErrPermission})
+	m = next.(Model)
+	if !m.statusIsError || !strings.Contains(m.statusMsg, "close A-1 failed") {
+		t.Errorf("unexpected status: %q", m.statusMsg)
+	}
+
+	m.timeTravelMode = true
+	next, _ = m.Update(runeKey("M"))
+	m = next.(Model)
+	if m.showMutationModal {
+		t.Error("mutations should be disabled while time-traveling")
+	}
+}
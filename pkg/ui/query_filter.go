@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// QueryFilter adds the structured query syntax of --search (status:open
+// label:backend priority:<2 ...) to the "/" filter. Structured parts narrow
+// the list; the remaining free text is ranked by the wrapped fuzzy or
+// semantic FilterFunc.
+//
+// The list filters in a background command, so the issue snapshot is
+// guarded and replaced wholesale rather than mutated.
+type QueryFilter struct {
+	mu     sync.RWMutex
+	issues []model.Issue // Parallel to the list's filter targets
+	qc     *search.QueryContext
+	me     string
+}
+
+// NewQueryFilter creates a filter resolving assignee:me to the current actor.
+func NewQueryFilter() *QueryFilter {
+	return &QueryFilter{me: datasource.DefaultActor()}
+}
+
+// SetItems records the issues behind the list items, in list order. all is
+// the full issue set, used to resolve blocks: filters.
+func (q *QueryFilter) SetItems(items []list.Item, all []model.Issue) {
+	issues := make([]model.Issue, 0, len(items))
+	for _, it := range items {
+		if issueItem, ok := it.(IssueItem); ok {
+			issues = append(issues, issueItem.Issue)
+		}
+	}
+	qc := search.NewQueryContext(all, q.me, time.Time{})
+
+	q.mu.Lock()
+	q.issues = issues
+	q.qc = qc
+	q.mu.Unlock()
+}
+
+// Wrap returns a list.FilterFunc applying the structured parts of the term
+// and delegating free text to next. A nil QueryFilter returns next.
+func (q *QueryFilter) Wrap(next list.FilterFunc) list.FilterFunc {
+	if q == nil {
+		return next
+	}
+	return func(term string, targets []string) []list.Rank {
+		query, err := search.ParseQuery(term)
+		if err != nil || !query.HasConstraints() {
+			// Incomplete filters ("status:") are treated as plain text
+			// until they parse.
+			return next(term, targets)
+		}
+
+		q.mu.RLock()
+		issues, base := q.issues, q.qc
+		q.mu.RUnlock()
+		if len(issues) != len(targets) || base == nil {
+			return next(term, targets)
+		}
+
+		qc := *base
+		qc.Now = time.Now()
+		allowed := make([]bool, len(issues))
+		for i := range issues {
+			allowed[i] = query.Match(issues[i], &qc)
+		}
+
+		var ranks []list.Rank
+		if text := query.Text(); strings.TrimSpace(text) != "" {
+			for _, r := range next(text, targets) {
+				if r.Index < len(allowed) && allowed[r.Index] {
+					ranks = append(ranks, r)
+				}
+			}
+			return ranks
+		}
+		for i, ok := range allowed {
+			if ok {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+		return ranks
+	}
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func rankIDs(items []list.Item, ranks []list.Rank) []string {
+	ids := make([]string, 0, len(ranks))
+	for _, r := range ranks {
+		ids = append(ids, items[r.Index].(IssueItem).Issue.ID)
+	}
+	return ids
+}
+
+func TestQueryFilter(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Login timeout", Status: model.StatusOpen, Priority: 1, Labels: []string{"backend"}},
+		{ID: "bv-2", Title: "Login page copy", Status: model.StatusClosed, Priority: 2, Labels: []string{"frontend"}},
+		{ID: "bv-3", Title: "Metrics export", Status: model.StatusOpen, Priority: 0, Labels: []string{"backend"}},
+	}
+	items := make([]list.Item, len(issues))
+	targets := make([]string, len(issues))
+	for i, iss := range issues {
+		items[i] = IssueItem{Issue: iss}
+		targets[i] = items[i].FilterValue()
+	}
+
+	tests := []struct {
+		name     string
+		term     string
+		want     []string
+		wantSeen string // term the wrapped filter receives; "" if it is not called
+	}{
+		{name: "structured filters keep list order", term: "status:open label:backend", want: []string{"bv-1", "bv-3"}},
+		{name: "priority comparison", term: "priority:<1", want: []string{"bv-3"}},
+		{name: "filters combine with fuzzy text", term: "login -status:closed", want: []string{"bv-1"}, wantSeen: "login"},
+		{name: "plain text passes through", term: "login", want: []string{"bv-1", "bv-2"}, wantSeen: "login"},
+		{name: "an incomplete filter is plain text", term: "status:", want: []string{}, wantSeen: "status:"},
+		{name: "only the free text reaches the wrapped filter", term: "metrics status:open", want: []string{"bv-3"}, wantSeen: "metrics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qf := NewQueryFilter()
+			qf.SetItems(items, nil)
+			var seen string
+			filter := qf.Wrap(func(term string, targets []string) []list.Rank {
+				seen = term
+				return list.DefaultFilter(term, targets)
+			})
+
+			got := rankIDs(items, filter(tt.term, targets))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if seen != tt.wantSeen {
+				t.Errorf("wrapped filter saw %q, want %q", seen, tt.wantSeen)
+			}
+		})
+	}
+
+	var nilFilter *QueryFilter
+	if got := rankIDs(items, nilFilter.Wrap(list.DefaultFilter)("status:open", targets)); len(got) != 0 {
+		t.Errorf("nil QueryFilter should not apply filters, got %v", got)
+	}
+}
+
+func TestSearchMatchPointsAtComment(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Auth flakiness", Status: model.StatusOpen, Comments: []*model.Comment{
+			{Author: "carol", Text: "Could be the proxy"},
+			{Author: "dave", Text: "Root cause: the oauth refresh token expires early"},
+		}},
+		{ID: "bv-2", Title: "Oauth settings", Status: model.StatusOpen},
+	}
+	m := NewModel(issues, nil, "")
+
+	if _, ok := m.searchMatch("bv-1"); ok {
+		t.Fatal("no match expected while unfiltered")
+	}
+	m.list.SetFilterText("oauth status:open")
+
+	match, ok := m.searchMatch("bv-1")
+	if !ok || match.Field != "comment" || match.Comment != 2 || match.Author != "dave" {
+		t.Fatalf("expected dave's comment, got %+v (ok=%v)", match, ok)
+	}
+	if match, ok := m.searchMatch("bv-2"); !ok || match.Field != "title" {
+		t.Errorf("expected title match for bv-2, got %+v", match)
+	}
+}
+
+func TestSearchMatchShownInResultRow(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Flaky login", Status: model.StatusOpen, Assignee: "dave", Comments: []*model.Comment{
+			{Author: "carol", Text: "Handing this to dave, who owns the proxy"},
+		}},
+		{ID: "bv-2", Title: "Dave's dashboard", Status: model.StatusOpen},
+		{ID: "bv-3", Title: "Unrelated", Status: model.StatusOpen},
+	}
+	m := NewModel(issues, nil, "")
+	m.list.SetFilterText("dave")
+
+	view := m.list.View()
+	if !strings.Contains(view, "comment #1 by carol: Handing this to dave") {
+		t.Errorf("result row should show the matching comment:\n%s", view)
+	}
+	if strings.Count(view, " · ") != 1 {
+		t.Errorf("a title match should not repeat the title:\n%s", view)
+	}
+}
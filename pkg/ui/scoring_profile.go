@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+)
+
+// SelectScoringProfile switches triage scoring to a profile by name: a
+// built-in or one from .bv/scoring.yaml.
+func (m *Model) SelectScoringProfile(name string) error {
+	p, err := m.scoringProfiles.Get(name)
+	if err != nil {
+		return err
+	}
+	m.setScoringProfile(p)
+	return nil
+}
+
+// cycleScoringProfile moves to the next profile and reports it in the status bar.
+func (m *Model) cycleScoringProfile() {
+	p := m.scoringProfiles.Next(m.scoringProfile.Name)
+	m.setScoringProfile(p)
+	m.statusMsg = fmt.Sprintf("Scoring profile: %s", p.Name)
+	if p.Description != "" {
+		m.statusMsg += " • " + p.Description
+	}
+	m.statusIsError = false
+}
+
+// setScoringProfile rescores triage, list badges and priority hints with p.
+// The background worker rebuilds its snapshot so later reloads keep it.
+func (m *Model) setScoringProfile(p *analysis.ScoringProfile) {
+	m.scoringProfile = p
+	if m.backgroundWorker != nil {
+		m.backgroundWorker.SetScoringProfile(p)
+	}
+	if m.analyzer == nil || m.analysis == nil {
+		return
+	}
+	m.analyzer.SetScoringProfile(p)
+	m.refreshTriage()
+	if m.analysis.IsPhase2Ready() {
+		m.refreshPriorityHints()
+	}
+	m.refreshListItemsPhase2()
+}
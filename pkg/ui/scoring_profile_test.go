@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScoringProfileRescoresTriage(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "b", Title: "Crash", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeBug},
+		{ID: "f", Title: "Export", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeFeature},
+	}
+	m := NewModel(issues, nil, "")
+	if m.scoringProfile.Name != "default" || m.triageScores["b"] != m.triageScores["f"] {
+		t.Fatalf("default profile %s scores %v", m.scoringProfile.Name, m.triageScores)
+	}
+
+	if err := m.SelectScoringProfile("bug-burndown"); err != nil {
+		t.Fatalf("SelectScoringProfile: %v", err)
+	}
+	if m.triageScores["b"] <= m.triageScores["f"] {
+		t.Errorf("bug-burndown should favor the bug: %v", m.triageScores)
+	}
+	if err := m.SelectScoringProfile("nope"); err == nil {
+		t.Error("unknown profile should be an error")
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}, Alt: true})
+	if m.scoringProfile.Name != "unblock" || !strings.Contains(m.statusMsg, "Scoring profile: unblock") {
+		t.Errorf("alt+p: profile %s status %q", m.scoringProfile.Name, m.statusMsg)
+	}
+}
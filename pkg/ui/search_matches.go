@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+)
+
+// searchMatches finds the passage each issue matched for the "/" filter
+// text. Matches for every issue are computed in one pass when the text
+// changes, so list rows can show them cheaply. The model and the list
+// delegate share one instance, so rows and the detail pane agree.
+type searchMatches struct {
+	issues []model.Issue
+	index  *search.ChunkIndex // Built lazily on the first query
+	text   string
+	byID   map[string]search.ChunkMatch
+}
+
+func newSearchMatches(issues []model.Issue) *searchMatches {
+	return &searchMatches{issues: issues}
+}
+
+// reset points the matcher at a new issue set.
+func (s *searchMatches) reset(issues []model.Issue) {
+	if s == nil {
+		return
+	}
+	*s = searchMatches{issues: issues}
+}
+
+// match returns the passage of issueID that matches text, the free-text
+// part of the filter.
+func (s *searchMatches) match(text, issueID string) (search.ChunkMatch, bool) {
+	text = strings.TrimSpace(text)
+	if s == nil || text == "" {
+		return search.ChunkMatch{}, false
+	}
+	if s.index == nil {
+		s.index = search.NewChunkIndex(s.issues)
+	}
+	if s.byID == nil || s.text != text {
+		ids := make([]string, len(s.issues))
+		for i := range s.issues {
+			ids[i] = s.issues[i].ID
+		}
+		s.byID = s.index.BestMatches(text, ids)
+		s.text = text
+	}
+	m, ok := s.byID[issueID]
+	return m, ok
+}
+
+// searchMatchSource names where a match came from, e.g. "design" or
+// "comment #3 by alice".
+func searchMatchSource(m search.ChunkMatch) string {
+	if m.Field != search.ChunkFieldComment {
+		return strings.ReplaceAll(m.Field, "_", " ")
+	}
+	source := fmt.Sprintf("comment #%d", m.Comment)
+	if m.Author != "" {
+		source += " by " + m.Author
+	}
+	return source
+}
@@ -0,0 +1,83 @@
+package ui
+
+import "github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+// Selection is the set of issues marked for batch actions. The list, board
+// and tree share one *Selection, so marks survive view switches and
+// refiltering. A nil *Selection is empty.
+type Selection struct {
+	ids map[string]bool
+}
+
+// NewSelection returns an empty selection.
+func NewSelection() *Selection {
+	return &Selection{ids: make(map[string]bool)}
+}
+
+// Has reports whether id is marked.
+func (s *Selection) Has(id string) bool {
+	return s != nil && s.ids[id]
+}
+
+// Len returns the number of marked issues.
+func (s *Selection) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.ids)
+}
+
+// Toggle flips the mark on id and reports whether it is now marked.
+func (s *Selection) Toggle(id string) bool {
+	if s.ids[id] {
+		delete(s.ids, id)
+		return false
+	}
+	s.ids[id] = true
+	return true
+}
+
+// Add marks every id.
+func (s *Selection) Add(ids ...string) {
+	for _, id := range ids {
+		if id != "" {
+			s.ids[id] = true
+		}
+	}
+}
+
+// Remove unmarks every id.
+func (s *Selection) Remove(ids ...string) {
+	for _, id := range ids {
+		delete(s.ids, id)
+	}
+}
+
+// Clear unmarks everything.
+func (s *Selection) Clear() {
+	for id := range s.ids {
+		delete(s.ids, id)
+	}
+}
+
+// ContainsAll reports whether every id is marked.
+func (s *Selection) ContainsAll(ids []string) bool {
+	for _, id := range ids {
+		if !s.Has(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the marked issues in the order they appear in issues.
+// Marks on issues that no longer exist are ignored.
+func (s *Selection) Filter(issues []model.Issue) []model.Issue {
+	var out []model.Issue
+	for _, issue := range issues {
+		if s.Has(issue.ID) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func sendKeys(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+	return m
+}
+
+var (
+	keySpace     = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	keyShiftDown = tea.KeyMsg{Type: tea.KeyShiftDown}
+	keyCtrlA     = tea.KeyMsg{Type: tea.KeyCtrlA}
+	keyEsc       = tea.KeyMsg{Type: tea.KeyEsc}
+)
+
+func TestSelectionInList(t *testing.T) {
+	m := NewModel([]model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}, nil, "")
+	first := m.cursorIssueID()
+
+	steps := []struct {
+		name       string
+		keys       []tea.KeyMsg
+		wantMarks  int
+		wantMarked string
+		notMarked  string
+		wantIndex  int // -1 to skip
+		wantFooter string
+		wantFilter string
+	}{
+		{name: "space marks the cursor row", keys: []tea.KeyMsg{keySpace}, wantMarks: 1, wantMarked: first, wantIndex: 0, wantFilter: "all"},
+		{name: "second space unmarks", keys: []tea.KeyMsg{keySpace}, wantMarks: 0, wantIndex: 0, wantFilter: "all"},
+		{name: "shift+down marks the row left and the row reached", keys: []tea.KeyMsg{keyShiftDown, keyShiftDown}, wantMarks: 3, wantIndex: 2, wantFooter: "3 marked", wantFilter: "all"},
+		{name: "esc clears marks before it clears filters", keys: []tea.KeyMsg{runeKey("o"), keyEsc}, wantMarks: 0, wantIndex: -1, wantFilter: "open"},
+		{name: "ctrl+a marks what the filter shows", keys: []tea.KeyMsg{keyCtrlA}, wantMarks: 3, notMarked: "s-4", wantIndex: -1, wantFilter: "open"},
+		{name: "ctrl+a on a fully marked view unmarks", keys: []tea.KeyMsg{keyCtrlA}, wantMarks: 0, wantIndex: -1, wantFilter: "open"},
+	}
+	for _, step := range steps {
+		m = sendKeys(t, m, step.keys...)
+		if m.selection.Len() != step.wantMarks {
+			t.Fatalf("%s: marks %d, want %d", step.name, m.selection.Len(), step.wantMarks)
+		}
+		if (step.wantMarked != "" && !m.selection.Has(step.wantMarked)) || (step.notMarked != "" && m.selection.Has(step.notMarked)) {
+			t.Errorf("%s: wrong rows marked", step.name)
+		}
+		if step.wantIndex >= 0 && m.list.Index() != step.wantIndex {
+			t.Errorf("%s: index %d, want %d", step.name, m.list.Index(), step.wantIndex)
+		}
+		if !strings.Contains(m.renderFooter(), step.wantFooter) {
+			t.Errorf("%s: footer should show %q", step.name, step.wantFooter)
+		}
+		if m.currentFilter != step.wantFilter {
+			t.Errorf("%s: filter %q, want %q", step.name, m.currentFilter, step.wantFilter)
+		}
+	}
+}
+
+func TestSelectionSharedWithBoardAndTree(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	m := NewModel(issues, nil, "")
+	m.tree.SetSize(100, 20)
+
+	listRow := func(m Model) string {
+		m.list.Select(0)
+		var row strings.Builder
+		IssueDelegate{Theme: m.theme, Marks: m.selection}.Render(&row, m.list, 0, m.list.Items()[0])
+		return row.String()
+	}
+	steps := []struct {
+		name           string
+		keys           []tea.KeyMsg
+		wantFocus      string
+		wantMarks      int
+		cursorMarked   bool
+		render         func(Model) string
+		wantRenderMark bool
+	}{
+		{name: "space marks a board card", keys: []tea.KeyMsg{runeKey("b"), keySpace}, wantFocus: "board", wantMarks: 1, cursorMarked: true,
+			render: func(m Model) string { return m.board.View(120, 40) }, wantRenderMark: true},
+		{name: "ctrl+a on the board marks every card", keys: []tea.KeyMsg{keyCtrlA}, wantFocus: "board", wantMarks: len(issues), cursorMarked: true},
+		{name: "marks carry over to the list", keys: []tea.KeyMsg{runeKey("b")}, wantFocus: "list", wantMarks: len(issues), cursorMarked: true,
+			render: listRow, wantRenderMark: true},
+		{name: "space in the tree unmarks", keys: []tea.KeyMsg{runeKey("E"), keySpace}, wantFocus: "tree", wantMarks: len(issues) - 1,
+			render: func(m Model) string { return m.tree.View() }, wantRenderMark: true},
+	}
+	for _, step := range steps {
+		m = sendKeys(t, m, step.keys...)
+		if m.FocusState() != step.wantFocus {
+			t.Fatalf("%s: focus %s, want %s", step.name, m.FocusState(), step.wantFocus)
+		}
+		if m.selection.Len() != step.wantMarks || m.selection.Has(m.cursorIssueID()) != step.cursorMarked {
+			t.Errorf("%s: marks %d (cursor %s marked: %v)", step.name, m.selection.Len(), m.cursorIssueID(), m.selection.Has(m.cursorIssueID()))
+		}
+		if step.render != nil && strings.Contains(step.render(m), "✓") != step.wantRenderMark {
+			t.Errorf("%s: rendered marks should be badged", step.name)
+		}
+	}
+}
+
+func TestBatchActionsWriteFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m := NewModel([]model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}, nil, "")
+
+	m = sendKeys(t, m, runeKey("B"))
+	if m.showBatchModal || !strings.Contains(m.statusMsg, "No issues marked") {
+		t.Fatalf("batch with no marks should refuse, status %q", m.statusMsg)
+	}
+
+	m.selection.Add("s-2", "s-1", "s-4")
+	m = sendKeys(t, m, runeKey("B"))
+	if !m.showBatchModal || m.FocusState() != "batch_modal" {
+		t.Fatalf("B should open the batch modal, focus %s", m.FocusState())
+	}
+	if !strings.Contains(m.View(), "3 issues marked") {
+		t.Error("modal should show the mark count")
+	}
+	m = sendKeys(t, m, keyEsc)
+
+	tests := []struct {
+		name       string
+		keys       []tea.KeyMsg
+		file       string
+		want       []string
+		notWant    string
+		wantIssues int
+	}{
+		{name: "json", keys: []tea.KeyMsg{runeKey("B"), runeKey("j")}, file: exportFilename("selection", "json"),
+			want: []string{`"id": "s-1"`, `"id": "s-2"`, `"id": "s-4"`}, notWant: `"id": "s-3"`, wantIssues: 3},
+		{name: "claim script", keys: []tea.KeyMsg{runeKey("B"), runeKey("c")}, file: exportFilename("claim", "sh"),
+			want: []string{"\"$BD\" update s-1 --status=in_progress\n"}, notWant: "update s-4"},
+		{name: "graph", keys: []tea.KeyMsg{runeKey("B"), runeKey("g")}, file: exportFilename("selection", "dot"),
+			want: []string{"digraph"}},
+		{name: "x exports just the marked issues", keys: []tea.KeyMsg{runeKey("x")}, file: exportFilename("selection", "md"),
+			want: []string{"s-2"}, notWant: "s-3"},
+	}
+	for _, tt := range tests {
+		m = sendKeys(t, m, tt.keys...)
+		if m.showBatchModal || m.FocusState() != "list" {
+			t.Fatalf("%s: the modal should close, focus %s", tt.name, m.FocusState())
+		}
+		if !strings.Contains(m.statusMsg, tt.file) {
+			t.Errorf("%s: status %q should name %s", tt.name, m.statusMsg, tt.file)
+		}
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if tt.wantIssues > 0 {
+			var exported []model.Issue
+			if err := json.Unmarshal(data, &exported); err != nil || len(exported) != tt.wantIssues {
+				t.Fatalf("%s: exported %d issues, err %v", tt.name, len(exported), err)
+			}
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s: %s missing %q", tt.name, tt.file, want)
+			}
+		}
+		if tt.notWant != "" && strings.Contains(string(data), tt.notWant) {
+			t.Errorf("%s: %s should not contain %q", tt.name, tt.file, tt.notWant)
+		}
+	}
+	if entries, _ := filepath.Glob("beads_report_*"); len(entries) != 0 {
+		t.Errorf("x should not export everything while issues are marked: %v", entries)
+	}
+
+	m = sendKeys(t, m, runeKey("B"), keyEsc)
+	if m.showBatchModal || m.selection.Len() != 3 {
+		t.Error("esc should close the modal and keep the marks")
+	}
+}
@@ -22,6 +22,7 @@ type semanticSearchSnapshot struct {
 	Embedder search.Embedder
 	IDs      []string
 	Docs     map[string]string
+	Lexical  *search.BM25Index // BM25 over the same issues; nil falls back to the short-query boost
 }
 
 // semanticResultCache holds cached filter results and pending state
@@ -252,6 +253,14 @@ func (s *SemanticSearch) SetDocs(docs map[string]string) {
 	s.snapshot.Store(snap)
 }
 
+// SetLexicalIndex sets the BM25 index whose scores are blended into the
+// text component.
+func (s *SemanticSearch) SetLexicalIndex(idx *search.BM25Index) {
+	snap := s.Snapshot()
+	snap.Lexical = idx
+	s.snapshot.Store(snap)
+}
+
 // Filter implements list.FilterFunc, returning ranks sorted by semantic similarity.
 // This is non-blocking: returns cached results or fuzzy fallback immediately,
 // and marks the term as pending for async computation.
@@ -323,6 +332,15 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 		hasVector bool
 	}
 
+	var lexical map[string]float64
+	if snap.Lexical != nil {
+		hits := snap.Lexical.Search(term, 0, nil)
+		lexical = make(map[string]float64, len(hits))
+		for _, h := range hits {
+			lexical[h.IssueID] = h.Score
+		}
+	}
+
 	scoredItems := make([]scored, len(snap.IDs))
 	scoreMap := make(map[string]SemanticScore, len(snap.IDs))
 	for i, id := range snap.IDs {
@@ -336,7 +354,9 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 			textScore = score
 		} else {
 			textScore = dotFloat32(q, entry.Vector)
-			if doc, ok := snap.Docs[id]; ok {
+			if lexical != nil {
+				textScore = search.BlendTextScore(lexical[id], textScore)
+			} else if doc, ok := snap.Docs[id]; ok {
 				textScore += search.ShortQueryLexicalBoost(term, doc)
 			}
 			score = textScore
@@ -504,7 +524,7 @@ func BuildSemanticIndexCmd(issues []model.Issue) tea.Cmd {
 		if err != nil {
 			return SemanticIndexReadyMsg{Error: err}
 		}
-		if !loaded || stats.Changed() {
+		if !loaded || stats.Changed() || idx.NeedsANNBuild() {
 			if err := idx.Save(indexPath); err != nil {
 				return SemanticIndexReadyMsg{Error: fmt.Errorf("save semantic index: %w", err)}
 			}
@@ -0,0 +1,550 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SessionState is the view state saved for a TUI session: which view is
+// open, how the list is filtered and sorted, where the cursor is, and the
+// layout. Issue data is never stored, only references to it.
+type SessionState struct {
+	View         string    `json:"view"`                    // list, detail, board, graph, tree, insights, ...
+	Recipe       string    `json:"recipe,omitempty"`        // Active recipe name (takes precedence over Filter)
+	Filter       string    `json:"filter,omitempty"`        // all, open, closed, ready or label:<name>
+	Search       string    `json:"search,omitempty"`        // Text in the "/" filter
+	Sort         string    `json:"sort,omitempty"`          // Sort mode key, see sortModeKeys
+	SelectedID   string    `json:"selected_id,omitempty"`   // Issue under the cursor
+	SwimLane     string    `json:"swim_lane,omitempty"`     // Board grouping: status, priority or type
+	SplitRatio   float64   `json:"split_ratio,omitempty"`   // List pane share in split view
+	DetailScroll int       `json:"detail_scroll,omitempty"` // Detail pane scroll offset
+	BoardDetail  bool      `json:"board_detail,omitempty"`  // Board detail panel open
+	SavedAt      time.Time `json:"saved_at"`
+}
+
+// SessionFile holds every named session for a project. It is saved to
+// .beads/sessions.json next to tree-state.json.
+//
+// File format (JSON):
+//
+//	{
+//	  "version": 1,
+//	  "last": "triage",
+//	  "sessions": {
+//	    "default": {"view": "list", "filter": "open", ...},
+//	    "triage":  {"view": "board", "swim_lane": "priority", ...}
+//	  }
+//	}
+//
+// "last" is the session that was active when bv last exited; it is restored
+// on the next start.
+type SessionFile struct {
+	Version  int                     `json:"version"`
+	Last     string                  `json:"last,omitempty"`
+	Sessions map[string]SessionState `json:"sessions"`
+}
+
+// SessionFileVersion is the current schema version for session persistence
+const SessionFileVersion = 1
+
+// DefaultSessionName is the session used until the user names one.
+const DefaultSessionName = "default"
+
+// sessionFileName is the filename for persisted sessions
+const sessionFileName = "sessions.json"
+
+// SessionPath returns the path to the session file in beadsDir, which
+// defaults to .beads in the current directory.
+func SessionPath(beadsDir string) string {
+	if beadsDir == "" {
+		beadsDir = ".beads"
+	}
+	return filepath.Join(beadsDir, sessionFileName)
+}
+
+// LoadSessionFile reads the session file at path. A missing file yields an
+// empty SessionFile and no error.
+func LoadSessionFile(path string) (*SessionFile, error) {
+	f := &SessionFile{Version: SessionFileVersion, Sessions: make(map[string]SessionState)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return f, err
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return &SessionFile{Version: SessionFileVersion, Sessions: make(map[string]SessionState)},
+			fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	if f.Sessions == nil {
+		f.Sessions = make(map[string]SessionState)
+	}
+	return f, nil
+}
+
+// Save writes the session file to path, creating its directory if needed.
+// The file is written to a temp file and renamed into place, so a crash or
+// a second bv exiting at the same time never leaves it half written.
+func (f *SessionFile) Save(path string) error {
+	f.Version = SessionFileVersion
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, sessionFileName+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Names returns the session names, most recently saved first.
+func (f *SessionFile) Names() []string {
+	names := make([]string, 0, len(f.Sessions))
+	for name := range f.Sessions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ti, tj := f.Sessions[names[i]].SavedAt, f.Sessions[names[j]].SavedAt
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Summary describes a session in one line for the picker.
+func (s SessionState) Summary() string {
+	parts := []string{s.View}
+	switch {
+	case s.Recipe != "":
+		parts = append(parts, "recipe "+s.Recipe)
+	case s.Filter != "" && s.Filter != "all":
+		parts = append(parts, s.Filter)
+	}
+	if s.Search != "" {
+		parts = append(parts, fmt.Sprintf("%q", s.Search))
+	}
+	if s.Sort != "" && s.Sort != "default" {
+		parts = append(parts, "sort "+s.Sort)
+	}
+	if s.View == "board" && s.SwimLane != "" && s.SwimLane != "status" {
+		parts = append(parts, "by "+s.SwimLane)
+	}
+	if s.SelectedID != "" {
+		parts = append(parts, "@ "+s.SelectedID)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// sortModeKeys are the stable names SortMode values are saved under.
+var sortModeKeys = map[SortMode]string{
+	SortDefault:     "default",
+	SortCreatedAsc:  "created_asc",
+	SortCreatedDesc: "created_desc",
+	SortPriority:    "priority",
+	SortUpdated:     "updated",
+}
+
+func parseSortModeKey(key string) SortMode {
+	for mode, k := range sortModeKeys {
+		if k == key {
+			return mode
+		}
+	}
+	return SortDefault
+}
+
+// swimLaneKeys are the stable names SwimLaneMode values are saved under.
+var swimLaneKeys = map[SwimLaneMode]string{
+	SwimByStatus:   "status",
+	SwimByPriority: "priority",
+	SwimByType:     "type",
+}
+
+func parseSwimLaneKey(key string) SwimLaneMode {
+	for mode, k := range swimLaneKeys {
+		if k == key {
+			return mode
+		}
+	}
+	return SwimByStatus
+}
+
+// sessionViewActions maps saved view names to the action that opens them.
+// "list" and "detail" need no action.
+var sessionViewActions = map[string]Action{
+	"board":           ActionBoard,
+	"graph":           ActionGraph,
+	"tree":            ActionTree,
+	"insights":        ActionInsights,
+	"history":         ActionHistory,
+	"actionable":      ActionActionable,
+	"label_dashboard": ActionLabelDashboard,
+	"flow_matrix":     ActionFlowMatrix,
+	"flow_metrics":    ActionFlowMetrics,
+	"timeline":        ActionTimeline,
+}
+
+// sessionsEnabled reports whether this model persists sessions. Models
+// without a beads file (e.g. --as-of snapshots) do not.
+func (m Model) sessionsEnabled() bool {
+	return m.sessionPath != ""
+}
+
+// sessionView returns the name of the view under any session picker.
+func (m Model) sessionView() string {
+	f := m.focused
+	if m.showSessionPicker {
+		f = m.sessionReturn
+	}
+	switch {
+	case m.isBoardView:
+		return "board"
+	case m.isGraphView:
+		return "graph"
+	case m.isActionableView:
+		return "actionable"
+	case m.isHistoryView:
+		return "history"
+	}
+	switch f {
+	case focusTree:
+		return "tree"
+	case focusInsights:
+		return "insights"
+	case focusLabelDashboard:
+		return "label_dashboard"
+	case focusFlowMatrix:
+		return "flow_matrix"
+	case focusFlowMetrics:
+		return "flow_metrics"
+	case focusTimeline:
+		return "timeline"
+	case focusDetail:
+		return "detail"
+	}
+	return "list"
+}
+
+// CaptureSession returns the current view state.
+func (m Model) CaptureSession() SessionState {
+	s := SessionState{
+		View:         m.sessionView(),
+		Filter:       m.currentFilter,
+		Sort:         sortModeKeys[m.sortMode],
+		SwimLane:     swimLaneKeys[m.board.GetSwimLaneMode()],
+		SplitRatio:   m.splitPaneRatio,
+		DetailScroll: m.viewport.YOffset,
+		BoardDetail:  m.board.IsDetailShown(),
+		SavedAt:      time.Now().UTC(),
+	}
+	if strings.HasPrefix(m.currentFilter, "recipe:") && m.activeRecipe != nil {
+		s.Recipe = m.activeRecipe.Name
+		s.Filter = ""
+	}
+	if m.list.FilterState() != list.Unfiltered {
+		s.Search = m.list.FilterValue()
+	}
+
+	switch s.View {
+	case "board":
+		if issue := m.board.SelectedIssue(); issue != nil {
+			s.SelectedID = issue.ID
+		}
+	case "graph":
+		if issue := m.graphView.SelectedIssue(); issue != nil {
+			s.SelectedID = issue.ID
+		}
+	case "tree":
+		if issue := m.tree.SelectedIssue(); issue != nil {
+			s.SelectedID = issue.ID
+		}
+	default:
+		if item, ok := m.list.SelectedItem().(IssueItem); ok {
+			s.SelectedID = item.Issue.ID
+		}
+	}
+	return s
+}
+
+// applySession restores s on top of the plain list view. Recipes, labels
+// and issues that no longer exist are skipped. With keepRecipe the active
+// recipe, filter and sort are kept and s's are ignored.
+func (m *Model) applySession(s SessionState, keepRecipe bool) {
+	m.isBoardView = false
+	m.isGraphView = false
+	m.isActionableView = false
+	m.isHistoryView = false
+	m.showDetails = false
+	m.showAttentionView = false
+	m.focused = focusList
+
+	switch r := m.recipeLoader.Get(s.Recipe); {
+	case keepRecipe:
+		// The recipe's own filter and sort stay in place
+	case s.Recipe != "" && r != nil:
+		m.sortMode = parseSortModeKey(s.Sort)
+		m.setActiveRecipe(r)
+		m.applyRecipe(r)
+	default:
+		m.sortMode = parseSortModeKey(s.Sort)
+		m.setActiveRecipe(nil)
+		m.currentFilter = "all"
+		switch {
+		case s.Filter == "open", s.Filter == "closed", s.Filter == "ready":
+			m.currentFilter = s.Filter
+		case strings.HasPrefix(s.Filter, "label:"):
+			m.currentFilter = s.Filter
+		}
+		m.applyFilter()
+	}
+	if s.Search != "" {
+		m.list.SetFilterText(s.Search)
+	} else {
+		m.list.ResetFilter()
+	}
+
+	if s.SplitRatio >= 0.2 && s.SplitRatio <= 0.8 {
+		m.splitPaneRatio = s.SplitRatio
+		if m.isSplitView {
+			m.recalculateSplitPaneSizes()
+		}
+	}
+	m.board.SetSwimLaneMode(parseSwimLaneKey(s.SwimLane))
+	if m.board.IsDetailShown() != s.BoardDetail {
+		m.board.ToggleDetail()
+	}
+
+	if s.SelectedID != "" {
+		for i, item := range m.list.VisibleItems() {
+			if it, ok := item.(IssueItem); ok && it.Issue.ID == s.SelectedID {
+				m.list.Select(i)
+				break
+			}
+		}
+	}
+
+	if action, ok := sessionViewActions[s.View]; ok {
+		*m, _, _ = m.runGlobalAction(action)
+	}
+	if s.SelectedID != "" {
+		switch s.View {
+		case "board":
+			m.board.SelectIssueByID(s.SelectedID)
+		case "graph":
+			m.graphView.SelectByID(s.SelectedID)
+		case "tree":
+			m.tree.SelectByID(s.SelectedID)
+		}
+	}
+
+	if s.View == "detail" {
+		if !m.isSplitView {
+			m.showDetails = true
+		}
+		m.focused = focusDetail
+	}
+	m.updateViewportContent()
+	m.viewport.SetYOffset(s.DetailScroll)
+}
+
+// RestoreSession loads the named session, or the last active one when name
+// is empty, and makes it the active session. A session that has not been
+// saved yet starts from the current view and is created on the next save.
+func (m *Model) RestoreSession(name string) error {
+	if !m.sessionsEnabled() {
+		return nil
+	}
+	f, err := LoadSessionFile(m.sessionPath)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = f.Last
+		if name == "" {
+			name = DefaultSessionName
+		}
+	}
+	m.sessionName = name
+	s, ok := f.Sessions[name]
+	if !ok {
+		return nil
+	}
+
+	// A recipe given on the command line wins over the saved recipe and filter
+	m.applySession(s, m.activeRecipe != nil)
+	return nil
+}
+
+// DisableSessions turns off session restore and save for this model.
+func (m *Model) DisableSessions() {
+	m.sessionPath = ""
+}
+
+// SaveSession stores the current view state under the active session name
+// and marks it as the session to restore next time.
+func (m Model) SaveSession() error {
+	if !m.sessionsEnabled() {
+		return nil
+	}
+	return m.saveSessionAs(m.activeSessionName())
+}
+
+func (m Model) activeSessionName() string {
+	if m.sessionName == "" {
+		return DefaultSessionName
+	}
+	return m.sessionName
+}
+
+func (m Model) saveSessionAs(name string) error {
+	f, err := LoadSessionFile(m.sessionPath)
+	if err != nil {
+		// A corrupt file is replaced rather than blocking saves forever
+		f = &SessionFile{Sessions: make(map[string]SessionState)}
+	}
+	f.Sessions[name] = m.CaptureSession()
+	f.Last = name
+	return f.Save(m.sessionPath)
+}
+
+// openSessionPicker shows the saved sessions.
+func (m *Model) openSessionPicker() {
+	if !m.sessionsEnabled() {
+		m.statusMsg = "Sessions need a beads file to save next to"
+		m.statusIsError = false
+		return
+	}
+	f, err := LoadSessionFile(m.sessionPath)
+	if err != nil {
+		m.statusMsg = err.Error()
+		m.statusIsError = true
+	}
+	m.sessionPicker = NewSessionPickerModel(f, m.activeSessionName(), m.theme)
+	m.sessionPicker.SetSize(m.width, m.height-1)
+	m.sessionReturn = m.focused
+	m.showSessionPicker = true
+	m.focused = focusSessionPicker
+}
+
+// closeSessionPicker hides the picker and returns focus to the view below.
+func (m *Model) closeSessionPicker() {
+	m.showSessionPicker = false
+	m.focused = m.sessionReturn
+}
+
+// handleSessionPickerKeys handles keyboard input when the session picker is
+// focused.
+func (m Model) handleSessionPickerKeys(msg tea.KeyMsg) Model {
+	if m.sessionPicker.IsNaming() {
+		switch msg.String() {
+		case "esc":
+			m.sessionPicker.StopNaming()
+		case "enter":
+			name := m.sessionPicker.NameValue()
+			if name == "" {
+				m.sessionPicker.StopNaming()
+				break
+			}
+			m.closeSessionPicker()
+			if err := m.saveSessionAs(name); err != nil {
+				m.statusMsg = fmt.Sprintf("Session save failed: %v", err)
+				m.statusIsError = true
+				break
+			}
+			m.sessionName = name
+			m.statusMsg = fmt.Sprintf("Saved session %q", name)
+			m.statusIsError = false
+		default:
+			m.sessionPicker.UpdateName(msg)
+		}
+		return m
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		m.sessionPicker.MoveDown()
+	case "k", "up":
+		m.sessionPicker.MoveUp()
+	case "esc", "q", "W":
+		m.closeSessionPicker()
+	case "n":
+		m.sessionPicker.StartNaming()
+	case "d":
+		name := m.sessionPicker.SelectedName()
+		if name == "" {
+			break
+		}
+		f, err := LoadSessionFile(m.sessionPath)
+		if err == nil {
+			delete(f.Sessions, name)
+			if f.Last == name {
+				f.Last = ""
+			}
+			err = f.Save(m.sessionPath)
+		}
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Session delete failed: %v", err)
+			m.statusIsError = true
+			break
+		}
+		if name == m.sessionName {
+			m.sessionName = DefaultSessionName
+		}
+		m.sessionPicker.Remove(name)
+		m.statusMsg = fmt.Sprintf("Deleted session %q", name)
+		m.statusIsError = false
+	case "enter":
+		name := m.sessionPicker.SelectedName()
+		if name == "" {
+			break
+		}
+		state := m.sessionPicker.SelectedState()
+		m.closeSessionPicker()
+		// Keep the session being left as the user left it
+		if err := m.saveSessionAs(m.activeSessionName()); err != nil {
+			m.statusMsg = fmt.Sprintf("Session save failed: %v", err)
+			m.statusIsError = true
+		}
+		m.sessionName = name
+		m.applySession(state, false)
+		if err := m.saveSessionAs(name); err == nil {
+			m.statusMsg = fmt.Sprintf("Session: %s", name)
+			m.statusIsError = false
+		}
+	}
+	return m
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SessionPickerModel represents the saved-session picker overlay
+type SessionPickerModel struct {
+	names         []string
+	states        map[string]SessionState
+	active        string
+	selectedIndex int
+	naming        bool
+	nameInput     textinput.Model
+	width         int
+	height        int
+	theme         Theme
+}
+
+// NewSessionPickerModel creates a picker over the sessions in f. The active
+// session and the default session are listed even if they were never saved.
+func NewSessionPickerModel(f *SessionFile, active string, theme Theme) SessionPickerModel {
+	names := f.Names()
+	for _, name := range []string{DefaultSessionName, active} {
+		if _, ok := f.Sessions[name]; !ok && (len(names) == 0 || names[0] != name) {
+			names = append([]string{name}, names...)
+		}
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "session name"
+	ti.CharLimit = 40
+	ti.Width = 30
+	ti.Prompt = "Name: "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(theme.Primary).Bold(true)
+
+	return SessionPickerModel{
+		names:     names,
+		states:    f.Sessions,
+		active:    active,
+		nameInput: ti,
+		theme:     theme,
+	}
+}
+
+// SetSize updates the picker dimensions
+func (m *SessionPickerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp moves selection up
+func (m *SessionPickerModel) MoveUp() {
+	if m.selectedIndex > 0 {
+		m.selectedIndex--
+	}
+}
+
+// MoveDown moves selection down
+func (m *SessionPickerModel) MoveDown() {
+	if m.selectedIndex < len(m.names)-1 {
+		m.selectedIndex++
+	}
+}
+
+// SelectedName returns the highlighted session name, or "" if there is none
+func (m *SessionPickerModel) SelectedName() string {
+	if m.selectedIndex >= len(m.names) {
+		return ""
+	}
+	return m.names[m.selectedIndex]
+}
+
+// SelectedState returns the saved state of the highlighted session. A
+// session that was never saved yields the plain list view.
+func (m *SessionPickerModel) SelectedState() SessionState {
+	if s, ok := m.states[m.SelectedName()]; ok {
+		return s
+	}
+	return SessionState{View: "list"}
+}
+
+// Remove drops name from the picker
+func (m *SessionPickerModel) Remove(name string) {
+	for i, n := range m.names {
+		if n == name {
+			m.names = append(m.names[:i], m.names[i+1:]...)
+			break
+		}
+	}
+	delete(m.states, name)
+	if m.selectedIndex >= len(m.names) && m.selectedIndex > 0 {
+		m.selectedIndex = len(m.names) - 1
+	}
+}
+
+// IsNaming reports whether the picker is prompting for a new session name
+func (m *SessionPickerModel) IsNaming() bool {
+	return m.naming
+}
+
+// StartNaming opens the name prompt for saving the current view
+func (m *SessionPickerModel) StartNaming() {
+	m.naming = true
+	m.nameInput.SetValue("")
+	m.nameInput.Focus()
+}
+
+// StopNaming closes the name prompt
+func (m *SessionPickerModel) StopNaming() {
+	m.naming = false
+	m.nameInput.Blur()
+}
+
+// UpdateName passes a key to the name prompt
+func (m *SessionPickerModel) UpdateName(msg tea.KeyMsg) {
+	m.nameInput, _ = m.nameInput.Update(msg)
+}
+
+// NameValue returns the typed session name, trimmed
+func (m *SessionPickerModel) NameValue() string {
+	return strings.TrimSpace(m.nameInput.Value())
+}
+
+// View renders the session picker overlay
+func (m *SessionPickerModel) View() string {
+	if m.width == 0 {
+		m.width = 60
+	}
+	if m.height == 0 {
+		m.height = 20
+	}
+
+	t := m.theme
+
+	boxWidth := 56
+	if m.width < 66 {
+		boxWidth = m.width - 10
+	}
+	if boxWidth < 30 {
+		boxWidth = 30
+	}
+
+	var lines []string
+
+	titleStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true).
+		MarginBottom(1)
+	lines = append(lines, titleStyle.Render("Sessions"))
+	lines = append(lines, "")
+
+	for i, name := range m.names {
+		isSelected := i == m.selectedIndex
+
+		nameStyle := t.Renderer.NewStyle()
+		if isSelected {
+			nameStyle = nameStyle.Foreground(t.Primary).Bold(true)
+		} else {
+			nameStyle = nameStyle.Foreground(t.Base.GetForeground())
+		}
+
+		prefix := "  "
+		if isSelected {
+			prefix = "▸ "
+		}
+		line := prefix + name
+		if name == m.active {
+			line += " ●"
+		}
+		lines = append(lines, nameStyle.Render(line))
+
+		descStyle := t.Renderer.NewStyle().
+			Foreground(t.Secondary).
+			Italic(true)
+		desc := "not saved yet"
+		if s, ok := m.states[name]; ok {
+			desc = s.Summary()
+			if !s.SavedAt.IsZero() {
+				desc += " · " + FormatTimeRel(s.SavedAt)
+			}
+		}
+		lines = append(lines, descStyle.Render("    "+truncateRunesHelper(desc, boxWidth-8, "…")))
+
+		if i < len(m.names)-1 {
+			lines = append(lines, "")
+		}
+	}
+
+	lines = append(lines, "")
+	footerStyle := t.Renderer.NewStyle().
+		Foreground(ColorFooterHint).
+		Italic(true)
+	if m.naming {
+		lines = append(lines, m.nameInput.View())
+		lines = append(lines, footerStyle.Render("enter: save current view • esc: cancel"))
+	} else {
+		lines = append(lines, footerStyle.Render("j/k: navigate • enter: switch • n: save as • d: delete • esc: close"))
+	}
+
+	content := strings.Join(lines, "\n")
+
+	boxStyle := t.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+
+func TestSessionFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".beads", sessionFileName)
+
+	f, err := LoadSessionFile(path)
+	if err != nil || len(f.Sessions) != 0 {
+		t.Fatalf("missing file should load empty, got %v, %v", f, err)
+	}
+
+	now := time.Now().UTC()
+	f.Sessions["old"] = SessionState{View: "list", SavedAt: now.Add(-time.Hour)}
+	f.Sessions["new"] = SessionState{View: "board", SwimLane: "priority", SavedAt: now}
+	f.Last = "new"
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadSessionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != SessionFileVersion || got.Last != "new" || got.Sessions["new"].SwimLane != "priority" {
+		t.Errorf("round trip = %+v", got)
+	}
+	if names := strings.Join(got.Names(), ","); names != "new,old" {
+		t.Errorf("names = %s, want most recent first", names)
+	}
+	if leftovers, _ := filepath.Glob(path + ".tmp-*"); len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := LoadSessionFile(path); err == nil || f.Sessions == nil {
+		t.Error("corrupt file should report an error and still return a usable file")
+	}
+}
+
+func TestSessionSummary(t *testing.T) {
+	s := SessionState{View: "board", Filter: "label:api", Search: "auth", Sort: "updated", SwimLane: "type", SelectedID: "bv-1"}
+	want := `board · label:api · "auth" · sort updated · by type · @ bv-1`
+	if got := s.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if got := (SessionState{View: "list", Filter: "all", Sort: "default"}).Summary(); got != "list" {
+		t.Errorf("defaults should be omitted, got %q", got)
+	}
+}
+
+func TestSessionSaveAndRestore(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	path := filepath.Join(t.TempDir(), sessionFileName)
+
+	m := NewModel(issues, nil, "")
+	m.sessionPath = path
+	m.currentFilter = "open"
+	m.cycleSortMode()
+	m = sendKeys(t, m, runeKey("b"))
+	m.board.CycleSwimLaneMode()
+	m.board.SelectIssueByID("s-3")
+	m.splitPaneRatio = 0.55
+	if err := m.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewModel(issues, nil, "")
+	restored.sessionPath = path
+	if err := restored.RestoreSession(""); err != nil {
+		t.Fatal(err)
+	}
+	if restored.FocusState() != "board" || !restored.IsBoardView() {
+		t.Fatalf("view = %s, want board", restored.FocusState())
+	}
+	if restored.currentFilter != "open" || restored.sortMode != SortCreatedAsc {
+		t.Errorf("filter %q sort %v", restored.currentFilter, restored.sortMode)
+	}
+	if restored.board.GetSwimLaneMode() != SwimByPriority {
+		t.Errorf("swim lane = %s", restored.board.GetSwimLaneModeName())
+	}
+	if issue := restored.board.SelectedIssue(); issue == nil || issue.ID != "s-3" {
+		t.Errorf("selected = %v, want s-3", issue)
+	}
+	if restored.splitPaneRatio != 0.55 {
+		t.Errorf("split ratio = %v", restored.splitPaneRatio)
+	}
+	if len(restored.list.VisibleItems()) != 3 {
+		t.Errorf("list should be filtered to open issues, got %d", len(restored.list.VisibleItems()))
+	}
+
+	// A command-line recipe wins over the saved filter
+	withRecipe := NewModel(issues, restored.recipeLoader.Get("triage"), "")
+	withRecipe.sessionPath = path
+	if err := withRecipe.RestoreSession(""); err != nil {
+		t.Fatal(err)
+	}
+	if withRecipe.currentFilter != "all" {
+		t.Errorf("saved filter should not override a CLI recipe, got %q", withRecipe.currentFilter)
+	}
+
+	// Without a beads file nothing is saved
+	m.DisableSessions()
+	if err := m.SaveSession(); err != nil || m.sessionsEnabled() {
+		t.Errorf("disabled sessions should be a no-op, err %v", err)
+	}
+}
+
+func TestSessionRestoreKeepsCLIRecipe(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	path := filepath.Join(t.TempDir(), sessionFileName)
+	f := &SessionFile{Last: "work", Sessions: map[string]SessionState{
+		"work":   {View: "board", Filter: "closed", Sort: "updated"},
+		"recipe": {View: "list", Recipe: "actionable"},
+	}}
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	blocked := NewModel(issues, nil, "").recipeLoader.Get("blocked")
+	for _, name := range []string{"work", "recipe"} {
+		m := NewModel(issues, blocked, "")
+		m.applyRecipe(blocked)
+		before := len(m.list.Items())
+		m.sessionPath = path
+		if err := m.RestoreSession(name); err != nil {
+			t.Fatal(err)
+		}
+		if m.activeRecipe != blocked {
+			t.Errorf("%s: CLI recipe replaced by %v", name, m.activeRecipe)
+		}
+		if m.currentFilter == "closed" || m.sortMode != SortDefault {
+			t.Errorf("%s: saved filter %q / sort %v applied over the CLI recipe", name, m.currentFilter, m.sortMode)
+		}
+		if got := len(m.list.Items()); got != before {
+			t.Errorf("%s: list has %d issues, want the recipe's %d", name, got, before)
+		}
+		if name == "work" && m.FocusState() != "board" {
+			t.Errorf("the saved view should still be restored, got %s", m.FocusState())
+		}
+	}
+}
+
+func TestSessionRestoresListSearchAndDetail(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	path := filepath.Join(t.TempDir(), sessionFileName)
+	f := &SessionFile{Sessions: map[string]SessionState{
+		"reading": {View: "detail", Search: "Third", SelectedID: "s-3"},
+	}}
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(issues, nil, "")
+	m.sessionPath = path
+	if err := m.RestoreSession("reading"); err != nil {
+		t.Fatal(err)
+	}
+	if m.FocusState() != "detail" || m.list.FilterValue() != "Third" {
+		t.Fatalf("focus %s, search %q", m.FocusState(), m.list.FilterValue())
+	}
+	if id := m.cursorIssueID(); id != "s-3" {
+		t.Errorf("cursor = %s, want s-3", id)
+	}
+
+	// A name that was never saved starts fresh and is created on save
+	m.sessionPath = path
+	if err := m.RestoreSession("fresh"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SaveSession(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := LoadSessionFile(path); got.Last != "fresh" || len(got.Sessions) != 2 {
+		t.Errorf("session file = %+v", got)
+	}
+}
+
+func TestSessionPicker(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	path := filepath.Join(t.TempDir(), sessionFileName)
+	m := NewModel(issues, nil, "")
+	m.sessionPath = path
+
+	// Save the list view as "triage" from the picker
+	m.currentFilter = "ready"
+	m.applyFilter()
+	m = sendKeys(t, m, runeKey("W"))
+	if m.FocusState() != "session_picker" || m.CurrentContext() != ContextSessionPicker {
+		t.Fatalf("W should open the picker, focus %s", m.FocusState())
+	}
+	m = sendKeys(t, m, runeKey("n"))
+	for _, r := range "triage" {
+		m = sendKeys(t, m, runeKey(string(r)))
+	}
+	m = sendKeys(t, m, keyEnter)
+	if m.showSessionPicker || m.sessionName != "triage" {
+		t.Fatalf("save as should close the picker and switch, active %q", m.sessionName)
+	}
+	f, _ := LoadSessionFile(path)
+	if f.Sessions["triage"].Filter != "ready" || f.Last != "triage" {
+		t.Fatalf("saved %+v", f)
+	}
+
+	// Move to the board; switching to "default" saves triage as left
+	m = sendKeys(t, m, runeKey("b"), runeKey("W"))
+	if !strings.Contains(m.View(), "triage ●") {
+		t.Error("picker should mark the active session")
+	}
+	m.sessionPicker.selectedIndex = indexOf(m.sessionPicker.names, DefaultSessionName)
+	m = sendKeys(t, m, keyEnter)
+	if m.sessionName != DefaultSessionName || m.IsBoardView() || m.currentFilter != "all" {
+		t.Errorf("switch to default: active %q board %v filter %q", m.sessionName, m.IsBoardView(), m.currentFilter)
+	}
+	f, _ = LoadSessionFile(path)
+	if f.Sessions["triage"].View != "board" {
+		t.Errorf("leaving triage should save it, got view %q", f.Sessions["triage"].View)
+	}
+
+	// Switch back, then delete it
+	m = sendKeys(t, m, runeKey("W"))
+	m.sessionPicker.selectedIndex = indexOf(m.sessionPicker.names, "triage")
+	m = sendKeys(t, m, keyEnter)
+	if !m.IsBoardView() || m.currentFilter != "ready" {
+		t.Errorf("triage should restore the board on ready issues, filter %q", m.currentFilter)
+	}
+	m = sendKeys(t, m, runeKey("W"))
+	m.sessionPicker.selectedIndex = indexOf(m.sessionPicker.names, "triage")
+	m = sendKeys(t, m, runeKey("d"), keyEsc)
+	f, _ = LoadSessionFile(path)
+	if _, ok := f.Sessions["triage"]; ok || m.sessionName != DefaultSessionName {
+		t.Errorf("delete: sessions %v, active %q", f.Names(), m.sessionName)
+	}
+	if m.showSessionPicker || m.FocusState() != "board" {
+		t.Errorf("esc should return to the board, focus %s", m.FocusState())
+	}
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -15,6 +15,7 @@ type ShortcutsSidebar struct {
 	scrollOffset int
 	theme        Theme
 	context      string // Current context for filtering shortcuts
+	keymap       *Keymap
 }
 
 // shortcutItem represents a single keyboard shortcut
@@ -45,6 +46,11 @@ func (s *ShortcutsSidebar) SetSize(width, height int) {
 	s.height = height
 }
 
+// SetKeymap sets the bindings shown for remappable actions
+func (s *ShortcutsSidebar) SetKeymap(k *Keymap) {
+	s.keymap = k
+}
+
 // SetContext updates the current context for filtering shortcuts
 func (s *ShortcutsSidebar) SetContext(ctx string) {
 	s.context = ctx
@@ -87,105 +93,130 @@ func (s *ShortcutsSidebar) Width() int {
 
 // allSections returns all shortcut sections with their contexts
 func (s *ShortcutsSidebar) allSections() []shortcutSection {
+	key := s.keymap.ShortLabel
+	// View keys are listed in pairs, so "Ctrl+J/Ctrl+K" becomes "^J/^K"
+	pair := func(a, b Action) string {
+		return strings.ReplaceAll(joinKeyLabels(key(a), key(b)), "Ctrl+", "^")
+	}
 	return []shortcutSection{
 		{
 			title:    "Navigation",
 			contexts: []string{}, // All contexts
 			items: []shortcutItem{
-				{"j/k", "Move ↓/↑"},
+				{joinKeyLabels(key(ActionMoveDown), key(ActionMoveUp)), "Move ↓/↑"},
 				{"G/gg", "End/Start"},
 				{"^d/^u", "Page ↓/↑"},
-				{"Enter", "Details"},
-				{"Esc", "Back"},
+				{key(ActionOpenDetails), "Details"},
+				{key(ActionBack), "Back"},
 			},
 		},
 		{
 			title:    "Views",
 			contexts: []string{"list", "detail", "split"},
 			items: []shortcutItem{
-				{"a", "Actionable"},
-				{"b", "Board"},
-				{"g", "Graph"},
-				{"h", "History"},
-				{"i", "Insights"},
-				{"?", "Help"},
-				{";", "This sidebar"},
-				{"p", "Priority hints"},
+				{key(ActionActionable), "Actionable"},
+				{key(ActionBoard), "Board"},
+				{key(ActionGraph), "Graph"},
+				{key(ActionHistory), "History"},
+				{key(ActionInsights), "Insights"},
+				{key(ActionFlowMetrics), "Flow metrics"},
+				{key(ActionTimeline), "Timeline"},
+				{key(ActionForecast), "Forecast"},
+				{key(ActionHelp), "Help"},
+				{key(ActionCommandPalette), "Commands"},
+				{key(ActionShortcuts), "This sidebar"},
+				{key(ActionPriorityHints), "Priority hints"},
 			},
 		},
 		{
 			title:    "Graph",
 			contexts: []string{"graph"},
 			items: []shortcutItem{
-				{"hjkl", "Navigate"},
-				{"H/L", "Scroll ←/→"},
-				{"PgUp/Dn", "Scroll ↑/↓"},
-				{"Enter", "Jump to issue"},
+				{key(ActionGraphLeft) + key(ActionGraphDown) + key(ActionGraphUp) + key(ActionGraphRight), "Navigate"},
+				{pair(ActionGraphScrollLeft, ActionGraphScrollRight), "Scroll ←/→"},
+				{pair(ActionGraphPageUp, ActionGraphPageDown), "Scroll ↑/↓"},
+				{key(ActionGraphOpen), "Jump to issue"},
 			},
 		},
 		{
 			title:    "Insights",
 			contexts: []string{"insights"},
 			items: []shortcutItem{
-				{"h/l", "Switch panel"},
-				{"j/k", "Select item"},
-				{"^j/^k", "Scroll detail"},
-				{"e", "Explanations"},
-				{"x", "Calc proof"},
-				{"m", "Heatmap"},
-				{"Enter", "Jump to issue"},
+				{pair(ActionInsightsPrevPanel, ActionInsightsNextPanel), "Switch panel"},
+				{pair(ActionInsightsDown, ActionInsightsUp), "Select item"},
+				{pair(ActionInsightsDetailDown, ActionInsightsDetailUp), "Scroll detail"},
+				{key(ActionInsightsExplain), "Explanations"},
+				{key(ActionInsightsCalc), "Calc proof"},
+				{key(ActionInsightsHeatmap), "Heatmap"},
+				{key(ActionInsightsOpen), "Jump to issue"},
 			},
 		},
 		{
 			title:    "History",
 			contexts: []string{"history"},
 			items: []shortcutItem{
-				{"v", "Git/Bead mode"},
-				{"/", "Search"},
-				{"j/k", "Navigate ↓/↑"},
-				{"J/K", "Detail ↓/↑"},
-				{"Tab", "Focus toggle"},
-				{"y", "Copy SHA"},
-				{"o", "Open in browser"},
-				{"g", "Graph view"},
-				{"c", "Cycle filter"},
+				{key(ActionHistoryMode), "Git/Bead mode"},
+				{key(ActionHistorySearch), "Search"},
+				{pair(ActionHistoryDown, ActionHistoryUp), "Navigate ↓/↑"},
+				{pair(ActionHistoryNext, ActionHistoryPrev), "Detail ↓/↑"},
+				{key(ActionHistoryFocus), "Focus toggle"},
+				{key(ActionHistoryCopySHA), "Copy SHA"},
+				{key(ActionHistoryBrowser), "Open in browser"},
+				{key(ActionHistoryGraph), "Graph view"},
+				{key(ActionHistoryConfidence), "Cycle filter"},
 			},
 		},
 		{
 			title:    "Board",
 			contexts: []string{"board"},
 			items: []shortcutItem{
-				{"h/l", "Columns ←/→"},
-				{"j/k", "Items ↓/↑"},
-				{"Tab", "Toggle detail"},
-				{"y", "Copy ID"},
-				{"^j/^k", "Scroll detail"},
-				{"Enter", "Full view"},
+				{pair(ActionBoardLeft, ActionBoardRight), "Columns ←/→"},
+				{pair(ActionBoardDown, ActionBoardUp), "Items ↓/↑"},
+				{key(ActionBoardDetail), "Toggle detail"},
+				{key(ActionBoardCopyID), "Copy ID"},
+				{pair(ActionBoardDetailDown, ActionBoardDetailUp), "Scroll detail"},
+				{key(ActionBoardOpen), "Full view"},
+			},
+		},
+		{
+			title:    "Multi-select",
+			contexts: []string{"list", "split", "board"},
+			items: []shortcutItem{
+				{key(ActionToggleSelect), "Mark/unmark"},
+				{key(ActionSelectDown), "Extend ↓"},
+				{key(ActionSelectUp), "Extend ↑"},
+				{key(ActionSelectAll), "Mark all"},
+				{key(ActionBatch), "Batch actions"},
 			},
 		},
 		{
 			title:    "Filters",
 			contexts: []string{"list", "split"},
 			items: []shortcutItem{
-				{"o", "Open only"},
-				{"c", "Closed only"},
-				{"r", "Ready (no blocks)"},
-				{"l", "Label picker"},
-				{"/", "Search"},
+				{key(ActionFilterOpen), "Open only"},
+				{key(ActionFilterClosed), "Closed only"},
+				{key(ActionFilterReady), "Ready (no blocks)"},
+				{key(ActionLabelPicker), "Label picker"},
+				{key(ActionSearch), "Search"},
 			},
 		},
 		{
 			title:    "Actions",
 			contexts: []string{"list", "detail", "split"},
 			items: []shortcutItem{
-				{"t/T", "Time-travel"},
-				{"x", "Export .md"},
-				{"y", "Copy ID"},
-				{"C", "Copy"},
-				{"O", "Open in $EDITOR"},
-				{"'", "Recipe picker"},
-				{"U", "Self-update"},
-				{"V", "Cass sessions"},
+				{joinKeyLabels(key(ActionTimeTravel), key(ActionQuickTimeTravel)), "Time-travel"},
+				{key(ActionExportMarkdown), "Export .md"},
+				{key(ActionCopyID), "Copy ID"},
+				{key(ActionCopyIssue), "Copy"},
+				{key(ActionOpenEditor), "Open in $EDITOR"},
+				{key(ActionMutate), "Claim/close/edit"},
+				{key(ActionMerge), "Merge conflicts"},
+				{key(ActionRecipes), "Recipe picker"},
+				{key(ActionSessions), "Sessions"},
+				{key(ActionThemes), "Themes"},
+				{key(ActionScoringProfile), "Scoring profile"},
+				{key(ActionSelfUpdate), "Self-update"},
+				{key(ActionCassSessions), "Cass sessions"},
 			},
 		},
 	}
@@ -246,6 +277,9 @@ func (s *ShortcutsSidebar) View() string {
 		sb.WriteString("\n")
 
 		for _, item := range section.items {
+			if item.key == "" {
+				continue // Unbound
+			}
 			line := keyStyle.Render(item.key) + descStyle.Render(item.desc)
 			sb.WriteString(line + "\n")
 		}
@@ -299,6 +299,13 @@ func (b *SnapshotBuilder) WithRecipe(r *recipe.Recipe) *SnapshotBuilder {
 	return b
 }
 
+// WithScoringProfile scores triage and priority hints with p instead of the
+// default weights.
+func (b *SnapshotBuilder) WithScoringProfile(p *analysis.ScoringProfile) *SnapshotBuilder {
+	b.analyzer.SetScoringProfile(p)
+	return b
+}
+
 func (b *SnapshotBuilder) WithBuildConfig(cfg snapshotBuildConfig) *SnapshotBuilder {
 	b.cfg = cfg
 	return b
@@ -692,6 +699,11 @@ func issueMatchesRecipe(issue model.Issue, issueMap map[string]*model.Issue, r *
 		}
 	}
 
+	// Custom field filter
+	if !r.Filters.MatchCustomFields(issue.CustomFields) {
+		return false
+	}
+
 	return true
 }
 
@@ -767,6 +779,10 @@ func sortIssuesByRecipe(issues []model.Issue, stats *analysis.GraphStats, r *rec
 				cmp = 1
 			}
 		default:
+			if name, ok := recipe.CustomSortField(field); ok {
+				cmp = recipe.CompareCustomField(&ii, &jj, name, desc)
+				break
+			}
 			switch {
 			case ii.Priority < jj.Priority:
 				cmp = -1
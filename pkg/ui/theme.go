@@ -51,10 +51,15 @@ func ThemeFg(hex string) lipgloss.TerminalColor {
 type Theme struct {
 	Renderer *lipgloss.Renderer
 
+	// Name is the catalog name of the palette this theme was built from
+	Name string
+
 	// Colors
-	Primary   lipgloss.AdaptiveColor
-	Secondary lipgloss.AdaptiveColor
-	Subtext   lipgloss.AdaptiveColor
+	Primary    lipgloss.AdaptiveColor
+	Secondary  lipgloss.AdaptiveColor
+	Subtext    lipgloss.AdaptiveColor
+	Text       lipgloss.AdaptiveColor
+	HeaderText lipgloss.AdaptiveColor
 
 	// Status
 	Open       lipgloss.AdaptiveColor
@@ -79,6 +84,11 @@ type Theme struct {
 	Highlight lipgloss.AdaptiveColor
 	Muted     lipgloss.AdaptiveColor
 
+	// Borders
+	PanelBorder    lipgloss.Border // Board columns, insight panels
+	CardBorder     lipgloss.Border // Board cards
+	SelectedBorder lipgloss.Border // Left edge of the selected list row
+
 	// Styles
 	Base     lipgloss.Style
 	Selected lipgloss.Style
@@ -99,7 +109,8 @@ type Theme struct {
 	TriageUnblocksAlt lipgloss.Style // Secondary unblocks ↪
 }
 
-// DefaultTheme returns the standard Dracula-inspired theme (adaptive).
+// DefaultTheme returns the active theme: the Dracula-inspired built-in
+// colors with any overrides from the palette selected by UseTheme.
 // Respects BV_THEME=light|dark to override background detection. (bv-128)
 func DefaultTheme(r *lipgloss.Renderer) Theme {
 	// Apply BV_THEME override so AdaptiveColor picks the right variant
@@ -115,6 +126,9 @@ func DefaultTheme(r *lipgloss.Renderer) Theme {
 		Secondary: lipgloss.AdaptiveColor{Light: "#555555", Dark: "#6272A4"}, // Gray
 		Subtext:   lipgloss.AdaptiveColor{Light: "#666666", Dark: "#BFBFBF"}, // Dim (was #999999, now ~6:1)
 
+		Text:       lipgloss.AdaptiveColor{Light: "#000000", Dark: "#F8F8F2"},
+		HeaderText: lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#282A36"},
+
 		Open:       lipgloss.AdaptiveColor{Light: "#007700", Dark: "#50FA7B"}, // Green (was #00A800, now ~4.6:1)
 		InProgress: lipgloss.AdaptiveColor{Light: "#006080", Dark: "#8BE9FD"}, // Cyan (darker for contrast)
 		Blocked:    lipgloss.AdaptiveColor{Light: "#CC0000", Dark: "#FF5555"}, // Red (slightly adjusted)
@@ -134,20 +148,25 @@ func DefaultTheme(r *lipgloss.Renderer) Theme {
 		Border:    lipgloss.AdaptiveColor{Light: "#AAAAAA", Dark: "#44475A"}, // Border (was #DDDDDD)
 		Highlight: lipgloss.AdaptiveColor{Light: "#E0E0E0", Dark: "#44475A"}, // Slightly darker
 		Muted:     lipgloss.AdaptiveColor{Light: "#555555", Dark: "#6272A4"}, // Dimmed text (was #888888, now ~7:1)
+
+		PanelBorder:    lipgloss.RoundedBorder(),
+		CardBorder:     lipgloss.RoundedBorder(),
+		SelectedBorder: lipgloss.ThickBorder(),
 	}
+	activePalette.apply(&t)
 
-	t.Base = r.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#000000", Dark: "#F8F8F2"})
+	t.Base = r.NewStyle().Foreground(t.Text)
 
 	t.Selected = r.NewStyle().
 		Background(t.Highlight).
-		Border(lipgloss.ThickBorder(), false, false, false, true).
+		Border(t.SelectedBorder, false, false, false, true).
 		BorderForeground(t.Primary).
 		PaddingLeft(1).
 		Bold(true)
 
 	t.Header = r.NewStyle().
 		Background(t.Primary).
-		Foreground(lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#282A36"}).
+		Foreground(t.HeaderText).
 		Bold(true).
 		Padding(0, 1)
 
@@ -167,6 +186,176 @@ func DefaultTheme(r *lipgloss.Renderer) Theme {
 	return t
 }
 
+// Palette is a resolved theme file: color overrides keyed by slot
+// ("colors.primary", "status.open_bg", ...) and border style names.
+// Slots it leaves out keep their built-in colors.
+type Palette struct {
+	Name    string
+	Colors  map[string]lipgloss.AdaptiveColor
+	Borders ThemeBorders
+}
+
+// activePalette is applied by DefaultTheme; set it with UseTheme.
+var activePalette = Palette{Name: DefaultThemeName}
+
+// ActivePalette returns the palette selected by UseTheme.
+func ActivePalette() Palette {
+	return activePalette
+}
+
+// UseTheme makes p the palette for every Theme built afterwards and resets
+// the package-level colors and panel styles to the built-ins plus p's
+// overrides, so switching themes never leaves colors from the last one.
+func UseTheme(p Palette) {
+	if p.Name == "" {
+		p.Name = DefaultThemeName
+	}
+	activePalette = p
+	for slot, color := range packageColorSlots {
+		*color = builtinPackageColors[slot]
+		if c, ok := p.Colors[slot]; ok {
+			*color = c
+		}
+	}
+	panel := lipgloss.RoundedBorder()
+	if b, ok := borderStyles[p.Borders.Panel]; ok {
+		panel = b
+	}
+	PanelStyle = lipgloss.NewStyle().
+		Border(panel).
+		BorderForeground(ColorBgHighlight)
+	FocusedPanelStyle = lipgloss.NewStyle().
+		Border(panel).
+		BorderForeground(ColorPrimary)
+}
+
+// apply copies p's colors and borders onto t's fields.
+func (p Palette) apply(t *Theme) {
+	t.Name = p.Name
+	fields := themeFieldSlots(t)
+	for slot, c := range p.Colors {
+		if field, ok := fields[slot]; ok {
+			*field = c
+		}
+	}
+	if b, ok := borderStyles[p.Borders.Panel]; ok {
+		t.PanelBorder = b
+	}
+	if b, ok := borderStyles[p.Borders.Card]; ok {
+		t.CardBorder = b
+	}
+	if b, ok := borderStyles[p.Borders.Selected]; ok {
+		t.SelectedBorder = b
+	}
+}
+
+// themeFieldSlots maps theme file slots to the Theme fields they set.
+func themeFieldSlots(t *Theme) map[string]*lipgloss.AdaptiveColor {
+	return map[string]*lipgloss.AdaptiveColor{
+		"colors.primary":     &t.Primary,
+		"colors.secondary":   &t.Secondary,
+		"colors.subtext":     &t.Subtext,
+		"colors.text":        &t.Text,
+		"colors.header_text": &t.HeaderText,
+		"colors.border":      &t.Border,
+		"colors.highlight":   &t.Highlight,
+		"colors.muted":       &t.Muted,
+
+		"status.open":        &t.Open,
+		"status.in_progress": &t.InProgress,
+		"status.blocked":     &t.Blocked,
+		"status.deferred":    &t.Deferred,
+		"status.pinned":      &t.Pinned,
+		"status.hooked":      &t.Hooked,
+		"status.review":      &t.Review,
+		"status.closed":      &t.Closed,
+		"status.tombstone":   &t.Tombstone,
+
+		"type.bug":     &t.Bug,
+		"type.feature": &t.Feature,
+		"type.task":    &t.Task,
+		"type.epic":    &t.Epic,
+		"type.chore":   &t.Chore,
+	}
+}
+
+// packageColorSlots maps theme file slots to the package-level colors
+// used by badges, the footer and views that don't hold a Theme.
+var packageColorSlots = map[string]*lipgloss.AdaptiveColor{
+	"colors.background":        &ColorBg,
+	"colors.background_dark":   &ColorBgDark,
+	"colors.background_subtle": &ColorBgSubtle,
+	"colors.highlight":         &ColorBgHighlight,
+	"colors.text":              &ColorText,
+	"colors.subtext":           &ColorSubtext,
+	"colors.muted":             &ColorMuted,
+	"colors.primary":           &ColorPrimary,
+	"colors.secondary":         &ColorSecondary,
+	"colors.info":              &ColorInfo,
+	"colors.success":           &ColorSuccess,
+	"colors.warning":           &ColorWarning,
+	"colors.danger":            &ColorDanger,
+	"colors.footer_hint":       &ColorFooterHint,
+	"colors.footer_key":        &ColorFooterKey,
+	"colors.footer_sep":        &ColorFooterSep,
+	"colors.footer_dim":        &ColorFooterDim,
+
+	"status.open":           &ColorStatusOpen,
+	"status.in_progress":    &ColorStatusInProgress,
+	"status.blocked":        &ColorStatusBlocked,
+	"status.deferred":       &ColorStatusDeferred,
+	"status.pinned":         &ColorStatusPinned,
+	"status.hooked":         &ColorStatusHooked,
+	"status.review":         &ColorStatusReview,
+	"status.closed":         &ColorStatusClosed,
+	"status.tombstone":      &ColorStatusTombstone,
+	"status.open_bg":        &ColorStatusOpenBg,
+	"status.in_progress_bg": &ColorStatusInProgressBg,
+	"status.blocked_bg":     &ColorStatusBlockedBg,
+	"status.deferred_bg":    &ColorStatusDeferredBg,
+	"status.pinned_bg":      &ColorStatusPinnedBg,
+	"status.hooked_bg":      &ColorStatusHookedBg,
+	"status.review_bg":      &ColorStatusReviewBg,
+	"status.closed_bg":      &ColorStatusClosedBg,
+	"status.tombstone_bg":   &ColorStatusTombstoneBg,
+
+	"priority.critical":    &ColorPrioCritical,
+	"priority.high":        &ColorPrioHigh,
+	"priority.medium":      &ColorPrioMedium,
+	"priority.low":         &ColorPrioLow,
+	"priority.critical_bg": &ColorPrioCriticalBg,
+	"priority.high_bg":     &ColorPrioHighBg,
+	"priority.medium_bg":   &ColorPrioMediumBg,
+	"priority.low_bg":      &ColorPrioLowBg,
+
+	"type.bug":     &ColorTypeBug,
+	"type.feature": &ColorTypeFeature,
+	"type.task":    &ColorTypeTask,
+	"type.epic":    &ColorTypeEpic,
+	"type.chore":   &ColorTypeChore,
+}
+
+// builtinPackageColors snapshots the package-level colors before any theme
+// is applied.
+var builtinPackageColors = func() map[string]lipgloss.AdaptiveColor {
+	colors := make(map[string]lipgloss.AdaptiveColor, len(packageColorSlots))
+	for slot, c := range packageColorSlots {
+		colors[slot] = *c
+	}
+	return colors
+}()
+
+// borderStyles are the border names a theme file may use.
+var borderStyles = map[string]lipgloss.Border{
+	"normal":  lipgloss.NormalBorder(),
+	"rounded": lipgloss.RoundedBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"block":   lipgloss.BlockBorder(),
+	"ascii":   lipgloss.ASCIIBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
 func (t Theme) GetStatusColor(s string) lipgloss.AdaptiveColor {
 	switch s {
 	case "open":
@@ -0,0 +1,347 @@
+package ui
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultThemeName is the built-in theme every other theme starts from.
+const DefaultThemeName = "default"
+
+// ThemeFilename is the project theme file name under .bv/.
+const ThemeFilename = "theme.yaml"
+
+//go:embed themes/*.yaml
+var presetThemesFS embed.FS
+
+// ThemeSpec is the theme file format:
+//
+//	name: dusk
+//	extends: solarized
+//	colors:
+//	  primary: "#B58900"
+//	  text: {light: "#073642", dark: "#EEE8D5"}
+//	status:
+//	  blocked: "#DC322F"
+//	  blocked_bg: {light: "#FDE2E1", dark: "#3A1414"}
+//	borders:
+//	  panel: double
+//
+// A color is a hex value or ANSI number used on both backgrounds, or a
+// light/dark pair. Slots that are left out are inherited.
+type ThemeSpec struct {
+	Name        string                `yaml:"name"`
+	Description string                `yaml:"description"`
+	Extends     string                `yaml:"extends"`
+	Colors      map[string]ThemeColor `yaml:"colors"`
+	Status      map[string]ThemeColor `yaml:"status"`
+	Priority    map[string]ThemeColor `yaml:"priority"`
+	Type        map[string]ThemeColor `yaml:"type"`
+	Borders     ThemeBorders          `yaml:"borders"`
+}
+
+// ThemeBorders names the border styles of a theme: normal, rounded,
+// thick, double, block, ascii or hidden.
+type ThemeBorders struct {
+	Panel    string `yaml:"panel"`
+	Card     string `yaml:"card"`
+	Selected string `yaml:"selected"`
+}
+
+// ThemeColor is an adaptive color read from a theme file.
+type ThemeColor lipgloss.AdaptiveColor
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func (c *ThemeColor) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if err := validateThemeColor(node.Value); err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*c = ThemeColor{Light: node.Value, Dark: node.Value}
+		return nil
+	case yaml.MappingNode:
+		var pair struct {
+			Light string `yaml:"light"`
+			Dark  string `yaml:"dark"`
+		}
+		if err := node.Decode(&pair); err != nil {
+			return err
+		}
+		if pair.Light == "" {
+			pair.Light = pair.Dark
+		}
+		if pair.Dark == "" {
+			pair.Dark = pair.Light
+		}
+		for _, v := range []string{pair.Light, pair.Dark} {
+			if err := validateThemeColor(v); err != nil {
+				return fmt.Errorf("line %d: %w", node.Line, err)
+			}
+		}
+		*c = ThemeColor{Light: pair.Light, Dark: pair.Dark}
+		return nil
+	default:
+		return fmt.Errorf("line %d: expected a color or {light, dark}", node.Line)
+	}
+}
+
+func validateThemeColor(v string) error {
+	if hexColorPattern.MatchString(v) {
+		return nil
+	}
+	if n, err := strconv.Atoi(v); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q (want #RRGGBB or an ANSI number 0-255)", v)
+}
+
+// colors flattens the spec's color sections into slot keys.
+func (s ThemeSpec) colors() map[string]lipgloss.AdaptiveColor {
+	out := make(map[string]lipgloss.AdaptiveColor)
+	for section, colors := range map[string]map[string]ThemeColor{
+		"colors": s.Colors, "status": s.Status, "priority": s.Priority, "type": s.Type,
+	} {
+		for key, c := range colors {
+			out[section+"."+key] = lipgloss.AdaptiveColor(c)
+		}
+	}
+	return out
+}
+
+// validate rejects unknown color slots and border names.
+func (s ThemeSpec) validate() error {
+	var unknown []string
+	for slot := range s.colors() {
+		if !isThemeSlot(slot) {
+			unknown = append(unknown, slot)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown color slot %s", strings.Join(unknown, ", "))
+	}
+	for _, b := range []string{s.Borders.Panel, s.Borders.Card, s.Borders.Selected} {
+		if _, ok := borderStyles[b]; b != "" && !ok {
+			return fmt.Errorf("unknown border style %q", b)
+		}
+	}
+	return nil
+}
+
+func isThemeSlot(slot string) bool {
+	if _, ok := packageColorSlots[slot]; ok {
+		return true
+	}
+	_, ok := themeFieldSlots(&Theme{})[slot]
+	return ok
+}
+
+// ParseThemeSpec parses and validates a theme file.
+func ParseThemeSpec(data []byte) (ThemeSpec, error) {
+	var spec ThemeSpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return ThemeSpec{}, err
+	}
+	if err := spec.validate(); err != nil {
+		return ThemeSpec{}, err
+	}
+	return spec, nil
+}
+
+// ThemeSummary describes a theme in the catalog.
+type ThemeSummary struct {
+	Name        string
+	Description string
+	Source      string // "builtin", "user" or "project"
+}
+
+// ThemeCatalog holds the built-in theme, the bundled presets and themes
+// loaded from the user themes directory and the project.
+type ThemeCatalog struct {
+	specs    map[string]ThemeSpec
+	sources  map[string]string
+	project  string
+	warnings []string
+}
+
+// UserThemesDir returns ~/.config/bv/themes, or "" if the home directory
+// is unknown.
+func UserThemesDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "bv", "themes")
+}
+
+// LoadThemeCatalog loads the presets, every *.yaml in userDir and then
+// projectDir/.bv/theme.yaml, later themes replacing earlier ones of the
+// same name. Files that fail to parse are skipped and reported in
+// Warnings.
+func LoadThemeCatalog(userDir, projectDir string) *ThemeCatalog {
+	c := &ThemeCatalog{
+		specs:   map[string]ThemeSpec{DefaultThemeName: {Name: DefaultThemeName, Description: "Dracula-inspired built-in colors"}},
+		sources: map[string]string{DefaultThemeName: "builtin"},
+	}
+
+	presets, _ := presetThemesFS.ReadDir("themes")
+	for _, entry := range presets {
+		data, err := presetThemesFS.ReadFile("themes/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		spec, err := ParseThemeSpec(data)
+		if err != nil {
+			c.warnings = append(c.warnings, fmt.Sprintf("preset %s: %v", entry.Name(), err))
+			continue
+		}
+		c.add(spec, entry.Name(), "builtin")
+	}
+
+	if userDir != "" {
+		paths, _ := filepath.Glob(filepath.Join(userDir, "*.yaml"))
+		for _, path := range paths {
+			if _, err := c.AddFile(path, "user"); err != nil {
+				c.warnings = append(c.warnings, err.Error())
+			}
+		}
+	}
+
+	if projectDir != "" {
+		path := filepath.Join(projectDir, ".bv", ThemeFilename)
+		if _, err := os.Stat(path); err == nil {
+			name, err := c.AddFile(path, "project")
+			if err != nil {
+				c.warnings = append(c.warnings, err.Error())
+			} else {
+				c.project = name
+			}
+		}
+	}
+	return c
+}
+
+// AddFile loads the theme file at path into the catalog and returns its
+// name: the file's name field, or the file name without extension ("project"
+// for the project theme file).
+func (c *ThemeCatalog) AddFile(path, source string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	spec, err := ParseThemeSpec(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return c.add(spec, filepath.Base(path), source), nil
+}
+
+func (c *ThemeCatalog) add(spec ThemeSpec, filename, source string) string {
+	if spec.Name == "" && source == "project" {
+		spec.Name = "project"
+	} else if spec.Name == "" {
+		spec.Name = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+	c.specs[spec.Name] = spec
+	c.sources[spec.Name] = source
+	return spec.Name
+}
+
+// Warnings returns problems found while loading theme files.
+func (c *ThemeCatalog) Warnings() []string {
+	return c.warnings
+}
+
+// Default returns the project theme's name if the project has one, and
+// the built-in theme's otherwise.
+func (c *ThemeCatalog) Default() string {
+	if c.project != "" {
+		return c.project
+	}
+	return DefaultThemeName
+}
+
+// List returns the catalog's themes, the built-in theme first and the rest
+// by name.
+func (c *ThemeCatalog) List() []ThemeSummary {
+	list := make([]ThemeSummary, 0, len(c.specs))
+	for name, spec := range c.specs {
+		list = append(list, ThemeSummary{Name: name, Description: spec.Description, Source: c.sources[name]})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].Name == DefaultThemeName) != (list[j].Name == DefaultThemeName) {
+			return list[i].Name == DefaultThemeName
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Lookup returns the catalog name for a theme name or a path to a theme
+// file, loading the file if it is not in the catalog yet.
+func (c *ThemeCatalog) Lookup(nameOrPath string) (string, error) {
+	if _, ok := c.specs[nameOrPath]; ok {
+		return nameOrPath, nil
+	}
+	if ext := filepath.Ext(nameOrPath); ext == ".yaml" || ext == ".yml" {
+		return c.AddFile(nameOrPath, "user")
+	}
+	names := make([]string, 0, len(c.specs))
+	for _, s := range c.List() {
+		names = append(names, s.Name)
+	}
+	return "", fmt.Errorf("unknown theme %q (available: %s)", nameOrPath, strings.Join(names, ", "))
+}
+
+// Resolve merges the named theme with the themes it extends.
+func (c *ThemeCatalog) Resolve(name string) (Palette, error) {
+	var chain []ThemeSpec
+	seen := make(map[string]bool)
+	for next := name; next != ""; {
+		if seen[next] {
+			return Palette{}, fmt.Errorf("theme %q: extends cycle at %q", name, next)
+		}
+		seen[next] = true
+		spec, ok := c.specs[next]
+		if !ok {
+			if next == name {
+				return Palette{}, fmt.Errorf("unknown theme %q", name)
+			}
+			return Palette{}, fmt.Errorf("theme %q extends unknown theme %q", name, next)
+		}
+		chain = append(chain, spec)
+		next = spec.Extends
+	}
+
+	p := Palette{Name: name, Colors: make(map[string]lipgloss.AdaptiveColor)}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for slot, color := range chain[i].colors() {
+			p.Colors[slot] = color
+		}
+		b := chain[i].Borders
+		if b.Panel != "" {
+			p.Borders.Panel = b.Panel
+		}
+		if b.Card != "" {
+			p.Borders.Card = b.Card
+		}
+		if b.Selected != "" {
+			p.Borders.Selected = b.Selected
+		}
+	}
+	return p, nil
+}
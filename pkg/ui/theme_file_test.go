@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func writeThemeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseThemeSpec(t *testing.T) {
+	spec, err := ParseThemeSpec([]byte(`
+name: dusk
+colors:
+  primary: "#B58900"
+  text: {light: "#073642", dark: "#EEE8D5"}
+status:
+  open_bg: {dark: "#1C3A1E"}
+priority:
+  critical: "196"
+borders:
+  panel: double
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := spec.colors()
+	if colors["colors.primary"] != (lipgloss.AdaptiveColor{Light: "#B58900", Dark: "#B58900"}) {
+		t.Errorf("scalar color = %+v", colors["colors.primary"])
+	}
+	if colors["colors.text"].Light != "#073642" || colors["status.open_bg"].Light != "#1C3A1E" {
+		t.Errorf("pair colors = %+v, %+v", colors["colors.text"], colors["status.open_bg"])
+	}
+	if colors["priority.critical"].Dark != "196" {
+		t.Errorf("ANSI color = %+v", colors["priority.critical"])
+	}
+
+	for _, bad := range []string{
+		"colors:\n  primry: \"#FFFFFF\"\n",
+		"colors:\n  primary: purple\n",
+		"borders:\n  panel: wavy\n",
+		"colours:\n  primary: \"#FFFFFF\"\n",
+	} {
+		if _, err := ParseThemeSpec([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestThemeCatalogPresets(t *testing.T) {
+	c := LoadThemeCatalog("", "")
+	if len(c.Warnings()) > 0 {
+		t.Fatalf("preset warnings: %v", c.Warnings())
+	}
+	var names []string
+	for _, s := range c.List() {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "default,colorblind-safe,gruvbox,high-contrast,solarized" {
+		t.Errorf("themes = %s", got)
+	}
+	for _, name := range names {
+		p, err := c.Resolve(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if name != DefaultThemeName && len(p.Colors) == 0 {
+			t.Errorf("%s sets no colors", name)
+		}
+	}
+	if c.Default() != DefaultThemeName {
+		t.Errorf("default = %s", c.Default())
+	}
+}
+
+func TestThemeCatalogUserAndProjectFiles(t *testing.T) {
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+	writeThemeFile(t, filepath.Join(userDir, "dusk.yaml"), "extends: solarized\ncolors:\n  primary: \"#B58900\"\nborders:\n  card: double\n")
+	writeThemeFile(t, filepath.Join(userDir, "loop-a.yaml"), "extends: loop-b\n")
+	writeThemeFile(t, filepath.Join(userDir, "loop-b.yaml"), "extends: loop-a\n")
+	writeThemeFile(t, filepath.Join(userDir, "broken.yaml"), "colors: [\n")
+	writeThemeFile(t, filepath.Join(projectDir, ".bv", ThemeFilename), "extends: dusk\nstatus:\n  open: \"#00FF00\"\n")
+
+	c := LoadThemeCatalog(userDir, projectDir)
+	if len(c.Warnings()) != 1 || !strings.Contains(c.Warnings()[0], "broken.yaml") {
+		t.Errorf("warnings = %v", c.Warnings())
+	}
+	if c.Default() != "project" {
+		t.Fatalf("project theme should be the default, got %s", c.Default())
+	}
+
+	p, err := c.Resolve("project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Colors["colors.primary"].Dark != "#B58900" {
+		t.Errorf("primary should come from dusk, got %+v", p.Colors["colors.primary"])
+	}
+	if p.Colors["colors.info"].Dark != "#2AA198" {
+		t.Errorf("info should come from solarized, got %+v", p.Colors["colors.info"])
+	}
+	if p.Colors["status.open"].Dark != "#00FF00" || p.Borders.Card != "double" {
+		t.Errorf("palette = %+v", p)
+	}
+
+	if _, err := c.Resolve("loop-a"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+	if _, err := c.Lookup("nope"); err == nil || !strings.Contains(err.Error(), "gruvbox") {
+		t.Errorf("unknown theme error should list themes, got %v", err)
+	}
+	name, err := c.Lookup(filepath.Join(userDir, "dusk.yaml"))
+	if err != nil || name != "dusk" {
+		t.Errorf("lookup by path = %q, %v", name, err)
+	}
+}
+
+func TestUseThemeAppliesAndResets(t *testing.T) {
+	t.Cleanup(func() { UseTheme(Palette{}) })
+	builtinOpen := ColorStatusOpen
+
+	c := LoadThemeCatalog("", "")
+	p, err := c.Resolve("high-contrast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	UseTheme(p)
+	theme := DefaultTheme(lipgloss.NewRenderer(os.Stdout))
+	if theme.Name != "high-contrast" || theme.Open != p.Colors["status.open"] {
+		t.Errorf("theme = %s, open %+v", theme.Name, theme.Open)
+	}
+	if ColorStatusOpen != p.Colors["status.open"] {
+		t.Errorf("package color not applied: %+v", ColorStatusOpen)
+	}
+	if theme.PanelBorder != lipgloss.ThickBorder() || PanelStyle.GetBorderStyle() != lipgloss.ThickBorder() {
+		t.Error("panel border should be thick")
+	}
+
+	UseTheme(Palette{})
+	theme = DefaultTheme(lipgloss.NewRenderer(os.Stdout))
+	if ColorStatusOpen != builtinOpen || theme.Name != DefaultThemeName || theme.PanelBorder != lipgloss.RoundedBorder() {
+		t.Errorf("default theme should restore built-ins, open %+v", ColorStatusOpen)
+	}
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ThemePickerModel represents the theme switcher overlay. Moving the cursor
+// previews the highlighted theme; the model keeps the theme that was active
+// when it opened so it can be restored on cancel.
+type ThemePickerModel struct {
+	themes        []ThemeSummary
+	original      string
+	selectedIndex int
+	width         int
+	height        int
+	theme         Theme
+}
+
+// NewThemePickerModel creates a picker over themes with the cursor on the
+// active theme.
+func NewThemePickerModel(themes []ThemeSummary, active string, theme Theme) ThemePickerModel {
+	m := ThemePickerModel{themes: themes, original: active, theme: theme}
+	for i, t := range themes {
+		if t.Name == active {
+			m.selectedIndex = i
+		}
+	}
+	return m
+}
+
+// SetSize updates the picker dimensions
+func (m *ThemePickerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp moves selection up
+func (m *ThemePickerModel) MoveUp() {
+	if m.selectedIndex > 0 {
+		m.selectedIndex--
+	}
+}
+
+// MoveDown moves selection down
+func (m *ThemePickerModel) MoveDown() {
+	if m.selectedIndex < len(m.themes)-1 {
+		m.selectedIndex++
+	}
+}
+
+// SelectedName returns the highlighted theme name, or "" if there is none
+func (m *ThemePickerModel) SelectedName() string {
+	if m.selectedIndex >= len(m.themes) {
+		return ""
+	}
+	return m.themes[m.selectedIndex].Name
+}
+
+// Original returns the theme that was active when the picker opened
+func (m *ThemePickerModel) Original() string {
+	return m.original
+}
+
+// View renders the theme picker overlay
+func (m *ThemePickerModel) View() string {
+	if m.width == 0 {
+		m.width = 60
+	}
+	if m.height == 0 {
+		m.height = 20
+	}
+
+	t := m.theme
+
+	boxWidth := 56
+	if m.width < 66 {
+		boxWidth = m.width - 10
+	}
+	if boxWidth < 30 {
+		boxWidth = 30
+	}
+
+	var lines []string
+
+	titleStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true).
+		MarginBottom(1)
+	lines = append(lines, titleStyle.Render("Themes"))
+	lines = append(lines, "")
+
+	for i, summary := range m.themes {
+		isSelected := i == m.selectedIndex
+
+		nameStyle := t.Renderer.NewStyle()
+		if isSelected {
+			nameStyle = nameStyle.Foreground(t.Primary).Bold(true)
+		} else {
+			nameStyle = nameStyle.Foreground(t.Base.GetForeground())
+		}
+
+		prefix := "  "
+		if isSelected {
+			prefix = "▸ "
+		}
+		line := prefix + summary.Name
+		if summary.Name == m.original {
+			line += " ●"
+		}
+		if summary.Source != "builtin" {
+			line += " (" + summary.Source + ")"
+		}
+		lines = append(lines, nameStyle.Render(line))
+
+		if summary.Description != "" {
+			descStyle := t.Renderer.NewStyle().
+				Foreground(t.Secondary).
+				Italic(true)
+			lines = append(lines, descStyle.Render("    "+truncateRunesHelper(summary.Description, boxWidth-8, "…")))
+		}
+	}
+
+	// Swatches of the previewed theme's status colors
+	lines = append(lines, "")
+	var swatches []string
+	for _, status := range []string{"open", "in_progress", "blocked", "deferred", "review", "closed"} {
+		swatches = append(swatches, t.Renderer.NewStyle().Foreground(t.GetStatusColor(status)).Render("■ "+status))
+	}
+	lines = append(lines, strings.Join(swatches, " "))
+
+	lines = append(lines, "")
+	footerStyle := t.Renderer.NewStyle().
+		Foreground(ColorFooterHint).
+		Italic(true)
+	lines = append(lines, footerStyle.Render("j/k: preview • enter: use theme • esc: revert"))
+
+	content := strings.Join(lines, "\n")
+
+	boxStyle := t.Renderer.NewStyle().
+		Border(t.PanelBorder).
+		BorderForeground(t.Primary).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
+
+// SelectTheme switches to a theme from the catalog by name, or loads it
+// from a theme file when given a .yaml path.
+func (m *Model) SelectTheme(nameOrPath string) error {
+	name, err := m.themes.Lookup(nameOrPath)
+	if err != nil {
+		return err
+	}
+	p, err := m.themes.Resolve(name)
+	if err != nil {
+		return err
+	}
+	m.setTheme(p)
+	return nil
+}
+
+// setTheme makes p the active palette and rebuilds every style derived from
+// the theme: sub-views keep their own copy of it.
+func (m *Model) setTheme(p Palette) {
+	UseTheme(p)
+	theme := DefaultTheme(m.theme.Renderer)
+	m.theme = theme
+
+	m.board.theme = theme
+	m.labelDashboard.theme = theme
+	m.velocityComparison.theme = theme
+	m.shortcutsSidebar.theme = theme
+	m.graphView.theme = theme
+	m.tree.theme = theme
+	m.flowMatrix.theme = theme
+	m.flowMetrics.theme = theme
+	m.timeline.theme = theme
+	m.forecast.theme = theme
+	m.actionableView.theme = theme
+	m.historyView.theme = theme
+	m.recipePicker.theme = theme
+	m.labelPicker.theme = theme
+	m.repoPicker.theme = theme
+	m.commandPalette.theme = theme
+	m.sessionPicker.theme = theme
+	m.themePicker.theme = theme
+	m.tutorialModel.theme = theme
+
+	m.insightsPanel.theme = theme
+	m.insightsPanel.detailVP.Style = m.insightsPanel.detailVP.Style.BorderForeground(theme.Primary)
+	if m.insightsPanel.mdRenderer != nil {
+		m.insightsPanel.mdRenderer.SetWidthWithTheme(m.insightsPanel.mdRenderer.width, theme)
+	}
+	if m.renderer != nil {
+		m.renderer.SetWidthWithTheme(m.renderer.width, theme)
+	}
+
+	m.updateListDelegate()
+	m.list.Styles.FilterPrompt = lipgloss.NewStyle().Foreground(theme.Primary)
+	m.list.Styles.FilterCursor = lipgloss.NewStyle().Foreground(theme.Primary)
+	m.timeTravelInput.PromptStyle = lipgloss.NewStyle().Foreground(theme.Primary).Bold(true)
+}
+
+// openThemePicker shows the theme switcher over the current view.
+func (m *Model) openThemePicker() {
+	m.themePicker = NewThemePickerModel(m.themes.List(), m.theme.Name, m.theme)
+	m.themePicker.SetSize(m.width, m.height-1)
+	m.themeReturn = m.focused
+	m.showThemePicker = true
+	m.focused = focusThemePicker
+}
+
+// closeThemePicker hides the picker and returns focus to the view below.
+func (m *Model) closeThemePicker() {
+	m.showThemePicker = false
+	m.focused = m.themeReturn
+}
+
+// handleThemePickerKeys handles keyboard input when the theme picker is
+// focused. Every cursor move previews the highlighted theme.
+func (m Model) handleThemePickerKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "j", "down":
+		m.themePicker.MoveDown()
+	case "k", "up":
+		m.themePicker.MoveUp()
+	case "esc", "q", "ctrl+t":
+		m.closeThemePicker()
+		if err := m.SelectTheme(m.themePicker.Original()); err != nil {
+			m.statusMsg = err.Error()
+			m.statusIsError = true
+		}
+		return m
+	case "enter":
+		m.closeThemePicker()
+		m.statusMsg = fmt.Sprintf("Theme: %s", m.theme.Name)
+		m.statusIsError = false
+		return m
+	default:
+		return m
+	}
+
+	if err := m.SelectTheme(m.themePicker.SelectedName()); err != nil {
+		m.statusMsg = err.Error()
+		m.statusIsError = true
+	}
+	return m
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var keyCtrlT = tea.KeyMsg{Type: tea.KeyCtrlT}
+
+func TestThemePickerPreviewsAndReverts(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	t.Cleanup(func() { UseTheme(Palette{}) })
+	m := NewModel(issues, nil, "")
+	m = sendKeys(t, m, runeKey("b"))
+
+	m = sendKeys(t, m, keyCtrlT)
+	if m.FocusState() != "theme_picker" || m.CurrentContext() != ContextThemePicker {
+		t.Fatalf("ctrl+t should open the picker, focus %s", m.FocusState())
+	}
+	if !strings.Contains(m.View(), "default ●") {
+		t.Error("picker should mark the active theme")
+	}
+
+	m = sendKeys(t, m, runeKey("j"))
+	if m.theme.Name != "colorblind-safe" || m.board.theme.Name != "colorblind-safe" {
+		t.Fatalf("moving should preview, theme %s", m.theme.Name)
+	}
+	if ActivePalette().Name != "colorblind-safe" {
+		t.Errorf("active palette = %s", ActivePalette().Name)
+	}
+
+	m = sendKeys(t, m, keyEsc)
+	if m.showThemePicker || m.theme.Name != DefaultThemeName || m.FocusState() != "board" {
+		t.Fatalf("esc should revert, theme %s focus %s", m.theme.Name, m.FocusState())
+	}
+
+	m = sendKeys(t, m, keyCtrlT, runeKey("j"), runeKey("j"), keyEnter)
+	if m.showThemePicker || m.theme.Name != "gruvbox" || !strings.Contains(m.statusMsg, "gruvbox") {
+		t.Errorf("enter should keep gruvbox, theme %s status %q", m.theme.Name, m.statusMsg)
+	}
+
+	if err := m.SelectTheme("nope"); err == nil {
+		t.Error("unknown theme should be an error")
+	}
+}
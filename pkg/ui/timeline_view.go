@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TimelineModel is a Gantt view of the open issues: each row is an issue's
+// ETA bar on a calendar axis, followed by its slack. Critical path bars are
+// highlighted and due dates the forecast misses are flagged.
+type TimelineModel struct {
+	timeline     analysis.Timeline
+	cursor       int
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// NewTimelineModel creates an empty timeline view
+func NewTimelineModel(theme Theme) TimelineModel {
+	return TimelineModel{theme: theme}
+}
+
+// SetData schedules the open issues from the execution plan and ETA
+// estimates. stats may be nil.
+func (m *TimelineModel) SetData(issues []model.Issue, stats *analysis.GraphStats) {
+	plan := analysis.NewAnalyzer(issues).GetExecutionPlan()
+	m.timeline = analysis.BuildTimeline(issues, stats, plan, analysis.TimelineOptions{Now: time.Now()})
+	if m.cursor >= len(m.timeline.Items) {
+		m.cursor = max(len(m.timeline.Items)-1, 0)
+	}
+	m.ensureVisible()
+}
+
+// SetSize updates the view dimensions
+func (m *TimelineModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.ensureVisible()
+}
+
+// Timeline returns the computed schedule
+func (m *TimelineModel) Timeline() analysis.Timeline {
+	return m.timeline
+}
+
+// SelectedIssueID returns the issue under the cursor, or "" if there is none
+func (m *TimelineModel) SelectedIssueID() string {
+	if m.cursor >= len(m.timeline.Items) {
+		return ""
+	}
+	return m.timeline.Items[m.cursor].ID
+}
+
+// Update handles navigation keys; returns the selected issue ID on enter
+func (m *TimelineModel) Update(msg tea.KeyMsg) string {
+	last := max(len(m.timeline.Items)-1, 0)
+	switch msg.String() {
+	case "j", "down":
+		if m.cursor < last {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "ctrl+d", "pgdown":
+		m.cursor = min(m.cursor+m.visibleRowCount(), last)
+	case "ctrl+u", "pgup":
+		m.cursor = max(m.cursor-m.visibleRowCount(), 0)
+	case "g", "home":
+		m.cursor = 0
+	case "G", "end":
+		m.cursor = last
+	case "enter":
+		return m.SelectedIssueID()
+	}
+	m.ensureVisible()
+	return ""
+}
+
+// ensureVisible adjusts scroll offset to keep cursor visible
+func (m *TimelineModel) ensureVisible() {
+	visibleRows := m.visibleRowCount()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	} else if m.cursor >= m.scrollOffset+visibleRows {
+		m.scrollOffset = m.cursor - visibleRows + 1
+	}
+}
+
+// visibleRowCount returns how many issue rows fit between the summary,
+// axis, detail line and footer
+func (m *TimelineModel) visibleRowCount() int {
+	available := m.height - 10
+	if available < 1 {
+		return 1
+	}
+	return available
+}
+
+// timelineCell is one column of a row's bar
+type timelineCell int
+
+const (
+	cellEmpty timelineCell = iota
+	cellBar
+	cellSlack
+	cellDue
+	cellMissedDue
+)
+
+// timelineScale maps calendar time to columns of the chart area
+type timelineScale struct {
+	start   time.Time
+	daysPer float64 // Days covered by one column
+	cols    int
+}
+
+func (s timelineScale) col(t time.Time) int {
+	return int(math.Floor(t.Sub(s.start).Hours() / 24 / s.daysPer))
+}
+
+// rowCells lays out one item's bar, slack and due marker
+func (s timelineScale) rowCells(it analysis.TimelineItem) []timelineCell {
+	cells := make([]timelineCell, s.cols)
+	set := func(from, to int, c timelineCell) {
+		for i := max(from, 0); i < min(to, s.cols); i++ {
+			cells[i] = c
+		}
+	}
+	start, end := s.col(it.Start), s.col(it.End)
+	if end <= start {
+		end = start + 1 // Even short estimates get a visible bar
+	}
+	set(start, end, cellBar)
+	set(end, s.col(it.SlackEnd()), cellSlack)
+	if it.DueDate != nil {
+		due := min(max(s.col(*it.DueDate), 0), s.cols-1)
+		if it.MissesDue {
+			cells[due] = cellMissedDue
+		} else {
+			cells[due] = cellDue
+		}
+	}
+	return cells
+}
+
+// View renders the timeline
+func (m *TimelineModel) View() string {
+	if m.width == 0 {
+		m.width = 80
+	}
+	if m.height == 0 {
+		m.height = 20
+	}
+
+	t := m.theme
+	r := t.Renderer
+	tl := m.timeline
+	dimStyle := r.NewStyle().Foreground(t.Secondary).Italic(true)
+	labelStyle := r.NewStyle().Foreground(t.Secondary).Bold(true)
+
+	var sb strings.Builder
+
+	sb.WriteString(r.NewStyle().Foreground(t.Primary).Bold(true).Render("Timeline"))
+	sb.WriteString(dimStyle.Render(fmt.Sprintf("  %s → %s • %d open • %d on the critical path",
+		tl.Start.Format("Jan 02"), tl.End.Format("Jan 02, 2006"), len(tl.Items), len(tl.CriticalPath))))
+	if tl.MissedDue > 0 {
+		sb.WriteString(r.NewStyle().Foreground(ColorDanger).Bold(true).Render(fmt.Sprintf(" • %d due dates missed", tl.MissedDue)))
+	}
+	if tl.Cycles {
+		sb.WriteString(r.NewStyle().Foreground(ColorWarning).Render(" • cycles broken"))
+	}
+	sb.WriteString("\n\n")
+
+	if len(tl.Items) == 0 {
+		sb.WriteString(dimStyle.Render("  No open issues to schedule"))
+		sb.WriteString("\n\n")
+		sb.WriteString(r.NewStyle().Foreground(ColorFooterHint).Italic(true).Render("esc: back"))
+		return sb.String()
+	}
+
+	labelWidth := 30
+	if m.width < 90 {
+		labelWidth = max(m.width/3, 14)
+	}
+	chartCols := max(m.width-labelWidth-4, 10)
+
+	// Fit the whole span, including due dates past the last finish
+	end := tl.End
+	for _, it := range tl.Items {
+		if it.DueDate != nil && it.DueDate.After(end) {
+			end = *it.DueDate
+		}
+	}
+	spanDays := math.Max(end.Sub(tl.Start).Hours()/24, 1)
+	scale := timelineScale{start: tl.Start, daysPer: spanDays / float64(chartCols-1), cols: chartCols}
+
+	// Calendar axis: a date label every ~12 columns
+	axis := []rune(strings.Repeat(" ", chartCols))
+	for c := 0; c+6 <= chartCols; c += 12 {
+		day := tl.Start.Add(durationFromDays(float64(c) * scale.daysPer))
+		copy(axis[c:], []rune(day.Format("Jan 02")))
+	}
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("  %-*s", labelWidth, "Issue")))
+	sb.WriteString(labelStyle.Render("│" + string(axis)))
+	sb.WriteString("\n")
+
+	barEnd := min(m.scrollOffset+m.visibleRowCount(), len(tl.Items))
+	for i := m.scrollOffset; i < barEnd; i++ {
+		it := tl.Items[i]
+		prefix := "  "
+		rowStyle := r.NewStyle().Foreground(t.Base.GetForeground())
+		if i == m.cursor {
+			prefix = "> "
+			rowStyle = rowStyle.Foreground(t.Primary).Bold(true).Background(ThemeBg("#333"))
+		}
+		marker := " "
+		if it.Critical {
+			marker = r.NewStyle().Foreground(ColorDanger).Bold(true).Render("!")
+		}
+		label := truncateRunesHelper(it.ID+" "+it.Title, labelWidth-1, "…")
+		sb.WriteString(rowStyle.Render(prefix))
+		sb.WriteString(marker)
+		sb.WriteString(rowStyle.Render(fmt.Sprintf("%-*s", labelWidth-1, label)))
+		sb.WriteString(dimStyle.Render("│"))
+		sb.WriteString(m.renderCells(scale.rowCells(it), it))
+		sb.WriteString("\n")
+	}
+	if len(tl.Items) > m.visibleRowCount() {
+		sb.WriteString(dimStyle.Render(fmt.Sprintf("  [%d-%d of %d]", m.scrollOffset+1, barEnd, len(tl.Items))))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(m.renderDetail())
+	sb.WriteString("\n\n")
+
+	legend := r.NewStyle().Foreground(t.Open).Render("█") + " estimate  " +
+		r.NewStyle().Foreground(ColorDanger).Render("█") + " critical  " +
+		r.NewStyle().Foreground(t.Secondary).Render("░") + " slack  " +
+		r.NewStyle().Foreground(t.Primary).Render("◆") + " due  " +
+		r.NewStyle().Foreground(ColorDanger).Render("◆") + " missed"
+	sb.WriteString(legend)
+	sb.WriteString("\n")
+	footerStyle := r.NewStyle().Foreground(ColorFooterHint).Italic(true)
+	sb.WriteString(footerStyle.Render("j/k: navigate | ^d/^u: page | enter: open issue | esc: back"))
+
+	return sb.String()
+}
+
+// renderCells styles runs of identical cells so a row costs a handful of
+// escape sequences rather than one per column
+func (m *TimelineModel) renderCells(cells []timelineCell, it analysis.TimelineItem) string {
+	r := m.theme.Renderer
+	barColor := m.theme.GetStatusColor(it.Status)
+	if it.Critical {
+		barColor = ColorDanger
+	}
+	styles := map[timelineCell]lipgloss.Style{
+		cellBar:       r.NewStyle().Foreground(barColor),
+		cellSlack:     r.NewStyle().Foreground(m.theme.Secondary),
+		cellDue:       r.NewStyle().Foreground(m.theme.Primary).Bold(true),
+		cellMissedDue: r.NewStyle().Foreground(ColorDanger).Bold(true),
+	}
+	glyphs := map[timelineCell]string{cellEmpty: " ", cellBar: "█", cellSlack: "░", cellDue: "◆", cellMissedDue: "◆"}
+
+	var sb strings.Builder
+	for i := 0; i < len(cells); {
+		j := i
+		for j < len(cells) && cells[j] == cells[i] {
+			j++
+		}
+		run := strings.Repeat(glyphs[cells[i]], j-i)
+		if style, ok := styles[cells[i]]; ok {
+			run = style.Render(run)
+		}
+		sb.WriteString(run)
+		i = j
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// renderDetail describes the selected item's dates, slack and blockers
+func (m *TimelineModel) renderDetail() string {
+	if m.cursor >= len(m.timeline.Items) {
+		return ""
+	}
+	t := m.theme
+	r := t.Renderer
+	it := m.timeline.Items[m.cursor]
+
+	parts := []string{
+		fmt.Sprintf("%s → %s (%.1fd)", it.Start.Format("Jan 02"), it.End.Format("Jan 02"), it.Days),
+	}
+	if it.Critical {
+		parts = append(parts, "critical")
+	} else {
+		parts = append(parts, fmt.Sprintf("slack %.1fd", it.SlackDays))
+	}
+	if it.DueDate != nil {
+		due := "due " + it.DueDate.Format("Jan 02")
+		if it.MissesDue {
+			due += fmt.Sprintf(" (%.1fd late)", it.End.Sub(*it.DueDate).Hours()/24)
+		}
+		parts = append(parts, due)
+	}
+	if it.Track != "" {
+		parts = append(parts, it.Track)
+	}
+	if len(it.BlockedBy) > 0 {
+		parts = append(parts, "after "+strings.Join(it.BlockedBy, ", "))
+	}
+
+	style := r.NewStyle().Foreground(t.Base.GetForeground())
+	if it.MissesDue || it.Critical {
+		style = r.NewStyle().Foreground(ColorDanger)
+	}
+	label := r.NewStyle().Foreground(t.Secondary).Bold(true).Render(it.ID + ": ")
+	return label + style.Render(truncateRunesHelper(strings.Join(parts, " • "), max(m.width-len(it.ID)-2, 10), "…"))
+}
+
+// durationFromDays converts fractional days to a Duration
+func durationFromDays(days float64) time.Duration {
+	return time.Duration(days * 24 * float64(time.Hour))
+}
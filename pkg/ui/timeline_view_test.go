@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTimelineRowCells(t *testing.T) {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	day := func(d float64) time.Time { return start.Add(durationFromDays(d)) }
+	due := day(3)
+	scale := timelineScale{start: start, daysPer: 1, cols: 10}
+
+	cells := scale.rowCells(analysis.TimelineItem{Start: day(1), End: day(4), SlackDays: 2, DueDate: &due, MissesDue: true})
+	want := []timelineCell{cellEmpty, cellBar, cellBar, cellMissedDue, cellSlack, cellSlack, cellEmpty, cellEmpty, cellEmpty, cellEmpty}
+	for i := range want {
+		if cells[i] != want[i] {
+			t.Fatalf("cells = %v, want %v", cells, want)
+		}
+	}
+
+	// Zero-length estimates still show one column; past-the-edge due dates clamp
+	late := day(40)
+	cells = scale.rowCells(analysis.TimelineItem{Start: day(2), End: day(2), DueDate: &late})
+	if cells[2] != cellBar || cells[3] != cellEmpty || cells[9] != cellDue {
+		t.Errorf("cells = %v", cells)
+	}
+}
+
+func TestTimelineViewNavigation(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	m := NewTimelineModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+	m.SetData(issues, nil)
+	m.SetSize(120, 30)
+
+	tl := m.Timeline()
+	if len(tl.Items) != 3 || strings.Join(tl.CriticalPath, ",") != "s-1,s-2" {
+		t.Fatalf("items %d, critical path %v", len(tl.Items), tl.CriticalPath)
+	}
+
+	out := m.View()
+	for _, want := range []string{"Timeline", "s-1 First", "s-3 Third", "critical", "enter: open issue"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if got := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); got != tl.Items[1].ID {
+		t.Errorf("enter returned %q, want %s", got, tl.Items[1].ID)
+	}
+
+	empty := NewTimelineModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+	empty.SetData([]model.Issue{{ID: "x", Status: model.StatusClosed}}, nil)
+	if !strings.Contains(empty.View(), "No open issues") || empty.SelectedIssueID() != "" {
+		t.Error("an all-closed project should have an empty timeline")
+	}
+}
+
+func TestTimelineOpensAndJumpsToIssue(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "s-1", Title: "First", Status: model.StatusOpen, Priority: 1},
+		{ID: "s-2", Title: "Second", Status: model.StatusOpen, Priority: 2,
+			Dependencies: []*model.Dependency{{IssueID: "s-2", DependsOnID: "s-1", Type: model.DepBlocks}}},
+		{ID: "s-3", Title: "Third", Status: model.StatusInProgress, Priority: 2},
+		{ID: "s-4", Title: "Fourth", Status: model.StatusClosed, Priority: 3},
+	}
+	m := NewModel(issues, nil, "")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m = sendKeys(t, m, runeKey("Z"))
+	if m.focused != focusTimeline || m.FocusState() != "timeline" {
+		t.Fatalf("Z should open the timeline, focus %s", m.FocusState())
+	}
+
+	m = sendKeys(t, m, runeKey("j"))
+	want := m.timeline.SelectedIssueID()
+	m = sendKeys(t, m, keyEnter)
+	if m.focused != focusDetail || m.cursorIssueID() != want {
+		t.Errorf("enter should open %s in the detail view, got %s (focus %s)", want, m.cursorIssueID(), m.FocusState())
+	}
+
+	m = sendKeys(t, m, runeKey("Z"), keyEsc)
+	if m.focused != focusList {
+		t.Errorf("esc should close the timeline, focus %s", m.FocusState())
+	}
+}
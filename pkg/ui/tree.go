@@ -193,6 +193,8 @@ type TreeModel struct {
 
 	// Persistence state (bv-19vz)
 	beadsDir string // Directory containing .beads (for tree-state.json)
+
+	marks *Selection // Issues marked for batch actions, shared with the list and board
 }
 
 // NewTreeModel creates an empty tree model
@@ -563,6 +565,17 @@ func (t *TreeModel) renderNode(node *IssueTreeNode, isSelected bool) string {
 	r := t.theme.Renderer
 	var sb strings.Builder
 
+	// Mark column, only while something is marked so the tree keeps its
+	// usual layout otherwise
+	if t.marks.Len() > 0 {
+		if t.marks.Has(issue.ID) {
+			sb.WriteString(r.NewStyle().Foreground(t.theme.Primary).Bold(true).Render("✓"))
+			sb.WriteString(" ")
+		} else {
+			sb.WriteString("  ")
+		}
+	}
+
 	// Build the tree prefix (indentation + branch characters)
 	prefix := t.buildTreePrefix(node)
 	sb.WriteString(prefix)
@@ -599,6 +612,9 @@ func (t *TreeModel) renderNode(node *IssueTreeNode, isSelected bool) string {
 	title := issue.Title
 	// Use lipgloss.Width for proper display width (handles ANSI codes + Unicode)
 	maxTitleLen := t.width - lipgloss.Width(prefix) - 25 // Account for prefix, indicator, icon, priority, ID
+	if t.marks.Len() > 0 {
+		maxTitleLen -= 2
+	}
 	if maxTitleLen < 20 {
 		maxTitleLen = 20
 	}
@@ -735,6 +751,29 @@ func (t *TreeModel) GetPriorityColor(priority int) lipgloss.AdaptiveColor {
 	}
 }
 
+// SetSelection shares the marked-issue set shown in the mark column.
+func (t *TreeModel) SetSelection(s *Selection) {
+	t.marks = s
+}
+
+// AllIssueIDs returns the IDs of every issue in the tree, collapsed
+// branches included, in tree order.
+func (t *TreeModel) AllIssueIDs() []string {
+	var ids []string
+	var walk func(nodes []*IssueTreeNode)
+	walk = func(nodes []*IssueTreeNode) {
+		for _, n := range nodes {
+			if n == nil || n.Issue == nil {
+				continue
+			}
+			ids = append(ids, n.Issue.ID)
+			walk(n.Children)
+		}
+	}
+	walk(t.roots)
+	return ids
+}
+
 // SelectedIssue returns the currently selected issue, or nil if none.
 func (t *TreeModel) SelectedIssue() *model.Issue {
 	if t.cursor >= 0 && t.cursor < len(t.flatList) {
@@ -681,7 +681,8 @@ func structuredTutorialPages() []StructuredTutorialPage {
 				Section{Title: "Navigation"},
 				KeyTable{Bindings: []KeyBinding{
 					{Key: "w", Desc: "Toggle workspace picker"},
-					{Key: "W", Desc: "Workspace-wide search"},
+					{Key: "W", Desc: "Saved sessions (named view layouts)"},
+					{Key: "Ctrl+t", Desc: "Switch theme (live preview)"},
 				}},
 				Spacer{Lines: 1},
 				Section{Title: "Cross-Repo Dependencies"},
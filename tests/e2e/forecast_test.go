@@ -166,3 +166,40 @@ func mustParseRFC3339(t *testing.T, s string) time.Time {
 	}
 	return parsed
 }
+
+func TestRobotForecast_MonteCarloIsSeeded(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, _ := createForecastRepo(t)
+
+	run := func(args ...string) map[string]any {
+		cmd := exec.Command(bv, append([]string{"--robot-forecast", "all", "--forecast-label", "backend"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("--robot-forecast failed: %v\n%s", err, out)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json decode: %v\nout=%s", err, out)
+		}
+		return payload
+	}
+
+	a := run("--forecast-seed", "3")["monte_carlo"].(map[string]any)
+	b := run("--forecast-seed", "3")["monte_carlo"].(map[string]any)
+	if a["scope"] != "label" || a["target"] != "backend" || a["trials"].(float64) != 1000 {
+		t.Fatalf("unexpected monte_carlo header: %v", a)
+	}
+	for _, key := range []string{"p50_days", "p85_days", "p95_days"} {
+		if a[key] != b[key] {
+			t.Fatalf("%s differs between runs with the same seed: %v vs %v", key, a[key], b[key])
+		}
+	}
+	if a["p50_days"].(float64) > a["p95_days"].(float64) {
+		t.Fatalf("p50 after p95: %v", a)
+	}
+
+	if _, ok := run("--forecast-trials", "0")["monte_carlo"]; ok {
+		t.Fatal("--forecast-trials=0 should omit monte_carlo")
+	}
+}
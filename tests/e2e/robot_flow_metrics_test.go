@@ -0,0 +1,64 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+)
+
+func TestRobotFlowMetricsUsesGitStatusHistory(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, _ := createHistoryRepo(t)
+
+	cmd := exec.Command(bv, "--robot-flow-metrics", "--flow-weeks", "4")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-flow-metrics failed: %v\n%s", err, out)
+	}
+
+	type percentiles struct {
+		Count int     `json:"count"`
+		P85   float64 `json:"p85_hours"`
+	}
+	type slice struct {
+		Issues     int         `json:"issues"`
+		LeadTime   percentiles `json:"lead_time"`
+		CycleTime  percentiles `json:"cycle_time"`
+		Throughput struct {
+			Closed int `json:"closed"`
+			Weekly []struct {
+				Closed int `json:"closed"`
+			} `json:"weekly"`
+		} `json:"throughput"`
+	}
+	var payload struct {
+		DataHash     string           `json:"data_hash"`
+		StatusSource string           `json:"status_source"`
+		Weeks        int              `json:"weeks"`
+		Overall      slice            `json:"overall"`
+		ByType       map[string]slice `json:"by_type"`
+		ByPriority   map[string]slice `json:"by_priority"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\n%s", err, out)
+	}
+
+	if payload.DataHash == "" || payload.StatusSource != "git" || payload.Weeks != 4 {
+		t.Fatalf("unexpected header: %s", out)
+	}
+	overall := payload.Overall
+	if overall.Issues != 1 || overall.Throughput.Closed != 1 || len(overall.Throughput.Weekly) != 4 {
+		t.Errorf("unexpected overall: %+v", overall)
+	}
+	// HIST-1 has no closed_at; the close time and first in_progress come from git.
+	if overall.LeadTime.Count != 1 || overall.CycleTime.Count != 1 {
+		t.Errorf("expected lead and cycle samples from history: %+v", overall)
+	}
+	if task := payload.ByType["task"]; task.CycleTime.Count != 1 {
+		t.Errorf("task slice missing cycle time: %+v", task)
+	}
+	if _, ok := payload.ByPriority["P1"]; !ok {
+		t.Errorf("missing P1 slice: %s", out)
+	}
+}
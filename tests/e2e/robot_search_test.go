@@ -81,3 +81,109 @@ func TestRobotSearchContract(t *testing.T) {
 		t.Fatalf("expected usage_hints")
 	}
 }
+
+func TestRobotSearchStructuredQuery(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Login timeout","status":"open","priority":1,"issue_type":"bug","labels":["backend"]}
+{"id":"B","title":"Login page copy","status":"closed","priority":2,"issue_type":"task","labels":["frontend"]}
+{"id":"C","title":"Metrics export","status":"open","priority":0,"issue_type":"task","labels":["backend","wontfix"]}
+{"id":"D","title":"Blocked on login","status":"blocked","priority":2,"issue_type":"task","dependencies":[{"issue_id":"D","depends_on_id":"A","type":"blocks"}]}`)
+
+	run := func(query string) (ids []string, parsed bool) {
+		t.Helper()
+		cmd := exec.Command(bv, "--search", query, "--robot-search")
+		cmd.Dir = env
+		cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("robot-search %q failed: %v\n%s", query, err, out)
+		}
+		var payload struct {
+			ParsedQuery *struct {
+				Filters []struct {
+					Field string `json:"field"`
+				} `json:"filters"`
+			} `json:"parsed_query"`
+			Results []struct {
+				IssueID string `json:"issue_id"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json decode: %v\n%s", err, out)
+		}
+		for _, r := range payload.Results {
+			ids = append(ids, r.IssueID)
+		}
+		return ids, payload.ParsedQuery != nil
+	}
+
+	ids, parsed := run("status:open label:backend -label:wontfix")
+	if !parsed || len(ids) != 1 || ids[0] != "A" {
+		t.Fatalf("expected only A with parsed_query, got %v (parsed=%v)", ids, parsed)
+	}
+	ids, _ = run("login -status:closed")
+	for _, id := range ids {
+		if id == "B" {
+			t.Fatalf("filtered issue %s leaked into %v", id, ids)
+		}
+	}
+	if len(ids) == 0 || ids[0] != "A" {
+		t.Fatalf("expected A to rank first for login, got %v", ids)
+	}
+	ids, _ = run("blocks:D")
+	if len(ids) != 1 || ids[0] != "A" {
+		t.Fatalf("expected A for blocks:D, got %v", ids)
+	}
+
+	cmd := exec.Command(bv, "--search", "priority:high", "--robot-search")
+	cmd.Dir = env
+	cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("expected invalid filter to fail, got %s", out)
+	}
+}
+
+func TestRobotSearchReturnsMatchingChunk(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Auth flakiness","description":"Users get logged out.","status":"open","priority":1,"issue_type":"bug","comments":[{"id":1,"issue_id":"A","author":"carol","text":"Could be the proxy"},{"id":2,"issue_id":"A","author":"dave","text":"Root cause: the oauth refresh token expires early"}]}
+{"id":"B","title":"Settings page","description":"Add a page.","status":"open","priority":2,"issue_type":"task"}`)
+
+	cmd := exec.Command(bv, "--search", "oauth token", "--robot-search")
+	cmd.Dir = env
+	cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("robot-search failed: %v\n%s", err, out)
+	}
+	var payload struct {
+		Results []struct {
+			IssueID string `json:"issue_id"`
+			Match   *struct {
+				ChunkID    string   `json:"chunk_id"`
+				Field      string   `json:"field"`
+				Comment    int      `json:"comment"`
+				Author     string   `json:"author"`
+				Snippet    string   `json:"snippet"`
+				Highlights [][2]int `json:"highlights"`
+			} `json:"match"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\n%s", err, out)
+	}
+	if len(payload.Results) == 0 || payload.Results[0].IssueID != "A" {
+		t.Fatalf("expected A first: %s", out)
+	}
+	m := payload.Results[0].Match
+	if m == nil {
+		t.Fatalf("missing match: %s", out)
+	}
+	if m.Field != "comment" || m.Comment != 2 || m.Author != "dave" || m.ChunkID != "A#comment:1" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if len(m.Highlights) != 2 || m.Snippet[m.Highlights[0][0]:m.Highlights[0][1]] != "oauth" {
+		t.Errorf("unexpected highlights %v in %q", m.Highlights, m.Snippet)
+	}
+}
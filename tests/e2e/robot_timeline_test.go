@@ -0,0 +1,69 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRobotTimelineFieldChanges(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, head := createHistoryRepo(t)
+
+	cmd := exec.Command(bv, "--robot-timeline", "HIST-1")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-timeline failed: %v\n%s", err, out)
+	}
+
+	var payload struct {
+		DataHash string `json:"data_hash"`
+		Head     string `json:"head"`
+		IssueID  string `json:"issue_id"`
+		Events   []struct {
+			Kind      string `json:"kind"`
+			CommitSHA string `json:"commit_sha"`
+			Author    string `json:"author"`
+			Changes   []struct {
+				Field string `json:"field"`
+				From  any    `json:"from"`
+				To    any    `json:"to"`
+			} `json:"changes"`
+		} `json:"events"`
+		TimeInStatus map[string]float64 `json:"time_in_status_hours"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\n%s", err, out)
+	}
+
+	if payload.DataHash == "" || payload.Head != head || payload.IssueID != "HIST-1" {
+		t.Fatalf("unexpected envelope: %s", out)
+	}
+	if len(payload.Events) != 3 {
+		t.Fatalf("expected created + 2 status changes, got %d events\n%s", len(payload.Events), out)
+	}
+	if payload.Events[0].Kind != "created" || payload.Events[0].Author != "Test" {
+		t.Errorf("first event should be the creation: %+v", payload.Events[0])
+	}
+	last := payload.Events[2]
+	if last.CommitSHA != head || len(last.Changes) != 1 || last.Changes[0].Field != "status" ||
+		last.Changes[0].From != "in_progress" || last.Changes[0].To != "closed" {
+		t.Errorf("last event should close the issue at HEAD: %+v", last)
+	}
+	if _, ok := payload.TimeInStatus["closed"]; !ok {
+		t.Errorf("time in status missing closed: %v", payload.TimeInStatus)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, ".bv", "timeline.json")); err != nil {
+		t.Errorf("timeline cache not written: %v", err)
+	}
+
+	// Unknown issues are an error.
+	cmd = exec.Command(bv, "--robot-timeline", "NOPE-1")
+	cmd.Dir = repoDir
+	if err := cmd.Run(); err == nil {
+		t.Error("expected --robot-timeline to fail for an unknown issue")
+	}
+}
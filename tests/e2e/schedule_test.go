@@ -0,0 +1,69 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRobotSchedule_UsesTeamRoster(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, _ := createForecastRepo(t)
+
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		cmd := exec.Command(bv, append([]string{"--robot-schedule"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--robot-schedule %v failed: %v\n%s", args, err, out)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json decode: %v\nout=%s", err, out)
+		}
+		return payload
+	}
+
+	// Without a roster, --agents generalists share the work
+	payload := run("--agents", "2")
+	if payload["roster"] != "default" {
+		t.Fatalf("roster = %v, want default", payload["roster"])
+	}
+	sched := payload["schedule"].(map[string]any)
+	if len(sched["members"].([]any)) != 2 || len(sched["assignments"].([]any)) != 2 {
+		t.Fatalf("unexpected default schedule: %v", sched)
+	}
+
+	// With one, each issue goes to the member whose skills match its label
+	bvDir := filepath.Join(repoDir, ".bv")
+	if err := os.MkdirAll(bvDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	roster := "members:\n  - name: ana\n    skills: [backend]\n  - name: fay\n    skills: [frontend]\n"
+	if err := os.WriteFile(filepath.Join(bvDir, "team.yaml"), []byte(roster), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	payload = run()
+	if payload["roster"] != filepath.Join(repoDir, ".bv", "team.yaml") {
+		t.Errorf("roster = %v", payload["roster"])
+	}
+	sched = payload["schedule"].(map[string]any)
+	assignees := make(map[string]string)
+	for _, a := range sched["assignments"].([]any) {
+		item := a.(map[string]any)
+		assignees[item["id"].(string)] = item["assignee"].(string)
+	}
+	if assignees["OPEN-1"] != "ana" || assignees["OPEN-2"] != "fay" {
+		t.Errorf("assignees = %v", assignees)
+	}
+
+	// A --team path that doesn't exist is an error rather than a silent default
+	cmd := exec.Command(bv, "--robot-schedule", "--team", filepath.Join(repoDir, "missing.yaml"))
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected missing --team to fail, got %s", out)
+	}
+}
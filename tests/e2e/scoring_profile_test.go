@@ -0,0 +1,62 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRobotTriage_ScoringProfile(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, _ := createForecastRepo(t)
+
+	bvDir := filepath.Join(repoDir, ".bv")
+	if err := os.MkdirAll(bvDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	body := "profiles:\n  ui-first:\n    description: Frontend polish sprint\n    label_multipliers: {frontend: 5}\n"
+	if err := os.WriteFile(filepath.Join(bvDir, "scoring.yaml"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		cmd := exec.Command(bv, args...)
+		cmd.Dir = repoDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json decode: %v\nout=%s", err, out)
+		}
+		return payload
+	}
+
+	triage := run("--robot-triage")["triage"].(map[string]any)
+	if profile := triage["meta"].(map[string]any)["profile"]; profile != "default" {
+		t.Errorf("default meta.profile = %v", profile)
+	}
+
+	triage = run("--robot-triage", "--profile", "ui-first")["triage"].(map[string]any)
+	if profile := triage["meta"].(map[string]any)["profile"]; profile != "ui-first" {
+		t.Errorf("meta.profile = %v", profile)
+	}
+	recs := triage["recommendations"].([]any)
+	if len(recs) == 0 || recs[0].(map[string]any)["id"] != "OPEN-2" {
+		t.Errorf("ui-first should put OPEN-2 first: %v", recs)
+	}
+
+	if profile := run("--robot-priority", "--profile", "unblock")["profile"]; profile != "unblock" {
+		t.Errorf("--robot-priority profile = %v", profile)
+	}
+
+	cmd := exec.Command(bv, "--robot-triage", "--profile", "nope")
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected an unknown profile to fail, got %s", out)
+	}
+}
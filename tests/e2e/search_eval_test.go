@@ -0,0 +1,87 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearchEvalCommand(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Login timeout on slow networks","status":"open","priority":1,"issue_type":"bug"}
+{"id":"B","title":"Export metrics to CSV","status":"open","priority":2,"issue_type":"feature"}
+{"id":"C","title":"Docs for login flow","status":"closed","priority":3,"issue_type":"task"}`)
+	queries := filepath.Join(env, "judged.yaml")
+	if err := os.WriteFile(queries, []byte(`queries:
+  - query: login timeout
+    relevant: [A]
+  - query: csv export
+    relevant: [B]
+  - query: login docs
+    relevant: [C, MISSING-1]
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bv, "search-eval", queries, "--json", "--grid-step", "0.25")
+	cmd.Dir = env
+	cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("search-eval failed: %v\n%s", err, out)
+	}
+	var payload struct {
+		DataHash   string   `json:"data_hash"`
+		Queries    int      `json:"queries"`
+		K          int      `json:"k"`
+		UnknownIDs []string `json:"unknown_ids"`
+		Results    []struct {
+			Name   string  `json:"name"`
+			MRR    float64 `json:"mrr"`
+			NDCG   float64 `json:"ndcg"`
+			Recall float64 `json:"recall"`
+		} `json:"results"`
+		BestPreset string `json:"best_preset"`
+		GridTried  int    `json:"grid_evaluated"`
+		Suggestion string `json:"suggestion"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\n%s", err, out)
+	}
+	if payload.DataHash == "" || payload.Queries != 3 || payload.K != 10 {
+		t.Fatalf("unexpected header: %s", out)
+	}
+	if len(payload.UnknownIDs) != 1 || payload.UnknownIDs[0] != "MISSING-1" {
+		t.Errorf("expected MISSING-1 reported, got %v", payload.UnknownIDs)
+	}
+	// text + 5 presets + grid
+	if len(payload.Results) != 7 || payload.Results[0].Name != "text" || payload.Results[6].Name != "grid" {
+		t.Fatalf("unexpected rows: %s", out)
+	}
+	if payload.Results[0].MRR != 1 {
+		t.Errorf("every query's first judged issue should rank first in text mode: %+v", payload.Results[0])
+	}
+	if payload.BestPreset == "" || payload.GridTried == 0 || !strings.HasPrefix(payload.Suggestion, "BV_SEARCH_MODE=") {
+		t.Errorf("missing recommendation: %s", out)
+	}
+
+	table := exec.Command(bv, "search-eval", queries, "--no-grid")
+	table.Dir = env
+	table.Env = cmd.Env
+	text, err := table.Output()
+	if err != nil {
+		t.Fatalf("search-eval table failed: %v\n%s", err, text)
+	}
+	for _, want := range []string{"nDCG@10", "hybrid:impact-first", "Best preset:", "Suggestion:"} {
+		if !strings.Contains(string(text), want) {
+			t.Errorf("table missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(string(text), "grid") {
+		t.Errorf("--no-grid should skip the grid row:\n%s", text)
+	}
+}
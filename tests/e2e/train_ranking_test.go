@@ -0,0 +1,80 @@
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRobotTrainRanking_LearnsAndExportsProfile(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir := t.TempDir()
+	beadsDir := filepath.Join(repoDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("2025-04-01T09:00:00Z", "init")
+
+	// Each day the most urgent open task is claimed
+	for day := 0; day <= 3; day++ {
+		var lines []string
+		for i := 0; i < 4; i++ {
+			status := "open"
+			if i < day {
+				status = "in_progress"
+			}
+			lines = append(lines, fmt.Sprintf(`{"id":"T-%d","title":"Task %d","status":"%s","priority":%d,"issue_type":"task","created_at":"2025-03-01T09:00:00Z","updated_at":"2025-03-01T09:00:00Z"}`, i, i, status, i))
+		}
+		if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		date := fmt.Sprintf("2025-04-0%dT09:00:00Z", day+1)
+		git(date, "add", ".beads")
+		git(date, "commit", "-m", fmt.Sprintf("day %d", day))
+	}
+
+	export := filepath.Join(repoDir, ".bv", "scoring.yaml")
+	cmd := exec.Command(bv, "--robot-train-ranking", "--train-export", export)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-train-ranking failed: %v\n%s", err, out)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json decode: %v\nout=%s", err, out)
+	}
+	if payload["revisions"].(float64) != 4 || payload["evaluated_on"] != "training" || payload["exported_to"] != export {
+		t.Fatalf("unexpected payload: %s", out)
+	}
+	if events := payload["events"].([]any); len(events) != 3 {
+		t.Errorf("events = %v", events)
+	}
+	if top1 := payload["trained"].(map[string]any)["top1"].(float64); top1 != 1 {
+		t.Errorf("trained top1 = %v", top1)
+	}
+
+	cmd = exec.Command(bv, "--robot-triage", "--profile", "learned")
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err != nil || !strings.Contains(string(out), `"profile":"learned"`) {
+		t.Errorf("exported profile not usable: %v\n%s", err, out)
+	}
+}
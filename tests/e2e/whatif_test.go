@@ -0,0 +1,82 @@
+package main_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRobotWhatIf_InlineAndFileScenarios(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir, _ := createForecastRepo(t)
+	beadsPath := filepath.Join(repoDir, ".beads", "beads.jsonl")
+	before, err := os.ReadFile(beadsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(spec string) map[string]any {
+		t.Helper()
+		cmd := exec.Command(bv, "--robot-whatif", spec, "--forecast-trials", "100")
+		cmd.Dir = repoDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--robot-whatif %s failed: %v\n%s", spec, err, out)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json decode: %v\nout=%s", err, out)
+		}
+		return payload
+	}
+
+	payload := run("close:OPEN-1,add-agents:1")
+	baseline := payload["baseline"].(map[string]any)
+	if baseline["open"].(float64) != 2 || payload["source"] != "inline" {
+		t.Fatalf("unexpected baseline: %v", payload)
+	}
+	scenarios := payload["scenarios"].([]any)
+	sc := scenarios[0].(map[string]any)
+	if sc["outcome"].(map[string]any)["open"].(float64) != 1 || sc["outcome"].(map[string]any)["agents"].(float64) != 2 {
+		t.Errorf("unexpected scenario outcome: %v", sc["outcome"])
+	}
+	closed := sc["diff"].(map[string]any)["closed_issues"].([]any)
+	if len(closed) != 1 || closed[0].(map[string]any)["id"] != "OPEN-1" {
+		t.Errorf("diff closed_issues = %v", closed)
+	}
+
+	scenarioFile := filepath.Join(repoDir, "scenarios.yaml")
+	body := "scenarios:\n  - name: ui-waits\n    edits:\n      - add_dep: {issue: OPEN-2, depends_on: OPEN-1}\n  - name: drop-ui\n    edits:\n      - defer: OPEN-2\n"
+	if err := os.WriteFile(scenarioFile, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	payload = run(scenarioFile)
+	scenarios = payload["scenarios"].([]any)
+	if len(scenarios) != 2 {
+		t.Fatalf("scenarios = %v", scenarios)
+	}
+	blocked := scenarios[0].(map[string]any)["newly_blocked"].([]any)
+	if len(blocked) != 1 || blocked[0] != "OPEN-2" {
+		t.Errorf("ui-waits newly_blocked = %v", blocked)
+	}
+	if deferred := scenarios[1].(map[string]any)["outcome"].(map[string]any)["deferred"].(float64); deferred != 1 {
+		t.Errorf("drop-ui deferred = %v", deferred)
+	}
+
+	after, err := os.ReadFile(beadsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("--robot-whatif modified the beads file")
+	}
+
+	cmd := exec.Command(bv, "--robot-whatif", "close:NOPE")
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected an unknown issue to fail, got %s", out)
+	}
+}